@@ -99,7 +99,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Permanently deletes all resources",
+                "description": "Permanently deletes all resources, including the trash and the files of attachments",
                 "tags": [
                     "v4"
                 ],
@@ -154,6 +154,12 @@ const docTemplate = `{
                 ],
                 "summary": "List accounts",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "description": "Filter by name",
@@ -207,6 +213,18 @@ const docTemplate = `{
                         "description": "Maximum number of Accounts to return. Defaults to 50.",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -216,6 +234,9 @@ const docTemplate = `{
                             "$ref": "#/definitions/v4.AccountListResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -367,6 +388,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/v4.AccountResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
                         }
                     },
                     "400": {
@@ -390,7 +417,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Deletes an account",
+                "description": "Deletes an account and moves it to the trash together with its transactions and match rules",
                 "produces": [
                     "application/json"
                 ],
@@ -406,6 +433,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -424,6 +457,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/v4.httpError"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -498,6 +537,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/v4.AccountEditable"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -519,6 +564,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/v4.AccountResponse"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AccountResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -528,16 +579,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/v4/accounts/{id}/recent-envelopes": {
-            "get": {
-                "description": "Returns a list of objects representing recent envelopes",
+        "/v4/accounts/{id}/merge": {
+            "post": {
+                "description": "Merges other accounts into this one. Their transactions, match rules and reconciliations are moved to this account and their initial balances are added to it, then they are deleted.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Accounts"
                 ],
-                "summary": "Get recent envelopes",
+                "summary": "Merge accounts",
                 "parameters": [
                     {
                         "type": "string",
@@ -546,142 +600,188 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Accounts to merge",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.AccountMerge"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.RecentEnvelopesResponse"
+                            "$ref": "#/definitions/v4.AccountResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.RecentEnvelopesResponse"
+                            "$ref": "#/definitions/v4.AccountResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.RecentEnvelopesResponse"
+                            "$ref": "#/definitions/v4.AccountResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.RecentEnvelopesResponse"
+                            "$ref": "#/definitions/v4.AccountResponse"
                         }
                     }
                 }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Accounts"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
             }
         },
-        "/v4/budgets": {
+        "/v4/accounts/{id}/recent-envelopes": {
             "get": {
-                "description": "Returns a list of budgets",
+                "description": "Returns a list of objects representing recent envelopes",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Budgets"
+                    "Accounts"
                 ],
-                "summary": "List budgets",
+                "summary": "Get recent envelopes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by name",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by note",
-                        "name": "note",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by currency",
-                        "name": "currency",
-                        "in": "query"
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Search for this text in name and note",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "The offset of the first Budget returned. Defaults to 0.",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Maximum number of Budgets to return. Defaults to 50.",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetListResponse"
+                            "$ref": "#/definitions/v4.RecentEnvelopesResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.RecentEnvelopesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.RecentEnvelopesResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetListResponse"
+                            "$ref": "#/definitions/v4.RecentEnvelopesResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new budget",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v4/accounts/{id}/register": {
+            "get": {
+                "description": "Returns the transactions of the account in date order. Each entry contains the balance and the reconciled balance of the account after the transaction, starting with the initial balance at its date. Use the next link of the pagination to get the next page.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Budgets"
+                    "Accounts"
                 ],
-                "summary": "Create budget",
+                "summary": "Get account register",
                 "parameters": [
                     {
-                        "description": "Budgets",
-                        "name": "budgets",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/v4.BudgetEditable"
-                            }
-                        }
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of transactions to return. Defaults to 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetCreateResponse"
+                            "$ref": "#/definitions/v4.AccountRegisterResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetCreateResponse"
+                            "$ref": "#/definitions/v4.AccountRegisterResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AccountRegisterResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetCreateResponse"
+                            "$ref": "#/definitions/v4.AccountRegisterResponse"
                         }
                     }
                 }
@@ -689,9 +789,19 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Budgets"
+                    "Accounts"
                 ],
                 "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "204": {
                         "description": "No Content"
@@ -699,16 +809,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v4/budgets/{id}": {
+        "/v4/attachments/{id}": {
             "get": {
-                "description": "Returns a specific budget",
+                "description": "Returns the metadata of a specific attachment. Use the file link to download the file.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Budgets"
+                    "Attachments"
                 ],
-                "summary": "Get budget",
+                "summary": "Get attachment",
                 "parameters": [
                     {
                         "type": "string",
@@ -723,35 +833,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.AttachmentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.AttachmentResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.AttachmentResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.AttachmentResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Deletes a budget",
+                "description": "Deletes an attachment and moves it to the trash. The file is deleted when the trash entry expires.",
                 "tags": [
-                    "Budgets"
+                    "Attachments"
                 ],
-                "summary": "Delete budget",
+                "summary": "Delete attachment",
                 "parameters": [
                     {
                         "type": "string",
@@ -760,6 +876,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -778,6 +900,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/v4.httpError"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -789,7 +917,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Budgets"
+                    "Attachments"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "parameters": [
@@ -825,19 +953,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Update an existing budget. Only values to be updated need to be specified.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v4/attachments/{id}/file": {
+            "get": {
+                "description": "Returns the file of an attachment",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "Budgets"
+                    "Attachments"
                 ],
-                "summary": "Update budget",
+                "summary": "Download attachment",
                 "parameters": [
                     {
                         "type": "string",
@@ -846,139 +973,279 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Budget",
-                        "name": "budget",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v4.BudgetEditable"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     }
                 }
-            }
-        },
-        "/v4/categories": {
-            "get": {
-                "description": "Returns a list of categories",
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Categories"
+                    "Attachments"
                 ],
-                "summary": "Get categories",
+                "summary": "Allowed HTTP verbs",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by name",
-                        "name": "name",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/audit": {
+            "get": {
+                "description": "Returns the log of all changes to resources, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by type of the resource, e.g. Transaction",
+                        "name": "model",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by note",
-                        "name": "note",
+                        "description": "Filter by ID of the resource",
+                        "name": "resource",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by budget ID",
-                        "name": "budget",
+                        "description": "Filter by kind of change. One of CREATE, UPDATE, DELETE",
+                        "name": "action",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Is the category archived?",
-                        "name": "archived",
+                        "type": "string",
+                        "description": "Filter by ID of the HTTP request that made the change",
+                        "name": "requestId",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Search for this text in name and note",
-                        "name": "search",
+                        "description": "Changes at and after this RFC3339 timestamp",
+                        "name": "fromTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Changes before and at this RFC3339 timestamp",
+                        "name": "untilTime",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "The offset of the first Category returned. Defaults to 0.",
+                        "description": "The offset of the first audit entry returned. Defaults to 0.",
                         "name": "offset",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Maximum number of Categories to return. Defaults to 50.",
+                        "description": "Maximum number of audit entries to return. Defaults to 50.",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are createdAt. Defaults to -createdAt",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryListResponse"
+                            "$ref": "#/definitions/v4.AuditEntryListResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryListResponse"
+                            "$ref": "#/definitions/v4.AuditEntryListResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryListResponse"
+                            "$ref": "#/definitions/v4.AuditEntryListResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/budgets": {
+            "get": {
+                "description": "Returns a list of budgets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "List budgets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by note",
+                        "name": "note",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search for this text in name and note",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset of the first Budget returned. Defaults to 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of Budgets to return. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetListResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Creates a new category",
+                "description": "Creates a new budget",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Budgets"
                 ],
-                "summary": "Create category",
+                "summary": "Create budget",
                 "parameters": [
                     {
-                        "description": "Categories",
-                        "name": "categories",
+                        "description": "Budgets",
+                        "name": "budgets",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/v4.CategoryEditable"
+                                "$ref": "#/definitions/v4.BudgetEditable"
                             }
                         }
                     }
@@ -987,25 +1254,19 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryCreateResponse"
+                            "$ref": "#/definitions/v4.BudgetCreateResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryCreateResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/v4.CategoryCreateResponse"
+                            "$ref": "#/definitions/v4.BudgetCreateResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryCreateResponse"
+                            "$ref": "#/definitions/v4.BudgetCreateResponse"
                         }
                     }
                 }
@@ -1013,7 +1274,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Categories"
+                    "Budgets"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "responses": {
@@ -1023,16 +1284,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v4/categories/{id}": {
+        "/v4/budgets/{id}": {
             "get": {
-                "description": "Returns a specific category",
+                "description": "Returns a specific budget",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Budgets"
                 ],
-                "summary": "Get category",
+                "summary": "Get budget",
                 "parameters": [
                     {
                         "type": "string",
@@ -1047,35 +1308,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryResponse"
+                            "$ref": "#/definitions/v4.BudgetResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryResponse"
+                            "$ref": "#/definitions/v4.BudgetResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryResponse"
+                            "$ref": "#/definitions/v4.BudgetResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryResponse"
+                            "$ref": "#/definitions/v4.BudgetResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Deletes a category",
+                "description": "Deletes a budget and moves it to the trash together with all its resources",
                 "tags": [
-                    "Categories"
+                    "Budgets"
                 ],
-                "summary": "Delete category",
+                "summary": "Delete budget",
                 "parameters": [
                     {
                         "type": "string",
@@ -1084,6 +1351,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1102,6 +1375,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/v4.httpError"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1113,7 +1392,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Categories"
+                    "Budgets"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "parameters": [
@@ -1151,7 +1430,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Update an existing category. Only values to be updated need to be specified.",
+                "description": "Update an existing budget. Only values to be updated need to be specified.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1159,9 +1438,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Budgets"
                 ],
-                "summary": "Update category",
+                "summary": "Update budget",
                 "parameters": [
                     {
                         "type": "string",
@@ -1172,75 +1451,257 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Category",
-                        "name": "category",
+                        "description": "Budget",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryEditable"
+                            "$ref": "#/definitions/v4.BudgetEditable"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryResponse"
+                            "$ref": "#/definitions/v4.BudgetResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryResponse"
+                            "$ref": "#/definitions/v4.BudgetResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryResponse"
+                            "$ref": "#/definitions/v4.BudgetResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.CategoryResponse"
+                            "$ref": "#/definitions/v4.BudgetResponse"
                         }
                     }
                 }
             }
         },
-        "/v4/envelopes": {
-            "get": {
-                "description": "Returns a list of envelopes",
+        "/v4/budgets/{id}/clone": {
+            "post": {
+                "description": "Creates a copy of a budget. Accounts, categories, envelopes and tags are always copied. Depending on the mode, goals and match rules or all resources including transactions and allocations are copied, too. Attachments are never copied.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Envelopes"
+                    "Budgets"
                 ],
-                "summary": "Get envelopes",
+                "summary": "Clone budget",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by name",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by note",
-                        "name": "note",
-                        "in": "query"
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by category ID",
-                        "name": "category",
+                        "description": "Clone options",
+                        "name": "options",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetClone"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/budgets/{id}/template": {
+            "post": {
+                "description": "Creates the categories and envelopes of a template for the budget. Categories and envelopes that already exist with the same name are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Apply template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.CategoryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.CategoryListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.CategoryListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.CategoryListResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/categories": {
+            "get": {
+                "description": "Returns a list of categories",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by note",
+                        "name": "note",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by budget ID",
+                        "name": "budget",
                         "in": "query"
                     },
                     {
                         "type": "boolean",
-                        "description": "Is the envelope archived?",
+                        "description": "Is the category archived?",
                         "name": "archived",
                         "in": "query"
                     },
@@ -1252,57 +1713,72 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "The offset of the first Envelope returned. Defaults to 0.",
+                        "description": "The offset of the first Category returned. Defaults to 0.",
                         "name": "offset",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Maximum number of Envelopes to return. Defaults to 50.",
+                        "description": "Maximum number of Categories to return. Defaults to 50.",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeListResponse"
+                            "$ref": "#/definitions/v4.CategoryListResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeListResponse"
+                            "$ref": "#/definitions/v4.CategoryListResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeListResponse"
+                            "$ref": "#/definitions/v4.CategoryListResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Creates a new envelope",
+                "description": "Creates a new category",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Envelopes"
+                    "Categories"
                 ],
-                "summary": "Create envelope",
+                "summary": "Create category",
                 "parameters": [
                     {
-                        "description": "Envelopes",
-                        "name": "envelopes",
+                        "description": "Categories",
+                        "name": "categories",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/v4.EnvelopeEditable"
+                                "$ref": "#/definitions/v4.CategoryEditable"
                             }
                         }
                     }
@@ -1311,25 +1787,25 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeCreateResponse"
+                            "$ref": "#/definitions/v4.CategoryCreateResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeCreateResponse"
+                            "$ref": "#/definitions/v4.CategoryCreateResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeCreateResponse"
+                            "$ref": "#/definitions/v4.CategoryCreateResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeCreateResponse"
+                            "$ref": "#/definitions/v4.CategoryCreateResponse"
                         }
                     }
                 }
@@ -1337,7 +1813,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Envelopes"
+                    "Categories"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "responses": {
@@ -1347,16 +1823,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v4/envelopes/{id}": {
+        "/v4/categories/{id}": {
             "get": {
-                "description": "Returns a specific Envelope",
+                "description": "Returns a specific category",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Envelopes"
+                    "Categories"
                 ],
-                "summary": "Get Envelope",
+                "summary": "Get category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1371,35 +1847,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                            "$ref": "#/definitions/v4.CategoryResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                            "$ref": "#/definitions/v4.CategoryResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                            "$ref": "#/definitions/v4.CategoryResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                            "$ref": "#/definitions/v4.CategoryResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Deletes an envelope",
+                "description": "Deletes a category and moves it to the trash together with its envelopes",
                 "tags": [
-                    "Envelopes"
+                    "Categories"
                 ],
-                "summary": "Delete envelope",
+                "summary": "Delete category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1408,6 +1890,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1426,6 +1914,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/v4.httpError"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1437,7 +1931,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Envelopes"
+                    "Categories"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "parameters": [
@@ -1475,7 +1969,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Updates an existing envelope. Only values to be updated need to be specified.",
+                "description": "Update an existing category. Only values to be updated need to be specified.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1483,9 +1977,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Envelopes"
+                    "Categories"
                 ],
-                "summary": "Update envelope",
+                "summary": "Update category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1496,215 +1990,171 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Envelope",
-                        "name": "envelope",
+                        "description": "Category",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeEditable"
+                            "$ref": "#/definitions/v4.CategoryEditable"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                            "$ref": "#/definitions/v4.CategoryResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                            "$ref": "#/definitions/v4.CategoryResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                            "$ref": "#/definitions/v4.CategoryResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.CategoryResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                            "$ref": "#/definitions/v4.CategoryResponse"
                         }
                     }
                 }
             }
         },
-        "/v4/envelopes/{id}/{month}": {
+        "/v4/envelopes": {
             "get": {
-                "description": "Returns configuration for a specific month",
+                "description": "Returns a list of envelopes",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Envelopes"
                 ],
-                "summary": "Get MonthConfig",
+                "summary": "Get envelopes",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "UUID",
-                        "description": "ID of the resource",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
                     },
                     {
                         "type": "string",
-                        "example": "2013-11",
-                        "description": "Year and month in YYYY-MM format",
-                        "name": "month",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by name",
+                        "name": "name",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "format": "UUID",
-                        "description": "ID of the resource",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by note",
+                        "name": "note",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "2013-11",
-                        "description": "Year and month in YYYY-MM format",
-                        "name": "month",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/v4.MonthConfigResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/v4.MonthConfigResponse"
-                        }
+                        "description": "Filter by category ID",
+                        "name": "category",
+                        "in": "query"
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/v4.MonthConfigResponse"
-                        }
+                    {
+                        "type": "boolean",
+                        "description": "Is the envelope archived?",
+                        "name": "archived",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/v4.MonthConfigResponse"
-                        }
-                    }
-                }
-            },
-            "options": {
-                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
-                "tags": [
-                    "Envelopes"
-                ],
-                "summary": "Allowed HTTP verbs",
-                "parameters": [
                     {
                         "type": "string",
-                        "format": "UUID",
-                        "description": "ID of the resource",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Search for this text in name and note",
+                        "name": "search",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "example": "2013-11",
-                        "description": "Year and month in YYYY-MM format",
-                        "name": "month",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "The offset of the first Envelope returned. Defaults to 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of Envelopes to return. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "format": "UUID",
-                        "description": "ID of the resource",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name",
+                        "name": "sort",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "2013-11",
-                        "description": "Year and month in YYYY-MM format",
-                        "name": "month",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.EnvelopeListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.httpError"
+                            "$ref": "#/definitions/v4.EnvelopeListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.EnvelopeListResponse"
                         }
                     }
                 }
             },
-            "patch": {
-                "description": "Changes configuration for a Month. If there is no configuration for the month yet, this endpoint transparently creates a configuration resource.",
+            "post": {
+                "description": "Creates a new envelope",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Envelopes"
                 ],
-                "summary": "Update MonthConfig",
+                "summary": "Create envelope",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "UUID",
-                        "description": "ID of the resource",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "2013-11",
-                        "description": "Year and month in YYYY-MM format",
-                        "name": "month",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "UUID",
-                        "description": "ID of the resource",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "2013-11",
-                        "description": "Year and month in YYYY-MM format",
-                        "name": "month",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "MonthConfig",
-                        "name": "monthConfig",
+                        "description": "Envelopes",
+                        "name": "envelopes",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v4.MonthConfigEditable"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v4.EnvelopeEditable"
+                            }
                         }
                     }
                 ],
@@ -1712,270 +2162,199 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/v4.MonthConfigResponse"
+                            "$ref": "#/definitions/v4.EnvelopeCreateResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.MonthConfigResponse"
+                            "$ref": "#/definitions/v4.EnvelopeCreateResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.MonthConfigResponse"
+                            "$ref": "#/definitions/v4.EnvelopeCreateResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.MonthConfigResponse"
+                            "$ref": "#/definitions/v4.EnvelopeCreateResponse"
                         }
                     }
                 }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Envelopes"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
             }
         },
-        "/v4/export": {
+        "/v4/envelopes/{id}": {
             "get": {
-                "description": "Exports all resources for the instance",
+                "description": "Returns a specific Envelope",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Export"
+                    "Envelopes"
+                ],
+                "summary": "Get Envelope",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Export",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.ExportResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.ExportResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     }
                 }
             },
-            "options": {
-                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+            "delete": {
+                "description": "Deletes an envelope and moves it to the trash together with its goals, month configs and transactions",
                 "tags": [
-                    "Export"
+                    "Envelopes"
+                ],
+                "summary": "Delete envelope",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Allowed HTTP verbs",
                 "responses": {
                     "204": {
                         "description": "No Content"
-                    }
-                }
-            }
-        },
-        "/v4/goals": {
-            "get": {
-                "description": "Returns a list of goals",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Goals"
-                ],
-                "summary": "Get goals",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter by name",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by note",
-                        "name": "note",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search for this text in name and note",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Is the goal archived?",
-                        "name": "archived",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by envelope ID",
-                        "name": "envelope",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Month of the goal. Ignores exact time, matches on the month of the RFC3339 timestamp provided.",
-                        "name": "month",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Goals for this and later months. Ignores exact time, matches on the month of the RFC3339 timestamp provided.",
-                        "name": "fromMonth",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Goals for this and earlier months. Ignores exact time, matches on the month of the RFC3339 timestamp provided.",
-                        "name": "untilMonth",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by amount",
-                        "name": "amount",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Amount less than or equal to this",
-                        "name": "amountLessOrEqual",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Amount more than or equal to this",
-                        "name": "amountMoreOrEqual",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "The offset of the first goal returned. Defaults to 0.",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Maximum number of goal to return. Defaults to 50.",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Period is exactly this",
-                        "name": "period",
-                        "in": "query"
                     },
-                    {
-                        "type": "integer",
-                        "description": "Period is less or equal to this. Non-recurring goals are not returned.",
-                        "name": "periodLessOrEqual",
-                        "in": "query"
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Period is more or equal to this. Non-recurring goals are not returned.",
-                        "name": "periodMoreOrEqual",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalListResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "412": {
+                        "description": "Precondition Failed",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalListResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalListResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Creates new goals",
-                "produces": [
-                    "application/json"
-                ],
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Goals"
+                    "Envelopes"
                 ],
-                "summary": "Create goals",
+                "summary": "Allowed HTTP verbs",
                 "parameters": [
                     {
-                        "description": "Goals",
-                        "name": "goals",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/v4.GoalEditable"
-                            }
-                        }
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/v4.GoalCreateResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalCreateResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalCreateResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalCreateResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     }
                 }
             },
-            "options": {
-                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
-                "tags": [
-                    "Goals"
+            "patch": {
+                "description": "Updates an existing envelope. Only values to be updated need to be specified.",
+                "consumes": [
+                    "application/json"
                 ],
-                "summary": "Allowed HTTP verbs",
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
-            }
-        },
-        "/v4/goals/{id}": {
-            "get": {
-                "description": "Returns a specific goal",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Goals"
+                    "Envelopes"
                 ],
-                "summary": "Get goal",
+                "summary": "Update envelope",
                 "parameters": [
                     {
                         "type": "string",
@@ -1984,41 +2363,67 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Envelope",
+                        "name": "envelope",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.EnvelopeEditable"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a goal",
+            }
+        },
+        "/v4/envelopes/{id}/history": {
+            "get": {
+                "description": "Returns everything that changed the balance of the envelope in chronological order: rollovers of the previous month's balance, resets of negative balances, allocations and transactions. Each entry contains the balance after it.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Goals"
+                    "Envelopes"
                 ],
-                "summary": "Delete goal",
+                "summary": "Get envelope history",
                 "parameters": [
                     {
                         "type": "string",
@@ -2027,28 +2432,54 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The first month in YYYY-MM format",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The last month in YYYY-MM format",
+                        "name": "until",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.EnvelopeHistoryResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.httpError"
+                            "$ref": "#/definitions/v4.EnvelopeHistoryResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.httpError"
+                            "$ref": "#/definitions/v4.EnvelopeHistoryResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.httpError"
+                            "$ref": "#/definitions/v4.EnvelopeHistoryResponse"
                         }
                     }
                 }
@@ -2056,7 +2487,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Goals"
+                    "Envelopes"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "parameters": [
@@ -2072,29 +2503,13 @@ const docTemplate = `{
                 "responses": {
                     "204": {
                         "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/v4.httpError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/v4.httpError"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/v4.httpError"
-                        }
                     }
                 }
-            },
-            "patch": {
-                "description": "Updates an existing goal. Only values to be updated need to be specified.",
+            }
+        },
+        "/v4/envelopes/{id}/merge": {
+            "post": {
+                "description": "Merges other envelopes into this one. Their transactions and goals are moved to this envelope and their allocations are added to the ones of this envelope, then they are deleted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2102,9 +2517,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Goals"
+                    "Envelopes"
                 ],
-                "summary": "Update goal",
+                "summary": "Merge envelopes",
                 "parameters": [
                     {
                         "type": "string",
@@ -2115,12 +2530,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Goal",
-                        "name": "goal",
+                        "description": "Envelopes to merge",
+                        "name": "merge",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalEditable"
+                            "$ref": "#/definitions/v4.EnvelopeMerge"
                         }
                     }
                 ],
@@ -2128,52 +2543,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.GoalResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/v4/import": {
-            "get": {
-                "description": "Returns general information about the v4 API",
-                "tags": [
-                    "Import"
-                ],
-                "summary": "Import API overview",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/v4.ImportResponse"
+                            "$ref": "#/definitions/v4.EnvelopeResponse"
                         }
                     }
                 }
             },
             "options": {
-                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs.",
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Import"
+                    "Envelopes"
                 ],
                 "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "204": {
                         "description": "No Content"
@@ -2181,32 +2589,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/v4/import/ynab-import-preview": {
-            "post": {
-                "description": "Returns a preview of transactions to be imported after parsing a YNAB Import format csv file",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+        "/v4/envelopes/{id}/{month}": {
+            "get": {
+                "description": "Returns configuration for a specific month",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Import"
+                    "Envelopes"
                 ],
-                "summary": "Transaction Import Preview",
+                "summary": "Get MonthConfig",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "File to import",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "ID of the account to import the transactions for",
-                        "name": "accountId",
-                        "in": "query",
+                        "example": "2013-11",
+                        "description": "Year and month in YYYY-MM format",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2013-11",
+                        "description": "Year and month in YYYY-MM format",
+                        "name": "month",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -2214,25 +2637,31 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.ImportPreviewList"
+                            "$ref": "#/definitions/v4.MonthConfigResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.ImportPreviewList"
+                            "$ref": "#/definitions/v4.MonthConfigResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.ImportPreviewList"
+                            "$ref": "#/definitions/v4.MonthConfigResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.ImportPreviewList"
+                            "$ref": "#/definitions/v4.MonthConfigResponse"
                         }
                     }
                 }
@@ -2240,167 +2669,315 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Import"
+                    "Envelopes"
                 ],
                 "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2013-11",
+                        "description": "Year and month in YYYY-MM format",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2013-11",
+                        "description": "Year and month in YYYY-MM format",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "204": {
                         "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
                     }
                 }
-            }
-        },
-        "/v4/import/ynab4": {
-            "post": {
-                "description": "Imports budgets from YNAB 4",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+            },
+            "patch": {
+                "description": "Changes configuration for a Month. If there is no configuration for the month yet, this endpoint transparently creates a configuration resource.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Import"
+                    "Envelopes"
                 ],
-                "summary": "Import YNAB 4 budget",
+                "summary": "Update MonthConfig",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "File to import",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Name for the new budget",
-                        "name": "budgetName",
-                        "in": "query",
+                        "example": "2013-11",
+                        "description": "Year and month in YYYY-MM format",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2013-11",
+                        "description": "Year and month in YYYY-MM format",
+                        "name": "month",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "MonthConfig",
+                        "name": "monthConfig",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthConfigEditable"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.MonthConfigResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.MonthConfigResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthConfigResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthConfigResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.BudgetResponse"
+                            "$ref": "#/definitions/v4.MonthConfigResponse"
                         }
                     }
                 }
-            },
-            "options": {
-                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
-                "tags": [
-                    "Import"
-                ],
-                "summary": "Allowed HTTP verbs",
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
             }
         },
-        "/v4/match-rules": {
+        "/v4/events": {
             "get": {
-                "description": "Returns a list of matchRules",
+                "description": "Streams changes to accounts, envelopes, month configs and transactions of a budget as Server-Sent Events.\nThe name of each event is the kind of change, e.g. \"transaction.created\", its data describes the change and its ID is the position to resume from.\nStreams resume after the event in the Last-Event-ID header or lastEventId parameter. Without it, the stream starts with a \"ready\" event.\nIf events to resume from are no longer available, the stream starts with a \"reset\" event and clients need to reload all data.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "MatchRules"
+                    "Events"
                 ],
-                "summary": "Get matchRules",
+                "summary": "Event stream",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Filter by priority",
-                        "name": "priority",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Filter by match",
-                        "name": "match",
-                        "in": "query"
+                        "description": "ID of the budget",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Filter by account ID",
-                        "name": "account",
+                        "description": "ID of the last received event. Ignored if the Last-Event-ID header is set",
+                        "name": "lastEventId",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "ID of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetEventData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/exchange-rates": {
+            "get": {
+                "description": "Returns a list of exchange rates, ordered by currency and latest date first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange Rates"
+                ],
+                "summary": "Get exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "description": "Filter by budget ID",
                         "name": "budget",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Filter by currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "The offset of the first Match Rule returned. Defaults to 0.",
+                        "description": "The offset of the first exchange rate returned. Defaults to 0.",
                         "name": "offset",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Maximum number of Match Rules to return. Defaults to 50.",
+                        "description": "Maximum number of exchange rates to return. Defaults to 50.",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are currency, date, createdAt. Defaults to currency,-date",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleListResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateListResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleListResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateListResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleListResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateListResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Creates matchRules from the list of submitted matchRule data. The response code is the highest response code number that a single matchRule creation would have caused. If it is not equal to 201, at least one matchRule has an error.",
+                "description": "Creates new exchange rates",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "MatchRules"
+                    "Exchange Rates"
                 ],
-                "summary": "Create matchRules",
+                "summary": "Create exchange rates",
                 "parameters": [
                     {
-                        "description": "MatchRules",
-                        "name": "matchRules",
+                        "description": "Exchange rates",
+                        "name": "exchangeRates",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/v4.MatchRuleEditable"
+                                "$ref": "#/definitions/v4.ExchangeRateEditable"
                             }
                         }
                     }
@@ -2409,25 +2986,25 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleCreateResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateCreateResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleCreateResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateCreateResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleCreateResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateCreateResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleCreateResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateCreateResponse"
                         }
                     }
                 }
@@ -2435,7 +3012,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "MatchRules"
+                    "Exchange Rates"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "responses": {
@@ -2445,16 +3022,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/v4/match-rules/{id}": {
+        "/v4/exchange-rates/import": {
+            "post": {
+                "description": "Imports exchange rates from a CSV file with the columns currency, date (YYYY-MM-DD) and rate. The first line must be a header with the column names. Existing rates for the same currency and date are updated.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange Rates"
+                ],
+                "summary": "Import exchange rates",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File to import",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the budget to import the exchange rates for",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ExchangeRateListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ExchangeRateListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ExchangeRateListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ExchangeRateListResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Exchange Rates"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/exchange-rates/{id}": {
             "get": {
-                "description": "Returns a specific matchRule",
+                "description": "Returns a specific exchange rate",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "MatchRules"
+                    "Exchange Rates"
                 ],
-                "summary": "Get matchRule",
+                "summary": "Get exchange rate",
                 "parameters": [
                     {
                         "type": "string",
@@ -2469,35 +3115,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Deletes an matchRule",
+                "description": "Deletes an exchange rate and moves it to the trash",
                 "tags": [
-                    "MatchRules"
+                    "Exchange Rates"
                 ],
-                "summary": "Delete matchRule",
+                "summary": "Delete exchange rate",
                 "parameters": [
                     {
                         "type": "string",
@@ -2506,6 +3158,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -2524,6 +3182,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/v4.httpError"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2535,7 +3199,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "MatchRules"
+                    "Exchange Rates"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "parameters": [
@@ -2573,7 +3237,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Update a matchRule. Only values to be updated need to be specified.",
+                "description": "Updates an exchange rate. Only values to be updated need to be specified.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2581,9 +3245,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MatchRules"
+                    "Exchange Rates"
                 ],
-                "summary": "Update matchRule",
+                "summary": "Update exchange rate",
                 "parameters": [
                     {
                         "type": "string",
@@ -2594,66 +3258,84 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "MatchRule",
-                        "name": "matchRule",
+                        "description": "Exchange rate",
+                        "name": "exchangeRate",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleEditable"
+                            "$ref": "#/definitions/v4.ExchangeRateEditable"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ExchangeRateResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                            "$ref": "#/definitions/v4.ExchangeRateResponse"
                         }
                     }
                 }
             }
         },
-        "/v4/months": {
+        "/v4/export": {
             "get": {
-                "description": "Returns data about a specific month.",
+                "description": "Exports all resources for the instance or a single budget.\n\nThe JSON format contains all fields of all resources. The CSV format is a zip archive with one file per model,\nthe XLSX format a workbook with one sheet per model. Both use human-readable columns with names of accounts,\ncategories and envelopes instead of their IDs.\n\nThe archive format is a zip archive with the JSON export as export.json and the files of all attachments.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/zip",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Months"
+                    "Export"
                 ],
-                "summary": "Get data about a month",
+                "summary": "Export",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID formatted as string",
+                        "description": "ID of the budget to export. If not set, all budgets are exported.",
                         "name": "budget",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx",
+                            "archive"
+                        ],
                         "type": "string",
-                        "example": "2022-07",
-                        "description": "Year and month in YYYY-MM format",
-                        "name": "month",
+                        "description": "Format of the export. Defaults to JSON.",
+                        "name": "format",
                         "in": "query"
                     }
                 ],
@@ -2661,188 +3343,105 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.MonthResponse"
+                            "$ref": "#/definitions/v4.ExportResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.MonthResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.MonthResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.MonthResponse"
+                            "$ref": "#/definitions/v4.httpError"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Sets allocations for a month for all envelopes that do not have an allocation yet",
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Months"
+                    "Export"
                 ],
-                "summary": "Set allocations for a month",
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/goals": {
+            "get": {
+                "description": "Returns a list of goals",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Get goals",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID formatted as string",
-                        "name": "budget",
-                        "in": "query",
-                        "required": true
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
                     },
                     {
                         "type": "string",
-                        "description": "The month in YYYY-MM format",
-                        "name": "month",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "description": "Budget",
-                        "name": "mode",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v4.BudgetAllocationMode"
-                        }
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/v4.httpError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/v4.httpError"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/v4.httpError"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Deletes all allocation for the specified month",
-                "tags": [
-                    "Months"
-                ],
-                "summary": "Delete allocations for a month",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID formatted as string",
-                        "name": "budget",
-                        "in": "query",
-                        "required": true
+                        "description": "Filter by name",
+                        "name": "name",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "The month in YYYY-MM format",
-                        "name": "month",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/v4.httpError"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/v4.httpError"
-                        }
+                        "description": "Filter by note",
+                        "name": "note",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/v4.httpError"
-                        }
-                    }
-                }
-            },
-            "options": {
-                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs.",
-                "tags": [
-                    "Months"
-                ],
-                "summary": "Allowed HTTP verbs",
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
-            }
-        },
-        "/v4/transactions": {
-            "get": {
-                "description": "Returns a list of transactions",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Transactions"
-                ],
-                "summary": "Get transactions",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
-                        "name": "date",
+                        "description": "Search for this text in name and note",
+                        "name": "search",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
-                        "name": "fromDate",
+                        "type": "boolean",
+                        "description": "Is the goal archived?",
+                        "name": "archived",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
-                        "name": "untilDate",
+                        "description": "Filter by envelope ID",
+                        "name": "envelope",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Availability date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
-                        "name": "availableFromDate",
+                        "description": "Month of the goal. Ignores exact time, matches on the month of the RFC3339 timestamp provided.",
+                        "name": "month",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Transactions available at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
-                        "name": "availableFromFromDate",
+                        "description": "Goals for this and later months. Ignores exact time, matches on the month of the RFC3339 timestamp provided.",
+                        "name": "fromMonth",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Transactions available before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
-                        "name": "availableFromUntilDate",
+                        "description": "Goals for this and earlier months. Ignores exact time, matches on the month of the RFC3339 timestamp provided.",
+                        "name": "untilMonth",
                         "in": "query"
                     },
                     {
@@ -2864,74 +3463,45 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by note",
-                        "name": "note",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by budget ID",
-                        "name": "budget",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by ID of associated account, regardeless of source or destination",
-                        "name": "account",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by source account ID",
-                        "name": "source",
+                        "type": "integer",
+                        "description": "The offset of the first goal returned. Defaults to 0.",
+                        "name": "offset",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by destination account ID",
-                        "name": "destination",
+                        "type": "integer",
+                        "description": "Maximum number of goal to return. Defaults to 50.",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "IN",
-                            "OUT",
-                            "INTERNAL"
-                        ],
                         "type": "string",
-                        "description": "Filter by direction of transaction",
-                        "name": "direction",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are month, name, amount, createdAt. Defaults to month,name",
+                        "name": "sort",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by envelope ID",
-                        "name": "envelope",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reconcilication state in source account",
-                        "name": "reconciledSource",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Reconcilication state in destination account",
-                        "name": "reconciledDestination",
+                        "type": "integer",
+                        "description": "Period is exactly this",
+                        "name": "period",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "The offset of the first Transaction returned. Defaults to 0.",
-                        "name": "offset",
+                        "description": "Period is less or equal to this. Non-recurring goals are not returned.",
+                        "name": "periodLessOrEqual",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Maximum number of Transactions to return. Defaults to 50.",
-                        "name": "limit",
+                        "description": "Period is more or equal to this. Non-recurring goals are not returned.",
+                        "name": "periodMoreOrEqual",
                         "in": "query"
                     }
                 ],
@@ -2939,42 +3509,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionListResponse"
+                            "$ref": "#/definitions/v4.GoalListResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionListResponse"
+                            "$ref": "#/definitions/v4.GoalListResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionListResponse"
+                            "$ref": "#/definitions/v4.GoalListResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Creates transactions from the list of submitted transaction data. The response code is the highest response code number that a single transaction creation would have caused. If it is not equal to 201, at least one transaction has an error.",
+                "description": "Creates new goals",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Goals"
                 ],
-                "summary": "Create transactions",
+                "summary": "Create goals",
                 "parameters": [
                     {
-                        "description": "Transactions",
-                        "name": "transactions",
+                        "description": "Goals",
+                        "name": "goals",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/v4.TransactionEditable"
+                                "$ref": "#/definitions/v4.GoalEditable"
                             }
                         }
                     }
@@ -2983,25 +3556,25 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionCreateResponse"
+                            "$ref": "#/definitions/v4.GoalCreateResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionCreateResponse"
+                            "$ref": "#/definitions/v4.GoalCreateResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionCreateResponse"
+                            "$ref": "#/definitions/v4.GoalCreateResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionCreateResponse"
+                            "$ref": "#/definitions/v4.GoalCreateResponse"
                         }
                     }
                 }
@@ -3009,7 +3582,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Transactions"
+                    "Goals"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "responses": {
@@ -3019,16 +3592,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/v4/transactions/{id}": {
+        "/v4/goals/{id}": {
             "get": {
-                "description": "Returns a specific transaction",
+                "description": "Returns a specific goal",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Goals"
                 ],
-                "summary": "Get transaction",
+                "summary": "Get goal",
                 "parameters": [
                     {
                         "type": "string",
@@ -3043,35 +3616,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionResponse"
+                            "$ref": "#/definitions/v4.GoalResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionResponse"
+                            "$ref": "#/definitions/v4.GoalResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionResponse"
+                            "$ref": "#/definitions/v4.GoalResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionResponse"
+                            "$ref": "#/definitions/v4.GoalResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Deletes a transaction",
+                "description": "Deletes a goal and moves it to the trash",
                 "tags": [
-                    "Transactions"
+                    "Goals"
                 ],
-                "summary": "Delete transaction",
+                "summary": "Delete goal",
                 "parameters": [
                     {
                         "type": "string",
@@ -3080,6 +3659,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -3098,6 +3683,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/v4.httpError"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3109,7 +3700,7 @@ const docTemplate = `{
             "options": {
                 "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
                 "tags": [
-                    "Transactions"
+                    "Goals"
                 ],
                 "summary": "Allowed HTTP verbs",
                 "parameters": [
@@ -3147,7 +3738,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Updates an existing transaction. Only values to be updated need to be specified.",
+                "description": "Updates an existing goal. Only values to be updated need to be specified.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3155,9 +3746,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Goals"
                 ],
-                "summary": "Update transaction",
+                "summary": "Update goal",
                 "parameters": [
                     {
                         "type": "string",
@@ -3168,179 +3759,6762 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Transaction",
-                        "name": "transaction",
+                        "description": "Goal",
+                        "name": "goal",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionEditable"
+                            "$ref": "#/definitions/v4.GoalEditable"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionResponse"
+                            "$ref": "#/definitions/v4.GoalResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionResponse"
+                            "$ref": "#/definitions/v4.GoalResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionResponse"
+                            "$ref": "#/definitions/v4.GoalResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.GoalResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/v4.TransactionResponse"
+                            "$ref": "#/definitions/v4.GoalResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/import": {
+            "get": {
+                "description": "Returns general information about the v4 API",
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Import API overview",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ImportResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs.",
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/import/ynab-import-preview": {
+            "post": {
+                "description": "Returns a preview of transactions to be imported after parsing a YNAB Import format csv file",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Transaction Import Preview",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File to import",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the account to import the transactions for",
+                        "name": "accountId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ImportPreviewList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ImportPreviewList"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ImportPreviewList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ImportPreviewList"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/import/ynab4": {
+            "post": {
+                "description": "Imports budgets from YNAB 4",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Import YNAB 4 budget",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File to import",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name for the new budget",
+                        "name": "budgetName",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/match-rules": {
+            "get": {
+                "description": "Returns a list of matchRules",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MatchRules"
+                ],
+                "summary": "Get matchRules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter by priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by match",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by account ID",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by budget ID",
+                        "name": "budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset of the first Match Rule returned. Defaults to 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of Match Rules to return. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are priority, match, createdAt. Defaults to priority,match",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates matchRules from the list of submitted matchRule data. The response code is the highest response code number that a single matchRule creation would have caused. If it is not equal to 201, at least one matchRule has an error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MatchRules"
+                ],
+                "summary": "Create matchRules",
+                "parameters": [
+                    {
+                        "description": "MatchRules",
+                        "name": "matchRules",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v4.MatchRuleEditable"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleCreateResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleCreateResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleCreateResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "MatchRules"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/match-rules/{id}": {
+            "get": {
+                "description": "Returns a specific matchRule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MatchRules"
+                ],
+                "summary": "Get matchRule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes an matchRule and moves it to the trash",
+                "tags": [
+                    "MatchRules"
+                ],
+                "summary": "Delete matchRule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "MatchRules"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a matchRule. Only values to be updated need to be specified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MatchRules"
+                ],
+                "summary": "Update matchRule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "MatchRule",
+                        "name": "matchRule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleEditable"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MatchRuleResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/months": {
+            "get": {
+                "description": "Returns data about a specific month.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Months"
+                ],
+                "summary": "Get data about a month",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID formatted as string",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2022-07",
+                        "description": "Year and month in YYYY-MM format",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Sets allocations for a month for all envelopes that do not have an allocation yet",
+                "tags": [
+                    "Months"
+                ],
+                "summary": "Set allocations for a month",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID formatted as string",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The month in YYYY-MM format",
+                        "name": "month",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Budget",
+                        "name": "mode",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.BudgetAllocationMode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes all allocation for the specified month",
+                "tags": [
+                    "Months"
+                ],
+                "summary": "Delete allocations for a month",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID formatted as string",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The month in YYYY-MM format",
+                        "name": "month",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs.",
+                "tags": [
+                    "Months"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/months/range": {
+            "get": {
+                "description": "Returns data about all months from the first to the last month, inclusive.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Months"
+                ],
+                "summary": "Get data about a range of months",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID formatted as string",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The first month in YYYY-MM format",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The last month in YYYY-MM format",
+                        "name": "until",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthRangeResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthRangeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthRangeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthRangeResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs.",
+                "tags": [
+                    "Months"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/months/transfer": {
+            "post": {
+                "description": "Moves money from the allocation of one envelope to another one for a month. If one of the envelopes is not set, the money is moved from or to the amount available to budget. Moves that make the amount available to budget or the balance of the source envelope negative are rejected unless forced.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Months"
+                ],
+                "summary": "Move money between envelopes",
+                "parameters": [
+                    {
+                        "description": "Transfer",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthTransfer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.MonthResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs.",
+                "tags": [
+                    "Months"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/payees": {
+            "get": {
+                "description": "Returns a list of payees",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payees"
+                ],
+                "summary": "List payees",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by note",
+                        "name": "note",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by budget ID",
+                        "name": "budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Is the payee archived?",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search for this text in name and note",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset of the first payee returned. Defaults to 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of payees to return. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates new payees. Payees are external accounts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payees"
+                ],
+                "summary": "Create payees",
+                "parameters": [
+                    {
+                        "description": "Payees",
+                        "name": "payees",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v4.PayeeEditable"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeCreateResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeCreateResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeCreateResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Payees"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/payees/{id}": {
+            "get": {
+                "description": "Returns a specific payee",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payees"
+                ],
+                "summary": "Get payee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a payee and moves it to the trash together with its transactions and match rules",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payees"
+                ],
+                "summary": "Delete payee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Payees"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates a payee. Only values to be updated need to be specified.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payees"
+                ],
+                "summary": "Update payee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payee",
+                        "name": "payee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeEditable"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/payees/{id}/merge": {
+            "post": {
+                "description": "Merges other payees into this one. Their transactions, match rules and reconciliations are moved to this payee, then they are deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payees"
+                ],
+                "summary": "Merge payees",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payees to merge",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeMerge"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Payees"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/reconciliations": {
+            "get": {
+                "description": "Returns the history of reconciliations, most recent statement first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reconciliations"
+                ],
+                "summary": "Get reconciliations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by budget ID",
+                        "name": "budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by account ID",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by state. Either IN_PROGRESS or COMPLETED",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset of the first reconciliation returned. Defaults to 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of reconciliations to return. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are statementDate, createdAt. Defaults to -statementDate,-createdAt",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Starts new reconciliations of accounts with a statement",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reconciliations"
+                ],
+                "summary": "Create reconciliations",
+                "parameters": [
+                    {
+                        "description": "Reconciliations",
+                        "name": "reconciliations",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v4.ReconciliationEditable"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationCreateResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationCreateResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationCreateResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Reconciliations"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/reconciliations/{id}": {
+            "get": {
+                "description": "Returns a specific reconciliation. For reconciliations in progress, the transactions that are not reconciled yet and the difference to the statement balance are included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reconciliations"
+                ],
+                "summary": "Get reconciliation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a reconciliation and moves it to the trash. Transactions stay reconciled.",
+                "tags": [
+                    "Reconciliations"
+                ],
+                "summary": "Delete reconciliation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Reconciliations"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates a reconciliation that is in progress. Only values to be updated need to be specified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reconciliations"
+                ],
+                "summary": "Update reconciliation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reconciliation",
+                        "name": "reconciliation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationEditable"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/reconciliations/{id}/complete": {
+            "post": {
+                "description": "Marks transactions as reconciled and completes the reconciliation. If the reconciled balance does not match the statement balance, completing fails unless an adjustment transaction for the difference is requested.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reconciliations"
+                ],
+                "summary": "Complete reconciliation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transactions to reconcile and adjustment",
+                        "name": "complete",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationComplete"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReconciliationResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Reconciliations"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/reports": {
+            "get": {
+                "description": "Returns links to all available reports",
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Reports overview",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.ReportResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/reports/health": {
+            "get": {
+                "description": "Returns health metrics for a budget at the end of a month: the age of money, the days of buffer and the months in which envelopes were overspent or the amount available to budget was negative",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Budget health",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the budget",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2022-07",
+                        "description": "Year and month in YYYY-MM format",
+                        "name": "month",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.HealthReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.HealthReportResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.HealthReportResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.HealthReportResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/reports/payees": {
+            "get": {
+                "description": "Returns the outflow to and inflow from every external account with transactions in the specified time range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Spending by payee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the budget",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "fromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "untilDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by envelope ID",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category ID",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "OUTFLOW",
+                            "INFLOW"
+                        ],
+                        "type": "string",
+                        "description": "Rank by total outflow or total inflow. Defaults to OUTFLOW.",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeReportResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeReportResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.PayeeReportResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/reports/tags": {
+            "get": {
+                "description": "Returns the outflow to and inflow from external accounts for every tag of the budget. Transfers between internal accounts are not counted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Totals by tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the budget",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "fromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "untilDate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagReportResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagReportResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagReportResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/search": {
+            "get": {
+                "description": "Searches the names of accounts, categories, envelopes and goals and the notes of transactions of a budget. All words need to match, words match as prefixes and diacritics are ignored.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the budget",
+                        "name": "budget",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The words to search for",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results to return. Defaults to 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.SearchResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.SearchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.SearchResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/tags": {
+            "get": {
+                "description": "Returns a list of tags",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Get tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by note",
+                        "name": "note",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by budget ID",
+                        "name": "budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search for this text in name and note",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset of the first Tag returned. Defaults to 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of Tags to return. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates new tags",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Create tags",
+                "parameters": [
+                    {
+                        "description": "Tags",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v4.TagEditable"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagCreateResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagCreateResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagCreateResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/tags/{id}": {
+            "get": {
+                "description": "Returns a specific tag",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Get tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a tag and moves it to the trash. Transactions keep existing, but lose the tag.",
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Delete tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update an existing tag. Only values to be updated need to be specified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Update tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagEditable"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TagResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/templates": {
+            "get": {
+                "description": "Returns all built-in templates for the category and envelope structure of budgets. Templates are applied with POST /v4/budgets/{id}/template.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "List templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TemplateListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TemplateListResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/templates/{id}": {
+            "get": {
+                "description": "Returns a specific template",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the template",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TemplateResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TemplateResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the template",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/transactions": {
+            "get": {
+                "description": "Returns a list of transactions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "fromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "untilDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Availability date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "availableFromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions available at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "availableFromFromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions available before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "availableFromUntilDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by amount",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount less than or equal to this",
+                        "name": "amountLessOrEqual",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount more than or equal to this",
+                        "name": "amountMoreOrEqual",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by note",
+                        "name": "note",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by budget ID",
+                        "name": "budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by ID of associated account, regardeless of source or destination",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by source account ID",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by destination account ID",
+                        "name": "destination",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "IN",
+                            "OUT",
+                            "INTERNAL"
+                        ],
+                        "type": "string",
+                        "description": "Filter by direction of transaction",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by envelope ID",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Cleared state in source account. Reconciled transactions are cleared.",
+                        "name": "clearedSource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Cleared state in destination account. Reconciled transactions are cleared.",
+                        "name": "clearedDestination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reconcilication state in source account",
+                        "name": "reconciledSource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reconcilication state in destination account",
+                        "name": "reconciledDestination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag IDs, separated by commas",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ANY",
+                            "ALL"
+                        ],
+                        "type": "string",
+                        "description": "Do transactions need to have any or all of the tags? Defaults to ANY",
+                        "name": "tagMatch",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. amount \u003e 50 and (envelope = 'Food' or note ~ 'pizza') and date \u003e= 2024-01-01. Supports and, or, not and parentheses. Fields are amount, date, availableFrom, note, envelope, category, tag, budget, account, source, destination, direction, type, clearedSource, clearedDestination, reconciledSource and reconciledDestination. Resources are referenced by quoted name or ID",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset of the first Transaction returned. Defaults to 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of Transactions to return. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are date, availableFrom, amount, createdAt. Defaults to -date,-createdAt",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates transactions from the list of submitted transaction data. The response code is the highest response code number that a single transaction creation would have caused. If it is not equal to 201, at least one transaction has an error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Create transactions",
+                "parameters": [
+                    {
+                        "description": "Transactions",
+                        "name": "transactions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v4.TransactionEditable"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionCreateResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionCreateResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionCreateResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes all transactions matching the query parameters and the IDs in the body and moves them to the trash. All transactions are deleted in one database transaction, if any deletion fails, no transaction is deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Delete transactions",
+                "parameters": [
+                    {
+                        "description": "IDs of the transactions",
+                        "name": "transactions",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkDelete"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "fromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "untilDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Availability date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "availableFromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions available at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "availableFromFromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions available before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "availableFromUntilDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by amount",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount less than or equal to this",
+                        "name": "amountLessOrEqual",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount more than or equal to this",
+                        "name": "amountMoreOrEqual",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by note",
+                        "name": "note",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by budget ID",
+                        "name": "budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by ID of associated account, regardeless of source or destination",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by source account ID",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by destination account ID",
+                        "name": "destination",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "IN",
+                            "OUT",
+                            "INTERNAL"
+                        ],
+                        "type": "string",
+                        "description": "Filter by direction of transaction",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by envelope ID",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Cleared state in source account. Reconciled transactions are cleared.",
+                        "name": "clearedSource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Cleared state in destination account. Reconciled transactions are cleared.",
+                        "name": "clearedDestination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reconcilication state in source account",
+                        "name": "reconciledSource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reconcilication state in destination account",
+                        "name": "reconciledDestination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag IDs, separated by commas",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ANY",
+                            "ALL"
+                        ],
+                        "type": "string",
+                        "description": "Do transactions need to have any or all of the tags? Defaults to ANY",
+                        "name": "tagMatch",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. amount \u003e 50 and (envelope = 'Food' or note ~ 'pizza') and date \u003e= 2024-01-01. Supports and, or, not and parentheses. Fields are amount, date, availableFrom, note, envelope, category, tag, budget, account, source, destination, direction, type, clearedSource, clearedDestination, reconciledSource and reconciledDestination. Resources are referenced by quoted name or ID",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would be changed without changing anything",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates all transactions matching the query parameters and the IDs in the body. Only values to be updated need to be specified. All transactions are updated in one database transaction, if any update fails, no transaction is updated. The response code is the highest response code number that a single update would have caused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Update transactions",
+                "parameters": [
+                    {
+                        "description": "Values to update and optionally the IDs of the transactions",
+                        "name": "transactions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "fromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "untilDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Availability date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "availableFromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions available at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "availableFromFromDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transactions available before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided.",
+                        "name": "availableFromUntilDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by amount",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount less than or equal to this",
+                        "name": "amountLessOrEqual",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount more than or equal to this",
+                        "name": "amountMoreOrEqual",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by note",
+                        "name": "note",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by budget ID",
+                        "name": "budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by ID of associated account, regardeless of source or destination",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by source account ID",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by destination account ID",
+                        "name": "destination",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "IN",
+                            "OUT",
+                            "INTERNAL"
+                        ],
+                        "type": "string",
+                        "description": "Filter by direction of transaction",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by envelope ID",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Cleared state in source account. Reconciled transactions are cleared.",
+                        "name": "clearedSource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Cleared state in destination account. Reconciled transactions are cleared.",
+                        "name": "clearedDestination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reconcilication state in source account",
+                        "name": "reconciledSource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reconcilication state in destination account",
+                        "name": "reconciledDestination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag IDs, separated by commas",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ANY",
+                            "ALL"
+                        ],
+                        "type": "string",
+                        "description": "Do transactions need to have any or all of the tags? Defaults to ANY",
+                        "name": "tagMatch",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. amount \u003e 50 and (envelope = 'Food' or note ~ 'pizza') and date \u003e= 2024-01-01. Supports and, or, not and parentheses. Fields are amount, date, availableFrom, note, envelope, category, tag, budget, account, source, destination, direction, type, clearedSource, clearedDestination, reconciledSource and reconciledDestination. Resources are referenced by quoted name or ID",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would be changed without changing anything",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionBulkResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/transactions/{id}": {
+            "get": {
+                "description": "Returns a specific transaction",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a transaction and moves it to the trash",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Delete transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates an existing transaction. Only values to be updated need to be specified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Update transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionEditable"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TransactionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/transactions/{id}/attachments": {
+            "get": {
+                "description": "Returns all attachments of a transaction, sorted by file name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Get attachments of a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AttachmentListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AttachmentListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AttachmentListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AttachmentListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Attaches a file to a transaction. Files can be up to 10 MiB in size and must be PDF documents, GIF, JPEG, PNG or WebP images or plain text.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Upload attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AttachmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AttachmentResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AttachmentResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.AttachmentResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/trash": {
+            "get": {
+                "description": "Returns deleted resources that can be restored, most recently deleted first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Get trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by type of the deleted resource, e.g. Envelope",
+                        "name": "model",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by ID of the deleted resource",
+                        "name": "resource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset of the first trash entry returned. Defaults to 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of trash entries to return. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are createdAt, expiresAt. Defaults to -createdAt",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TrashEntryListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TrashEntryListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TrashEntryListResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/trash/{id}": {
+            "get": {
+                "description": "Returns a specific deleted resource",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Get trash entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TrashEntryResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TrashEntryResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TrashEntryResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.TrashEntryResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently deletes a deleted resource so that it can not be restored anymore",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Delete trash entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/trash/{id}/restore": {
+            "post": {
+                "description": "Restores a deleted resource together with all resources that were deleted with it. If any of them can not be restored, none are.",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore trash entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/webhooks": {
+            "get": {
+                "description": "Returns all webhooks, sorted by URL",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates new webhooks. Events are \"envelope.negative\" and, for every type of resource, events for created, updated and deleted resources, e.g. \"transaction.created\" or \"monthConfig.updated\".\nPayloads are sent as POST requests and signed with the secret. The X-Envelope-Zero-Signature header contains \"sha256=\" followed by the hex encoded HMAC-SHA256 of the body.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create webhooks",
+                "parameters": [
+                    {
+                        "description": "Webhooks",
+                        "name": "webhooks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v4.WebhookEditable"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookCreateResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookCreateResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v4/webhooks/{id}": {
+            "get": {
+                "description": "Returns a specific webhook",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the resource, changes with every update"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently deletes a webhook together with its deliveries",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update an existing webhook. Only values to be updated need to be specified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookEditable"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the resource. If the resource has changed since, the request fails with status 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v4/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Returns the delivery log of a webhook, newest first. Failed deliveries are retried with an exponential backoff.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event",
+                        "name": "event",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status. One of PENDING, SUCCEEDED, FAILED",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset of the first delivery returned. Defaults to 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries to return. Defaults to 50.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are createdAt. Defaults to -createdAt",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of an earlier response. If the data has not changed, the response is empty with status 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookDeliveryListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookDeliveryListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookDeliveryListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.WebhookDeliveryListResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "UUID",
+                        "description": "ID of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v4.httpError"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Returns the software version of the API",
+                "tags": [
+                    "General"
+                ],
+                "summary": "API version",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/version.Response"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
+                "tags": [
+                    "General"
+                ],
+                "summary": "Allowed HTTP verbs",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AuditAction": {
+            "type": "string",
+            "enum": [
+                "CREATE",
+                "UPDATE",
+                "DELETE"
+            ],
+            "x-enum-varnames": [
+                "AuditActionCreate",
+                "AuditActionUpdate",
+                "AuditActionDelete"
+            ]
+        },
+        "models.AuditChange": {
+            "type": "object",
+            "properties": {
+                "new": {
+                    "description": "Value after the change, null for deletions"
+                },
+                "old": {
+                    "description": "Value before the change, null for creations"
+                }
+            }
+        },
+        "models.AuditChanges": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/models.AuditChange"
+            }
+        },
+        "models.BudgetEventData": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "The kind of change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AuditAction"
+                        }
+                    ],
+                    "example": "UPDATE"
+                },
+                "changes": {
+                    "description": "Changed fields with their old and new values",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AuditChanges"
+                        }
+                    ]
+                },
+                "model": {
+                    "description": "Name of the model",
+                    "type": "string",
+                    "example": "Transaction"
+                },
+                "month": {
+                    "description": "The month of month configs",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "requestId": {
+                    "description": "ID of the HTTP request that caused the change, if any",
+                    "type": "string",
+                    "example": "c6b1a2f0-5f4e-4c59-8c8e-3f2b7d0c1e9a"
+                },
+                "resourceId": {
+                    "description": "ID of the resource. For month configs, this is the ID of the envelope",
+                    "type": "string",
+                    "example": "5b2fd1ef-1ea6-4bd9-9bd9-4ab5c4f6a3b8"
+                }
+            }
+        },
+        "models.EnvelopeHistoryType": {
+            "type": "string",
+            "enum": [
+                "ROLLOVER",
+                "RESET",
+                "ALLOCATION",
+                "TRANSACTION"
+            ],
+            "x-enum-comments": {
+                "EnvelopeHistoryAllocation": "Money is allocated to the envelope",
+                "EnvelopeHistoryReset": "The negative balance at the end of the previous month is not carried over",
+                "EnvelopeHistoryRollover": "The balance at the end of the previous month is carried over",
+                "EnvelopeHistoryTransaction": "A transaction for the envelope"
+            },
+            "x-enum-descriptions": [
+                "The balance at the end of the previous month is carried over",
+                "The negative balance at the end of the previous month is not carried over",
+                "Money is allocated to the envelope",
+                "A transaction for the envelope"
+            ],
+            "x-enum-varnames": [
+                "EnvelopeHistoryRollover",
+                "EnvelopeHistoryReset",
+                "EnvelopeHistoryAllocation",
+                "EnvelopeHistoryTransaction"
+            ]
+        },
+        "models.ReconciliationState": {
+            "type": "string",
+            "enum": [
+                "IN_PROGRESS",
+                "COMPLETED"
+            ],
+            "x-enum-varnames": [
+                "ReconciliationStateInProgress",
+                "ReconciliationStateCompleted"
+            ]
+        },
+        "models.SearchResultType": {
+            "type": "string",
+            "enum": [
+                "ACCOUNT",
+                "CATEGORY",
+                "ENVELOPE",
+                "GOAL",
+                "TRANSACTION"
+            ],
+            "x-enum-varnames": [
+                "SearchResultAccount",
+                "SearchResultCategory",
+                "SearchResultEnvelope",
+                "SearchResultGoal",
+                "SearchResultTransaction"
+            ]
+        },
+        "models.TransactionState": {
+            "type": "string",
+            "enum": [
+                "UNCLEARED",
+                "CLEARED",
+                "RECONCILED"
+            ],
+            "x-enum-varnames": [
+                "TransactionStateUncleared",
+                "TransactionStateCleared",
+                "TransactionStateReconciled"
+            ]
+        },
+        "models.TrashResource": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "The resource as it was before it was deleted",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "model": {
+                    "description": "Name of the model of the resource",
+                    "type": "string",
+                    "example": "Transaction"
+                }
+            }
+        },
+        "models.WebhookDeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "SUCCEEDED",
+                "FAILED"
+            ],
+            "x-enum-comments": {
+                "WebhookDeliveryFailed": "All attempts failed",
+                "WebhookDeliveryPending": "The delivery is waiting for its next attempt",
+                "WebhookDeliverySucceeded": "The receiver accepted the payload"
+            },
+            "x-enum-descriptions": [
+                "The delivery is waiting for its next attempt",
+                "The receiver accepted the payload",
+                "All attempts failed"
+            ],
+            "x-enum-varnames": [
+                "WebhookDeliveryPending",
+                "WebhookDeliverySucceeded",
+                "WebhookDeliveryFailed"
+            ]
+        },
+        "models.WebhookEvent": {
+            "type": "string",
+            "enum": [
+                "envelope.negative"
+            ],
+            "x-enum-varnames": [
+                "WebhookEventEnvelopeNegative"
+            ]
+        },
+        "root.Links": {
+            "type": "object",
+            "properties": {
+                "docs": {
+                    "description": "Swagger API documentation",
+                    "type": "string",
+                    "example": "https://example.com/api/docs/index.html"
+                },
+                "healthz": {
+                    "description": "Healthz endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/healtzh"
+                },
+                "metrics": {
+                    "description": "Endpoint returning Prometheus metrics",
+                    "type": "string",
+                    "example": "https://example.com/api/metrics"
+                },
+                "v4": {
+                    "description": "List endpoint for all v4 endpoints",
+                    "type": "string",
+                    "example": "https://example.com/api/v4"
+                },
+                "version": {
+                    "description": "Endpoint returning the version of the backend",
+                    "type": "string",
+                    "example": "https://example.com/api/version"
+                }
+            }
+        },
+        "root.Response": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "$ref": "#/definitions/root.Links"
+                }
+            }
+        },
+        "templates.Category": {
+            "type": "object",
+            "properties": {
+                "envelopes": {
+                    "description": "Envelopes in the category",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/templates.Envelope"
+                    }
+                },
+                "name": {
+                    "description": "Name of the category",
+                    "type": "string",
+                    "example": "Daily Spending"
+                },
+                "note": {
+                    "description": "Note for the category",
+                    "type": "string",
+                    "example": "Everything we need daily"
+                }
+            }
+        },
+        "templates.Envelope": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name of the envelope",
+                    "type": "string",
+                    "example": "Groceries"
+                },
+                "note": {
+                    "description": "Note for the envelope",
+                    "type": "string",
+                    "example": "Food and household supplies"
+                }
+            }
+        },
+        "templates.Template": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "description": "Categories in the template",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/templates.Category"
+                    }
+                },
+                "description": {
+                    "description": "Description of the template",
+                    "type": "string",
+                    "example": "Common categories for a household"
+                },
+                "id": {
+                    "description": "ID of the template",
+                    "type": "string",
+                    "example": "household"
+                },
+                "name": {
+                    "description": "Name of the template",
+                    "type": "string",
+                    "example": "Household"
+                }
+            }
+        },
+        "v4.Account": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Is the account archived?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "budgetId": {
+                    "description": "ID of the budget this account belongs to",
+                    "type": "string",
+                    "example": "550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "currency": {
+                    "description": "ISO 4217 code of the currency of the account. If empty, the currency of the budget is used",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "defaultEnvelopeId": {
+                    "description": "Envelope for outgoing transactions to the account that have none set. Only external accounts can have one",
+                    "type": "string",
+                    "example": "c9e4ebe8-4b9b-4b4d-8b4a-6a1c0b2f0e51"
+                },
+                "external": {
+                    "description": "Does the account belong to the budget owner or not?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": false
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "importHash": {
+                    "description": "The SHA256 hash of a unique combination of values to use in duplicate detection for imports",
+                    "type": "string",
+                    "example": "867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70"
+                },
+                "initialBalance": {
+                    "description": "Balance of the account before any transactions were recorded",
+                    "type": "number",
+                    "default": 0,
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 173.12
+                },
+                "initialBalanceDate": {
+                    "description": "Date of the initial balance",
+                    "type": "string",
+                    "example": "2017-05-12T00:00:00Z"
+                },
+                "links": {
+                    "$ref": "#/definitions/v4.AccountLinks"
+                },
+                "name": {
+                    "description": "Name of the account",
+                    "type": "string",
+                    "example": "Cash"
+                },
+                "note": {
+                    "description": "A longer description for the account",
+                    "type": "string",
+                    "example": "Money in my wallet"
+                },
+                "onBudget": {
+                    "description": "Does the account factor into the available budget? Always false when external: true",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
+                }
+            }
+        },
+        "v4.AccountComputedData": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance of the account, including all transactions referencing it",
+                    "type": "number",
+                    "example": 2735.17
+                },
+                "budgetBalance": {
+                    "description": "Balance of the account in the currency of the budget",
+                    "type": "number",
+                    "example": 2516.36
+                },
+                "clearedBalance": {
+                    "description": "Balance of the account, including all cleared and reconciled transactions referencing it",
+                    "type": "number",
+                    "example": 2672.41
+                },
+                "id": {
+                    "description": "ID of the account",
+                    "type": "string",
+                    "example": "95018a69-758b-46c6-8bab-db70d9614f9d"
+                },
+                "reconciledBalance": {
+                    "description": "Balance of the account, including all reconciled transactions referencing it",
+                    "type": "number",
+                    "example": 2539.57
+                },
+                "unclearedBalance": {
+                    "description": "Sum of all transactions referencing the account that are neither cleared nor reconciled",
+                    "type": "number",
+                    "example": 62.76
+                }
+            }
+        },
+        "v4.AccountComputedDataResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.AccountComputedData"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "v4.AccountComputedRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "A list of UUIDs for the accounts",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time": {
+                    "description": "The time for which the computation is requested",
+                    "type": "string"
+                }
+            }
+        },
+        "v4.AccountCreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of created Accounts",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.AccountResponse"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.AccountEditable": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Is the account archived?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "budgetId": {
+                    "description": "ID of the budget this account belongs to",
+                    "type": "string",
+                    "example": "550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "currency": {
+                    "description": "ISO 4217 code of the currency of the account. If empty, the currency of the budget is used",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "defaultEnvelopeId": {
+                    "description": "Envelope for outgoing transactions to the account that have none set. Only external accounts can have one",
+                    "type": "string",
+                    "example": "c9e4ebe8-4b9b-4b4d-8b4a-6a1c0b2f0e51"
+                },
+                "external": {
+                    "description": "Does the account belong to the budget owner or not?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": false
+                },
+                "importHash": {
+                    "description": "The SHA256 hash of a unique combination of values to use in duplicate detection for imports",
+                    "type": "string",
+                    "example": "867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70"
+                },
+                "initialBalance": {
+                    "description": "Balance of the account before any transactions were recorded",
+                    "type": "number",
+                    "default": 0,
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 173.12
+                },
+                "initialBalanceDate": {
+                    "description": "Date of the initial balance",
+                    "type": "string",
+                    "example": "2017-05-12T00:00:00Z"
+                },
+                "name": {
+                    "description": "Name of the account",
+                    "type": "string",
+                    "example": "Cash"
+                },
+                "note": {
+                    "description": "A longer description for the account",
+                    "type": "string",
+                    "example": "Money in my wallet"
+                },
+                "onBudget": {
+                    "description": "Does the account factor into the available budget? Always false when external: true",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                }
+            }
+        },
+        "v4.AccountLinks": {
+            "type": "object",
+            "properties": {
+                "computedData": {
+                    "description": "Computed data endpoint for accounts",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/accounts/computed"
+                },
+                "merge": {
+                    "description": "Endpoint to merge other accounts into this one",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/merge"
+                },
+                "recentEnvelopes": {
+                    "description": "Envelopes in recent transactions where this account was the target",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/recent-envelopes"
+                },
+                "register": {
+                    "description": "Transactions of the account with running balances",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/register"
+                },
+                "self": {
+                    "description": "The account itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"
+                },
+                "transactions": {
+                    "description": "Transactions referencing the account",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/transactions?account=af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"
+                }
+            }
+        },
+        "v4.AccountListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of accounts",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Account"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "v4.AccountMerge": {
+            "type": "object",
+            "properties": {
+                "accountIds": {
+                    "description": "IDs of the accounts to merge into this account. They are deleted after their transactions and match rules have been moved",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "4c7a0d3e-5d0c-4d8e-9b6c-2f3a1e0d9c8b"
+                    ]
+                }
+            }
+        },
+        "v4.AccountRegisterEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Change of the account balance. Positive for incoming, negative for outgoing transactions",
+                    "type": "number",
+                    "example": -32.17
+                },
+                "balance": {
+                    "description": "Balance of the account after the transaction",
+                    "type": "number",
+                    "example": 2735.17
+                },
+                "reconciledBalance": {
+                    "description": "Reconciled balance of the account after the transaction",
+                    "type": "number",
+                    "example": 2539.57
+                },
+                "transaction": {
+                    "description": "The transaction",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Transaction"
+                        }
+                    ]
+                }
+            }
+        },
+        "v4.AccountRegisterPagination": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "The amount of transactions returned in this response",
+                    "type": "integer",
+                    "example": 25
+                },
+                "limit": {
+                    "description": "The maximum amount of transactions to return for this request",
+                    "type": "integer",
+                    "example": 50
+                },
+                "next": {
+                    "description": "The next page. Null if this is the last page",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/register?cursor=MjAy"
+                }
+            }
+        },
+        "v4.AccountRegisterResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Transactions of the account, earliest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.AccountRegisterEntry"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.AccountRegisterPagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "v4.AccountResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data for the account",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Account"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "The error, if any occurred for this transaction",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.AllocationMode": {
+            "type": "string",
+            "enum": [
+                "ALLOCATE_LAST_MONTH_BUDGET",
+                "ALLOCATE_LAST_MONTH_SPEND"
+            ],
+            "x-enum-varnames": [
+                "AllocateLastMonthBudget",
+                "AllocateLastMonthSpend"
+            ]
+        },
+        "v4.Attachment": {
+            "type": "object",
+            "properties": {
+                "contentType": {
+                    "description": "MIME type of the file",
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "fileName": {
+                    "description": "Name of the file as it was uploaded",
+                    "type": "string",
+                    "example": "receipt.pdf"
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "$ref": "#/definitions/v4.AttachmentLinks"
+                },
+                "size": {
+                    "description": "Size of the file in bytes",
+                    "type": "integer",
+                    "example": 48213
+                },
+                "transactionId": {
+                    "description": "ID of the transaction the file is attached to",
+                    "type": "string",
+                    "example": "d430d7c3-d14c-4712-9336-ee56965a6673"
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
+                }
+            }
+        },
+        "v4.AttachmentLinks": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "description": "Download of the file",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/attachments/4f1a8c2e-7b3d-4e8a-9c1f-2d6b5a4e3c21/file"
+                },
+                "self": {
+                    "description": "The attachment itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/attachments/4f1a8c2e-7b3d-4e8a-9c1f-2d6b5a4e3c21"
+                },
+                "transaction": {
+                    "description": "The transaction the file is attached to",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/transactions/d430d7c3-d14c-4712-9336-ee56965a6673"
+                }
+            }
+        },
+        "v4.AttachmentListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of attachments",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Attachment"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.AttachmentResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data for the attachment",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Attachment"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "The kind of change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AuditAction"
+                        }
+                    ],
+                    "example": "UPDATE"
+                },
+                "changes": {
+                    "description": "Changed fields, identified by their database column, with their old and new values",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.AuditChange"
+                    }
+                },
+                "id": {
+                    "description": "ID of the audit entry",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "description": "Links for the audit entry",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.AuditEntryLinks"
+                        }
+                    ]
+                },
+                "model": {
+                    "description": "Type of the resource that was changed",
+                    "type": "string",
+                    "example": "Transaction"
+                },
+                "requestId": {
+                    "description": "ID of the HTTP request that made the change, as sent in the X-Request-ID header",
+                    "type": "string",
+                    "example": "c6b1a2f0-5f4e-4c59-8c8e-3f2b7d0c1e9a"
+                },
+                "resourceId": {
+                    "description": "ID of the resource. For month configs, this is the ID of the envelope.",
+                    "type": "string",
+                    "example": "0b4ea4b5-1a8e-4c35-9fa0-1a2a1a0a4f3e"
+                },
+                "time": {
+                    "description": "Time of the change",
+                    "type": "string",
+                    "example": "2024-04-02T19:28:44.491514Z"
+                }
+            }
+        },
+        "v4.AuditEntryLinks": {
+            "type": "object",
+            "properties": {
+                "resource": {
+                    "description": "The resource that was changed. For month configs, this is the envelope.",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/transactions/0b4ea4b5-1a8e-4c35-9fa0-1a2a1a0a4f3e"
+                }
+            }
+        },
+        "v4.AuditEntryListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of audit entries",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.AuditEntry"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "v4.Budget": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "currency": {
+                    "description": "The currency for the budget",
+                    "type": "string",
+                    "example": "€"
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "$ref": "#/definitions/v4.BudgetLinks"
+                },
+                "name": {
+                    "description": "Name of the budget",
+                    "type": "string",
+                    "example": "Morre's Budget"
+                },
+                "note": {
+                    "description": "A longer description of the budget",
+                    "type": "string",
+                    "example": "My personal expenses"
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
+                }
+            }
+        },
+        "v4.BudgetAllocationMode": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "Mode to allocate budget with",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.AllocationMode"
+                        }
+                    ],
+                    "example": "ALLOCATE_LAST_MONTH_SPEND"
+                }
+            }
+        },
+        "v4.BudgetClone": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "What to copy. STRUCTURE copies accounts, categories, envelopes and tags, STRUCTURE_GOALS_MATCH_RULES adds goals and match rules, ALL adds transactions, allocations and initial balances.",
+                    "default": "STRUCTURE",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.BudgetCloneMode"
+                        }
+                    ],
+                    "example": "STRUCTURE"
+                },
+                "name": {
+                    "description": "Name of the new budget. Defaults to the name of the budget with \" (copy)\" appended.",
+                    "type": "string",
+                    "example": "Morre's Budget 2025"
+                }
+            }
+        },
+        "v4.BudgetCloneMode": {
+            "type": "string",
+            "enum": [
+                "STRUCTURE",
+                "STRUCTURE_GOALS_MATCH_RULES",
+                "ALL"
+            ],
+            "x-enum-varnames": [
+                "BudgetCloneStructure",
+                "BudgetCloneGoalsMatchRules",
+                "BudgetCloneAll"
+            ]
+        },
+        "v4.BudgetCreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of created Budgets",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.BudgetResponse"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.BudgetEditable": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "The currency for the budget",
+                    "type": "string",
+                    "example": "€"
+                },
+                "name": {
+                    "description": "Name of the budget",
+                    "type": "string",
+                    "example": "Morre's Budget"
+                },
+                "note": {
+                    "description": "A longer description of the budget",
+                    "type": "string",
+                    "example": "My personal expenses"
+                }
+            }
+        },
+        "v4.BudgetLinks": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "Accounts for this budget",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/accounts?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "categories": {
+                    "description": "Categories for this budget",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/categories?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "envelopes": {
+                    "description": "Envelopes for this budget",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/envelopes?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "matchRules": {
+                    "type": "string",
+                    "example": "https://example.com/api/v4/match-rules?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "month": {
+                    "description": "This uses 'YYYY-MM' for clients to replace with the actual year and month.",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/months?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf\u0026month=YYYY-MM"
+                },
+                "self": {
+                    "description": "The budget itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/budgets/550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "transactions": {
+                    "description": "Transactions for this budget",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/transactions?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                }
+            }
+        },
+        "v4.BudgetListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of budgets",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Budget"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "v4.BudgetResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data for the budget",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Budget"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.BudgetTemplate": {
+            "type": "object",
+            "properties": {
+                "template": {
+                    "description": "ID of the template to apply",
+                    "type": "string",
+                    "example": "household"
+                }
+            }
+        },
+        "v4.Category": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Is the category archived?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "budgetId": {
+                    "description": "ID of the budget the category belongs to",
+                    "type": "string",
+                    "example": "52d967d3-33f4-4b04-9ba7-772e5ab9d0ce"
+                },
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "envelopes": {
+                    "description": "These fields are computed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Envelope"
+                    }
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "$ref": "#/definitions/v4.CategoryLinks"
+                },
+                "name": {
+                    "description": "Name of the category",
+                    "type": "string",
+                    "example": "Saving"
+                },
+                "note": {
+                    "description": "Notes about the category",
+                    "type": "string",
+                    "example": "All envelopes for long-term saving"
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
+                }
+            }
+        },
+        "v4.CategoryCreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of the created Categories or their respective error",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.CategoryResponse"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.CategoryEditable": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Is the category archived?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "budgetId": {
+                    "description": "ID of the budget the category belongs to",
+                    "type": "string",
+                    "example": "52d967d3-33f4-4b04-9ba7-772e5ab9d0ce"
+                },
+                "name": {
+                    "description": "Name of the category",
+                    "type": "string",
+                    "example": "Saving"
+                },
+                "note": {
+                    "description": "Notes about the category",
+                    "type": "string",
+                    "example": "All envelopes for long-term saving"
+                }
+            }
+        },
+        "v4.CategoryEnvelopes": {
+            "type": "object",
+            "properties": {
+                "allocation": {
+                    "description": "Sum of allocations for the envelopes",
+                    "type": "number",
+                    "example": 90
+                },
+                "archived": {
+                    "description": "Is the category archived?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "balance": {
+                    "description": "Sum of the balances of the envelopes",
+                    "type": "number",
+                    "example": -10.13
+                },
+                "budgetId": {
+                    "description": "ID of the budget the category belongs to",
+                    "type": "string",
+                    "example": "52d967d3-33f4-4b04-9ba7-772e5ab9d0ce"
+                },
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "envelopes": {
+                    "description": "Slice of all envelopes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.EnvelopeMonth"
+                    }
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "$ref": "#/definitions/v4.CategoryLinks"
+                },
+                "name": {
+                    "description": "Name of the category",
+                    "type": "string",
+                    "example": "Saving"
+                },
+                "note": {
+                    "description": "Notes about the category",
+                    "type": "string",
+                    "example": "All envelopes for long-term saving"
+                },
+                "spent": {
+                    "description": "Sum spent for all envelopes",
+                    "type": "number",
+                    "example": 100.13
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
+                }
+            }
+        },
+        "v4.CategoryLinks": {
+            "type": "object",
+            "properties": {
+                "envelopes": {
+                    "description": "Envelopes for this category",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/envelopes?category=3b1ea324-d438-4419-882a-2fc91d71772f"
+                },
+                "self": {
+                    "description": "The category itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/categories/3b1ea324-d438-4419-882a-2fc91d71772f"
+                }
+            }
+        },
+        "v4.CategoryListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of Categories",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Category"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "v4.CategoryResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data for the Category",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Category"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.Envelope": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Is the envelope archived?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "categoryId": {
+                    "description": "ID of the category the envelope belongs to",
+                    "type": "string",
+                    "example": "878c831f-af99-4a71-b3ca-80deb7d793c1"
+                },
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "description": "Links to related resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.EnvelopeLinks"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "Name of the envelope",
+                    "type": "string",
+                    "example": "Groceries"
+                },
+                "note": {
+                    "description": "Notes about the envelope",
+                    "type": "string",
+                    "example": "For stuff bought at supermarkets and drugstores"
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
+                }
+            }
+        },
+        "v4.EnvelopeCreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data for the Envelope",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.EnvelopeResponse"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.EnvelopeEditable": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Is the envelope archived?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "categoryId": {
+                    "description": "ID of the category the envelope belongs to",
+                    "type": "string",
+                    "example": "878c831f-af99-4a71-b3ca-80deb7d793c1"
+                },
+                "name": {
+                    "description": "Name of the envelope",
+                    "type": "string",
+                    "example": "Groceries"
+                },
+                "note": {
+                    "description": "Notes about the envelope",
+                    "type": "string",
+                    "example": "For stuff bought at supermarkets and drugstores"
+                }
+            }
+        },
+        "v4.EnvelopeHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Change of the balance in the currency of the budget. For rollovers, the amount carried over",
+                    "type": "number",
+                    "example": -32.17
+                },
+                "balance": {
+                    "description": "Balance of the envelope after the change",
+                    "type": "number",
+                    "example": 67.83
+                },
+                "date": {
+                    "description": "Date of the change. For all entries except transactions, this is the first of the month",
+                    "type": "string",
+                    "example": "2024-01-15T00:00:00Z"
+                },
+                "links": {
+                    "description": "Links to related resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.EnvelopeHistoryEntryLinks"
+                        }
+                    ]
+                },
+                "note": {
+                    "description": "Note of the transaction or month config",
+                    "type": "string",
+                    "example": "Weekly groceries"
+                },
+                "transactionId": {
+                    "description": "ID of the transaction for transaction entries",
+                    "type": "string",
+                    "example": "3b1ebf0d-5a0b-4b8c-9a33-1ef15c0c4c2e"
+                },
+                "type": {
+                    "description": "The kind of change. One of ROLLOVER, RESET, ALLOCATION or TRANSACTION",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.EnvelopeHistoryType"
+                        }
+                    ],
+                    "example": "TRANSACTION"
+                }
+            }
+        },
+        "v4.EnvelopeHistoryEntryLinks": {
+            "type": "object",
+            "properties": {
+                "transaction": {
+                    "description": "The transaction for transaction entries",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/transactions/3b1ebf0d-5a0b-4b8c-9a33-1ef15c0c4c2e"
+                }
+            }
+        },
+        "v4.EnvelopeHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "History entries, earliest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.EnvelopeHistoryEntry"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.EnvelopeLinks": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "description": "The history of the envelope's balance",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166/history"
+                },
+                "merge": {
+                    "description": "Endpoint to merge other envelopes into this one",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166/merge"
+                },
+                "month": {
+                    "description": "The MonthConfig for the envelope",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166/YYYY-MM"
+                },
+                "self": {
+                    "description": "The envelope itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166"
+                },
+                "transactions": {
+                    "description": "The envelope's transactions",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/transactions?envelope=45b6b5b9-f746-4ae9-b77b-7688b91f8166"
+                }
+            }
+        },
+        "v4.EnvelopeListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of Envelopes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Envelope"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "v4.EnvelopeMerge": {
+            "type": "object",
+            "properties": {
+                "envelopeIds": {
+                    "description": "IDs of the envelopes to merge into this envelope. They are deleted after their transactions, goals and allocations have been moved",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "4c7a0d3e-5d0c-4d8e-9b6c-2f3a1e0d9c8b"
+                    ]
+                }
+            }
+        },
+        "v4.EnvelopeMonth": {
+            "type": "object",
+            "properties": {
+                "allocation": {
+                    "description": "The amount of money allocated",
+                    "type": "number",
+                    "example": 85.44
+                },
+                "archived": {
+                    "description": "Is the envelope archived?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "balance": {
+                    "description": "The balance at the end of the monht",
+                    "type": "number",
+                    "example": 12.32
+                },
+                "categoryId": {
+                    "description": "ID of the category the envelope belongs to",
+                    "type": "string",
+                    "example": "878c831f-af99-4a71-b3ca-80deb7d793c1"
+                },
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "description": "Links to related resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.EnvelopeLinks"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "Name of the envelope",
+                    "type": "string",
+                    "example": "Groceries"
+                },
+                "note": {
+                    "description": "Notes about the envelope",
+                    "type": "string",
+                    "example": "For stuff bought at supermarkets and drugstores"
+                },
+                "spent": {
+                    "description": "The amount spent over the whole month",
+                    "type": "number",
+                    "example": 73.12
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
+                }
+            }
+        },
+        "v4.EnvelopeResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data for the Envelope",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Envelope"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "budgetId": {
+                    "description": "ID of the budget the exchange rate belongs to",
+                    "type": "string",
+                    "example": "550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "currency": {
+                    "description": "ISO 4217 code of the currency",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "description": "Date from which on the rate is valid. The time is always set to midnight UTC",
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00Z"
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "description": "Links for the exchange rate",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.ExchangeRateLinks"
+                        }
+                    ]
+                },
+                "rate": {
+                    "description": "Value of one unit of the currency in the currency of the budget",
+                    "type": "number",
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 0.92
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
+                }
+            }
+        },
+        "v4.ExchangeRateCreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of created resources",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.ExchangeRateResponse"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.ExchangeRateEditable": {
+            "type": "object",
+            "properties": {
+                "budgetId": {
+                    "description": "ID of the budget the exchange rate belongs to",
+                    "type": "string",
+                    "example": "550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "currency": {
+                    "description": "ISO 4217 code of the currency",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "description": "Date from which on the rate is valid. The time is always set to midnight UTC",
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00Z"
+                },
+                "rate": {
+                    "description": "Value of one unit of the currency in the currency of the budget",
+                    "type": "number",
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 0.92
+                }
+            }
+        },
+        "v4.ExchangeRateLinks": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "description": "The budget the exchange rate belongs to",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/budgets/550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "self": {
+                    "description": "The exchange rate itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/exchange-rates/b5ef3b7c-6d9f-4a51-9c5d-3d2c0b7b0a19"
+                }
+            }
+        },
+        "v4.ExchangeRateListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of resources",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.ExchangeRate"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "v4.ExchangeRateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "The resource",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.ExchangeRate"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.ExportResponse": {
+            "type": "object",
+            "properties": {
+                "clacks": {
+                    "description": "This will always have the value \"GNU Terry Pratchett\"",
+                    "type": "string"
+                },
+                "creationTime": {
+                    "description": "Time the export was created",
+                    "type": "string"
+                },
+                "data": {
+                    "description": "The exported data",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "version": {
+                    "description": "The version of the backend the export was made with",
+                    "type": "string"
+                }
+            }
+        },
+        "v4.Goal": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "How much money should be saved for this goal?",
+                    "type": "number",
+                    "default": 0,
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 750
+                },
+                "archived": {
+                    "description": "If this goal is still in use or not",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "envelopeId": {
+                    "description": "The ID of the envelope this goal is for",
+                    "type": "string",
+                    "example": "f81566d9-af4d-4f13-9830-c62c4b5e4c7e"
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "$ref": "#/definitions/v4.GoalLinks"
+                },
+                "month": {
+                    "description": "The month the goal should be reached",
+                    "type": "string",
+                    "example": "2024-07-01T00:00:00.000000Z"
+                },
+                "name": {
+                    "description": "Name of the goal",
+                    "type": "string",
+                    "example": "New TV"
+                },
+                "note": {
+                    "description": "Note about the goal",
+                    "type": "string",
+                    "example": "We want to replace the old CRT TV soon-ish"
+                },
+                "period": {
+                    "description": "The period in months for when this goal should repeat. \"0\" means no repetition",
+                    "type": "integer",
+                    "default": 0,
+                    "example": 6
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
+                }
+            }
+        },
+        "v4.GoalCreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of created resources",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.GoalResponse"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.GoalEditable": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "How much money should be saved for this goal?",
+                    "type": "number",
+                    "default": 0,
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 750
+                },
+                "archived": {
+                    "description": "If this goal is still in use or not",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "envelopeId": {
+                    "description": "The ID of the envelope this goal is for",
+                    "type": "string",
+                    "example": "f81566d9-af4d-4f13-9830-c62c4b5e4c7e"
+                },
+                "month": {
+                    "description": "The month the goal should be reached",
+                    "type": "string",
+                    "example": "2024-07-01T00:00:00.000000Z"
+                },
+                "name": {
+                    "description": "Name of the goal",
+                    "type": "string",
+                    "example": "New TV"
+                },
+                "note": {
+                    "description": "Note about the goal",
+                    "type": "string",
+                    "example": "We want to replace the old CRT TV soon-ish"
+                },
+                "period": {
+                    "description": "The period in months for when this goal should repeat. \"0\" means no repetition",
+                    "type": "integer",
+                    "default": 0,
+                    "example": 6
+                }
+            }
+        },
+        "v4.GoalLinks": {
+            "type": "object",
+            "properties": {
+                "envelope": {
+                    "description": "The Envelope this goal references",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/envelopes/c1a96ae4-80e3-4827-8ed0-c7656f224fee"
+                },
+                "self": {
+                    "description": "The Goal itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/goals/438cc6c0-9baf-49fd-a75a-d76bd5cab19c"
+                }
+            }
+        },
+        "v4.GoalListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of resources",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Goal"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
                         }
-                    }
+                    ]
                 }
             }
         },
-        "/version": {
-            "get": {
-                "description": "Returns the software version of the API",
-                "tags": [
-                    "General"
-                ],
-                "summary": "API version",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/version.Response"
+        "v4.GoalResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "The resource",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Goal"
                         }
-                    }
+                    ]
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
                 }
-            },
-            "options": {
-                "description": "Returns an empty response with the HTTP Header \"allow\" set to the allowed HTTP verbs",
-                "tags": [
-                    "General"
-                ],
-                "summary": "Allowed HTTP verbs",
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
+            }
+        },
+        "v4.HealthReport": {
+            "type": "object",
+            "properties": {
+                "ageOfMoney": {
+                    "description": "Average age in days of the money spent in the most recent outflows. null if no outflow could be matched to income",
+                    "type": "integer",
+                    "example": 34
+                },
+                "availableNegativeMonths": {
+                    "description": "Months in which the amount available to budget was negative",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024-02-01T00:00:00.000000Z"
+                    ]
+                },
+                "budgetId": {
+                    "description": "ID of the budget",
+                    "type": "string",
+                    "example": "550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                },
+                "daysOfBuffer": {
+                    "description": "Number of days the on-budget balance lasts at the average daily outflow. null if there was no outflow",
+                    "type": "integer",
+                    "example": 52
+                },
+                "links": {
+                    "$ref": "#/definitions/v4.HealthReportLinks"
+                },
+                "month": {
+                    "description": "The month the metrics are calculated for",
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00.000000Z"
+                },
+                "monthsAnalyzed": {
+                    "description": "Number of months from the first month with data up to and including the requested month",
+                    "type": "integer",
+                    "example": 14
+                },
+                "overspentMonths": {
+                    "description": "Months in which at least one envelope had a negative balance",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024-03-01T00:00:00.000000Z"
+                    ]
                 }
             }
-        }
-    },
-    "definitions": {
-        "root.Links": {
+        },
+        "v4.HealthReportLinks": {
             "type": "object",
             "properties": {
-                "docs": {
-                    "description": "Swagger API documentation",
+                "budget": {
+                    "description": "The budget",
                     "type": "string",
-                    "example": "https://example.com/api/docs/index.html"
+                    "example": "https://example.com/api/v4/budgets/550dc009-cea6-4c12-b2a5-03446eb7b7cf"
                 },
-                "healthz": {
-                    "description": "Healthz endpoint",
+                "month": {
+                    "description": "The month the metrics are calculated for",
                     "type": "string",
-                    "example": "https://example.com/api/healtzh"
+                    "example": "https://example.com/api/v4/months?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf\u0026month=2024-03"
+                }
+            }
+        },
+        "v4.HealthReportResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data for the budget health report",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.HealthReport"
+                        }
+                    ]
                 },
-                "metrics": {
-                    "description": "Endpoint returning Prometheus metrics",
+                "error": {
+                    "description": "The error, if any occurred",
                     "type": "string",
-                    "example": "https://example.com/api/metrics"
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.ImportLinks": {
+            "type": "object",
+            "properties": {
+                "matchRules": {
+                    "description": "URL of YNAB Import preview endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/import/ynab-import-preview"
                 },
-                "v4": {
-                    "description": "List endpoint for all v4 endpoints",
+                "transactions": {
+                    "description": "URL of YNAB4 import endpoint",
                     "type": "string",
-                    "example": "https://example.com/api/v4"
+                    "example": "https://example.com/api/v4/import/ynab4"
+                }
+            }
+        },
+        "v4.ImportPreviewList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of transaction previews",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.TransactionPreview"
+                    }
                 },
-                "version": {
-                    "description": "Endpoint returning the version of the backend",
+                "error": {
+                    "description": "The error, if any occurred for this Match Rule",
                     "type": "string",
-                    "example": "https://example.com/api/version"
+                    "example": "the specified resource ID is not a valid UUID"
                 }
             }
         },
-        "root.Response": {
+        "v4.ImportResponse": {
             "type": "object",
             "properties": {
                 "links": {
-                    "$ref": "#/definitions/root.Links"
+                    "description": "Links for the v4 API",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.ImportLinks"
+                        }
+                    ]
                 }
             }
         },
-        "v4.Account": {
+        "v4.Links": {
             "type": "object",
             "properties": {
-                "archived": {
-                    "description": "Is the account archived?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                "accounts": {
+                    "description": "URL of Account collection endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/accounts"
                 },
-                "budgetId": {
-                    "description": "ID of the budget this account belongs to",
+                "audit": {
+                    "description": "URL of the audit log",
                     "type": "string",
-                    "example": "550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                    "example": "https://example.com/api/v4/audit"
                 },
-                "createdAt": {
-                    "description": "Time the resource was created",
+                "budgets": {
+                    "description": "URL of Budget collection endpoint",
                     "type": "string",
-                    "example": "2022-04-02T19:28:44.491514Z"
+                    "example": "https://example.com/api/v4/budgets"
                 },
-                "external": {
-                    "description": "Does the account belong to the budget owner or not?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": false
+                "categories": {
+                    "description": "URL of Category collection endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/categories"
                 },
-                "id": {
-                    "description": "UUID for the resource",
+                "envelopes": {
+                    "description": "URL of Envelope collection endpoint",
                     "type": "string",
-                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                    "example": "https://example.com/api/v4/envelopes"
                 },
-                "importHash": {
-                    "description": "The SHA256 hash of a unique combination of values to use in duplicate detection for imports",
+                "events": {
+                    "description": "URL of the event stream",
                     "type": "string",
-                    "example": "867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70"
+                    "example": "https://example.com/api/v4/events"
                 },
-                "initialBalance": {
-                    "description": "Balance of the account before any transactions were recorded",
-                    "type": "number",
-                    "default": 0,
-                    "maximum": 1000000000000,
-                    "minimum": 1e-8,
-                    "multipleOf": 1e-8,
-                    "example": 173.12
+                "exchangeRates": {
+                    "description": "URL of Exchange Rate collection endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/exchange-rates"
                 },
-                "initialBalanceDate": {
-                    "description": "Date of the initial balance",
+                "goals": {
+                    "description": "URL of goal collection endpoint",
                     "type": "string",
-                    "example": "2017-05-12T00:00:00Z"
+                    "example": "https://example.com/api/v4/goals"
                 },
-                "links": {
-                    "$ref": "#/definitions/v4.AccountLinks"
+                "import": {
+                    "description": "URL of import list endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/import"
                 },
-                "name": {
-                    "description": "Name of the account",
+                "matchRules": {
+                    "description": "URL of Match Rule collection endpoint",
                     "type": "string",
-                    "example": "Cash"
+                    "example": "https://example.com/api/v4/match-rules"
+                },
+                "months": {
+                    "description": "URL of Month endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/months"
+                },
+                "payees": {
+                    "description": "URL of Payee collection endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/payees"
+                },
+                "reconciliations": {
+                    "description": "URL of Reconciliation collection endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/reconciliations"
+                },
+                "reports": {
+                    "description": "URL of Report list endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/reports"
+                },
+                "search": {
+                    "description": "URL of the search",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/search"
+                },
+                "tags": {
+                    "description": "URL of Tag collection endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/tags"
+                },
+                "templates": {
+                    "description": "URL of budget template list endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/templates"
+                },
+                "transactions": {
+                    "description": "URL of Transaction collection endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/transactions"
+                },
+                "trash": {
+                    "description": "URL of the trash",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/trash"
+                },
+                "webhooks": {
+                    "description": "URL of Webhook collection endpoint",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/webhooks"
+                }
+            }
+        },
+        "v4.MatchRule": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "description": "The payee or account to map matching transactions to",
+                    "type": "string",
+                    "example": "f9e873c2-fb96-4367-bfb6-7ecd9bf4a6b5"
+                },
+                "createdAt": {
+                    "description": "Time the resource was created",
+                    "type": "string",
+                    "example": "2022-04-02T19:28:44.491514Z"
+                },
+                "id": {
+                    "description": "UUID for the resource",
+                    "type": "string",
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                },
+                "links": {
+                    "$ref": "#/definitions/v4.MatchRuleLinks"
                 },
-                "note": {
-                    "description": "A longer description for the account",
+                "match": {
+                    "description": "The matching applied to the opposite account. This is a glob pattern. Multiple globs are allowed. Globbing is case sensitive.",
                     "type": "string",
-                    "example": "Money in my wallet"
+                    "example": "Bank*"
                 },
-                "onBudget": {
-                    "description": "Does the account factor into the available budget? Always false when external: true",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                "priority": {
+                    "description": "The priority of the match rule",
+                    "type": "integer",
+                    "example": 3
                 },
                 "updatedAt": {
                     "description": "Last time the resource was updated",
@@ -3349,373 +10523,420 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.AccountComputedData": {
+        "v4.MatchRuleCreateResponse": {
             "type": "object",
             "properties": {
-                "balance": {
-                    "description": "Balance of the account, including all transactions referencing it",
-                    "type": "number",
-                    "example": 2735.17
+                "data": {
+                    "description": "List of created Match Rules",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.MatchRuleResponse"
+                    }
                 },
-                "id": {
-                    "description": "ID of the account",
+                "error": {
+                    "description": "The error, if any occurred",
                     "type": "string",
-                    "example": "95018a69-758b-46c6-8bab-db70d9614f9d"
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.MatchRuleEditable": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "description": "The payee or account to map matching transactions to",
+                    "type": "string",
+                    "example": "f9e873c2-fb96-4367-bfb6-7ecd9bf4a6b5"
                 },
-                "reconciledBalance": {
-                    "description": "Balance of the account, including all reconciled transactions referencing it",
-                    "type": "number",
-                    "example": 2539.57
+                "match": {
+                    "description": "The matching applied to the opposite account. This is a glob pattern. Multiple globs are allowed. Globbing is case sensitive.",
+                    "type": "string",
+                    "example": "Bank*"
+                },
+                "priority": {
+                    "description": "The priority of the match rule",
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "v4.AccountComputedDataResponse": {
+        "v4.MatchRuleLinks": {
+            "type": "object",
+            "properties": {
+                "self": {
+                    "description": "The match rule itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/match-rules/95685c82-53c6-455d-b235-f49960b73b21"
+                }
+            }
+        },
+        "v4.MatchRuleListResponse": {
             "type": "object",
             "properties": {
                 "data": {
+                    "description": "List of Match Rules",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.AccountComputedData"
+                        "$ref": "#/definitions/v4.MatchRule"
                     }
                 },
                 "error": {
-                    "type": "string"
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
                 }
             }
         },
-        "v4.AccountComputedRequest": {
+        "v4.MatchRuleResponse": {
             "type": "object",
             "properties": {
-                "ids": {
-                    "description": "A list of UUIDs for the accounts",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "data": {
+                    "description": "The Match Rule data, if creation was successful",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.MatchRule"
+                        }
+                    ]
                 },
-                "time": {
-                    "description": "The time for which the computation is requested",
-                    "type": "string"
+                "error": {
+                    "description": "The error, if any occurred for this Match Rule",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
                 }
             }
         },
-        "v4.AccountCreateResponse": {
+        "v4.Month": {
             "type": "object",
             "properties": {
-                "data": {
-                    "description": "List of created Accounts",
+                "allocation": {
+                    "description": "The sum of all allocations for this month",
+                    "type": "number",
+                    "example": 1200.5
+                },
+                "available": {
+                    "description": "The amount available to budget",
+                    "type": "number",
+                    "example": 217.34
+                },
+                "balance": {
+                    "description": "The sum of all envelope balances",
+                    "type": "number",
+                    "example": 5231.37
+                },
+                "categories": {
+                    "description": "A list of envelope month calculations grouped by category",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.AccountResponse"
+                        "$ref": "#/definitions/v4.CategoryEnvelopes"
                     }
                 },
-                "error": {
-                    "description": "The error, if any occurred",
+                "id": {
+                    "description": "The ID of the Budget",
                     "type": "string",
-                    "example": "the specified resource ID is not a valid UUID"
+                    "example": "1e777d24-3f5b-4c43-8000-04f65f895578"
+                },
+                "income": {
+                    "description": "The total income for the month (sum of all incoming transactions without an Envelope)",
+                    "type": "number",
+                    "example": 2317.34
+                },
+                "month": {
+                    "description": "The month",
+                    "type": "string",
+                    "example": "2006-05-01T00:00:00.000000Z"
+                },
+                "name": {
+                    "description": "The name of the Budget",
+                    "type": "string",
+                    "example": "Zero budget"
+                },
+                "spent": {
+                    "description": "The amount of money spent in this month",
+                    "type": "number",
+                    "example": 133.7
                 }
             }
         },
-        "v4.AccountEditable": {
+        "v4.MonthConfig": {
             "type": "object",
             "properties": {
-                "archived": {
-                    "description": "Is the account archived?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                "allocation": {
+                    "description": "The maximum value is \"999999999999.99999999\", swagger unfortunately rounds this.",
+                    "type": "number",
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 22.01
                 },
-                "budgetId": {
-                    "description": "ID of the budget this account belongs to",
+                "envelopeID": {
+                    "description": "We do not use the default model here, we use envelope ID and month",
+                    "type": "string"
+                },
+                "envelopeId": {
+                    "description": "ID of the envelope",
                     "type": "string",
-                    "example": "550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                    "example": "10b9705d-3356-459e-9d5a-28d42a6c4547"
                 },
-                "external": {
-                    "description": "Does the account belong to the budget owner or not?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": false
+                "links": {
+                    "$ref": "#/definitions/v4.MonthConfigLinks"
                 },
-                "importHash": {
-                    "description": "The SHA256 hash of a unique combination of values to use in duplicate detection for imports",
-                    "type": "string",
-                    "example": "867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70"
+                "month": {
+                    "description": "We do not use the default model here, we use envelope ID and month",
+                    "type": "string"
                 },
-                "initialBalance": {
-                    "description": "Balance of the account before any transactions were recorded",
+                "note": {
+                    "description": "A note for the month config",
+                    "type": "string",
+                    "example": "Added 200€ here because we replaced Tim's expensive vase"
+                }
+            }
+        },
+        "v4.MonthConfigEditable": {
+            "type": "object",
+            "properties": {
+                "allocation": {
+                    "description": "The maximum value is \"999999999999.99999999\", swagger unfortunately rounds this.",
                     "type": "number",
-                    "default": 0,
                     "maximum": 1000000000000,
                     "minimum": 1e-8,
                     "multipleOf": 1e-8,
-                    "example": 173.12
+                    "example": 22.01
                 },
-                "initialBalanceDate": {
-                    "description": "Date of the initial balance",
+                "envelopeId": {
+                    "description": "ID of the envelope",
                     "type": "string",
-                    "example": "2017-05-12T00:00:00Z"
+                    "example": "10b9705d-3356-459e-9d5a-28d42a6c4547"
                 },
-                "name": {
-                    "description": "Name of the account",
+                "month": {
+                    "description": "The month. This is always set to 00:00 UTC on the first of the month.",
                     "type": "string",
-                    "example": "Cash"
+                    "example": "1969-06-01T00:00:00.000000Z"
                 },
                 "note": {
-                    "description": "A longer description for the account",
+                    "description": "A note for the month config",
                     "type": "string",
-                    "example": "Money in my wallet"
-                },
-                "onBudget": {
-                    "description": "Does the account factor into the available budget? Always false when external: true",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                    "example": "Added 200€ here because we replaced Tim's expensive vase"
                 }
             }
         },
-        "v4.AccountLinks": {
+        "v4.MonthConfigLinks": {
             "type": "object",
             "properties": {
-                "computedData": {
-                    "description": "Computed data endpoint for accounts",
-                    "type": "string",
-                    "example": "https://example.com/api/v4/accounts/computed"
-                },
-                "recentEnvelopes": {
-                    "description": "Envelopes in recent transactions where this account was the target",
+                "envelope": {
+                    "description": "The Envelope this config belongs to",
                     "type": "string",
-                    "example": "https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/recent-envelopes"
+                    "example": "https://example.com/api/v4/envelopes/61027ebb-ab75-4a49-9e23-a104ddd9ba6b"
                 },
                 "self": {
-                    "description": "The account itself",
+                    "description": "The Month Config itself",
                     "type": "string",
-                    "example": "https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"
+                    "example": "https://example.com/api/v4/envelopes/61027ebb-ab75-4a49-9e23-a104ddd9ba6b/2017-10"
+                }
+            }
+        },
+        "v4.MonthConfigResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Config for the month",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.MonthConfig"
+                        }
+                    ]
                 },
-                "transactions": {
-                    "description": "Transactions referencing the account",
+                "error": {
+                    "description": "The error, if any occurred",
                     "type": "string",
-                    "example": "https://example.com/api/v4/transactions?account=af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"
+                    "example": "the specified resource ID is not a valid UUID"
                 }
             }
         },
-        "v4.AccountListResponse": {
+        "v4.MonthRangeResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of accounts",
+                    "description": "Data for the months, earliest month first",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.Account"
+                        "$ref": "#/definitions/v4.Month"
                     }
                 },
                 "error": {
                     "description": "The error, if any occurred",
-                    "type": "string",
-                    "example": "the specified resource ID is not a valid UUID"
-                },
-                "pagination": {
-                    "description": "Pagination information",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/v4.Pagination"
-                        }
-                    ]
+                    "type": "string"
                 }
             }
         },
-        "v4.AccountResponse": {
+        "v4.MonthResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "Data for the account",
+                    "description": "Data for the month",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.Account"
+                            "$ref": "#/definitions/v4.Month"
                         }
                     ]
                 },
                 "error": {
-                    "description": "The error, if any occurred for this transaction",
-                    "type": "string",
-                    "example": "the specified resource ID is not a valid UUID"
+                    "description": "The error, if any occurred",
+                    "type": "string"
                 }
             }
         },
-        "v4.AllocationMode": {
-            "type": "string",
-            "enum": [
-                "ALLOCATE_LAST_MONTH_BUDGET",
-                "ALLOCATE_LAST_MONTH_SPEND"
-            ],
-            "x-enum-varnames": [
-                "AllocateLastMonthBudget",
-                "AllocateLastMonthSpend"
-            ]
-        },
-        "v4.Budget": {
+        "v4.MonthTransfer": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "description": "Time the resource was created",
-                    "type": "string",
-                    "example": "2022-04-02T19:28:44.491514Z"
+                "amount": {
+                    "description": "The amount to move",
+                    "type": "number",
+                    "default": 0,
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 25
                 },
-                "currency": {
-                    "description": "The currency for the budget",
+                "budgetId": {
+                    "description": "ID of the budget",
                     "type": "string",
-                    "example": "€"
+                    "example": "81b0c9ce-6fd3-4e1e-becc-106055898a2a"
                 },
-                "id": {
-                    "description": "UUID for the resource",
-                    "type": "string",
-                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                "force": {
+                    "description": "Move the money even if the amount available to budget or the balance of the source envelope becomes negative",
+                    "type": "boolean",
+                    "default": false,
+                    "example": false
                 },
-                "links": {
-                    "$ref": "#/definitions/v4.BudgetLinks"
+                "fromEnvelopeId": {
+                    "description": "ID of the envelope to move the money from. If not set, the money is taken from the amount available to budget",
+                    "type": "string",
+                    "example": "d5a6ea4b-4e4a-4ef6-9f5d-3c1d0b4bb1a2"
                 },
-                "name": {
-                    "description": "Name of the budget",
+                "month": {
+                    "description": "The month to move the money in",
                     "type": "string",
-                    "example": "Morre's Budget"
+                    "example": "2024-07-01T00:00:00.000000Z"
                 },
                 "note": {
-                    "description": "A longer description of the budget",
+                    "description": "A note for the move. It is added to the notes of the month configs",
                     "type": "string",
-                    "example": "My personal expenses"
+                    "example": "Rent went up"
                 },
-                "updatedAt": {
-                    "description": "Last time the resource was updated",
+                "toEnvelopeId": {
+                    "description": "ID of the envelope to move the money to. If not set, the money is moved to the amount available to budget",
                     "type": "string",
-                    "example": "2022-04-17T20:14:01.048145Z"
-                }
-            }
-        },
-        "v4.BudgetAllocationMode": {
-            "type": "object",
-            "properties": {
-                "mode": {
-                    "description": "Mode to allocate budget with",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/v4.AllocationMode"
-                        }
-                    ],
-                    "example": "ALLOCATE_LAST_MONTH_SPEND"
+                    "example": "2e3a6f0b-3b4d-4a61-b6a5-36a4a8f7e5c4"
                 }
             }
         },
-        "v4.BudgetCreateResponse": {
+        "v4.Pagination": {
             "type": "object",
             "properties": {
-                "data": {
-                    "description": "List of created Budgets",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v4.BudgetResponse"
-                    }
+                "count": {
+                    "description": "The amount of records returned in this response",
+                    "type": "integer",
+                    "example": 25
                 },
-                "error": {
-                    "description": "The error, if any occurred",
-                    "type": "string",
-                    "example": "the specified resource ID is not a valid UUID"
-                }
-            }
-        },
-        "v4.BudgetEditable": {
-            "type": "object",
-            "properties": {
-                "currency": {
-                    "description": "The currency for the budget",
-                    "type": "string",
-                    "example": "€"
+                "limit": {
+                    "description": "The maximum amount of resources to return for this request",
+                    "type": "integer",
+                    "example": 25
                 },
-                "name": {
-                    "description": "Name of the budget",
+                "next": {
+                    "description": "The next page. Not set if this is the last page",
                     "type": "string",
-                    "example": "Morre's Budget"
+                    "example": "https://example.com/api/v4/transactions?cursor=eyJz"
                 },
-                "note": {
-                    "description": "A longer description of the budget",
+                "offset": {
+                    "description": "The offset for the first record returned. Always 0 when paginating with a cursor",
+                    "type": "integer",
+                    "example": 50
+                },
+                "previous": {
+                    "description": "The previous page. Not set if this is the first page",
                     "type": "string",
-                    "example": "My personal expenses"
+                    "example": "https://example.com/api/v4/transactions?cursor=eyJ"
+                },
+                "total": {
+                    "description": "The total number of resources matching the query. Always 0 when paginating with a cursor",
+                    "type": "integer",
+                    "example": 827
                 }
             }
         },
-        "v4.BudgetLinks": {
+        "v4.Payee": {
             "type": "object",
             "properties": {
-                "accounts": {
-                    "description": "Accounts for this budget",
+                "archived": {
+                    "description": "Is the payee archived?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "budgetId": {
+                    "description": "ID of the budget this payee belongs to",
                     "type": "string",
-                    "example": "https://example.com/api/v4/accounts?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                    "example": "550dc009-cea6-4c12-b2a5-03446eb7b7cf"
                 },
-                "categories": {
-                    "description": "Categories for this budget",
+                "createdAt": {
+                    "description": "Time the resource was created",
                     "type": "string",
-                    "example": "https://example.com/api/v4/categories?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                    "example": "2022-04-02T19:28:44.491514Z"
                 },
-                "envelopes": {
-                    "description": "Envelopes for this budget",
+                "currency": {
+                    "description": "ISO 4217 code of the currency of the payee. If empty, the currency of the budget is used",
                     "type": "string",
-                    "example": "https://example.com/api/v4/envelopes?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                    "example": "USD"
                 },
-                "matchRules": {
+                "defaultEnvelopeId": {
+                    "description": "Envelope for outgoing transactions to the payee that have none set",
                     "type": "string",
-                    "example": "https://example.com/api/v4/match-rules?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                    "example": "c9e4ebe8-4b9b-4b4d-8b4a-6a1c0b2f0e51"
                 },
-                "month": {
-                    "description": "This uses 'YYYY-MM' for clients to replace with the actual year and month.",
+                "id": {
+                    "description": "UUID for the resource",
                     "type": "string",
-                    "example": "https://example.com/api/v4/months?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf\u0026month=YYYY-MM"
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
                 },
-                "self": {
-                    "description": "The budget itself",
+                "links": {
+                    "$ref": "#/definitions/v4.PayeeLinks"
+                },
+                "name": {
+                    "description": "Name of the payee",
                     "type": "string",
-                    "example": "https://example.com/api/v4/budgets/550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                    "example": "Supermarket"
                 },
-                "transactions": {
-                    "description": "Transactions for this budget",
+                "note": {
+                    "description": "A longer description for the payee",
                     "type": "string",
-                    "example": "https://example.com/api/v4/transactions?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf"
+                    "example": "The one around the corner"
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
+                    "type": "string",
+                    "example": "2022-04-17T20:14:01.048145Z"
                 }
             }
         },
-        "v4.BudgetListResponse": {
+        "v4.PayeeCreateResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of budgets",
+                    "description": "List of created payees",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.Budget"
+                        "$ref": "#/definitions/v4.PayeeResponse"
                     }
                 },
-                "error": {
-                    "description": "The error, if any occurred",
-                    "type": "string",
-                    "example": "the specified resource ID is not a valid UUID"
-                },
-                "pagination": {
-                    "description": "Pagination information",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/v4.Pagination"
-                        }
-                    ]
-                }
-            }
-        },
-        "v4.BudgetResponse": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "description": "Data for the budget",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/v4.Budget"
-                        }
-                    ]
-                },
                 "error": {
                     "description": "The error, if any occurred",
                     "type": "string",
@@ -3723,214 +10944,237 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.Category": {
+        "v4.PayeeEditable": {
             "type": "object",
             "properties": {
                 "archived": {
-                    "description": "Is the category archived?",
+                    "description": "Is the payee archived?",
                     "type": "boolean",
                     "default": false,
                     "example": true
                 },
                 "budgetId": {
-                    "description": "ID of the budget the category belongs to",
+                    "description": "ID of the budget this payee belongs to",
                     "type": "string",
-                    "example": "52d967d3-33f4-4b04-9ba7-772e5ab9d0ce"
+                    "example": "550dc009-cea6-4c12-b2a5-03446eb7b7cf"
                 },
-                "createdAt": {
-                    "description": "Time the resource was created",
+                "currency": {
+                    "description": "ISO 4217 code of the currency of the payee. If empty, the currency of the budget is used",
                     "type": "string",
-                    "example": "2022-04-02T19:28:44.491514Z"
-                },
-                "envelopes": {
-                    "description": "These fields are computed",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v4.Envelope"
-                    }
+                    "example": "USD"
                 },
-                "id": {
-                    "description": "UUID for the resource",
+                "defaultEnvelopeId": {
+                    "description": "Envelope for outgoing transactions to the payee that have none set",
                     "type": "string",
-                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
-                },
-                "links": {
-                    "$ref": "#/definitions/v4.CategoryLinks"
+                    "example": "c9e4ebe8-4b9b-4b4d-8b4a-6a1c0b2f0e51"
                 },
                 "name": {
-                    "description": "Name of the category",
+                    "description": "Name of the payee",
                     "type": "string",
-                    "example": "Saving"
+                    "example": "Supermarket"
                 },
                 "note": {
-                    "description": "Notes about the category",
+                    "description": "A longer description for the payee",
                     "type": "string",
-                    "example": "All envelopes for long-term saving"
+                    "example": "The one around the corner"
+                }
+            }
+        },
+        "v4.PayeeLinks": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "description": "The payee as an account",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"
                 },
-                "updatedAt": {
-                    "description": "Last time the resource was updated",
+                "matchRules": {
+                    "description": "Match rules targeting the payee",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/match-rules?account=af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"
+                },
+                "merge": {
+                    "description": "Endpoint to merge other payees into this one",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/payees/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/merge"
+                },
+                "self": {
+                    "description": "The payee itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/payees/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"
+                },
+                "transactions": {
+                    "description": "Transactions referencing the payee",
                     "type": "string",
-                    "example": "2022-04-17T20:14:01.048145Z"
+                    "example": "https://example.com/api/v4/transactions?account=af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"
                 }
             }
         },
-        "v4.CategoryCreateResponse": {
+        "v4.PayeeListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of the created Categories or their respective error",
+                    "description": "List of payees",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.CategoryResponse"
+                        "$ref": "#/definitions/v4.Payee"
                     }
                 },
                 "error": {
                     "description": "The error, if any occurred",
                     "type": "string",
                     "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
                 }
             }
         },
-        "v4.CategoryEditable": {
+        "v4.PayeeMerge": {
             "type": "object",
             "properties": {
-                "archived": {
-                    "description": "Is the category archived?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
-                },
-                "budgetId": {
-                    "description": "ID of the budget the category belongs to",
-                    "type": "string",
-                    "example": "52d967d3-33f4-4b04-9ba7-772e5ab9d0ce"
-                },
-                "name": {
-                    "description": "Name of the category",
-                    "type": "string",
-                    "example": "Saving"
-                },
-                "note": {
-                    "description": "Notes about the category",
-                    "type": "string",
-                    "example": "All envelopes for long-term saving"
+                "payeeIds": {
+                    "description": "IDs of the payees to merge into this payee. They are deleted after their transactions and match rules have been moved",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "4c7a0d3e-5d0c-4d8e-9b6c-2f3a1e0d9c8b"
+                    ]
                 }
             }
         },
-        "v4.CategoryEnvelopes": {
+        "v4.PayeeReport": {
             "type": "object",
             "properties": {
-                "allocation": {
-                    "description": "Sum of allocations for the envelopes",
+                "accountId": {
+                    "description": "ID of the external account",
+                    "type": "string",
+                    "example": "17e30c52-c7a8-4f6d-a8bf-e9a4cd44a386"
+                },
+                "averageInflow": {
+                    "description": "Average amount of transactions from the payee",
                     "type": "number",
-                    "example": 90
+                    "example": 12.99
                 },
-                "archived": {
-                    "description": "Is the category archived?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                "averageOutflow": {
+                    "description": "Average amount of transactions to the payee",
+                    "type": "number",
+                    "example": 30.13941176
                 },
-                "balance": {
-                    "description": "Sum of the balances of the envelopes",
+                "inflow": {
+                    "description": "Sum of all transactions from the payee",
                     "type": "number",
-                    "example": -10.13
+                    "example": 12.99
                 },
-                "budgetId": {
-                    "description": "ID of the budget the category belongs to",
-                    "type": "string",
-                    "example": "52d967d3-33f4-4b04-9ba7-772e5ab9d0ce"
+                "inflowCount": {
+                    "description": "Number of transactions from the payee",
+                    "type": "integer",
+                    "example": 1
                 },
-                "createdAt": {
-                    "description": "Time the resource was created",
-                    "type": "string",
-                    "example": "2022-04-02T19:28:44.491514Z"
+                "links": {
+                    "$ref": "#/definitions/v4.PayeeReportLinks"
                 },
-                "envelopes": {
-                    "description": "Slice of all envelopes",
+                "months": {
+                    "description": "Breakdown by month, earliest month first",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.EnvelopeMonth"
+                        "$ref": "#/definitions/v4.PayeeReportMonth"
                     }
                 },
-                "id": {
-                    "description": "UUID for the resource",
-                    "type": "string",
-                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
-                },
-                "links": {
-                    "$ref": "#/definitions/v4.CategoryLinks"
-                },
                 "name": {
-                    "description": "Name of the category",
-                    "type": "string",
-                    "example": "Saving"
-                },
-                "note": {
-                    "description": "Notes about the category",
+                    "description": "Name of the external account",
                     "type": "string",
-                    "example": "All envelopes for long-term saving"
+                    "example": "Supermarket"
                 },
-                "spent": {
-                    "description": "Sum spent for all envelopes",
+                "outflow": {
+                    "description": "Sum of all transactions to the payee",
                     "type": "number",
-                    "example": 100.13
+                    "example": 512.37
                 },
-                "updatedAt": {
-                    "description": "Last time the resource was updated",
-                    "type": "string",
-                    "example": "2022-04-17T20:14:01.048145Z"
+                "outflowCount": {
+                    "description": "Number of transactions to the payee",
+                    "type": "integer",
+                    "example": 17
                 }
             }
         },
-        "v4.CategoryLinks": {
+        "v4.PayeeReportLinks": {
             "type": "object",
             "properties": {
-                "envelopes": {
-                    "description": "Envelopes for this category",
+                "account": {
+                    "description": "The external account",
                     "type": "string",
-                    "example": "https://example.com/api/v4/envelopes?category=3b1ea324-d438-4419-882a-2fc91d71772f"
+                    "example": "https://example.com/api/v4/accounts/17e30c52-c7a8-4f6d-a8bf-e9a4cd44a386"
                 },
-                "self": {
-                    "description": "The category itself",
+                "transactions": {
+                    "description": "Transactions referencing the external account",
                     "type": "string",
-                    "example": "https://example.com/api/v4/categories/3b1ea324-d438-4419-882a-2fc91d71772f"
+                    "example": "https://example.com/api/v4/transactions?account=17e30c52-c7a8-4f6d-a8bf-e9a4cd44a386"
                 }
             }
         },
-        "v4.CategoryListResponse": {
+        "v4.PayeeReportMonth": {
+            "type": "object",
+            "properties": {
+                "inflow": {
+                    "description": "Sum of all transactions from the payee in this month",
+                    "type": "number",
+                    "example": 0
+                },
+                "inflowCount": {
+                    "description": "Number of transactions from the payee in this month",
+                    "type": "integer",
+                    "example": 0
+                },
+                "month": {
+                    "description": "The month",
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00.000000Z"
+                },
+                "outflow": {
+                    "description": "Sum of all transactions to the payee in this month",
+                    "type": "number",
+                    "example": 83.12
+                },
+                "outflowCount": {
+                    "description": "Number of transactions to the payee in this month",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "v4.PayeeReportResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of Categories",
+                    "description": "Payees, ranked by the sort criterion",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.Category"
+                        "$ref": "#/definitions/v4.PayeeReport"
                     }
                 },
                 "error": {
                     "description": "The error, if any occurred",
                     "type": "string",
                     "example": "the specified resource ID is not a valid UUID"
-                },
-                "pagination": {
-                    "description": "Pagination information",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/v4.Pagination"
-                        }
-                    ]
                 }
             }
         },
-        "v4.CategoryResponse": {
+        "v4.PayeeResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "Data for the Category",
+                    "description": "Data for the payee",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.Category"
+                            "$ref": "#/definitions/v4.Payee"
                         }
                     ]
                 },
@@ -3941,47 +11185,117 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.Envelope": {
+        "v4.RecentEnvelope": {
             "type": "object",
             "properties": {
                 "archived": {
-                    "description": "Is the envelope archived?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                    "type": "boolean"
                 },
-                "categoryId": {
-                    "description": "ID of the category the envelope belongs to",
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v4.RecentEnvelopesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data for the account",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.RecentEnvelope"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred for this transaction",
                     "type": "string",
-                    "example": "878c831f-af99-4a71-b3ca-80deb7d793c1"
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.Reconciliation": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "description": "The ID of the account that is reconciled",
+                    "type": "string",
+                    "example": "f81566d9-af4d-4f13-9830-c62c4b5e4c7e"
+                },
+                "adjustmentId": {
+                    "description": "ID of the transaction created to balance the account with the statement on completion",
+                    "type": "string",
+                    "example": "0b4ea4b5-1a8e-4c35-9fa0-1a2a1a0a4f3e"
+                },
+                "completedAt": {
+                    "description": "Time the reconciliation was completed",
+                    "type": "string",
+                    "example": "2024-04-02T19:28:44.491514Z"
                 },
                 "createdAt": {
                     "description": "Time the resource was created",
                     "type": "string",
                     "example": "2022-04-02T19:28:44.491514Z"
                 },
+                "difference": {
+                    "description": "Difference between the statement balance and the balance if all listed transactions are reconciled",
+                    "type": "number",
+                    "example": -12.5
+                },
                 "id": {
                     "description": "UUID for the resource",
                     "type": "string",
                     "example": "65392deb-5e92-4268-b114-297faad6cdce"
                 },
                 "links": {
-                    "description": "Links to related resources",
+                    "description": "Links for the reconciliation",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.EnvelopeLinks"
+                            "$ref": "#/definitions/v4.ReconciliationLinks"
                         }
                     ]
                 },
-                "name": {
-                    "description": "Name of the envelope",
+                "note": {
+                    "description": "A note for the reconciliation",
                     "type": "string",
-                    "example": "Groceries"
+                    "example": "Bank statement 2024/03"
                 },
-                "note": {
-                    "description": "Notes about the envelope",
+                "reconciledBalance": {
+                    "description": "Balance of the account at the statement date including only reconciled transactions",
+                    "type": "number",
+                    "example": 1175.72
+                },
+                "state": {
+                    "description": "State of the reconciliation. Either IN_PROGRESS or COMPLETED",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ReconciliationState"
+                        }
+                    ],
+                    "example": "IN_PROGRESS"
+                },
+                "statementBalance": {
+                    "description": "Balance of the account according to the statement",
+                    "type": "number",
+                    "default": 0,
+                    "maximum": 1000000000000,
+                    "minimum": -1000000000000,
+                    "multipleOf": 1e-8,
+                    "example": 1250.72
+                },
+                "statementDate": {
+                    "description": "Date of the statement. All transactions up to and including this date are reconciled",
                     "type": "string",
-                    "example": "For stuff bought at supermarkets and drugstores"
+                    "example": "2024-03-31T00:00:00Z"
+                },
+                "transactions": {
+                    "description": "Transactions of the account up to the statement date that are not reconciled yet",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Transaction"
+                    }
                 },
                 "updatedAt": {
                     "description": "Last time the resource was updated",
@@ -3990,14 +11304,35 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.EnvelopeCreateResponse": {
+        "v4.ReconciliationComplete": {
+            "type": "object",
+            "properties": {
+                "createAdjustment": {
+                    "description": "Create a transaction for the difference to the statement balance if there is one",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "transactionIds": {
+                    "description": "IDs of the transactions to mark as reconciled. If not set, all transactions of the reconciliation are reconciled",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "f81566d9-af4d-4f13-9830-c62c4b5e4c7e"
+                    ]
+                }
+            }
+        },
+        "v4.ReconciliationCreateResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "Data for the Envelope",
+                    "description": "List of created resources",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.EnvelopeResponse"
+                        "$ref": "#/definitions/v4.ReconciliationResponse"
                     }
                 },
                 "error": {
@@ -4007,60 +11342,63 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.EnvelopeEditable": {
+        "v4.ReconciliationEditable": {
             "type": "object",
             "properties": {
-                "archived": {
-                    "description": "Is the envelope archived?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
-                },
-                "categoryId": {
-                    "description": "ID of the category the envelope belongs to",
+                "accountId": {
+                    "description": "The ID of the account that is reconciled",
                     "type": "string",
-                    "example": "878c831f-af99-4a71-b3ca-80deb7d793c1"
+                    "example": "f81566d9-af4d-4f13-9830-c62c4b5e4c7e"
                 },
-                "name": {
-                    "description": "Name of the envelope",
+                "note": {
+                    "description": "A note for the reconciliation",
                     "type": "string",
-                    "example": "Groceries"
+                    "example": "Bank statement 2024/03"
                 },
-                "note": {
-                    "description": "Notes about the envelope",
+                "statementBalance": {
+                    "description": "Balance of the account according to the statement",
+                    "type": "number",
+                    "default": 0,
+                    "maximum": 1000000000000,
+                    "minimum": -1000000000000,
+                    "multipleOf": 1e-8,
+                    "example": 1250.72
+                },
+                "statementDate": {
+                    "description": "Date of the statement. All transactions up to and including this date are reconciled",
                     "type": "string",
-                    "example": "For stuff bought at supermarkets and drugstores"
+                    "example": "2024-03-31T00:00:00Z"
                 }
             }
         },
-        "v4.EnvelopeLinks": {
+        "v4.ReconciliationLinks": {
             "type": "object",
             "properties": {
-                "month": {
-                    "description": "The MonthConfig for the envelope",
+                "account": {
+                    "description": "The account that is reconciled",
                     "type": "string",
-                    "example": "https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166/YYYY-MM"
+                    "example": "https://example.com/api/v4/accounts/c1a96ae4-80e3-4827-8ed0-c7656f224fee"
                 },
-                "self": {
-                    "description": "The envelope itself",
+                "complete": {
+                    "description": "Completes the reconciliation",
                     "type": "string",
-                    "example": "https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166"
+                    "example": "https://example.com/api/v4/reconciliations/438cc6c0-9baf-49fd-a75a-d76bd5cab19c/complete"
                 },
-                "transactions": {
-                    "description": "The envelope's transactions",
+                "self": {
+                    "description": "The reconciliation itself",
                     "type": "string",
-                    "example": "https://example.com/api/v4/transactions?envelope=45b6b5b9-f746-4ae9-b77b-7688b91f8166"
+                    "example": "https://example.com/api/v4/reconciliations/438cc6c0-9baf-49fd-a75a-d76bd5cab19c"
                 }
             }
         },
-        "v4.EnvelopeListResponse": {
+        "v4.ReconciliationListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of Envelopes",
+                    "description": "List of resources",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.Envelope"
+                        "$ref": "#/definitions/v4.Reconciliation"
                     }
                 },
                 "error": {
@@ -4078,80 +11416,79 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.EnvelopeMonth": {
+        "v4.ReconciliationResponse": {
             "type": "object",
             "properties": {
-                "allocation": {
-                    "description": "The amount of money allocated",
-                    "type": "number",
-                    "example": 85.44
-                },
-                "archived": {
-                    "description": "Is the envelope archived?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
-                },
-                "balance": {
-                    "description": "The balance at the end of the monht",
-                    "type": "number",
-                    "example": 12.32
+                "data": {
+                    "description": "The resource",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Reconciliation"
+                        }
+                    ]
                 },
-                "categoryId": {
-                    "description": "ID of the category the envelope belongs to",
+                "error": {
+                    "description": "The error, if any occurred",
                     "type": "string",
-                    "example": "878c831f-af99-4a71-b3ca-80deb7d793c1"
-                },
-                "createdAt": {
-                    "description": "Time the resource was created",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.ReportLinks": {
+            "type": "object",
+            "properties": {
+                "health": {
+                    "description": "URL of the budget health report",
                     "type": "string",
-                    "example": "2022-04-02T19:28:44.491514Z"
+                    "example": "https://example.com/api/v4/reports/health"
                 },
-                "id": {
-                    "description": "UUID for the resource",
+                "payees": {
+                    "description": "URL of the payee report",
                     "type": "string",
-                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
+                    "example": "https://example.com/api/v4/reports/payees"
                 },
+                "tags": {
+                    "description": "URL of the tag report",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/reports/tags"
+                }
+            }
+        },
+        "v4.ReportResponse": {
+            "type": "object",
+            "properties": {
                 "links": {
-                    "description": "Links to related resources",
+                    "description": "Links to all available reports",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.EnvelopeLinks"
+                            "$ref": "#/definitions/v4.ReportLinks"
                         }
                     ]
-                },
-                "name": {
-                    "description": "Name of the envelope",
-                    "type": "string",
-                    "example": "Groceries"
-                },
-                "note": {
-                    "description": "Notes about the envelope",
-                    "type": "string",
-                    "example": "For stuff bought at supermarkets and drugstores"
-                },
-                "spent": {
-                    "description": "The amount spent over the whole month",
-                    "type": "number",
-                    "example": 73.12
-                },
-                "updatedAt": {
-                    "description": "Last time the resource was updated",
-                    "type": "string",
-                    "example": "2022-04-17T20:14:01.048145Z"
                 }
             }
         },
-        "v4.EnvelopeResponse": {
+        "v4.Response": {
             "type": "object",
             "properties": {
-                "data": {
-                    "description": "Data for the Envelope",
+                "links": {
+                    "description": "Links for the v4 API",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.Envelope"
+                            "$ref": "#/definitions/v4.Links"
                         }
                     ]
+                }
+            }
+        },
+        "v4.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Matching resources, best match first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.SearchResult"
+                    }
                 },
                 "error": {
                     "description": "The error, if any occurred",
@@ -4160,90 +11497,78 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.ExportResponse": {
+        "v4.SearchResult": {
             "type": "object",
             "properties": {
-                "clacks": {
-                    "description": "This will always have the value \"GNU Terry Pratchett\"",
-                    "type": "string"
+                "id": {
+                    "description": "ID of the resource",
+                    "type": "string",
+                    "example": "a0909e84-e8f9-4cb6-82a5-025dff105ff2"
                 },
-                "creationTime": {
-                    "description": "Time the export was created",
-                    "type": "string"
+                "links": {
+                    "$ref": "#/definitions/v4.SearchResultLinks"
                 },
-                "data": {
-                    "description": "The exported data",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "type": "integer",
-                            "format": "int32"
-                        }
-                    }
+                "score": {
+                    "description": "How well the resource matches the search. Higher is better",
+                    "type": "number",
+                    "example": 2.37
                 },
-                "version": {
-                    "description": "The version of the backend the export was made with",
-                    "type": "string"
+                "text": {
+                    "description": "The text that matched. The note for transactions, the name for all other resources",
+                    "type": "string",
+                    "example": "Groceries"
+                },
+                "type": {
+                    "description": "Type of the resource",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SearchResultType"
+                        }
+                    ],
+                    "example": "ENVELOPE"
                 }
             }
         },
-        "v4.Goal": {
+        "v4.SearchResultLinks": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "description": "How much money should be saved for this goal?",
-                    "type": "number",
-                    "default": 0,
-                    "maximum": 1000000000000,
-                    "minimum": 1e-8,
-                    "multipleOf": 1e-8,
-                    "example": 750
-                },
-                "archived": {
-                    "description": "If this goal is still in use or not",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                "self": {
+                    "description": "The resource",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/envelopes/a0909e84-e8f9-4cb6-82a5-025dff105ff2"
+                }
+            }
+        },
+        "v4.Tag": {
+            "type": "object",
+            "properties": {
+                "budgetId": {
+                    "description": "ID of the budget the tag belongs to",
+                    "type": "string",
+                    "example": "52d967d3-33f4-4b04-9ba7-772e5ab9d0ce"
                 },
                 "createdAt": {
                     "description": "Time the resource was created",
                     "type": "string",
                     "example": "2022-04-02T19:28:44.491514Z"
                 },
-                "envelopeId": {
-                    "description": "The ID of the envelope this goal is for",
-                    "type": "string",
-                    "example": "f81566d9-af4d-4f13-9830-c62c4b5e4c7e"
-                },
                 "id": {
                     "description": "UUID for the resource",
                     "type": "string",
                     "example": "65392deb-5e92-4268-b114-297faad6cdce"
                 },
                 "links": {
-                    "$ref": "#/definitions/v4.GoalLinks"
-                },
-                "month": {
-                    "description": "The month the goal should be reached",
-                    "type": "string",
-                    "example": "2024-07-01T00:00:00.000000Z"
+                    "$ref": "#/definitions/v4.TagLinks"
                 },
                 "name": {
-                    "description": "Name of the goal",
+                    "description": "Name of the tag",
                     "type": "string",
-                    "example": "New TV"
+                    "example": "Vacation 2024"
                 },
                 "note": {
-                    "description": "Note about the goal",
+                    "description": "Notes about the tag",
                     "type": "string",
-                    "example": "We want to replace the old CRT TV soon-ish"
-                },
-                "period": {
-                    "description": "The period in months for when this goal should repeat. \"0\" means no repetition",
-                    "type": "integer",
-                    "default": 0,
-                    "example": 6
+                    "example": "Everything we spent on the trip to Lisbon"
                 },
                 "updatedAt": {
                     "description": "Last time the resource was updated",
@@ -4252,14 +11577,14 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.GoalCreateResponse": {
+        "v4.TagCreateResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of created resources",
+                    "description": "List of the created tags or their respective error",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.GoalResponse"
+                        "$ref": "#/definitions/v4.TagResponse"
                     }
                 },
                 "error": {
@@ -4269,100 +11594,144 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.GoalEditable": {
+        "v4.TagEditable": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "description": "How much money should be saved for this goal?",
-                    "type": "number",
-                    "default": 0,
-                    "maximum": 1000000000000,
-                    "minimum": 1e-8,
-                    "multipleOf": 1e-8,
-                    "example": 750
+                "budgetId": {
+                    "description": "ID of the budget the tag belongs to",
+                    "type": "string",
+                    "example": "52d967d3-33f4-4b04-9ba7-772e5ab9d0ce"
                 },
-                "archived": {
-                    "description": "If this goal is still in use or not",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                "name": {
+                    "description": "Name of the tag",
+                    "type": "string",
+                    "example": "Vacation 2024"
                 },
-                "envelopeId": {
-                    "description": "The ID of the envelope this goal is for",
+                "note": {
+                    "description": "Notes about the tag",
                     "type": "string",
-                    "example": "f81566d9-af4d-4f13-9830-c62c4b5e4c7e"
+                    "example": "Everything we spent on the trip to Lisbon"
+                }
+            }
+        },
+        "v4.TagLinks": {
+            "type": "object",
+            "properties": {
+                "self": {
+                    "description": "The tag itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/tags/8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"
                 },
-                "month": {
-                    "description": "The month the goal should be reached",
+                "transactions": {
+                    "description": "Transactions with this tag",
                     "type": "string",
-                    "example": "2024-07-01T00:00:00.000000Z"
+                    "example": "https://example.com/api/v4/transactions?tags=8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"
+                }
+            }
+        },
+        "v4.TagListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "List of tags",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Tag"
+                    }
                 },
-                "name": {
-                    "description": "Name of the goal",
+                "error": {
+                    "description": "The error, if any occurred",
                     "type": "string",
-                    "example": "New TV"
+                    "example": "the specified resource ID is not a valid UUID"
                 },
-                "note": {
-                    "description": "Note about the goal",
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "v4.TagReport": {
+            "type": "object",
+            "properties": {
+                "inflow": {
+                    "description": "Sum of all transactions with the tag from external accounts",
+                    "type": "number",
+                    "example": 120
+                },
+                "inflowCount": {
+                    "description": "Number of transactions with the tag from external accounts",
+                    "type": "integer",
+                    "example": 1
+                },
+                "links": {
+                    "$ref": "#/definitions/v4.TagReportLinks"
+                },
+                "name": {
+                    "description": "Name of the tag",
                     "type": "string",
-                    "example": "We want to replace the old CRT TV soon-ish"
+                    "example": "Vacation 2024"
                 },
-                "period": {
-                    "description": "The period in months for when this goal should repeat. \"0\" means no repetition",
+                "outflow": {
+                    "description": "Sum of all transactions with the tag to external accounts",
+                    "type": "number",
+                    "example": 1432.17
+                },
+                "outflowCount": {
+                    "description": "Number of transactions with the tag to external accounts",
                     "type": "integer",
-                    "default": 0,
-                    "example": 6
+                    "example": 23
+                },
+                "tagId": {
+                    "description": "ID of the tag",
+                    "type": "string",
+                    "example": "8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"
                 }
             }
         },
-        "v4.GoalLinks": {
+        "v4.TagReportLinks": {
             "type": "object",
             "properties": {
-                "envelope": {
-                    "description": "The Envelope this goal references",
+                "tag": {
+                    "description": "The tag",
                     "type": "string",
-                    "example": "https://example.com/api/v4/envelopes/c1a96ae4-80e3-4827-8ed0-c7656f224fee"
+                    "example": "https://example.com/api/v4/tags/8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"
                 },
-                "self": {
-                    "description": "The Goal itself",
+                "transactions": {
+                    "description": "Transactions with the tag",
                     "type": "string",
-                    "example": "https://example.com/api/v4/goals/438cc6c0-9baf-49fd-a75a-d76bd5cab19c"
+                    "example": "https://example.com/api/v4/transactions?tags=8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"
                 }
             }
         },
-        "v4.GoalListResponse": {
+        "v4.TagReportResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of resources",
+                    "description": "Tags of the budget, sorted by name",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.Goal"
+                        "$ref": "#/definitions/v4.TagReport"
                     }
                 },
                 "error": {
                     "description": "The error, if any occurred",
                     "type": "string",
                     "example": "the specified resource ID is not a valid UUID"
-                },
-                "pagination": {
-                    "description": "Pagination information",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/v4.Pagination"
-                        }
-                    ]
                 }
             }
         },
-        "v4.GoalResponse": {
+        "v4.TagResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "The resource",
+                    "description": "Data for the tag",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.Goal"
+                            "$ref": "#/definitions/v4.Tag"
                         }
                     ]
                 },
@@ -4373,131 +11742,160 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.ImportLinks": {
-            "type": "object",
-            "properties": {
-                "matchRules": {
-                    "description": "URL of YNAB Import preview endpoint",
-                    "type": "string",
-                    "example": "https://example.com/api/v4/import/ynab-import-preview"
-                },
-                "transactions": {
-                    "description": "URL of YNAB4 import endpoint",
-                    "type": "string",
-                    "example": "https://example.com/api/v4/import/ynab4"
-                }
-            }
-        },
-        "v4.ImportPreviewList": {
+        "v4.TemplateListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of transaction previews",
+                    "description": "List of templates",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.TransactionPreview"
+                        "$ref": "#/definitions/templates.Template"
                     }
                 },
                 "error": {
-                    "description": "The error, if any occurred for this Match Rule",
+                    "description": "The error, if any occurred",
                     "type": "string",
                     "example": "the specified resource ID is not a valid UUID"
                 }
             }
         },
-        "v4.ImportResponse": {
+        "v4.TemplateResponse": {
             "type": "object",
             "properties": {
-                "links": {
-                    "description": "Links for the v4 API",
+                "data": {
+                    "description": "Data for the template",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.ImportLinks"
+                            "$ref": "#/definitions/templates.Template"
                         }
                     ]
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "there is no template matching your query"
                 }
             }
         },
-        "v4.Links": {
+        "v4.Transaction": {
             "type": "object",
             "properties": {
-                "accounts": {
-                    "description": "URL of Account collection endpoint",
-                    "type": "string",
-                    "example": "https://example.com/api/v4/accounts"
+                "amount": {
+                    "description": "The maximum value is \"999999999999.99999999\", swagger unfortunately rounds this.",
+                    "type": "number",
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 14.03
                 },
-                "budgets": {
-                    "description": "URL of Budget collection endpoint",
+                "availableFrom": {
+                    "description": "The date from which on the transaction amount is available for budgeting. Only used for income transactions. Defaults to the transaction date.",
                     "type": "string",
-                    "example": "https://example.com/api/v4/budgets"
+                    "example": "2021-11-17T00:00:00Z"
                 },
-                "categories": {
-                    "description": "URL of Category collection endpoint",
-                    "type": "string",
-                    "example": "https://example.com/api/v4/categories"
+                "clearedDestination": {
+                    "description": "Has the transaction cleared in the destination account? Reconciled transactions are always cleared",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
                 },
-                "envelopes": {
-                    "description": "URL of Envelope collection endpoint",
-                    "type": "string",
-                    "example": "https://example.com/api/v4/envelopes"
+                "clearedSource": {
+                    "description": "Has the transaction cleared in the source account? Reconciled transactions are always cleared",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
                 },
-                "goals": {
-                    "description": "URL of goal collection endpoint",
+                "createdAt": {
+                    "description": "Time the resource was created",
                     "type": "string",
-                    "example": "https://example.com/api/v4/goals"
+                    "example": "2022-04-02T19:28:44.491514Z"
                 },
-                "import": {
-                    "description": "URL of import list endpoint",
+                "date": {
+                    "description": "Date of the transaction. Time is currently only used for sorting",
                     "type": "string",
-                    "example": "https://example.com/api/v4/import"
+                    "example": "1815-12-10T18:43:00.271152Z"
                 },
-                "matchRules": {
-                    "description": "URL of Match Rule collection endpoint",
+                "destinationAccountId": {
+                    "description": "ID of the destination account",
                     "type": "string",
-                    "example": "https://example.com/api/v4/match-rules"
+                    "example": "8e16b456-a719-48ce-9fec-e115cfa7cbcc"
                 },
-                "months": {
-                    "description": "URL of Month endpoint",
-                    "type": "string",
-                    "example": "https://example.com/api/v4/months"
+                "destinationAmount": {
+                    "description": "The amount in the currency of the destination account. Must be set if and only if the accounts have different currencies",
+                    "type": "number",
+                    "default": 0,
+                    "maximum": 1000000000000,
+                    "minimum": 0,
+                    "multipleOf": 1e-8,
+                    "example": 15.21
                 },
-                "transactions": {
-                    "description": "URL of Transaction collection endpoint",
-                    "type": "string",
-                    "example": "https://example.com/api/v4/transactions"
-                }
-            }
-        },
-        "v4.MatchRule": {
-            "type": "object",
-            "properties": {
-                "accountId": {
-                    "description": "The account to map matching transactions to",
-                    "type": "string",
-                    "example": "f9e873c2-fb96-4367-bfb6-7ecd9bf4a6b5"
+                "destinationState": {
+                    "description": "State of the transaction in the destination account. One of UNCLEARED, CLEARED or RECONCILED",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TransactionState"
+                        }
+                    ],
+                    "example": "RECONCILED"
                 },
-                "createdAt": {
-                    "description": "Time the resource was created",
+                "envelopeId": {
+                    "description": "ID of the envelope",
                     "type": "string",
-                    "example": "2022-04-02T19:28:44.491514Z"
+                    "example": "2649c965-7999-4873-ae16-89d5d5fa972e"
                 },
                 "id": {
                     "description": "UUID for the resource",
                     "type": "string",
                     "example": "65392deb-5e92-4268-b114-297faad6cdce"
                 },
+                "importHash": {
+                    "description": "The SHA256 hash of a unique combination of values to use in duplicate detection",
+                    "type": "string",
+                    "example": "867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70"
+                },
                 "links": {
-                    "$ref": "#/definitions/v4.MatchRuleLinks"
+                    "$ref": "#/definitions/v4.TransactionLinks"
                 },
-                "match": {
-                    "description": "The matching applied to the opposite account. This is a glob pattern. Multiple globs are allowed. Globbing is case sensitive.",
+                "note": {
+                    "description": "A note",
                     "type": "string",
-                    "example": "Bank*"
+                    "example": "Lunch"
                 },
-                "priority": {
-                    "description": "The priority of the match rule",
-                    "type": "integer",
-                    "example": 3
+                "reconciledDestination": {
+                    "description": "Is the transaction reconciled in the destination account?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "reconciledSource": {
+                    "description": "Is the transaction reconciled in the source account? Reconciled transactions can only be changed when they are set to not reconciled in the same request",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "sourceAccountId": {
+                    "description": "ID of the source account",
+                    "type": "string",
+                    "example": "fd81dc45-a3a2-468e-a6fa-b2618f30aa45"
+                },
+                "sourceState": {
+                    "description": "State of the transaction in the source account. One of UNCLEARED, CLEARED or RECONCILED",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TransactionState"
+                        }
+                    ],
+                    "example": "CLEARED"
+                },
+                "tagIds": {
+                    "description": "IDs of the tags of the transaction. On updates, the tags are replaced with these",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"
+                    ]
                 },
                 "updatedAt": {
                     "description": "Last time the resource was updated",
@@ -4506,415 +11904,495 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.MatchRuleCreateResponse": {
+        "v4.TransactionBulkDelete": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "IDs of the transactions to delete. If set, only transactions with these IDs that also match the query parameters are deleted.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "d430d7c3-d14c-4712-9336-ee56965a6673"
+                    ]
+                }
+            }
+        },
+        "v4.TransactionBulkResponse": {
             "type": "object",
             "properties": {
+                "count": {
+                    "description": "Number of transactions matching the request",
+                    "type": "integer",
+                    "example": 300
+                },
                 "data": {
-                    "description": "List of created Match Rules",
+                    "description": "One entry per matching transaction. For deletions and failed changes, this is the transaction as it was before.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.MatchRuleResponse"
+                        "$ref": "#/definitions/v4.TransactionResponse"
                     }
                 },
                 "error": {
                     "description": "The error, if any occurred",
                     "type": "string",
-                    "example": "the specified resource ID is not a valid UUID"
+                    "example": "either transaction IDs or at least one filter must be set"
                 }
             }
         },
-        "v4.MatchRuleEditable": {
+        "v4.TransactionBulkUpdate": {
             "type": "object",
             "properties": {
-                "accountId": {
-                    "description": "The account to map matching transactions to",
+                "amount": {
+                    "description": "The maximum value is \"999999999999.99999999\", swagger unfortunately rounds this.",
+                    "type": "number",
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 14.03
+                },
+                "availableFrom": {
+                    "description": "The date from which on the transaction amount is available for budgeting. Only used for income transactions. Defaults to the transaction date.",
                     "type": "string",
-                    "example": "f9e873c2-fb96-4367-bfb6-7ecd9bf4a6b5"
+                    "example": "2021-11-17T00:00:00Z"
+                },
+                "clearedDestination": {
+                    "description": "Has the transaction cleared in the destination account? Reconciled transactions are always cleared",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "clearedSource": {
+                    "description": "Has the transaction cleared in the source account? Reconciled transactions are always cleared",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "date": {
+                    "description": "Date of the transaction. Time is currently only used for sorting",
+                    "type": "string",
+                    "example": "1815-12-10T18:43:00.271152Z"
+                },
+                "destinationAccountId": {
+                    "description": "ID of the destination account",
+                    "type": "string",
+                    "example": "8e16b456-a719-48ce-9fec-e115cfa7cbcc"
+                },
+                "destinationAmount": {
+                    "description": "The amount in the currency of the destination account. Must be set if and only if the accounts have different currencies",
+                    "type": "number",
+                    "default": 0,
+                    "maximum": 1000000000000,
+                    "minimum": 0,
+                    "multipleOf": 1e-8,
+                    "example": 15.21
+                },
+                "envelopeId": {
+                    "description": "ID of the envelope",
+                    "type": "string",
+                    "example": "2649c965-7999-4873-ae16-89d5d5fa972e"
+                },
+                "ids": {
+                    "description": "IDs of the transactions to update. If set, only transactions with these IDs that also match the query parameters are updated.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "d430d7c3-d14c-4712-9336-ee56965a6673"
+                    ]
+                },
+                "importHash": {
+                    "description": "The SHA256 hash of a unique combination of values to use in duplicate detection",
+                    "type": "string",
+                    "example": "867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70"
+                },
+                "note": {
+                    "description": "A note",
+                    "type": "string",
+                    "example": "Lunch"
+                },
+                "reconciledDestination": {
+                    "description": "Is the transaction reconciled in the destination account?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "reconciledSource": {
+                    "description": "Is the transaction reconciled in the source account? Reconciled transactions can only be changed when they are set to not reconciled in the same request",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
                 },
-                "match": {
-                    "description": "The matching applied to the opposite account. This is a glob pattern. Multiple globs are allowed. Globbing is case sensitive.",
+                "sourceAccountId": {
+                    "description": "ID of the source account",
                     "type": "string",
-                    "example": "Bank*"
+                    "example": "fd81dc45-a3a2-468e-a6fa-b2618f30aa45"
                 },
-                "priority": {
-                    "description": "The priority of the match rule",
-                    "type": "integer",
-                    "example": 3
-                }
-            }
-        },
-        "v4.MatchRuleLinks": {
-            "type": "object",
-            "properties": {
-                "self": {
-                    "description": "The match rule itself",
-                    "type": "string",
-                    "example": "https://example.com/api/v4/match-rules/95685c82-53c6-455d-b235-f49960b73b21"
+                "tagIds": {
+                    "description": "IDs of the tags of the transaction. On updates, the tags are replaced with these",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"
+                    ]
                 }
             }
         },
-        "v4.MatchRuleListResponse": {
+        "v4.TransactionCreateResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of Match Rules",
+                    "description": "List of created Transactions",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.MatchRule"
+                        "$ref": "#/definitions/v4.TransactionResponse"
                     }
                 },
                 "error": {
                     "description": "The error, if any occurred",
                     "type": "string",
                     "example": "the specified resource ID is not a valid UUID"
-                },
-                "pagination": {
-                    "description": "Pagination information",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/v4.Pagination"
-                        }
-                    ]
-                }
-            }
-        },
-        "v4.MatchRuleResponse": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "description": "The Match Rule data, if creation was successful",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/v4.MatchRule"
-                        }
-                    ]
-                },
-                "error": {
-                    "description": "The error, if any occurred for this Match Rule",
-                    "type": "string",
-                    "example": "the specified resource ID is not a valid UUID"
                 }
             }
         },
-        "v4.Month": {
+        "v4.TransactionEditable": {
             "type": "object",
             "properties": {
-                "allocation": {
-                    "description": "The sum of all allocations for this month",
-                    "type": "number",
-                    "example": 1200.5
-                },
-                "available": {
-                    "description": "The amount available to budget",
-                    "type": "number",
-                    "example": 217.34
-                },
-                "balance": {
-                    "description": "The sum of all envelope balances",
+                "amount": {
+                    "description": "The maximum value is \"999999999999.99999999\", swagger unfortunately rounds this.",
                     "type": "number",
-                    "example": 5231.37
-                },
-                "categories": {
-                    "description": "A list of envelope month calculations grouped by category",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v4.CategoryEnvelopes"
-                    }
+                    "maximum": 1000000000000,
+                    "minimum": 1e-8,
+                    "multipleOf": 1e-8,
+                    "example": 14.03
                 },
-                "id": {
-                    "description": "The ID of the Budget",
+                "availableFrom": {
+                    "description": "The date from which on the transaction amount is available for budgeting. Only used for income transactions. Defaults to the transaction date.",
                     "type": "string",
-                    "example": "1e777d24-3f5b-4c43-8000-04f65f895578"
+                    "example": "2021-11-17T00:00:00Z"
                 },
-                "income": {
-                    "description": "The total income for the month (sum of all incoming transactions without an Envelope)",
-                    "type": "number",
-                    "example": 2317.34
+                "clearedDestination": {
+                    "description": "Has the transaction cleared in the destination account? Reconciled transactions are always cleared",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
                 },
-                "month": {
-                    "description": "The month",
+                "clearedSource": {
+                    "description": "Has the transaction cleared in the source account? Reconciled transactions are always cleared",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "date": {
+                    "description": "Date of the transaction. Time is currently only used for sorting",
                     "type": "string",
-                    "example": "2006-05-01T00:00:00.000000Z"
+                    "example": "1815-12-10T18:43:00.271152Z"
                 },
-                "name": {
-                    "description": "The name of the Budget",
+                "destinationAccountId": {
+                    "description": "ID of the destination account",
                     "type": "string",
-                    "example": "Zero budget"
+                    "example": "8e16b456-a719-48ce-9fec-e115cfa7cbcc"
                 },
-                "spent": {
-                    "description": "The amount of money spent in this month",
-                    "type": "number",
-                    "example": 133.7
-                }
-            }
-        },
-        "v4.MonthConfig": {
-            "type": "object",
-            "properties": {
-                "allocation": {
-                    "description": "The maximum value is \"999999999999.99999999\", swagger unfortunately rounds this.",
+                "destinationAmount": {
+                    "description": "The amount in the currency of the destination account. Must be set if and only if the accounts have different currencies",
                     "type": "number",
+                    "default": 0,
                     "maximum": 1000000000000,
-                    "minimum": 1e-8,
+                    "minimum": 0,
                     "multipleOf": 1e-8,
-                    "example": 22.01
-                },
-                "envelopeID": {
-                    "description": "We do not use the default model here, we use envelope ID and month",
-                    "type": "string"
+                    "example": 15.21
                 },
                 "envelopeId": {
                     "description": "ID of the envelope",
                     "type": "string",
-                    "example": "10b9705d-3356-459e-9d5a-28d42a6c4547"
-                },
-                "links": {
-                    "$ref": "#/definitions/v4.MonthConfigLinks"
+                    "example": "2649c965-7999-4873-ae16-89d5d5fa972e"
                 },
-                "month": {
-                    "description": "We do not use the default model here, we use envelope ID and month",
-                    "type": "string"
+                "importHash": {
+                    "description": "The SHA256 hash of a unique combination of values to use in duplicate detection",
+                    "type": "string",
+                    "example": "867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70"
                 },
                 "note": {
-                    "description": "A note for the month config",
+                    "description": "A note",
                     "type": "string",
-                    "example": "Added 200€ here because we replaced Tim's expensive vase"
-                }
-            }
-        },
-        "v4.MonthConfigEditable": {
-            "type": "object",
-            "properties": {
-                "allocation": {
-                    "description": "The maximum value is \"999999999999.99999999\", swagger unfortunately rounds this.",
-                    "type": "number",
-                    "maximum": 1000000000000,
-                    "minimum": 1e-8,
-                    "multipleOf": 1e-8,
-                    "example": 22.01
+                    "example": "Lunch"
                 },
-                "envelopeId": {
-                    "description": "ID of the envelope",
-                    "type": "string",
-                    "example": "10b9705d-3356-459e-9d5a-28d42a6c4547"
+                "reconciledDestination": {
+                    "description": "Is the transaction reconciled in the destination account?",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
                 },
-                "month": {
-                    "description": "The month. This is always set to 00:00 UTC on the first of the month.",
-                    "type": "string",
-                    "example": "1969-06-01T00:00:00.000000Z"
+                "reconciledSource": {
+                    "description": "Is the transaction reconciled in the source account? Reconciled transactions can only be changed when they are set to not reconciled in the same request",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
                 },
-                "note": {
-                    "description": "A note for the month config",
+                "sourceAccountId": {
+                    "description": "ID of the source account",
                     "type": "string",
-                    "example": "Added 200€ here because we replaced Tim's expensive vase"
+                    "example": "fd81dc45-a3a2-468e-a6fa-b2618f30aa45"
+                },
+                "tagIds": {
+                    "description": "IDs of the tags of the transaction. On updates, the tags are replaced with these",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"
+                    ]
                 }
             }
         },
-        "v4.MonthConfigLinks": {
+        "v4.TransactionLinks": {
             "type": "object",
             "properties": {
-                "envelope": {
-                    "description": "The Envelope this config belongs to",
+                "attachments": {
+                    "description": "Attachments of the transaction",
                     "type": "string",
-                    "example": "https://example.com/api/v4/envelopes/61027ebb-ab75-4a49-9e23-a104ddd9ba6b"
+                    "example": "https://example.com/api/v4/transactions/d430d7c3-d14c-4712-9336-ee56965a6673/attachments"
                 },
                 "self": {
-                    "description": "The Month Config itself",
+                    "description": "The transaction itself",
                     "type": "string",
-                    "example": "https://example.com/api/v4/envelopes/61027ebb-ab75-4a49-9e23-a104ddd9ba6b/2017-10"
+                    "example": "https://example.com/api/v4/transactions/d430d7c3-d14c-4712-9336-ee56965a6673"
                 }
             }
         },
-        "v4.MonthConfigResponse": {
+        "v4.TransactionListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "Config for the month",
+                    "description": "List of transactions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Transaction"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.MonthConfig"
+                            "$ref": "#/definitions/v4.Pagination"
                         }
                     ]
+                }
+            }
+        },
+        "v4.TransactionPreview": {
+            "type": "object",
+            "properties": {
+                "destinationAccountName": {
+                    "description": "Name of the destination account from the CSV file",
+                    "type": "string",
+                    "example": "Deutsche Bahn"
                 },
-                "error": {
-                    "description": "The error, if any occurred",
+                "duplicateTransactionIds": {
+                    "description": "IDs of transactions that this transaction duplicates",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "matchRuleId": {
+                    "description": "ID of the match rule that was applied to this transaction preview",
                     "type": "string",
-                    "example": "the specified resource ID is not a valid UUID"
+                    "example": "042d101d-f1de-4403-9295-59dc0ea58677"
+                },
+                "sourceAccountName": {
+                    "description": "Name of the source account from the CSV file",
+                    "type": "string",
+                    "example": "Employer"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/v4.Transaction"
                 }
             }
         },
-        "v4.MonthResponse": {
+        "v4.TransactionResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "Data for the month",
+                    "description": "The Transaction data, if creation was successful",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.Month"
+                            "$ref": "#/definitions/v4.Transaction"
                         }
                     ]
                 },
                 "error": {
-                    "description": "The error, if any occurred",
-                    "type": "string"
+                    "description": "The error, if any occurred for this transaction",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
                 }
             }
         },
-        "v4.Pagination": {
+        "v4.TrashEntry": {
             "type": "object",
             "properties": {
-                "count": {
-                    "description": "The amount of records returned in this response",
-                    "type": "integer",
-                    "example": 25
+                "deletedAt": {
+                    "description": "Time the resource was deleted",
+                    "type": "string",
+                    "example": "2024-04-02T19:28:44.491514Z"
                 },
-                "limit": {
-                    "description": "The maximum amount of resources to return for this request",
-                    "type": "integer",
-                    "example": 25
+                "expiresAt": {
+                    "description": "Time after which the resource can not be restored anymore",
+                    "type": "string",
+                    "example": "2024-05-02T19:28:44.491514Z"
                 },
-                "offset": {
-                    "description": "The offset for the first record returned",
-                    "type": "integer",
-                    "example": 50
+                "id": {
+                    "description": "ID of the trash entry",
+                    "type": "string",
+                    "example": "4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a"
                 },
-                "total": {
-                    "description": "The total number of resources matching the query",
-                    "type": "integer",
-                    "example": 827
+                "links": {
+                    "description": "Links for the trash entry",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.TrashEntryLinks"
+                        }
+                    ]
+                },
+                "model": {
+                    "description": "Type of the deleted resource",
+                    "type": "string",
+                    "example": "Envelope"
+                },
+                "resourceId": {
+                    "description": "ID of the deleted resource",
+                    "type": "string",
+                    "example": "0b4ea4b5-1a8e-4c35-9fa0-1a2a1a0a4f3e"
+                },
+                "resources": {
+                    "description": "The deleted resource, followed by all resources that were deleted with it",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrashResource"
+                    }
                 }
             }
         },
-        "v4.RecentEnvelope": {
+        "v4.TrashEntryLinks": {
             "type": "object",
             "properties": {
-                "archived": {
-                    "type": "boolean"
-                },
-                "id": {
-                    "type": "string"
+                "restore": {
+                    "description": "Restores the deleted resources",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/trash/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/restore"
                 },
-                "name": {
-                    "type": "string"
+                "self": {
+                    "description": "The trash entry itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/trash/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a"
                 }
             }
         },
-        "v4.RecentEnvelopesResponse": {
+        "v4.TrashEntryListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "Data for the account",
+                    "description": "List of trash entries",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.RecentEnvelope"
+                        "$ref": "#/definitions/v4.TrashEntry"
                     }
                 },
                 "error": {
-                    "description": "The error, if any occurred for this transaction",
+                    "description": "The error, if any occurred",
                     "type": "string",
                     "example": "the specified resource ID is not a valid UUID"
+                },
+                "pagination": {
+                    "description": "Pagination information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.Pagination"
+                        }
+                    ]
                 }
             }
         },
-        "v4.Response": {
+        "v4.TrashEntryResponse": {
             "type": "object",
             "properties": {
-                "links": {
-                    "description": "Links for the v4 API",
+                "data": {
+                    "description": "Data for the trash entry",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.Links"
+                            "$ref": "#/definitions/v4.TrashEntry"
                         }
                     ]
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
                 }
             }
         },
-        "v4.Transaction": {
+        "v4.Webhook": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "description": "The maximum value is \"999999999999.99999999\", swagger unfortunately rounds this.",
-                    "type": "number",
-                    "maximum": 1000000000000,
-                    "minimum": 1e-8,
-                    "multipleOf": 1e-8,
-                    "example": 14.03
-                },
-                "availableFrom": {
-                    "description": "The date from which on the transaction amount is available for budgeting. Only used for income transactions. Defaults to the transaction date.",
-                    "type": "string",
-                    "example": "2021-11-17T00:00:00Z"
-                },
                 "createdAt": {
                     "description": "Time the resource was created",
                     "type": "string",
                     "example": "2022-04-02T19:28:44.491514Z"
                 },
-                "date": {
-                    "description": "Date of the transaction. Time is currently only used for sorting",
-                    "type": "string",
-                    "example": "1815-12-10T18:43:00.271152Z"
-                },
-                "destinationAccountId": {
-                    "description": "ID of the destination account",
-                    "type": "string",
-                    "example": "8e16b456-a719-48ce-9fec-e115cfa7cbcc"
-                },
-                "envelopeId": {
-                    "description": "ID of the envelope",
-                    "type": "string",
-                    "example": "2649c965-7999-4873-ae16-89d5d5fa972e"
+                "events": {
+                    "description": "Events the webhook is notified about",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "transaction.created",
+                        "envelope.negative"
+                    ]
                 },
                 "id": {
                     "description": "UUID for the resource",
                     "type": "string",
                     "example": "65392deb-5e92-4268-b114-297faad6cdce"
                 },
-                "importHash": {
-                    "description": "The SHA256 hash of a unique combination of values to use in duplicate detection",
-                    "type": "string",
-                    "example": "867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70"
-                },
                 "links": {
-                    "$ref": "#/definitions/v4.TransactionLinks"
-                },
-                "note": {
-                    "description": "A note",
-                    "type": "string",
-                    "example": "Lunch"
-                },
-                "reconciledDestination": {
-                    "description": "Is the transaction reconciled in the destination account?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
-                },
-                "reconciledSource": {
-                    "description": "Is the transaction reconciled in the source account?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                    "$ref": "#/definitions/v4.WebhookLinks"
                 },
-                "sourceAccountId": {
-                    "description": "ID of the source account",
+                "secret": {
+                    "description": "Secret to sign the payloads with. Generated if not set on creation",
                     "type": "string",
-                    "example": "fd81dc45-a3a2-468e-a6fa-b2618f30aa45"
+                    "example": "8a2d0f6b5c3e4a1f9e7d6c5b4a3f2e1d"
                 },
                 "updatedAt": {
                     "description": "Last time the resource was updated",
                     "type": "string",
                     "example": "2022-04-17T20:14:01.048145Z"
+                },
+                "url": {
+                    "description": "URL the payloads are sent to with a POST request",
+                    "type": "string",
+                    "example": "https://home.example.com/hooks/envelope-zero"
                 }
             }
         },
-        "v4.TransactionCreateResponse": {
+        "v4.WebhookCreateResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of created Transactions",
+                    "description": "List of the created webhooks or their respective error",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.TransactionResponse"
+                        "$ref": "#/definitions/v4.WebhookResponse"
                     }
                 },
                 "error": {
@@ -4924,84 +12402,99 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.TransactionEditable": {
+        "v4.WebhookDelivery": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "description": "The maximum value is \"999999999999.99999999\", swagger unfortunately rounds this.",
-                    "type": "number",
-                    "maximum": 1000000000000,
-                    "minimum": 1e-8,
-                    "multipleOf": 1e-8,
-                    "example": 14.03
+                "attempts": {
+                    "description": "Number of attempts to send the payload",
+                    "type": "integer",
+                    "example": 2
                 },
-                "availableFrom": {
-                    "description": "The date from which on the transaction amount is available for budgeting. Only used for income transactions. Defaults to the transaction date.",
+                "createdAt": {
+                    "description": "Time the resource was created",
                     "type": "string",
-                    "example": "2021-11-17T00:00:00Z"
+                    "example": "2022-04-02T19:28:44.491514Z"
                 },
-                "date": {
-                    "description": "Date of the transaction. Time is currently only used for sorting",
+                "error": {
+                    "description": "Why the last attempt failed",
                     "type": "string",
-                    "example": "1815-12-10T18:43:00.271152Z"
+                    "example": "the receiver responded with status 503"
                 },
-                "destinationAccountId": {
-                    "description": "ID of the destination account",
-                    "type": "string",
-                    "example": "8e16b456-a719-48ce-9fec-e115cfa7cbcc"
+                "event": {
+                    "description": "The event the webhook is notified about",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WebhookEvent"
+                        }
+                    ],
+                    "example": "transaction.created"
                 },
-                "envelopeId": {
-                    "description": "ID of the envelope",
+                "id": {
+                    "description": "UUID for the resource",
                     "type": "string",
-                    "example": "2649c965-7999-4873-ae16-89d5d5fa972e"
+                    "example": "65392deb-5e92-4268-b114-297faad6cdce"
                 },
-                "importHash": {
-                    "description": "The SHA256 hash of a unique combination of values to use in duplicate detection",
-                    "type": "string",
-                    "example": "867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70"
+                "links": {
+                    "description": "Links for the delivery",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v4.WebhookDeliveryLinks"
+                        }
+                    ]
                 },
-                "note": {
-                    "description": "A note",
+                "nextAttemptAt": {
+                    "description": "Time of the next attempt. Only set for pending deliveries",
                     "type": "string",
-                    "example": "Lunch"
+                    "example": "2024-04-02T19:32:44.491514Z"
                 },
-                "reconciledDestination": {
-                    "description": "Is the transaction reconciled in the destination account?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                "payload": {
+                    "description": "The payload that is sent",
+                    "type": "object"
                 },
-                "reconciledSource": {
-                    "description": "Is the transaction reconciled in the source account?",
-                    "type": "boolean",
-                    "default": false,
-                    "example": true
+                "responseStatus": {
+                    "description": "HTTP status of the response to the last attempt. 0 if no response was received",
+                    "type": "integer",
+                    "example": 503
                 },
-                "sourceAccountId": {
-                    "description": "ID of the source account",
+                "status": {
+                    "description": "Status of the delivery. One of PENDING, SUCCEEDED, FAILED",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WebhookDeliveryStatus"
+                        }
+                    ],
+                    "example": "PENDING"
+                },
+                "updatedAt": {
+                    "description": "Last time the resource was updated",
                     "type": "string",
-                    "example": "fd81dc45-a3a2-468e-a6fa-b2618f30aa45"
+                    "example": "2022-04-17T20:14:01.048145Z"
+                },
+                "webhookId": {
+                    "description": "ID of the webhook",
+                    "type": "string",
+                    "example": "1b6a3d36-5a4b-4f6e-9d2c-8e7f6a5b4c3d"
                 }
             }
         },
-        "v4.TransactionLinks": {
+        "v4.WebhookDeliveryLinks": {
             "type": "object",
             "properties": {
-                "self": {
-                    "description": "The transaction itself",
+                "webhook": {
+                    "description": "The webhook of the delivery",
                     "type": "string",
-                    "example": "https://example.com/api/v4/transactions/d430d7c3-d14c-4712-9336-ee56965a6673"
+                    "example": "https://example.com/api/v4/webhooks/1b6a3d36-5a4b-4f6e-9d2c-8e7f6a5b4c3d"
                 }
             }
         },
-        "v4.TransactionListResponse": {
+        "v4.WebhookDeliveryListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "List of transactions",
+                    "description": "List of deliveries",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v4.Transaction"
+                        "$ref": "#/definitions/v4.WebhookDelivery"
                     }
                 },
                 "error": {
@@ -5019,49 +12512,77 @@ const docTemplate = `{
                 }
             }
         },
-        "v4.TransactionPreview": {
+        "v4.WebhookEditable": {
             "type": "object",
             "properties": {
-                "destinationAccountName": {
-                    "description": "Name of the destination account from the CSV file",
-                    "type": "string",
-                    "example": "Deutsche Bahn"
-                },
-                "duplicateTransactionIds": {
-                    "description": "IDs of transactions that this transaction duplicates",
+                "events": {
+                    "description": "Events the webhook is notified about",
                     "type": "array",
                     "items": {
                         "type": "string"
-                    }
+                    },
+                    "example": [
+                        "transaction.created",
+                        "envelope.negative"
+                    ]
                 },
-                "matchRuleId": {
-                    "description": "ID of the match rule that was applied to this transaction preview",
+                "secret": {
+                    "description": "Secret to sign the payloads with. Generated if not set on creation",
                     "type": "string",
-                    "example": "042d101d-f1de-4403-9295-59dc0ea58677"
+                    "example": "8a2d0f6b5c3e4a1f9e7d6c5b4a3f2e1d"
                 },
-                "sourceAccountName": {
-                    "description": "Name of the source account from the CSV file",
+                "url": {
+                    "description": "URL the payloads are sent to with a POST request",
                     "type": "string",
-                    "example": "Employer"
+                    "example": "https://home.example.com/hooks/envelope-zero"
+                }
+            }
+        },
+        "v4.WebhookLinks": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "description": "Deliveries of the webhook",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/webhooks/1b6a3d36-5a4b-4f6e-9d2c-8e7f6a5b4c3d/deliveries"
                 },
-                "transaction": {
-                    "$ref": "#/definitions/v4.Transaction"
+                "self": {
+                    "description": "The webhook itself",
+                    "type": "string",
+                    "example": "https://example.com/api/v4/webhooks/1b6a3d36-5a4b-4f6e-9d2c-8e7f6a5b4c3d"
                 }
             }
         },
-        "v4.TransactionResponse": {
+        "v4.WebhookListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "description": "The Transaction data, if creation was successful",
+                    "description": "List of webhooks",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v4.Webhook"
+                    }
+                },
+                "error": {
+                    "description": "The error, if any occurred",
+                    "type": "string",
+                    "example": "the specified resource ID is not a valid UUID"
+                }
+            }
+        },
+        "v4.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data for the webhook",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/v4.Transaction"
+                            "$ref": "#/definitions/v4.Webhook"
                         }
                     ]
                 },
                 "error": {
-                    "description": "The error, if any occurred for this transaction",
+                    "description": "The error, if any occurred",
                     "type": "string",
                     "example": "the specified resource ID is not a valid UUID"
                 }
@@ -1,6 +1,7 @@
 package v4
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/envelope-zero/backend/v7/internal/httputil"
@@ -8,6 +9,7 @@ import (
 	"github.com/envelope-zero/backend/v7/internal/uuid"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
 )
 
 // RegisterAccountRoutes registers the routes for accounts with
@@ -16,7 +18,7 @@ func RegisterAccountRoutes(r *gin.RouterGroup) {
 	// Root group
 	{
 		r.OPTIONS("", OptionsAccountList)
-		r.GET("", GetAccounts)
+		r.GET("", httputil.NotModified, GetAccounts)
 		r.POST("", CreateAccounts)
 	}
 
@@ -24,10 +26,14 @@ func RegisterAccountRoutes(r *gin.RouterGroup) {
 	{
 		r.OPTIONS("/:id", OptionsAccountDetail)
 		r.GET("/:id", GetAccount)
-		r.GET("/:id/recent-envelopes", GetAccountRecentEnvelopes)
+		r.GET("/:id/recent-envelopes", httputil.NotModified, GetAccountRecentEnvelopes)
 		r.POST("/computed", GetAccountData) // This is a POST endpoints because some clients don't allow GET requests to have bodies
 		r.PATCH("/:id", UpdateAccount)
 		r.DELETE("/:id", DeleteAccount)
+		r.OPTIONS("/:id/merge", OptionsAccountMerge)
+		r.POST("/:id/merge", MergeAccounts)
+		r.OPTIONS("/:id/register", OptionsAccountRegister)
+		r.GET("/:id/register", httputil.NotModified, GetAccountRegister)
 	}
 }
 
@@ -81,7 +87,7 @@ func CreateAccounts(c *gin.Context) {
 
 	for _, editable := range editables {
 		account := editable.model()
-		err = models.DB.Create(&account).Error
+		err = models.DB.WithContext(c).Create(&account).Error
 		if err != nil {
 			status = r.appendError(err, status)
 			continue
@@ -99,8 +105,10 @@ func CreateAccounts(c *gin.Context) {
 // @Tags			Accounts
 // @Produce		json
 // @Success		200	{object}	AccountListResponse
-// @Failure		400	{object}	AccountListResponse
-// @Failure		500	{object}	AccountListResponse
+// @Success		304
+// @Failure		400				{object}	AccountListResponse
+// @Failure		500				{object}	AccountListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
 // @Router			/v4/accounts [get]
 // @Param			name		query	string	false	"Filter by name"
 // @Param			note		query	string	false	"Filter by note"
@@ -111,6 +119,8 @@ func CreateAccounts(c *gin.Context) {
 // @Param			search		query	string	false	"Search for this text in name and note"
 // @Param			offset		query	uint	false	"The offset of the first Account returned. Defaults to 0."
 // @Param			limit		query	int		false	"Maximum number of Accounts to return. Defaults to 50."
+// @Param			sort		query	string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name"
+// @Param			cursor		query	string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
 func GetAccounts(c *gin.Context) {
 	var filter AccountQueryFilter
 	if err := c.Bind(&filter); err != nil {
@@ -135,20 +145,25 @@ func GetAccounts(c *gin.Context) {
 	}
 
 	q := models.DB.
-		Order("name ASC").
 		Where(&model, queryFields...)
 
 	q = stringFilters(models.DB, q, setFields, filter.Name, filter.Note, filter.Search)
 
-	// Set the offset. Does not need checking since the default is 0
-	q = q.Offset(int(filter.Offset))
-
 	// Default to 50 Accounts and set the limit
 	limit := 50
 	if slices.Contains(setFields, "Limit") {
 		limit = filter.Limit
 	}
-	q = q.Limit(limit)
+
+	page, err := newListPage(accountSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, AccountListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
 
 	var accounts []models.Account
 	err = q.Find(&accounts).Error
@@ -160,8 +175,7 @@ func GetAccounts(c *gin.Context) {
 		return
 	}
 
-	var count int64
-	err = q.Limit(-1).Offset(-1).Count(&count).Error
+	accounts, pagination, err := paginate(c, q, page, accounts)
 	if err != nil {
 		e := err.Error()
 		c.JSON(status(err), AccountListResponse{
@@ -179,13 +193,8 @@ func GetAccounts(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, AccountListResponse{
-		Data: data,
-		Pagination: &Pagination{
-			Count:  len(data),
-			Total:  count,
-			Offset: filter.Offset,
-			Limit:  limit,
-		},
+		Data:       data,
+		Pagination: &pagination,
 	})
 }
 
@@ -194,6 +203,7 @@ func GetAccounts(c *gin.Context) {
 // @Tags			Accounts
 // @Produce		json
 // @Success		200	{object}	AccountResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
 // @Failure		400	{object}	AccountResponse
 // @Failure		404	{object}	AccountResponse
 // @Failure		500	{object}	AccountResponse
@@ -221,6 +231,7 @@ func GetAccount(c *gin.Context) {
 	}
 
 	data := newAccount(c, account)
+	c.Header("ETag", httputil.ETag(account.UpdatedAt))
 	c.JSON(http.StatusOK, AccountResponse{Data: &data})
 }
 
@@ -229,10 +240,12 @@ func GetAccount(c *gin.Context) {
 //	@Tags			Accounts
 //	@Produce		json
 //	@Success		200	{object}	RecentEnvelopesResponse
-//	@Failure		400	{object}	RecentEnvelopesResponse
-//	@Failure		404	{object}	RecentEnvelopesResponse
-//	@Failure		500	{object}	RecentEnvelopesResponse
-//	@Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+//	@Success		304
+//	@Failure		400				{object}	RecentEnvelopesResponse
+//	@Failure		404				{object}	RecentEnvelopesResponse
+//	@Failure		500				{object}	RecentEnvelopesResponse
+//	@Param			id				path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+//	@Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
 //	@Router			/v4/accounts/{id}/recent-envelopes [get]
 //
 // GetAccountRecentEnvelopes returns recent envelopes for an account.
@@ -360,10 +373,52 @@ func GetAccountData(c *gin.Context) {
 			return
 		}
 
+		// Cleared Balance
+		clearedBalance, err := account.ClearedBalance(models.DB, request.Time)
+		if err != nil {
+			s := err.Error()
+			c.JSON(status(err), AccountComputedDataResponse{
+				Error: &s,
+			})
+			return
+		}
+
+		// Uncleared Balance
+		unclearedBalance, err := account.UnclearedBalance(models.DB, request.Time)
+		if err != nil {
+			s := err.Error()
+			c.JSON(status(err), AccountComputedDataResponse{
+				Error: &s,
+			})
+			return
+		}
+
+		// Balance in the currency of the budget
+		rates, err := models.LoadExchangeRates(models.DB, account.BudgetID)
+		if err != nil {
+			s := err.Error()
+			c.JSON(status(err), AccountComputedDataResponse{
+				Error: &s,
+			})
+			return
+		}
+
+		budgetBalance, err := rates.Convert(balance, account.Currency, request.Time)
+		if err != nil {
+			s := err.Error()
+			c.JSON(status(err), AccountComputedDataResponse{
+				Error: &s,
+			})
+			return
+		}
+
 		data = append(data, AccountComputedData{
 			ID:                id,
 			Balance:           balance,
+			ClearedBalance:    clearedBalance,
+			UnclearedBalance:  unclearedBalance,
 			ReconciledBalance: reconciledBalance,
+			BudgetBalance:     budgetBalance,
 		})
 	}
 
@@ -374,12 +429,14 @@ func GetAccountData(c *gin.Context) {
 // @Description	Updates an account. Only values to be updated need to be specified.
 // @Tags			Accounts
 // @Produce		json
-// @Success		200		{object}	AccountResponse
-// @Failure		400		{object}	AccountResponse
-// @Failure		404		{object}	AccountResponse
-// @Failure		500		{object}	AccountResponse
-// @Param			id		path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
-// @Param			account	body		AccountEditable	true	"Account"
+// @Success		200			{object}	AccountResponse
+// @Failure		400			{object}	AccountResponse
+// @Failure		404			{object}	AccountResponse
+// @Failure		412			{object}	AccountResponse
+// @Failure		500			{object}	AccountResponse
+// @Param			id			path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			account		body		AccountEditable	true	"Account"
+// @Param			If-Match	header		string			false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/accounts/{id} [patch]
 func UpdateAccount(c *gin.Context) {
 	var uri URIID
@@ -421,7 +478,14 @@ func UpdateAccount(c *gin.Context) {
 		return
 	}
 
-	err = models.DB.Model(&account).Select("", updateFields...).Updates(data.model()).Error
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &account)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&account).Select("", updateFields...).Updates(data.model()).Error
+	})
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), AccountResponse{
@@ -431,19 +495,208 @@ func UpdateAccount(c *gin.Context) {
 	}
 
 	apiResource := newAccount(c, account)
+	c.Header("ETag", httputil.ETag(account.UpdatedAt))
 	c.JSON(http.StatusOK, AccountResponse{Data: &apiResource})
 }
 
 // @Summary		Delete account
-// @Description	Deletes an account
+// @Description	Deletes an account and moves it to the trash together with its transactions and match rules
 // @Tags			Accounts
 // @Produce		json
 // @Success		204
-// @Failure		400	{object}	httpError
-// @Failure		404	{object}	httpError
-// @Failure		500	{object}	httpError
-// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/accounts/{id} [delete]
 func DeleteAccount(c *gin.Context) {
 	deleteResource[models.Account](c)
 }
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Accounts
+// @Success		204
+// @Param			id	path	URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/accounts/{id}/merge [options]
+func OptionsAccountMerge(c *gin.Context) {
+	httputil.OptionsPost(c)
+}
+
+// @Summary		Merge accounts
+// @Description	Merges other accounts into this one. Their transactions, match rules and reconciliations are moved to this account and their initial balances are added to it, then they are deleted.
+// @Tags			Accounts
+// @Accept			json
+// @Produce		json
+// @Success		200		{object}	AccountResponse
+// @Failure		400		{object}	AccountResponse
+// @Failure		404		{object}	AccountResponse
+// @Failure		500		{object}	AccountResponse
+// @Param			id		path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			merge	body		AccountMerge	true	"Accounts to merge"
+// @Router			/v4/accounts/{id}/merge [post]
+func MergeAccounts(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AccountResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var data AccountMerge
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AccountResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var account models.Account
+	err = models.DB.First(&account, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AccountResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err = account.Merge(models.DB.WithContext(c), data.AccountIDs)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AccountResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	apiResource := newAccount(c, account)
+	c.JSON(http.StatusOK, AccountResponse{Data: &apiResource})
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Accounts
+// @Success		204
+// @Param			id	path	URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/accounts/{id}/register [options]
+func OptionsAccountRegister(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Get account register
+// @Description	Returns the transactions of the account in date order. Each entry contains the balance and the reconciled balance of the account after the transaction, starting with the initial balance at its date. Use the next link of the pagination to get the next page.
+// @Tags			Accounts
+// @Produce		json
+// @Success		200	{object}	AccountRegisterResponse
+// @Success		304
+// @Failure		400				{object}	AccountRegisterResponse
+// @Failure		404				{object}	AccountRegisterResponse
+// @Failure		500				{object}	AccountRegisterResponse
+// @Param			id				path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			cursor			query		string	false	"Cursor of the page to return"
+// @Param			limit			query		int		false	"Maximum number of transactions to return. Defaults to 50"
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/accounts/{id}/register [get]
+func GetAccountRegister(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AccountRegisterResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var query AccountRegisterQuery
+	if err := c.BindQuery(&query); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, AccountRegisterResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	limit := 50
+	if query.Limit < 0 {
+		s := errRegisterLimitInvalid.Error()
+		c.JSON(http.StatusBadRequest, AccountRegisterResponse{
+			Error: &s,
+		})
+		return
+	} else if query.Limit > 0 {
+		limit = query.Limit
+	}
+
+	var after *models.RegisterPosition
+	if query.Cursor != "" {
+		position, err := decodeRegisterCursor(query.Cursor)
+		if err != nil {
+			s := err.Error()
+			c.JSON(http.StatusBadRequest, AccountRegisterResponse{
+				Error: &s,
+			})
+			return
+		}
+		after = &position
+	}
+
+	var account models.Account
+	err = models.DB.First(&account, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AccountRegisterResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// Get one more entry than requested to know if there is a next page
+	entries, err := account.Register(models.DB, after, limit+1)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AccountRegisterResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var next *string
+	if len(entries) > limit {
+		entries = entries[:limit]
+
+		url := c.GetString(string(models.DBContextURL))
+		link := fmt.Sprintf("%s/v4/accounts/%s/register?cursor=%s&limit=%d", url, account.ID, encodeRegisterCursor(entries[limit-1].Position()), limit)
+		next = &link
+	}
+
+	data := make([]AccountRegisterEntry, 0, len(entries))
+	for _, entry := range entries {
+		apiResource, err := newAccountRegisterEntry(c, entry)
+		if err != nil {
+			s := err.Error()
+			c.JSON(status(err), AccountRegisterResponse{
+				Error: &s,
+			})
+			return
+		}
+		data = append(data, apiResource)
+	}
+
+	c.JSON(http.StatusOK, AccountRegisterResponse{
+		Data: data,
+		Pagination: &AccountRegisterPagination{
+			Count: len(data),
+			Limit: limit,
+			Next:  next,
+		},
+	})
+}
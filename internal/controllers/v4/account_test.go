@@ -585,6 +585,31 @@ func (suite *TestSuiteStandard) TestAccountsPagination() {
 	}
 }
 
+// TestAccountsPaginationNext verifies that the link to the next page returns the next page.
+func (suite *TestSuiteStandard) TestAccountsPaginationNext() {
+	for i := 0; i < 5; i++ {
+		createTestAccount(suite.T(), v4.AccountEditable{Name: fmt.Sprint(i)})
+	}
+
+	r := test.Request(suite.T(), http.MethodGet, "http://example.com/v4/accounts?limit=3", "")
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+
+	var first v4.AccountListResponse
+	test.DecodeResponse(suite.T(), &r, &first)
+	require.NotNil(suite.T(), first.Pagination.Next)
+	assert.True(suite.T(), strings.HasPrefix(*first.Pagination.Next, "http://example.com/v4/accounts?"), *first.Pagination.Next)
+
+	r = test.Request(suite.T(), http.MethodGet, *first.Pagination.Next, "")
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+
+	var second v4.AccountListResponse
+	test.DecodeResponse(suite.T(), &r, &second)
+	require.Len(suite.T(), second.Data, 2)
+	assert.Equal(suite.T(), "3", second.Data[0].Name)
+	assert.Equal(suite.T(), "4", second.Data[1].Name)
+	assert.Nil(suite.T(), second.Pagination.Next)
+}
+
 func (suite *TestSuiteStandard) TestAccountRecentEnvelopes() {
 	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
 
@@ -685,3 +710,188 @@ func (suite *TestSuiteStandard) TestAccountRecentEnvelopes() {
 	// Only one of the two is archived, but since the order is undefined we XOR them
 	suite.Assert().Equal(true, data[2].Archived != data[3].Archived)
 }
+
+// TestAccountsComputedCleared verifies the balances for the different transaction states.
+func (suite *TestSuiteStandard) TestAccountsComputedCleared() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	initialBalanceDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bank := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true, InitialBalance: decimal.NewFromFloat(100), InitialBalanceDate: &initialBalanceDate})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+
+	for _, editable := range []v4.TransactionEditable{
+		{Amount: decimal.NewFromFloat(10)},
+		{Amount: decimal.NewFromFloat(20), ClearedSource: true},
+		{Amount: decimal.NewFromFloat(30), ReconciledSource: true},
+	} {
+		editable.SourceAccountID = bank.Data.ID
+		editable.DestinationAccountID = shop.Data.ID
+		editable.Date = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		_ = createTestTransaction(suite.T(), editable)
+	}
+
+	recorder := test.Request(suite.T(), http.MethodPost, "/v4/accounts/computed", map[string]any{
+		"time": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339),
+		"ids":  []string{bank.Data.ID.String()},
+	})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.AccountComputedDataResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+
+	data := response.Data[0]
+	assert.True(suite.T(), data.Balance.Equal(decimal.NewFromFloat(40)), "Balance is %s", data.Balance)
+	assert.True(suite.T(), data.ClearedBalance.Equal(decimal.NewFromFloat(50)), "Cleared balance is %s", data.ClearedBalance)
+	assert.True(suite.T(), data.UnclearedBalance.Equal(decimal.NewFromFloat(-10)), "Uncleared balance is %s", data.UnclearedBalance)
+	assert.True(suite.T(), data.ReconciledBalance.Equal(decimal.NewFromFloat(70)), "Reconciled balance is %s", data.ReconciledBalance)
+}
+
+// TestAccountsMerge verifies that merging accounts moves their transactions and adds up the initial balances.
+func (suite *TestSuiteStandard) TestAccountsMerge() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Checking", OnBudget: true, InitialBalance: decimal.NewFromFloat(100)})
+	duplicate := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Checking (Import)", OnBudget: true, InitialBalance: decimal.NewFromFloat(20)})
+	savings := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Savings", OnBudget: true})
+	payee := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+	dollar := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Dollar", Currency: "USD"})
+
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: duplicate.Data.ID, DestinationAccountID: payee.Data.ID, Amount: decimal.NewFromFloat(7)})
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: savings.Data.ID, DestinationAccountID: account.Data.ID, Amount: decimal.NewFromFloat(3)})
+
+	tests := []struct {
+		name   string
+		ids    []uuid.UUID
+		status int
+	}{
+		{"Itself", []uuid.UUID{account.Data.ID}, http.StatusBadRequest},
+		{"Other currency", []uuid.UUID{dollar.Data.ID}, http.StatusBadRequest},
+		{"Transfer between the accounts", []uuid.UUID{savings.Data.ID}, http.StatusBadRequest},
+		{"Not found", []uuid.UUID{uuid.New()}, http.StatusNotFound},
+		{"Duplicate", []uuid.UUID{duplicate.Data.ID}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, account.Data.Links.Merge, v4.AccountMerge{AccountIDs: tt.ids})
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var merged v4.AccountResponse
+			test.DecodeResponse(t, &recorder, &merged)
+			assert.True(t, decimal.NewFromFloat(120).Equal(merged.Data.InitialBalance), "Initial balance is %s", merged.Data.InitialBalance)
+		})
+	}
+
+	recorder := test.Request(suite.T(), http.MethodOptions, account.Data.Links.Merge, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, POST", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodGet, transaction.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var mergedTransaction v4.TransactionResponse
+	test.DecodeResponse(suite.T(), &recorder, &mergedTransaction)
+	assert.Equal(suite.T(), account.Data.ID, mergedTransaction.Data.SourceAccountID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, duplicate.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+}
+
+// TestAccountsMergeFails verifies that merging into an account fails for invalid requests.
+func (suite *TestSuiteStandard) TestAccountsMergeFails() {
+	account := createTestAccount(suite.T(), v4.AccountEditable{})
+
+	tests := []struct {
+		name   string
+		path   string
+		body   any
+		status int
+	}{
+		{"Not a UUID", "http://example.com/v4/accounts/notauuid/merge", v4.AccountMerge{}, http.StatusBadRequest},
+		{"Not found", "http://example.com/v4/accounts/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/merge", v4.AccountMerge{}, http.StatusNotFound},
+		{"Broken body", account.Data.Links.Merge, `{ "accountIds": 2 }`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, tt.path, tt.body)
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
+
+func (suite *TestSuiteStandard) TestAccountsRegister() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	initialDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true, InitialBalance: decimal.NewFromFloat(100), InitialBalanceDate: &initialDate})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+
+	for i := 1; i <= 3; i++ {
+		_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+			Date:                 time.Date(2024, 1, 10*i, 0, 0, 0, 0, time.UTC),
+			SourceAccountID:      account.Data.ID,
+			DestinationAccountID: shop.Data.ID,
+			Amount:               decimal.NewFromFloat(10),
+			ReconciledSource:     i == 1,
+		})
+	}
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("%s?limit=2", account.Data.Links.Register), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.AccountRegisterResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	suite.Require().Len(response.Data, 2)
+	suite.Require().NotNil(response.Pagination.Next)
+	assert.Equal(suite.T(), 2, response.Pagination.Count)
+	assert.Equal(suite.T(), 2, response.Pagination.Limit)
+
+	assert.True(suite.T(), decimal.NewFromFloat(-10).Equal(response.Data[0].Amount), "Amount is %s", response.Data[0].Amount)
+	assert.True(suite.T(), decimal.NewFromFloat(10).Equal(response.Data[0].Transaction.Amount), "Transaction amount is %s", response.Data[0].Transaction.Amount)
+	assert.True(suite.T(), decimal.NewFromFloat(90).Equal(response.Data[0].Balance), "Balance is %s", response.Data[0].Balance)
+	assert.True(suite.T(), decimal.NewFromFloat(80).Equal(response.Data[1].Balance), "Balance is %s", response.Data[1].Balance)
+	assert.True(suite.T(), decimal.NewFromFloat(90).Equal(response.Data[1].ReconciledBalance), "Reconciled balance is %s", response.Data[1].ReconciledBalance)
+
+	// The next page continues with the running balances
+	recorder = test.Request(suite.T(), http.MethodGet, *response.Pagination.Next, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	suite.Require().Len(response.Data, 1)
+	assert.Nil(suite.T(), response.Pagination.Next)
+	assert.True(suite.T(), decimal.NewFromFloat(70).Equal(response.Data[0].Balance), "Balance is %s", response.Data[0].Balance)
+	assert.True(suite.T(), decimal.NewFromFloat(90).Equal(response.Data[0].ReconciledBalance), "Reconciled balance is %s", response.Data[0].ReconciledBalance)
+
+	recorder = test.Request(suite.T(), http.MethodOptions, account.Data.Links.Register, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET", recorder.Header().Get("allow"))
+}
+
+func (suite *TestSuiteStandard) TestAccountsRegisterFails() {
+	account := createTestAccount(suite.T(), v4.AccountEditable{})
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"Not a UUID", "http://example.com/v4/accounts/notauuid/register", http.StatusBadRequest},
+		{"Not found", "http://example.com/v4/accounts/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/register", http.StatusNotFound},
+		{"Invalid cursor", fmt.Sprintf("%s?cursor=notacursor", account.Data.Links.Register), http.StatusBadRequest},
+		{"Negative limit", fmt.Sprintf("%s?limit=-1", account.Data.Links.Register), http.StatusBadRequest},
+		{"Limit not a number", fmt.Sprintf("%s?limit=many", account.Data.Links.Register), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, tt.path, "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.AccountRegisterResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package v4
 
 import (
+	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/envelope-zero/backend/v7/internal/models"
@@ -21,6 +23,8 @@ type AccountEditable struct {
 	InitialBalance     decimal.Decimal `json:"initialBalance" example:"173.12" default:"0" minimum:"0.00000001" maximum:"999999999999.99999999" multipleOf:"0.00000001"` // Balance of the account before any transactions were recorded
 	InitialBalanceDate *time.Time      `json:"initialBalanceDate" example:"2017-05-12T00:00:00Z"`                                                                        // Date of the initial balance
 	Archived           bool            `json:"archived" example:"true" default:"false"`                                                                                  // Is the account archived?
+	Currency           string          `json:"currency" example:"USD" default:""`                                                                                        // ISO 4217 code of the currency of the account. If empty, the currency of the budget is used
+	DefaultEnvelopeID  *uuid.UUID      `json:"defaultEnvelopeId" example:"c9e4ebe8-4b9b-4b4d-8b4a-6a1c0b2f0e51"`                                                         // Envelope for outgoing transactions to the account that have none set. Only external accounts can have one
 	ImportHash         string          `json:"importHash" example:"867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70" default:""`                         // The SHA256 hash of a unique combination of values to use in duplicate detection for imports
 }
 
@@ -35,6 +39,8 @@ func (editable AccountEditable) model() models.Account {
 		InitialBalance:     editable.InitialBalance,
 		InitialBalanceDate: editable.InitialBalanceDate,
 		Archived:           editable.Archived,
+		Currency:           strings.ToUpper(strings.TrimSpace(editable.Currency)),
+		DefaultEnvelopeID:  editable.DefaultEnvelopeID,
 		ImportHash:         editable.ImportHash,
 	}
 }
@@ -44,6 +50,8 @@ type AccountLinks struct {
 	RecentEnvelopes string `json:"recentEnvelopes" example:"https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/recent-envelopes"` // Envelopes in recent transactions where this account was the target
 	ComputedData    string `json:"computedData" example:"https://example.com/api/v4/accounts/computed"`                                                 // Computed data endpoint for accounts
 	Transactions    string `json:"transactions" example:"https://example.com/api/v4/transactions?account=af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"`         // Transactions referencing the account
+	Merge           string `json:"merge" example:"https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/merge"`                      // Endpoint to merge other accounts into this one
+	Register        string `json:"register" example:"https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/register"`                // Transactions of the account with running balances
 }
 
 // Account is the API v4 representation of an Account in EZ.
@@ -67,6 +75,8 @@ func newAccount(c *gin.Context, model models.Account) Account {
 			InitialBalance:     model.InitialBalance,
 			InitialBalanceDate: model.InitialBalanceDate,
 			Archived:           model.Archived,
+			Currency:           model.Currency,
+			DefaultEnvelopeID:  model.DefaultEnvelopeID,
 			ImportHash:         model.ImportHash,
 		},
 		Links: AccountLinks{
@@ -74,6 +84,8 @@ func newAccount(c *gin.Context, model models.Account) Account {
 			RecentEnvelopes: fmt.Sprintf("%s/v4/accounts/%s/recent-envelopes", url, model.ID),
 			ComputedData:    fmt.Sprintf("%s/v4/accounts/computed", url),
 			Transactions:    fmt.Sprintf("%s/v4/transactions?account=%s", url, model.ID),
+			Merge:           fmt.Sprintf("%s/v4/accounts/%s/merge", url, model.ID),
+			Register:        fmt.Sprintf("%s/v4/accounts/%s/register", url, model.ID),
 		},
 	}
 }
@@ -117,6 +129,18 @@ type AccountQueryFilter struct {
 	Search   string       `form:"search" filterField:"false"` // By string in name or note
 	Offset   uint         `form:"offset" filterField:"false"` // The offset of the first Account returned. Defaults to 0.
 	Limit    int          `form:"limit" filterField:"false"`  // Maximum number of Accounts to return. Defaults to 50.
+	Sort     string       `form:"sort" filterField:"false"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor   string       `form:"cursor" filterField:"false"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// accountSorting defines how lists of accounts can be sorted
+var accountSorting = listSorting{
+	table: "accounts",
+	fields: map[string]string{
+		"name":      "accounts.name",
+		"createdAt": "accounts.created_at",
+	},
+	defaults: "name",
 }
 
 func (f AccountQueryFilter) model() (models.Account, error) {
@@ -147,10 +171,87 @@ type AccountComputedRequest struct {
 type AccountComputedData struct {
 	ID                ez_uuid.UUID    `json:"id" example:"95018a69-758b-46c6-8bab-db70d9614f9d"` // ID of the account
 	Balance           decimal.Decimal `json:"balance" example:"2735.17"`                         // Balance of the account, including all transactions referencing it
+	ClearedBalance    decimal.Decimal `json:"clearedBalance" example:"2672.41"`                  // Balance of the account, including all cleared and reconciled transactions referencing it
+	UnclearedBalance  decimal.Decimal `json:"unclearedBalance" example:"62.76"`                  // Sum of all transactions referencing the account that are neither cleared nor reconciled
 	ReconciledBalance decimal.Decimal `json:"reconciledBalance" example:"2539.57"`               // Balance of the account, including all reconciled transactions referencing it
+	BudgetBalance     decimal.Decimal `json:"budgetBalance" example:"2516.36"`                   // Balance of the account in the currency of the budget
 }
 
 type AccountComputedDataResponse struct {
 	Data  []AccountComputedData `json:"data"`
 	Error *string               `json:"error"`
 }
+
+type AccountMerge struct {
+	AccountIDs []uuid.UUID `json:"accountIds" example:"4c7a0d3e-5d0c-4d8e-9b6c-2f3a1e0d9c8b"` // IDs of the accounts to merge into this account. They are deleted after their transactions and match rules have been moved
+}
+
+type AccountRegisterQuery struct {
+	Cursor string `form:"cursor"` // Cursor of the page to return. Taken from the next link of the previous page
+	Limit  int    `form:"limit"`  // Maximum number of transactions to return. Defaults to 50
+}
+
+// AccountRegisterEntry is a transaction in the register of an account.
+type AccountRegisterEntry struct {
+	Transaction       Transaction     `json:"transaction"`                         // The transaction
+	Amount            decimal.Decimal `json:"amount" example:"-32.17"`             // Change of the account balance. Positive for incoming, negative for outgoing transactions
+	Balance           decimal.Decimal `json:"balance" example:"2735.17"`           // Balance of the account after the transaction
+	ReconciledBalance decimal.Decimal `json:"reconciledBalance" example:"2539.57"` // Reconciled balance of the account after the transaction
+}
+
+func newAccountRegisterEntry(c *gin.Context, entry models.RegisterEntry) (AccountRegisterEntry, error) {
+	transaction, err := newTransaction(c, models.DB, entry.Transaction)
+	if err != nil {
+		return AccountRegisterEntry{}, err
+	}
+
+	return AccountRegisterEntry{
+		Transaction:       transaction,
+		Amount:            entry.Amount,
+		Balance:           entry.Balance,
+		ReconciledBalance: entry.ReconciledBalance,
+	}, nil
+}
+
+type AccountRegisterPagination struct {
+	Count int     `json:"count" example:"25"`                                                                                           // The amount of transactions returned in this response
+	Limit int     `json:"limit" example:"50"`                                                                                           // The maximum amount of transactions to return for this request
+	Next  *string `json:"next" example:"https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/register?cursor=MjAy"` // The next page. Null if this is the last page
+}
+
+type AccountRegisterResponse struct {
+	Data       []AccountRegisterEntry     `json:"data"`                                                          // Transactions of the account, earliest first
+	Error      *string                    `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Pagination *AccountRegisterPagination `json:"pagination"`                                                    // Pagination information
+}
+
+// encodeRegisterCursor returns the opaque cursor for the position in the register.
+func encodeRegisterCursor(position models.RegisterPosition) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%s|%s", position.Date.Format(time.RFC3339Nano), position.ID))
+}
+
+// decodeRegisterCursor returns the position in the register for an opaque cursor.
+func decodeRegisterCursor(cursor string) (models.RegisterPosition, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return models.RegisterPosition{}, errRegisterCursorInvalid
+	}
+
+	date, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return models.RegisterPosition{}, errRegisterCursorInvalid
+	}
+
+	position := models.RegisterPosition{}
+	position.Date, err = time.Parse(time.RFC3339Nano, date)
+	if err != nil {
+		return models.RegisterPosition{}, errRegisterCursorInvalid
+	}
+
+	position.ID, err = uuid.Parse(id)
+	if err != nil {
+		return models.RegisterPosition{}, errRegisterCursorInvalid
+	}
+
+	return position, nil
+}
@@ -0,0 +1,309 @@
+package v4
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// RegisterAttachmentRoutes registers the routes for attachments with
+// the RouterGroup that is passed.
+//
+// Attachments are created and listed for their transaction, see RegisterTransactionRoutes.
+func RegisterAttachmentRoutes(r *gin.RouterGroup) {
+	// Attachment with ID
+	{
+		r.OPTIONS("/:id", OptionsAttachmentDetail)
+		r.GET("/:id", GetAttachment)
+		r.DELETE("/:id", DeleteAttachment)
+	}
+
+	// File of the attachment
+	{
+		r.OPTIONS("/:id/file", OptionsAttachmentFile)
+		r.GET("/:id/file", GetAttachmentFile)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Attachments
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/transactions/{id}/attachments [options]
+func OptionsTransactionAttachments(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	err = models.DB.First(&models.Transaction{}, uri.ID).Error
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	httputil.OptionsGetPost(c)
+}
+
+// @Summary		Get attachments of a transaction
+// @Description	Returns all attachments of a transaction, sorted by file name
+// @Tags			Attachments
+// @Produce		json
+// @Success		200	{object}	AttachmentListResponse
+// @Success		304
+// @Failure		400				{object}	AttachmentListResponse
+// @Failure		404				{object}	AttachmentListResponse
+// @Failure		500				{object}	AttachmentListResponse
+// @Param			id				path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/transactions/{id}/attachments [get]
+func GetTransactionAttachments(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AttachmentListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err = models.DB.First(&models.Transaction{}, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AttachmentListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var attachments []models.Attachment
+	err = models.DB.Where(&models.Attachment{TransactionID: uri.ID.UUID}).Order("file_name ASC, created_at ASC").Find(&attachments).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AttachmentListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// When there are no resources, we want an empty list, not null
+	data := make([]Attachment, 0)
+	for _, attachment := range attachments {
+		data = append(data, newAttachment(c, attachment))
+	}
+
+	c.JSON(http.StatusOK, AttachmentListResponse{Data: data})
+}
+
+// @Summary		Upload attachment
+// @Description	Attaches a file to a transaction. Files can be up to 10 MiB in size and must be PDF documents, GIF, JPEG, PNG or WebP images or plain text.
+// @Tags			Attachments
+// @Accept			multipart/form-data
+// @Produce		json
+// @Success		201		{object}	AttachmentResponse
+// @Failure		400		{object}	AttachmentResponse
+// @Failure		404		{object}	AttachmentResponse
+// @Failure		500		{object}	AttachmentResponse
+// @Param			id		path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			file	formData	file	true	"File to attach"
+// @Router			/v4/transactions/{id}/attachments [post]
+func CreateTransactionAttachment(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AttachmentResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err = models.DB.First(&models.Transaction{}, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AttachmentResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	f, err := getUploadedFile(c, attachmentLimits)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AttachmentResponse{
+			Error: &s,
+		})
+		return
+	}
+	defer f.Close()
+
+	attachment := models.Attachment{
+		TransactionID: uri.ID.UUID,
+		FileName:      f.name,
+		ContentType:   f.contentType,
+		Size:          f.size,
+	}
+
+	err = models.CreateAttachment(models.DB.WithContext(c), &attachment, f)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AttachmentResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := newAttachment(c, attachment)
+	c.JSON(http.StatusCreated, AttachmentResponse{Data: &data})
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Attachments
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/attachments/{id} [options]
+func OptionsAttachmentDetail(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	err = models.DB.First(&models.Attachment{}, uri.ID).Error
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	httputil.OptionsGetDelete(c)
+}
+
+// @Summary		Get attachment
+// @Description	Returns the metadata of a specific attachment. Use the file link to download the file.
+// @Tags			Attachments
+// @Produce		json
+// @Success		200	{object}	AttachmentResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
+// @Failure		400	{object}	AttachmentResponse
+// @Failure		404	{object}	AttachmentResponse
+// @Failure		500	{object}	AttachmentResponse
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/attachments/{id} [get]
+func GetAttachment(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AttachmentResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var attachment models.Attachment
+	err = models.DB.First(&attachment, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AttachmentResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := newAttachment(c, attachment)
+	c.Header("ETag", httputil.ETag(attachment.UpdatedAt))
+	c.JSON(http.StatusOK, AttachmentResponse{Data: &data})
+}
+
+// @Summary		Delete attachment
+// @Description	Deletes an attachment and moves it to the trash. The file is deleted when the trash entry expires.
+// @Tags			Attachments
+// @Success		204
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/attachments/{id} [delete]
+func DeleteAttachment(c *gin.Context) {
+	deleteResource[models.Attachment](c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Attachments
+// @Success		204
+// @Param			id	path	URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/attachments/{id}/file [options]
+func OptionsAttachmentFile(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Download attachment
+// @Description	Returns the file of an attachment
+// @Tags			Attachments
+// @Produce		application/octet-stream
+// @Success		200	{file}		file
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/attachments/{id}/file [get]
+func GetAttachmentFile(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	var attachment models.Attachment
+	err = models.DB.First(&attachment, uri.ID).Error
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	f, err := models.Storage.Open(attachment.StorageName())
+	if err != nil {
+		log.Error().Str("attachment", attachment.ID.String()).Err(err).Msg("Opening the file failed")
+		c.JSON(http.StatusInternalServerError, httpError{
+			Error: models.ErrGeneral.Error(),
+		})
+		return
+	}
+	defer f.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, f, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+	})
+}
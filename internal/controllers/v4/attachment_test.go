@@ -0,0 +1,238 @@
+package v4_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPDF = "%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n"
+
+func createTestAttachment(t *testing.T, transaction v4.TransactionResponse, name, content string, expectedStatus ...int) v4.AttachmentResponse {
+	// Default to 201 Created as expected status
+	if len(expectedStatus) == 0 {
+		expectedStatus = append(expectedStatus, http.StatusCreated)
+	}
+
+	body, headers := test.MultipartFile(t, name, strings.NewReader(content))
+	r := test.Request(t, http.MethodPost, transaction.Data.Links.Attachments, body, headers)
+	test.AssertHTTPStatus(t, &r, expectedStatus...)
+
+	var attachment v4.AttachmentResponse
+	test.DecodeResponse(t, &r, &attachment)
+
+	return attachment
+}
+
+// TestAttachments verifies that files can be attached to transactions, listed, downloaded and deleted.
+func (suite *TestSuiteStandard) TestAttachments() {
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{Amount: decimal.NewFromFloat(17.32)})
+
+	receipt := createTestAttachment(suite.T(), transaction, "receipt.pdf", testPDF)
+	assert.Equal(suite.T(), transaction.Data.ID, receipt.Data.TransactionID)
+	assert.Equal(suite.T(), "receipt.pdf", receipt.Data.FileName)
+	assert.Equal(suite.T(), "application/pdf", receipt.Data.ContentType)
+	assert.Equal(suite.T(), int64(len(testPDF)), receipt.Data.Size)
+	assert.Equal(suite.T(), transaction.Data.Links.Self, receipt.Data.Links.Transaction)
+
+	_ = createTestAttachment(suite.T(), transaction, "Notes.txt", "Paid in cash")
+
+	recorder := test.Request(suite.T(), http.MethodGet, transaction.Data.Links.Attachments, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var list v4.AttachmentListResponse
+	test.DecodeResponse(suite.T(), &recorder, &list)
+	require.Len(suite.T(), list.Data, 2)
+	assert.Equal(suite.T(), "Notes.txt", list.Data[0].FileName)
+	assert.Equal(suite.T(), "text/plain", list.Data[0].ContentType)
+	assert.Equal(suite.T(), receipt.Data.ID, list.Data[1].ID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, receipt.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var get v4.AttachmentResponse
+	test.DecodeResponse(suite.T(), &recorder, &get)
+	assert.Equal(suite.T(), receipt.Data.Links, get.Data.Links)
+
+	recorder = test.Request(suite.T(), http.MethodGet, receipt.Data.Links.File, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	assert.Equal(suite.T(), testPDF, recorder.Body.String())
+	assert.Equal(suite.T(), "application/pdf", recorder.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), `attachment; filename=receipt.pdf`, recorder.Header().Get("Content-Disposition"))
+
+	recorder = test.Request(suite.T(), http.MethodOptions, transaction.Data.Links.Attachments, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET, POST", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodOptions, receipt.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET, DELETE", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodOptions, receipt.Data.Links.File, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodDelete, receipt.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, receipt.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+
+	// The file is kept while the attachment is in the trash
+	f, err := models.Storage.Open(receipt.Data.ID.String())
+	require.Nil(suite.T(), err)
+	f.Close()
+}
+
+// TestAttachmentsFails verifies that invalid uploads and requests for attachments fail.
+func (suite *TestSuiteStandard) TestAttachmentsFails() {
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{Amount: decimal.NewFromFloat(17.32)})
+
+	r := createTestAttachment(suite.T(), transaction, "archive.zip", "PK\x03\x04\x14\x00\x00\x00", http.StatusBadRequest)
+	assert.Contains(suite.T(), *r.Error, "the file type is not supported")
+
+	r = createTestAttachment(suite.T(), transaction, "huge.txt", strings.Repeat("a", 10<<20+1), http.StatusBadRequest)
+	assert.Contains(suite.T(), *r.Error, "the file is larger than the maximum size")
+
+	recorder := test.Request(suite.T(), http.MethodPost, transaction.Data.Links.Attachments, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+
+	missing := v4.TransactionResponse{Data: &v4.Transaction{Links: v4.TransactionLinks{Attachments: fmt.Sprintf("http://example.com/v4/transactions/%s/attachments", uuid.New())}}}
+	_ = createTestAttachment(suite.T(), missing, "receipt.pdf", testPDF, http.StatusNotFound)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"GET not a UUID", http.MethodGet, "attachments/notauuid", http.StatusBadRequest},
+		{"GET not found", http.MethodGet, fmt.Sprintf("attachments/%s", uuid.New()), http.StatusNotFound},
+		{"GET file not a UUID", http.MethodGet, "attachments/notauuid/file", http.StatusBadRequest},
+		{"GET file not found", http.MethodGet, fmt.Sprintf("attachments/%s/file", uuid.New()), http.StatusNotFound},
+		{"DELETE not found", http.MethodDelete, fmt.Sprintf("attachments/%s", uuid.New()), http.StatusNotFound},
+		{"OPTIONS not found", http.MethodOptions, fmt.Sprintf("attachments/%s", uuid.New()), http.StatusNotFound},
+		{"List not a UUID", http.MethodGet, "transactions/notauuid/attachments", http.StatusBadRequest},
+		{"List transaction not found", http.MethodGet, fmt.Sprintf("transactions/%s/attachments", uuid.New()), http.StatusNotFound},
+		{"OPTIONS list transaction not found", http.MethodOptions, fmt.Sprintf("transactions/%s/attachments", uuid.New()), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, tt.method, fmt.Sprintf("http://example.com/v4/%s", tt.path), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
+
+// TestAttachmentsFileMissing verifies that a download fails with a server error
+// if the file of an attachment is missing.
+func (suite *TestSuiteStandard) TestAttachmentsFileMissing() {
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{Amount: decimal.NewFromFloat(17.32)})
+	receipt := createTestAttachment(suite.T(), transaction, "receipt.pdf", testPDF)
+
+	require.Nil(suite.T(), models.Storage.Delete(receipt.Data.ID.String()))
+
+	recorder := test.Request(suite.T(), http.MethodGet, receipt.Data.Links.File, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusInternalServerError)
+}
+
+// TestAttachmentsTransactionDeleted verifies that attachments are deleted and restored with their transaction.
+func (suite *TestSuiteStandard) TestAttachmentsTransactionDeleted() {
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{Amount: decimal.NewFromFloat(17.32)})
+	receipt := createTestAttachment(suite.T(), transaction, "receipt.pdf", testPDF)
+
+	recorder := test.Request(suite.T(), http.MethodDelete, transaction.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, receipt.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/trash?resource=%s", transaction.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var trash v4.TrashEntryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &trash)
+	require.Len(suite.T(), trash.Data, 1)
+
+	recorder = test.Request(suite.T(), http.MethodPost, trash.Data[0].Links.Restore, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, receipt.Data.Links.File, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	assert.Equal(suite.T(), testPDF, recorder.Body.String())
+
+	// Without a trash, the file is deleted together with the transaction
+	retention := models.TrashRetention
+	models.TrashRetention = 0
+	defer func() { models.TrashRetention = retention }()
+
+	recorder = test.Request(suite.T(), http.MethodDelete, transaction.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	_, err := models.Storage.Open(receipt.Data.ID.String())
+	assert.ErrorIs(suite.T(), err, fs.ErrNotExist)
+}
+
+// TestAttachmentsBulkDeleteDryRun verifies that files of attachments are kept when
+// deleting their transactions is not committed.
+func (suite *TestSuiteStandard) TestAttachmentsBulkDeleteDryRun() {
+	retention := models.TrashRetention
+	models.TrashRetention = 0
+	defer func() { models.TrashRetention = retention }()
+
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{Amount: decimal.NewFromFloat(17.32), Note: "Receipt"})
+	receipt := createTestAttachment(suite.T(), transaction, "receipt.pdf", testPDF)
+
+	recorder := test.Request(suite.T(), http.MethodDelete, "http://example.com/v4/transactions?dryRun=true&note=Receipt", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	recorder = test.Request(suite.T(), http.MethodGet, receipt.Data.Links.File, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	assert.Equal(suite.T(), testPDF, recorder.Body.String(), "The file must be kept for dry runs")
+
+	recorder = test.Request(suite.T(), http.MethodDelete, "http://example.com/v4/transactions?note=Receipt", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	_, err := models.Storage.Open(receipt.Data.ID.String())
+	assert.ErrorIs(suite.T(), err, fs.ErrNotExist)
+}
+
+// TestAttachmentsExport verifies that the archive export contains the files of all attachments.
+func (suite *TestSuiteStandard) TestAttachmentsExport() {
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{Amount: decimal.NewFromFloat(17.32)})
+	receipt := createTestAttachment(suite.T(), transaction, "receipt.pdf", testPDF)
+
+	recorder := test.Request(suite.T(), http.MethodGet, "http://example.com/v4/export?format=archive", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	assert.Equal(suite.T(), "application/zip", recorder.Header().Get("Content-Type"))
+
+	body := recorder.Body.Bytes()
+	reader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.Nil(suite.T(), err)
+
+	f, err := reader.Open(fmt.Sprintf("attachments/%s/receipt.pdf", receipt.Data.ID))
+	require.Nil(suite.T(), err)
+	content, err := io.ReadAll(f)
+	require.Nil(suite.T(), err)
+	assert.Equal(suite.T(), testPDF, string(content))
+
+	f, err = reader.Open("export.json")
+	require.Nil(suite.T(), err)
+	content, err = io.ReadAll(f)
+	require.Nil(suite.T(), err)
+	assert.Contains(suite.T(), string(content), receipt.Data.ID.String())
+}
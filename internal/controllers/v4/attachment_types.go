@@ -0,0 +1,57 @@
+package v4
+
+import (
+	"fmt"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// attachmentLimits are the limits for files attached to transactions.
+var attachmentLimits = uploadLimits{
+	maxSize:      10 << 20,
+	contentTypes: []string{"application/pdf", "image/gif", "image/jpeg", "image/png", "image/webp", "text/plain"},
+}
+
+type AttachmentLinks struct {
+	Self        string `json:"self" example:"https://example.com/api/v4/attachments/4f1a8c2e-7b3d-4e8a-9c1f-2d6b5a4e3c21"`         // The attachment itself
+	File        string `json:"file" example:"https://example.com/api/v4/attachments/4f1a8c2e-7b3d-4e8a-9c1f-2d6b5a4e3c21/file"`    // Download of the file
+	Transaction string `json:"transaction" example:"https://example.com/api/v4/transactions/d430d7c3-d14c-4712-9336-ee56965a6673"` // The transaction the file is attached to
+}
+
+type Attachment struct {
+	models.DefaultModel
+	TransactionID uuid.UUID       `json:"transactionId" example:"d430d7c3-d14c-4712-9336-ee56965a6673"` // ID of the transaction the file is attached to
+	FileName      string          `json:"fileName" example:"receipt.pdf"`                               // Name of the file as it was uploaded
+	ContentType   string          `json:"contentType" example:"application/pdf"`                        // MIME type of the file
+	Size          int64           `json:"size" example:"48213"`                                         // Size of the file in bytes
+	Links         AttachmentLinks `json:"links"`
+}
+
+func newAttachment(c *gin.Context, model models.Attachment) Attachment {
+	url := c.GetString(string(models.DBContextURL))
+
+	return Attachment{
+		DefaultModel:  model.DefaultModel,
+		TransactionID: model.TransactionID,
+		FileName:      model.FileName,
+		ContentType:   model.ContentType,
+		Size:          model.Size,
+		Links: AttachmentLinks{
+			Self:        fmt.Sprintf("%s/v4/attachments/%s", url, model.ID),
+			File:        fmt.Sprintf("%s/v4/attachments/%s/file", url, model.ID),
+			Transaction: fmt.Sprintf("%s/v4/transactions/%s", url, model.TransactionID),
+		},
+	}
+}
+
+type AttachmentListResponse struct {
+	Data  []Attachment `json:"data"`                                                          // List of attachments
+	Error *string      `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+type AttachmentResponse struct {
+	Data  *Attachment `json:"data"`                                                          // Data for the attachment
+	Error *string     `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
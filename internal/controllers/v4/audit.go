@@ -0,0 +1,131 @@
+package v4
+
+import (
+	"net/http"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slices"
+)
+
+// RegisterAuditRoutes registers the routes for the audit log with
+// the RouterGroup that is passed.
+func RegisterAuditRoutes(r *gin.RouterGroup) {
+	{
+		r.OPTIONS("", OptionsAuditEntries)
+		r.GET("", httputil.NotModified, GetAuditEntries)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Audit
+// @Success		204
+// @Router			/v4/audit [options]
+func OptionsAuditEntries(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Get audit log
+// @Description	Returns the log of all changes to resources, newest first
+// @Tags			Audit
+// @Produce		json
+// @Success		200	{object}	AuditEntryListResponse
+// @Success		304
+// @Failure		400				{object}	AuditEntryListResponse
+// @Failure		500				{object}	AuditEntryListResponse
+// @Param			model			query		string	false	"Filter by type of the resource, e.g. Transaction"
+// @Param			resource		query		string	false	"Filter by ID of the resource"
+// @Param			action			query		string	false	"Filter by kind of change. One of CREATE, UPDATE, DELETE"
+// @Param			requestId		query		string	false	"Filter by ID of the HTTP request that made the change"
+// @Param			fromTime		query		string	false	"Changes at and after this RFC3339 timestamp"
+// @Param			untilTime		query		string	false	"Changes before and at this RFC3339 timestamp"
+// @Param			offset			query		uint	false	"The offset of the first audit entry returned. Defaults to 0."
+// @Param			limit			query		int		false	"Maximum number of audit entries to return. Defaults to 50."
+// @Param			sort			query		string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are createdAt. Defaults to -createdAt"
+// @Param			cursor			query		string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/audit [get]
+func GetAuditEntries(c *gin.Context) {
+	var filter AuditEntryQueryFilter
+	if err := c.Bind(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, AuditEntryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// Get the parameters set in the query string
+	_, setFields := httputil.GetURLFields(c.Request.URL, filter)
+
+	if filter.Action != "" && !slices.Contains([]models.AuditAction{models.AuditActionCreate, models.AuditActionUpdate, models.AuditActionDelete}, filter.Action) {
+		s := errAuditActionInvalid.Error()
+		c.JSON(http.StatusBadRequest, AuditEntryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	q := models.DB.
+		Where(&models.AuditEntry{
+			Model:      filter.Model,
+			ResourceID: filter.ResourceID.UUID,
+			Action:     filter.Action,
+			RequestID:  filter.RequestID,
+		})
+
+	if !filter.FromTime.IsZero() {
+		q = q.Where("created_at >= ?", filter.FromTime)
+	}
+
+	if !filter.UntilTime.IsZero() {
+		q = q.Where("created_at <= ?", filter.UntilTime)
+	}
+
+	// Default to 50 audit entries and set the limit
+	limit := 50
+	if slices.Contains(setFields, "Limit") {
+		limit = filter.Limit
+	}
+
+	page, err := newListPage(auditEntrySorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, AuditEntryListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
+
+	var entries []models.AuditEntry
+	err = q.Find(&entries).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AuditEntryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	entries, pagination, err := paginate(c, q, page, entries)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), AuditEntryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := make([]AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		data = append(data, newAuditEntry(c, entry))
+	}
+
+	c.JSON(http.StatusOK, AuditEntryListResponse{
+		Data:       data,
+		Pagination: &pagination,
+	})
+}
@@ -0,0 +1,100 @@
+package v4_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuditEntries verifies that changes made through the API are recorded
+// with the ID of the request that made them.
+func (suite *TestSuiteStandard) TestAuditEntries() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{Name: "Audited"})
+
+	recorder := test.Request(suite.T(), http.MethodPatch, budget.Data.Links.Self, map[string]any{"name": "Renamed"}, map[string]string{"X-Request-ID": "rename-budget"})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/audit?resource=%s", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.AuditEntryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+
+	require.Len(suite.T(), response.Data, 2)
+	assert.Equal(suite.T(), int64(2), response.Pagination.Total)
+
+	// Newest entries come first
+	update := response.Data[0]
+	assert.Equal(suite.T(), models.AuditActionUpdate, update.Action)
+	assert.Equal(suite.T(), "Budget", update.Model)
+	assert.Equal(suite.T(), "rename-budget", update.RequestID)
+	assert.Equal(suite.T(), models.AuditChange{Old: "Audited", New: "Renamed"}, update.Changes["name"])
+	assert.Equal(suite.T(), budget.Data.Links.Self, update.Links.Resource)
+
+	assert.Equal(suite.T(), models.AuditActionCreate, response.Data[1].Action)
+	assert.NotEmpty(suite.T(), response.Data[1].RequestID)
+}
+
+// TestAuditEntriesFilter verifies that audit entries are filtered correctly.
+func (suite *TestSuiteStandard) TestAuditEntriesFilter() {
+	start := time.Now()
+
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	_ = createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank"})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+
+	recorder := test.Request(suite.T(), http.MethodDelete, shop.Data.Links.Self, "", map[string]string{"X-Request-ID": "delete-account"})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	tests := []struct {
+		name  string
+		query string
+		len   int
+	}{
+		{"All", "", 4},
+		{"Model", "model=Account", 3},
+		{"Action", "action=DELETE", 1},
+		{"Request ID", "requestId=delete-account", 1},
+		{"From time", fmt.Sprintf("fromTime=%s", start.Format(time.RFC3339Nano)), 4},
+		{"Until time", fmt.Sprintf("untilTime=%s", start.Format(time.RFC3339Nano)), 0},
+		{"Limit", "limit=1", 1},
+		{"Offset", "offset=3", 1},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/audit?%s", tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+			var response v4.AuditEntryListResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.Len(t, response.Data, tt.len)
+		})
+	}
+}
+
+// TestAuditEntriesFails verifies that invalid requests are handled correctly.
+func (suite *TestSuiteStandard) TestAuditEntriesFails() {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"Invalid action", "action=READ"},
+		{"Invalid resource ID", "resource=NotParseableAsUUID"},
+		{"Invalid time", "fromTime=yesterday"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/audit?%s", tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusBadRequest)
+		})
+	}
+}
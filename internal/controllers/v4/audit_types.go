@@ -0,0 +1,89 @@
+package v4
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditEntryLinks struct {
+	Resource string `json:"resource" example:"https://example.com/api/v4/transactions/0b4ea4b5-1a8e-4c35-9fa0-1a2a1a0a4f3e"` // The resource that was changed. For month configs, this is the envelope.
+}
+
+// AuditEntry is the API representation of an entry in the audit log.
+type AuditEntry struct {
+	ID         uuid.UUID                     `json:"id" example:"65392deb-5e92-4268-b114-297faad6cdce"`         // ID of the audit entry
+	Time       time.Time                     `json:"time" example:"2024-04-02T19:28:44.491514Z"`                // Time of the change
+	Model      string                        `json:"model" example:"Transaction"`                               // Type of the resource that was changed
+	ResourceID uuid.UUID                     `json:"resourceId" example:"0b4ea4b5-1a8e-4c35-9fa0-1a2a1a0a4f3e"` // ID of the resource. For month configs, this is the ID of the envelope.
+	Action     models.AuditAction            `json:"action" example:"UPDATE"`                                   // The kind of change
+	Changes    map[string]models.AuditChange `json:"changes"`                                                   // Changed fields, identified by their database column, with their old and new values
+	RequestID  string                        `json:"requestId" example:"c6b1a2f0-5f4e-4c59-8c8e-3f2b7d0c1e9a"`  // ID of the HTTP request that made the change, as sent in the X-Request-ID header
+	Links      AuditEntryLinks               `json:"links"`                                                     // Links for the audit entry
+}
+
+// auditResourcePaths maps model names to the path of their collection endpoint.
+var auditResourcePaths = map[string]string{
+	"Account":        "accounts",
+	"Attachment":     "attachments",
+	"Budget":         "budgets",
+	"Category":       "categories",
+	"Envelope":       "envelopes",
+	"ExchangeRate":   "exchange-rates",
+	"Goal":           "goals",
+	"MatchRule":      "match-rules",
+	"MonthConfig":    "envelopes",
+	"Reconciliation": "reconciliations",
+	"Tag":            "tags",
+	"Transaction":    "transactions",
+	"TransactionTag": "transactions",
+}
+
+func newAuditEntry(c *gin.Context, model models.AuditEntry) AuditEntry {
+	url := c.GetString(string(models.DBContextURL))
+
+	return AuditEntry{
+		ID:         model.ID,
+		Time:       model.CreatedAt,
+		Model:      model.Model,
+		ResourceID: model.ResourceID,
+		Action:     model.Action,
+		Changes:    model.Changes,
+		RequestID:  model.RequestID,
+		Links: AuditEntryLinks{
+			Resource: fmt.Sprintf("%s/v4/%s/%s", url, auditResourcePaths[model.Model], model.ResourceID),
+		},
+	}
+}
+
+type AuditEntryListResponse struct {
+	Data       []AuditEntry `json:"data"`                                                          // List of audit entries
+	Error      *string      `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Pagination *Pagination  `json:"pagination"`                                                    // Pagination information
+}
+
+type AuditEntryQueryFilter struct {
+	Model      string             `form:"model"`     // By type of the resource, e.g. "Transaction"
+	ResourceID ez_uuid.UUID       `form:"resource"`  // By ID of the resource
+	Action     models.AuditAction `form:"action"`    // By kind of change
+	RequestID  string             `form:"requestId"` // By ID of the HTTP request
+	FromTime   time.Time          `form:"fromTime"`  // Changes at and after this time
+	UntilTime  time.Time          `form:"untilTime"` // Changes before and at this time
+	Offset     uint               `form:"offset"`    // The offset of the first audit entry returned. Defaults to 0.
+	Limit      int                `form:"limit"`     // Maximum number of audit entries to return. Defaults to 50.
+	Sort       string             `form:"sort"`      // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor     string             `form:"cursor"`    // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// auditEntrySorting defines how lists of audit entries can be sorted
+var auditEntrySorting = listSorting{
+	table: "audit_entries",
+	fields: map[string]string{
+		"createdAt": "audit_entries.created_at",
+	},
+	defaults: "-createdAt",
+}
@@ -1,12 +1,15 @@
 package v4
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/envelope-zero/backend/v7/internal/httputil"
 	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/templates"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
 )
 
 // RegisterBudgetRoutes registers the routes for Budgets with
@@ -15,7 +18,7 @@ func RegisterBudgetRoutes(r *gin.RouterGroup) {
 	// Root group
 	{
 		r.OPTIONS("", OptionsBudgetList)
-		r.GET("", GetBudgets)
+		r.GET("", httputil.NotModified, GetBudgets)
 		r.POST("", CreateBudgets)
 	}
 
@@ -25,6 +28,12 @@ func RegisterBudgetRoutes(r *gin.RouterGroup) {
 		r.GET("/:id", GetBudget)
 		r.PATCH("/:id", UpdateBudget)
 		r.DELETE("/:id", DeleteBudget)
+
+		r.OPTIONS("/:id/clone", OptionsBudgetClone)
+		r.POST("/:id/clone", CloneBudget)
+
+		r.OPTIONS("/:id/template", OptionsBudgetTemplate)
+		r.POST("/:id/template", ApplyBudgetTemplate)
 	}
 }
 
@@ -80,7 +89,7 @@ func CreateBudgets(c *gin.Context) {
 	for _, editable := range budgets {
 		budget := editable.model()
 
-		err := models.DB.Create(&budget).Error
+		err := models.DB.WithContext(c).Create(&budget).Error
 		if err != nil {
 			status = r.appendError(err, status)
 			continue
@@ -98,7 +107,9 @@ func CreateBudgets(c *gin.Context) {
 // @Tags			Budgets
 // @Produce		json
 // @Success		200	{object}	BudgetListResponse
-// @Failure		500	{object}	BudgetListResponse
+// @Success		304
+// @Failure		500				{object}	BudgetListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
 // @Router			/v4/budgets [get]
 // @Param			name		query	string	false	"Filter by name"
 // @Param			note		query	string	false	"Filter by note"
@@ -106,6 +117,8 @@ func CreateBudgets(c *gin.Context) {
 // @Param			search		query	string	false	"Search for this text in name and note"
 // @Param			offset		query	uint	false	"The offset of the first Budget returned. Defaults to 0."
 // @Param			limit		query	int		false	"Maximum number of Budgets to return. Defaults to 50."
+// @Param			sort		query	string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name"
+// @Param			cursor		query	string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
 func GetBudgets(c *gin.Context) {
 	var filter BudgetQueryFilter
 
@@ -117,24 +130,28 @@ func GetBudgets(c *gin.Context) {
 
 	var budgets []models.Budget
 
-	// Always sort by name
 	q := models.DB.
-		Order("name ASC").
 		Where(filter.model(), queryFields...)
 
 	q = stringFilters(models.DB, q, setFields, filter.Name, filter.Note, filter.Search)
 
-	// Set the offset. Does not need checking since the default is 0
-	q = q.Offset(int(filter.Offset))
-
 	// Default to all Budgets and set the limit
 	limit := 50
 	if slices.Contains(setFields, "Limit") {
 		limit = filter.Limit
 	}
-	q = q.Limit(limit)
 
-	err := q.Find(&budgets).Error
+	page, err := newListPage(budgetSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, BudgetListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
+
+	err = q.Find(&budgets).Error
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), BudgetListResponse{
@@ -143,8 +160,7 @@ func GetBudgets(c *gin.Context) {
 		return
 	}
 
-	var count int64
-	err = q.Limit(-1).Offset(-1).Count(&count).Error
+	budgets, pagination, err := paginate(c, q, page, budgets)
 	if err != nil {
 		e := err.Error()
 		c.JSON(status(err), BudgetListResponse{
@@ -159,13 +175,8 @@ func GetBudgets(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, BudgetListResponse{
-		Data: apiResources,
-		Pagination: &Pagination{
-			Count:  len(apiResources),
-			Total:  count,
-			Offset: filter.Offset,
-			Limit:  limit,
-		},
+		Data:       apiResources,
+		Pagination: &pagination,
 	})
 }
 
@@ -174,6 +185,7 @@ func GetBudgets(c *gin.Context) {
 // @Tags			Budgets
 // @Produce		json
 // @Success		200	{object}	BudgetResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
 // @Failure		400	{object}	BudgetResponse
 // @Failure		404	{object}	BudgetResponse
 // @Failure		500	{object}	BudgetResponse
@@ -201,6 +213,7 @@ func GetBudget(c *gin.Context) {
 	}
 
 	apiResource := newBudget(c, budget)
+	c.Header("ETag", httputil.ETag(budget.UpdatedAt))
 	c.JSON(http.StatusOK, BudgetResponse{Data: &apiResource})
 }
 
@@ -209,12 +222,14 @@ func GetBudget(c *gin.Context) {
 // @Tags			Budgets
 // @Accept			json
 // @Produce		json
-// @Success		200		{object}	BudgetResponse
-// @Failure		400		{object}	BudgetResponse
-// @Failure		404		{object}	BudgetResponse
-// @Failure		500		{object}	BudgetResponse
-// @Param			id		path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
-// @Param			budget	body		BudgetEditable	true	"Budget"
+// @Success		200			{object}	BudgetResponse
+// @Failure		400			{object}	BudgetResponse
+// @Failure		404			{object}	BudgetResponse
+// @Failure		412			{object}	BudgetResponse
+// @Failure		500			{object}	BudgetResponse
+// @Param			id			path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			budget		body		BudgetEditable	true	"Budget"
+// @Param			If-Match	header		string			false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/budgets/{id} [patch]
 func UpdateBudget(c *gin.Context) {
 	var uri URIID
@@ -256,7 +271,14 @@ func UpdateBudget(c *gin.Context) {
 		return
 	}
 
-	err = models.DB.Model(&budget).Select("", updateFields...).Updates(data).Error
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &budget)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&budget).Select("", updateFields...).Updates(data.model()).Error
+	})
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), BudgetResponse{
@@ -266,18 +288,184 @@ func UpdateBudget(c *gin.Context) {
 	}
 
 	apiResource := newBudget(c, budget)
+	c.Header("ETag", httputil.ETag(budget.UpdatedAt))
 	c.JSON(http.StatusOK, BudgetResponse{Data: &apiResource})
 }
 
 // @Summary		Delete budget
-// @Description	Deletes a budget
+// @Description	Deletes a budget and moves it to the trash together with all its resources
 // @Tags			Budgets
 // @Success		204
-// @Failure		400	{object}	httpError
-// @Failure		404	{object}	httpError
-// @Failure		500	{object}	httpError
-// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/budgets/{id} [delete]
 func DeleteBudget(c *gin.Context) {
 	deleteResource[models.Budget](c)
 }
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Budgets
+// @Success		204
+// @Param			id	path	URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/budgets/{id}/clone [options]
+func OptionsBudgetClone(c *gin.Context) {
+	httputil.OptionsPost(c)
+}
+
+// @Summary		Clone budget
+// @Description	Creates a copy of a budget. Accounts, categories, envelopes and tags are always copied. Depending on the mode, goals and match rules or all resources including transactions and allocations are copied, too. Attachments are never copied.
+// @Tags			Budgets
+// @Accept			json
+// @Produce		json
+// @Success		201		{object}	BudgetResponse
+// @Failure		400		{object}	BudgetResponse
+// @Failure		404		{object}	BudgetResponse
+// @Failure		500		{object}	BudgetResponse
+// @Param			id		path		URIID		true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			options	body		BudgetClone	true	"Clone options"
+// @Router			/v4/budgets/{id}/clone [post]
+func CloneBudget(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), BudgetResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var data BudgetClone
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), BudgetResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if data.Mode == "" {
+		data.Mode = BudgetCloneStructure
+	}
+
+	if !slices.Contains([]BudgetCloneMode{BudgetCloneStructure, BudgetCloneGoalsMatchRules, BudgetCloneAll}, data.Mode) {
+		s := errBudgetCloneModeInvalid.Error()
+		c.JSON(http.StatusBadRequest, BudgetResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var budget models.Budget
+	err = models.DB.First(&budget, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), BudgetResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if data.Name == "" {
+		data.Name = fmt.Sprintf("%s (copy)", budget.Name)
+	}
+
+	clone, err := budget.Clone(models.DB.WithContext(c), data.Name, models.CloneOptions{
+		GoalsAndMatchRules: data.Mode != BudgetCloneStructure,
+		Transactions:       data.Mode == BudgetCloneAll,
+	})
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), BudgetResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	apiResource := newBudget(c, clone)
+	c.JSON(http.StatusCreated, BudgetResponse{Data: &apiResource})
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Budgets
+// @Success		204
+// @Param			id	path	URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/budgets/{id}/template [options]
+func OptionsBudgetTemplate(c *gin.Context) {
+	httputil.OptionsPost(c)
+}
+
+// @Summary		Apply template
+// @Description	Creates the categories and envelopes of a template for the budget. Categories and envelopes that already exist with the same name are kept.
+// @Tags			Budgets
+// @Accept			json
+// @Produce		json
+// @Success		201			{object}	CategoryListResponse
+// @Failure		400			{object}	CategoryListResponse
+// @Failure		404			{object}	CategoryListResponse
+// @Failure		500			{object}	CategoryListResponse
+// @Param			id			path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			template	body		BudgetTemplate	true	"Template"
+// @Router			/v4/budgets/{id}/template [post]
+func ApplyBudgetTemplate(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), CategoryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var data BudgetTemplate
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), CategoryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	template, err := templates.Get(data.Template)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), CategoryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	categories, err := template.Apply(models.DB.WithContext(c), uri.ID.UUID)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), CategoryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	apiResources := make([]Category, 0, len(categories))
+	for _, category := range categories {
+		apiResource, err := newCategory(c, models.DB, category)
+		if err != nil {
+			s := err.Error()
+			c.JSON(status(err), CategoryListResponse{
+				Error: &s,
+			})
+			return
+		}
+
+		apiResources = append(apiResources, apiResource)
+	}
+
+	c.JSON(http.StatusCreated, CategoryListResponse{Data: apiResources})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/envelope-zero/backend/v7/test"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -355,3 +356,140 @@ func (suite *TestSuiteStandard) TestBudgetsPagination() {
 		})
 	}
 }
+
+// TestBudgetsClone verifies that budgets are cloned correctly.
+func (suite *TestSuiteStandard) TestBudgetsClone() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{Name: "Original", Currency: "€"})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	external := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID, Name: "Daily"})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Groceries"})
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      account.Data.ID,
+		DestinationAccountID: external.Data.ID,
+		EnvelopeID:           &envelope.Data.ID,
+		Amount:               decimal.NewFromFloat(15),
+	})
+
+	tests := []struct {
+		name         string
+		body         v4.BudgetClone
+		expectedName string
+		transactions int
+	}{
+		{"Defaults", v4.BudgetClone{}, "Original (copy)", 0},
+		{"Structure", v4.BudgetClone{Name: "Structure", Mode: v4.BudgetCloneStructure}, "Structure", 0},
+		{"Goals and match rules", v4.BudgetClone{Name: "Goals", Mode: v4.BudgetCloneGoalsMatchRules}, "Goals", 0},
+		{"Everything", v4.BudgetClone{Name: "Everything", Mode: v4.BudgetCloneAll}, "Everything", 1},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, fmt.Sprintf("http://example.com/v4/budgets/%s/clone", budget.Data.ID), tt.body)
+			test.AssertHTTPStatus(t, &recorder, http.StatusCreated)
+
+			var clone v4.BudgetResponse
+			test.DecodeResponse(t, &recorder, &clone)
+			assert.NotEqual(t, budget.Data.ID, clone.Data.ID)
+			assert.Equal(t, tt.expectedName, clone.Data.Name)
+			assert.Equal(t, "€", clone.Data.Currency)
+
+			recorder = test.Request(t, http.MethodGet, clone.Data.Links.Accounts, "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+			var accounts v4.AccountListResponse
+			test.DecodeResponse(t, &recorder, &accounts)
+			assert.Len(t, accounts.Data, 2)
+
+			recorder = test.Request(t, http.MethodGet, clone.Data.Links.Envelopes, "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+			var envelopes v4.EnvelopeListResponse
+			test.DecodeResponse(t, &recorder, &envelopes)
+			require.Len(t, envelopes.Data, 1)
+			assert.Equal(t, "Groceries", envelopes.Data[0].Name)
+
+			recorder = test.Request(t, http.MethodGet, clone.Data.Links.Transactions, "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+			var transactions v4.TransactionListResponse
+			test.DecodeResponse(t, &recorder, &transactions)
+			assert.Len(t, transactions.Data, tt.transactions)
+		})
+	}
+}
+
+// TestBudgetsCloneFails verifies that failing clone requests are handled correctly.
+func (suite *TestSuiteStandard) TestBudgetsCloneFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	tests := []struct {
+		name   string
+		id     string
+		body   any
+		status int // expected response status
+	}{
+		{"Invalid ID", "NotParseableAsUUID", v4.BudgetClone{}, http.StatusBadRequest},
+		{"Non-existing budget", uuid.New().String(), v4.BudgetClone{}, http.StatusNotFound},
+		{"Invalid body", budget.Data.ID.String(), `{"mode": 2}`, http.StatusBadRequest},
+		{"Invalid mode", budget.Data.ID.String(), v4.BudgetClone{Mode: "EVERYTHING"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, fmt.Sprintf("http://example.com/v4/budgets/%s/clone", tt.id), tt.body)
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
+
+// TestBudgetsApplyTemplate verifies that templates are applied correctly.
+func (suite *TestSuiteStandard) TestBudgetsApplyTemplate() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	_ = createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID, Name: "Fixed Costs", Note: "Existing"})
+
+	// Applying the template twice must not fail or duplicate resources
+	for i := 0; i < 2; i++ {
+		recorder := test.Request(suite.T(), http.MethodPost, fmt.Sprintf("http://example.com/v4/budgets/%s/template", budget.Data.ID), v4.BudgetTemplate{Template: "minimal"})
+		test.AssertHTTPStatus(suite.T(), &recorder, http.StatusCreated)
+
+		var response v4.CategoryListResponse
+		test.DecodeResponse(suite.T(), &recorder, &response)
+		suite.Require().Len(response.Data, 2)
+		assert.Equal(suite.T(), "Fixed Costs", response.Data[0].Name)
+		assert.Equal(suite.T(), "Existing", response.Data[0].Note, "Existing categories must not be modified")
+		assert.Len(suite.T(), response.Data[0].Envelopes, 2)
+	}
+
+	recorder := test.Request(suite.T(), http.MethodGet, budget.Data.Links.Categories, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var categories v4.CategoryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &categories)
+	assert.Len(suite.T(), categories.Data, 2)
+}
+
+// TestBudgetsApplyTemplateFails verifies that failing template requests are handled correctly.
+func (suite *TestSuiteStandard) TestBudgetsApplyTemplateFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	tests := []struct {
+		name   string
+		id     string
+		body   any
+		status int // expected response status
+	}{
+		{"Invalid ID", "NotParseableAsUUID", v4.BudgetTemplate{Template: "minimal"}, http.StatusBadRequest},
+		{"Non-existing budget", uuid.New().String(), v4.BudgetTemplate{Template: "minimal"}, http.StatusNotFound},
+		{"Invalid body", budget.Data.ID.String(), `{"template": 2}`, http.StatusBadRequest},
+		{"Non-existing template", budget.Data.ID.String(), v4.BudgetTemplate{Template: "does-not-exist"}, http.StatusNotFound},
+		{"Path traversal", budget.Data.ID.String(), v4.BudgetTemplate{Template: "../data/minimal"}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, fmt.Sprintf("http://example.com/v4/budgets/%s/template", tt.id), tt.body)
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
@@ -96,6 +96,18 @@ type BudgetQueryFilter struct {
 	Search   string `form:"search" filterField:"false"` // By string in name or note
 	Offset   uint   `form:"offset" filterField:"false"` // The offset of the first Budget returned. Defaults to 0.
 	Limit    int    `form:"limit" filterField:"false"`  // Maximum number of Budgets to return. Defaults to 50.
+	Sort     string `form:"sort" filterField:"false"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor   string `form:"cursor" filterField:"false"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// budgetSorting defines how lists of budgets can be sorted
+var budgetSorting = listSorting{
+	table: "budgets",
+	fields: map[string]string{
+		"name":      "budgets.name",
+		"createdAt": "budgets.created_at",
+	},
+	defaults: "name",
 }
 
 func (f BudgetQueryFilter) model() models.Budget {
@@ -104,3 +116,21 @@ func (f BudgetQueryFilter) model() models.Budget {
 		Currency: f.Currency,
 	}
 }
+
+// swagger:enum BudgetCloneMode
+type BudgetCloneMode string
+
+const (
+	BudgetCloneStructure       BudgetCloneMode = "STRUCTURE"
+	BudgetCloneGoalsMatchRules BudgetCloneMode = "STRUCTURE_GOALS_MATCH_RULES"
+	BudgetCloneAll             BudgetCloneMode = "ALL"
+)
+
+type BudgetClone struct {
+	Name string          `json:"name" example:"Morre's Budget 2025" default:""` // Name of the new budget. Defaults to the name of the budget with " (copy)" appended.
+	Mode BudgetCloneMode `json:"mode" example:"STRUCTURE" default:"STRUCTURE"`  // What to copy. STRUCTURE copies accounts, categories, envelopes and tags, STRUCTURE_GOALS_MATCH_RULES adds goals and match rules, ALL adds transactions, allocations and initial balances.
+}
+
+type BudgetTemplate struct {
+	Template string `json:"template" example:"household"` // ID of the template to apply
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
 )
 
 // RegisterCategoryRoutes registers the routes for categories with
@@ -15,7 +16,7 @@ func RegisterCategoryRoutes(r *gin.RouterGroup) {
 	// Root group
 	{
 		r.OPTIONS("", OptionsCategoryList)
-		r.GET("", GetCategories)
+		r.GET("", httputil.NotModified, GetCategories)
 		r.POST("", CreateCategories)
 	}
 
@@ -80,7 +81,7 @@ func CreateCategories(c *gin.Context) {
 	for _, editable := range editables {
 		category := editable.model()
 
-		err = models.DB.Create(&category).Error
+		err = models.DB.WithContext(c).Create(&category).Error
 		if err != nil {
 			status = r.appendError(err, status)
 			continue
@@ -102,8 +103,10 @@ func CreateCategories(c *gin.Context) {
 // @Tags			Categories
 // @Produce		json
 // @Success		200	{object}	CategoryListResponse
-// @Failure		400	{object}	CategoryListResponse
-// @Failure		500	{object}	CategoryListResponse
+// @Success		304
+// @Failure		400				{object}	CategoryListResponse
+// @Failure		500				{object}	CategoryListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
 // @Router			/v4/categories [get]
 // @Param			name		query	string	false	"Filter by name"
 // @Param			note		query	string	false	"Filter by note"
@@ -112,6 +115,8 @@ func CreateCategories(c *gin.Context) {
 // @Param			search		query	string	false	"Search for this text in name and note"
 // @Param			offset		query	uint	false	"The offset of the first Category returned. Defaults to 0."
 // @Param			limit		query	int		false	"Maximum number of Categories to return. Defaults to 50."
+// @Param			sort		query	string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name"
+// @Param			cursor		query	string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
 func GetCategories(c *gin.Context) {
 	var filter CategoryQueryFilter
 
@@ -132,20 +137,25 @@ func GetCategories(c *gin.Context) {
 	}
 
 	q := models.DB.
-		Order("name ASC").
 		Where(&filterModel, queryFields...)
 
 	q = stringFilters(models.DB, q, setFields, filter.Name, filter.Note, filter.Search)
 
-	// Set the offset. Does not need checking since the default is 0
-	q = q.Offset(int(filter.Offset))
-
 	// Default to 50 Accounts and set the limit
 	limit := 50
 	if slices.Contains(setFields, "Limit") {
 		limit = filter.Limit
 	}
-	q = q.Limit(limit)
+
+	page, err := newListPage(categorySorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, CategoryListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
 
 	var categories []models.Category
 	err = q.Find(&categories).Error
@@ -157,8 +167,7 @@ func GetCategories(c *gin.Context) {
 		return
 	}
 
-	var count int64
-	err = q.Limit(-1).Offset(-1).Count(&count).Error
+	categories, pagination, err := paginate(c, q, page, categories)
 	if err != nil {
 		e := err.Error()
 		c.JSON(status(err), CategoryListResponse{
@@ -181,13 +190,8 @@ func GetCategories(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, CategoryListResponse{
-		Data: data,
-		Pagination: &Pagination{
-			Count:  len(data),
-			Total:  count,
-			Offset: filter.Offset,
-			Limit:  limit,
-		},
+		Data:       data,
+		Pagination: &pagination,
 	})
 }
 
@@ -196,6 +200,7 @@ func GetCategories(c *gin.Context) {
 // @Tags			Categories
 // @Produce		json
 // @Success		200	{object}	CategoryResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
 // @Failure		400	{object}	CategoryResponse
 // @Failure		404	{object}	CategoryResponse
 // @Failure		500	{object}	CategoryResponse
@@ -231,6 +236,7 @@ func GetCategory(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", httputil.ETag(category.UpdatedAt))
 	c.JSON(http.StatusOK, CategoryResponse{Data: &data})
 }
 
@@ -242,9 +248,11 @@ func GetCategory(c *gin.Context) {
 // @Success		200			{object}	CategoryResponse
 // @Failure		400			{object}	CategoryResponse
 // @Failure		404			{object}	CategoryResponse
+// @Failure		412			{object}	CategoryResponse
 // @Failure		500			{object}	CategoryResponse
 // @Param			id			path		URIID				true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
 // @Param			category	body		CategoryEditable	true	"Category"
+// @Param			If-Match	header		string				false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/categories/{id} [patch]
 func UpdateCategory(c *gin.Context) {
 	var uri URIID
@@ -286,7 +294,14 @@ func UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	err = models.DB.Model(&category).Select("", updateFields...).Updates(data.model()).Error
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &category)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&category).Select("", updateFields...).Updates(data.model()).Error
+	})
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), CategoryResponse{
@@ -304,17 +319,20 @@ func UpdateCategory(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", httputil.ETag(category.UpdatedAt))
 	c.JSON(http.StatusOK, CategoryResponse{Data: &r})
 }
 
 // @Summary		Delete category
-// @Description	Deletes a category
+// @Description	Deletes a category and moves it to the trash together with its envelopes
 // @Tags			Categories
 // @Success		204
-// @Failure		400	{object}	httpError
-// @Failure		404	{object}	httpError
-// @Failure		500	{object}	httpError
-// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/categories/{id} [delete]
 func DeleteCategory(c *gin.Context) {
 	deleteResource[models.Category](c)
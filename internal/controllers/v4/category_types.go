@@ -107,6 +107,18 @@ type CategoryQueryFilter struct {
 	Search   string       `form:"search" filterField:"false"` // By string in name or note
 	Offset   uint         `form:"offset" filterField:"false"` // The offset of the first Category returned. Defaults to 0.
 	Limit    int          `form:"limit" filterField:"false"`  // Maximum number of Categories to return. Defaults to 50.
+	Sort     string       `form:"sort" filterField:"false"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor   string       `form:"cursor" filterField:"false"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// categorySorting defines how lists of categories can be sorted
+var categorySorting = listSorting{
+	table: "categories",
+	fields: map[string]string{
+		"name":      "categories.name",
+		"createdAt": "categories.created_at",
+	},
+	defaults: "name",
 }
 
 func (f CategoryQueryFilter) model() (models.Category, error) {
@@ -8,7 +8,7 @@ import (
 )
 
 // @Summary		Delete everything
-// @Description	Permanently deletes all resources
+// @Description	Permanently deletes all resources, including the trash and the files of attachments
 // @Tags			v4
 // @Success		204
 // @Failure		400		{object}	httpError
@@ -31,19 +31,44 @@ func Cleanup(c *gin.Context) {
 	// Foreign keys are checked during cleanup,
 	// add new models *before* any of the models
 	// they reference
+	//
+	// Webhooks are deleted first so that no deliveries
+	// are queued for the deleted resources, budget events
+	// and audit entries are deleted last since deleting
+	// resources writes them
 	resources := []any{
+		models.WebhookDelivery{},
+		models.Webhook{},
+		models.TrashEntry{},
+		models.Reconciliation{},
+		models.Attachment{},
+		models.TransactionTag{},
 		models.Transaction{},
+		models.Tag{},
 		models.MonthConfig{},
 		models.MatchRule{},
 		models.Goal{},
 		models.Envelope{},
 		models.Category{},
 		models.Account{},
+		models.ExchangeRate{},
 		models.Budget{},
+		models.BudgetEvent{},
+		models.AuditEntry{},
 	}
 
 	// Use a transaction so that we can roll back if errors happen
-	tx := models.DB.Begin()
+	tx := models.DB.WithContext(c).Begin()
+
+	// The files of attachments are deleted once the database is cleaned up
+	attachments, err := models.StoredAttachments(tx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, httpError{
+			Error: err.Error(),
+		})
+		tx.Rollback()
+		return
+	}
 
 	for _, model := range resources {
 		err := tx.Unscoped().Where("true").Delete(&model).Error
@@ -56,6 +81,14 @@ func Cleanup(c *gin.Context) {
 		}
 	}
 
-	tx.Commit()
+	err = tx.Commit().Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	models.DeleteStoredAttachments(attachments)
 	c.JSON(http.StatusNoContent, nil)
 }
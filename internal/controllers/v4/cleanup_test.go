@@ -2,11 +2,13 @@ package v4_test
 
 import (
 	"fmt"
+	"io/fs"
 	"net/http"
 	"testing"
 	"time"
 
 	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/envelope-zero/backend/v7/internal/types"
 	"github.com/envelope-zero/backend/v7/test"
 	"github.com/shopspring/decimal"
@@ -18,12 +20,14 @@ func (suite *TestSuiteStandard) TestCleanup() {
 	account := createTestAccount(suite.T(), v4.AccountEditable{Name: "TestCleanup"})
 	_ = createTestCategory(suite.T(), v4.CategoryEditable{})
 	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{})
-	_ = createTestTransaction(suite.T(), v4.TransactionEditable{Amount: decimal.NewFromFloat(17.32)})
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{Amount: decimal.NewFromFloat(17.32)})
+	attachment := createTestAttachment(suite.T(), transaction, "receipt.pdf", testPDF)
 	_ = patchTestMonthConfig(suite.T(), envelope.Data.ID, types.NewMonth(time.Now().Year(), time.Now().Month()), v4.MonthConfigEditable{})
 	_ = createTestMatchRule(suite.T(), v4.MatchRuleEditable{AccountID: account.Data.ID, Match: "Delete me"})
 
 	tests := []string{
 		"http://example.com/v4/accounts",
+		"http://example.com/v4/audit",
 		"http://example.com/v4/budgets",
 		"http://example.com/v4/categories",
 		"http://example.com/v4/envelopes",
@@ -36,6 +40,9 @@ func (suite *TestSuiteStandard) TestCleanup() {
 	recorder := test.Request(suite.T(), http.MethodDelete, "http://example.com/v4?confirm=yes-please-delete-everything", "")
 	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
 
+	_, err := models.Storage.Open(attachment.Data.ID.String())
+	assert.ErrorIs(suite.T(), err, fs.ErrNotExist, "The files of attachments must be deleted")
+
 	// Verify
 	for _, tt := range tests {
 		suite.T().Run(tt, func(t *testing.T) {
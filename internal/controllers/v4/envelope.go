@@ -5,9 +5,11 @@ import (
 
 	"github.com/envelope-zero/backend/v7/internal/httputil"
 	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
 	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
 )
 
 // RegisterEnvelopeRoutes registers the routes for envelopes with
@@ -16,7 +18,7 @@ func RegisterEnvelopeRoutes(r *gin.RouterGroup) {
 	// Root group
 	{
 		r.OPTIONS("", OptionsEnvelopeList)
-		r.GET("", GetEnvelopes)
+		r.GET("", httputil.NotModified, GetEnvelopes)
 		r.POST("", CreateEnvelopes)
 	}
 
@@ -26,6 +28,10 @@ func RegisterEnvelopeRoutes(r *gin.RouterGroup) {
 		r.GET("/:id", GetEnvelope)
 		r.PATCH("/:id", UpdateEnvelope)
 		r.DELETE("/:id", DeleteEnvelope)
+		r.OPTIONS("/:id/history", OptionsEnvelopeHistory)
+		r.GET("/:id/history", httputil.NotModified, GetEnvelopeHistory)
+		r.OPTIONS("/:id/merge", OptionsEnvelopeMerge)
+		r.POST("/:id/merge", MergeEnvelopes)
 	}
 }
 
@@ -80,7 +86,7 @@ func CreateEnvelopes(c *gin.Context) {
 
 	for _, editable := range envelopes {
 		envelope := editable.model()
-		err = models.DB.Create(&envelope).Error
+		err = models.DB.WithContext(c).Create(&envelope).Error
 		if err != nil {
 			status = r.appendError(err, status)
 			continue
@@ -98,8 +104,10 @@ func CreateEnvelopes(c *gin.Context) {
 // @Tags			Envelopes
 // @Produce		json
 // @Success		200	{object}	EnvelopeListResponse
-// @Failure		400	{object}	EnvelopeListResponse
-// @Failure		500	{object}	EnvelopeListResponse
+// @Success		304
+// @Failure		400				{object}	EnvelopeListResponse
+// @Failure		500				{object}	EnvelopeListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
 // @Router			/v4/envelopes [get]
 // @Param			name		query	string	false	"Filter by name"
 // @Param			note		query	string	false	"Filter by note"
@@ -108,6 +116,8 @@ func CreateEnvelopes(c *gin.Context) {
 // @Param			search		query	string	false	"Search for this text in name and note"
 // @Param			offset		query	uint	false	"The offset of the first Envelope returned. Defaults to 0."
 // @Param			limit		query	int		false	"Maximum number of Envelopes to return. Defaults to 50."
+// @Param			sort		query	string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name"
+// @Param			cursor		query	string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
 func GetEnvelopes(c *gin.Context) {
 	var filter EnvelopeQueryFilter
 
@@ -127,7 +137,6 @@ func GetEnvelopes(c *gin.Context) {
 	}
 
 	q := models.DB.
-		Order("envelopes.name ASC").
 		Where(&model, queryFields...)
 
 	q = stringFilters(models.DB, q, setFields, filter.Name, filter.Note, filter.Search)
@@ -139,15 +148,21 @@ func GetEnvelopes(c *gin.Context) {
 			Where("budgets.id = ?", filter.BudgetID.UUID)
 	}
 
-	// Set the offset. Does not need checking since the default is 0
-	q = q.Offset(int(filter.Offset))
-
 	// Default to 50 Accounts and set the limit
 	limit := 50
 	if slices.Contains(setFields, "Limit") {
 		limit = filter.Limit
 	}
-	q = q.Limit(limit)
+
+	page, err := newListPage(envelopeSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, EnvelopeListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
 
 	var envelopes []models.Envelope
 	err = q.Find(&envelopes).Error
@@ -159,8 +174,7 @@ func GetEnvelopes(c *gin.Context) {
 		return
 	}
 
-	var count int64
-	err = q.Limit(-1).Offset(-1).Count(&count).Error
+	envelopes, pagination, err := paginate(c, q, page, envelopes)
 	if err != nil {
 		e := err.Error()
 		c.JSON(status(err), EnvelopeListResponse{
@@ -175,13 +189,8 @@ func GetEnvelopes(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, EnvelopeListResponse{
-		Data: data,
-		Pagination: &Pagination{
-			Count:  len(data),
-			Total:  count,
-			Offset: filter.Offset,
-			Limit:  limit,
-		},
+		Data:       data,
+		Pagination: &pagination,
 	})
 }
 
@@ -190,6 +199,7 @@ func GetEnvelopes(c *gin.Context) {
 // @Tags			Envelopes
 // @Produce		json
 // @Success		200	{object}	EnvelopeResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
 // @Failure		400	{object}	EnvelopeResponse
 // @Failure		404	{object}	EnvelopeResponse
 // @Failure		500	{object}	EnvelopeResponse
@@ -217,6 +227,7 @@ func GetEnvelope(c *gin.Context) {
 	}
 
 	data := newEnvelope(c, envelope)
+	c.Header("ETag", httputil.ETag(envelope.UpdatedAt))
 	c.JSON(http.StatusOK, EnvelopeResponse{Data: &data})
 }
 
@@ -228,9 +239,11 @@ func GetEnvelope(c *gin.Context) {
 // @Success		200			{object}	EnvelopeResponse
 // @Failure		400			{object}	EnvelopeResponse
 // @Failure		404			{object}	EnvelopeResponse
+// @Failure		412			{object}	EnvelopeResponse
 // @Failure		500			{object}	EnvelopeResponse
 // @Param			id			path		URIID				true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
 // @Param			envelope	body		v4.EnvelopeEditable	true	"Envelope"
+// @Param			If-Match	header		string				false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/envelopes/{id} [patch]
 func UpdateEnvelope(c *gin.Context) {
 	var uri URIID
@@ -272,7 +285,14 @@ func UpdateEnvelope(c *gin.Context) {
 		return
 	}
 
-	err = models.DB.Model(&envelope).Select("", updateFields...).Updates(data.model()).Error
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &envelope)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&envelope).Select("", updateFields...).Updates(data.model()).Error
+	})
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), EnvelopeResponse{
@@ -282,18 +302,175 @@ func UpdateEnvelope(c *gin.Context) {
 	}
 
 	apiResource := newEnvelope(c, envelope)
+	c.Header("ETag", httputil.ETag(envelope.UpdatedAt))
 	c.JSON(http.StatusOK, EnvelopeResponse{Data: &apiResource})
 }
 
 // @Summary		Delete envelope
-// @Description	Deletes an envelope
+// @Description	Deletes an envelope and moves it to the trash together with its goals, month configs and transactions
 // @Tags			Envelopes
 // @Success		204
-// @Failure		400	{object}	httpError
-// @Failure		404	{object}	httpError
-// @Failure		500	{object}	httpError
-// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/envelopes/{id} [delete]
 func DeleteEnvelope(c *gin.Context) {
 	deleteResource[models.Envelope](c)
 }
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Envelopes
+// @Success		204
+// @Param			id	path	URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/envelopes/{id}/merge [options]
+func OptionsEnvelopeMerge(c *gin.Context) {
+	httputil.OptionsPost(c)
+}
+
+// @Summary		Merge envelopes
+// @Description	Merges other envelopes into this one. Their transactions and goals are moved to this envelope and their allocations are added to the ones of this envelope, then they are deleted.
+// @Tags			Envelopes
+// @Accept			json
+// @Produce		json
+// @Success		200		{object}	EnvelopeResponse
+// @Failure		400		{object}	EnvelopeResponse
+// @Failure		404		{object}	EnvelopeResponse
+// @Failure		500		{object}	EnvelopeResponse
+// @Param			id		path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			merge	body		EnvelopeMerge	true	"Envelopes to merge"
+// @Router			/v4/envelopes/{id}/merge [post]
+func MergeEnvelopes(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), EnvelopeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var data EnvelopeMerge
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), EnvelopeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var envelope models.Envelope
+	err = models.DB.First(&envelope, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), EnvelopeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err = envelope.Merge(models.DB.WithContext(c), data.EnvelopeIDs)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), EnvelopeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	apiResource := newEnvelope(c, envelope)
+	c.JSON(http.StatusOK, EnvelopeResponse{Data: &apiResource})
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Envelopes
+// @Success		204
+// @Param			id	path	URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/envelopes/{id}/history [options]
+func OptionsEnvelopeHistory(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Get envelope history
+// @Description	Returns everything that changed the balance of the envelope in chronological order: rollovers of the previous month's balance, resets of negative balances, allocations and transactions. Each entry contains the balance after it.
+// @Tags			Envelopes
+// @Produce		json
+// @Success		200	{object}	EnvelopeHistoryResponse
+// @Success		304
+// @Failure		400				{object}	EnvelopeHistoryResponse
+// @Failure		404				{object}	EnvelopeHistoryResponse
+// @Failure		500				{object}	EnvelopeHistoryResponse
+// @Param			id				path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			from			query		string	true	"The first month in YYYY-MM format"
+// @Param			until			query		string	true	"The last month in YYYY-MM format"
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/envelopes/{id}/history [get]
+func GetEnvelopeHistory(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), EnvelopeHistoryResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var query EnvelopeHistoryQuery
+	if err := c.BindQuery(&query); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, EnvelopeHistoryResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if query.From.IsZero() || query.Until.IsZero() {
+		s := errMonthRangeNotSet.Error()
+		c.JSON(http.StatusBadRequest, EnvelopeHistoryResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	from, until := types.MonthOf(query.From), types.MonthOf(query.Until)
+	if until.Before(from) {
+		s := errMonthRangeInvalid.Error()
+		c.JSON(http.StatusBadRequest, EnvelopeHistoryResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var envelope models.Envelope
+	err = models.DB.First(&envelope, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), EnvelopeHistoryResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	entries, err := envelope.History(models.DB, from, until)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), EnvelopeHistoryResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := make([]EnvelopeHistoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		data = append(data, newEnvelopeHistoryEntry(c, entry))
+	}
+
+	c.JSON(http.StatusOK, EnvelopeHistoryResponse{Data: data})
+}
@@ -5,11 +5,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
 	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
 	"github.com/envelope-zero/backend/v7/test"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -451,3 +454,152 @@ func (suite *TestSuiteStandard) TestEnvelopesPagination() {
 		})
 	}
 }
+
+// TestEnvelopesMerge verifies that merging envelopes moves their transactions, goals and allocations.
+func (suite *TestSuiteStandard) TestEnvelopesMerge() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Groceries"})
+	duplicate := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Food"})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	payee := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+
+	month := types.NewMonth(2024, 3)
+	_ = patchTestMonthConfig(suite.T(), envelope.Data.ID, month, v4.MonthConfigEditable{Allocation: decimal.NewFromFloat(40)})
+	_ = patchTestMonthConfig(suite.T(), duplicate.Data.ID, month, v4.MonthConfigEditable{Allocation: decimal.NewFromFloat(25)})
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: account.Data.ID, DestinationAccountID: payee.Data.ID, EnvelopeID: &duplicate.Data.ID, Amount: decimal.NewFromFloat(12)})
+	goal := createTestGoal(suite.T(), v4.GoalEditable{EnvelopeID: duplicate.Data.ID, Name: "Party", Amount: decimal.NewFromFloat(100)})
+
+	otherBudget := createTestEnvelope(suite.T(), v4.EnvelopeEditable{})
+
+	tests := []struct {
+		name   string
+		ids    []uuid.UUID
+		status int
+	}{
+		{"Itself", []uuid.UUID{envelope.Data.ID}, http.StatusBadRequest},
+		{"Other budget", []uuid.UUID{otherBudget.Data.ID}, http.StatusBadRequest},
+		{"Not found", []uuid.UUID{uuid.New()}, http.StatusNotFound},
+		{"Duplicate", []uuid.UUID{duplicate.Data.ID}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, envelope.Data.Links.Merge, v4.EnvelopeMerge{EnvelopeIDs: tt.ids})
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+
+	recorder := test.Request(suite.T(), http.MethodOptions, envelope.Data.Links.Merge, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, POST", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodGet, transaction.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var mergedTransaction v4.TransactionResponse
+	test.DecodeResponse(suite.T(), &recorder, &mergedTransaction)
+	assert.Equal(suite.T(), envelope.Data.ID, *mergedTransaction.Data.EnvelopeID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, goal.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var mergedGoal v4.GoalResponse
+	test.DecodeResponse(suite.T(), &recorder, &mergedGoal)
+	assert.Equal(suite.T(), envelope.Data.ID, mergedGoal.Data.EnvelopeID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/envelopes/%s/%s", envelope.Data.ID, month), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var monthConfig v4.MonthConfigResponse
+	test.DecodeResponse(suite.T(), &recorder, &monthConfig)
+	assert.True(suite.T(), decimal.NewFromFloat(65).Equal(monthConfig.Data.Allocation), "Allocation is %s", monthConfig.Data.Allocation)
+
+	recorder = test.Request(suite.T(), http.MethodGet, duplicate.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+}
+
+// TestEnvelopesMergeFails verifies that merging into an envelope fails for invalid requests.
+func (suite *TestSuiteStandard) TestEnvelopesMergeFails() {
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{})
+
+	tests := []struct {
+		name   string
+		path   string
+		body   any
+		status int
+	}{
+		{"Not a UUID", "http://example.com/v4/envelopes/notauuid/merge", v4.EnvelopeMerge{}, http.StatusBadRequest},
+		{"Not found", "http://example.com/v4/envelopes/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/merge", v4.EnvelopeMerge{}, http.StatusNotFound},
+		{"Broken body", envelope.Data.Links.Merge, `{ "envelopeIds": 2 }`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, tt.path, tt.body)
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
+
+// TestEnvelopesHistory verifies that the history of an envelope explains its balance.
+func (suite *TestSuiteStandard) TestEnvelopesHistory() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID}).Data.ID})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+
+	_ = patchTestMonthConfig(suite.T(), envelope.Data.ID, types.NewMonth(2024, 1), v4.MonthConfigEditable{Allocation: decimal.NewFromFloat(20)})
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{
+		Date:                 time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		SourceAccountID:      account.Data.ID,
+		DestinationAccountID: shop.Data.ID,
+		EnvelopeID:           &envelope.Data.ID,
+		Amount:               decimal.NewFromFloat(25),
+	})
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("%s?from=2024-01&until=2024-02", envelope.Data.Links.History), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.EnvelopeHistoryResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	suite.Require().Len(response.Data, 3)
+
+	assert.Equal(suite.T(), models.EnvelopeHistoryAllocation, response.Data[0].Type)
+	assert.Equal(suite.T(), models.EnvelopeHistoryTransaction, response.Data[1].Type)
+	assert.Equal(suite.T(), transaction.Data.Links.Self, response.Data[1].Links.Transaction)
+	assert.True(suite.T(), decimal.NewFromFloat(-5).Equal(response.Data[1].Balance), "Balance is %s", response.Data[1].Balance)
+	assert.Equal(suite.T(), models.EnvelopeHistoryReset, response.Data[2].Type)
+	assert.True(suite.T(), decimal.Zero.Equal(response.Data[2].Balance), "Balance is %s", response.Data[2].Balance)
+
+	recorder = test.Request(suite.T(), http.MethodOptions, envelope.Data.Links.History, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET", recorder.Header().Get("allow"))
+}
+
+func (suite *TestSuiteStandard) TestEnvelopesHistoryFails() {
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{})
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"Not a UUID", "http://example.com/v4/envelopes/notauuid/history?from=2024-01&until=2024-02", http.StatusBadRequest},
+		{"Not found", "http://example.com/v4/envelopes/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/history?from=2024-01&until=2024-02", http.StatusNotFound},
+		{"No months", envelope.Data.Links.History, http.StatusBadRequest},
+		{"Invalid month", fmt.Sprintf("%s?from=January&until=2024-02", envelope.Data.Links.History), http.StatusBadRequest},
+		{"Until before from", fmt.Sprintf("%s?from=2024-03&until=2024-02", envelope.Data.Links.History), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, tt.path, "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.EnvelopeHistoryResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
@@ -2,11 +2,13 @@ package v4
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/envelope-zero/backend/v7/internal/models"
 	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 // EnvelopeEditable represents all user configurable parameters
@@ -31,6 +33,8 @@ type EnvelopeLinks struct {
 	Self         string `json:"self" example:"https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166"`                     // The envelope itself
 	Transactions string `json:"transactions" example:"https://example.com/api/v4/transactions?envelope=45b6b5b9-f746-4ae9-b77b-7688b91f8166"` // The envelope's transactions
 	Month        string `json:"month" example:"https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166/YYYY-MM"`            // The MonthConfig for the envelope
+	Merge        string `json:"merge" example:"https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166/merge"`              // Endpoint to merge other envelopes into this one
+	History      string `json:"history" example:"https://example.com/api/v4/envelopes/45b6b5b9-f746-4ae9-b77b-7688b91f8166/history"`          // The history of the envelope's balance
 }
 
 type Envelope struct {
@@ -54,6 +58,8 @@ func newEnvelope(c *gin.Context, model models.Envelope) Envelope {
 			Self:         fmt.Sprintf("%s/v4/envelopes/%s", url, model.ID),
 			Transactions: fmt.Sprintf("%s/v4/transactions?envelope=%s", url, model.ID),
 			Month:        fmt.Sprintf("%s/v4/envelopes/%s/YYYY-MM", url, model.ID),
+			Merge:        fmt.Sprintf("%s/v4/envelopes/%s/merge", url, model.ID),
+			History:      fmt.Sprintf("%s/v4/envelopes/%s/history", url, model.ID),
 		},
 	}
 }
@@ -97,6 +103,18 @@ type EnvelopeQueryFilter struct {
 	Search     string       `form:"search" filterField:"false"` // By string in name or note
 	Offset     uint         `form:"offset" filterField:"false"` // The offset of the first Envelope returned. Defaults to 0.
 	Limit      int          `form:"limit" filterField:"false"`  // Maximum number of Envelopes to return. Defaults to 50.
+	Sort       string       `form:"sort" filterField:"false"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor     string       `form:"cursor" filterField:"false"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// envelopeSorting defines how lists of envelopes can be sorted
+var envelopeSorting = listSorting{
+	table: "envelopes",
+	fields: map[string]string{
+		"name":      "envelopes.name",
+		"createdAt": "envelopes.created_at",
+	},
+	defaults: "name",
 }
 
 func (f EnvelopeQueryFilter) model() (models.Envelope, error) {
@@ -105,3 +123,51 @@ func (f EnvelopeQueryFilter) model() (models.Envelope, error) {
 		Archived:   f.Archived,
 	}, nil
 }
+
+type EnvelopeMerge struct {
+	EnvelopeIDs []uuid.UUID `json:"envelopeIds" example:"4c7a0d3e-5d0c-4d8e-9b6c-2f3a1e0d9c8b"` // IDs of the envelopes to merge into this envelope. They are deleted after their transactions, goals and allocations have been moved
+}
+
+type EnvelopeHistoryQuery struct {
+	From  time.Time `form:"from" time_format:"2006-01" time_utc:"1" example:"2024-01"`  // First month in YYYY-MM format
+	Until time.Time `form:"until" time_format:"2006-01" time_utc:"1" example:"2024-12"` // Last month in YYYY-MM format
+}
+
+// EnvelopeHistoryEntry is a change to the balance of an envelope.
+type EnvelopeHistoryEntry struct {
+	Type          models.EnvelopeHistoryType `json:"type" example:"TRANSACTION"`                                   // The kind of change. One of ROLLOVER, RESET, ALLOCATION or TRANSACTION
+	Date          time.Time                  `json:"date" example:"2024-01-15T00:00:00Z"`                          // Date of the change. For all entries except transactions, this is the first of the month
+	TransactionID *uuid.UUID                 `json:"transactionId" example:"3b1ebf0d-5a0b-4b8c-9a33-1ef15c0c4c2e"` // ID of the transaction for transaction entries
+	Note          string                     `json:"note" example:"Weekly groceries"`                              // Note of the transaction or month config
+	Amount        decimal.Decimal            `json:"amount" example:"-32.17"`                                      // Change of the balance in the currency of the budget. For rollovers, the amount carried over
+	Balance       decimal.Decimal            `json:"balance" example:"67.83"`                                      // Balance of the envelope after the change
+	Links         EnvelopeHistoryEntryLinks  `json:"links"`                                                        // Links to related resources
+}
+
+type EnvelopeHistoryEntryLinks struct {
+	Transaction string `json:"transaction" example:"https://example.com/api/v4/transactions/3b1ebf0d-5a0b-4b8c-9a33-1ef15c0c4c2e"` // The transaction for transaction entries
+}
+
+func newEnvelopeHistoryEntry(c *gin.Context, entry models.EnvelopeHistoryEntry) EnvelopeHistoryEntry {
+	url := c.GetString(string(models.DBContextURL))
+
+	e := EnvelopeHistoryEntry{
+		Type:          entry.Type,
+		Date:          entry.Date,
+		TransactionID: entry.TransactionID,
+		Note:          entry.Note,
+		Amount:        entry.Amount,
+		Balance:       entry.Balance,
+	}
+
+	if entry.TransactionID != nil {
+		e.Links.Transaction = fmt.Sprintf("%s/v4/transactions/%s", url, entry.TransactionID)
+	}
+
+	return e
+}
+
+type EnvelopeHistoryResponse struct {
+	Data  []EnvelopeHistoryEntry `json:"data"`                                                          // History entries, earliest first
+	Error *string                `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
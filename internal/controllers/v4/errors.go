@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/envelope-zero/backend/v7/internal/httputil"
 	"github.com/envelope-zero/backend/v7/internal/models"
 )
 
@@ -21,12 +22,36 @@ func status(err error) int {
 		return http.StatusNotFound
 	}
 
+	if errors.Is(err, httputil.ErrPreconditionFailed) {
+		return http.StatusPreconditionFailed
+	}
+
 	return http.StatusBadRequest
 }
 
 var (
 	errAccountIDParameter = errors.New("the accountId parameter must be set")
+	errBudgetIDParameter  = errors.New("the budget parameter must be set")
 	errMonthNotSetInQuery = errors.New("the month query parameter must be set")
+	errMonthRangeNotSet   = errors.New("the from and until query parameters must be set")
+	errMonthRangeInvalid  = errors.New("the until month must not be before the from month")
+	errMonthNotSet        = errors.New("the month must be set")
+)
+
+// Account errors
+var (
+	errRegisterCursorInvalid = errors.New("the cursor is invalid")
+	errRegisterLimitInvalid  = errors.New("the limit must be positive")
+)
+
+// Audit errors
+var (
+	errAuditActionInvalid = errors.New("the action must be one of CREATE, UPDATE or DELETE")
+)
+
+// Budget errors
+var (
+	errBudgetCloneModeInvalid = errors.New("the clone mode must be one of STRUCTURE, STRUCTURE_GOALS_MATCH_RULES or ALL")
 )
 
 // Cleanup errors
@@ -34,16 +59,67 @@ var (
 	errCleanupConfirmation = errors.New("the confirmation for the cleanup API call was incorrect")
 )
 
+// Event errors
+var (
+	errEventIDInvalid = errors.New("the last event ID must be a non-negative integer")
+)
+
+// Export errors
+var (
+	errExportFormatInvalid = errors.New("the export format must be one of json, csv, xlsx or archive")
+)
+
 // Import errors
 var (
-	errNoFilePost       = errors.New("you must send a file to this endpoint")
-	errWrongFileSuffix  = errors.New("this endpoint only supports files of the following types")
-	errBudgetNameInUse  = errors.New("this budget name is already in use. Imports from YNAB 4 create a new budget, therefore the name needs to be unique")
-	errBudgetNameNotSet = errors.New("the budgetName parameter must be set")
+	errNoFilePost         = errors.New("you must send a file to this endpoint")
+	errWrongFileSuffix    = errors.New("this endpoint only supports files of the following types")
+	errFileTooLarge       = errors.New("the file is larger than the maximum size")
+	errFileTypeNotAllowed = errors.New("the file type is not supported, supported types are")
+	errBudgetNameInUse    = errors.New("this budget name is already in use. Imports from YNAB 4 create a new budget, therefore the name needs to be unique")
+	errBudgetNameNotSet   = errors.New("the budgetName parameter must be set")
+)
+
+// Exchange rate errors
+var (
+	errExchangeRateImportBudget  = errors.New("the budget parameter must be set to a budget ID")
+	errExchangeRateImportColumn  = errors.New("the CSV file is missing a column")
+	errExchangeRateImportInvalid = errors.New("the CSV file is invalid")
+)
+
+// Month errors
+var (
+	errMonthTransferAvailable = errors.New("the move would make the amount available to budget negative. Set force to move the money anyway")
+	errMonthTransferBalance   = errors.New("the move would make the balance of the source envelope negative. Set force to move the money anyway")
+)
+
+// Pagination errors
+var (
+	errPaginationCursorInvalid = errors.New("the cursor is invalid for this list")
+	errPaginationOffsetCursor  = errors.New("offset and cursor cannot be used together")
+	errPaginationSortInvalid   = errors.New("the sort parameter is invalid")
+)
+
+// Search errors
+var (
+	errSearchQueryNotSet  = errors.New("the q parameter must be set")
+	errSearchLimitInvalid = errors.New("the limit must be positive")
 )
 
 // Transaction errors
 var (
 	errTransactionDirectionInvalid = errors.New("the specified transaction direction is invalid")
 	errTransactionTypeInvalid      = errors.New("the specified transaction type is invalid")
+	errTransactionBulkNoSelection  = errors.New("either transaction IDs or at least one filter must be set")
+	errTransactionTagsInvalid      = errors.New("the tags parameter must be a comma separated list of tag IDs")
+	errTransactionTagMatchInvalid  = errors.New("the tagMatch parameter must be one of ANY or ALL")
+)
+
+// Report errors
+var (
+	errPayeeReportSortInvalid = errors.New("the specified sort order for the payee report is invalid")
+)
+
+// Webhook errors
+var (
+	errWebhookDeliveryStatusInvalid = errors.New("the status must be one of PENDING, SUCCEEDED or FAILED")
 )
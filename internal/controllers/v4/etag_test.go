@@ -0,0 +1,182 @@
+package v4_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestETagIfMatch verifies that updates and deletions with an outdated ETag fail.
+func (suite *TestSuiteStandard) TestETagIfMatch() {
+	category := createTestCategory(suite.T(), v4.CategoryEditable{Name: "Groceries"})
+
+	recorder := test.Request(suite.T(), http.MethodGet, category.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	etag := recorder.Header().Get("ETag")
+	require.NotEmpty(suite.T(), etag)
+
+	// Another client updates the category
+	recorder = test.Request(suite.T(), http.MethodPatch, category.Data.Links.Self, map[string]any{"note": "Weekly shopping"}, map[string]string{"If-Match": etag})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	updated := recorder.Header().Get("ETag")
+	assert.NotEqual(suite.T(), etag, updated, "Updates must change the ETag")
+
+	recorder = test.Request(suite.T(), http.MethodGet, category.Data.Links.Self, "")
+	assert.Equal(suite.T(), updated, recorder.Header().Get("ETag"), "Updates must return the new ETag")
+
+	// The outdated ETag does not match anymore
+	recorder = test.Request(suite.T(), http.MethodPatch, category.Data.Links.Self, map[string]any{"note": "Overwritten"}, map[string]string{"If-Match": etag})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusPreconditionFailed)
+
+	var response v4.CategoryResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	assert.Equal(suite.T(), httputil.ErrPreconditionFailed.Error(), *response.Error)
+
+	recorder = test.Request(suite.T(), http.MethodDelete, category.Data.Links.Self, "", map[string]string{"If-Match": etag})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusPreconditionFailed)
+
+	recorder = test.Request(suite.T(), http.MethodGet, category.Data.Links.Self, "")
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	assert.Equal(suite.T(), "Weekly shopping", response.Data.Note)
+
+	recorder = test.Request(suite.T(), http.MethodDelete, category.Data.Links.Self, "", map[string]string{"If-Match": updated})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+}
+
+// TestETagResources verifies that all single-resource GET endpoints return an ETag
+// and that their PATCH and DELETE endpoints check it.
+func (suite *TestSuiteStandard) TestETagResources() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Checking", OnBudget: true})
+	payee := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Grocery Store", External: true})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID})
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{
+		Amount:               decimal.NewFromFloat(10),
+		SourceAccountID:      account.Data.ID,
+		DestinationAccountID: payee.Data.ID,
+		EnvelopeID:           &envelope.Data.ID,
+	})
+	tag := createTestTag(suite.T(), v4.TagEditable{BudgetID: budget.Data.ID})
+	webhook := createTestWebhook(suite.T(), v4.WebhookEditable{Events: []models.WebhookEvent{"transaction.created"}})
+
+	tests := []struct {
+		name  string
+		url   string
+		patch map[string]any
+	}{
+		{"Account", account.Data.Links.Self, map[string]any{"note": "Updated"}},
+		{"Budget", budget.Data.Links.Self, map[string]any{"note": "Updated"}},
+		{"Envelope", envelope.Data.Links.Self, map[string]any{"note": "Updated"}},
+		{"Payee", fmt.Sprintf("http://example.com/v4/payees/%s", payee.Data.ID), map[string]any{"note": "Updated"}},
+		{"Tag", tag.Data.Links.Self, map[string]any{"note": "Updated"}},
+		{"Transaction", transaction.Data.Links.Self, map[string]any{"note": "Updated"}},
+		{"Webhook", webhook.Data.Links.Self, map[string]any{"url": "https://example.com/updated"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, tt.url, "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+			etag := recorder.Header().Get("ETag")
+			require.NotEmpty(t, etag)
+
+			recorder = test.Request(t, http.MethodPatch, tt.url, tt.patch, map[string]string{"If-Match": `"outdated"`})
+			test.AssertHTTPStatus(t, &recorder, http.StatusPreconditionFailed)
+
+			recorder = test.Request(t, http.MethodPatch, tt.url, tt.patch, map[string]string{"If-Match": etag})
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+			recorder = test.Request(t, http.MethodDelete, tt.url, "", map[string]string{"If-Match": etag})
+			test.AssertHTTPStatus(t, &recorder, http.StatusPreconditionFailed)
+		})
+	}
+}
+
+// TestETagTransactionTags verifies that changing only the tags of a transaction changes its ETag.
+func (suite *TestSuiteStandard) TestETagTransactionTags() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	tag := createTestTag(suite.T(), v4.TagEditable{BudgetID: budget.Data.ID})
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{
+		Amount:               decimal.NewFromFloat(10),
+		SourceAccountID:      createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Checking", OnBudget: true}).Data.ID,
+		DestinationAccountID: createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Grocery Store", External: true}).Data.ID,
+	})
+
+	recorder := test.Request(suite.T(), http.MethodGet, transaction.Data.Links.Self, "")
+	etag := recorder.Header().Get("ETag")
+
+	recorder = test.Request(suite.T(), http.MethodPatch, transaction.Data.Links.Self, map[string]any{"tagIds": []uuid.UUID{tag.Data.ID}}, map[string]string{"If-Match": etag})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	assert.NotEqual(suite.T(), etag, recorder.Header().Get("ETag"))
+
+	recorder = test.Request(suite.T(), http.MethodPatch, transaction.Data.Links.Self, map[string]any{"tagIds": []uuid.UUID{}}, map[string]string{"If-Match": etag})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusPreconditionFailed)
+}
+
+// TestETagMonthConfig verifies that month configs have an ETag once they exist.
+func (suite *TestSuiteStandard) TestETagMonthConfig() {
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{})
+	url := fmt.Sprintf("http://example.com/v4/envelopes/%s/%s", envelope.Data.ID, types.NewMonth(2024, 2))
+
+	recorder := test.Request(suite.T(), http.MethodGet, url, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	assert.Empty(suite.T(), recorder.Header().Get("ETag"), "Month configs that do not exist have no ETag")
+
+	recorder = test.Request(suite.T(), http.MethodPatch, url, map[string]any{"allocation": 20})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	etag := recorder.Header().Get("ETag")
+	require.NotEmpty(suite.T(), etag)
+
+	recorder = test.Request(suite.T(), http.MethodGet, url, "")
+	assert.Equal(suite.T(), etag, recorder.Header().Get("ETag"))
+
+	recorder = test.Request(suite.T(), http.MethodPatch, url, map[string]any{"allocation": 30}, map[string]string{"If-Match": etag})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	recorder = test.Request(suite.T(), http.MethodPatch, url, map[string]any{"allocation": 40}, map[string]string{"If-Match": etag})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusPreconditionFailed)
+}
+
+// TestETagIfNoneMatch verifies that lists and months are not sent again when they have not changed.
+func (suite *TestSuiteStandard) TestETagIfNoneMatch() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID})
+
+	urls := []string{
+		fmt.Sprintf("http://example.com/v4/categories?budget=%s", budget.Data.ID),
+		fmt.Sprintf("http://example.com/v4/months?budget=%s&month=2024-01", budget.Data.ID),
+	}
+
+	etags := make([]string, 0, len(urls))
+	for _, url := range urls {
+		recorder := test.Request(suite.T(), http.MethodGet, url, "")
+		test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+		etag := recorder.Header().Get("ETag")
+		require.NotEmpty(suite.T(), etag, url)
+		etags = append(etags, etag)
+
+		recorder = test.Request(suite.T(), http.MethodGet, url, "", map[string]string{"If-None-Match": etag})
+		test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotModified)
+		assert.Empty(suite.T(), recorder.Body.String(), url)
+	}
+
+	// Changed data is sent again
+	_ = createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID})
+
+	for i, url := range urls {
+		recorder := test.Request(suite.T(), http.MethodGet, url, "", map[string]string{"If-None-Match": etags[i]})
+		test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+		assert.NotEqual(suite.T(), etags[i], recorder.Header().Get("ETag"), url)
+	}
+}
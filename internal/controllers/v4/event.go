@@ -0,0 +1,169 @@
+package v4
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// eventsBatchSize is the maximum number of events read at once.
+const eventsBatchSize = 100
+
+var (
+	eventStreamsStop     = make(chan struct{})
+	eventStreamsStopOnce sync.Once
+)
+
+// StopEventStreams ends all open event streams. Streams are kept open until the
+// client disconnects otherwise, which blocks a graceful shutdown of the server.
+func StopEventStreams() {
+	eventStreamsStopOnce.Do(func() {
+		close(eventStreamsStop)
+	})
+}
+
+// RegisterEventRoutes registers the routes for the event stream with
+// the RouterGroup that is passed.
+func RegisterEventRoutes(r *gin.RouterGroup) {
+	{
+		r.OPTIONS("", OptionsEvents)
+		r.GET("", GetEvents)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Events
+// @Success		204
+// @Router			/v4/events [options]
+func OptionsEvents(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Event stream
+// @Description	Streams changes to accounts, envelopes, month configs and transactions of a budget as Server-Sent Events.
+// @Description	The name of each event is the kind of change, e.g. "transaction.created", its data describes the change and its ID is the position to resume from.
+// @Description	Streams resume after the event in the Last-Event-ID header or lastEventId parameter. Without it, the stream starts with a "ready" event.
+// @Description	If events to resume from are no longer available, the stream starts with a "reset" event and clients need to reload all data.
+// @Tags			Events
+// @Produce		text/event-stream
+// @Success		200				{object}	models.BudgetEventData
+// @Failure		400				{object}	httpError
+// @Failure		404				{object}	httpError
+// @Failure		500				{object}	httpError
+// @Param			budget			query		string	true	"ID of the budget"
+// @Param			lastEventId		query		string	false	"ID of the last received event. Ignored if the Last-Event-ID header is set"
+// @Param			Last-Event-ID	header		string	false	"ID of the last received event"
+// @Router			/v4/events [get]
+func GetEvents(c *gin.Context) {
+	var filter EventQueryFilter
+	if err := c.BindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if filter.BudgetID == ez_uuid.Nil {
+		c.JSON(http.StatusBadRequest, httpError{
+			Error: errBudgetIDParameter.Error(),
+		})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = filter.LastEventID
+	}
+
+	var last uint64
+	if lastEventID != "" {
+		var err error
+		last, err = strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpError{
+				Error: errEventIDInvalid.Error(),
+			})
+			return
+		}
+	}
+
+	err := models.DB.First(&models.Budget{}, filter.BudgetID.UUID).Error
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	oldest, latest, err := models.BudgetEventSequences(models.DB)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	switch {
+	case lastEventID == "":
+		renderEvent(c, eventReady, latest, "{}")
+		last = latest
+
+	// Events are missing if they were removed from the buffer or if
+	// the ID is from before all events were deleted
+	case last > latest || oldest > last+1:
+		renderEvent(c, eventReset, latest, "{}")
+		last = latest
+	}
+	c.Writer.Flush()
+
+	// Events are only read from the database when the stream starts or resumes
+	// and when new events of the budget have been written
+	for {
+		written := models.BudgetEventsWritten(filter.BudgetID.UUID)
+
+		events, err := models.BudgetEvents(models.DB, filter.BudgetID.UUID, last, eventsBatchSize)
+		if err != nil {
+			log.Error().Str("budget", filter.BudgetID.String()).Err(err).Msg("Reading events failed")
+			return
+		}
+
+		for _, event := range events {
+			renderEvent(c, event.Type, event.Sequence, event.Data)
+			last = event.Sequence
+		}
+		c.Writer.Flush()
+
+		// Read the next batch right away if there are more events
+		if len(events) == eventsBatchSize {
+			continue
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-eventStreamsStop:
+			return
+		case <-written:
+		}
+	}
+}
+
+// renderEvent writes an event to the stream.
+func renderEvent(c *gin.Context, name string, id uint64, data string) {
+	c.Render(-1, sse.Event{
+		Event: name,
+		Id:    strconv.FormatUint(id, 10),
+		Data:  data,
+	})
+}
@@ -0,0 +1,161 @@
+package v4_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamEvent is an event read from an event stream.
+type streamEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// streamEvents requests the event stream for a short time and returns the events it sent.
+func streamEvents(t *testing.T, url string, headers ...map[string]string) (httptest.ResponseRecorder, []streamEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	recorder := test.RequestContext(t, ctx, http.MethodGet, url, "", headers...)
+
+	var events []streamEvent
+	for _, block := range strings.Split(recorder.Body.String(), "\n\n") {
+		var event streamEvent
+		for _, line := range strings.Split(block, "\n") {
+			field, value, _ := strings.Cut(line, ":")
+			switch field {
+			case "id":
+				event.ID = value
+			case "event":
+				event.Event = value
+			case "data":
+				event.Data = value
+			}
+		}
+
+		if event.Event != "" {
+			events = append(events, event)
+		}
+	}
+
+	return recorder, events
+}
+
+func (suite *TestSuiteStandard) TestEvents() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Checking", OnBudget: true})
+	external := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Grocery Store", External: true})
+
+	url := fmt.Sprintf("http://example.com/v4/events?budget=%s", budget.Data.ID)
+
+	// Without an event ID, only new events are sent
+	recorder, events := streamEvents(suite.T(), url)
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	assert.Contains(suite.T(), recorder.Header().Get("Content-Type"), "text/event-stream")
+	require.Len(suite.T(), events, 1)
+	assert.Equal(suite.T(), "ready", events[0].Event)
+	ready := events[0].ID
+
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{
+		Amount:               decimal.NewFromFloat(12),
+		SourceAccountID:      account.Data.ID,
+		DestinationAccountID: external.Data.ID,
+	})
+
+	// Events of other budgets are not sent
+	other := createTestBudget(suite.T(), v4.BudgetEditable{})
+	_ = createTestAccount(suite.T(), v4.AccountEditable{BudgetID: other.Data.ID})
+
+	_, events = streamEvents(suite.T(), url, map[string]string{"Last-Event-ID": ready})
+	require.Len(suite.T(), events, 1)
+	assert.Equal(suite.T(), "transaction.created", events[0].Event)
+	assert.Contains(suite.T(), events[0].Data, transaction.Data.ID.String())
+
+	// Streams resume from the query parameter, too
+	_, events = streamEvents(suite.T(), fmt.Sprintf("%s&lastEventId=0", url))
+	require.Len(suite.T(), events, 3)
+	assert.Equal(suite.T(), "account.created", events[0].Event)
+	assert.Equal(suite.T(), "account.created", events[1].Event)
+	assert.Equal(suite.T(), "transaction.created", events[2].Event)
+
+	// The header takes precedence
+	_, events = streamEvents(suite.T(), fmt.Sprintf("%s&lastEventId=0", url), map[string]string{"Last-Event-ID": events[1].ID})
+	require.Len(suite.T(), events, 1)
+	assert.Equal(suite.T(), "transaction.created", events[0].Event)
+
+	// Events after the latest one cannot be resumed from
+	_, events = streamEvents(suite.T(), url, map[string]string{"Last-Event-ID": "1000000"})
+	require.Len(suite.T(), events, 1)
+	assert.Equal(suite.T(), "reset", events[0].Event)
+}
+
+// TestEventsWritten verifies that events are sent to open streams as soon as they are written.
+func (suite *TestSuiteStandard) TestEventsWritten() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	url := fmt.Sprintf("http://example.com/v4/events?budget=%s", budget.Data.ID)
+
+	_, events := streamEvents(suite.T(), url)
+	require.Len(suite.T(), events, 1)
+	ready := events[0].ID
+
+	streamed := make(chan []streamEvent)
+	go func() {
+		_, events := streamEvents(suite.T(), url, map[string]string{"Last-Event-ID": ready})
+		streamed <- events
+	}()
+
+	// The account is created after the stream has read the existing events
+	time.Sleep(20 * time.Millisecond)
+	account := models.Account{BudgetID: budget.Data.ID}
+	require.Nil(suite.T(), models.DB.Create(&account).Error)
+
+	events = <-streamed
+	require.Len(suite.T(), events, 1)
+	assert.Equal(suite.T(), "account.created", events[0].Event)
+	assert.Contains(suite.T(), events[0].Data, account.ID.String())
+}
+
+func (suite *TestSuiteStandard) TestEventsFails() {
+	tests := []struct {
+		name    string
+		query   string
+		headers map[string]string
+		status  int
+	}{
+		{"No budget", "", nil, http.StatusBadRequest},
+		{"Budget not a UUID", "budget=notauuid", nil, http.StatusBadRequest},
+		{"Budget not found", fmt.Sprintf("budget=%s", uuid.New()), nil, http.StatusNotFound},
+		{"Event ID invalid", fmt.Sprintf("budget=%s&lastEventId=-1", uuid.New()), nil, http.StatusBadRequest},
+		{"Event ID header invalid", fmt.Sprintf("budget=%s", uuid.New()), map[string]string{"Last-Event-ID": "latest"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			var headers []map[string]string
+			if tt.headers != nil {
+				headers = append(headers, tt.headers)
+			}
+
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/events?%s", tt.query), "", headers...)
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+
+	recorder := test.Request(suite.T(), http.MethodOptions, "http://example.com/v4/events", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET", recorder.Header().Get("allow"))
+}
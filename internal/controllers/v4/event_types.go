@@ -0,0 +1,16 @@
+package v4
+
+import (
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+)
+
+type EventQueryFilter struct {
+	BudgetID    ez_uuid.UUID `form:"budget"`      // ID of the budget
+	LastEventID string       `form:"lastEventId"` // ID of the last received event, for clients that cannot set the Last-Event-ID header
+}
+
+// Events sent in addition to the changes of resources.
+const (
+	eventReady = "ready" // Sent when a stream starts without resuming. Its ID is the position to resume from
+	eventReset = "reset" // Sent when a stream cannot be resumed since events were removed from the buffer. Clients need to reload all data
+)
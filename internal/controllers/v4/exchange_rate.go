@@ -0,0 +1,420 @@
+package v4
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+func RegisterExchangeRateRoutes(r *gin.RouterGroup) {
+	{
+		r.OPTIONS("", OptionsExchangeRates)
+		r.GET("", httputil.NotModified, GetExchangeRates)
+		r.POST("", CreateExchangeRates)
+		r.OPTIONS("/import", OptionsExchangeRateImport)
+		r.POST("/import", ImportExchangeRates)
+	}
+	{
+		r.OPTIONS("/:id", OptionsExchangeRateDetail)
+		r.GET("/:id", GetExchangeRate)
+		r.PATCH("/:id", UpdateExchangeRate)
+		r.DELETE("/:id", DeleteExchangeRate)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Exchange Rates
+// @Success		204
+// @Router			/v4/exchange-rates [options]
+func OptionsExchangeRates(c *gin.Context) {
+	httputil.OptionsGetPost(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Exchange Rates
+// @Success		204
+// @Router			/v4/exchange-rates/import [options]
+func OptionsExchangeRateImport(c *gin.Context) {
+	httputil.OptionsPost(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Exchange Rates
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/exchange-rates/{id} [options]
+func OptionsExchangeRateDetail(c *gin.Context) {
+	resourceOptionsDetail(c, models.ExchangeRate{})
+}
+
+// @Summary		Create exchange rates
+// @Description	Creates new exchange rates
+// @Tags			Exchange Rates
+// @Produce		json
+// @Success		201				{object}	ExchangeRateCreateResponse
+// @Failure		400				{object}	ExchangeRateCreateResponse
+// @Failure		404				{object}	ExchangeRateCreateResponse
+// @Failure		500				{object}	ExchangeRateCreateResponse
+// @Param			exchangeRates	body		[]ExchangeRateEditable	true	"Exchange rates"
+// @Router			/v4/exchange-rates [post]
+func CreateExchangeRates(c *gin.Context) {
+	var exchangeRates []ExchangeRateEditable
+
+	// Bind data and return error if not possible
+	err := httputil.BindData(c, &exchangeRates)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ExchangeRateCreateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// The final http status. Will be modified when errors occur
+	status := http.StatusCreated
+	r := ExchangeRateCreateResponse{}
+
+	for _, create := range exchangeRates {
+		exchangeRate := create.model()
+		err = models.DB.WithContext(c).Create(&exchangeRate).Error
+		if err != nil {
+			status = r.appendError(err, status)
+			continue
+		}
+
+		// Transform for the API and append
+		apiResource := newExchangeRate(c, exchangeRate)
+		r.Data = append(r.Data, ExchangeRateResponse{Data: &apiResource})
+	}
+
+	c.JSON(status, r)
+}
+
+// @Summary		Import exchange rates
+// @Description	Imports exchange rates from a CSV file with the columns currency, date (YYYY-MM-DD) and rate. The first line must be a header with the column names. Existing rates for the same currency and date are updated.
+// @Tags			Exchange Rates
+// @Accept			multipart/form-data
+// @Produce		json
+// @Success		201		{object}	ExchangeRateListResponse
+// @Failure		400		{object}	ExchangeRateListResponse
+// @Failure		404		{object}	ExchangeRateListResponse
+// @Failure		500		{object}	ExchangeRateListResponse
+// @Param			file	formData	file					true	"File to import"
+// @Param			budget	query		ExchangeRateImportQuery	false	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/exchange-rates/import [post]
+func ImportExchangeRates(c *gin.Context) {
+	var query ExchangeRateImportQuery
+	if err := c.BindQuery(&query); err != nil {
+		s := errExchangeRateImportBudget.Error()
+		c.JSON(http.StatusBadRequest, ExchangeRateListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	f, err := getUploadedFile(c, uploadLimits{suffix: ".csv"})
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), ExchangeRateListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	rates, err := parseExchangeRates(f, query.BudgetID.UUID)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), ExchangeRateListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	imported, err := models.ImportExchangeRates(models.DB.WithContext(c), rates)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), ExchangeRateListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := make([]ExchangeRate, 0, len(imported))
+	for _, rate := range imported {
+		data = append(data, newExchangeRate(c, rate))
+	}
+
+	c.JSON(http.StatusCreated, ExchangeRateListResponse{Data: data})
+}
+
+// parseExchangeRates reads exchange rates for a budget from a CSV file.
+//
+// The first line must contain the column names currency, date and rate in any order.
+func parseExchangeRates(r io.Reader, budgetID uuid.UUID) ([]models.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errExchangeRateImportInvalid, err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"currency", "date", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", errExchangeRateImportColumn, name)
+		}
+	}
+
+	rates := make([]models.ExchangeRate, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errExchangeRateImportInvalid, err)
+		}
+
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(record[columns["date"]]))
+		if err != nil {
+			return nil, fmt.Errorf("%w in line %d: %w", errExchangeRateImportInvalid, line, err)
+		}
+
+		rate, err := decimal.NewFromString(strings.TrimSpace(record[columns["rate"]]))
+		if err != nil {
+			return nil, fmt.Errorf("%w in line %d: %w", errExchangeRateImportInvalid, line, err)
+		}
+
+		rates = append(rates, models.ExchangeRate{
+			BudgetID: budgetID,
+			Currency: record[columns["currency"]],
+			Date:     date,
+			Rate:     rate,
+		})
+	}
+
+	return rates, nil
+}
+
+// @Summary		Get exchange rates
+// @Description	Returns a list of exchange rates, ordered by currency and latest date first
+// @Tags			Exchange Rates
+// @Produce		json
+// @Success		200	{object}	ExchangeRateListResponse
+// @Success		304
+// @Failure		400				{object}	ExchangeRateListResponse
+// @Failure		500				{object}	ExchangeRateListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/exchange-rates [get]
+// @Param			budget		query	string	false	"Filter by budget ID"
+// @Param			currency	query	string	false	"Filter by currency"
+// @Param			offset		query	uint	false	"The offset of the first exchange rate returned. Defaults to 0."
+// @Param			limit		query	int		false	"Maximum number of exchange rates to return. Defaults to 50."
+// @Param			sort		query	string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are currency, date, createdAt. Defaults to currency,-date"
+// @Param			cursor		query	string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
+func GetExchangeRates(c *gin.Context) {
+	var filter ExchangeRateQueryFilter
+
+	if err := c.Bind(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, ExchangeRateListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	queryFields, setFields := httputil.GetURLFields(c.Request.URL, filter)
+
+	where := filter.model()
+	q := models.DB.
+		Where(&where, queryFields...)
+
+	// Default to 50 exchange rates and set the limit
+	limit := 50
+	if slices.Contains(setFields, "Limit") {
+		limit = filter.Limit
+	}
+
+	page, err := newListPage(exchangeRateSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, ExchangeRateListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
+
+	var exchangeRates []models.ExchangeRate
+	err = q.Find(&exchangeRates).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), ExchangeRateListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	exchangeRates, pagination, err := paginate(c, q, page, exchangeRates)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ExchangeRateListResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Transform resources to their API representation
+	data := make([]ExchangeRate, 0, len(exchangeRates))
+	for _, exchangeRate := range exchangeRates {
+		data = append(data, newExchangeRate(c, exchangeRate))
+	}
+
+	c.JSON(http.StatusOK, ExchangeRateListResponse{
+		Data:       data,
+		Pagination: &pagination,
+	})
+}
+
+// @Summary		Get exchange rate
+// @Description	Returns a specific exchange rate
+// @Tags			Exchange Rates
+// @Produce		json
+// @Success		200	{object}	ExchangeRateResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
+// @Failure		400	{object}	ExchangeRateResponse
+// @Failure		404	{object}	ExchangeRateResponse
+// @Failure		500	{object}	ExchangeRateResponse
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/exchange-rates/{id} [get]
+func GetExchangeRate(c *gin.Context) {
+	exchangeRate, err := getExchangeRate(c)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ExchangeRateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	apiResource := newExchangeRate(c, exchangeRate)
+	c.Header("ETag", httputil.ETag(exchangeRate.UpdatedAt))
+	c.JSON(http.StatusOK, ExchangeRateResponse{Data: &apiResource})
+}
+
+// @Summary		Update exchange rate
+// @Description	Updates an exchange rate. Only values to be updated need to be specified.
+// @Tags			Exchange Rates
+// @Accept			json
+// @Produce		json
+// @Success		200				{object}	ExchangeRateResponse
+// @Failure		400				{object}	ExchangeRateResponse
+// @Failure		404				{object}	ExchangeRateResponse
+// @Failure		412				{object}	ExchangeRateResponse
+// @Failure		500				{object}	ExchangeRateResponse
+// @Param			id				path		URIID					true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			exchangeRate	body		ExchangeRateEditable	true	"Exchange rate"
+// @Param			If-Match		header		string					false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/exchange-rates/{id} [patch]
+func UpdateExchangeRate(c *gin.Context) {
+	exchangeRate, err := getExchangeRate(c)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ExchangeRateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Get the fields that are set to be updated
+	updateFields, err := httputil.GetBodyFields(c, ExchangeRateEditable{})
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ExchangeRateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Bind the data for the patch
+	var data ExchangeRateEditable
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ExchangeRateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &exchangeRate)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&exchangeRate).Select("", updateFields...).Updates(data.model()).Error
+	})
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ExchangeRateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	apiResource := newExchangeRate(c, exchangeRate)
+	c.Header("ETag", httputil.ETag(exchangeRate.UpdatedAt))
+	c.JSON(http.StatusOK, ExchangeRateResponse{Data: &apiResource})
+}
+
+// @Summary		Delete exchange rate
+// @Description	Deletes an exchange rate and moves it to the trash
+// @Tags			Exchange Rates
+// @Success		204
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/exchange-rates/{id} [delete]
+func DeleteExchangeRate(c *gin.Context) {
+	deleteResource[models.ExchangeRate](c)
+}
+
+// getExchangeRate returns the exchange rate for the ID in the URI.
+func getExchangeRate(c *gin.Context) (models.ExchangeRate, error) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	var exchangeRate models.ExchangeRate
+	err = models.DB.First(&exchangeRate, uri.ID).Error
+	return exchangeRate, err
+}
@@ -0,0 +1,195 @@
+package v4_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestExchangeRate(t *testing.T, c v4.ExchangeRateEditable, expectedStatus ...int) v4.ExchangeRateResponse {
+	if c.BudgetID == uuid.Nil {
+		c.BudgetID = createTestBudget(t, v4.BudgetEditable{Currency: "EUR"}).Data.ID
+	}
+
+	if c.Currency == "" {
+		c.Currency = "USD"
+	}
+
+	// Default to 201 Created as expected status
+	if len(expectedStatus) == 0 {
+		expectedStatus = append(expectedStatus, http.StatusCreated)
+	}
+
+	body := []v4.ExchangeRateEditable{c}
+	r := test.Request(t, http.MethodPost, "http://example.com/v4/exchange-rates", body)
+	test.AssertHTTPStatus(t, &r, expectedStatus...)
+
+	var res v4.ExchangeRateCreateResponse
+	test.DecodeResponse(t, &r, &res)
+
+	return res.Data[0]
+}
+
+// TestExchangeRates verifies creating, listing, updating and deleting exchange rates.
+func (suite *TestSuiteStandard) TestExchangeRates() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{Currency: "EUR"})
+
+	rate := createTestExchangeRate(suite.T(), v4.ExchangeRateEditable{
+		BudgetID: budget.Data.ID,
+		Currency: "usd",
+		Date:     time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC),
+		Rate:     decimal.NewFromFloat(0.92),
+	})
+	assert.Equal(suite.T(), "USD", rate.Data.Currency)
+	assert.Equal(suite.T(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), rate.Data.Date)
+
+	_ = createTestExchangeRate(suite.T(), v4.ExchangeRateEditable{BudgetID: budget.Data.ID, Currency: "GBP", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Rate: decimal.NewFromFloat(1.17)})
+	_ = createTestExchangeRate(suite.T(), v4.ExchangeRateEditable{BudgetID: budget.Data.ID, Currency: "USD", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Rate: decimal.NewFromFloat(0.93)}, http.StatusBadRequest)
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/exchange-rates?budget=%s&currency=usd", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var list v4.ExchangeRateListResponse
+	test.DecodeResponse(suite.T(), &recorder, &list)
+	require.Len(suite.T(), list.Data, 1)
+	assert.Equal(suite.T(), rate.Data.ID, list.Data[0].ID)
+	assert.Equal(suite.T(), int64(1), list.Pagination.Total)
+
+	recorder = test.Request(suite.T(), http.MethodPatch, rate.Data.Links.Self, map[string]any{"rate": 0.95, "date": "2024-04-01T12:00:00Z"})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var updated v4.ExchangeRateResponse
+	test.DecodeResponse(suite.T(), &recorder, &updated)
+	assert.True(suite.T(), decimal.NewFromFloat(0.95).Equal(updated.Data.Rate), "Rate is %s", updated.Data.Rate)
+	assert.Equal(suite.T(), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), updated.Data.Date)
+
+	recorder = test.Request(suite.T(), http.MethodPatch, rate.Data.Links.Self, map[string]any{"rate": 0})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+
+	recorder = test.Request(suite.T(), http.MethodOptions, rate.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET, PATCH, DELETE", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodDelete, rate.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, rate.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+}
+
+// TestExchangeRatesFails verifies that requests for exchange rates fail for invalid input.
+func (suite *TestSuiteStandard) TestExchangeRatesFails() {
+	_ = createTestExchangeRate(suite.T(), v4.ExchangeRateEditable{Currency: "Dollar", Rate: decimal.NewFromFloat(1)}, http.StatusBadRequest)
+	_ = createTestExchangeRate(suite.T(), v4.ExchangeRateEditable{BudgetID: uuid.New(), Rate: decimal.NewFromFloat(1)}, http.StatusNotFound)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"GET not a UUID", http.MethodGet, "/notauuid", http.StatusBadRequest},
+		{"GET not found", http.MethodGet, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"PATCH not found", http.MethodPatch, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"DELETE not found", http.MethodDelete, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"OPTIONS not found", http.MethodOptions, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"List invalid budget", http.MethodGet, "?budget=notauuid", http.StatusBadRequest},
+		{"Import without budget", http.MethodPost, "/import", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, tt.method, fmt.Sprintf("http://example.com/v4/exchange-rates%s", tt.path), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
+
+// TestExchangeRatesImport verifies the import of exchange rates from CSV files.
+func (suite *TestSuiteStandard) TestExchangeRatesImport() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{Currency: "EUR"})
+	path := fmt.Sprintf("http://example.com/v4/exchange-rates/import?budget=%s", budget.Data.ID)
+
+	tests := []struct {
+		name   string
+		file   string
+		status int
+		count  int
+	}{
+		{"Import", "exchange-rates/rates.csv", http.StatusCreated, 3},
+		{"Import again updates existing rates", "exchange-rates/rates.csv", http.StatusCreated, 3},
+		{"Missing column", "exchange-rates/missing-date.csv", http.StatusBadRequest, 0},
+		{"Invalid date", "exchange-rates/invalid-date.csv", http.StatusBadRequest, 0},
+		{"Wrong file type", "importer/Budget.yfull", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			body, headers := test.LoadTestFile(t, tt.file)
+			recorder := test.Request(t, http.MethodPost, path, body, headers)
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.ExchangeRateListResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.Len(t, response.Data, tt.count)
+		})
+	}
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/exchange-rates?budget=%s", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var list v4.ExchangeRateListResponse
+	test.DecodeResponse(suite.T(), &recorder, &list)
+	assert.Len(suite.T(), list.Data, 3)
+}
+
+// TestExchangeRatesConversion verifies that balances of accounts in other currencies
+// are converted to the currency of the budget.
+func (suite *TestSuiteStandard) TestExchangeRatesConversion() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{Currency: "EUR"})
+	initialBalanceDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dollar := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Dollar", OnBudget: true, Currency: "usd", InitialBalance: decimal.NewFromFloat(1000), InitialBalanceDate: &initialBalanceDate})
+	euro := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Euro", OnBudget: true})
+	assert.Equal(suite.T(), "USD", dollar.Data.Currency)
+
+	_ = createTestExchangeRate(suite.T(), v4.ExchangeRateEditable{BudgetID: budget.Data.ID, Currency: "USD", Date: initialBalanceDate, Rate: decimal.NewFromFloat(0.9)})
+
+	// Transfers between accounts with different currencies need the destination amount
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: dollar.Data.ID, DestinationAccountID: euro.Data.ID, Amount: decimal.NewFromFloat(100), Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}, http.StatusBadRequest)
+	transfer := createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      dollar.Data.ID,
+		DestinationAccountID: euro.Data.ID,
+		Amount:               decimal.NewFromFloat(100),
+		DestinationAmount:    decimal.NewFromFloat(91),
+		Date:                 time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	})
+	assert.True(suite.T(), decimal.NewFromFloat(91).Equal(transfer.Data.DestinationAmount))
+
+	recorder := test.Request(suite.T(), http.MethodPost, "/v4/accounts/computed", map[string]any{
+		"time": time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339),
+		"ids":  []string{dollar.Data.ID.String(), euro.Data.ID.String()},
+	})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var computed v4.AccountComputedDataResponse
+	test.DecodeResponse(suite.T(), &recorder, &computed)
+	assert.True(suite.T(), decimal.NewFromFloat(900).Equal(computed.Data[0].Balance), "Balance is %s", computed.Data[0].Balance)
+	assert.True(suite.T(), decimal.NewFromFloat(810).Equal(computed.Data[0].BudgetBalance), "Budget balance is %s", computed.Data[0].BudgetBalance)
+	assert.True(suite.T(), decimal.NewFromFloat(91).Equal(computed.Data[1].BudgetBalance), "Budget balance is %s", computed.Data[1].BudgetBalance)
+
+	recorder = test.Request(suite.T(), http.MethodGet, strings.Replace(budget.Data.Links.Month, "YYYY-MM", "2024-01", 1), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var month v4.MonthResponse
+	test.DecodeResponse(suite.T(), &recorder, &month)
+	assert.True(suite.T(), decimal.NewFromFloat(901).Equal(month.Data.Available), "Available is %s", month.Data.Available)
+}
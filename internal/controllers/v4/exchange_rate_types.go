@@ -0,0 +1,124 @@
+package v4
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ExchangeRateEditable struct {
+	BudgetID uuid.UUID       `json:"budgetId" example:"550dc009-cea6-4c12-b2a5-03446eb7b7cf"`                                          // ID of the budget the exchange rate belongs to
+	Currency string          `json:"currency" example:"USD"`                                                                           // ISO 4217 code of the currency
+	Date     time.Time       `json:"date" example:"2024-03-01T00:00:00Z"`                                                              // Date from which on the rate is valid. The time is always set to midnight UTC
+	Rate     decimal.Decimal `json:"rate" example:"0.92" minimum:"0.00000001" maximum:"999999999999.99999999" multipleOf:"0.00000001"` // Value of one unit of the currency in the currency of the budget
+}
+
+// model returns the database resource for the API representation of the editable fields
+func (editable ExchangeRateEditable) model() models.ExchangeRate {
+	date := editable.Date
+	if !date.IsZero() {
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	return models.ExchangeRate{
+		BudgetID: editable.BudgetID,
+		Currency: strings.ToUpper(strings.TrimSpace(editable.Currency)),
+		Date:     date,
+		Rate:     editable.Rate,
+	}
+}
+
+type ExchangeRateLinks struct {
+	Self   string `json:"self" example:"https://example.com/api/v4/exchange-rates/b5ef3b7c-6d9f-4a51-9c5d-3d2c0b7b0a19"` // The exchange rate itself
+	Budget string `json:"budget" example:"https://example.com/api/v4/budgets/550dc009-cea6-4c12-b2a5-03446eb7b7cf"`      // The budget the exchange rate belongs to
+}
+
+type ExchangeRate struct {
+	models.DefaultModel
+	ExchangeRateEditable
+	Links ExchangeRateLinks `json:"links"` // Links for the exchange rate
+}
+
+func newExchangeRate(c *gin.Context, model models.ExchangeRate) ExchangeRate {
+	url := c.GetString(string(models.DBContextURL))
+
+	return ExchangeRate{
+		DefaultModel: model.DefaultModel,
+		ExchangeRateEditable: ExchangeRateEditable{
+			BudgetID: model.BudgetID,
+			Currency: model.Currency,
+			Date:     model.Date,
+			Rate:     model.Rate,
+		},
+		Links: ExchangeRateLinks{
+			Self:   fmt.Sprintf("%s/v4/exchange-rates/%s", url, model.ID),
+			Budget: fmt.Sprintf("%s/v4/budgets/%s", url, model.BudgetID),
+		},
+	}
+}
+
+type ExchangeRateListResponse struct {
+	Data       []ExchangeRate `json:"data"`                                                          // List of resources
+	Error      *string        `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Pagination *Pagination    `json:"pagination"`                                                    // Pagination information
+}
+
+type ExchangeRateCreateResponse struct {
+	Error *string                `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Data  []ExchangeRateResponse `json:"data"`                                                          // List of created resources
+}
+
+func (e *ExchangeRateCreateResponse) appendError(err error, currentStatus int) int {
+	s := err.Error()
+	e.Data = append(e.Data, ExchangeRateResponse{Error: &s})
+
+	// The final status code is the highest HTTP status code number
+	newStatus := status(err)
+	if newStatus > currentStatus {
+		return newStatus
+	}
+
+	return currentStatus
+}
+
+type ExchangeRateResponse struct {
+	Error *string       `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Data  *ExchangeRate `json:"data"`                                                          // The resource
+}
+
+type ExchangeRateQueryFilter struct {
+	BudgetID ez_uuid.UUID `form:"budget"`                     // By budget ID
+	Currency string       `form:"currency"`                   // By currency
+	Offset   uint         `form:"offset" filterField:"false"` // The offset of the first exchange rate returned. Defaults to 0.
+	Limit    int          `form:"limit" filterField:"false"`  // Maximum number of exchange rates to return. Defaults to 50.
+	Sort     string       `form:"sort" filterField:"false"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor   string       `form:"cursor" filterField:"false"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// exchangeRateSorting defines how lists of exchange rates can be sorted
+var exchangeRateSorting = listSorting{
+	table: "exchange_rates",
+	fields: map[string]string{
+		"currency":  "exchange_rates.currency",
+		"date":      "datetime(exchange_rates.date)",
+		"createdAt": "exchange_rates.created_at",
+	},
+	defaults: "currency,-date",
+}
+
+func (f ExchangeRateQueryFilter) model() models.ExchangeRate {
+	return models.ExchangeRate{
+		BudgetID: f.BudgetID.UUID,
+		Currency: strings.ToUpper(strings.TrimSpace(f.Currency)),
+	}
+}
+
+type ExchangeRateImportQuery struct {
+	BudgetID ez_uuid.UUID `form:"budget" binding:"required"` // ID of the budget to import the exchange rates for
+}
@@ -1,14 +1,21 @@
 package v4
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"reflect"
 	"time"
 
+	"github.com/envelope-zero/backend/v7/internal/exporter"
 	"github.com/envelope-zero/backend/v7/internal/httputil"
 	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 )
 
 var backendVersion string
@@ -32,17 +39,59 @@ func OptionsExport(c *gin.Context) {
 }
 
 // @Summary		Export
-// @Description	Exports all resources for the instance
+// @Description	Exports all resources for the instance or a single budget.
+// @Description
+// @Description	The JSON format contains all fields of all resources. The CSV format is a zip archive with one file per model,
+// @Description	the XLSX format a workbook with one sheet per model. Both use human-readable columns with names of accounts,
+// @Description	categories and envelopes instead of their IDs.
+// @Description
+// @Description	The archive format is a zip archive with the JSON export as export.json and the files of all attachments.
 // @Tags			Export
 // @Produce		json
-// @Success		200	{object}	ExportResponse
-// @Failure		500	{object}	ExportResponse
+// @Produce		application/zip
+// @Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Success		200		{object}	ExportResponse
+// @Failure		400		{object}	httpError
+// @Failure		404		{object}	httpError
+// @Failure		500		{object}	httpError
+// @Param			budget	query		string			false	"ID of the budget to export. If not set, all budgets are exported."
+// @Param			format	query		ExportFormat	false	"Format of the export. Defaults to JSON."
 // @Router			/v4/export [get]
 func GetExport(c *gin.Context) {
-	resources := make(map[string]json.RawMessage)
+	var query ExportQuery
+	if err := c.Bind(&query); err != nil {
+		c.JSON(http.StatusBadRequest, httpError{
+			Error: err.Error(),
+		})
+		return
+	}
 
-	for _, model := range models.Registry {
-		b, err := model.Export()
+	if query.Format == "" {
+		query.Format = ExportFormatJSON
+	}
+
+	if !slices.Contains([]ExportFormat{ExportFormatJSON, ExportFormatCSV, ExportFormatXLSX, ExportFormatArchive}, query.Format) {
+		c.JSON(http.StatusBadRequest, httpError{
+			Error: errExportFormatInvalid.Error(),
+		})
+		return
+	}
+
+	var budgetID *uuid.UUID
+	if query.BudgetID != ez_uuid.Nil {
+		err := models.DB.First(&models.Budget{}, query.BudgetID.UUID).Error
+		if err != nil {
+			c.JSON(status(err), httpError{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		budgetID = &query.BudgetID.UUID
+	}
+
+	if query.Format == ExportFormatJSON {
+		export, err := exportResponse(budgetID)
 		if err != nil {
 			c.JSON(status(err), httpError{
 				Error: err.Error(),
@@ -50,13 +99,98 @@ func GetExport(c *gin.Context) {
 			return
 		}
 
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	tables := make([]models.Table, 0, len(models.Registry))
+	if query.Format != ExportFormatArchive {
+		for _, model := range models.Registry {
+			table, err := model.Table(budgetID)
+			if err != nil {
+				c.JSON(status(err), httpError{
+					Error: err.Error(),
+				})
+				return
+			}
+
+			tables = append(tables, table)
+		}
+	}
+
+	// The export is written to a buffer first so that errors can still be
+	// sent as JSON
+	var (
+		buffer      bytes.Buffer
+		err         error
+		contentType string
+		extension   string
+	)
+
+	switch query.Format {
+	case ExportFormatCSV:
+		err = exporter.CSV(&buffer, tables)
+		contentType = "application/zip"
+		extension = "zip"
+	case ExportFormatXLSX:
+		err = exporter.XLSX(&buffer, tables)
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		extension = "xlsx"
+	case ExportFormatArchive:
+		err = archive(&buffer, budgetID)
+		contentType = "application/zip"
+		extension = "zip"
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"envelope-zero-export-%s.%s\"", time.Now().Format(time.DateOnly), extension))
+	c.Data(http.StatusOK, contentType, buffer.Bytes())
+}
+
+// exportResponse returns the JSON export of all resources. If budgetID is set,
+// only the resources of that budget are exported.
+func exportResponse(budgetID *uuid.UUID) (ExportResponse, error) {
+	resources := make(map[string]json.RawMessage)
+
+	for _, model := range models.Registry {
+		b, err := model.Export(budgetID)
+		if err != nil {
+			return ExportResponse{}, err
+		}
+
 		resources[reflect.TypeOf(model).Name()] = b
 	}
 
-	c.JSON(http.StatusOK, ExportResponse{
+	return ExportResponse{
 		Version:      backendVersion,
 		Data:         resources,
 		CreationTime: time.Now(),
 		Clacks:       "GNU Terry Pratchett",
-	})
+	}, nil
+}
+
+// archive writes the JSON export together with the files of all attachments.
+func archive(w io.Writer, budgetID *uuid.UUID) error {
+	export, err := exportResponse(budgetID)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := models.ExportedAttachments(budgetID)
+	if err != nil {
+		return err
+	}
+
+	return exporter.Archive(w, b, attachments, models.Storage)
 }
@@ -1,13 +1,19 @@
 package v4_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"testing"
 	"time"
 
 	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
 	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -53,3 +59,86 @@ func (suite *TestSuiteStandard) TestExport() {
 	require.Len(t, categories, 1, "Number of categories in export must be 1")
 	assert.Equal(t, c.Data.CreatedAt, categories[0].CreatedAt)
 }
+
+// TestExportBudget verifies that only resources of the budget are exported
+// when the budget parameter is set.
+func (suite *TestSuiteStandard) TestExportBudget() {
+	t := suite.T()
+
+	b := createTestBudget(t, v4.BudgetEditable{})
+	_ = createTestCategory(t, v4.CategoryEditable{BudgetID: b.Data.ID})
+	_ = createTestCategory(t, v4.CategoryEditable{BudgetID: createTestBudget(t, v4.BudgetEditable{}).Data.ID})
+
+	recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/export?budget=%s", b.Data.ID), "")
+	test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+	var response v4.ExportResponse
+	test.DecodeResponse(t, &recorder, &response)
+
+	var budgets []models.Budget
+	require.Nil(t, json.Unmarshal(response.Data["Budget"], &budgets))
+	require.Len(t, budgets, 1, "Number of budgets in export must be 1")
+	assert.Equal(t, b.Data.ID, budgets[0].ID)
+
+	var categories []models.Category
+	require.Nil(t, json.Unmarshal(response.Data["Category"], &categories))
+	require.Len(t, categories, 1, "Number of categories in export must be 1")
+}
+
+// TestExportFormats verifies that the CSV and XLSX exports are zip archives
+// with one file per model.
+func (suite *TestSuiteStandard) TestExportFormats() {
+	t := suite.T()
+
+	b := createTestBudget(t, v4.BudgetEditable{})
+	_ = createTestCategory(t, v4.CategoryEditable{BudgetID: b.Data.ID, Name: "Daily"})
+
+	tests := []struct {
+		format      string
+		contentType string
+		file        string // file in the archive that contains the categories
+	}{
+		{"csv", "application/zip", "categories.csv"},
+		{"xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xl/worksheets/sheet3.xml"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.format, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/export?budget=%s&format=%s", b.Data.ID, tt.format), "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+			assert.Equal(t, tt.contentType, recorder.Header().Get("Content-Type"))
+			assert.Contains(t, recorder.Header().Get("Content-Disposition"), "attachment")
+
+			body := recorder.Body.Bytes()
+			reader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+			require.Nil(t, err)
+
+			f, err := reader.Open(tt.file)
+			require.Nil(t, err)
+
+			content, err := io.ReadAll(f)
+			require.Nil(t, err)
+			assert.Contains(t, string(content), "Daily")
+		})
+	}
+}
+
+// TestExportFails verifies that invalid export requests are handled correctly.
+func (suite *TestSuiteStandard) TestExportFails() {
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"Invalid format", "format=pdf", http.StatusBadRequest},
+		{"Invalid budget ID", "budget=NotParseableAsUUID", http.StatusBadRequest},
+		{"Non-existing budget", fmt.Sprintf("budget=%s", uuid.New()), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/export?%s", tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
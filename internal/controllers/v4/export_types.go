@@ -3,8 +3,25 @@ package v4
 import (
 	"encoding/json"
 	"time"
+
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+)
+
+// swagger:enum ExportFormat
+type ExportFormat string
+
+const (
+	ExportFormatJSON    ExportFormat = "json"
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatXLSX    ExportFormat = "xlsx"
+	ExportFormatArchive ExportFormat = "archive"
 )
 
+type ExportQuery struct {
+	BudgetID ez_uuid.UUID `form:"budget"` // ID of the budget to export
+	Format   ExportFormat `form:"format"` // Format of the export
+}
+
 type ExportResponse struct {
 	Version      string                     `json:"version"`      // The version of the backend the export was made with
 	Data         map[string]json.RawMessage `json:"data"`         // The exported data
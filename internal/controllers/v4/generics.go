@@ -2,14 +2,17 @@ package v4
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/envelope-zero/backend/v7/internal/httputil"
 	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type Resource interface {
-	models.Account | models.Budget | models.Category | models.Envelope | models.Goal | models.MatchRule | models.Transaction
+	models.Account | models.Attachment | models.Budget | models.Category | models.Envelope | models.ExchangeRate | models.Goal | models.MatchRule | models.Reconciliation | models.Tag | models.Transaction | models.Webhook
+	LastUpdate() time.Time
 }
 
 // resourceOptionsDetail returns the appropriate response for an HTTP OPTIONS request for a specific resource.
@@ -55,13 +58,41 @@ func deleteResource[R Resource](c *gin.Context) {
 		return
 	}
 
-	err = models.DB.Delete(&resource).Error
+	// Resources are moved to the trash together with all resources depending on them
+	var files []string
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &resource)
+		if err != nil {
+			return err
+		}
+
+		_, files, err = models.MoveToTrash(tx, &resource)
+		return err
+	})
 	if err != nil {
 		c.JSON(status(err), httpError{
 			Error: err.Error(),
 		})
 		return
 	}
+	models.DeleteStoredAttachments(files)
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// ifMatch checks the If-Match header of the request against the resource.
+//
+// The resource is read again with the transaction that writes it, so that no other
+// request can change it between the check and the write.
+func ifMatch[R interface{ LastUpdate() time.Time }](c *gin.Context, tx *gorm.DB, resource *R) error {
+	if c.GetHeader("If-Match") == "" {
+		return nil
+	}
+
+	err := tx.First(resource).Error
+	if err != nil {
+		return err
+	}
+
+	return httputil.IfMatch(c, httputil.ETag((*resource).LastUpdate()))
+}
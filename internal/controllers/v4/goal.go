@@ -9,12 +9,13 @@ import (
 	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
 )
 
 func RegisterGoalRoutes(r *gin.RouterGroup) {
 	{
 		r.OPTIONS("", OptionsGoals)
-		r.GET("", GetGoals)
+		r.GET("", httputil.NotModified, GetGoals)
 		r.POST("", CreateGoals)
 	}
 	{
@@ -76,7 +77,7 @@ func CreateGoals(c *gin.Context) {
 
 	for _, create := range goals {
 		goal := create.model()
-		err = models.DB.Create(&goal).Error
+		err = models.DB.WithContext(c).Create(&goal).Error
 		if err != nil {
 			status = r.appendError(err, status)
 			continue
@@ -95,8 +96,10 @@ func CreateGoals(c *gin.Context) {
 // @Tags			Goals
 // @Produce		json
 // @Success		200	{object}	GoalListResponse
-// @Failure		400	{object}	GoalListResponse
-// @Failure		500	{object}	GoalListResponse
+// @Success		304
+// @Failure		400				{object}	GoalListResponse
+// @Failure		500				{object}	GoalListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
 // @Router			/v4/goals [get]
 // @Param			name				query	string	false	"Filter by name"
 // @Param			note				query	string	false	"Filter by note"
@@ -111,6 +114,8 @@ func CreateGoals(c *gin.Context) {
 // @Param			amountMoreOrEqual	query	string	false	"Amount more than or equal to this"
 // @Param			offset				query	uint	false	"The offset of the first goal returned. Defaults to 0."
 // @Param			limit				query	int		false	"Maximum number of goal to return. Defaults to 50."
+// @Param			sort				query	string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are month, name, amount, createdAt. Defaults to month,name"
+// @Param			cursor				query	string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
 // @Param			period				query	uint	false	"Period is exactly this"
 // @Param			periodLessOrEqual	query	uint	false	"Period is less or equal to this. Non-recurring goals are not returned."
 // @Param			periodMoreOrEqual	query	uint	false	"Period is more or equal to this. Non-recurring goals are not returned."
@@ -137,20 +142,25 @@ func GetGoals(c *gin.Context) {
 	}
 
 	q := models.DB.
-		Order("date(goals.month) ASC, goals.name ASC").
 		Where(&where, queryFields...)
 
 	q = stringFilters(models.DB, q, setFields, filter.Name, filter.Note, filter.Search)
 
-	// Set the offset. Does not need checking since the default is 0
-	q = q.Offset(int(filter.Offset))
-
 	// Default to 50 Accounts and set the limit
 	limit := 50
 	if slices.Contains(setFields, "Limit") {
 		limit = filter.Limit
 	}
-	q = q.Limit(limit)
+
+	page, err := newListPage(goalSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, GoalListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
 
 	if !where.Month.IsZero() {
 		q = q.Where("goals.month >= date(?)", where.Month).Where("goals.month < date(?)", where.Month.AddDate(0, 1))
@@ -225,8 +235,7 @@ func GetGoals(c *gin.Context) {
 		return
 	}
 
-	var count int64
-	err = q.Limit(-1).Offset(-1).Count(&count).Error
+	goals, pagination, err := paginate(c, q, page, goals)
 	if err != nil {
 		e := err.Error()
 		c.JSON(status(err), GoalListResponse{
@@ -242,13 +251,8 @@ func GetGoals(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, GoalListResponse{
-		Data: data,
-		Pagination: &Pagination{
-			Count:  len(data),
-			Total:  count,
-			Offset: filter.Offset,
-			Limit:  limit,
-		},
+		Data:       data,
+		Pagination: &pagination,
 	})
 }
 
@@ -257,6 +261,7 @@ func GetGoals(c *gin.Context) {
 // @Tags			Goals
 // @Produce		json
 // @Success		200	{object}	GoalResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
 // @Failure		400	{object}	GoalResponse
 // @Failure		404	{object}	GoalResponse
 // @Failure		500	{object}	GoalResponse
@@ -284,6 +289,7 @@ func GetGoal(c *gin.Context) {
 	}
 
 	apiResource := newGoal(c, goal)
+	c.Header("ETag", httputil.ETag(goal.UpdatedAt))
 	c.JSON(http.StatusOK, GoalResponse{Data: &apiResource})
 }
 
@@ -292,12 +298,14 @@ func GetGoal(c *gin.Context) {
 // @Tags			Goals
 // @Accept			json
 // @Produce		json
-// @Success		200		{object}	GoalResponse
-// @Failure		400		{object}	GoalResponse
-// @Failure		404		{object}	GoalResponse
-// @Failure		500		{object}	GoalResponse
-// @Param			id		path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
-// @Param			goal	body		GoalEditable	true	"Goal"
+// @Success		200			{object}	GoalResponse
+// @Failure		400			{object}	GoalResponse
+// @Failure		404			{object}	GoalResponse
+// @Failure		412			{object}	GoalResponse
+// @Failure		500			{object}	GoalResponse
+// @Param			id			path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			goal		body		GoalEditable	true	"Goal"
+// @Param			If-Match	header		string			false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/goals/{id} [patch]
 func UpdateGoal(c *gin.Context) {
 	var uri URIID
@@ -341,7 +349,14 @@ func UpdateGoal(c *gin.Context) {
 		return
 	}
 
-	err = models.DB.Model(&goal).Select("", updateFields...).Updates(data.model()).Error
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &goal)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&goal).Select("", updateFields...).Updates(data.model()).Error
+	})
 	if err != nil {
 		e := err.Error()
 		c.JSON(status(err), GoalResponse{
@@ -351,17 +366,20 @@ func UpdateGoal(c *gin.Context) {
 	}
 
 	apiResource := newGoal(c, goal)
+	c.Header("ETag", httputil.ETag(goal.UpdatedAt))
 	c.JSON(http.StatusOK, GoalResponse{Data: &apiResource})
 }
 
 // @Summary		Delete goal
-// @Description	Deletes a goal
+// @Description	Deletes a goal and moves it to the trash
 // @Tags			Goals
 // @Success		204
-// @Failure		400	{object}	httpError
-// @Failure		404	{object}	httpError
-// @Failure		500	{object}	httpError
-// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/goals/{id} [delete]
 func DeleteGoal(c *gin.Context) {
 	deleteResource[models.Goal](c)
@@ -247,6 +247,46 @@ func (suite *TestSuiteStandard) TestGoalsGetFilter() {
 	}
 }
 
+func (suite *TestSuiteStandard) TestGoalsSort() {
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{})
+
+	for _, goal := range []v4.GoalEditable{
+		{Name: "Vacation", Amount: decimal.NewFromFloat(1000), Month: types.NewMonth(2024, 6)},
+		{Name: "Bike", Amount: decimal.NewFromFloat(500), Month: types.NewMonth(2024, 3)},
+		{Name: "Car", Amount: decimal.NewFromFloat(2000), Month: types.NewMonth(2024, 6)},
+	} {
+		goal.EnvelopeID = envelope.Data.ID
+		_ = createTestGoal(suite.T(), goal)
+	}
+
+	tests := []struct {
+		name     string
+		sort     string
+		expected []string
+	}{
+		{"Default", "", []string{"Bike", "Car", "Vacation"}},
+		{"Name", "name", []string{"Bike", "Car", "Vacation"}},
+		{"Name descending", "-name", []string{"Vacation", "Car", "Bike"}},
+		{"Amount", "amount", []string{"Bike", "Vacation", "Car"}},
+		{"Month descending and amount", "-month,amount", []string{"Vacation", "Car", "Bike"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			var re v4.GoalListResponse
+			r := test.Request(t, http.MethodGet, fmt.Sprintf("/v4/goals?envelope=%s&sort=%s", envelope.Data.ID, tt.sort), "")
+			test.AssertHTTPStatus(t, &r, http.StatusOK)
+			test.DecodeResponse(t, &r, &re)
+
+			names := make([]string, 0, len(re.Data))
+			for _, goal := range re.Data {
+				names = append(names, goal.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
 // TestGoalsGetInvalidQuery verifies that invalid filtering queries
 // return a HTTP Bad Request.
 func (suite *TestSuiteStandard) TestGoalsGetInvalidQuery() {
@@ -259,6 +299,7 @@ func (suite *TestSuiteStandard) TestGoalsGetInvalidQuery() {
 		"limit=name",            // limit is an int
 		"untilMonth=2023-11-01", // Format is "YYYY-MM"
 		"fromMonth=Yesterday",
+		"sort=envelope", // goals cannot be sorted by envelope
 	}
 
 	for _, tt := range tests {
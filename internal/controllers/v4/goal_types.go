@@ -115,6 +115,20 @@ type GoalQueryFilter struct {
 	PeriodMoreOrEqual uint            `form:"periodMoreOrEqual" filterField:"false"` // Period is more or equal to this. Non-recurring goals are not returned.
 	Offset            uint            `form:"offset" filterField:"false"`            // The offset of the first goal returned. Defaults to 0.
 	Limit             int             `form:"limit" filterField:"false"`             // Maximum number of goals to return. Defaults to 50.
+	Sort              string          `form:"sort" filterField:"false"`              // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor            string          `form:"cursor" filterField:"false"`            // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// goalSorting defines how lists of goals can be sorted
+var goalSorting = listSorting{
+	table: "goals",
+	fields: map[string]string{
+		"month":     "date(goals.month)",
+		"name":      "goals.name",
+		"amount":    "goals.amount",
+		"createdAt": "goals.created_at",
+	},
+	defaults: "month,name",
 }
 
 func (f GoalQueryFilter) model() (models.Goal, error) {
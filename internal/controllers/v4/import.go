@@ -3,6 +3,8 @@ package v4
 import (
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"strings"
@@ -16,6 +18,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/ryanuber/go-glob"
+	"golang.org/x/exp/slices"
 )
 
 type ImportQuery struct {
@@ -26,27 +29,74 @@ type ImportPreviewQuery struct {
 	AccountID ez_uuid.UUID `form:"accountId" binding:"required"` // ID of the account to import the transactions for
 }
 
+// uploadLimits restricts the files accepted by getUploadedFile.
+type uploadLimits struct {
+	suffix       string   // Suffix the file name must have. If empty, all names are accepted
+	maxSize      int64    // Maximum size in bytes. If 0, the size is not limited
+	contentTypes []string // MIME types the content must have. If empty, all types are accepted
+}
+
+// uploadedFile is a file sent to an endpoint.
+type uploadedFile struct {
+	multipart.File
+	name        string // Name of the file as sent by the client
+	size        int64  // Size in bytes
+	contentType string // MIME type detected from the content
+}
+
 // getUploadedFile returns the form file and handles potential errors.
-func getUploadedFile(c *gin.Context, suffix string) (multipart.File, error) {
+//
+// Files that do not match the limits are rejected.
+func getUploadedFile(c *gin.Context, limits uploadLimits) (uploadedFile, error) {
 	formFile, err := c.FormFile("file")
 	if formFile == nil {
-		return nil, errNoFilePost
+		return uploadedFile{}, errNoFilePost
 	}
 
 	if err != nil {
-		return nil, err
+		return uploadedFile{}, err
 	}
 
-	if !strings.HasSuffix(formFile.Filename, suffix) {
-		return nil, fmt.Errorf("%w: %s", errWrongFileSuffix, suffix)
+	if !strings.HasSuffix(formFile.Filename, limits.suffix) {
+		return uploadedFile{}, fmt.Errorf("%w: %s", errWrongFileSuffix, limits.suffix)
+	}
+
+	if limits.maxSize > 0 && formFile.Size > limits.maxSize {
+		return uploadedFile{}, fmt.Errorf("%w: %d bytes", errFileTooLarge, limits.maxSize)
 	}
 
 	f, err := formFile.Open()
 	if err != nil {
-		return nil, err
+		return uploadedFile{}, err
+	}
+
+	// The content type is detected from the first 512 bytes as the
+	// type sent by the client cannot be trusted
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		f.Close()
+		return uploadedFile{}, err
 	}
 
-	return f, nil
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		f.Close()
+		return uploadedFile{}, err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if len(limits.contentTypes) > 0 && !slices.Contains(limits.contentTypes, contentType) {
+		f.Close()
+		return uploadedFile{}, fmt.Errorf("%w: %s", errFileTypeNotAllowed, strings.Join(limits.contentTypes, ", "))
+	}
+
+	return uploadedFile{
+		File:        f,
+		name:        formFile.Filename,
+		size:        formFile.Size,
+		contentType: contentType,
+	}, nil
 }
 
 // duplicateTransactions finds duplicate transactions by their import hash. For all input resources,
@@ -136,7 +186,8 @@ func match(transaction *importer.TransactionPreview, rules []models.MatchRule) {
 	}
 }
 
-// recommendEnvelope sets the first of the recommended envelopes for the opposing account.
+// recommendEnvelope sets the default envelope of the opposing account. If it has none,
+// the first of the recommended envelopes for the opposing account is set.
 func recommendEnvelope(transaction *importer.TransactionPreview, id uuid.UUID) error {
 	// Load the account
 	var destinationAccount models.Account
@@ -145,6 +196,11 @@ func recommendEnvelope(transaction *importer.TransactionPreview, id uuid.UUID) e
 		return err
 	}
 
+	if destinationAccount.DefaultEnvelopeID != nil {
+		transaction.Transaction.EnvelopeID = destinationAccount.DefaultEnvelopeID
+		return nil
+	}
+
 	// Preset the most popular recent envelope
 	envelopes, err := destinationAccount.RecentEnvelopes(models.DB)
 	if err != nil {
@@ -259,7 +315,7 @@ func ImportYnabImportPreview(c *gin.Context) {
 		return
 	}
 
-	f, err := getUploadedFile(c, ".csv")
+	f, err := getUploadedFile(c, uploadLimits{suffix: ".csv"})
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), ImportPreviewList{
@@ -340,7 +396,15 @@ func ImportYnabImportPreview(c *gin.Context) {
 	// We need to transform the responses for v4
 	data := make([]TransactionPreview, 0, len(transactions))
 	for _, t := range transactions {
-		data = append(data, newTransactionPreview(c, t))
+		preview, err := newTransactionPreview(c, t)
+		if err != nil {
+			s := err.Error()
+			c.JSON(status(err), ImportPreviewList{
+				Error: &s,
+			})
+			return
+		}
+		data = append(data, preview)
 	}
 
 	c.JSON(http.StatusOK, ImportPreviewList{Data: data})
@@ -384,7 +448,7 @@ func ImportYnab4(c *gin.Context) {
 		return
 	}
 
-	f, err := getUploadedFile(c, ".yfull")
+	f, err := getUploadedFile(c, uploadLimits{suffix: ".yfull"})
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), BudgetResponse{
@@ -404,7 +468,7 @@ func ImportYnab4(c *gin.Context) {
 	// do not contain it
 	resources.Budget.Name = query.BudgetName
 
-	budget, err = importer.Create(models.DB, resources)
+	budget, err = importer.Create(models.DB.WithContext(c), resources)
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), BudgetResponse{
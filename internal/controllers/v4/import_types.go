@@ -2,24 +2,30 @@ package v4
 
 import (
 	"github.com/envelope-zero/backend/v7/internal/importer"
+	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 // newTransactionPreview transforms a TransactionPreview to the API resource
-func newTransactionPreview(c *gin.Context, t importer.TransactionPreview) TransactionPreview {
+func newTransactionPreview(c *gin.Context, t importer.TransactionPreview) (TransactionPreview, error) {
 	id := &t.MatchRuleID
 	if t.MatchRuleID == uuid.Nil {
 		id = nil
 	}
 
+	transaction, err := newTransaction(c, models.DB, t.Transaction)
+	if err != nil {
+		return TransactionPreview{}, err
+	}
+
 	return TransactionPreview{
-		Transaction:             newTransaction(c, t.Transaction),
+		Transaction:             transaction,
 		SourceAccountName:       t.SourceAccountName,
 		DestinationAccountName:  t.DestinationAccountName,
 		DuplicateTransactionIDs: t.DuplicateTransactionIDs,
 		MatchRuleID:             id,
-	}
+	}, nil
 }
 
 // TransactionPreview is used to preview transactions that will be imported to allow for editing.
@@ -10,6 +10,7 @@ import (
 	"github.com/envelope-zero/backend/v7/internal/models"
 	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // RegisterMatchRuleRoutes registers the routes for matchRules with
@@ -18,7 +19,7 @@ func RegisterMatchRuleRoutes(r *gin.RouterGroup) {
 	// Root group
 	{
 		r.OPTIONS("", OptionsMatchRuleList)
-		r.GET("", GetMatchRules)
+		r.GET("", httputil.NotModified, GetMatchRules)
 		r.POST("", CreateMatchRules)
 	}
 
@@ -83,7 +84,7 @@ func CreateMatchRules(c *gin.Context) {
 		matchRule := editable.model()
 
 		// Create the resource
-		err = models.DB.Create(&matchRule).Error
+		err = models.DB.WithContext(c).Create(&matchRule).Error
 		if err != nil {
 			status = r.appendError(err, status)
 			continue
@@ -100,15 +101,19 @@ func CreateMatchRules(c *gin.Context) {
 // @Description	Returns a list of matchRules
 // @Tags			MatchRules
 // @Produce		json
-// @Success		200			{object}	MatchRuleListResponse
-// @Failure		400			{object}	MatchRuleListResponse
-// @Failure		500			{object}	MatchRuleListResponse
-// @Param			priority	query		uint	false	"Filter by priority"
-// @Param			match		query		string	false	"Filter by match"
-// @Param			account		query		string	false	"Filter by account ID"
-// @Param			budget		query		string	false	"Filter by budget ID"
-// @Param			offset		query		uint	false	"The offset of the first Match Rule returned. Defaults to 0."
-// @Param			limit		query		int		false	"Maximum number of Match Rules to return. Defaults to 50.".
+// @Success		200	{object}	MatchRuleListResponse
+// @Success		304
+// @Failure		400				{object}	MatchRuleListResponse
+// @Failure		500				{object}	MatchRuleListResponse
+// @Param			priority		query		uint	false	"Filter by priority"
+// @Param			match			query		string	false	"Filter by match"
+// @Param			account			query		string	false	"Filter by account ID"
+// @Param			budget			query		string	false	"Filter by budget ID"
+// @Param			offset			query		uint	false	"The offset of the first Match Rule returned. Defaults to 0."
+// @Param			limit			query		int		false	"Maximum number of Match Rules to return. Defaults to 50.".
+// @Param			sort			query		string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are priority, match, createdAt. Defaults to priority,match"
+// @Param			cursor			query		string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
 // @Router			/v4/match-rules [get]
 func GetMatchRules(c *gin.Context) {
 	var filter MatchRuleQueryFilter
@@ -132,7 +137,6 @@ func GetMatchRules(c *gin.Context) {
 	}
 
 	q := models.DB.
-		Order("priority ASC, match ASC").
 		Where(&model, queryFields...)
 
 	// Filter for match containing the query string or explicitly empty one
@@ -149,15 +153,21 @@ func GetMatchRules(c *gin.Context) {
 			Where("budgets.id = ?", filter.BudgetID.UUID)
 	}
 
-	// Set the offset. Does not need checking since the default is 0
-	q = q.Offset(int(filter.Offset))
-
 	// Default to 50 Match Rules and set the limit
 	limit := 50
 	if slices.Contains(setFields, "Limit") {
 		limit = filter.Limit
 	}
-	q = q.Limit(limit)
+
+	page, err := newListPage(matchRuleSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, MatchRuleListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
 
 	// Execute the query
 	var matchRules []models.MatchRule
@@ -168,8 +178,7 @@ func GetMatchRules(c *gin.Context) {
 		return
 	}
 
-	var count int64
-	err = q.Limit(-1).Offset(-1).Count(&count).Error
+	matchRules, pagination, err := paginate(c, q, page, matchRules)
 	if err != nil {
 		e := err.Error()
 		c.JSON(status(err), MatchRuleListResponse{
@@ -184,13 +193,8 @@ func GetMatchRules(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, MatchRuleListResponse{
-		Data: data,
-		Pagination: &Pagination{
-			Count:  len(data),
-			Total:  count,
-			Offset: filter.Offset,
-			Limit:  limit,
-		},
+		Data:       data,
+		Pagination: &pagination,
 	})
 }
 
@@ -199,6 +203,7 @@ func GetMatchRules(c *gin.Context) {
 // @Tags			MatchRules
 // @Produce		json
 // @Success		200	{object}	MatchRuleResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
 // @Failure		400	{object}	MatchRuleResponse
 // @Failure		404	{object}	MatchRuleResponse
 // @Failure		500	{object}	MatchRuleResponse
@@ -224,6 +229,7 @@ func GetMatchRule(c *gin.Context) {
 	}
 	data := newMatchRule(c, matchRule)
 
+	c.Header("ETag", httputil.ETag(matchRule.UpdatedAt))
 	c.JSON(http.StatusOK, MatchRuleResponse{
 		Data: &data,
 	})
@@ -237,9 +243,11 @@ func GetMatchRule(c *gin.Context) {
 // @Success		200			{object}	MatchRuleResponse
 // @Failure		400			{object}	MatchRuleResponse
 // @Failure		404			{object}	MatchRuleResponse
+// @Failure		412			{object}	MatchRuleResponse
 // @Failure		500			{object}	MatchRuleResponse
 // @Param			id			path		URIID				true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
 // @Param			matchRule	body		MatchRuleEditable	true	"MatchRule"
+// @Param			If-Match	header		string				false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/match-rules/{id} [patch]
 func UpdateMatchRule(c *gin.Context) {
 	var uri URIID
@@ -281,7 +289,14 @@ func UpdateMatchRule(c *gin.Context) {
 		return
 	}
 
-	err = models.DB.Model(&matchRule).Select("", updateFields...).Updates(data.model()).Error
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &matchRule)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&matchRule).Select("", updateFields...).Updates(data.model()).Error
+	})
 	if err != nil {
 		e := err.Error()
 		c.JSON(status(err), MatchRuleResponse{
@@ -291,19 +306,22 @@ func UpdateMatchRule(c *gin.Context) {
 	}
 
 	apiResource := newMatchRule(c, matchRule)
+	c.Header("ETag", httputil.ETag(matchRule.UpdatedAt))
 	c.JSON(http.StatusOK, MatchRuleResponse{
 		Data: &apiResource,
 	})
 }
 
 // @Summary		Delete matchRule
-// @Description	Deletes an matchRule
+// @Description	Deletes an matchRule and moves it to the trash
 // @Tags			MatchRules
 // @Success		204
-// @Failure		400	{object}	httpError
-// @Failure		404	{object}	httpError
-// @Failure		500	{object}	httpError
-// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/match-rules/{id} [delete]
 func DeleteMatchRule(c *gin.Context) {
 	deleteResource[models.MatchRule](c)
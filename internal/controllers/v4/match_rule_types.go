@@ -10,7 +10,7 @@ import (
 )
 
 type MatchRuleEditable struct {
-	AccountID uuid.UUID `json:"accountId" example:"f9e873c2-fb96-4367-bfb6-7ecd9bf4a6b5"` // The account to map matching transactions to
+	AccountID uuid.UUID `json:"accountId" example:"f9e873c2-fb96-4367-bfb6-7ecd9bf4a6b5"` // The payee or account to map matching transactions to
 	Priority  uint      `json:"priority" example:"3"`                                     // The priority of the match rule
 	Match     string    `json:"match" example:"Bank*"`                                    // The matching applied to the opposite account. This is a glob pattern. Multiple globs are allowed. Globbing is case sensitive.
 }
@@ -87,6 +87,19 @@ type MatchRuleQueryFilter struct {
 	AccountID ez_uuid.UUID `form:"account"`                    // By ID of the Account they map to
 	Offset    uint         `form:"offset" filterField:"false"` // The offset of the first Match Rule returned. Defaults to 0.
 	Limit     int          `form:"limit" filterField:"false"`  // Maximum number of Match Rules to return. Defaults to 50.
+	Sort      string       `form:"sort" filterField:"false"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor    string       `form:"cursor" filterField:"false"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// matchRuleSorting defines how lists of match rules can be sorted
+var matchRuleSorting = listSorting{
+	table: "match_rules",
+	fields: map[string]string{
+		"priority":  "match_rules.priority",
+		"match":     "match_rules.match",
+		"createdAt": "match_rules.created_at",
+	},
+	defaults: "priority,match",
 }
 
 // Parse returns a models.MatchRuleCreate struct that represents the MatchRuleQueryFilter.
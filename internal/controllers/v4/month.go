@@ -1,9 +1,9 @@
 package v4
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/envelope-zero/backend/v7/internal/httputil"
 	"github.com/envelope-zero/backend/v7/internal/models"
@@ -20,6 +20,27 @@ type MonthResponse struct {
 	Error *string `json:"error"` // The error, if any occurred
 }
 
+type MonthRangeResponse struct {
+	Data  []Month `json:"data"`  // Data for the months, earliest month first
+	Error *string `json:"error"` // The error, if any occurred
+}
+
+type MonthRangeQuery struct {
+	BudgetID string    `form:"budget" example:"81b0c9ce-6fd3-4e1e-becc-106055898a2a"`      // ID of the budget
+	From     time.Time `form:"from" time_format:"2006-01" time_utc:"1" example:"2024-01"`  // First month in YYYY-MM format
+	Until    time.Time `form:"until" time_format:"2006-01" time_utc:"1" example:"2024-12"` // Last month in YYYY-MM format
+}
+
+type MonthTransfer struct {
+	BudgetID       uuid.UUID       `json:"budgetId" example:"81b0c9ce-6fd3-4e1e-becc-106055898a2a"`                                                      // ID of the budget
+	Month          types.Month     `json:"month" example:"2024-07-01T00:00:00.000000Z"`                                                                  // The month to move the money in
+	FromEnvelopeID *uuid.UUID      `json:"fromEnvelopeId" example:"d5a6ea4b-4e4a-4ef6-9f5d-3c1d0b4bb1a2"`                                                // ID of the envelope to move the money from. If not set, the money is taken from the amount available to budget
+	ToEnvelopeID   *uuid.UUID      `json:"toEnvelopeId" example:"2e3a6f0b-3b4d-4a61-b6a5-36a4a8f7e5c4"`                                                  // ID of the envelope to move the money to. If not set, the money is moved to the amount available to budget
+	Amount         decimal.Decimal `json:"amount" example:"25" minimum:"0.00000001" maximum:"999999999999.99999999" multipleOf:"0.00000001" default:"0"` // The amount to move
+	Note           string          `json:"note" example:"Rent went up" default:""`                                                                       // A note for the move. It is added to the notes of the month configs
+	Force          bool            `json:"force" example:"false" default:"false"`                                                                        // Move the money even if the amount available to budget or the balance of the source envelope becomes negative
+}
+
 type Month struct {
 	ID         uuid.UUID           `json:"id" example:"1e777d24-3f5b-4c43-8000-04f65f895578"` // The ID of the Budget
 	Name       string              `json:"name" example:"Zero budget"`                        // The name of the Budget
@@ -53,9 +74,15 @@ type EnvelopeMonth struct {
 func RegisterMonthRoutes(r *gin.RouterGroup) {
 	{
 		r.OPTIONS("", OptionsMonth)
-		r.GET("", GetMonth)
+		r.GET("", httputil.NotModified, GetMonth)
 		r.POST("", SetAllocations)
 		r.DELETE("", DeleteAllocations)
+
+		r.OPTIONS("/range", OptionsMonthRange)
+		r.GET("/range", httputil.NotModified, GetMonthRange)
+
+		r.OPTIONS("/transfer", OptionsMonthTransfer)
+		r.POST("/transfer", TransferAllocation)
 	}
 }
 
@@ -68,19 +95,48 @@ func OptionsMonth(c *gin.Context) {
 	httputil.OptionsGetPostDelete(c)
 }
 
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs.
+// @Tags			Months
+// @Success		204
+// @Router			/v4/months/range [options]
+func OptionsMonthRange(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs.
+// @Tags			Months
+// @Success		204
+// @Router			/v4/months/transfer [options]
+func OptionsMonthTransfer(c *gin.Context) {
+	httputil.OptionsPost(c)
+}
+
 // @Summary		Get data about a month
 // @Description	Returns data about a specific month.
 // @Tags			Months
 // @Produce		json
-// @Success		200		{object}	MonthResponse
-// @Failure		400		{object}	MonthResponse
-// @Failure		404		{object}	MonthResponse
-// @Failure		500		{object}	MonthResponse
-// @Param			budget	query		string		true	"ID formatted as string"
-// @Param			month	query		QueryMonth	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Success		200	{object}	MonthResponse
+// @Success		304
+// @Failure		400				{object}	MonthResponse
+// @Failure		404				{object}	MonthResponse
+// @Failure		500				{object}	MonthResponse
+// @Param			budget			query		string		true	"ID formatted as string"
+// @Param			month			query		QueryMonth	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-None-Match	header		string		false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
 // @Router			/v4/months [get]
 func GetMonth(c *gin.Context) {
-	qMonth, b, err := parseMonthQuery(c)
+	month, b, err := parseMonthQuery(c)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), MonthResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	months, err := budgetMonths(c, models.DB, b, month, month)
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), MonthResponse{
@@ -89,74 +145,139 @@ func GetMonth(c *gin.Context) {
 		return
 	}
 
-	month := qMonth
+	c.JSON(http.StatusOK, MonthResponse{Data: &months[0]})
+}
+
+// @Summary		Get data about a range of months
+// @Description	Returns data about all months from the first to the last month, inclusive.
+// @Tags			Months
+// @Produce		json
+// @Success		200	{object}	MonthRangeResponse
+// @Success		304
+// @Failure		400				{object}	MonthRangeResponse
+// @Failure		404				{object}	MonthRangeResponse
+// @Failure		500				{object}	MonthRangeResponse
+// @Param			budget			query		string	true	"ID formatted as string"
+// @Param			from			query		string	true	"The first month in YYYY-MM format"
+// @Param			until			query		string	true	"The last month in YYYY-MM format"
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/months/range [get]
+func GetMonthRange(c *gin.Context) {
+	var query MonthRangeQuery
+	if err := c.BindQuery(&query); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, MonthRangeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if query.From.IsZero() || query.Until.IsZero() {
+		s := errMonthRangeNotSet.Error()
+		c.JSON(http.StatusBadRequest, MonthRangeResponse{
+			Error: &s,
+		})
+		return
+	}
 
-	result := Month{
-		ID:    b.ID,
-		Name:  b.Name,
-		Month: month,
+	from, until := types.MonthOf(query.From), types.MonthOf(query.Until)
+	if until.Before(from) {
+		s := errMonthRangeInvalid.Error()
+		c.JSON(http.StatusBadRequest, MonthRangeResponse{
+			Error: &s,
+		})
+		return
 	}
 
-	// Add allocated sum to response
-	allocated, err := b.Allocated(models.DB, result.Month)
+	budgetID, err := uuid.Parse(query.BudgetID)
 	if err != nil {
 		s := err.Error()
-		c.JSON(status(err), MonthResponse{
+		c.JSON(http.StatusBadRequest, MonthRangeResponse{
 			Error: &s,
 		})
 		return
 	}
-	result.Allocation = allocated
 
-	// Add income to response
-	income, err := b.Income(models.DB, result.Month)
+	var b models.Budget
+	err = models.DB.First(&b, budgetID).Error
 	if err != nil {
 		s := err.Error()
-		c.JSON(status(err), MonthResponse{
+		c.JSON(status(err), MonthRangeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	months, err := budgetMonths(c, models.DB, b, from, until)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), MonthRangeResponse{
 			Error: &s,
 		})
 		return
 	}
-	result.Income = income
+
+	c.JSON(http.StatusOK, MonthRangeResponse{Data: months})
+}
+
+// budgetMonths calculates the data for all months from the first to the last month, inclusive.
+//
+// The envelope balances are calculated in a single pass over all months.
+func budgetMonths(c *gin.Context, db *gorm.DB, b models.Budget, from, until types.Month) ([]Month, error) {
+	months := make([]Month, 0)
+	for month := from; !month.After(until); month = month.AddDate(0, 1) {
+		result := Month{
+			ID:         b.ID,
+			Name:       b.Name,
+			Month:      month,
+			Categories: make([]CategoryEnvelopes, 0),
+		}
+
+		// Add allocated sum to response
+		allocated, err := b.Allocated(db, month)
+		if err != nil {
+			return nil, err
+		}
+		result.Allocation = allocated
+
+		// Add income to response
+		income, err := b.Income(db, month)
+		if err != nil {
+			return nil, err
+		}
+		result.Income = income
+
+		months = append(months, result)
+	}
 
 	// Get all categories for the budget
 	var categories []models.Category
-	err = models.DB.
+	err := db.
 		Where(&models.Category{BudgetID: b.ID}).
 		Order("name ASC").
 		Find(&categories).
 		Error
 	if err != nil {
-		s := err.Error()
-		c.JSON(status(err), MonthResponse{
-			Error: &s,
-		})
-		return
+		return nil, err
 	}
 
-	result.Categories = make([]CategoryEnvelopes, 0)
-	result.Balance = decimal.Zero
-
 	// Get envelopes for all categories
 	for _, category := range categories {
-		var categoryEnvelopes CategoryEnvelopes
-
 		// Set the basic category values
-		categoryResource, err := newCategory(c, models.DB, category)
+		categoryResource, err := newCategory(c, db, category)
 		if err != nil {
-			s := err.Error()
-			c.JSON(status(err), MonthResponse{
-				Error: &s,
-			})
-			return
+			return nil, err
 		}
 
-		categoryEnvelopes.Category = categoryResource
-		categoryEnvelopes.Envelopes = make([]EnvelopeMonth, 0)
+		for i := range months {
+			months[i].Categories = append(months[i].Categories, CategoryEnvelopes{
+				Category:  categoryResource,
+				Envelopes: make([]EnvelopeMonth, 0),
+			})
+		}
 
 		var envelopes []models.Envelope
-
-		err = models.DB.
+		err = db.
 			Where(&models.Envelope{
 				CategoryID: category.ID,
 			}).
@@ -164,65 +285,64 @@ func GetMonth(c *gin.Context) {
 			Find(&envelopes).
 			Error
 		if err != nil {
-			s := err.Error()
-			c.JSON(status(err), MonthResponse{
-				Error: &s,
-			})
-			return
+			return nil, err
 		}
 
 		for _, envelope := range envelopes {
-			envelopeMonth, err := envelopeMonth(c, models.DB, envelope, result.Month)
+			envelopeMonths, err := envelope.Months(db, from, until)
 			if err != nil {
-				s := err.Error()
-				c.JSON(status(err), MonthResponse{
-					Error: &s,
-				})
-				return
+				return nil, err
 			}
 
-			// Update the month's summarized data
-			result.Balance = result.Balance.Add(envelopeMonth.Balance)
-			result.Spent = result.Spent.Add(envelopeMonth.Spent)
-
-			// Update the category's summarized data
-			categoryEnvelopes.Balance = categoryEnvelopes.Balance.Add(envelopeMonth.Balance)
-			categoryEnvelopes.Spent = categoryEnvelopes.Spent.Add(envelopeMonth.Spent)
-			categoryEnvelopes.Allocation = categoryEnvelopes.Allocation.Add(envelopeMonth.Allocation)
-			categoryEnvelopes.Envelopes = append(categoryEnvelopes.Envelopes, envelopeMonth)
+			for i, e := range envelopeMonths {
+				envelopeMonth := EnvelopeMonth{
+					Envelope:   newEnvelope(c, envelope),
+					Spent:      e.Spent,
+					Balance:    e.Balance,
+					Allocation: e.Allocation,
+				}
+
+				// Update the month's summarized data
+				months[i].Balance = months[i].Balance.Add(envelopeMonth.Balance)
+				months[i].Spent = months[i].Spent.Add(envelopeMonth.Spent)
+
+				// Update the category's summarized data
+				categoryEnvelopes := &months[i].Categories[len(months[i].Categories)-1]
+				categoryEnvelopes.Balance = categoryEnvelopes.Balance.Add(envelopeMonth.Balance)
+				categoryEnvelopes.Spent = categoryEnvelopes.Spent.Add(envelopeMonth.Spent)
+				categoryEnvelopes.Allocation = categoryEnvelopes.Allocation.Add(envelopeMonth.Allocation)
+				categoryEnvelopes.Envelopes = append(categoryEnvelopes.Envelopes, envelopeMonth)
+			}
 		}
-
-		result.Categories = append(result.Categories, categoryEnvelopes)
 	}
 
-	// Available amount is the sum of balances of all on-budget accounts, then subtract the sum of all envelope balances
-	result.Available = result.Balance.Neg()
-
 	// Get all on budget accounts for the budget
 	var accounts []models.Account
-	err = models.DB.Where(&models.Account{BudgetID: b.ID, OnBudget: true}).Find(&accounts).Error
+	err = db.Where(&models.Account{BudgetID: b.ID, OnBudget: true}).Find(&accounts).Error
 	if err != nil {
-		s := err.Error()
-		c.JSON(status(err), MonthResponse{
-			Error: &s,
-		})
-		return
+		return nil, err
 	}
 
-	// Add all on-balance accounts to the available sum
-	for _, a := range accounts {
-		_, available, err := a.GetBalanceMonth(models.DB, month)
-		if err != nil {
-			s := err.Error()
-			c.JSON(status(err), MonthResponse{
-				Error: &s,
-			})
-			return
+	rates, err := models.LoadExchangeRates(db, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range months {
+		// Available amount is the sum of balances of all on-budget accounts, then subtract the sum of all envelope balances
+		months[i].Available = months[i].Balance.Neg()
+
+		// Add all on-balance accounts to the available sum
+		for _, a := range accounts {
+			_, available, err := a.BudgetBalanceMonth(db, rates, months[i].Month)
+			if err != nil {
+				return nil, err
+			}
+			months[i].Available = months[i].Available.Add(available)
 		}
-		result.Available = result.Available.Add(available)
 	}
 
-	c.JSON(http.StatusOK, MonthResponse{Data: &result})
+	return months, nil
 }
 
 // @Summary		Delete allocations for a month
@@ -264,7 +384,7 @@ func DeleteAllocations(c *gin.Context) {
 
 	for _, monthConfig := range monthConfigs {
 		monthConfig.Allocation = decimal.Zero
-		err = models.DB.Updates(&monthConfig).Error
+		err = models.DB.WithContext(c).Updates(&monthConfig).Error
 		if err != nil {
 			c.JSON(status(err), httpError{
 				Error: err.Error(),
@@ -346,12 +466,19 @@ func SetAllocations(c *gin.Context) {
 		// If the mode is the spend of last month, calculate and set it
 		amount := allocation.Amount
 		if data.Mode == AllocateLastMonthSpend {
-			amount = models.Envelope{DefaultModel: models.DefaultModel{ID: allocation.EnvelopeID}}.Spent(models.DB, pastMonth).Neg()
+			spent, err := models.Envelope{DefaultModel: models.DefaultModel{ID: allocation.EnvelopeID}}.Spent(models.DB, pastMonth)
+			if err != nil {
+				c.JSON(status(err), httpError{
+					Error: err.Error(),
+				})
+				return
+			}
+			amount = spent.Neg()
 		}
 
 		// Find and update the correct MonthConfig.
 		// If it does not exist, create it
-		err = models.DB.Where(models.MonthConfig{
+		err = models.DB.WithContext(c).Where(models.MonthConfig{
 			Month:      month,
 			EnvelopeID: allocation.EnvelopeID,
 		}).Assign(models.MonthConfig{
@@ -369,35 +496,96 @@ func SetAllocations(c *gin.Context) {
 	c.JSON(http.StatusNoContent, gin.H{})
 }
 
-// envelopeMonth calculates the month specific values for an envelope and returns an EnvelopeMonth with them
-func envelopeMonth(c *gin.Context, db *gorm.DB, e models.Envelope, month types.Month) (EnvelopeMonth, error) {
-	spent := e.Spent(db, month)
+// @Summary		Move money between envelopes
+// @Description	Moves money from the allocation of one envelope to another one for a month. If one of the envelopes is not set, the money is moved from or to the amount available to budget. Moves that make the amount available to budget or the balance of the source envelope negative are rejected unless forced.
+// @Tags			Months
+// @Accept			json
+// @Produce		json
+// @Success		200			{object}	MonthResponse
+// @Failure		400			{object}	MonthResponse
+// @Failure		404			{object}	MonthResponse
+// @Failure		500			{object}	MonthResponse
+// @Param			transfer	body		MonthTransfer	true	"Transfer"
+// @Router			/v4/months/transfer [post]
+func TransferAllocation(c *gin.Context) {
+	var data MonthTransfer
+	err := httputil.BindData(c, &data)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), MonthResponse{
+			Error: &s,
+		})
+		return
+	}
 
-	envelopeMonth := EnvelopeMonth{
-		Envelope:   newEnvelope(c, e),
-		Spent:      spent,
-		Balance:    decimal.NewFromFloat(0),
-		Allocation: decimal.NewFromFloat(0),
+	if data.Month.IsZero() {
+		s := errMonthNotSet.Error()
+		c.JSON(http.StatusBadRequest, MonthResponse{
+			Error: &s,
+		})
+		return
 	}
 
-	var monthConfig models.MonthConfig
-	err := db.First(&monthConfig, &models.MonthConfig{
-		EnvelopeID: e.ID,
-		Month:      month,
-	}).Error
+	var budget models.Budget
+	err = models.DB.First(&budget, data.BudgetID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), MonthResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	month := types.MonthOf(time.Time(data.Month))
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := models.MoveAllocation(tx, budget.ID, month, data.FromEnvelopeID, data.ToEnvelopeID, data.Amount, data.Note)
+		if err != nil || data.Force {
+			return err
+		}
+
+		// The amount available to budget only decreases when money is taken from it
+		if data.FromEnvelopeID == nil {
+			months, err := budgetMonths(c, tx, budget, month, month)
+			if err != nil {
+				return err
+			}
+
+			if months[0].Available.IsNegative() {
+				return errMonthTransferAvailable
+			}
+
+			return nil
+		}
+
+		balance, err := models.Envelope{DefaultModel: models.DefaultModel{ID: *data.FromEnvelopeID}}.Balance(tx, month)
+		if err != nil {
+			return err
+		}
+
+		if balance.IsNegative() {
+			return errMonthTransferBalance
+		}
 
-	// If an unexpected error occurs, return
-	if err != nil && !errors.Is(err, models.ErrResourceNotFound) {
-		return EnvelopeMonth{}, err
+		return nil
+	})
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), MonthResponse{
+			Error: &s,
+		})
+		return
 	}
 
-	envelopeMonth.Balance, err = e.Balance(db, month)
+	months, err := budgetMonths(c, models.DB, budget, month, month)
 	if err != nil {
-		return EnvelopeMonth{}, err
+		s := err.Error()
+		c.JSON(status(err), MonthResponse{
+			Error: &s,
+		})
+		return
 	}
 
-	envelopeMonth.Allocation = monthConfig.Allocation
-	return envelopeMonth, nil
+	c.JSON(http.StatusOK, MonthResponse{Data: &months[0]})
 }
 
 // parseMonthQuery takes in the context and parses the request
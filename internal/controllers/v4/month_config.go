@@ -8,6 +8,7 @@ import (
 	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/envelope-zero/backend/v7/internal/types"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // RegisterMonthConfigRoutes registers the routes for transactions with
@@ -43,6 +44,7 @@ func OptionsMonthConfigDetail(c *gin.Context) {
 // @Tags			Envelopes
 // @Produce		json
 // @Success		200		{object}	MonthConfigResponse
+// @Header			200		{string}	ETag	"Entity tag of the resource, changes with every update"
 // @Failure		400		{object}	MonthConfigResponse
 // @Failure		404		{object}	MonthConfigResponse
 // @Failure		500		{object}	MonthConfigResponse
@@ -89,6 +91,7 @@ func GetMonthConfig(c *gin.Context) {
 	}
 
 	data = newMonthConfig(c, mConfig)
+	c.Header("ETag", httputil.ETag(mConfig.UpdatedAt))
 	c.JSON(http.StatusOK, MonthConfigResponse{Data: &data})
 }
 
@@ -99,10 +102,12 @@ func GetMonthConfig(c *gin.Context) {
 // @Success		201			{object}	MonthConfigResponse
 // @Failure		400			{object}	MonthConfigResponse
 // @Failure		404			{object}	MonthConfigResponse
+// @Failure		412			{object}	MonthConfigResponse
 // @Failure		500			{object}	MonthConfigResponse
 // @Param			id			path		URIMonth			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
 // @Param			month		path		URIMonth			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
 // @Param			monthConfig	body		MonthConfigEditable	true	"MonthConfig"
+// @Param			If-Match	header		string				false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
 // @Router			/v4/envelopes/{id}/{month} [patch]
 func UpdateMonthConfig(c *gin.Context) {
 	var uri URIMonth
@@ -150,7 +155,7 @@ func UpdateMonthConfig(c *gin.Context) {
 		data.Month = types.Month(uri.Month)
 
 		model := data.model()
-		e := models.DB.Create(&model).Error
+		e := models.DB.WithContext(c).Create(&model).Error
 
 		if e != nil {
 			s := err.Error()
@@ -160,6 +165,7 @@ func UpdateMonthConfig(c *gin.Context) {
 		}
 
 		apiResource := newMonthConfig(c, model)
+		c.Header("ETag", httputil.ETag(model.UpdatedAt))
 		c.JSON(http.StatusOK, MonthConfigResponse{
 			Data: &apiResource,
 		})
@@ -175,8 +181,15 @@ func UpdateMonthConfig(c *gin.Context) {
 		return
 	}
 
-	// Perform the actual update
-	err = models.DB.Model(&m).Select("", updateFields...).Updates(data.model()).Error
+	// Perform the actual update. Only existing month configs have an ETag to match
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &m)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&m).Select("", updateFields...).Updates(data.model()).Error
+	})
 	if err != nil {
 		s := err.Error()
 		c.JSON(status(err), MonthConfigResponse{
@@ -186,5 +199,6 @@ func UpdateMonthConfig(c *gin.Context) {
 	}
 
 	apiResource := newMonthConfig(c, m)
+	c.Header("ETag", httputil.ETag(m.UpdatedAt))
 	c.JSON(http.StatusOK, MonthConfigResponse{Data: &apiResource})
 }
@@ -541,3 +541,219 @@ func (suite *TestSuiteStandard) TestMonths() {
 		})
 	}
 }
+
+// TestMonthsRange verifies that the month range endpoint returns the same data as
+// requesting every month on its own.
+func (suite *TestSuiteStandard) TestMonthsRange() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID, Name: "Daily"})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Groceries"})
+	otherEnvelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Rent"})
+	_ = createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID, Name: "Empty"})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, OnBudget: true, Name: "Bank"})
+	externalAccount := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, External: true, Name: "Shop"})
+
+	patchTestMonthConfig(suite.T(), envelope.Data.ID, types.NewMonth(2023, 12), v4.MonthConfigEditable{Allocation: decimal.NewFromFloat(30)})
+	patchTestMonthConfig(suite.T(), envelope.Data.ID, types.NewMonth(2024, 2), v4.MonthConfigEditable{Allocation: decimal.NewFromFloat(20)})
+	patchTestMonthConfig(suite.T(), otherEnvelope.Data.ID, types.NewMonth(2024, 1), v4.MonthConfigEditable{Allocation: decimal.NewFromFloat(500)})
+
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+		Date:                 time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		AvailableFrom:        types.NewMonth(2024, 1),
+		Amount:               decimal.NewFromFloat(1000),
+		SourceAccountID:      externalAccount.Data.ID,
+		DestinationAccountID: account.Data.ID,
+	})
+
+	// Overspend in January, which must not roll over into February
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+		Date:                 time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC),
+		Amount:               decimal.NewFromFloat(50),
+		SourceAccountID:      account.Data.ID,
+		DestinationAccountID: externalAccount.Data.ID,
+		EnvelopeID:           &envelope.Data.ID,
+	})
+
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+		Date:                 time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		Amount:               decimal.NewFromFloat(5),
+		SourceAccountID:      externalAccount.Data.ID,
+		DestinationAccountID: account.Data.ID,
+		EnvelopeID:           &envelope.Data.ID,
+	})
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/months/range?budget=%s&from=2024-01&until=2024-04", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.MonthRangeResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	suite.Require().Len(response.Data, 4)
+
+	balances := []float64{-20, 20, 25, 25}
+	for i, month := range response.Data {
+		suite.T().Run(month.Month.String(), func(t *testing.T) {
+			assert.Equal(t, types.NewMonth(2024, time.Month(i+1)), month.Month)
+
+			// The envelope balance is carried over correctly
+			assert.True(t, month.Categories[0].Envelopes[0].Balance.Equal(decimal.NewFromFloat(balances[i])), "Balance is %s, expected %f", month.Categories[0].Envelopes[0].Balance, balances[i])
+
+			// The data is the same as for the single month
+			recorder := test.Request(t, http.MethodGet, strings.Replace(budget.Data.Links.Month, "YYYY-MM", month.Month.String(), 1), "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+			var single v4.MonthResponse
+			test.DecodeResponse(t, &recorder, &single)
+
+			assert.True(t, single.Data.Income.Equal(month.Income), "Income is %s, expected %s", month.Income, single.Data.Income)
+			assert.True(t, single.Data.Available.Equal(month.Available), "Available is %s, expected %s", month.Available, single.Data.Available)
+			assert.True(t, single.Data.Balance.Equal(month.Balance), "Balance is %s, expected %s", month.Balance, single.Data.Balance)
+			assert.True(t, single.Data.Spent.Equal(month.Spent), "Spent is %s, expected %s", month.Spent, single.Data.Spent)
+			assert.True(t, single.Data.Allocation.Equal(month.Allocation), "Allocation is %s, expected %s", month.Allocation, single.Data.Allocation)
+			assert.Equal(t, len(single.Data.Categories), len(month.Categories))
+		})
+	}
+}
+
+func (suite *TestSuiteStandard) TestMonthsRangeFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"No months", fmt.Sprintf("budget=%s", budget.Data.ID), http.StatusBadRequest},
+		{"No until", fmt.Sprintf("budget=%s&from=2024-01", budget.Data.ID), http.StatusBadRequest},
+		{"Invalid month", fmt.Sprintf("budget=%s&from=January&until=2024-02", budget.Data.ID), http.StatusBadRequest},
+		{"Until before from", fmt.Sprintf("budget=%s&from=2024-03&until=2024-02", budget.Data.ID), http.StatusBadRequest},
+		{"Invalid budget ID", "budget=NotAUUID&from=2024-01&until=2024-02", http.StatusBadRequest},
+		{"Budget does not exist", fmt.Sprintf("budget=%s&from=2024-01&until=2024-02", uuid.New()), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/months/range?%s", tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.MonthRangeResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
+
+func (suite *TestSuiteStandard) TestMonthsRangeDBFail() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	suite.CloseDB()
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/months/range?budget=%s&from=2024-01&until=2024-02", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusInternalServerError)
+}
+
+// TestMonthsTransfer verifies that money is moved between envelopes and the amount available to budget.
+func (suite *TestSuiteStandard) TestMonthsTransfer() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID})
+	rent := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Rent"})
+	groceries := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Groceries"})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	employer := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Employer", External: true})
+
+	month := types.NewMonth(2024, 1)
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+		Date:                 time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		AvailableFrom:        month,
+		Amount:               decimal.NewFromFloat(100),
+		SourceAccountID:      employer.Data.ID,
+		DestinationAccountID: account.Data.ID,
+	})
+
+	tests := []struct {
+		name      string
+		from      *uuid.UUID
+		to        *uuid.UUID
+		amount    float64
+		note      string
+		force     bool
+		status    int
+		available float64
+	}{
+		{"Budget rent", nil, &rent.Data.ID, 60, "", false, http.StatusOK, 40},
+		{"Available negative", nil, &groceries.Data.ID, 50, "", false, http.StatusBadRequest, 0},
+		{"Available negative forced", nil, &groceries.Data.ID, 50, "", true, http.StatusOK, -10},
+		{"Source balance negative", &rent.Data.ID, &groceries.Data.ID, 70, "", false, http.StatusBadRequest, 0},
+		{"Between envelopes", &rent.Data.ID, &groceries.Data.ID, 20, "Groceries are expensive", false, http.StatusOK, -10},
+		{"Back to available", &groceries.Data.ID, nil, 10, "", false, http.StatusOK, 0},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, "http://example.com/v4/months/transfer", v4.MonthTransfer{
+				BudgetID:       budget.Data.ID,
+				Month:          month,
+				FromEnvelopeID: tt.from,
+				ToEnvelopeID:   tt.to,
+				Amount:         decimal.NewFromFloat(tt.amount),
+				Note:           tt.note,
+				Force:          tt.force,
+			})
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var response v4.MonthResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.True(t, decimal.NewFromFloat(tt.available).Equal(response.Data.Available), "Available is %s, expected %f", response.Data.Available, tt.available)
+		})
+	}
+
+	allocations := map[uuid.UUID]float64{rent.Data.ID: 40, groceries.Data.ID: 60}
+	for id, allocation := range allocations {
+		recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/envelopes/%s/%s", id, month), "")
+		test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+		var monthConfig v4.MonthConfigResponse
+		test.DecodeResponse(suite.T(), &recorder, &monthConfig)
+		assert.True(suite.T(), decimal.NewFromFloat(allocation).Equal(monthConfig.Data.Allocation), "Allocation is %s, expected %f", monthConfig.Data.Allocation, allocation)
+
+		if id == groceries.Data.ID {
+			assert.Equal(suite.T(), "Moved 50 from available to budget\nMoved 20 from Rent: Groceries are expensive\nMoved 10 to available to budget", monthConfig.Data.Note)
+		}
+	}
+}
+
+func (suite *TestSuiteStandard) TestMonthsTransferFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID}).Data.ID})
+	otherBudget := createTestEnvelope(suite.T(), v4.EnvelopeEditable{})
+	month := types.NewMonth(2024, 1)
+
+	tests := []struct {
+		name   string
+		body   any
+		status int
+	}{
+		{"Broken body", `{ "amount": "many" }`, http.StatusBadRequest},
+		{"No month", v4.MonthTransfer{BudgetID: budget.Data.ID, ToEnvelopeID: &envelope.Data.ID, Amount: decimal.NewFromFloat(1)}, http.StatusBadRequest},
+		{"Budget does not exist", v4.MonthTransfer{BudgetID: uuid.New(), Month: month, ToEnvelopeID: &envelope.Data.ID, Amount: decimal.NewFromFloat(1)}, http.StatusNotFound},
+		{"No envelopes", v4.MonthTransfer{BudgetID: budget.Data.ID, Month: month, Amount: decimal.NewFromFloat(1)}, http.StatusBadRequest},
+		{"Same envelope", v4.MonthTransfer{BudgetID: budget.Data.ID, Month: month, FromEnvelopeID: &envelope.Data.ID, ToEnvelopeID: &envelope.Data.ID, Amount: decimal.NewFromFloat(1)}, http.StatusBadRequest},
+		{"Amount not positive", v4.MonthTransfer{BudgetID: budget.Data.ID, Month: month, ToEnvelopeID: &envelope.Data.ID}, http.StatusBadRequest},
+		{"Envelope of other budget", v4.MonthTransfer{BudgetID: budget.Data.ID, Month: month, ToEnvelopeID: &otherBudget.Data.ID, Amount: decimal.NewFromFloat(1), Force: true}, http.StatusBadRequest},
+		{"Envelope does not exist", v4.MonthTransfer{BudgetID: budget.Data.ID, Month: month, ToEnvelopeID: &uuid.UUID{1}, Amount: decimal.NewFromFloat(1), Force: true}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, "http://example.com/v4/months/transfer", tt.body)
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.MonthResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package v4
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// listSorting defines how a list of resources can be sorted.
+type listSorting struct {
+	table    string            // Table of the resource
+	fields   map[string]string // Fields that can be used in the sort parameter with the SQL expression to sort by
+	defaults string            // Sort parameter used when none is set
+}
+
+// sortKey is an SQL expression a list is sorted by.
+type sortKey struct {
+	expression string
+	descending bool
+}
+
+// listCursor is the position in a list that a page starts after.
+//
+// It is passed to clients as an opaque string.
+type listCursor struct {
+	Sort     string `json:"s"`           // Sort parameter of the list
+	Values   []any  `json:"v"`           // Values of the sort keys for the record at the position
+	Backward bool   `json:"b,omitempty"` // If the page is before the position
+}
+
+// listPage is the page of a list requested by a client.
+//
+// Pages are either selected by an offset or by a cursor. With a cursor, records
+// are selected by the values they are sorted by, which keeps deep pages fast and
+// consistent when records are created or deleted in between requests.
+type listPage struct {
+	sorting listSorting
+	sort    string
+	keys    []sortKey
+	offset  uint
+	limit   int
+	cursor  *listCursor
+}
+
+// newListPage returns the requested page of a list.
+func newListPage(sorting listSorting, sort, cursor string, offset uint, limit int) (listPage, error) {
+	page := listPage{
+		sorting: sorting,
+		sort:    sort,
+		offset:  offset,
+		limit:   limit,
+	}
+
+	if cursor != "" {
+		if offset != 0 {
+			return listPage{}, errPaginationOffsetCursor
+		}
+
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return listPage{}, errPaginationCursorInvalid
+		}
+
+		err = json.Unmarshal(raw, &page.cursor)
+		if err != nil || page.cursor == nil || page.cursor.Sort != sort {
+			return listPage{}, errPaginationCursorInvalid
+		}
+	}
+
+	if sort == "" {
+		sort = sorting.defaults
+	}
+
+	for _, field := range strings.Split(sort, ",") {
+		descending := strings.HasPrefix(field, "-")
+		expression, ok := sorting.fields[strings.TrimPrefix(field, "-")]
+		if !ok {
+			fields := make([]string, 0, len(sorting.fields))
+			for name := range sorting.fields {
+				fields = append(fields, name)
+			}
+			slices.Sort(fields)
+
+			return listPage{}, fmt.Errorf("%w, it must be one of %s, prefixed with - for descending order", errPaginationSortInvalid, strings.Join(fields, ", "))
+		}
+
+		page.keys = append(page.keys, sortKey{expression: expression, descending: descending})
+	}
+
+	// The ID is always the last key so that the order is unambiguous
+	page.keys = append(page.keys, sortKey{expression: fmt.Sprintf("%s.id", sorting.table)})
+
+	if page.cursor != nil && len(page.cursor.Values) != len(page.keys) {
+		return listPage{}, errPaginationCursorInvalid
+	}
+
+	return page, nil
+}
+
+// query returns the query for the records of the page.
+//
+// One record more than the limit is selected to determine if there are more records.
+func (p listPage) query(q *gorm.DB) *gorm.DB {
+	backward := p.cursor != nil && p.cursor.Backward
+	for _, key := range p.keys {
+		if key.descending != backward {
+			q = q.Order(fmt.Sprintf("%s DESC", key.expression))
+		} else {
+			q = q.Order(fmt.Sprintf("%s ASC", key.expression))
+		}
+	}
+
+	if p.cursor != nil {
+		// A record is after the cursor if it is after it for one key
+		// and equal for all keys before that one
+		conditions := make([]string, 0, len(p.keys))
+		var values []any
+		for i, key := range p.keys {
+			operator := ">"
+			if key.descending != backward {
+				operator = "<"
+			}
+
+			condition := make([]string, 0, i+1)
+			for _, previous := range p.keys[:i] {
+				condition = append(condition, fmt.Sprintf("%s = ?", previous.expression))
+			}
+			condition = append(condition, fmt.Sprintf("%s %s ?", key.expression, operator))
+
+			conditions = append(conditions, fmt.Sprintf("(%s)", strings.Join(condition, " AND ")))
+			values = append(values, p.cursor.Values[:i+1]...)
+		}
+
+		q = q.Where(fmt.Sprintf("(%s)", strings.Join(conditions, " OR ")), values...)
+	} else {
+		q = q.Offset(int(p.offset))
+	}
+
+	if p.limit < 0 {
+		return q.Limit(-1)
+	}
+
+	return q.Limit(p.limit + 1)
+}
+
+// paginate returns the records of the page and the pagination information for them.
+//
+// q is the query the records were selected with, records are the records selected.
+func paginate[R any](c *gin.Context, q *gorm.DB, p listPage, records []R) ([]R, Pagination, error) {
+	pagination := Pagination{
+		Offset: p.offset,
+		Limit:  p.limit,
+	}
+
+	more := p.limit >= 0 && len(records) > p.limit
+	if more {
+		records = records[:p.limit]
+	}
+
+	backward := p.cursor != nil && p.cursor.Backward
+	if backward {
+		slices.Reverse(records)
+	}
+	pagination.Count = len(records)
+
+	// Counting all records is only done for offset pagination
+	// since it needs to go through all matching records
+	if p.cursor == nil {
+		err := q.Limit(-1).Offset(-1).Count(&pagination.Total).Error
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+	}
+
+	if len(records) == 0 {
+		return records, pagination, nil
+	}
+
+	if more || backward {
+		next, err := p.link(c, records[len(records)-1], false)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		pagination.Next = &next
+	}
+
+	if (backward && more) || (!backward && (p.cursor != nil || p.offset > 0)) {
+		previous, err := p.link(c, records[0], true)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		pagination.Previous = &previous
+	}
+
+	return records, pagination, nil
+}
+
+// link returns the link to the page before or after the record.
+func (p listPage) link(c *gin.Context, record any, backward bool) (string, error) {
+	id := reflect.Indirect(reflect.ValueOf(record)).FieldByName("ID").Interface().(uuid.UUID)
+
+	// The values are read as text so that they are compared in the representation
+	// they are stored in. The database driver would parse timestamps otherwise.
+	expressions := make([]string, 0, len(p.keys))
+	for _, key := range p.keys {
+		expressions = append(expressions, fmt.Sprintf("CAST(%s AS TEXT)", key.expression))
+	}
+
+	values := make([]any, len(p.keys))
+	pointers := make([]any, len(p.keys))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	err := models.DB.
+		Table(p.sorting.table).
+		Select(strings.Join(expressions, ", ")).
+		Where(fmt.Sprintf("%s.id = ?", p.sorting.table), id).
+		Row().
+		Scan(pointers...)
+	if err != nil {
+		return "", err
+	}
+
+	for i, value := range values {
+		if b, ok := value.([]byte); ok {
+			values[i] = string(b)
+		}
+	}
+
+	raw, err := json.Marshal(listCursor{Sort: p.sort, Values: values, Backward: backward})
+	if err != nil {
+		return "", err
+	}
+
+	query := c.Request.URL.Query()
+	query.Del("offset")
+	query.Set("cursor", base64.RawURLEncoding.EncodeToString(raw))
+
+	return fmt.Sprintf("%s%s?%s", c.GetString(string(models.DBContextURL)), c.Request.URL.Path, query.Encode()), nil
+}
@@ -0,0 +1,416 @@
+package v4
+
+import (
+	"net/http"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+// RegisterPayeeRoutes registers the routes for payees with
+// the RouterGroup that is passed.
+func RegisterPayeeRoutes(r *gin.RouterGroup) {
+	// Root group
+	{
+		r.OPTIONS("", OptionsPayeeList)
+		r.GET("", httputil.NotModified, GetPayees)
+		r.POST("", CreatePayees)
+	}
+
+	// Payee with ID
+	{
+		r.OPTIONS("/:id", OptionsPayeeDetail)
+		r.GET("/:id", GetPayee)
+		r.PATCH("/:id", UpdatePayee)
+		r.DELETE("/:id", DeletePayee)
+		r.OPTIONS("/:id/merge", OptionsPayeeMerge)
+		r.POST("/:id/merge", MergePayees)
+	}
+}
+
+// getPayee returns the payee with the ID from the URI.
+//
+// Payees are external accounts, for all other accounts, a not found error is returned.
+func getPayee(c *gin.Context) (models.Account, error) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	var payee models.Account
+	err = models.DB.Where(&models.Account{External: true}).First(&payee, uri.ID).Error
+	return payee, err
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Payees
+// @Success		204
+// @Router			/v4/payees [options]
+func OptionsPayeeList(c *gin.Context) {
+	httputil.OptionsGetPost(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Payees
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/payees/{id} [options]
+func OptionsPayeeDetail(c *gin.Context) {
+	_, err := getPayee(c)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	httputil.OptionsGetPatchDelete(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Payees
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/payees/{id}/merge [options]
+func OptionsPayeeMerge(c *gin.Context) {
+	_, err := getPayee(c)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	httputil.OptionsPost(c)
+}
+
+// @Summary		Create payees
+// @Description	Creates new payees. Payees are external accounts.
+// @Tags			Payees
+// @Produce		json
+// @Success		201		{object}	PayeeCreateResponse
+// @Failure		400		{object}	PayeeCreateResponse
+// @Failure		404		{object}	PayeeCreateResponse
+// @Failure		500		{object}	PayeeCreateResponse
+// @Param			payees	body		[]PayeeEditable	true	"Payees"
+// @Router			/v4/payees [post]
+func CreatePayees(c *gin.Context) {
+	var editables []PayeeEditable
+
+	// Bind data and return error if not possible
+	err := httputil.BindData(c, &editables)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), PayeeCreateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// The final http status. Will be modified when errors occur
+	status := http.StatusCreated
+	r := PayeeCreateResponse{}
+
+	for _, editable := range editables {
+		payee := editable.model()
+		err = models.DB.WithContext(c).Create(&payee).Error
+		if err != nil {
+			status = r.appendError(err, status)
+			continue
+		}
+
+		data := newPayee(c, payee)
+		r.Data = append(r.Data, PayeeResponse{Data: &data})
+	}
+
+	c.JSON(status, r)
+}
+
+// @Summary		List payees
+// @Description	Returns a list of payees
+// @Tags			Payees
+// @Produce		json
+// @Success		200	{object}	PayeeListResponse
+// @Success		304
+// @Failure		400				{object}	PayeeListResponse
+// @Failure		500				{object}	PayeeListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/payees [get]
+// @Param			name		query	string	false	"Filter by name"
+// @Param			note		query	string	false	"Filter by note"
+// @Param			budget		query	string	false	"Filter by budget ID"
+// @Param			archived	query	bool	false	"Is the payee archived?"
+// @Param			search		query	string	false	"Search for this text in name and note"
+// @Param			offset		query	uint	false	"The offset of the first payee returned. Defaults to 0."
+// @Param			limit		query	int		false	"Maximum number of payees to return. Defaults to 50."
+// @Param			sort		query	string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name"
+// @Param			cursor		query	string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
+func GetPayees(c *gin.Context) {
+	var filter PayeeQueryFilter
+	if err := c.Bind(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, PayeeListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// Get the set parameters in the query string
+	queryFields, setFields := httputil.GetURLFields(c.Request.URL, filter)
+
+	model := filter.model()
+	q := models.DB.
+		Where(&models.Account{External: true}).
+		Where(&model, queryFields...)
+
+	q = stringFilters(models.DB, q, setFields, filter.Name, filter.Note, filter.Search)
+
+	// Default to 50 payees and set the limit
+	limit := 50
+	if slices.Contains(setFields, "Limit") {
+		limit = filter.Limit
+	}
+
+	page, err := newListPage(payeeSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, PayeeListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
+
+	var payees []models.Account
+	err = q.Find(&payees).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	payees, pagination, err := paginate(c, q, page, payees)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), PayeeListResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// When there are no resources, we want an empty list, not null
+	// Therefore, we use make to create a slice with zero elements
+	// which will be marshalled to an empty JSON array
+	data := make([]Payee, 0)
+	for _, payee := range payees {
+		data = append(data, newPayee(c, payee))
+	}
+
+	c.JSON(http.StatusOK, PayeeListResponse{
+		Data:       data,
+		Pagination: &pagination,
+	})
+}
+
+// @Summary		Get payee
+// @Description	Returns a specific payee
+// @Tags			Payees
+// @Produce		json
+// @Success		200	{object}	PayeeResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
+// @Failure		400	{object}	PayeeResponse
+// @Failure		404	{object}	PayeeResponse
+// @Failure		500	{object}	PayeeResponse
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/payees/{id} [get]
+func GetPayee(c *gin.Context) {
+	payee, err := getPayee(c)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := newPayee(c, payee)
+	c.Header("ETag", httputil.ETag(payee.UpdatedAt))
+	c.JSON(http.StatusOK, PayeeResponse{Data: &data})
+}
+
+// @Summary		Update payee
+// @Description	Updates a payee. Only values to be updated need to be specified.
+// @Tags			Payees
+// @Produce		json
+// @Success		200			{object}	PayeeResponse
+// @Failure		400			{object}	PayeeResponse
+// @Failure		404			{object}	PayeeResponse
+// @Failure		412			{object}	PayeeResponse
+// @Failure		500			{object}	PayeeResponse
+// @Param			id			path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			payee		body		PayeeEditable	true	"Payee"
+// @Param			If-Match	header		string			false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/payees/{id} [patch]
+func UpdatePayee(c *gin.Context) {
+	payee, err := getPayee(c)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	updateFields, err := httputil.GetBodyFields(c, PayeeEditable{})
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var data PayeeEditable
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &payee)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&payee).Select("", updateFields...).Updates(data.model()).Error
+	})
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// The default envelope is not reset on the payee by the update when it is removed,
+	// so the payee is loaded again
+	payee, err = getPayee(c)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	apiResource := newPayee(c, payee)
+	c.Header("ETag", httputil.ETag(payee.UpdatedAt))
+	c.JSON(http.StatusOK, PayeeResponse{Data: &apiResource})
+}
+
+// @Summary		Merge payees
+// @Description	Merges other payees into this one. Their transactions, match rules and reconciliations are moved to this payee, then they are deleted.
+// @Tags			Payees
+// @Accept			json
+// @Produce		json
+// @Success		200		{object}	PayeeResponse
+// @Failure		400		{object}	PayeeResponse
+// @Failure		404		{object}	PayeeResponse
+// @Failure		500		{object}	PayeeResponse
+// @Param			id		path		URIID		true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			merge	body		PayeeMerge	true	"Payees to merge"
+// @Router			/v4/payees/{id}/merge [post]
+func MergePayees(c *gin.Context) {
+	payee, err := getPayee(c)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var data PayeeMerge
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err = payee.MergePayees(models.DB.WithContext(c), data.PayeeIDs)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	apiResource := newPayee(c, payee)
+	c.JSON(http.StatusOK, PayeeResponse{Data: &apiResource})
+}
+
+// @Summary		Delete payee
+// @Description	Deletes a payee and moves it to the trash together with its transactions and match rules
+// @Tags			Payees
+// @Produce		json
+// @Success		204
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/payees/{id} [delete]
+func DeletePayee(c *gin.Context) {
+	payee, err := getPayee(c)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// Payees are moved to the trash together with all resources depending on them
+	var files []string
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &payee)
+		if err != nil {
+			return err
+		}
+
+		_, files, err = models.MoveToTrash(tx, &payee)
+		return err
+	})
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+	models.DeleteStoredAttachments(files)
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,181 @@
+package v4_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestPayee(t *testing.T, c v4.PayeeEditable, expectedStatus ...int) v4.PayeeResponse {
+	if c.BudgetID == uuid.Nil {
+		c.BudgetID = createTestBudget(t, v4.BudgetEditable{Name: "Testing budget"}).Data.ID
+	}
+
+	if c.Name == "" {
+		c.Name = uuid.NewString()
+	}
+
+	// Default to 201 Created as expected status
+	if len(expectedStatus) == 0 {
+		expectedStatus = append(expectedStatus, http.StatusCreated)
+	}
+
+	body := []v4.PayeeEditable{c}
+	r := test.Request(t, http.MethodPost, "http://example.com/v4/payees", body)
+	test.AssertHTTPStatus(t, &r, expectedStatus...)
+
+	var res v4.PayeeCreateResponse
+	test.DecodeResponse(t, &r, &res)
+
+	return res.Data[0]
+}
+
+// TestPayees verifies creating, listing, updating and deleting payees.
+func (suite *TestSuiteStandard) TestPayees() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID}).Data.ID})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+
+	payee := createTestPayee(suite.T(), v4.PayeeEditable{BudgetID: budget.Data.ID, Name: "Supermarket", DefaultEnvelopeID: &envelope.Data.ID})
+	assert.Equal(suite.T(), envelope.Data.ID, *payee.Data.DefaultEnvelopeID)
+
+	// Payees are external accounts
+	recorder := test.Request(suite.T(), http.MethodGet, payee.Data.Links.Account, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var asAccount v4.AccountResponse
+	test.DecodeResponse(suite.T(), &recorder, &asAccount)
+	assert.True(suite.T(), asAccount.Data.External)
+
+	// Accounts are not listed as payees and can not be requested as payees
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/payees?budget=%s", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var list v4.PayeeListResponse
+	test.DecodeResponse(suite.T(), &recorder, &list)
+	require.Len(suite.T(), list.Data, 1)
+	assert.Equal(suite.T(), payee.Data.ID, list.Data[0].ID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/payees/%s", account.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+
+	// Transactions to the payee use its default envelope
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: account.Data.ID, DestinationAccountID: payee.Data.ID, Amount: decimal.NewFromFloat(12)})
+	require.NotNil(suite.T(), transaction.Data.EnvelopeID)
+	assert.Equal(suite.T(), envelope.Data.ID, *transaction.Data.EnvelopeID)
+
+	// Renaming a payee does not touch other accounts
+	recorder = test.Request(suite.T(), http.MethodPatch, payee.Data.Links.Self, map[string]any{"name": "Corner shop", "defaultEnvelopeId": nil})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var updated v4.PayeeResponse
+	test.DecodeResponse(suite.T(), &recorder, &updated)
+	assert.Equal(suite.T(), "Corner shop", updated.Data.Name)
+	assert.Nil(suite.T(), updated.Data.DefaultEnvelopeID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, account.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var unchanged v4.AccountResponse
+	test.DecodeResponse(suite.T(), &recorder, &unchanged)
+	assert.Equal(suite.T(), "Bank", unchanged.Data.Name)
+
+	recorder = test.Request(suite.T(), http.MethodOptions, payee.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET, PATCH, DELETE", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodOptions, payee.Data.Links.Merge, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, POST", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodDelete, payee.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, transaction.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+}
+
+// TestPayeesMerge verifies that merging payees moves their transactions and match rules.
+func (suite *TestSuiteStandard) TestPayeesMerge() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	payee := createTestPayee(suite.T(), v4.PayeeEditable{BudgetID: budget.Data.ID, Name: "Amazon"})
+	duplicate := createTestPayee(suite.T(), v4.PayeeEditable{BudgetID: budget.Data.ID, Name: "AMAZON.DE"})
+
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: account.Data.ID, DestinationAccountID: duplicate.Data.ID, Amount: decimal.NewFromFloat(23)})
+	matchRule := createTestMatchRule(suite.T(), v4.MatchRuleEditable{AccountID: duplicate.Data.ID, Match: "AMAZON*"})
+
+	tests := []struct {
+		name   string
+		ids    []uuid.UUID
+		status int
+	}{
+		{"Account", []uuid.UUID{account.Data.ID}, http.StatusBadRequest},
+		{"Itself", []uuid.UUID{payee.Data.ID}, http.StatusBadRequest},
+		{"Not found", []uuid.UUID{uuid.New()}, http.StatusNotFound},
+		{"Duplicate", []uuid.UUID{duplicate.Data.ID}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodPost, payee.Data.Links.Merge, v4.PayeeMerge{PayeeIDs: tt.ids})
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+
+	recorder := test.Request(suite.T(), http.MethodGet, transaction.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var merged v4.TransactionResponse
+	test.DecodeResponse(suite.T(), &recorder, &merged)
+	assert.Equal(suite.T(), payee.Data.ID, merged.Data.DestinationAccountID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, matchRule.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var mergedMatchRule v4.MatchRuleResponse
+	test.DecodeResponse(suite.T(), &recorder, &mergedMatchRule)
+	assert.Equal(suite.T(), payee.Data.ID, mergedMatchRule.Data.AccountID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, duplicate.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+}
+
+// TestPayeesFails verifies that requests for payees fail for invalid input.
+func (suite *TestSuiteStandard) TestPayeesFails() {
+	_ = createTestPayee(suite.T(), v4.PayeeEditable{BudgetID: uuid.New()}, http.StatusNotFound)
+	_ = createTestPayee(suite.T(), v4.PayeeEditable{Currency: "Dollar"}, http.StatusBadRequest)
+
+	otherEnvelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{})
+	_ = createTestPayee(suite.T(), v4.PayeeEditable{DefaultEnvelopeID: &otherEnvelope.Data.ID}, http.StatusBadRequest)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"GET not a UUID", http.MethodGet, "/notauuid", http.StatusBadRequest},
+		{"GET not found", http.MethodGet, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"PATCH not found", http.MethodPatch, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"DELETE not found", http.MethodDelete, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"OPTIONS not found", http.MethodOptions, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"Merge not found", http.MethodPost, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/merge", http.StatusNotFound},
+		{"OPTIONS merge not found", http.MethodOptions, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/merge", http.StatusNotFound},
+		{"List invalid budget", http.MethodGet, "?budget=notauuid", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, tt.method, fmt.Sprintf("http://example.com/v4/payees%s", tt.path), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
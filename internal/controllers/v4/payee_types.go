@@ -0,0 +1,136 @@
+package v4
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PayeeEditable represents the user editable properties of a payee.
+//
+// Payees are external accounts, the endpoints for payees only handle those.
+type PayeeEditable struct {
+	Name              string     `json:"name" example:"Supermarket" default:""`                            // Name of the payee
+	Note              string     `json:"note" example:"The one around the corner" default:""`              // A longer description for the payee
+	BudgetID          uuid.UUID  `json:"budgetId" example:"550dc009-cea6-4c12-b2a5-03446eb7b7cf"`          // ID of the budget this payee belongs to
+	DefaultEnvelopeID *uuid.UUID `json:"defaultEnvelopeId" example:"c9e4ebe8-4b9b-4b4d-8b4a-6a1c0b2f0e51"` // Envelope for outgoing transactions to the payee that have none set
+	Archived          bool       `json:"archived" example:"true" default:"false"`                          // Is the payee archived?
+	Currency          string     `json:"currency" example:"USD" default:""`                                // ISO 4217 code of the currency of the payee. If empty, the currency of the budget is used
+}
+
+// model returns the database resource for the editable fields
+func (editable PayeeEditable) model() models.Account {
+	return models.Account{
+		Name:              editable.Name,
+		Note:              editable.Note,
+		BudgetID:          editable.BudgetID,
+		External:          true,
+		DefaultEnvelopeID: editable.DefaultEnvelopeID,
+		Archived:          editable.Archived,
+		Currency:          strings.ToUpper(strings.TrimSpace(editable.Currency)),
+	}
+}
+
+type PayeeLinks struct {
+	Self         string `json:"self" example:"https://example.com/api/v4/payees/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"`                       // The payee itself
+	Account      string `json:"account" example:"https://example.com/api/v4/accounts/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"`                  // The payee as an account
+	Transactions string `json:"transactions" example:"https://example.com/api/v4/transactions?account=af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"` // Transactions referencing the payee
+	MatchRules   string `json:"matchRules" example:"https://example.com/api/v4/match-rules?account=af892e10-7e0a-4fb8-b1bc-4b6d88401ed2"`    // Match rules targeting the payee
+	Merge        string `json:"merge" example:"https://example.com/api/v4/payees/af892e10-7e0a-4fb8-b1bc-4b6d88401ed2/merge"`                // Endpoint to merge other payees into this one
+}
+
+// Payee is the API v4 representation of a payee in EZ.
+type Payee struct {
+	models.DefaultModel
+	PayeeEditable
+	Links PayeeLinks `json:"links"`
+}
+
+func newPayee(c *gin.Context, model models.Account) Payee {
+	url := c.GetString(string(models.DBContextURL))
+
+	return Payee{
+		DefaultModel: model.DefaultModel,
+		PayeeEditable: PayeeEditable{
+			Name:              model.Name,
+			Note:              model.Note,
+			BudgetID:          model.BudgetID,
+			DefaultEnvelopeID: model.DefaultEnvelopeID,
+			Archived:          model.Archived,
+			Currency:          model.Currency,
+		},
+		Links: PayeeLinks{
+			Self:         fmt.Sprintf("%s/v4/payees/%s", url, model.ID),
+			Account:      fmt.Sprintf("%s/v4/accounts/%s", url, model.ID),
+			Transactions: fmt.Sprintf("%s/v4/transactions?account=%s", url, model.ID),
+			MatchRules:   fmt.Sprintf("%s/v4/match-rules?account=%s", url, model.ID),
+			Merge:        fmt.Sprintf("%s/v4/payees/%s/merge", url, model.ID),
+		},
+	}
+}
+
+type PayeeListResponse struct {
+	Data       []Payee     `json:"data"`                                                          // List of payees
+	Error      *string     `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Pagination *Pagination `json:"pagination"`                                                    // Pagination information
+}
+
+type PayeeCreateResponse struct {
+	Error *string         `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Data  []PayeeResponse `json:"data"`                                                          // List of created payees
+}
+
+func (p *PayeeCreateResponse) appendError(err error, currentStatus int) int {
+	s := err.Error()
+	p.Data = append(p.Data, PayeeResponse{Error: &s})
+
+	// The final status code is the highest HTTP status code number
+	newStatus := status(err)
+	if newStatus > currentStatus {
+		return newStatus
+	}
+
+	return currentStatus
+}
+
+type PayeeResponse struct {
+	Data  *Payee  `json:"data"`                                                          // Data for the payee
+	Error *string `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+type PayeeQueryFilter struct {
+	Name     string       `form:"name" filterField:"false"`   // Fuzzy filter for the payee name
+	Note     string       `form:"note" filterField:"false"`   // Fuzzy filter for the note
+	BudgetID ez_uuid.UUID `form:"budget"`                     // By budget ID
+	Archived bool         `form:"archived"`                   // Is the payee archived?
+	Search   string       `form:"search" filterField:"false"` // By string in name or note
+	Offset   uint         `form:"offset" filterField:"false"` // The offset of the first payee returned. Defaults to 0.
+	Limit    int          `form:"limit" filterField:"false"`  // Maximum number of payees to return. Defaults to 50.
+	Sort     string       `form:"sort" filterField:"false"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor   string       `form:"cursor" filterField:"false"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// payeeSorting defines how lists of payees can be sorted
+var payeeSorting = listSorting{
+	table: "accounts",
+	fields: map[string]string{
+		"name":      "accounts.name",
+		"createdAt": "accounts.created_at",
+	},
+	defaults: "name",
+}
+
+func (f PayeeQueryFilter) model() models.Account {
+	return models.Account{
+		BudgetID: f.BudgetID.UUID,
+		Archived: f.Archived,
+	}
+}
+
+type PayeeMerge struct {
+	PayeeIDs []uuid.UUID `json:"payeeIds" example:"4c7a0d3e-5d0c-4d8e-9b6c-2f3a1e0d9c8b"` // IDs of the payees to merge into this payee. They are deleted after their transactions and match rules have been moved
+}
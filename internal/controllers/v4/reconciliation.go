@@ -0,0 +1,404 @@
+package v4
+
+import (
+	"net/http"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+func RegisterReconciliationRoutes(r *gin.RouterGroup) {
+	{
+		r.OPTIONS("", OptionsReconciliations)
+		r.GET("", httputil.NotModified, GetReconciliations)
+		r.POST("", CreateReconciliations)
+	}
+	{
+		r.OPTIONS("/:id", OptionsReconciliationDetail)
+		r.GET("/:id", GetReconciliation)
+		r.PATCH("/:id", UpdateReconciliation)
+		r.DELETE("/:id", DeleteReconciliation)
+		r.OPTIONS("/:id/complete", OptionsReconciliationComplete)
+		r.POST("/:id/complete", CompleteReconciliation)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Reconciliations
+// @Success		204
+// @Router			/v4/reconciliations [options]
+func OptionsReconciliations(c *gin.Context) {
+	httputil.OptionsGetPost(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Reconciliations
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/reconciliations/{id} [options]
+func OptionsReconciliationDetail(c *gin.Context) {
+	resourceOptionsDetail(c, models.Reconciliation{})
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Reconciliations
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/reconciliations/{id}/complete [options]
+func OptionsReconciliationComplete(c *gin.Context) {
+	_, err := getReconciliation(c)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	httputil.OptionsPost(c)
+}
+
+// @Summary		Create reconciliations
+// @Description	Starts new reconciliations of accounts with a statement
+// @Tags			Reconciliations
+// @Produce		json
+// @Success		201				{object}	ReconciliationCreateResponse
+// @Failure		400				{object}	ReconciliationCreateResponse
+// @Failure		404				{object}	ReconciliationCreateResponse
+// @Failure		500				{object}	ReconciliationCreateResponse
+// @Param			reconciliations	body		[]ReconciliationEditable	true	"Reconciliations"
+// @Router			/v4/reconciliations [post]
+func CreateReconciliations(c *gin.Context) {
+	var reconciliations []ReconciliationEditable
+
+	// Bind data and return error if not possible
+	err := httputil.BindData(c, &reconciliations)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationCreateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// The final http status. Will be modified when errors occur
+	status := http.StatusCreated
+	r := ReconciliationCreateResponse{}
+
+	for _, create := range reconciliations {
+		reconciliation := create.model()
+		err = models.DB.WithContext(c).Create(&reconciliation).Error
+		if err != nil {
+			status = r.appendError(err, status)
+			continue
+		}
+
+		// Transform for the API and append
+		apiResource, err := newReconciliation(c, reconciliation)
+		if err != nil {
+			status = r.appendError(err, status)
+			continue
+		}
+		r.Data = append(r.Data, ReconciliationResponse{Data: &apiResource})
+	}
+
+	c.JSON(status, r)
+}
+
+// @Summary		Get reconciliations
+// @Description	Returns the history of reconciliations, most recent statement first
+// @Tags			Reconciliations
+// @Produce		json
+// @Success		200	{object}	ReconciliationListResponse
+// @Success		304
+// @Failure		400				{object}	ReconciliationListResponse
+// @Failure		500				{object}	ReconciliationListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/reconciliations [get]
+// @Param			budget	query	string	false	"Filter by budget ID"
+// @Param			account	query	string	false	"Filter by account ID"
+// @Param			state	query	string	false	"Filter by state. Either IN_PROGRESS or COMPLETED"
+// @Param			offset	query	uint	false	"The offset of the first reconciliation returned. Defaults to 0."
+// @Param			limit	query	int		false	"Maximum number of reconciliations to return. Defaults to 50."
+// @Param			sort	query	string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are statementDate, createdAt. Defaults to -statementDate,-createdAt"
+// @Param			cursor	query	string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
+func GetReconciliations(c *gin.Context) {
+	var filter ReconciliationQueryFilter
+
+	if err := c.Bind(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, ReconciliationListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	queryFields, setFields := httputil.GetURLFields(c.Request.URL, filter)
+
+	where := filter.model()
+	q := models.DB.
+		Where(&where, queryFields...)
+
+	if filter.BudgetID != ez_uuid.Nil {
+		q = q.
+			Joins("JOIN accounts on accounts.id = reconciliations.account_id").
+			Where("accounts.budget_id = ?", filter.BudgetID.UUID)
+	}
+
+	// Default to 50 reconciliations and set the limit
+	limit := 50
+	if slices.Contains(setFields, "Limit") {
+		limit = filter.Limit
+	}
+
+	page, err := newListPage(reconciliationSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, ReconciliationListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
+
+	var reconciliations []models.Reconciliation
+	err = q.Find(&reconciliations).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), ReconciliationListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	reconciliations, pagination, err := paginate(c, q, page, reconciliations)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationListResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Transform resources to their API representation
+	data := make([]Reconciliation, 0, len(reconciliations))
+	for _, reconciliation := range reconciliations {
+		apiResource, err := newReconciliation(c, reconciliation)
+		if err != nil {
+			e := err.Error()
+			c.JSON(status(err), ReconciliationListResponse{
+				Error: &e,
+			})
+			return
+		}
+		data = append(data, apiResource)
+	}
+
+	c.JSON(http.StatusOK, ReconciliationListResponse{
+		Data:       data,
+		Pagination: &pagination,
+	})
+}
+
+// @Summary		Get reconciliation
+// @Description	Returns a specific reconciliation. For reconciliations in progress, the transactions that are not reconciled yet and the difference to the statement balance are included.
+// @Tags			Reconciliations
+// @Produce		json
+// @Success		200	{object}	ReconciliationResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
+// @Failure		400	{object}	ReconciliationResponse
+// @Failure		404	{object}	ReconciliationResponse
+// @Failure		500	{object}	ReconciliationResponse
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/reconciliations/{id} [get]
+func GetReconciliation(c *gin.Context) {
+	reconciliation, err := getReconciliation(c)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	apiResource, err := newReconciliation(c, reconciliation)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	c.Header("ETag", httputil.ETag(reconciliation.UpdatedAt))
+	c.JSON(http.StatusOK, ReconciliationResponse{Data: &apiResource})
+}
+
+// @Summary		Update reconciliation
+// @Description	Updates a reconciliation that is in progress. Only values to be updated need to be specified.
+// @Tags			Reconciliations
+// @Accept			json
+// @Produce		json
+// @Success		200				{object}	ReconciliationResponse
+// @Failure		400				{object}	ReconciliationResponse
+// @Failure		404				{object}	ReconciliationResponse
+// @Failure		412				{object}	ReconciliationResponse
+// @Failure		500				{object}	ReconciliationResponse
+// @Param			id				path		URIID					true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			reconciliation	body		ReconciliationEditable	true	"Reconciliation"
+// @Param			If-Match		header		string					false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/reconciliations/{id} [patch]
+func UpdateReconciliation(c *gin.Context) {
+	reconciliation, err := getReconciliation(c)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Get the fields that are set to be updated
+	updateFields, err := httputil.GetBodyFields(c, ReconciliationEditable{})
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Bind the data for the patch
+	var data ReconciliationEditable
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &reconciliation)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&reconciliation).Select("", updateFields...).Updates(data.model()).Error
+	})
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	apiResource, err := newReconciliation(c, reconciliation)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	c.Header("ETag", httputil.ETag(reconciliation.UpdatedAt))
+	c.JSON(http.StatusOK, ReconciliationResponse{Data: &apiResource})
+}
+
+// @Summary		Complete reconciliation
+// @Description	Marks transactions as reconciled and completes the reconciliation. If the reconciled balance does not match the statement balance, completing fails unless an adjustment transaction for the difference is requested.
+// @Tags			Reconciliations
+// @Accept			json
+// @Produce		json
+// @Success		200			{object}	ReconciliationResponse
+// @Failure		400			{object}	ReconciliationResponse
+// @Failure		404			{object}	ReconciliationResponse
+// @Failure		500			{object}	ReconciliationResponse
+// @Param			id			path		URIID					true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			complete	body		ReconciliationComplete	false	"Transactions to reconcile and adjustment"
+// @Router			/v4/reconciliations/{id}/complete [post]
+func CompleteReconciliation(c *gin.Context) {
+	reconciliation, err := getReconciliation(c)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	var data ReconciliationComplete
+	if c.Request.ContentLength != 0 {
+		err = httputil.BindData(c, &data)
+		if err != nil {
+			e := err.Error()
+			c.JSON(status(err), ReconciliationResponse{
+				Error: &e,
+			})
+			return
+		}
+	}
+
+	err = reconciliation.Complete(models.DB.WithContext(c), data.TransactionIDs, data.CreateAdjustment)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	apiResource, err := newReconciliation(c, reconciliation)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), ReconciliationResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ReconciliationResponse{Data: &apiResource})
+}
+
+// @Summary		Delete reconciliation
+// @Description	Deletes a reconciliation and moves it to the trash. Transactions stay reconciled.
+// @Tags			Reconciliations
+// @Success		204
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/reconciliations/{id} [delete]
+func DeleteReconciliation(c *gin.Context) {
+	deleteResource[models.Reconciliation](c)
+}
+
+// getReconciliation returns the reconciliation for the ID in the URI.
+func getReconciliation(c *gin.Context) (models.Reconciliation, error) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		return models.Reconciliation{}, err
+	}
+
+	var reconciliation models.Reconciliation
+	err = models.DB.First(&reconciliation, uri.ID).Error
+	return reconciliation, err
+}
@@ -0,0 +1,175 @@
+package v4_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestReconciliation(t *testing.T, c v4.ReconciliationEditable, expectedStatus ...int) v4.ReconciliationResponse {
+	if c.AccountID == uuid.Nil {
+		budget := createTestBudget(t, v4.BudgetEditable{})
+		c.AccountID = createTestAccount(t, v4.AccountEditable{BudgetID: budget.Data.ID, OnBudget: true}).Data.ID
+	}
+
+	// Default to 201 Created as expected status
+	if len(expectedStatus) == 0 {
+		expectedStatus = append(expectedStatus, http.StatusCreated)
+	}
+
+	body := []v4.ReconciliationEditable{c}
+	r := test.Request(t, http.MethodPost, "http://example.com/v4/reconciliations", body)
+	test.AssertHTTPStatus(t, &r, expectedStatus...)
+
+	var res v4.ReconciliationCreateResponse
+	test.DecodeResponse(t, &r, &res)
+
+	return res.Data[0]
+}
+
+// TestReconciliationWorkflow verifies that a reconciliation lists the
+// transactions that are not reconciled and reconciles them on completion.
+func (suite *TestSuiteStandard) TestReconciliationWorkflow() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	bank := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      bank.Data.ID,
+		DestinationAccountID: shop.Data.ID,
+		Amount:               decimal.NewFromFloat(20),
+		Date:                 time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC),
+	})
+
+	reconciliation := createTestReconciliation(suite.T(), v4.ReconciliationEditable{
+		AccountID:        bank.Data.ID,
+		StatementDate:    time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+		StatementBalance: decimal.NewFromFloat(-25),
+	})
+	assert.Equal(suite.T(), models.ReconciliationStateInProgress, reconciliation.Data.State)
+	require.Len(suite.T(), reconciliation.Data.Transactions, 1)
+	assert.Equal(suite.T(), transaction.Data.ID, reconciliation.Data.Transactions[0].ID)
+	assert.True(suite.T(), decimal.NewFromFloat(-5).Equal(reconciliation.Data.Difference), "Difference is %s", reconciliation.Data.Difference)
+
+	recorder := test.Request(suite.T(), http.MethodPost, reconciliation.Data.Links.Complete, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+
+	recorder = test.Request(suite.T(), http.MethodPatch, reconciliation.Data.Links.Self, map[string]any{"statementBalance": -20})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var updated v4.ReconciliationResponse
+	test.DecodeResponse(suite.T(), &recorder, &updated)
+	assert.True(suite.T(), updated.Data.Difference.IsZero(), "Difference is %s", updated.Data.Difference)
+
+	recorder = test.Request(suite.T(), http.MethodPost, reconciliation.Data.Links.Complete, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var completed v4.ReconciliationResponse
+	test.DecodeResponse(suite.T(), &recorder, &completed)
+	assert.Equal(suite.T(), models.ReconciliationStateCompleted, completed.Data.State)
+	assert.NotNil(suite.T(), completed.Data.CompletedAt)
+	assert.Len(suite.T(), completed.Data.Transactions, 0)
+
+	recorder = test.Request(suite.T(), http.MethodGet, transaction.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var reconciled v4.TransactionResponse
+	test.DecodeResponse(suite.T(), &recorder, &reconciled)
+	assert.True(suite.T(), reconciled.Data.ReconciledSource)
+
+	recorder = test.Request(suite.T(), http.MethodPatch, reconciliation.Data.Links.Self, map[string]any{"note": "Too late"})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/reconciliations?account=%s&state=COMPLETED", bank.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var history v4.ReconciliationListResponse
+	test.DecodeResponse(suite.T(), &recorder, &history)
+	require.Len(suite.T(), history.Data, 1)
+	assert.Equal(suite.T(), reconciliation.Data.ID, history.Data[0].ID)
+}
+
+// TestReconciliationCompleteAdjustment verifies that completing with an
+// adjustment creates a transaction for the difference.
+func (suite *TestSuiteStandard) TestReconciliationCompleteAdjustment() {
+	reconciliation := createTestReconciliation(suite.T(), v4.ReconciliationEditable{
+		StatementDate:    time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+		StatementBalance: decimal.NewFromFloat(17.5),
+	})
+
+	recorder := test.Request(suite.T(), http.MethodPost, reconciliation.Data.Links.Complete, v4.ReconciliationComplete{CreateAdjustment: true})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var completed v4.ReconciliationResponse
+	test.DecodeResponse(suite.T(), &recorder, &completed)
+	require.NotNil(suite.T(), completed.Data.AdjustmentID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/transactions/%s", completed.Data.AdjustmentID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var adjustment v4.TransactionResponse
+	test.DecodeResponse(suite.T(), &recorder, &adjustment)
+	assert.Equal(suite.T(), reconciliation.Data.AccountID, adjustment.Data.DestinationAccountID)
+	assert.True(suite.T(), decimal.NewFromFloat(17.5).Equal(adjustment.Data.Amount))
+	assert.True(suite.T(), adjustment.Data.ReconciledDestination)
+}
+
+// TestReconciliationsFails verifies that requests for reconciliations fail for invalid input.
+func (suite *TestSuiteStandard) TestReconciliationsFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	external := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, External: true})
+
+	_ = createTestReconciliation(suite.T(), v4.ReconciliationEditable{AccountID: external.Data.ID}, http.StatusBadRequest)
+	_ = createTestReconciliation(suite.T(), v4.ReconciliationEditable{AccountID: uuid.New()}, http.StatusNotFound)
+
+	reconciliation := createTestReconciliation(suite.T(), v4.ReconciliationEditable{})
+	recorder := test.Request(suite.T(), http.MethodPost, reconciliation.Data.Links.Complete, v4.ReconciliationComplete{TransactionIDs: []uuid.UUID{uuid.New()}})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"GET not a UUID", http.MethodGet, "/notauuid", http.StatusBadRequest},
+		{"GET not found", http.MethodGet, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"DELETE not found", http.MethodDelete, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"OPTIONS not found", http.MethodOptions, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"OPTIONS complete not found", http.MethodOptions, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/complete", http.StatusNotFound},
+		{"Complete not found", http.MethodPost, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/complete", http.StatusNotFound},
+		{"List invalid account", http.MethodGet, "?account=notauuid", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, tt.method, fmt.Sprintf("http://example.com/v4/reconciliations%s", tt.path), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
+
+// TestReconciliationOptions verifies the allowed methods for reconciliations.
+func (suite *TestSuiteStandard) TestReconciliationOptions() {
+	reconciliation := createTestReconciliation(suite.T(), v4.ReconciliationEditable{})
+
+	recorder := test.Request(suite.T(), http.MethodOptions, reconciliation.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET, PATCH, DELETE", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodOptions, reconciliation.Data.Links.Complete, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, POST", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodDelete, reconciliation.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+}
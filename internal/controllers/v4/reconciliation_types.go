@@ -0,0 +1,168 @@
+package v4
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ReconciliationEditable struct {
+	AccountID        uuid.UUID       `json:"accountId" example:"f81566d9-af4d-4f13-9830-c62c4b5e4c7e"`                                                                                // The ID of the account that is reconciled
+	StatementDate    time.Time       `json:"statementDate" example:"2024-03-31T00:00:00Z"`                                                                                            // Date of the statement. All transactions up to and including this date are reconciled
+	StatementBalance decimal.Decimal `json:"statementBalance" example:"1250.72" minimum:"-999999999999.99999999" maximum:"999999999999.99999999" multipleOf:"0.00000001" default:"0"` // Balance of the account according to the statement
+	Note             string          `json:"note" example:"Bank statement 2024/03" default:""`                                                                                        // A note for the reconciliation
+}
+
+// model returns the database resource for the API representation of the editable fields
+func (editable ReconciliationEditable) model() models.Reconciliation {
+	return models.Reconciliation{
+		AccountID:        editable.AccountID,
+		StatementDate:    editable.StatementDate,
+		StatementBalance: editable.StatementBalance,
+		Note:             editable.Note,
+	}
+}
+
+type ReconciliationLinks struct {
+	Self     string `json:"self" example:"https://example.com/api/v4/reconciliations/438cc6c0-9baf-49fd-a75a-d76bd5cab19c"`              // The reconciliation itself
+	Complete string `json:"complete" example:"https://example.com/api/v4/reconciliations/438cc6c0-9baf-49fd-a75a-d76bd5cab19c/complete"` // Completes the reconciliation
+	Account  string `json:"account" example:"https://example.com/api/v4/accounts/c1a96ae4-80e3-4827-8ed0-c7656f224fee"`                  // The account that is reconciled
+}
+
+type Reconciliation struct {
+	models.DefaultModel
+	ReconciliationEditable
+	State             models.ReconciliationState `json:"state" example:"IN_PROGRESS"`                                 // State of the reconciliation. Either IN_PROGRESS or COMPLETED
+	CompletedAt       *time.Time                 `json:"completedAt" example:"2024-04-02T19:28:44.491514Z"`           // Time the reconciliation was completed
+	AdjustmentID      *uuid.UUID                 `json:"adjustmentId" example:"0b4ea4b5-1a8e-4c35-9fa0-1a2a1a0a4f3e"` // ID of the transaction created to balance the account with the statement on completion
+	ReconciledBalance decimal.Decimal            `json:"reconciledBalance" example:"1175.72"`                         // Balance of the account at the statement date including only reconciled transactions
+	Difference        decimal.Decimal            `json:"difference" example:"-12.5"`                                  // Difference between the statement balance and the balance if all listed transactions are reconciled
+	Transactions      []Transaction              `json:"transactions"`                                                // Transactions of the account up to the statement date that are not reconciled yet
+	Links             ReconciliationLinks        `json:"links"`                                                       // Links for the reconciliation
+}
+
+// newReconciliation returns the API v4 representation of the resource.
+//
+// For reconciliations in progress, the uncleared transactions and the difference
+// to the statement are calculated.
+func newReconciliation(c *gin.Context, model models.Reconciliation) (Reconciliation, error) {
+	url := c.GetString(string(models.DBContextURL))
+	self := fmt.Sprintf("%s/v4/reconciliations/%s", url, model.ID)
+
+	reconciliation := Reconciliation{
+		DefaultModel: model.DefaultModel,
+		ReconciliationEditable: ReconciliationEditable{
+			AccountID:        model.AccountID,
+			StatementDate:    model.StatementDate,
+			StatementBalance: model.StatementBalance,
+			Note:             model.Note,
+		},
+		State:             model.State,
+		CompletedAt:       model.CompletedAt,
+		AdjustmentID:      model.AdjustmentID,
+		ReconciledBalance: model.StatementBalance,
+		Transactions:      make([]Transaction, 0),
+		Links: ReconciliationLinks{
+			Self:     self,
+			Complete: self + "/complete",
+			Account:  fmt.Sprintf("%s/v4/accounts/%s", url, model.AccountID),
+		},
+	}
+
+	// Completed reconciliations match the statement
+	if model.State == models.ReconciliationStateCompleted {
+		return reconciliation, nil
+	}
+
+	db := models.DB.WithContext(c)
+	transactions, err := model.Transactions(db)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+
+	reconciliation.ReconciledBalance, err = model.ReconciledBalance(db)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+
+	reconciliation.Difference, err = model.Difference(db, transactions)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+
+	for _, transaction := range transactions {
+		apiResource, err := newTransaction(c, db, transaction)
+		if err != nil {
+			return Reconciliation{}, err
+		}
+		reconciliation.Transactions = append(reconciliation.Transactions, apiResource)
+	}
+
+	return reconciliation, nil
+}
+
+type ReconciliationListResponse struct {
+	Data       []Reconciliation `json:"data"`                                                          // List of resources
+	Error      *string          `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Pagination *Pagination      `json:"pagination"`                                                    // Pagination information
+}
+
+type ReconciliationCreateResponse struct {
+	Error *string                  `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Data  []ReconciliationResponse `json:"data"`                                                          // List of created resources
+}
+
+func (t *ReconciliationCreateResponse) appendError(err error, currentStatus int) int {
+	s := err.Error()
+	t.Data = append(t.Data, ReconciliationResponse{Error: &s})
+
+	// The final status code is the highest HTTP status code number
+	newStatus := status(err)
+	if newStatus > currentStatus {
+		return newStatus
+	}
+
+	return currentStatus
+}
+
+type ReconciliationResponse struct {
+	Error *string         `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Data  *Reconciliation `json:"data"`                                                          // The resource
+}
+
+type ReconciliationComplete struct {
+	TransactionIDs   []uuid.UUID `json:"transactionIds" example:"f81566d9-af4d-4f13-9830-c62c4b5e4c7e"` // IDs of the transactions to mark as reconciled. If not set, all transactions of the reconciliation are reconciled
+	CreateAdjustment bool        `json:"createAdjustment" example:"true" default:"false"`               // Create a transaction for the difference to the statement balance if there is one
+}
+
+type ReconciliationQueryFilter struct {
+	BudgetID  ez_uuid.UUID               `form:"budget" filterField:"false"` // By budget ID
+	AccountID ez_uuid.UUID               `form:"account"`                    // By account ID
+	State     models.ReconciliationState `form:"state"`                      // By state
+	Offset    uint                       `form:"offset" filterField:"false"` // The offset of the first reconciliation returned. Defaults to 0.
+	Limit     int                        `form:"limit" filterField:"false"`  // Maximum number of reconciliations to return. Defaults to 50.
+	Sort      string                     `form:"sort" filterField:"false"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor    string                     `form:"cursor" filterField:"false"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// reconciliationSorting defines how lists of reconciliations can be sorted
+var reconciliationSorting = listSorting{
+	table: "reconciliations",
+	fields: map[string]string{
+		"statementDate": "datetime(reconciliations.statement_date)",
+		"createdAt":     "reconciliations.created_at",
+	},
+	defaults: "-statementDate,-createdAt",
+}
+
+func (f ReconciliationQueryFilter) model() models.Reconciliation {
+	return models.Reconciliation{
+		AccountID: f.AccountID.UUID,
+		State:     f.State,
+	}
+}
@@ -0,0 +1,477 @@
+package v4
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"golang.org/x/exp/slices"
+)
+
+// RegisterReportRoutes registers the routes for reports with
+// the RouterGroup that is passed.
+func RegisterReportRoutes(r *gin.RouterGroup) {
+	{
+		r.OPTIONS("", OptionsReports)
+		r.GET("", GetReports)
+
+		r.OPTIONS("/payees", OptionsPayeeReport)
+		r.GET("/payees", GetPayeeReport)
+
+		r.OPTIONS("/health", OptionsHealthReport)
+		r.GET("/health", GetHealthReport)
+
+		r.OPTIONS("/tags", OptionsTagReport)
+		r.GET("/tags", GetTagReport)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Reports
+// @Success		204
+// @Router			/v4/reports [options]
+func OptionsReports(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Reports overview
+// @Description	Returns links to all available reports
+// @Tags			Reports
+// @Success		200	{object}	ReportResponse
+// @Router			/v4/reports [get]
+func GetReports(c *gin.Context) {
+	url := c.GetString(string(models.DBContextURL))
+
+	c.JSON(http.StatusOK, ReportResponse{
+		Links: ReportLinks{
+			Payees: url + "/v4/reports/payees",
+			Health: url + "/v4/reports/health",
+			Tags:   url + "/v4/reports/tags",
+		},
+	})
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Reports
+// @Success		204
+// @Router			/v4/reports/payees [options]
+func OptionsPayeeReport(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Spending by payee
+// @Description	Returns the outflow to and inflow from every external account with transactions in the specified time range
+// @Tags			Reports
+// @Produce		json
+// @Success		200			{object}	PayeeReportResponse
+// @Failure		400			{object}	PayeeReportResponse
+// @Failure		404			{object}	PayeeReportResponse
+// @Failure		500			{object}	PayeeReportResponse
+// @Param			budget		query		string			true	"ID of the budget"
+// @Param			fromDate	query		string			false	"Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			untilDate	query		string			false	"Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			envelope	query		string			false	"Filter by envelope ID"
+// @Param			category	query		string			false	"Filter by category ID"
+// @Param			sort		query		PayeeReportSort	false	"Rank by total outflow or total inflow. Defaults to OUTFLOW."
+// @Router			/v4/reports/payees [get]
+func GetPayeeReport(c *gin.Context) {
+	var filter PayeeReportQueryFilter
+	if err := c.Bind(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, PayeeReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if filter.BudgetID == ez_uuid.Nil {
+		s := errBudgetIDParameter.Error()
+		c.JSON(http.StatusBadRequest, PayeeReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = PayeeReportSortOutflow
+	}
+
+	if !slices.Contains([]PayeeReportSort{PayeeReportSortOutflow, PayeeReportSortInflow}, filter.Sort) {
+		s := errPayeeReportSortInvalid.Error()
+		c.JSON(http.StatusBadRequest, PayeeReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err := models.DB.First(&models.Budget{}, filter.BudgetID.UUID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// Each row is one transaction between an internal and an external account.
+	// AccountID is the ID of the external account, Outflow is true when the
+	// money went to the external account.
+	var rows []struct {
+		AccountID uuid.UUID
+		Amount    decimal.Decimal
+		Date      time.Time
+		Outflow   bool
+	}
+
+	// We join on the source account ID for the budget since all resources need to
+	// belong to the same budget anyways
+	q := models.DB.
+		Table("transactions").
+		Joins("JOIN accounts on accounts.id = transactions.source_account_id").
+		Joins("JOIN budgets on budgets.id = accounts.budget_id").
+		Joins("JOIN accounts AS direction_accounts_source on direction_accounts_source.id = transactions.source_account_id").
+		Joins("JOIN accounts AS direction_accounts_destination on direction_accounts_destination.id = transactions.destination_account_id").
+		Where("budgets.id = ?", filter.BudgetID.UUID).
+		Where("direction_accounts_source.external != direction_accounts_destination.external").
+		Select("IIF(direction_accounts_destination.external, direction_accounts_destination.id, direction_accounts_source.id) AS account_id, transactions.amount AS amount, transactions.date AS date, direction_accounts_destination.external AS outflow")
+
+	if !filter.FromDate.IsZero() {
+		q = q.Where("transactions.date >= date(?)", time.Date(filter.FromDate.Year(), filter.FromDate.Month(), filter.FromDate.Day(), 0, 0, 0, 0, time.UTC))
+	}
+
+	if !filter.UntilDate.IsZero() {
+		q = q.Where("transactions.date < date(?)", time.Date(filter.UntilDate.Year(), filter.UntilDate.Month(), filter.UntilDate.Day()+1, 0, 0, 0, 0, time.UTC))
+	}
+
+	if filter.EnvelopeID != ez_uuid.Nil {
+		q = q.Where("transactions.envelope_id = ?", filter.EnvelopeID.UUID)
+	}
+
+	if filter.CategoryID != ez_uuid.Nil {
+		q = q.
+			Joins("JOIN envelopes AS category_filter_envelopes on category_filter_envelopes.id = transactions.envelope_id").
+			Joins("JOIN categories AS category_filter_categories on category_filter_categories.id = category_filter_envelopes.category_id").
+			Where("category_filter_categories.id = ?", filter.CategoryID.UUID)
+	}
+
+	err = q.Find(&rows).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), PayeeReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// Aggregate the transactions per external account and month
+	reports := make(map[uuid.UUID]*PayeeReport)
+	months := make(map[uuid.UUID]map[types.Month]*PayeeReportMonth)
+	for _, row := range rows {
+		report, ok := reports[row.AccountID]
+		if !ok {
+			report = &PayeeReport{AccountID: row.AccountID}
+			reports[row.AccountID] = report
+			months[row.AccountID] = make(map[types.Month]*PayeeReportMonth)
+		}
+
+		month := types.MonthOf(row.Date)
+		reportMonth, ok := months[row.AccountID][month]
+		if !ok {
+			reportMonth = &PayeeReportMonth{Month: month}
+			months[row.AccountID][month] = reportMonth
+		}
+
+		if row.Outflow {
+			report.Outflow = report.Outflow.Add(row.Amount)
+			report.OutflowCount++
+			reportMonth.Outflow = reportMonth.Outflow.Add(row.Amount)
+			reportMonth.OutflowCount++
+		} else {
+			report.Inflow = report.Inflow.Add(row.Amount)
+			report.InflowCount++
+			reportMonth.Inflow = reportMonth.Inflow.Add(row.Amount)
+			reportMonth.InflowCount++
+		}
+	}
+
+	// Load the external accounts to get their names
+	ids := make([]uuid.UUID, 0, len(reports))
+	for id := range reports {
+		ids = append(ids, id)
+	}
+
+	var accounts []models.Account
+	if len(ids) > 0 {
+		err = models.DB.Where("id IN ?", ids).Find(&accounts).Error
+		if err != nil {
+			s := err.Error()
+			c.JSON(status(err), PayeeReportResponse{
+				Error: &s,
+			})
+			return
+		}
+	}
+
+	url := c.GetString(string(models.DBContextURL))
+
+	// When there are no resources, we want an empty list, not null
+	data := make([]PayeeReport, 0, len(accounts))
+	for _, account := range accounts {
+		report := reports[account.ID]
+		report.Name = account.Name
+
+		if report.OutflowCount > 0 {
+			report.AverageOutflow = report.Outflow.DivRound(decimal.NewFromInt(int64(report.OutflowCount)), 8)
+		}
+
+		if report.InflowCount > 0 {
+			report.AverageInflow = report.Inflow.DivRound(decimal.NewFromInt(int64(report.InflowCount)), 8)
+		}
+
+		report.Months = make([]PayeeReportMonth, 0, len(months[account.ID]))
+		for _, m := range months[account.ID] {
+			report.Months = append(report.Months, *m)
+		}
+
+		sort.Slice(report.Months, func(i, j int) bool {
+			return report.Months[i].Month.Before(report.Months[j].Month)
+		})
+
+		report.Links = PayeeReportLinks{
+			Account:      fmt.Sprintf("%s/v4/accounts/%s", url, account.ID),
+			Transactions: fmt.Sprintf("%s/v4/transactions?account=%s", url, account.ID),
+		}
+
+		data = append(data, *report)
+	}
+
+	// Rank by the requested total. Ties are broken by the other total, then by name
+	sort.Slice(data, func(i, j int) bool {
+		first, second := data[i].Outflow, data[j].Outflow
+		firstTie, secondTie := data[i].Inflow, data[j].Inflow
+		if filter.Sort == PayeeReportSortInflow {
+			first, second, firstTie, secondTie = data[i].Inflow, data[j].Inflow, data[i].Outflow, data[j].Outflow
+		}
+
+		if !first.Equal(second) {
+			return first.GreaterThan(second)
+		}
+
+		if !firstTie.Equal(secondTie) {
+			return firstTie.GreaterThan(secondTie)
+		}
+
+		return data[i].Name < data[j].Name
+	})
+
+	c.JSON(http.StatusOK, PayeeReportResponse{Data: data})
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Reports
+// @Success		204
+// @Router			/v4/reports/health [options]
+func OptionsHealthReport(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Budget health
+// @Description	Returns health metrics for a budget at the end of a month: the age of money, the days of buffer and the months in which envelopes were overspent or the amount available to budget was negative
+// @Tags			Reports
+// @Produce		json
+// @Success		200		{object}	HealthReportResponse
+// @Failure		400		{object}	HealthReportResponse
+// @Failure		404		{object}	HealthReportResponse
+// @Failure		500		{object}	HealthReportResponse
+// @Param			budget	query		string		true	"ID of the budget"
+// @Param			month	query		QueryMonth	false	"The month to calculate the metrics for. Defaults to the current month."
+// @Router			/v4/reports/health [get]
+func GetHealthReport(c *gin.Context) {
+	var filter HealthReportQueryFilter
+	if err := c.BindQuery(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, HealthReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if filter.BudgetID == ez_uuid.Nil {
+		s := errBudgetIDParameter.Error()
+		c.JSON(http.StatusBadRequest, HealthReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var budget models.Budget
+	err := models.DB.First(&budget, filter.BudgetID.UUID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), HealthReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	month := types.MonthOf(time.Now())
+	if !filter.Month.IsZero() {
+		month = types.MonthOf(filter.Month)
+	}
+
+	health, err := budget.Health(models.DB, month)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), HealthReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	url := c.GetString(string(models.DBContextURL))
+
+	c.JSON(http.StatusOK, HealthReportResponse{Data: &HealthReport{
+		BudgetHealth: health,
+		BudgetID:     budget.ID,
+		Month:        month,
+		Links: HealthReportLinks{
+			Budget: fmt.Sprintf("%s/v4/budgets/%s", url, budget.ID),
+			Month:  fmt.Sprintf("%s/v4/months?budget=%s&month=%s", url, budget.ID, month),
+		},
+	}})
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Reports
+// @Success		204
+// @Router			/v4/reports/tags [options]
+func OptionsTagReport(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Totals by tag
+// @Description	Returns the outflow to and inflow from external accounts for every tag of the budget. Transfers between internal accounts are not counted.
+// @Tags			Reports
+// @Produce		json
+// @Success		200			{object}	TagReportResponse
+// @Failure		400			{object}	TagReportResponse
+// @Failure		404			{object}	TagReportResponse
+// @Failure		500			{object}	TagReportResponse
+// @Param			budget		query		string	true	"ID of the budget"
+// @Param			fromDate	query		string	false	"Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			untilDate	query		string	false	"Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Router			/v4/reports/tags [get]
+func GetTagReport(c *gin.Context) {
+	var filter TagReportQueryFilter
+	if err := c.Bind(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, TagReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if filter.BudgetID == ez_uuid.Nil {
+		s := errBudgetIDParameter.Error()
+		c.JSON(http.StatusBadRequest, TagReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err := models.DB.First(&models.Budget{}, filter.BudgetID.UUID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var tags []models.Tag
+	err = models.DB.Where(&models.Tag{BudgetID: filter.BudgetID.UUID}).Order("name ASC").Find(&tags).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// Each row is one tagged transaction between an internal and an external account.
+	// Outflow is true when the money went to the external account.
+	var rows []struct {
+		TagID   uuid.UUID
+		Amount  decimal.Decimal
+		Outflow bool
+	}
+
+	q := models.DB.
+		Table("transaction_tags").
+		Joins("JOIN tags on tags.id = transaction_tags.tag_id").
+		Joins("JOIN transactions on transactions.id = transaction_tags.transaction_id").
+		Joins("JOIN accounts AS direction_accounts_source on direction_accounts_source.id = transactions.source_account_id").
+		Joins("JOIN accounts AS direction_accounts_destination on direction_accounts_destination.id = transactions.destination_account_id").
+		Where("tags.budget_id = ?", filter.BudgetID.UUID).
+		Where("direction_accounts_source.external != direction_accounts_destination.external").
+		Select("transaction_tags.tag_id AS tag_id, transactions.amount AS amount, direction_accounts_destination.external AS outflow")
+
+	if !filter.FromDate.IsZero() {
+		q = q.Where("transactions.date >= date(?)", time.Date(filter.FromDate.Year(), filter.FromDate.Month(), filter.FromDate.Day(), 0, 0, 0, 0, time.UTC))
+	}
+
+	if !filter.UntilDate.IsZero() {
+		q = q.Where("transactions.date < date(?)", time.Date(filter.UntilDate.Year(), filter.UntilDate.Month(), filter.UntilDate.Day()+1, 0, 0, 0, 0, time.UTC))
+	}
+
+	err = q.Find(&rows).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagReportResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	url := c.GetString(string(models.DBContextURL))
+
+	// Every tag is part of the report, even if it has no transactions
+	reports := make(map[uuid.UUID]*TagReport, len(tags))
+	data := make([]TagReport, len(tags))
+	for i, tag := range tags {
+		data[i] = TagReport{
+			TagID: tag.ID,
+			Name:  tag.Name,
+			Links: TagReportLinks{
+				Tag:          fmt.Sprintf("%s/v4/tags/%s", url, tag.ID),
+				Transactions: fmt.Sprintf("%s/v4/transactions?tags=%s", url, tag.ID),
+			},
+		}
+		reports[tag.ID] = &data[i]
+	}
+
+	for _, row := range rows {
+		report := reports[row.TagID]
+		if row.Outflow {
+			report.Outflow = report.Outflow.Add(row.Amount)
+			report.OutflowCount++
+		} else {
+			report.Inflow = report.Inflow.Add(row.Amount)
+			report.InflowCount++
+		}
+	}
+
+	c.JSON(http.StatusOK, TagReportResponse{Data: data})
+}
@@ -0,0 +1,335 @@
+package v4_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReportsPayees verifies that the payee report aggregates and ranks
+// external accounts correctly.
+func (suite *TestSuiteStandard) TestReportsPayees() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	cash := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Cash", OnBudget: true})
+	supermarket := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Supermarket", External: true})
+	employer := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Employer", External: true})
+	_ = createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Never used", External: true})
+
+	groceriesCategory := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID})
+	groceries := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: groceriesCategory.Data.ID})
+	household := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID}).Data.ID})
+
+	transactions := []struct {
+		source      uuid.UUID
+		destination uuid.UUID
+		envelope    *uuid.UUID
+		amount      float64
+		date        time.Time
+	}{
+		{cash.Data.ID, supermarket.Data.ID, &groceries.Data.ID, 20, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{cash.Data.ID, supermarket.Data.ID, &groceries.Data.ID, 30, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{cash.Data.ID, supermarket.Data.ID, &household.Data.ID, 15, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC)},
+		{supermarket.Data.ID, cash.Data.ID, &groceries.Data.ID, 5, time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{employer.Data.ID, cash.Data.ID, nil, 1000, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range transactions {
+		_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+			SourceAccountID:      tt.source,
+			DestinationAccountID: tt.destination,
+			EnvelopeID:           tt.envelope,
+			Amount:               decimal.NewFromFloat(tt.amount),
+			Date:                 tt.date,
+		})
+	}
+
+	// Default ranking is by outflow
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/payees?budget=%s", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.PayeeReportResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+
+	suite.Require().Len(response.Data, 2, "Only external accounts with transactions must be in the report")
+
+	s := response.Data[0]
+	assert.Equal(suite.T(), supermarket.Data.ID, s.AccountID)
+	assert.Equal(suite.T(), "Supermarket", s.Name)
+	assert.True(suite.T(), s.Outflow.Equal(decimal.NewFromFloat(65)), "Outflow is %s", s.Outflow)
+	assert.True(suite.T(), s.Inflow.Equal(decimal.NewFromFloat(5)), "Inflow is %s", s.Inflow)
+	assert.Equal(suite.T(), 3, s.OutflowCount)
+	assert.Equal(suite.T(), 1, s.InflowCount)
+	assert.True(suite.T(), s.AverageOutflow.Equal(decimal.RequireFromString("21.66666667")), "Average outflow is %s", s.AverageOutflow)
+	assert.True(suite.T(), s.AverageInflow.Equal(decimal.NewFromFloat(5)), "Average inflow is %s", s.AverageInflow)
+	assert.Equal(suite.T(), fmt.Sprintf("http://example.com/v4/accounts/%s", supermarket.Data.ID), s.Links.Account)
+
+	suite.Require().Len(s.Months, 2)
+	assert.Equal(suite.T(), types.NewMonth(2024, 1), s.Months[0].Month)
+	assert.True(suite.T(), s.Months[0].Outflow.Equal(decimal.NewFromFloat(50)), "January outflow is %s", s.Months[0].Outflow)
+	assert.Equal(suite.T(), 2, s.Months[0].OutflowCount)
+	assert.Equal(suite.T(), types.NewMonth(2024, 2), s.Months[1].Month)
+	assert.True(suite.T(), s.Months[1].Inflow.Equal(decimal.NewFromFloat(5)), "February inflow is %s", s.Months[1].Inflow)
+
+	assert.Equal(suite.T(), employer.Data.ID, response.Data[1].AccountID)
+
+	tests := []struct {
+		name    string
+		query   string
+		ids     []uuid.UUID
+		outflow []float64
+	}{
+		{"Sort by inflow", "sort=INFLOW", []uuid.UUID{employer.Data.ID, supermarket.Data.ID}, []float64{0, 65}},
+		{"Envelope", fmt.Sprintf("envelope=%s", groceries.Data.ID), []uuid.UUID{supermarket.Data.ID}, []float64{50}},
+		{"Category", fmt.Sprintf("category=%s", groceriesCategory.Data.ID), []uuid.UUID{supermarket.Data.ID}, []float64{50}},
+		{"From date", "fromDate=2024-02-01T00:00:00Z", []uuid.UUID{supermarket.Data.ID}, []float64{15}},
+		{"Until date", "untilDate=2024-01-31T00:00:00Z", []uuid.UUID{supermarket.Data.ID, employer.Data.ID}, []float64{50, 0}},
+		{"Empty range", "fromDate=2025-01-01T00:00:00Z", []uuid.UUID{}, []float64{}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/payees?budget=%s&%s", budget.Data.ID, tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+			var response v4.PayeeReportResponse
+			test.DecodeResponse(t, &recorder, &response)
+
+			ids := make([]uuid.UUID, 0)
+			for i, report := range response.Data {
+				ids = append(ids, report.AccountID)
+				assert.True(t, report.Outflow.Equal(decimal.NewFromFloat(tt.outflow[i])), "Outflow for %s is %s, expected %f", report.Name, report.Outflow, tt.outflow[i])
+			}
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
+
+// TestReportsPayeesFails verifies that invalid requests for the payee report fail.
+func (suite *TestSuiteStandard) TestReportsPayeesFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"No budget", "", http.StatusBadRequest},
+		{"Invalid budget ID", "budget=NotAUUID", http.StatusBadRequest},
+		{"Budget does not exist", fmt.Sprintf("budget=%s", uuid.New()), http.StatusNotFound},
+		{"Invalid sort", fmt.Sprintf("budget=%s&sort=NAME", budget.Data.ID), http.StatusBadRequest},
+		{"Invalid date", fmt.Sprintf("budget=%s&fromDate=yesterday", budget.Data.ID), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/payees?%s", tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.PayeeReportResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
+
+func (suite *TestSuiteStandard) TestReportsPayeesDBFail() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	suite.CloseDB()
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/payees?budget=%s", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusInternalServerError)
+}
+
+func (suite *TestSuiteStandard) TestReportsGet() {
+	recorder := test.Request(suite.T(), http.MethodGet, "http://example.com/v4/reports", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.ReportResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	assert.Equal(suite.T(), "http://example.com/v4/reports/payees", response.Links.Payees)
+	assert.Equal(suite.T(), "http://example.com/v4/reports/health", response.Links.Health)
+	assert.Equal(suite.T(), "http://example.com/v4/reports/tags", response.Links.Tags)
+}
+
+func (suite *TestSuiteStandard) TestReportsHealth() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	cash := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Cash", OnBudget: true})
+	employer := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Employer", External: true})
+	supermarket := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Supermarket", External: true})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID}).Data.ID})
+
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      employer.Data.ID,
+		DestinationAccountID: cash.Data.ID,
+		Amount:               decimal.NewFromFloat(300),
+		Date:                 time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		AvailableFrom:        types.NewMonth(2024, 3),
+	})
+
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      cash.Data.ID,
+		DestinationAccountID: supermarket.Data.ID,
+		EnvelopeID:           &envelope.Data.ID,
+		Amount:               decimal.NewFromFloat(100),
+		Date:                 time.Date(2024, 3, 22, 0, 0, 0, 0, time.UTC),
+	})
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/health?budget=%s&month=2024-03", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.HealthReportResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+
+	suite.Require().NotNil(response.Data)
+	assert.Equal(suite.T(), budget.Data.ID, response.Data.BudgetID)
+	assert.Equal(suite.T(), types.NewMonth(2024, 3), response.Data.Month)
+	suite.Require().NotNil(response.Data.AgeOfMoney)
+	assert.Equal(suite.T(), int64(21), *response.Data.AgeOfMoney)
+
+	// 100 spent over the 10 days since the outflow, 200 left
+	suite.Require().NotNil(response.Data.DaysOfBuffer)
+	assert.Equal(suite.T(), int64(20), *response.Data.DaysOfBuffer)
+
+	assert.Equal(suite.T(), 1, response.Data.MonthsAnalyzed)
+	assert.Equal(suite.T(), []types.Month{types.NewMonth(2024, 3)}, response.Data.OverspentMonths)
+	assert.Equal(suite.T(), []types.Month{}, response.Data.AvailableNegativeMonths)
+	assert.Equal(suite.T(), fmt.Sprintf("http://example.com/v4/months?budget=%s&month=2024-03", budget.Data.ID), response.Data.Links.Month)
+
+	// Without a month, the current month is used
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/health?budget=%s", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	assert.Equal(suite.T(), types.MonthOf(time.Now()).String(), response.Data.Month.String())
+}
+
+func (suite *TestSuiteStandard) TestReportsHealthFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"No budget", "", http.StatusBadRequest},
+		{"Invalid budget ID", "budget=NotAUUID", http.StatusBadRequest},
+		{"Budget does not exist", fmt.Sprintf("budget=%s", uuid.New()), http.StatusNotFound},
+		{"Invalid month", fmt.Sprintf("budget=%s&month=March", budget.Data.ID), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/health?%s", tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.HealthReportResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
+
+func (suite *TestSuiteStandard) TestReportsHealthDBFail() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	suite.CloseDB()
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/health?budget=%s", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusInternalServerError)
+}
+
+// TestReportsTags verifies that the tag report sums up the transactions for each tag.
+func (suite *TestSuiteStandard) TestReportsTags() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	cash := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Cash", OnBudget: true})
+	bank := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	hotel := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Hotel", External: true})
+	vacation := createTestTag(suite.T(), v4.TagEditable{BudgetID: budget.Data.ID, Name: "Vacation"})
+	unused := createTestTag(suite.T(), v4.TagEditable{BudgetID: budget.Data.ID, Name: "Unused"})
+
+	transactions := []struct {
+		source      uuid.UUID
+		destination uuid.UUID
+		amount      float64
+		date        time.Time
+	}{
+		{cash.Data.ID, hotel.Data.ID, 200, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{cash.Data.ID, hotel.Data.ID, 50, time.Date(2024, 7, 5, 0, 0, 0, 0, time.UTC)},
+		{hotel.Data.ID, cash.Data.ID, 20, time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC)},
+		{bank.Data.ID, cash.Data.ID, 300, time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range transactions {
+		_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+			SourceAccountID:      tt.source,
+			DestinationAccountID: tt.destination,
+			Amount:               decimal.NewFromFloat(tt.amount),
+			Date:                 tt.date,
+			TagIDs:               []uuid.UUID{vacation.Data.ID},
+		})
+	}
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/tags?budget=%s", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.TagReportResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+
+	suite.Require().Len(response.Data, 2, "All tags of the budget must be in the report")
+	assert.Equal(suite.T(), unused.Data.ID, response.Data[0].TagID)
+	assert.True(suite.T(), response.Data[0].Outflow.IsZero(), "Outflow for unused tag is %s", response.Data[0].Outflow)
+	assert.Equal(suite.T(), 0, response.Data[0].OutflowCount)
+
+	v := response.Data[1]
+	assert.Equal(suite.T(), vacation.Data.ID, v.TagID)
+	assert.Equal(suite.T(), "Vacation", v.Name)
+	assert.True(suite.T(), v.Outflow.Equal(decimal.NewFromFloat(250)), "Outflow is %s", v.Outflow)
+	assert.True(suite.T(), v.Inflow.Equal(decimal.NewFromFloat(20)), "Inflow is %s", v.Inflow)
+	assert.Equal(suite.T(), 2, v.OutflowCount, "Transfers between internal accounts must not be counted")
+	assert.Equal(suite.T(), 1, v.InflowCount)
+	assert.Equal(suite.T(), vacation.Data.Links.Self, v.Links.Tag)
+	assert.Equal(suite.T(), vacation.Data.Links.Transactions, v.Links.Transactions)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/tags?budget=%s&fromDate=2024-07-02T00:00:00Z&untilDate=2024-07-05T00:00:00Z", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	test.DecodeResponse(suite.T(), &recorder, &response)
+
+	suite.Require().Len(response.Data, 2)
+	assert.True(suite.T(), response.Data[1].Outflow.Equal(decimal.NewFromFloat(50)), "Outflow in date range is %s", response.Data[1].Outflow)
+	assert.True(suite.T(), response.Data[1].Inflow.IsZero(), "Inflow in date range is %s", response.Data[1].Inflow)
+}
+
+// TestReportsTagsFails verifies that invalid requests for the tag report fail.
+func (suite *TestSuiteStandard) TestReportsTagsFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"No budget", "", http.StatusBadRequest},
+		{"Invalid budget ID", "budget=NotAUUID", http.StatusBadRequest},
+		{"Budget does not exist", fmt.Sprintf("budget=%s", uuid.New()), http.StatusNotFound},
+		{"Invalid date", fmt.Sprintf("budget=%s&untilDate=tomorrow", budget.Data.ID), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/reports/tags?%s", tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.TagReportResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
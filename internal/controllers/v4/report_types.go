@@ -0,0 +1,120 @@
+package v4
+
+import (
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ReportLinks struct {
+	Payees string `json:"payees" example:"https://example.com/api/v4/reports/payees"` // URL of the payee report
+	Health string `json:"health" example:"https://example.com/api/v4/reports/health"` // URL of the budget health report
+	Tags   string `json:"tags" example:"https://example.com/api/v4/reports/tags"`     // URL of the tag report
+}
+
+type ReportResponse struct {
+	Links ReportLinks `json:"links"` // Links to all available reports
+}
+
+// swagger:enum PayeeReportSort
+type PayeeReportSort string
+
+const (
+	PayeeReportSortOutflow PayeeReportSort = "OUTFLOW"
+	PayeeReportSortInflow  PayeeReportSort = "INFLOW"
+)
+
+type PayeeReportQueryFilter struct {
+	BudgetID   ez_uuid.UUID    `form:"budget"`    // ID of the budget
+	FromDate   time.Time       `form:"fromDate"`  // From this date. Time is ignored.
+	UntilDate  time.Time       `form:"untilDate"` // Until this date. Time is ignored.
+	EnvelopeID ez_uuid.UUID    `form:"envelope"`  // ID of the envelope
+	CategoryID ez_uuid.UUID    `form:"category"`  // ID of the category
+	Sort       PayeeReportSort `form:"sort"`      // Sort by total outflow or inflow. Defaults to OUTFLOW.
+}
+
+type PayeeReportMonth struct {
+	Month        types.Month     `json:"month" example:"2024-03-01T00:00:00.000000Z"` // The month
+	Outflow      decimal.Decimal `json:"outflow" example:"83.12"`                     // Sum of all transactions to the payee in this month
+	Inflow       decimal.Decimal `json:"inflow" example:"0"`                          // Sum of all transactions from the payee in this month
+	OutflowCount int             `json:"outflowCount" example:"3"`                    // Number of transactions to the payee in this month
+	InflowCount  int             `json:"inflowCount" example:"0"`                     // Number of transactions from the payee in this month
+}
+
+type PayeeReportLinks struct {
+	Account      string `json:"account" example:"https://example.com/api/v4/accounts/17e30c52-c7a8-4f6d-a8bf-e9a4cd44a386"`                  // The external account
+	Transactions string `json:"transactions" example:"https://example.com/api/v4/transactions?account=17e30c52-c7a8-4f6d-a8bf-e9a4cd44a386"` // Transactions referencing the external account
+}
+
+// PayeeReport summarizes the money flow between the budget and one external account.
+type PayeeReport struct {
+	AccountID      uuid.UUID          `json:"accountId" example:"17e30c52-c7a8-4f6d-a8bf-e9a4cd44a386"` // ID of the external account
+	Name           string             `json:"name" example:"Supermarket"`                               // Name of the external account
+	Outflow        decimal.Decimal    `json:"outflow" example:"512.37"`                                 // Sum of all transactions to the payee
+	Inflow         decimal.Decimal    `json:"inflow" example:"12.99"`                                   // Sum of all transactions from the payee
+	OutflowCount   int                `json:"outflowCount" example:"17"`                                // Number of transactions to the payee
+	InflowCount    int                `json:"inflowCount" example:"1"`                                  // Number of transactions from the payee
+	AverageOutflow decimal.Decimal    `json:"averageOutflow" example:"30.13941176"`                     // Average amount of transactions to the payee
+	AverageInflow  decimal.Decimal    `json:"averageInflow" example:"12.99"`                            // Average amount of transactions from the payee
+	Months         []PayeeReportMonth `json:"months"`                                                   // Breakdown by month, earliest month first
+	Links          PayeeReportLinks   `json:"links"`
+}
+
+type PayeeReportResponse struct {
+	Data  []PayeeReport `json:"data"`                                                          // Payees, ranked by the sort criterion
+	Error *string       `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+type HealthReportQueryFilter struct {
+	QueryMonth
+	BudgetID ez_uuid.UUID `form:"budget"` // ID of the budget
+}
+
+type HealthReportLinks struct {
+	Budget string `json:"budget" example:"https://example.com/api/v4/budgets/550dc009-cea6-4c12-b2a5-03446eb7b7cf"`                    // The budget
+	Month  string `json:"month" example:"https://example.com/api/v4/months?budget=550dc009-cea6-4c12-b2a5-03446eb7b7cf&month=2024-03"` // The month the metrics are calculated for
+}
+
+// HealthReport contains the health metrics of a budget at the end of a month.
+type HealthReport struct {
+	models.BudgetHealth
+	BudgetID uuid.UUID         `json:"budgetId" example:"550dc009-cea6-4c12-b2a5-03446eb7b7cf"` // ID of the budget
+	Month    types.Month       `json:"month" example:"2024-03-01T00:00:00.000000Z"`             // The month the metrics are calculated for
+	Links    HealthReportLinks `json:"links"`
+}
+
+type HealthReportResponse struct {
+	Data  *HealthReport `json:"data"`                                                          // Data for the budget health report
+	Error *string       `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+type TagReportQueryFilter struct {
+	BudgetID  ez_uuid.UUID `form:"budget"`    // ID of the budget
+	FromDate  time.Time    `form:"fromDate"`  // From this date. Time is ignored.
+	UntilDate time.Time    `form:"untilDate"` // Until this date. Time is ignored.
+}
+
+type TagReportLinks struct {
+	Tag          string `json:"tag" example:"https://example.com/api/v4/tags/8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"`                       // The tag
+	Transactions string `json:"transactions" example:"https://example.com/api/v4/transactions?tags=8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"` // Transactions with the tag
+}
+
+// TagReport sums up the transactions with one tag.
+type TagReport struct {
+	TagID        uuid.UUID       `json:"tagId" example:"8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"` // ID of the tag
+	Name         string          `json:"name" example:"Vacation 2024"`                         // Name of the tag
+	Outflow      decimal.Decimal `json:"outflow" example:"1432.17"`                            // Sum of all transactions with the tag to external accounts
+	Inflow       decimal.Decimal `json:"inflow" example:"120"`                                 // Sum of all transactions with the tag from external accounts
+	OutflowCount int             `json:"outflowCount" example:"23"`                            // Number of transactions with the tag to external accounts
+	InflowCount  int             `json:"inflowCount" example:"1"`                              // Number of transactions with the tag from external accounts
+	Links        TagReportLinks  `json:"links"`
+}
+
+type TagReportResponse struct {
+	Data  []TagReport `json:"data"`                                                          // Tags of the budget, sorted by name
+	Error *string     `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
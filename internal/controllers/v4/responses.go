@@ -5,8 +5,10 @@ package v4
 
 // Pagination contains information about the pagination for collection endpoint responses.
 type Pagination struct {
-	Count  int   `json:"count" example:"25"`  // The amount of records returned in this response
-	Offset uint  `json:"offset" example:"50"` // The offset for the first record returned
-	Limit  int   `json:"limit" example:"25"`  // The maximum amount of resources to return for this request
-	Total  int64 `json:"total" example:"827"` // The total number of resources matching the query
+	Count    int     `json:"count" example:"25"`                                                    // The amount of records returned in this response
+	Offset   uint    `json:"offset" example:"50"`                                                   // The offset for the first record returned. Always 0 when paginating with a cursor
+	Limit    int     `json:"limit" example:"25"`                                                    // The maximum amount of resources to return for this request
+	Total    int64   `json:"total" example:"827"`                                                   // The total number of resources matching the query. Always 0 when paginating with a cursor
+	Next     *string `json:"next" example:"https://example.com/api/v4/transactions?cursor=eyJz"`    // The next page. Not set if this is the last page
+	Previous *string `json:"previous" example:"https://example.com/api/v4/transactions?cursor=eyJ"` // The previous page. Not set if this is the first page
 }
@@ -19,15 +19,26 @@ type Response struct {
 }
 
 type Links struct {
-	Accounts     string `json:"accounts" example:"https://example.com/api/v4/accounts"`         // URL of Account collection endpoint
-	Budgets      string `json:"budgets" example:"https://example.com/api/v4/budgets"`           // URL of Budget collection endpoint
-	Categories   string `json:"categories" example:"https://example.com/api/v4/categories"`     // URL of Category collection endpoint
-	Envelopes    string `json:"envelopes" example:"https://example.com/api/v4/envelopes"`       // URL of Envelope collection endpoint
-	Goals        string `json:"goals" example:"https://example.com/api/v4/goals"`               // URL of goal collection endpoint
-	Import       string `json:"import" example:"https://example.com/api/v4/import"`             // URL of import list endpoint
-	MatchRules   string `json:"matchRules" example:"https://example.com/api/v4/match-rules"`    // URL of Match Rule collection endpoint
-	Months       string `json:"months" example:"https://example.com/api/v4/months"`             // URL of Month endpoint
-	Transactions string `json:"transactions" example:"https://example.com/api/v4/transactions"` // URL of Transaction collection endpoint
+	Accounts        string `json:"accounts" example:"https://example.com/api/v4/accounts"`               // URL of Account collection endpoint
+	Audit           string `json:"audit" example:"https://example.com/api/v4/audit"`                     // URL of the audit log
+	Budgets         string `json:"budgets" example:"https://example.com/api/v4/budgets"`                 // URL of Budget collection endpoint
+	Categories      string `json:"categories" example:"https://example.com/api/v4/categories"`           // URL of Category collection endpoint
+	Envelopes       string `json:"envelopes" example:"https://example.com/api/v4/envelopes"`             // URL of Envelope collection endpoint
+	Events          string `json:"events" example:"https://example.com/api/v4/events"`                   // URL of the event stream
+	ExchangeRates   string `json:"exchangeRates" example:"https://example.com/api/v4/exchange-rates"`    // URL of Exchange Rate collection endpoint
+	Goals           string `json:"goals" example:"https://example.com/api/v4/goals"`                     // URL of goal collection endpoint
+	Import          string `json:"import" example:"https://example.com/api/v4/import"`                   // URL of import list endpoint
+	MatchRules      string `json:"matchRules" example:"https://example.com/api/v4/match-rules"`          // URL of Match Rule collection endpoint
+	Months          string `json:"months" example:"https://example.com/api/v4/months"`                   // URL of Month endpoint
+	Payees          string `json:"payees" example:"https://example.com/api/v4/payees"`                   // URL of Payee collection endpoint
+	Reconciliations string `json:"reconciliations" example:"https://example.com/api/v4/reconciliations"` // URL of Reconciliation collection endpoint
+	Reports         string `json:"reports" example:"https://example.com/api/v4/reports"`                 // URL of Report list endpoint
+	Search          string `json:"search" example:"https://example.com/api/v4/search"`                   // URL of the search
+	Tags            string `json:"tags" example:"https://example.com/api/v4/tags"`                       // URL of Tag collection endpoint
+	Templates       string `json:"templates" example:"https://example.com/api/v4/templates"`             // URL of budget template list endpoint
+	Transactions    string `json:"transactions" example:"https://example.com/api/v4/transactions"`       // URL of Transaction collection endpoint
+	Trash           string `json:"trash" example:"https://example.com/api/v4/trash"`                     // URL of the trash
+	Webhooks        string `json:"webhooks" example:"https://example.com/api/v4/webhooks"`               // URL of Webhook collection endpoint
 }
 
 // Get returns the link list for v4
@@ -42,15 +53,26 @@ func Get(c *gin.Context) {
 
 	c.JSON(http.StatusOK, Response{
 		Links: Links{
-			Accounts:     url + "/v4/accounts",
-			Budgets:      url + "/v4/budgets",
-			Categories:   url + "/v4/categories",
-			Envelopes:    url + "/v4/envelopes",
-			Goals:        url + "/v4/goals",
-			Import:       url + "/v4/import",
-			MatchRules:   url + "/v4/match-rules",
-			Months:       url + "/v4/months",
-			Transactions: url + "/v4/transactions",
+			Accounts:        url + "/v4/accounts",
+			Audit:           url + "/v4/audit",
+			Budgets:         url + "/v4/budgets",
+			Categories:      url + "/v4/categories",
+			Envelopes:       url + "/v4/envelopes",
+			Events:          url + "/v4/events",
+			ExchangeRates:   url + "/v4/exchange-rates",
+			Goals:           url + "/v4/goals",
+			Import:          url + "/v4/import",
+			MatchRules:      url + "/v4/match-rules",
+			Months:          url + "/v4/months",
+			Payees:          url + "/v4/payees",
+			Reconciliations: url + "/v4/reconciliations",
+			Reports:         url + "/v4/reports",
+			Search:          url + "/v4/search",
+			Tags:            url + "/v4/tags",
+			Templates:       url + "/v4/templates",
+			Transactions:    url + "/v4/transactions",
+			Trash:           url + "/v4/trash",
+			Webhooks:        url + "/v4/webhooks",
 		},
 	})
 }
@@ -24,15 +24,26 @@ func TestGet(t *testing.T) {
 	// this only tests the path, not the host
 	l := v4.Response{
 		Links: v4.Links{
-			Accounts:     "/v4/accounts",
-			Budgets:      "/v4/budgets",
-			Categories:   "/v4/categories",
-			Envelopes:    "/v4/envelopes",
-			Goals:        "/v4/goals",
-			Import:       "/v4/import",
-			MatchRules:   "/v4/match-rules",
-			Months:       "/v4/months",
-			Transactions: "/v4/transactions",
+			Accounts:        "/v4/accounts",
+			Audit:           "/v4/audit",
+			Budgets:         "/v4/budgets",
+			Categories:      "/v4/categories",
+			Envelopes:       "/v4/envelopes",
+			Events:          "/v4/events",
+			ExchangeRates:   "/v4/exchange-rates",
+			Goals:           "/v4/goals",
+			Import:          "/v4/import",
+			MatchRules:      "/v4/match-rules",
+			Months:          "/v4/months",
+			Payees:          "/v4/payees",
+			Reconciliations: "/v4/reconciliations",
+			Reports:         "/v4/reports",
+			Search:          "/v4/search",
+			Tags:            "/v4/tags",
+			Templates:       "/v4/templates",
+			Transactions:    "/v4/transactions",
+			Trash:           "/v4/trash",
+			Webhooks:        "/v4/webhooks",
 		},
 	}
 
@@ -0,0 +1,115 @@
+package v4
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterSearchRoutes registers the routes for the search with
+// the RouterGroup that is passed.
+func RegisterSearchRoutes(r *gin.RouterGroup) {
+	{
+		r.OPTIONS("", OptionsSearch)
+		r.GET("", GetSearch)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Search
+// @Success		204
+// @Router			/v4/search [options]
+func OptionsSearch(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Search
+// @Description	Searches the names of accounts, categories, envelopes and goals and the notes of transactions of a budget. All words need to match, words match as prefixes and diacritics are ignored.
+// @Tags			Search
+// @Produce		json
+// @Success		200		{object}	SearchResponse
+// @Failure		400		{object}	SearchResponse
+// @Failure		404		{object}	SearchResponse
+// @Failure		500		{object}	SearchResponse
+// @Param			budget	query		string	true	"ID of the budget"
+// @Param			q		query		string	true	"The words to search for"
+// @Param			limit	query		int		false	"Maximum number of results to return. Defaults to 50"
+// @Router			/v4/search [get]
+func GetSearch(c *gin.Context) {
+	var filter SearchQueryFilter
+	if err := c.BindQuery(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, SearchResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if filter.BudgetID == ez_uuid.Nil {
+		s := errBudgetIDParameter.Error()
+		c.JSON(http.StatusBadRequest, SearchResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	if filter.Query == "" {
+		s := errSearchQueryNotSet.Error()
+		c.JSON(http.StatusBadRequest, SearchResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	limit := 50
+	if filter.Limit < 0 {
+		s := errSearchLimitInvalid.Error()
+		c.JSON(http.StatusBadRequest, SearchResponse{
+			Error: &s,
+		})
+		return
+	} else if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+
+	err := models.DB.First(&models.Budget{}, filter.BudgetID.UUID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), SearchResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	results, err := models.Search(models.DB, filter.BudgetID.UUID, filter.Query, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), SearchResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	url := c.GetString(string(models.DBContextURL))
+
+	// When there are no results, we want an empty list, not null
+	data := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		data = append(data, SearchResult{
+			Type:  result.Type,
+			ID:    result.ResourceID,
+			Text:  result.Text,
+			Score: result.Score,
+			Links: SearchResultLinks{
+				Self: fmt.Sprintf("%s/v4/%s/%s", url, searchResultPaths[result.Type], result.ResourceID),
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, SearchResponse{Data: data})
+}
@@ -0,0 +1,116 @@
+package v4_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSearch verifies that all searchable resources are found and linked.
+func (suite *TestSuiteStandard) TestSearch() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Rent account", OnBudget: true})
+	landlord := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Landlord", External: true})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID, Name: "Housing"})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Rent"})
+	goal := createTestGoal(suite.T(), v4.GoalEditable{EnvelopeID: envelope.Data.ID, Name: "Rent deposit", Amount: decimal.NewFromFloat(1500)})
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: account.Data.ID, DestinationAccountID: landlord.Data.ID, EnvelopeID: &envelope.Data.ID, Amount: decimal.NewFromFloat(750), Note: "Rent for March"})
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/search?budget=%s&q=rent", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.SearchResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	suite.Require().Len(response.Data, 4)
+
+	// The envelope has the shortest text and is the best match
+	assert.Equal(suite.T(), envelope.Data.ID, response.Data[0].ID)
+	assert.Equal(suite.T(), "Rent", response.Data[0].Text)
+
+	links := make(map[models.SearchResultType]string)
+	for _, result := range response.Data {
+		links[result.Type] = result.Links.Self
+	}
+
+	assert.Equal(suite.T(), map[models.SearchResultType]string{
+		models.SearchResultAccount:     account.Data.Links.Self,
+		models.SearchResultEnvelope:    envelope.Data.Links.Self,
+		models.SearchResultGoal:        goal.Data.Links.Self,
+		models.SearchResultTransaction: transaction.Data.Links.Self,
+	}, links)
+
+	tests := []struct {
+		name  string
+		query string
+		ids   []uuid.UUID
+	}{
+		{"Prefix", "q=hous", []uuid.UUID{category.Data.ID}},
+		{"All words", "q=rent+march", []uuid.UUID{transaction.Data.ID}},
+		{"Case insensitive", "q=LANDLORD", []uuid.UUID{landlord.Data.ID}},
+		{"No match", "q=groceries", []uuid.UUID{}},
+		{"Limit", "q=rent&limit=1", []uuid.UUID{envelope.Data.ID}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/search?budget=%s&%s", budget.Data.ID, tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+			var response v4.SearchResponse
+			test.DecodeResponse(t, &recorder, &response)
+
+			ids := make([]uuid.UUID, 0)
+			for _, result := range response.Data {
+				ids = append(ids, result.ID)
+			}
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+
+	// Updates through the API are reflected in the results
+	recorder = test.Request(suite.T(), http.MethodPatch, envelope.Data.Links.Self, map[string]any{"name": "Apartment"})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/search?budget=%s&q=apartment", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	test.DecodeResponse(suite.T(), &recorder, &response)
+	suite.Require().Len(response.Data, 1)
+	assert.Equal(suite.T(), envelope.Data.ID, response.Data[0].ID)
+}
+
+// TestSearchFails verifies that invalid searches fail.
+func (suite *TestSuiteStandard) TestSearchFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"No budget", "q=rent", http.StatusBadRequest},
+		{"Invalid budget ID", "budget=NotAUUID&q=rent", http.StatusBadRequest},
+		{"Budget does not exist", fmt.Sprintf("budget=%s&q=rent", uuid.New()), http.StatusNotFound},
+		{"No query", fmt.Sprintf("budget=%s", budget.Data.ID), http.StatusBadRequest},
+		{"Only whitespace", fmt.Sprintf("budget=%s&q=+++", budget.Data.ID), http.StatusBadRequest},
+		{"Negative limit", fmt.Sprintf("budget=%s&q=rent&limit=-1", budget.Data.ID), http.StatusBadRequest},
+		{"Invalid limit", fmt.Sprintf("budget=%s&q=rent&limit=many", budget.Data.ID), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/search?%s", tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.SearchResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
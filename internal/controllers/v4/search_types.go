@@ -0,0 +1,40 @@
+package v4
+
+import (
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/google/uuid"
+)
+
+type SearchQueryFilter struct {
+	BudgetID ez_uuid.UUID `form:"budget"` // ID of the budget
+	Query    string       `form:"q"`      // The words to search for
+	Limit    int          `form:"limit"`  // Maximum number of results
+}
+
+type SearchResultLinks struct {
+	Self string `json:"self" example:"https://example.com/api/v4/envelopes/a0909e84-e8f9-4cb6-82a5-025dff105ff2"` // The resource
+}
+
+// SearchResult is a resource that matches a search.
+type SearchResult struct {
+	Type  models.SearchResultType `json:"type" example:"ENVELOPE"`                           // Type of the resource
+	ID    uuid.UUID               `json:"id" example:"a0909e84-e8f9-4cb6-82a5-025dff105ff2"` // ID of the resource
+	Text  string                  `json:"text" example:"Groceries"`                          // The text that matched. The note for transactions, the name for all other resources
+	Score float64                 `json:"score" example:"2.37"`                              // How well the resource matches the search. Higher is better
+	Links SearchResultLinks       `json:"links"`
+}
+
+type SearchResponse struct {
+	Data  []SearchResult `json:"data"`                                                          // Matching resources, best match first
+	Error *string        `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+// searchResultPaths are the paths of the resources by result type.
+var searchResultPaths = map[models.SearchResultType]string{
+	models.SearchResultAccount:     "accounts",
+	models.SearchResultCategory:    "categories",
+	models.SearchResultEnvelope:    "envelopes",
+	models.SearchResultGoal:        "goals",
+	models.SearchResultTransaction: "transactions",
+}
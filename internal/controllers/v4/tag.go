@@ -0,0 +1,305 @@
+package v4
+
+import (
+	"net/http"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+// RegisterTagRoutes registers the routes for tags with
+// the RouterGroup that is passed.
+func RegisterTagRoutes(r *gin.RouterGroup) {
+	// Root group
+	{
+		r.OPTIONS("", OptionsTagList)
+		r.GET("", httputil.NotModified, GetTags)
+		r.POST("", CreateTags)
+	}
+
+	// Tag with ID
+	{
+		r.OPTIONS("/:id", OptionsTagDetail)
+		r.GET("/:id", GetTag)
+		r.PATCH("/:id", UpdateTag)
+		r.DELETE("/:id", DeleteTag)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Tags
+// @Success		204
+// @Router			/v4/tags [options]
+func OptionsTagList(c *gin.Context) {
+	httputil.OptionsGetPost(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Tags
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/tags/{id} [options]
+func OptionsTagDetail(c *gin.Context) {
+	resourceOptionsDetail(c, models.Tag{})
+}
+
+// @Summary		Create tags
+// @Description	Creates new tags
+// @Tags			Tags
+// @Produce		json
+// @Success		201		{object}	TagCreateResponse
+// @Failure		400		{object}	TagCreateResponse
+// @Failure		404		{object}	TagCreateResponse
+// @Failure		500		{object}	TagCreateResponse
+// @Param			tags	body		[]TagEditable	true	"Tags"
+// @Router			/v4/tags [post]
+func CreateTags(c *gin.Context) {
+	var editables []TagEditable
+
+	// Bind data and return error if not possible
+	err := httputil.BindData(c, &editables)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TagCreateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// The final http status. Will be modified when errors occur
+	status := http.StatusCreated
+	r := TagCreateResponse{}
+
+	for _, editable := range editables {
+		tag := editable.model()
+
+		err = models.DB.WithContext(c).Create(&tag).Error
+		if err != nil {
+			status = r.appendError(err, status)
+			continue
+		}
+
+		data := newTag(c, tag)
+		r.Data = append(r.Data, TagResponse{Data: &data})
+	}
+
+	c.JSON(status, r)
+}
+
+// @Summary		Get tags
+// @Description	Returns a list of tags
+// @Tags			Tags
+// @Produce		json
+// @Success		200	{object}	TagListResponse
+// @Success		304
+// @Failure		400				{object}	TagListResponse
+// @Failure		500				{object}	TagListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/tags [get]
+// @Param			name	query	string	false	"Filter by name"
+// @Param			note	query	string	false	"Filter by note"
+// @Param			budget	query	string	false	"Filter by budget ID"
+// @Param			search	query	string	false	"Search for this text in name and note"
+// @Param			offset	query	uint	false	"The offset of the first Tag returned. Defaults to 0."
+// @Param			limit	query	int		false	"Maximum number of Tags to return. Defaults to 50."
+// @Param			sort	query	string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are name, createdAt. Defaults to name"
+// @Param			cursor	query	string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
+func GetTags(c *gin.Context) {
+	var filter TagQueryFilter
+	if err := c.Bind(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, TagListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// Get the fields that we are filtering for
+	queryFields, setFields := httputil.GetURLFields(c.Request.URL, filter)
+
+	filterModel := filter.model()
+	q := models.DB.
+		Where(&filterModel, queryFields...)
+
+	q = stringFilters(models.DB, q, setFields, filter.Name, filter.Note, filter.Search)
+
+	// Default to 50 tags and set the limit
+	limit := 50
+	if slices.Contains(setFields, "Limit") {
+		limit = filter.Limit
+	}
+
+	page, err := newListPage(tagSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, TagListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
+
+	var tags []models.Tag
+	err = q.Find(&tags).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	tags, pagination, err := paginate(c, q, page, tags)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TagListResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	data := make([]Tag, 0)
+	for _, tag := range tags {
+		data = append(data, newTag(c, tag))
+	}
+
+	c.JSON(http.StatusOK, TagListResponse{
+		Data:       data,
+		Pagination: &pagination,
+	})
+}
+
+// @Summary		Get tag
+// @Description	Returns a specific tag
+// @Tags			Tags
+// @Produce		json
+// @Success		200	{object}	TagResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
+// @Failure		400	{object}	TagResponse
+// @Failure		404	{object}	TagResponse
+// @Failure		500	{object}	TagResponse
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/tags/{id} [get]
+func GetTag(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var tag models.Tag
+	err = models.DB.First(&tag, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := newTag(c, tag)
+	c.Header("ETag", httputil.ETag(tag.UpdatedAt))
+	c.JSON(http.StatusOK, TagResponse{Data: &data})
+}
+
+// @Summary		Update tag
+// @Description	Update an existing tag. Only values to be updated need to be specified.
+// @Tags			Tags
+// @Accept			json
+// @Produce		json
+// @Success		200			{object}	TagResponse
+// @Failure		400			{object}	TagResponse
+// @Failure		404			{object}	TagResponse
+// @Failure		412			{object}	TagResponse
+// @Failure		500			{object}	TagResponse
+// @Param			id			path		URIID		true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			tag			body		TagEditable	true	"Tag"
+// @Param			If-Match	header		string		false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/tags/{id} [patch]
+func UpdateTag(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var tag models.Tag
+	err = models.DB.First(&tag, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	updateFields, err := httputil.GetBodyFields(c, TagEditable{})
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var data TagEditable
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &tag)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&tag).Select("", updateFields...).Updates(data.model()).Error
+	})
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TagResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	r := newTag(c, tag)
+	c.Header("ETag", httputil.ETag(tag.UpdatedAt))
+	c.JSON(http.StatusOK, TagResponse{Data: &r})
+}
+
+// @Summary		Delete tag
+// @Description	Deletes a tag and moves it to the trash. Transactions keep existing, but lose the tag.
+// @Tags			Tags
+// @Success		204
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/tags/{id} [delete]
+func DeleteTag(c *gin.Context) {
+	deleteResource[models.Tag](c)
+}
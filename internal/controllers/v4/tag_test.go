@@ -0,0 +1,259 @@
+package v4_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestTag(t *testing.T, c v4.TagEditable, expectedStatus ...int) v4.TagResponse {
+	if c.BudgetID == uuid.Nil {
+		c.BudgetID = createTestBudget(t, v4.BudgetEditable{Name: "Testing budget"}).Data.ID
+	}
+
+	if c.Name == "" {
+		c.Name = uuid.NewString()
+	}
+
+	// Default to 201 Created as expected status
+	if len(expectedStatus) == 0 {
+		expectedStatus = append(expectedStatus, http.StatusCreated)
+	}
+
+	r := test.Request(t, http.MethodPost, "http://example.com/v4/tags", []v4.TagEditable{c})
+	test.AssertHTTPStatus(t, &r, expectedStatus...)
+
+	var tag v4.TagCreateResponse
+	test.DecodeResponse(t, &r, &tag)
+
+	if r.Code == http.StatusCreated {
+		return tag.Data[0]
+	}
+
+	return v4.TagResponse{}
+}
+
+// TestTags verifies that tags can be created, listed, updated and deleted.
+func (suite *TestSuiteStandard) TestTags() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	tag := createTestTag(suite.T(), v4.TagEditable{BudgetID: budget.Data.ID, Name: " Vacation 2024 ", Note: "Lisbon"})
+	assert.Equal(suite.T(), "Vacation 2024", tag.Data.Name)
+	assert.Equal(suite.T(), fmt.Sprintf("http://example.com/v4/transactions?tags=%s", tag.Data.ID), tag.Data.Links.Transactions)
+
+	_ = createTestTag(suite.T(), v4.TagEditable{BudgetID: budget.Data.ID, Name: "Tax deductible"})
+	_ = createTestTag(suite.T(), v4.TagEditable{Name: "Vacation 2024"})
+
+	r := createTestTag(suite.T(), v4.TagEditable{BudgetID: budget.Data.ID, Name: "Vacation 2024"}, http.StatusBadRequest)
+	assert.Nil(suite.T(), r.Data)
+
+	recorder := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/tags?budget=%s&search=vacation", budget.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var list v4.TagListResponse
+	test.DecodeResponse(suite.T(), &recorder, &list)
+	require.Len(suite.T(), list.Data, 1)
+	assert.Equal(suite.T(), tag.Data.ID, list.Data[0].ID)
+	assert.Equal(suite.T(), int64(1), list.Pagination.Total)
+
+	recorder = test.Request(suite.T(), http.MethodPatch, tag.Data.Links.Self, map[string]any{"name": "Vacation 2025"})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var updated v4.TagResponse
+	test.DecodeResponse(suite.T(), &recorder, &updated)
+	assert.Equal(suite.T(), "Vacation 2025", updated.Data.Name)
+	assert.Equal(suite.T(), "Lisbon", updated.Data.Note)
+
+	recorder = test.Request(suite.T(), http.MethodPatch, tag.Data.Links.Self, map[string]any{"name": "Tax deductible"})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+
+	var failed v4.TagResponse
+	test.DecodeResponse(suite.T(), &recorder, &failed)
+	assert.Equal(suite.T(), models.ErrTagNameNotUnique.Error(), *failed.Error)
+
+	recorder = test.Request(suite.T(), http.MethodOptions, tag.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET, PATCH, DELETE", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodDelete, tag.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, tag.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+}
+
+// TestTagsFails verifies that requests for tags fail for invalid input.
+func (suite *TestSuiteStandard) TestTagsFails() {
+	_ = createTestTag(suite.T(), v4.TagEditable{BudgetID: uuid.New()}, http.StatusNotFound)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"GET not a UUID", http.MethodGet, "/notauuid", http.StatusBadRequest},
+		{"GET not found", http.MethodGet, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"PATCH not found", http.MethodPatch, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"DELETE not found", http.MethodDelete, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"OPTIONS not found", http.MethodOptions, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"List invalid budget", http.MethodGet, "?budget=notauuid", http.StatusBadRequest},
+		{"List invalid sort", http.MethodGet, "?sort=note", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, tt.method, fmt.Sprintf("http://example.com/v4/tags%s", tt.path), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
+
+// TestTagsTransactions verifies that transactions can be tagged and filtered by their tags.
+func (suite *TestSuiteStandard) TestTagsTransactions() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	bank := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+	vacation := createTestTag(suite.T(), v4.TagEditable{BudgetID: budget.Data.ID, Name: "Vacation"})
+	tax := createTestTag(suite.T(), v4.TagEditable{BudgetID: budget.Data.ID, Name: "Tax deductible"})
+	otherBudgetTag := createTestTag(suite.T(), v4.TagEditable{Name: "Other budget"})
+
+	both := createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      bank.Data.ID,
+		DestinationAccountID: shop.Data.ID,
+		Amount:               decimal.NewFromFloat(10),
+		TagIDs:               []uuid.UUID{vacation.Data.ID, tax.Data.ID, vacation.Data.ID},
+	})
+	assert.Equal(suite.T(), []uuid.UUID{tax.Data.ID, vacation.Data.ID}, both.Data.TagIDs, "Tags are not deduplicated and sorted by name")
+
+	vacationOnly := createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      bank.Data.ID,
+		DestinationAccountID: shop.Data.ID,
+		Amount:               decimal.NewFromFloat(20),
+		TagIDs:               []uuid.UUID{vacation.Data.ID},
+	})
+
+	untagged := createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      bank.Data.ID,
+		DestinationAccountID: shop.Data.ID,
+		Amount:               decimal.NewFromFloat(30),
+	})
+	assert.Equal(suite.T(), []uuid.UUID{}, untagged.Data.TagIDs)
+
+	// Tags of other budgets cannot be used and the transaction is not created
+	failed := createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      bank.Data.ID,
+		DestinationAccountID: shop.Data.ID,
+		Amount:               decimal.NewFromFloat(40),
+		TagIDs:               []uuid.UUID{otherBudgetTag.Data.ID},
+	}, http.StatusBadRequest)
+	assert.Equal(suite.T(), models.ErrTransactionTagBudget.Error(), *failed.Error)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []uuid.UUID
+	}{
+		{"Any", fmt.Sprintf("tags=%s,%s", vacation.Data.ID, tax.Data.ID), []uuid.UUID{both.Data.ID, vacationOnly.Data.ID}},
+		{"All", fmt.Sprintf("tags=%s,%s&tagMatch=ALL", vacation.Data.ID, tax.Data.ID), []uuid.UUID{both.Data.ID}},
+		{"All with duplicates", fmt.Sprintf("tags=%s,%s&tagMatch=ALL", vacation.Data.ID, vacation.Data.ID), []uuid.UUID{both.Data.ID, vacationOnly.Data.ID}},
+		{"Expression", "filter=tag%20%3D%20%22Tax%20deductible%22", []uuid.UUID{both.Data.ID}},
+		{"Expression negated", "filter=tag%20!%3D%20%22Vacation%22", []uuid.UUID{untagged.Data.ID}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/transactions?budget=%s&sort=amount&%s", budget.Data.ID, tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+
+			var response v4.TransactionListResponse
+			test.DecodeResponse(t, &recorder, &response)
+
+			ids := make([]uuid.UUID, 0)
+			for _, transaction := range response.Data {
+				ids = append(ids, transaction.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+
+	// Updates replace the tags, other fields are kept
+	recorder := test.Request(suite.T(), http.MethodPatch, both.Data.Links.Self, map[string]any{"tagIds": []uuid.UUID{tax.Data.ID}})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var updated v4.TransactionResponse
+	test.DecodeResponse(suite.T(), &recorder, &updated)
+	assert.Equal(suite.T(), []uuid.UUID{tax.Data.ID}, updated.Data.TagIDs)
+	assert.True(suite.T(), updated.Data.Amount.Equal(decimal.NewFromFloat(10)))
+
+	recorder = test.Request(suite.T(), http.MethodPatch, both.Data.Links.Self, map[string]any{"tagIds": []uuid.UUID{otherBudgetTag.Data.ID}, "note": "Not saved"})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+
+	recorder = test.Request(suite.T(), http.MethodGet, both.Data.Links.Self, "")
+	test.DecodeResponse(suite.T(), &recorder, &updated)
+	assert.Equal(suite.T(), []uuid.UUID{tax.Data.ID}, updated.Data.TagIDs)
+	assert.Equal(suite.T(), "", updated.Data.Note, "The update was not rolled back when setting the tags failed")
+
+	// Bulk updates set the tags for all matching transactions
+	recorder = test.Request(suite.T(), http.MethodPatch, fmt.Sprintf("http://example.com/v4/transactions?tags=%s", vacation.Data.ID), map[string]any{"tagIds": []uuid.UUID{}})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var bulk v4.TransactionBulkResponse
+	test.DecodeResponse(suite.T(), &recorder, &bulk)
+	require.Equal(suite.T(), 1, bulk.Count)
+	assert.Equal(suite.T(), vacationOnly.Data.ID, bulk.Data[0].Data.ID)
+	assert.Equal(suite.T(), []uuid.UUID{}, bulk.Data[0].Data.TagIDs)
+
+	// Deleting a tag keeps the transactions, restoring it restores the links
+	recorder = test.Request(suite.T(), http.MethodDelete, tax.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, both.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	test.DecodeResponse(suite.T(), &recorder, &updated)
+	assert.Equal(suite.T(), []uuid.UUID{}, updated.Data.TagIDs)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/trash?resource=%s", tax.Data.ID), "")
+	var trash v4.TrashEntryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &trash)
+	require.Len(suite.T(), trash.Data, 1)
+
+	recorder = test.Request(suite.T(), http.MethodPost, trash.Data[0].Links.Restore, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, both.Data.Links.Self, "")
+	test.DecodeResponse(suite.T(), &recorder, &updated)
+	assert.Equal(suite.T(), []uuid.UUID{tax.Data.ID}, updated.Data.TagIDs)
+}
+
+// TestTagsTransactionsFails verifies that invalid tag filters fail.
+func (suite *TestSuiteStandard) TestTagsTransactionsFails() {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"Invalid tag ID", "tags=Vacation"},
+		{"Empty tag ID", fmt.Sprintf("tags=%s,", uuid.New())},
+		{"Invalid match", fmt.Sprintf("tags=%s&tagMatch=SOME", uuid.New())},
+		{"Unknown expression operator", "filter=tag%20%3E%20%22Vacation%22"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/transactions?%s", tt.query), "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusBadRequest)
+
+			var response v4.TransactionListResponse
+			test.DecodeResponse(t, &recorder, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package v4
+
+import (
+	"fmt"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TagEditable represents all user configurable parameters
+type TagEditable struct {
+	Name     string    `json:"name" example:"Vacation 2024" default:""`                             // Name of the tag
+	BudgetID uuid.UUID `json:"budgetId" example:"52d967d3-33f4-4b04-9ba7-772e5ab9d0ce"`             // ID of the budget the tag belongs to
+	Note     string    `json:"note" example:"Everything we spent on the trip to Lisbon" default:""` // Notes about the tag
+}
+
+func (editable TagEditable) model() models.Tag {
+	return models.Tag{
+		BudgetID: editable.BudgetID,
+		Name:     editable.Name,
+		Note:     editable.Note,
+	}
+}
+
+type TagLinks struct {
+	Self         string `json:"self" example:"https://example.com/api/v4/tags/8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"`                      // The tag itself
+	Transactions string `json:"transactions" example:"https://example.com/api/v4/transactions?tags=8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"` // Transactions with this tag
+}
+
+type Tag struct {
+	models.DefaultModel
+	TagEditable
+	Links TagLinks `json:"links"`
+}
+
+func newTag(c *gin.Context, model models.Tag) Tag {
+	url := c.GetString(string(models.DBContextURL))
+
+	return Tag{
+		DefaultModel: model.DefaultModel,
+		TagEditable: TagEditable{
+			BudgetID: model.BudgetID,
+			Name:     model.Name,
+			Note:     model.Note,
+		},
+		Links: TagLinks{
+			Self:         fmt.Sprintf("%s/v4/tags/%s", url, model.ID),
+			Transactions: fmt.Sprintf("%s/v4/transactions?tags=%s", url, model.ID),
+		},
+	}
+}
+
+type TagListResponse struct {
+	Data       []Tag       `json:"data"`                                                          // List of tags
+	Error      *string     `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Pagination *Pagination `json:"pagination"`                                                    // Pagination information
+}
+
+type TagCreateResponse struct {
+	Data  []TagResponse `json:"data"`                                                          // List of the created tags or their respective error
+	Error *string       `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+func (t *TagCreateResponse) appendError(err error, currentStatus int) int {
+	s := err.Error()
+	t.Data = append(t.Data, TagResponse{Error: &s})
+
+	// The final status code is the highest HTTP status code number
+	newStatus := status(err)
+	if newStatus > currentStatus {
+		return newStatus
+	}
+
+	return currentStatus
+}
+
+type TagResponse struct {
+	Data  *Tag    `json:"data"`                                                          // Data for the tag
+	Error *string `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+type TagQueryFilter struct {
+	BudgetID ez_uuid.UUID `form:"budget"`                     // By ID of the budget
+	Name     string       `form:"name" filterField:"false"`   // By name
+	Note     string       `form:"note" filterField:"false"`   // By note
+	Search   string       `form:"search" filterField:"false"` // By string in name or note
+	Offset   uint         `form:"offset" filterField:"false"` // The offset of the first tag returned. Defaults to 0.
+	Limit    int          `form:"limit" filterField:"false"`  // Maximum number of tags to return. Defaults to 50.
+	Sort     string       `form:"sort" filterField:"false"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor   string       `form:"cursor" filterField:"false"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// tagSorting defines how lists of tags can be sorted
+var tagSorting = listSorting{
+	table: "tags",
+	fields: map[string]string{
+		"name":      "tags.name",
+		"createdAt": "tags.created_at",
+	},
+	defaults: "name",
+}
+
+func (f TagQueryFilter) model() models.Tag {
+	return models.Tag{
+		BudgetID: f.BudgetID.UUID,
+	}
+}
@@ -0,0 +1,95 @@
+package v4
+
+import (
+	"net/http"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/templates"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterTemplateRoutes registers the routes for budget templates with
+// the RouterGroup that is passed.
+func RegisterTemplateRoutes(r *gin.RouterGroup) {
+	{
+		r.OPTIONS("", OptionsTemplateList)
+		r.GET("", httputil.NotModified, GetTemplates)
+	}
+
+	{
+		r.OPTIONS("/:id", OptionsTemplateDetail)
+		r.GET("/:id", GetTemplate)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Templates
+// @Success		204
+// @Router			/v4/templates [options]
+func OptionsTemplateList(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Templates
+// @Success		204
+// @Param			id	path	string	true	"ID of the template"
+// @Router			/v4/templates/{id} [options]
+func OptionsTemplateDetail(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		List templates
+// @Description	Returns all built-in templates for the category and envelope structure of budgets. Templates are applied with POST /v4/budgets/{id}/template.
+// @Tags			Templates
+// @Produce		json
+// @Success		200	{object}	TemplateListResponse
+// @Success		304
+// @Failure		500				{object}	TemplateListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/templates [get]
+func GetTemplates(c *gin.Context) {
+	list, err := templates.List()
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusInternalServerError, TemplateListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, TemplateListResponse{Data: list})
+}
+
+// @Summary		Get template
+// @Description	Returns a specific template
+// @Tags			Templates
+// @Produce		json
+// @Success		200	{object}	TemplateResponse
+// @Failure		404	{object}	TemplateResponse
+// @Param			id	path		string	true	"ID of the template"
+// @Router			/v4/templates/{id} [get]
+func GetTemplate(c *gin.Context) {
+	var uri URITemplate
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TemplateResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	template, err := templates.Get(uri.ID)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TemplateResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, TemplateResponse{Data: &template})
+}
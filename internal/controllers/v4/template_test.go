@@ -0,0 +1,56 @@
+package v4_test
+
+import (
+	"net/http"
+	"testing"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTemplatesList verifies that all built-in templates are listed.
+func (suite *TestSuiteStandard) TestTemplatesList() {
+	recorder := test.Request(suite.T(), http.MethodGet, "http://example.com/v4/templates", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var response v4.TemplateListResponse
+	test.DecodeResponse(suite.T(), &recorder, &response)
+
+	suite.Require().Len(response.Data, 3)
+	assert.Equal(suite.T(), "household", response.Data[0].ID)
+	assert.Equal(suite.T(), "minimal", response.Data[1].ID)
+	assert.Equal(suite.T(), "student", response.Data[2].ID)
+
+	for _, template := range response.Data {
+		assert.NotEmpty(suite.T(), template.Name, "Template %s has no name", template.ID)
+		assert.NotEmpty(suite.T(), template.Categories, "Template %s has no categories", template.ID)
+	}
+}
+
+// TestTemplatesGet verifies that single templates are returned correctly.
+func (suite *TestSuiteStandard) TestTemplatesGet() {
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{"Existing template", "household", http.StatusOK},
+		{"Non-existing template", "does-not-exist", http.StatusNotFound},
+		{"File name", "household.json", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, http.MethodGet, "http://example.com/v4/templates/"+tt.id, "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+
+			var response v4.TemplateResponse
+			test.DecodeResponse(t, &recorder, &response)
+
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.id, response.Data.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package v4
+
+import "github.com/envelope-zero/backend/v7/internal/templates"
+
+type TemplateListResponse struct {
+	Data  []templates.Template `json:"data"`                                                          // List of templates
+	Error *string              `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+type TemplateResponse struct {
+	Data  *templates.Template `json:"data"`                                                     // Data for the template
+	Error *string             `json:"error" example:"there is no template matching your query"` // The error, if any occurred
+}
+
+type URITemplate struct {
+	ID string `uri:"id" binding:"required" example:"household"` // ID of the template
+}
@@ -15,9 +15,12 @@ func (suite *TestSuiteStandard) TestOptionsHeaderResources() {
 	}{
 		{"http://example.com/v4", "OPTIONS, GET, DELETE"},
 		{"http://example.com/v4/accounts", "OPTIONS, GET, POST"},
+		{"http://example.com/v4/audit", "OPTIONS, GET"},
 		{"http://example.com/v4/budgets", "OPTIONS, GET, POST"},
 		{"http://example.com/v4/categories", "OPTIONS, GET, POST"},
 		{"http://example.com/v4/envelopes", "OPTIONS, GET, POST"},
+		{"http://example.com/v4/exchange-rates", "OPTIONS, GET, POST"},
+		{"http://example.com/v4/exchange-rates/import", "OPTIONS, POST"},
 		{"http://example.com/v4/export", "OPTIONS, GET"},
 		{"http://example.com/v4/goals", "OPTIONS, GET, POST"},
 		{"http://example.com/v4/import", "OPTIONS, GET"},
@@ -25,7 +28,20 @@ func (suite *TestSuiteStandard) TestOptionsHeaderResources() {
 		{"http://example.com/v4/import/ynab4", "OPTIONS, POST"},
 		{"http://example.com/v4/match-rules", "OPTIONS, GET, POST"},
 		{"http://example.com/v4/months", "OPTIONS, GET, POST, DELETE"},
-		{"http://example.com/v4/transactions", "OPTIONS, GET, POST"},
+		{"http://example.com/v4/months/range", "OPTIONS, GET"},
+		{"http://example.com/v4/months/transfer", "OPTIONS, POST"},
+		{"http://example.com/v4/payees", "OPTIONS, GET, POST"},
+		{"http://example.com/v4/reconciliations", "OPTIONS, GET, POST"},
+		{"http://example.com/v4/reports", "OPTIONS, GET"},
+		{"http://example.com/v4/reports/payees", "OPTIONS, GET"},
+		{"http://example.com/v4/reports/health", "OPTIONS, GET"},
+		{"http://example.com/v4/reports/tags", "OPTIONS, GET"},
+		{"http://example.com/v4/search", "OPTIONS, GET"},
+		{"http://example.com/v4/tags", "OPTIONS, GET, POST"},
+		{"http://example.com/v4/templates", "OPTIONS, GET"},
+		{"http://example.com/v4/templates/household", "OPTIONS, GET"},
+		{"http://example.com/v4/transactions", "OPTIONS, GET, POST, PATCH, DELETE"},
+		{"http://example.com/v4/trash", "OPTIONS, GET"},
 	}
 
 	for _, tt := range optionsHeaderTests {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/storage"
 	"github.com/envelope-zero/backend/v7/test"
 	"github.com/stretchr/testify/suite"
 )
@@ -40,6 +41,8 @@ func (suite *TestSuiteStandard) SetupTest() {
 	if err != nil {
 		log.Fatalf("Database initialization failed with: %#v", err)
 	}
+
+	models.Storage = storage.Local{Directory: suite.T().TempDir()}
 }
 
 // CloseDB closes the database connection. This enables testing the handling
@@ -1,6 +1,7 @@
 package v4
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/envelope-zero/backend/v7/internal/models"
 	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
 	"gorm.io/gorm"
 )
@@ -19,8 +21,10 @@ func RegisterTransactionRoutes(r *gin.RouterGroup) {
 	// Root group
 	{
 		r.OPTIONS("", OptionsTransactions)
-		r.GET("", GetTransactions)
+		r.GET("", httputil.NotModified, GetTransactions)
 		r.POST("", CreateTransactions)
+		r.PATCH("", UpdateTransactions)
+		r.DELETE("", DeleteTransactions)
 	}
 
 	// Transaction with ID
@@ -30,6 +34,13 @@ func RegisterTransactionRoutes(r *gin.RouterGroup) {
 		r.PATCH("/:id", UpdateTransaction)
 		r.DELETE("/:id", DeleteTransaction)
 	}
+
+	// Attachments of the transaction
+	{
+		r.OPTIONS("/:id/attachments", OptionsTransactionAttachments)
+		r.GET("/:id/attachments", httputil.NotModified, GetTransactionAttachments)
+		r.POST("/:id/attachments", CreateTransactionAttachment)
+	}
 }
 
 // @Summary		Allowed HTTP verbs
@@ -38,7 +49,7 @@ func RegisterTransactionRoutes(r *gin.RouterGroup) {
 // @Success		204
 // @Router			/v4/transactions [options]
 func OptionsTransactions(c *gin.Context) {
-	httputil.OptionsGetPost(c)
+	httputil.OptionsGetPostPatchDelete(c)
 }
 
 // @Summary		Allowed HTTP verbs
@@ -59,6 +70,7 @@ func OptionsTransactionDetail(c *gin.Context) {
 // @Tags			Transactions
 // @Produce		json
 // @Success		200	{object}	TransactionResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
 // @Failure		400	{object}	TransactionResponse
 // @Failure		404	{object}	TransactionResponse
 // @Failure		500	{object}	TransactionResponse
@@ -85,7 +97,16 @@ func GetTransaction(c *gin.Context) {
 		return
 	}
 
-	data := newTransaction(c, transaction)
+	data, err := newTransaction(c, models.DB, transaction)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	c.Header("ETag", httputil.ETag(transaction.UpdatedAt))
 	c.JSON(http.StatusOK, TransactionResponse{Data: &data})
 }
 
@@ -94,8 +115,10 @@ func GetTransaction(c *gin.Context) {
 // @Tags			Transactions
 // @Produce		json
 // @Success		200	{object}	TransactionListResponse
-// @Failure		400	{object}	TransactionListResponse
-// @Failure		500	{object}	TransactionListResponse
+// @Success		304
+// @Failure		400				{object}	TransactionListResponse
+// @Failure		500				{object}	TransactionListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
 // @Router			/v4/transactions [get]
 // @Param			date					query	string					false	"Date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
 // @Param			fromDate				query	string					false	"Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
@@ -113,10 +136,17 @@ func GetTransaction(c *gin.Context) {
 // @Param			destination				query	string					false	"Filter by destination account ID"
 // @Param			direction				query	TransactionDirection	false	"Filter by direction of transaction"
 // @Param			envelope				query	string					false	"Filter by envelope ID"
+// @Param			clearedSource			query	bool					false	"Cleared state in source account. Reconciled transactions are cleared."
+// @Param			clearedDestination		query	bool					false	"Cleared state in destination account. Reconciled transactions are cleared."
 // @Param			reconciledSource		query	bool					false	"Reconcilication state in source account"
 // @Param			reconciledDestination	query	bool					false	"Reconcilication state in destination account"
+// @Param			tags					query	string					false	"Filter by tag IDs, separated by commas"
+// @Param			tagMatch				query	TagMatch				false	"Do transactions need to have any or all of the tags? Defaults to ANY"
+// @Param			filter					query	string					false	"Filter expression, e.g. amount > 50 and (envelope = 'Food' or note ~ 'pizza') and date >= 2024-01-01. Supports and, or, not and parentheses. Fields are amount, date, availableFrom, note, envelope, category, tag, budget, account, source, destination, direction, type, clearedSource, clearedDestination, reconciledSource and reconciledDestination. Resources are referenced by quoted name or ID"
 // @Param			offset					query	uint					false	"The offset of the first Transaction returned. Defaults to 0."
 // @Param			limit					query	int						false	"Maximum number of Transactions to return. Defaults to 50."
+// @Param			sort					query	string					false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are date, availableFrom, amount, createdAt. Defaults to -date,-createdAt"
+// @Param			cursor					query	string					false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
 func GetTransactions(c *gin.Context) {
 	var filter TransactionQueryFilter
 	if err := c.Bind(&filter); err != nil {
@@ -130,8 +160,42 @@ func GetTransactions(c *gin.Context) {
 	// Get the fields set in the filter
 	queryFields, setFields := httputil.GetURLFields(c.Request.URL, filter)
 
-	// Convert the QueryFilter to a Create struct
-	model, err := filter.model()
+	q, err := transactionQuery(models.DB, filter, queryFields, setFields)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionListResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Default to 50 transactions and set the limit
+	limit := 50
+	if slices.Contains(setFields, "Limit") {
+		limit = filter.Limit
+	}
+
+	page, err := newListPage(transactionSorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, TransactionListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
+
+	var transactions []models.Transaction
+	err = q.Find(&transactions).Error
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionListResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	transactions, pagination, err := paginate(c, q, page, transactions)
 	if err != nil {
 		e := err.Error()
 		c.JSON(status(err), TransactionListResponse{
@@ -140,8 +204,436 @@ func GetTransactions(c *gin.Context) {
 		return
 	}
 
-	var q *gorm.DB
-	q = models.DB.Order("datetime(transactions.date) DESC, datetime(transactions.created_at) DESC").Where(&model, queryFields...)
+	data := make([]Transaction, 0)
+	for _, transaction := range transactions {
+		apiResource, err := newTransaction(c, models.DB, transaction)
+		if err != nil {
+			e := err.Error()
+			c.JSON(status(err), TransactionListResponse{
+				Error: &e,
+			})
+			return
+		}
+		data = append(data, apiResource)
+	}
+
+	c.JSON(http.StatusOK, TransactionListResponse{
+		Data:       data,
+		Pagination: &pagination,
+	})
+}
+
+// @Summary		Create transactions
+// @Description	Creates transactions from the list of submitted transaction data. The response code is the highest response code number that a single transaction creation would have caused. If it is not equal to 201, at least one transaction has an error.
+// @Tags			Transactions
+// @Produce		json
+// @Success		201				{object}	TransactionCreateResponse
+// @Failure		400				{object}	TransactionCreateResponse
+// @Failure		404				{object}	TransactionCreateResponse
+// @Failure		500				{object}	TransactionCreateResponse
+// @Param			transactions	body		[]TransactionEditable	true	"Transactions"
+// @Router			/v4/transactions [post]
+func CreateTransactions(c *gin.Context) {
+	var editables []TransactionEditable
+
+	// Bind data and return error if not possible
+	err := httputil.BindData(c, &editables)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionCreateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// The final http status. Will be modified when errors occur
+	status := http.StatusCreated
+	r := TransactionCreateResponse{}
+
+	for _, editable := range editables {
+		transaction := editable.model()
+		err := models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+			err := tx.Create(&transaction).Error
+			if err != nil || len(editable.TagIDs) == 0 {
+				return err
+			}
+
+			return models.SetTransactionTags(tx, transaction.ID, editable.TagIDs)
+		})
+		// Append the error
+		if err != nil {
+			status = r.appendError(err, status)
+			continue
+		}
+
+		data, err := newTransaction(c, models.DB, transaction)
+		if err != nil {
+			status = r.appendError(err, status)
+			continue
+		}
+		r.Data = append(r.Data, TransactionResponse{Data: &data})
+	}
+
+	c.JSON(status, r)
+}
+
+// @Summary		Update transactions
+// @Description	Updates all transactions matching the query parameters and the IDs in the body. Only values to be updated need to be specified. All transactions are updated in one database transaction, if any update fails, no transaction is updated. The response code is the highest response code number that a single update would have caused.
+// @Tags			Transactions
+// @Accept			json
+// @Produce		json
+// @Success		200						{object}	TransactionBulkResponse
+// @Failure		400						{object}	TransactionBulkResponse
+// @Failure		404						{object}	TransactionBulkResponse
+// @Failure		500						{object}	TransactionBulkResponse
+// @Param			transactions			body		TransactionBulkUpdate	true	"Values to update and optionally the IDs of the transactions"
+// @Param			date					query	string					false	"Date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			fromDate				query	string					false	"Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			untilDate				query	string					false	"Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			availableFromDate		query	string					false	"Availability date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			availableFromFromDate	query	string					false	"Transactions available at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			availableFromUntilDate	query	string					false	"Transactions available before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			amount					query	string					false	"Filter by amount"
+// @Param			amountLessOrEqual		query	string					false	"Amount less than or equal to this"
+// @Param			amountMoreOrEqual		query	string					false	"Amount more than or equal to this"
+// @Param			note					query	string					false	"Filter by note"
+// @Param			budget					query	string					false	"Filter by budget ID"
+// @Param			account					query	string					false	"Filter by ID of associated account, regardeless of source or destination"
+// @Param			source					query	string					false	"Filter by source account ID"
+// @Param			destination				query	string					false	"Filter by destination account ID"
+// @Param			direction				query	TransactionDirection	false	"Filter by direction of transaction"
+// @Param			envelope				query	string					false	"Filter by envelope ID"
+// @Param			clearedSource			query	bool					false	"Cleared state in source account. Reconciled transactions are cleared."
+// @Param			clearedDestination		query	bool					false	"Cleared state in destination account. Reconciled transactions are cleared."
+// @Param			reconciledSource		query	bool					false	"Reconcilication state in source account"
+// @Param			reconciledDestination	query	bool					false	"Reconcilication state in destination account"
+// @Param			tags					query	string					false	"Filter by tag IDs, separated by commas"
+// @Param			tagMatch				query	TagMatch				false	"Do transactions need to have any or all of the tags? Defaults to ANY"
+// @Param			filter					query	string					false	"Filter expression, e.g. amount > 50 and (envelope = 'Food' or note ~ 'pizza') and date >= 2024-01-01. Supports and, or, not and parentheses. Fields are amount, date, availableFrom, note, envelope, category, tag, budget, account, source, destination, direction, type, clearedSource, clearedDestination, reconciledSource and reconciledDestination. Resources are referenced by quoted name or ID"
+// @Param			dryRun					query	bool					false	"Only report what would be changed without changing anything"
+// @Router			/v4/transactions [patch]
+func UpdateTransactions(c *gin.Context) {
+	var options TransactionBulkQuery
+	err := c.ShouldBindQuery(&options)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionBulkResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Get the fields that are set to be updated
+	updateFields, err := httputil.GetBodyFields(c, TransactionEditable{})
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionBulkResponse{
+			Error: &e,
+		})
+		return
+	}
+	updateFields, setTags := transactionTagFields(updateFields)
+
+	var update TransactionBulkUpdate
+	err = httputil.BindData(c, &update)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionBulkResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Use a transaction so that we can roll back if errors happen
+	tx := models.DB.WithContext(c).Begin()
+
+	transactions, err := bulkTransactions(c, tx, update.IDs)
+	if err != nil {
+		tx.Rollback()
+		e := err.Error()
+		c.JSON(status(err), TransactionBulkResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// The final http status. Will be modified when errors occur
+	status := http.StatusOK
+	r := TransactionBulkResponse{Count: len(transactions), Data: []TransactionResponse{}}
+
+	for _, transaction := range transactions {
+		model := update.model()
+
+		// If the amount set via the API request is not existent or
+		// is 0, we use the old amount
+		if model.Amount.IsZero() {
+			model.Amount = transaction.Amount
+		}
+
+		original, err := newTransaction(c, tx, transaction)
+		if err != nil {
+			status = r.appendError(err, status, Transaction{DefaultModel: transaction.DefaultModel})
+			continue
+		}
+
+		if !setTags || len(updateFields) > 0 {
+			err = tx.Model(&transaction).Select("", updateFields...).Updates(model).Error
+		}
+
+		if err == nil && setTags {
+			err = models.SetTransactionTags(tx, transaction.ID, update.TagIDs)
+		}
+
+		if err != nil {
+			status = r.appendError(err, status, original)
+			continue
+		}
+
+		data, err := newTransaction(c, tx, transaction)
+		if err != nil {
+			status = r.appendError(err, status, original)
+			continue
+		}
+		r.Data = append(r.Data, TransactionResponse{Data: &data})
+	}
+
+	commitBulk(c, tx, options, status, r, nil)
+}
+
+// @Summary		Delete transactions
+// @Description	Deletes all transactions matching the query parameters and the IDs in the body and moves them to the trash. All transactions are deleted in one database transaction, if any deletion fails, no transaction is deleted.
+// @Tags			Transactions
+// @Accept			json
+// @Produce		json
+// @Success		200						{object}	TransactionBulkResponse
+// @Failure		400						{object}	TransactionBulkResponse
+// @Failure		404						{object}	TransactionBulkResponse
+// @Failure		500						{object}	TransactionBulkResponse
+// @Param			transactions			body		TransactionBulkDelete	false	"IDs of the transactions"
+// @Param			date					query	string					false	"Date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			fromDate				query	string					false	"Transactions at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			untilDate				query	string					false	"Transactions before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			availableFromDate		query	string					false	"Availability date of the transaction. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			availableFromFromDate	query	string					false	"Transactions available at and after this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			availableFromUntilDate	query	string					false	"Transactions available before and at this date. Ignores exact time, matches on the day of the RFC3339 timestamp provided."
+// @Param			amount					query	string					false	"Filter by amount"
+// @Param			amountLessOrEqual		query	string					false	"Amount less than or equal to this"
+// @Param			amountMoreOrEqual		query	string					false	"Amount more than or equal to this"
+// @Param			note					query	string					false	"Filter by note"
+// @Param			budget					query	string					false	"Filter by budget ID"
+// @Param			account					query	string					false	"Filter by ID of associated account, regardeless of source or destination"
+// @Param			source					query	string					false	"Filter by source account ID"
+// @Param			destination				query	string					false	"Filter by destination account ID"
+// @Param			direction				query	TransactionDirection	false	"Filter by direction of transaction"
+// @Param			envelope				query	string					false	"Filter by envelope ID"
+// @Param			clearedSource			query	bool					false	"Cleared state in source account. Reconciled transactions are cleared."
+// @Param			clearedDestination		query	bool					false	"Cleared state in destination account. Reconciled transactions are cleared."
+// @Param			reconciledSource		query	bool					false	"Reconcilication state in source account"
+// @Param			reconciledDestination	query	bool					false	"Reconcilication state in destination account"
+// @Param			tags					query	string					false	"Filter by tag IDs, separated by commas"
+// @Param			tagMatch				query	TagMatch				false	"Do transactions need to have any or all of the tags? Defaults to ANY"
+// @Param			filter					query	string					false	"Filter expression, e.g. amount > 50 and (envelope = 'Food' or note ~ 'pizza') and date >= 2024-01-01. Supports and, or, not and parentheses. Fields are amount, date, availableFrom, note, envelope, category, tag, budget, account, source, destination, direction, type, clearedSource, clearedDestination, reconciledSource and reconciledDestination. Resources are referenced by quoted name or ID"
+// @Param			dryRun					query	bool					false	"Only report what would be changed without changing anything"
+// @Router			/v4/transactions [delete]
+func DeleteTransactions(c *gin.Context) {
+	var options TransactionBulkQuery
+	err := c.ShouldBindQuery(&options)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionBulkResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// The body is optional, transactions can be selected by query parameters only
+	var selection TransactionBulkDelete
+	err = httputil.BindData(c, &selection)
+	if err != nil && !errors.Is(err, httputil.ErrRequestBodyEmpty) {
+		e := err.Error()
+		c.JSON(status(err), TransactionBulkResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Use a transaction so that we can roll back if errors happen
+	tx := models.DB.WithContext(c).Begin()
+
+	transactions, err := bulkTransactions(c, tx, selection.IDs)
+	if err != nil {
+		tx.Rollback()
+		e := err.Error()
+		c.JSON(status(err), TransactionBulkResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// The final http status. Will be modified when errors occur
+	status := http.StatusOK
+	r := TransactionBulkResponse{Count: len(transactions), Data: []TransactionResponse{}}
+
+	// Files of attachments are only deleted if the deletion is committed
+	var files []string
+	for _, transaction := range transactions {
+		data, err := newTransaction(c, tx, transaction)
+		if err != nil {
+			status = r.appendError(err, status, Transaction{DefaultModel: transaction.DefaultModel})
+			continue
+		}
+
+		_, deleted, err := models.MoveToTrash(tx, &transaction)
+		if err != nil {
+			status = r.appendError(err, status, data)
+			continue
+		}
+		files = append(files, deleted...)
+
+		r.Data = append(r.Data, TransactionResponse{Data: &data})
+	}
+
+	commitBulk(c, tx, options, status, r, files)
+}
+
+// @Summary		Update transaction
+// @Description	Updates an existing transaction. Only values to be updated need to be specified.
+// @Tags			Transactions
+// @Accept			json
+// @Produce		json
+// @Success		200			{object}	TransactionResponse
+// @Failure		400			{object}	TransactionResponse
+// @Failure		404			{object}	TransactionResponse
+// @Failure		412			{object}	TransactionResponse
+// @Failure		500			{object}	TransactionResponse
+// @Param			id			path		URIID				true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			transaction	body		TransactionEditable	true	"Transaction"
+// @Param			If-Match	header		string				false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/transactions/{id} [patch]
+func UpdateTransaction(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Get the transaction resource
+	var transaction models.Transaction
+	err = models.DB.First(&transaction, uri.ID).Error
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// Get the fields that are set to be updated
+	updateFields, err := httputil.GetBodyFields(c, TransactionEditable{})
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionResponse{
+			Error: &e,
+		})
+		return
+	}
+	updateFields, setTags := transactionTagFields(updateFields)
+
+	// Bind the update for the patch
+	var update TransactionEditable
+	err = httputil.BindData(c, &update)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &transaction)
+		if err != nil {
+			return err
+		}
+
+		// If the amount set via the API request is not existent or
+		// is 0, we use the old amount
+		if update.Amount.IsZero() {
+			update.Amount = transaction.Amount
+		}
+
+		if !setTags || len(updateFields) > 0 {
+			err := tx.Model(&transaction).Select("", updateFields...).Updates(update.model()).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if setTags {
+			err := models.SetTransactionTags(tx, transaction.ID, update.TagIDs)
+			if err != nil {
+				return err
+			}
+
+			// Changing only the tags still updates the transaction so that its ETag changes
+			if len(updateFields) == 0 {
+				return tx.Model(&transaction).UpdateColumn("UpdatedAt", time.Now()).Error
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	data, err := newTransaction(c, models.DB, transaction)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), TransactionResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	c.Header("ETag", httputil.ETag(transaction.UpdatedAt))
+	c.JSON(http.StatusOK, TransactionResponse{Data: &data})
+}
+
+// @Summary		Delete transaction
+// @Description	Deletes a transaction and moves it to the trash
+// @Tags			Transactions
+// @Success		204
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/transactions/{id} [delete]
+func DeleteTransaction(c *gin.Context) {
+	deleteResource[models.Transaction](c)
+}
+
+// transactionQuery returns the query for all transactions matching the filter.
+//
+// Offset and limit of the filter are not applied.
+func transactionQuery(db *gorm.DB, filter TransactionQueryFilter, queryFields []any, setFields []string) (*gorm.DB, error) {
+	// Convert the QueryFilter to a Create struct
+	model, err := filter.model()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Where(&model, queryFields...)
 
 	// Filter for the transaction being at the same date
 	if !filter.Date.IsZero() {
@@ -180,6 +672,15 @@ func GetTransactions(c *gin.Context) {
 			Where("budgets.id = ?", filter.BudgetID)
 	}
 
+	// Reconciled transactions are always cleared
+	if slices.Contains(setFields, "ClearedSource") {
+		q = q.Where("(transactions.cleared_source OR transactions.reconciled_source) = ?", filter.ClearedSource)
+	}
+
+	if slices.Contains(setFields, "ClearedDestination") {
+		q = q.Where("(transactions.cleared_destination OR transactions.reconciled_destination) = ?", filter.ClearedDestination)
+	}
+
 	if filter.AccountID != ez_uuid.Nil {
 		q = q.Where(models.DB.Where(&models.Transaction{
 			SourceAccountID: filter.AccountID.UUID,
@@ -190,11 +691,7 @@ func GetTransactions(c *gin.Context) {
 
 	if filter.Direction != "" {
 		if !slices.Contains([]TransactionDirection{DirectionIn, DirectionOut, DirectionInternal}, filter.Direction) {
-			s := errTransactionDirectionInvalid.Error()
-			c.JSON(http.StatusBadRequest, TransactionListResponse{
-				Error: &s,
-			})
-			return
+			return nil, errTransactionDirectionInvalid
 		}
 
 		// Internal transactions are internal account to internal account
@@ -224,11 +721,7 @@ func GetTransactions(c *gin.Context) {
 
 	if filter.Type != "" {
 		if !slices.Contains([]TransactionType{TypeIncome, TypeSpend, TypeTransfer}, filter.Type) {
-			s := errTransactionTypeInvalid.Error()
-			c.JSON(http.StatusBadRequest, TransactionListResponse{
-				Error: &s,
-			})
-			return
+			return nil, errTransactionTypeInvalid
 		}
 
 		// Income is coming from an off-budget to an on-budget account
@@ -270,178 +763,91 @@ func GetTransactions(c *gin.Context) {
 		q = q.Where("transactions.note = ''")
 	}
 
-	// Set the offset. Does not need checking since the default is 0
-	q = q.Offset(int(filter.Offset))
-
-	// Default to 50 transactions and set the limit
-	limit := 50
-	if slices.Contains(setFields, "Limit") {
-		limit = filter.Limit
-	}
-	q = q.Limit(limit)
-
-	var transactions []models.Transaction
-	err = q.Find(&transactions).Error
-	if err != nil {
-		e := err.Error()
-		c.JSON(status(err), TransactionListResponse{
-			Error: &e,
-		})
-		return
-	}
-
-	var count int64
-	err = q.Limit(-1).Offset(-1).Count(&count).Error
-	if err != nil {
-		e := err.Error()
-		c.JSON(status(err), TransactionListResponse{
-			Error: &e,
-		})
-		return
+	if filter.TagMatch != "" && !slices.Contains([]TagMatch{TagMatchAny, TagMatchAll}, filter.TagMatch) {
+		return nil, errTransactionTagMatchInvalid
 	}
 
-	data := make([]Transaction, 0)
-	for _, transaction := range transactions {
-		data = append(data, newTransaction(c, transaction))
-	}
-
-	c.JSON(http.StatusOK, TransactionListResponse{
-		Data: data,
-		Pagination: &Pagination{
-			Count:  len(data),
-			Total:  count,
-			Offset: filter.Offset,
-			Limit:  limit,
-		},
-	})
-}
+	if slices.Contains(setFields, "Tags") {
+		ids, err := filter.tagIDs()
+		if err != nil {
+			return nil, err
+		}
 
-// @Summary		Create transactions
-// @Description	Creates transactions from the list of submitted transaction data. The response code is the highest response code number that a single transaction creation would have caused. If it is not equal to 201, at least one transaction has an error.
-// @Tags			Transactions
-// @Produce		json
-// @Success		201				{object}	TransactionCreateResponse
-// @Failure		400				{object}	TransactionCreateResponse
-// @Failure		404				{object}	TransactionCreateResponse
-// @Failure		500				{object}	TransactionCreateResponse
-// @Param			transactions	body		[]TransactionEditable	true	"Transactions"
-// @Router			/v4/transactions [post]
-func CreateTransactions(c *gin.Context) {
-	var editables []TransactionEditable
+		// With ALL, a transaction needs to be linked to every tag
+		tagged := models.DB.Model(&models.TransactionTag{}).Select("transaction_tags.transaction_id").Where("transaction_tags.tag_id IN ?", ids)
+		if filter.TagMatch == TagMatchAll {
+			tagged = tagged.Group("transaction_tags.transaction_id").Having("COUNT(transaction_tags.tag_id) = ?", len(ids))
+		}
 
-	// Bind data and return error if not possible
-	err := httputil.BindData(c, &editables)
-	if err != nil {
-		e := err.Error()
-		c.JSON(status(err), TransactionCreateResponse{
-			Error: &e,
-		})
-		return
+		q = q.Where("transactions.id IN (?)", tagged)
 	}
 
-	// The final http status. Will be modified when errors occur
-	status := http.StatusCreated
-	r := TransactionCreateResponse{}
-
-	for _, editable := range editables {
-		transaction := editable.model()
-		err := models.DB.Create(&transaction).Error
-		// Append the error
+	if slices.Contains(setFields, "Filter") {
+		expression, err := transactionFilter(filter.Filter)
 		if err != nil {
-			status = r.appendError(err, status)
-			continue
+			return nil, err
 		}
-
-		data := newTransaction(c, transaction)
-		r.Data = append(r.Data, TransactionResponse{Data: &data})
+		q = q.Where(expression)
 	}
 
-	c.JSON(status, r)
+	return q, nil
 }
 
-// @Summary		Update transaction
-// @Description	Updates an existing transaction. Only values to be updated need to be specified.
-// @Tags			Transactions
-// @Accept			json
-// @Produce		json
-// @Success		200			{object}	TransactionResponse
-// @Failure		400			{object}	TransactionResponse
-// @Failure		404			{object}	TransactionResponse
-// @Failure		500			{object}	TransactionResponse
-// @Param			id			path		URIID				true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
-// @Param			transaction	body		TransactionEditable	true	"Transaction"
-// @Router			/v4/transactions/{id} [patch]
-func UpdateTransaction(c *gin.Context) {
-	var uri URIID
-	err := c.ShouldBindUri(&uri)
+// bulkTransactions returns the transactions selected by the query parameters
+// and the IDs for a bulk operation.
+//
+// Offset and limit are ignored since bulk operations always affect all matching transactions.
+func bulkTransactions(c *gin.Context, tx *gorm.DB, ids []uuid.UUID) ([]models.Transaction, error) {
+	var filter TransactionQueryFilter
+	err := c.ShouldBindQuery(&filter)
 	if err != nil {
-		e := err.Error()
-		c.JSON(status(err), TransactionResponse{
-			Error: &e,
-		})
-		return
+		return nil, err
 	}
 
-	// Get the transaction resource
-	var transaction models.Transaction
-	err = models.DB.First(&transaction, uri.ID).Error
-	if err != nil {
-		e := err.Error()
-		c.JSON(status(err), TransactionResponse{
-			Error: &e,
-		})
-		return
+	queryFields, setFields := httputil.GetURLFields(c.Request.URL, filter)
+	setFields = slices.DeleteFunc(setFields, func(field string) bool {
+		return field == "Offset" || field == "Limit" || field == "Sort" || field == "Cursor"
+	})
+
+	// Without any selection, all transactions would be affected
+	if len(ids) == 0 && len(setFields) == 0 {
+		return nil, errTransactionBulkNoSelection
 	}
 
-	// Get the fields that are set to be updated
-	updateFields, err := httputil.GetBodyFields(c, TransactionEditable{})
+	q, err := transactionQuery(tx, filter, queryFields, setFields)
 	if err != nil {
-		e := err.Error()
-		c.JSON(status(err), TransactionResponse{
-			Error: &e,
-		})
-		return
+		return nil, err
 	}
 
-	// Bind the update for the patch
-	var update TransactionEditable
-	err = httputil.BindData(c, &update)
-	if err != nil {
-		e := err.Error()
-		c.JSON(status(err), TransactionResponse{
-			Error: &e,
-		})
-		return
+	if len(ids) > 0 {
+		q = q.Where("transactions.id IN ?", ids)
 	}
 
-	// If the amount set via the API request is not existent or
-	// is 0, we use the old amount
-	if update.Amount.IsZero() {
-		update.Amount = transaction.Amount
+	var transactions []models.Transaction
+	err = q.Order("datetime(transactions.date) DESC, datetime(transactions.created_at) DESC").Find(&transactions).Error
+	return transactions, err
+}
+
+// commitBulk commits the database transaction of a bulk operation and sends the response.
+//
+// If any transaction failed or for dry runs, nothing is committed. The files of attachments
+// are deleted only after the transaction is committed.
+func commitBulk(c *gin.Context, tx *gorm.DB, options TransactionBulkQuery, status int, r TransactionBulkResponse, files []string) {
+	if options.DryRun || status != http.StatusOK {
+		tx.Rollback()
+		c.JSON(status, r)
+		return
 	}
 
-	err = models.DB.Model(&transaction).Select("", updateFields...).Updates(update.model()).Error
+	err := tx.Commit().Error
 	if err != nil {
 		e := err.Error()
-		c.JSON(status(err), TransactionResponse{
+		c.JSON(http.StatusInternalServerError, TransactionBulkResponse{
 			Error: &e,
 		})
 		return
 	}
+	models.DeleteStoredAttachments(files)
 
-	data := newTransaction(c, transaction)
-	c.JSON(http.StatusOK, TransactionResponse{Data: &data})
-}
-
-// @Summary		Delete transaction
-// @Description	Deletes a transaction
-// @Tags			Transactions
-// @Success		204
-// @Failure		400	{object}	httpError
-// @Failure		404	{object}	httpError
-// @Failure		500	{object}	httpError
-// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
-// @Router			/v4/transactions/{id} [delete]
-func DeleteTransaction(c *gin.Context) {
-	deleteResource[models.Transaction](c)
+	c.JSON(status, r)
 }
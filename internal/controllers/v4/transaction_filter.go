@@ -0,0 +1,323 @@
+package v4
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/filter"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/clause"
+)
+
+// filterCondition returns the SQL condition for a comparison in a filter expression.
+type filterCondition func(c filter.Comparison) (clause.Expr, error)
+
+// transactionFilterFields are the fields that can be used in filter expressions for transactions.
+var transactionFilterFields = map[string]filterCondition{
+	"amount":                filterDecimal("transactions.amount"),
+	"date":                  filterDate("transactions.date"),
+	"availableFrom":         filterDate("transactions.available_from"),
+	"note":                  filterText("transactions.note"),
+	"clearedSource":         filterBool("(transactions.cleared_source OR transactions.reconciled_source)"),
+	"clearedDestination":    filterBool("(transactions.cleared_destination OR transactions.reconciled_destination)"),
+	"reconciledSource":      filterBool("transactions.reconciled_source"),
+	"reconciledDestination": filterBool("transactions.reconciled_destination"),
+	"envelope": filterLookup{
+		columns:  []string{"transactions.envelope_id"},
+		table:    "envelopes",
+		query:    "SELECT envelopes.id FROM envelopes WHERE %s",
+		nullable: true,
+	}.condition,
+	"category": filterLookup{
+		columns:  []string{"transactions.envelope_id"},
+		table:    "categories",
+		query:    "SELECT envelopes.id FROM envelopes JOIN categories ON categories.id = envelopes.category_id WHERE %s",
+		nullable: true,
+	}.condition,
+	"budget": filterLookup{
+		columns: []string{"transactions.source_account_id"},
+		table:   "budgets",
+		query:   "SELECT accounts.id FROM accounts JOIN budgets ON budgets.id = accounts.budget_id WHERE %s",
+	}.condition,
+	"source": filterLookup{
+		columns: []string{"transactions.source_account_id"},
+		table:   "accounts",
+		query:   "SELECT accounts.id FROM accounts WHERE %s",
+	}.condition,
+	"destination": filterLookup{
+		columns: []string{"transactions.destination_account_id"},
+		table:   "accounts",
+		query:   "SELECT accounts.id FROM accounts WHERE %s",
+	}.condition,
+	"account": filterLookup{
+		columns: []string{"transactions.source_account_id", "transactions.destination_account_id"},
+		table:   "accounts",
+		query:   "SELECT accounts.id FROM accounts WHERE %s",
+	}.condition,
+	"tag": filterLookup{
+		columns: []string{"transactions.id"},
+		table:   "tags",
+		query:   "SELECT transaction_tags.transaction_id FROM transaction_tags JOIN tags ON tags.id = transaction_tags.tag_id WHERE %s",
+	}.condition,
+
+	// Directions and types depend on the accounts, see transactionQuery
+	"direction": filterAccounts("external", map[string][2]bool{
+		string(DirectionIn):       {true, false},
+		string(DirectionOut):      {false, true},
+		string(DirectionInternal): {false, false},
+	}),
+	"type": filterAccounts("on_budget", map[string][2]bool{
+		string(TypeIncome):   {false, true},
+		string(TypeSpend):    {true, false},
+		string(TypeTransfer): {true, true},
+	}),
+}
+
+// transactionFilter returns the condition for a filter expression for transactions.
+func transactionFilter(expression string) (clause.Expr, error) {
+	node, err := filter.Parse(expression)
+	if err != nil {
+		return clause.Expr{}, err
+	}
+
+	return filterExpression(node, transactionFilterFields)
+}
+
+// filterExpression translates the syntax tree of a filter expression into an SQL condition.
+//
+// All values are passed as parameters, the SQL only contains the expressions defined for the fields.
+func filterExpression(node filter.Node, fields map[string]filterCondition) (clause.Expr, error) {
+	switch n := node.(type) {
+	case filter.And:
+		return filterJoin("AND", n.Left, n.Right, fields)
+
+	case filter.Or:
+		return filterJoin("OR", n.Left, n.Right, fields)
+
+	case filter.Not:
+		operand, err := filterExpression(n.Operand, fields)
+		if err != nil {
+			return clause.Expr{}, err
+		}
+
+		// Comparisons with NULL are NULL, which must count as not matching
+		return clause.Expr{SQL: fmt.Sprintf("(NOT COALESCE(%s, FALSE))", operand.SQL), Vars: operand.Vars}, nil
+
+	case filter.Comparison:
+		condition, ok := fields[n.Field]
+		if !ok {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+
+			return clause.Expr{}, filter.Errorf(n.Position, "unknown field '%s', it must be one of %s", n.Field, strings.Join(names, ", "))
+		}
+
+		return condition(n)
+	}
+
+	return clause.Expr{}, filter.Errorf(node.Pos(), "unsupported expression")
+}
+
+// filterJoin joins the conditions for two nodes with the operator.
+func filterJoin(operator string, left, right filter.Node, fields map[string]filterCondition) (clause.Expr, error) {
+	l, err := filterExpression(left, fields)
+	if err != nil {
+		return clause.Expr{}, err
+	}
+
+	r, err := filterExpression(right, fields)
+	if err != nil {
+		return clause.Expr{}, err
+	}
+
+	return clause.Expr{SQL: fmt.Sprintf("(%s %s %s)", l.SQL, operator, r.SQL), Vars: slices.Concat(l.Vars, r.Vars)}, nil
+}
+
+// filterOperatorError returns the error for an operator that cannot be used with a field.
+func filterOperatorError(c filter.Comparison, allowed ...filter.Operator) error {
+	operators := make([]string, 0, len(allowed))
+	for _, operator := range allowed {
+		operators = append(operators, string(operator))
+	}
+
+	return filter.Errorf(c.Position, "the operator %s cannot be used with %s, use one of %s", c.Operator, c.Field, strings.Join(operators, " "))
+}
+
+// filterNegate negates the condition if the operator is a negation.
+func filterNegate(c filter.Comparison, condition clause.Expr) clause.Expr {
+	if c.Operator == filter.NotEqual || c.Operator == filter.NotContains {
+		return clause.Expr{SQL: fmt.Sprintf("(NOT COALESCE(%s, FALSE))", condition.SQL), Vars: condition.Vars}
+	}
+	return condition
+}
+
+// filterContains returns the pattern for a LIKE condition that matches text containing the value.
+func filterContains(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return fmt.Sprintf("%%%s%%", replacer.Replace(value))
+}
+
+// filterDecimal compares a decimal column.
+func filterDecimal(column string) filterCondition {
+	return func(c filter.Comparison) (clause.Expr, error) {
+		if !slices.Contains([]filter.Operator{filter.Equal, filter.NotEqual, filter.Less, filter.LessOrEqual, filter.Greater, filter.GreaterOrEqual}, c.Operator) {
+			return clause.Expr{}, filterOperatorError(c, filter.Equal, filter.NotEqual, filter.Less, filter.LessOrEqual, filter.Greater, filter.GreaterOrEqual)
+		}
+
+		value, err := decimal.NewFromString(c.Value.Text)
+		if c.Value.Kind != filter.Number || err != nil {
+			return clause.Expr{}, filter.Errorf(c.Value.Position, "the value for %s must be a number", c.Field)
+		}
+
+		return clause.Expr{SQL: fmt.Sprintf("(%s %s ?)", column, c.Operator), Vars: []any{value}}, nil
+	}
+}
+
+// filterDate compares the day of a timestamp column.
+func filterDate(column string) filterCondition {
+	return func(c filter.Comparison) (clause.Expr, error) {
+		day, err := time.Parse(time.DateOnly, c.Value.Text)
+		if c.Value.Kind != filter.Date || err != nil {
+			return clause.Expr{}, filter.Errorf(c.Value.Position, "the value for %s must be a date in the format YYYY-MM-DD", c.Field)
+		}
+		next := day.AddDate(0, 0, 1)
+
+		switch c.Operator {
+		case filter.Equal:
+			return clause.Expr{SQL: fmt.Sprintf("(%[1]s >= date(?) AND %[1]s < date(?))", column), Vars: []any{day, next}}, nil
+		case filter.NotEqual:
+			return clause.Expr{SQL: fmt.Sprintf("(%[1]s < date(?) OR %[1]s >= date(?))", column), Vars: []any{day, next}}, nil
+		case filter.Less:
+			return clause.Expr{SQL: fmt.Sprintf("(%s < date(?))", column), Vars: []any{day}}, nil
+		case filter.LessOrEqual:
+			return clause.Expr{SQL: fmt.Sprintf("(%s < date(?))", column), Vars: []any{next}}, nil
+		case filter.Greater:
+			return clause.Expr{SQL: fmt.Sprintf("(%s >= date(?))", column), Vars: []any{next}}, nil
+		case filter.GreaterOrEqual:
+			return clause.Expr{SQL: fmt.Sprintf("(%s >= date(?))", column), Vars: []any{day}}, nil
+		}
+
+		return clause.Expr{}, filterOperatorError(c, filter.Equal, filter.NotEqual, filter.Less, filter.LessOrEqual, filter.Greater, filter.GreaterOrEqual)
+	}
+}
+
+// filterText compares a text column. The contains operators ignore the case of ASCII letters.
+func filterText(column string) filterCondition {
+	return func(c filter.Comparison) (clause.Expr, error) {
+		if c.Value.Kind != filter.String {
+			return clause.Expr{}, filter.Errorf(c.Value.Position, "the value for %s must be a quoted string", c.Field)
+		}
+
+		switch c.Operator {
+		case filter.Equal, filter.NotEqual:
+			return clause.Expr{SQL: fmt.Sprintf("(%s %s ?)", column, c.Operator), Vars: []any{c.Value.Text}}, nil
+		case filter.Contains, filter.NotContains:
+			return filterNegate(c, clause.Expr{SQL: fmt.Sprintf(`(%s LIKE ? ESCAPE '\')`, column), Vars: []any{filterContains(c.Value.Text)}}), nil
+		}
+
+		return clause.Expr{}, filterOperatorError(c, filter.Equal, filter.NotEqual, filter.Contains, filter.NotContains)
+	}
+}
+
+// filterBool compares a boolean expression.
+func filterBool(expression string) filterCondition {
+	return func(c filter.Comparison) (clause.Expr, error) {
+		if c.Operator != filter.Equal && c.Operator != filter.NotEqual {
+			return clause.Expr{}, filterOperatorError(c, filter.Equal, filter.NotEqual)
+		}
+
+		value := strings.ToLower(c.Value.Text)
+		if c.Value.Kind != filter.Word || (value != "true" && value != "false") {
+			return clause.Expr{}, filter.Errorf(c.Value.Position, "the value for %s must be true or false", c.Field)
+		}
+
+		return clause.Expr{SQL: fmt.Sprintf("(%s %s ?)", expression, c.Operator), Vars: []any{value == "true"}}, nil
+	}
+}
+
+// filterAccounts compares a boolean column of the source and destination accounts
+// with the pair of values for the value of the comparison.
+func filterAccounts(column string, values map[string][2]bool) filterCondition {
+	return func(c filter.Comparison) (clause.Expr, error) {
+		if c.Operator != filter.Equal && c.Operator != filter.NotEqual {
+			return clause.Expr{}, filterOperatorError(c, filter.Equal, filter.NotEqual)
+		}
+
+		pair, ok := values[strings.ToUpper(c.Value.Text)]
+		if c.Value.Kind != filter.Word || !ok {
+			allowed := make([]string, 0, len(values))
+			for value := range values {
+				allowed = append(allowed, value)
+			}
+			slices.Sort(allowed)
+
+			return clause.Expr{}, filter.Errorf(c.Value.Position, "the value for %s must be one of %s", c.Field, strings.Join(allowed, ", "))
+		}
+
+		return filterNegate(c, clause.Expr{
+			SQL: fmt.Sprintf(
+				"(transactions.source_account_id IN (SELECT accounts.id FROM accounts WHERE accounts.%[1]s = ?) AND transactions.destination_account_id IN (SELECT accounts.id FROM accounts WHERE accounts.%[1]s = ?))",
+				column,
+			),
+			Vars: []any{pair[0], pair[1]},
+		}), nil
+	}
+}
+
+// filterLookup compares resources referenced by a transaction by their name or ID.
+type filterLookup struct {
+	columns  []string // Columns of the transaction with the ID of the referenced resource. The comparison matches if it matches for any column
+	table    string   // Table of the resource with the name
+	query    string   // Query for the referenced IDs, with a placeholder for the condition on the table
+	nullable bool     // If the reference can be compared with null
+}
+
+func (l filterLookup) condition(c filter.Comparison) (clause.Expr, error) {
+	if l.nullable && c.Value.Kind == filter.Word && strings.EqualFold(c.Value.Text, "null") {
+		switch c.Operator {
+		case filter.Equal:
+			return clause.Expr{SQL: fmt.Sprintf("(%s IS NULL)", l.columns[0])}, nil
+		case filter.NotEqual:
+			return clause.Expr{SQL: fmt.Sprintf("(%s IS NOT NULL)", l.columns[0])}, nil
+		}
+		return clause.Expr{}, filterOperatorError(c, filter.Equal, filter.NotEqual)
+	}
+
+	if c.Value.Kind != filter.String {
+		if l.nullable {
+			return clause.Expr{}, filter.Errorf(c.Value.Position, "the value for %s must be a quoted name or ID or null", c.Field)
+		}
+		return clause.Expr{}, filter.Errorf(c.Value.Position, "the value for %s must be a quoted name or ID", c.Field)
+	}
+
+	var condition string
+	var value any
+	switch c.Operator {
+	case filter.Equal, filter.NotEqual:
+		if id, err := uuid.Parse(c.Value.Text); err == nil {
+			condition, value = fmt.Sprintf("%s.id = ?", l.table), id
+		} else {
+			condition, value = fmt.Sprintf("%s.name = ?", l.table), c.Value.Text
+		}
+	case filter.Contains, filter.NotContains:
+		condition, value = fmt.Sprintf(`%s.name LIKE ? ESCAPE '\'`, l.table), filterContains(c.Value.Text)
+	default:
+		return clause.Expr{}, filterOperatorError(c, filter.Equal, filter.NotEqual, filter.Contains, filter.NotContains)
+	}
+
+	query := fmt.Sprintf(l.query, condition)
+	conditions := make([]string, 0, len(l.columns))
+	vars := make([]any, 0, len(l.columns))
+	for _, column := range l.columns {
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", column, query))
+		vars = append(vars, value)
+	}
+
+	return filterNegate(c, clause.Expr{SQL: fmt.Sprintf("(%s)", strings.Join(conditions, " OR ")), Vars: vars}), nil
+}
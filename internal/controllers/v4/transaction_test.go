@@ -3,6 +3,7 @@ package v4_test
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -304,6 +305,137 @@ func (suite *TestSuiteStandard) TestTransactionsGetFilter() {
 	}
 }
 
+// TestTransactionsGetFilterExpression verifies that filter expressions select the correct transactions.
+func (suite *TestSuiteStandard) TestTransactionsGetFilterExpression() {
+	b := createTestBudget(suite.T(), v4.BudgetEditable{Name: "Household"})
+	other := createTestBudget(suite.T(), v4.BudgetEditable{Name: "Side business"})
+
+	checking := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: b.Data.ID, Name: "Checking", OnBudget: true})
+	savings := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: b.Data.ID, Name: "Savings", OnBudget: true})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: b.Data.ID, Name: "Pizza Place", External: true})
+	employer := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: b.Data.ID, Name: "Employer", External: true})
+	otherAccount := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: other.Data.ID, Name: "Business", OnBudget: true})
+	otherClient := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: other.Data.ID, Name: "Client", External: true})
+
+	living := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: b.Data.ID, Name: "Living"})
+	food := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: living.Data.ID, Name: "Food"})
+	rent := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: living.Data.ID, Name: "Rent"})
+
+	transactions := []v4.TransactionEditable{
+		{Date: time.Date(2023, 12, 28, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromFloat(30), Note: "Pizza night", EnvelopeID: &food.Data.ID, SourceAccountID: checking.Data.ID, DestinationAccountID: shop.Data.ID},
+		{Date: time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC), Amount: decimal.NewFromFloat(80), Note: "Groceries 100% organic", EnvelopeID: &food.Data.ID, SourceAccountID: checking.Data.ID, DestinationAccountID: shop.Data.ID, ReconciledSource: true},
+		{Date: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromFloat(700), Note: "January rent", EnvelopeID: &rent.Data.ID, SourceAccountID: checking.Data.ID, DestinationAccountID: shop.Data.ID, ClearedSource: true},
+		{Date: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromFloat(2000), Note: "Salary", SourceAccountID: employer.Data.ID, DestinationAccountID: checking.Data.ID},
+		{Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromFloat(500), Note: "", SourceAccountID: checking.Data.ID, DestinationAccountID: savings.Data.ID},
+		{Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromFloat(300), Note: "Invoice", SourceAccountID: otherClient.Data.ID, DestinationAccountID: otherAccount.Data.ID},
+	}
+
+	for _, transaction := range transactions {
+		_ = createTestTransaction(suite.T(), transaction)
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		notes      []string
+	}{
+		{"Request example", `amount > 50 and (envelope = "Food" or note ~ "pizza") and date >= 2024-01-01`, []string{"Groceries 100% organic"}},
+		{"Or", `note = "Salary" or note = "Invoice"`, []string{"Salary", "Invoice"}},
+		{"Not", `not (amount < 500 or amount > 700)`, []string{"January rent", ""}},
+		{"Not equal", `amount != 30 and budget = "Household" and date != 2024-01-31`, []string{"Groceries 100% organic", "January rent", ""}},
+		{"Date equal ignores time", `date = 2024-01-05`, []string{"Groceries 100% organic"}},
+		{"Date less or equal", `date <= 2024-01-05`, []string{"Pizza night", "Groceries 100% organic"}},
+		{"Date greater", `date > 2024-01-10`, []string{"Salary", ""}},
+		{"Available from defaults to the next month", `availableFrom <= 2024-01-01`, []string{"Pizza night"}},
+		{"Note contains is case insensitive", `note ~ "PIZZA"`, []string{"Pizza night"}},
+		{"Note contains escapes wildcards", `note ~ "100%"`, []string{"Groceries 100% organic"}},
+		{"Note does not contain", `note !~ "i" and budget = "Household"`, []string{"January rent", "Salary", ""}},
+		{"Empty note", `note = ""`, []string{""}},
+		{"Envelope by ID", fmt.Sprintf(`envelope = "%s"`, rent.Data.ID), []string{"January rent"}},
+		{"Envelope contains", `envelope ~ "foo"`, []string{"Pizza night", "Groceries 100% organic"}},
+		{"No envelope", `envelope = null`, []string{"Salary", "", "Invoice"}},
+		{"Not the envelope includes no envelope", `envelope != "Food"`, []string{"January rent", "Salary", "", "Invoice"}},
+		{"Not not the envelope", `not envelope != "Rent"`, []string{"January rent"}},
+		{"With envelope", `envelope != null`, []string{"Pizza night", "Groceries 100% organic", "January rent"}},
+		{"Category", `category = "Living" and amount < 100`, []string{"Pizza night", "Groceries 100% organic"}},
+		{"Budget", `budget = "Side business"`, []string{"Invoice"}},
+		{"Budget by ID", fmt.Sprintf(`budget = '%s'`, other.Data.ID), []string{"Invoice"}},
+		{"Account", `account = "Savings" or account = "Employer"`, []string{"Salary", ""}},
+		{"Not the account", `account != "Checking"`, []string{"Invoice"}},
+		{"Source", `source = "Employer"`, []string{"Salary"}},
+		{"Destination", `destination ~ "pizza"`, []string{"Pizza night", "Groceries 100% organic", "January rent"}},
+		{"Direction", `direction = in`, []string{"Salary", "Invoice"}},
+		{"Direction not out", `direction != OUT`, []string{"Salary", "", "Invoice"}},
+		{"Type", `type = TRANSFER`, []string{""}},
+		{"Reconciled", `reconciledSource = true`, []string{"Groceries 100% organic"}},
+		{"Cleared includes reconciled", `clearedSource = true`, []string{"Groceries 100% organic", "January rent"}},
+		{"Not cleared", `clearedSource = false and reconciledDestination != true and budget = "Household"`, []string{"Pizza night", "Salary", ""}},
+		{"Keywords are case insensitive", `NOT note = "" AND amount >= 700 OR amount = 30`, []string{"Pizza night", "January rent", "Salary"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			r := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/transactions?sort=date&filter=%s", url.QueryEscape(tt.expression)), "")
+			test.AssertHTTPStatus(t, &r, http.StatusOK)
+
+			var response v4.TransactionListResponse
+			test.DecodeResponse(t, &r, &response)
+
+			notes := make([]string, 0)
+			for _, transaction := range response.Data {
+				notes = append(notes, transaction.Note)
+			}
+			assert.ElementsMatch(t, tt.notes, notes)
+		})
+	}
+
+	// Filter expressions are combined with the other filters
+	r := test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/transactions?account=%s&filter=%s", shop.Data.ID, url.QueryEscape(`amount < 100`)), "")
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+
+	var response v4.TransactionListResponse
+	test.DecodeResponse(suite.T(), &r, &response)
+	assert.Len(suite.T(), response.Data, 2)
+}
+
+// TestTransactionsGetFilterExpressionInvalid verifies that invalid filter expressions
+// return a HTTP Bad Request with the position of the error.
+func (suite *TestSuiteStandard) TestTransactionsGetFilterExpressionInvalid() {
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{``, "invalid filter expression at position 1: expected a field name, found the end of the expression"},
+		{`amount > 50 and (note ~ "pizza"`, "invalid filter expression at position 32: expected ) to close ( at position 17, found the end of the expression"},
+		{`payee = "Shop"`, "invalid filter expression at position 1: unknown field 'payee', it must be one of account, amount, availableFrom, budget, category, clearedDestination, clearedSource, date, destination, direction, envelope, note, reconciledDestination, reconciledSource, source, tag, type"},
+		{`amount ~ 5`, "invalid filter expression at position 1: the operator ~ cannot be used with amount, use one of = != < <= > >="},
+		{`amount = "5"`, "invalid filter expression at position 10: the value for amount must be a number"},
+		{`date >= 2024-02-30`, "invalid filter expression at position 9: the value for date must be a date in the format YYYY-MM-DD"},
+		{`note = pizza`, "invalid filter expression at position 8: the value for note must be a quoted string"},
+		{`note > "a"`, "invalid filter expression at position 1: the operator > cannot be used with note, use one of = != ~ !~"},
+		{`envelope = 5`, "invalid filter expression at position 12: the value for envelope must be a quoted name or ID or null"},
+		{`account = null`, "invalid filter expression at position 11: the value for account must be a quoted name or ID"},
+		{`envelope ~ null`, "invalid filter expression at position 1: the operator ~ cannot be used with envelope, use one of = !="},
+		{`source < "Cash"`, "invalid filter expression at position 1: the operator < cannot be used with source, use one of = != ~ !~"},
+		{`direction = UP`, "invalid filter expression at position 13: the value for direction must be one of IN, INTERNAL, OUT"},
+		{`type ~ SPEND`, "invalid filter expression at position 1: the operator ~ cannot be used with type, use one of = !="},
+		{`reconciledSource = yes`, "invalid filter expression at position 20: the value for reconciledSource must be true or false"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.expression, func(t *testing.T) {
+			r := test.Request(t, http.MethodGet, fmt.Sprintf("http://example.com/v4/transactions?filter=%s", url.QueryEscape(tt.expression)), "")
+			test.AssertHTTPStatus(t, &r, http.StatusBadRequest)
+
+			var response v4.TransactionListResponse
+			test.DecodeResponse(t, &r, &response)
+			if assert.NotNil(t, response.Error) {
+				assert.Equal(t, tt.err, *response.Error)
+			}
+		})
+	}
+}
+
 // TestTransactionsGetInvalidQuery verifies that invalid filtering queries
 // return a HTTP Bad Request.
 func (suite *TestSuiteStandard) TestTransactionsGetInvalidQuery() {
@@ -319,6 +451,11 @@ func (suite *TestSuiteStandard) TestTransactionsGetInvalidQuery() {
 		"limit=name",         // limit is an int
 		"direction=external", // direction needs to be a TransactionDirection, external does not exist
 		"type=winnings",      // type needs to be a TransactionType, winnings don't exist (would be nice though, right?)
+		"sort=payee",         // transactions cannot be sorted by payee
+		"sort=-",             // the field is missing
+		"cursor=NotACursor",  // cursors are base64 encoded
+		"cursor=e30",         // cursors need a position, this is {}
+		"offset=2&cursor=e30",
 	}
 
 	for _, tt := range tests {
@@ -335,6 +472,97 @@ func (suite *TestSuiteStandard) TestTransactionsGetInvalidQuery() {
 	}
 }
 
+// TestTransactionsCursorPagination verifies that following the next and previous
+// links returns all transactions in the same order as offset pagination.
+func (suite *TestSuiteStandard) TestTransactionsCursorPagination() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+
+	// Some transactions share date and amount so that the order depends on the ID
+	for i, amount := range []float64{3, 1, 2, 2, 5} {
+		_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+			Date:                 time.Date(2024, 1, 1+i%3, 0, 0, 0, 0, time.UTC),
+			SourceAccountID:      account.Data.ID,
+			DestinationAccountID: shop.Data.ID,
+			Amount:               decimal.NewFromFloat(amount),
+		})
+	}
+
+	tests := []struct {
+		name string
+		sort string
+	}{
+		{"Default", ""},
+		{"Amount", "amount"},
+		{"Amount descending and date", "-amount,date"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			path := fmt.Sprintf("http://example.com/v4/transactions?budget=%s&sort=%s", budget.Data.ID, tt.sort)
+
+			recorder := test.Request(t, http.MethodGet, fmt.Sprintf("%s&limit=-1", path), "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+			var all v4.TransactionListResponse
+			test.DecodeResponse(t, &recorder, &all)
+			assert.Len(t, all.Data, 5)
+			assert.Nil(t, all.Pagination.Next)
+
+			var pages []v4.TransactionListResponse
+			next := fmt.Sprintf("%s&limit=2", path)
+
+			// Five transactions are three pages. The loop is bounded in case the next link is always set
+			for range 4 {
+				recorder := test.Request(t, http.MethodGet, next, "")
+				test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+				var page v4.TransactionListResponse
+				test.DecodeResponse(t, &recorder, &page)
+				pages = append(pages, page)
+
+				if page.Pagination.Next == nil {
+					break
+				}
+				next = *page.Pagination.Next
+			}
+
+			if !assert.Len(t, pages, 3) {
+				return
+			}
+
+			var ids []uuid.UUID
+			for i, page := range pages {
+				if i == 0 {
+					assert.Equal(t, int64(5), page.Pagination.Total, "The first page uses offset pagination and has a total")
+				} else {
+					assert.Zero(t, page.Pagination.Total, "Pages after the first use cursor pagination and have no total")
+				}
+				assert.Equal(t, i == 0, page.Pagination.Previous == nil, "Only the first page has no previous page")
+
+				for _, transaction := range page.Data {
+					ids = append(ids, transaction.ID)
+				}
+			}
+
+			for i, transaction := range all.Data {
+				assert.Equal(t, transaction.ID, ids[i], "Transaction %d differs between offset and cursor pagination", i)
+			}
+
+			// Going back from the last page returns the second page
+			recorder = test.Request(t, http.MethodGet, *pages[2].Pagination.Previous, "")
+			test.AssertHTTPStatus(t, &recorder, http.StatusOK)
+			var previous v4.TransactionListResponse
+			test.DecodeResponse(t, &recorder, &previous)
+			if assert.Len(t, previous.Data, 2) {
+				assert.Equal(t, pages[1].Data[0].ID, previous.Data[0].ID)
+				assert.Equal(t, pages[1].Data[1].ID, previous.Data[1].ID)
+			}
+			assert.NotNil(t, previous.Pagination.Previous)
+			assert.NotNil(t, previous.Pagination.Next)
+		})
+	}
+}
+
 // TestTransactionsCreateInvalidBody verifies that creation of transactions
 // with an unparseable request body returns a HTTP Bad Request.
 func (suite *TestSuiteStandard) TestTransactionsCreateInvalidBody() {
@@ -602,3 +830,206 @@ func (suite *TestSuiteStandard) TestTransactionsUpdate() {
 		})
 	}
 }
+
+// TestTransactionsUpdateReconciled verifies that reconciled transactions can only
+// be changed when they are unlocked in the same request.
+func (suite *TestSuiteStandard) TestTransactionsUpdateReconciled() {
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{
+		Amount:               decimal.NewFromFloat(23.14),
+		SourceAccountID:      createTestAccount(suite.T(), v4.AccountEditable{Name: "Internal Source Account", External: false}).Data.ID,
+		DestinationAccountID: createTestAccount(suite.T(), v4.AccountEditable{Name: "External destination account", External: true}).Data.ID,
+		ReconciledSource:     true,
+	})
+	assert.True(suite.T(), transaction.Data.ClearedSource)
+	assert.Equal(suite.T(), models.TransactionStateReconciled, transaction.Data.SourceState)
+	assert.Equal(suite.T(), models.TransactionStateUncleared, transaction.Data.DestinationState)
+
+	r := test.Request(suite.T(), http.MethodPatch, transaction.Data.Links.Self, map[string]any{"note": "Changed"})
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusBadRequest)
+
+	r = test.Request(suite.T(), http.MethodPatch, transaction.Data.Links.Self, map[string]any{"note": "Changed", "reconciledSource": false})
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+
+	var updated v4.TransactionResponse
+	test.DecodeResponse(suite.T(), &r, &updated)
+	assert.Equal(suite.T(), "Changed", updated.Data.Note)
+	assert.Equal(suite.T(), models.TransactionStateCleared, updated.Data.SourceState)
+
+	r = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/transactions?account=%s&clearedSource=true", transaction.Data.SourceAccountID), "")
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+
+	var list v4.TransactionListResponse
+	test.DecodeResponse(suite.T(), &r, &list)
+	assert.Len(suite.T(), list.Data, 1)
+}
+
+// TestTransactionsBulkUpdate verifies that transactions are updated in bulk.
+func (suite *TestSuiteStandard) TestTransactionsBulkUpdate() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID})
+	groceries := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Groceries"})
+	eatingOut := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID, Name: "Eating out"})
+	bank := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	cafe := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Cafe", External: true})
+
+	for _, amount := range []float64{3.5, 4, 4.2} {
+		_ = createTestTransaction(suite.T(), v4.TransactionEditable{
+			SourceAccountID:      bank.Data.ID,
+			DestinationAccountID: cafe.Data.ID,
+			EnvelopeID:           &groceries.Data.ID,
+			Amount:               decimal.NewFromFloat(amount),
+			Note:                 "Coffee",
+		})
+	}
+
+	lunch := createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      bank.Data.ID,
+		DestinationAccountID: cafe.Data.ID,
+		EnvelopeID:           &groceries.Data.ID,
+		Amount:               decimal.NewFromFloat(12),
+		Note:                 "Lunch",
+	})
+
+	// Dry run
+	r := test.Request(suite.T(), http.MethodPatch, fmt.Sprintf("http://example.com/v4/transactions?dryRun=true&note=Coffee&envelope=%s", groceries.Data.ID), map[string]any{"envelopeId": eatingOut.Data.ID})
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+
+	var response v4.TransactionBulkResponse
+	test.DecodeResponse(suite.T(), &r, &response)
+	assert.Equal(suite.T(), 3, response.Count)
+
+	r = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/transactions?envelope=%s", eatingOut.Data.ID), "")
+	var transactions v4.TransactionListResponse
+	test.DecodeResponse(suite.T(), &r, &transactions)
+	assert.Len(suite.T(), transactions.Data, 0, "Dry runs must not change transactions")
+
+	// Update by filter
+	r = test.Request(suite.T(), http.MethodPatch, fmt.Sprintf("http://example.com/v4/transactions?note=Coffee&envelope=%s", groceries.Data.ID), map[string]any{"envelopeId": eatingOut.Data.ID})
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+	test.DecodeResponse(suite.T(), &r, &response)
+	assert.Equal(suite.T(), 3, response.Count)
+	for _, transaction := range response.Data {
+		assert.Nil(suite.T(), transaction.Error)
+		assert.Equal(suite.T(), eatingOut.Data.ID, *transaction.Data.EnvelopeID)
+		assert.False(suite.T(), transaction.Data.Amount.IsZero(), "The amount must not be changed when it is not set")
+	}
+
+	r = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/transactions?envelope=%s", eatingOut.Data.ID), "")
+	test.DecodeResponse(suite.T(), &r, &transactions)
+	assert.Len(suite.T(), transactions.Data, 3)
+
+	// Update by ID
+	r = test.Request(suite.T(), http.MethodPatch, "http://example.com/v4/transactions", map[string]any{"ids": []uuid.UUID{lunch.Data.ID}, "note": "Lunch with friends"})
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+	test.DecodeResponse(suite.T(), &r, &response)
+	assert.Equal(suite.T(), 1, response.Count)
+	assert.Equal(suite.T(), "Lunch with friends", response.Data[0].Data.Note)
+}
+
+// TestTransactionsBulkUpdateAtomic verifies that no transaction is updated
+// when the update fails for one of them.
+func (suite *TestSuiteStandard) TestTransactionsBulkUpdateAtomic() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	bank := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	employer := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Employer", External: true})
+
+	spending := createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: bank.Data.ID, DestinationAccountID: employer.Data.ID, Amount: decimal.NewFromFloat(10), Note: "Refund"})
+	income := createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: employer.Data.ID, DestinationAccountID: bank.Data.ID, Amount: decimal.NewFromFloat(1000), Note: "Salary"})
+
+	// For the income, source and destination would be the same
+	r := test.Request(suite.T(), http.MethodPatch, "http://example.com/v4/transactions", map[string]any{
+		"ids":                  []uuid.UUID{spending.Data.ID, income.Data.ID},
+		"destinationAccountId": employer.Data.ID,
+		"note":                 "Changed",
+	})
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusBadRequest)
+
+	var response v4.TransactionBulkResponse
+	test.DecodeResponse(suite.T(), &r, &response)
+	assert.Equal(suite.T(), 2, response.Count)
+
+	failed := 0
+	for _, transaction := range response.Data {
+		if transaction.Error != nil {
+			failed++
+			assert.Equal(suite.T(), income.Data.ID, transaction.Data.ID)
+		}
+	}
+	assert.Equal(suite.T(), 1, failed)
+
+	r = test.Request(suite.T(), http.MethodGet, spending.Data.Links.Self, "")
+	var transaction v4.TransactionResponse
+	test.DecodeResponse(suite.T(), &r, &transaction)
+	assert.Equal(suite.T(), "Refund", transaction.Data.Note, "No transaction must be updated when one update fails")
+}
+
+// TestTransactionsBulkDelete verifies that transactions are deleted in bulk.
+func (suite *TestSuiteStandard) TestTransactionsBulkDelete() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	bank := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+
+	first := createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: bank.Data.ID, DestinationAccountID: shop.Data.ID, Amount: decimal.NewFromFloat(1), Note: "Duplicate"})
+	_ = createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: bank.Data.ID, DestinationAccountID: shop.Data.ID, Amount: decimal.NewFromFloat(1), Note: "Duplicate"})
+	kept := createTestTransaction(suite.T(), v4.TransactionEditable{SourceAccountID: bank.Data.ID, DestinationAccountID: shop.Data.ID, Amount: decimal.NewFromFloat(2), Note: "Original"})
+
+	r := test.Request(suite.T(), http.MethodDelete, "http://example.com/v4/transactions?dryRun=true&note=Duplicate", "")
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+
+	var response v4.TransactionBulkResponse
+	test.DecodeResponse(suite.T(), &r, &response)
+	assert.Equal(suite.T(), 2, response.Count)
+
+	r = test.Request(suite.T(), http.MethodGet, first.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+
+	r = test.Request(suite.T(), http.MethodDelete, "http://example.com/v4/transactions?note=Duplicate", "")
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+	test.DecodeResponse(suite.T(), &r, &response)
+	assert.Equal(suite.T(), 2, response.Count)
+
+	r = test.Request(suite.T(), http.MethodGet, first.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusNotFound)
+
+	r = test.Request(suite.T(), http.MethodDelete, "http://example.com/v4/transactions", v4.TransactionBulkDelete{IDs: []uuid.UUID{kept.Data.ID}})
+	test.AssertHTTPStatus(suite.T(), &r, http.StatusOK)
+	test.DecodeResponse(suite.T(), &r, &response)
+	assert.Equal(suite.T(), 1, response.Count)
+
+	r = test.Request(suite.T(), http.MethodGet, "http://example.com/v4/trash?model=Transaction", "")
+	var trash v4.TrashEntryListResponse
+	test.DecodeResponse(suite.T(), &r, &trash)
+	assert.Len(suite.T(), trash.Data, 3, "Deleted transactions must be moved to the trash")
+}
+
+// TestTransactionsBulkFails verifies that bulk operations fail for invalid requests.
+func (suite *TestSuiteStandard) TestTransactionsBulkFails() {
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		body   any
+		status int
+	}{
+		{"Update without selection", http.MethodPatch, "", map[string]any{"note": "Everything"}, http.StatusBadRequest},
+		{"Update with only pagination", http.MethodPatch, "?limit=1", map[string]any{"note": "Everything"}, http.StatusBadRequest},
+		{"Update with empty body", http.MethodPatch, "?note=Coffee", "", http.StatusBadRequest},
+		{"Update with invalid direction", http.MethodPatch, "?direction=UP", map[string]any{"note": "Up"}, http.StatusBadRequest},
+		{"Update with invalid dry run", http.MethodPatch, "?dryRun=maybe", map[string]any{"note": "Up"}, http.StatusBadRequest},
+		{"Delete without selection", http.MethodDelete, "", "", http.StatusBadRequest},
+		{"Delete with invalid filter", http.MethodDelete, "?envelope=notauuid", "", http.StatusBadRequest},
+		{"Delete with invalid filter expression", http.MethodDelete, "?filter=amount", "", http.StatusBadRequest},
+		{"Delete with invalid body", http.MethodDelete, "", `{"ids": "all"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			r := test.Request(t, tt.method, fmt.Sprintf("http://example.com/v4/transactions%s", tt.query), tt.body)
+			test.AssertHTTPStatus(t, &r, tt.status)
+
+			var response v4.TransactionBulkResponse
+			test.DecodeResponse(t, &r, &response)
+			assert.NotNil(t, response.Error)
+		})
+	}
+}
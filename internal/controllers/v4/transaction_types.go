@@ -2,6 +2,8 @@ package v4
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/envelope-zero/backend/v7/internal/models"
@@ -10,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 type TransactionEditable struct {
@@ -18,16 +21,22 @@ type TransactionEditable struct {
 	// The maximum value is "999999999999.99999999", swagger unfortunately rounds this.
 	Amount decimal.Decimal `json:"amount" example:"14.03" minimum:"0.00000001" maximum:"999999999999.99999999" multipleOf:"0.00000001"` // The amount for the transaction
 
+	DestinationAmount decimal.Decimal `json:"destinationAmount" example:"15.21" default:"0" minimum:"0" maximum:"999999999999.99999999" multipleOf:"0.00000001"` // The amount in the currency of the destination account. Must be set if and only if the accounts have different currencies
+
 	Note                  string     `json:"note" example:"Lunch" default:""`                                     // A note
 	SourceAccountID       uuid.UUID  `json:"sourceAccountId" example:"fd81dc45-a3a2-468e-a6fa-b2618f30aa45"`      // ID of the source account
 	DestinationAccountID  uuid.UUID  `json:"destinationAccountId" example:"8e16b456-a719-48ce-9fec-e115cfa7cbcc"` // ID of the destination account
 	EnvelopeID            *uuid.UUID `json:"envelopeId" example:"2649c965-7999-4873-ae16-89d5d5fa972e"`           // ID of the envelope
-	ReconciledSource      bool       `json:"reconciledSource" example:"true" default:"false"`                     // Is the transaction reconciled in the source account?
+	ClearedSource         bool       `json:"clearedSource" example:"true" default:"false"`                        // Has the transaction cleared in the source account? Reconciled transactions are always cleared
+	ClearedDestination    bool       `json:"clearedDestination" example:"true" default:"false"`                   // Has the transaction cleared in the destination account? Reconciled transactions are always cleared
+	ReconciledSource      bool       `json:"reconciledSource" example:"true" default:"false"`                     // Is the transaction reconciled in the source account? Reconciled transactions can only be changed when they are set to not reconciled in the same request
 	ReconciledDestination bool       `json:"reconciledDestination" example:"true" default:"false"`                // Is the transaction reconciled in the destination account?
 
 	AvailableFrom types.Month `json:"availableFrom" example:"2021-11-17T00:00:00Z"` // The date from which on the transaction amount is available for budgeting. Only used for income transactions. Defaults to the transaction date.
 
 	ImportHash string `json:"importHash" example:"867e3a26dc0baf73f4bff506f31a97f6c32088917e9e5cf1a5ed6f3f84a6fa70" default:""` // The SHA256 hash of a unique combination of values to use in duplicate detection
+
+	TagIDs []uuid.UUID `json:"tagIds" example:"8b8f2a3d-2c2a-4c58-8d3e-2a2ebd8d3c5b"` // IDs of the tags of the transaction. On updates, the tags are replaced with these
 }
 
 // model returns the database resource for the API representation of the editable fields
@@ -35,10 +44,13 @@ func (editable TransactionEditable) model() models.Transaction {
 	return models.Transaction{
 		Date:                  editable.Date,
 		Amount:                editable.Amount,
+		DestinationAmount:     editable.DestinationAmount,
 		Note:                  editable.Note,
 		SourceAccountID:       editable.SourceAccountID,
 		DestinationAccountID:  editable.DestinationAccountID,
 		EnvelopeID:            editable.EnvelopeID,
+		ClearedSource:         editable.ClearedSource,
+		ClearedDestination:    editable.ClearedDestination,
 		ReconciledSource:      editable.ReconciledSource,
 		ReconciledDestination: editable.ReconciledDestination,
 		AvailableFrom:         editable.AvailableFrom,
@@ -47,38 +59,63 @@ func (editable TransactionEditable) model() models.Transaction {
 }
 
 type TransactionLinks struct {
-	Self string `json:"self" example:"https://example.com/api/v4/transactions/d430d7c3-d14c-4712-9336-ee56965a6673"` // The transaction itself
+	Self        string `json:"self" example:"https://example.com/api/v4/transactions/d430d7c3-d14c-4712-9336-ee56965a6673"`                    // The transaction itself
+	Attachments string `json:"attachments" example:"https://example.com/api/v4/transactions/d430d7c3-d14c-4712-9336-ee56965a6673/attachments"` // Attachments of the transaction
 }
 
 // Transaction is the representation of a Transaction in API v4.
 type Transaction struct {
 	models.DefaultModel
 	TransactionEditable
-	Links TransactionLinks `json:"links"`
+	SourceState      models.TransactionState `json:"sourceState" example:"CLEARED"`         // State of the transaction in the source account. One of UNCLEARED, CLEARED or RECONCILED
+	DestinationState models.TransactionState `json:"destinationState" example:"RECONCILED"` // State of the transaction in the destination account. One of UNCLEARED, CLEARED or RECONCILED
+	Links            TransactionLinks        `json:"links"`
 }
 
 // newTransaction returns the API v4 representation of the resource
-func newTransaction(c *gin.Context, model models.Transaction) Transaction {
+func newTransaction(c *gin.Context, db *gorm.DB, model models.Transaction) (Transaction, error) {
 	url := c.GetString(string(models.DBContextURL))
 
+	tagIDs, err := models.TransactionTagIDs(db, model.ID)
+	if err != nil {
+		return Transaction{}, err
+	}
+
 	return Transaction{
 		DefaultModel: model.DefaultModel,
 		TransactionEditable: TransactionEditable{
 			Date:                  model.Date,
 			Amount:                model.Amount,
+			DestinationAmount:     model.DestinationAmount,
 			Note:                  model.Note,
 			SourceAccountID:       model.SourceAccountID,
 			DestinationAccountID:  model.DestinationAccountID,
 			EnvelopeID:            model.EnvelopeID,
+			ClearedSource:         model.ClearedSource || model.ReconciledSource,
+			ClearedDestination:    model.ClearedDestination || model.ReconciledDestination,
 			ReconciledSource:      model.ReconciledSource,
 			ReconciledDestination: model.ReconciledDestination,
 			AvailableFrom:         model.AvailableFrom,
 			ImportHash:            model.ImportHash,
+			TagIDs:                tagIDs,
 		},
+		SourceState:      model.SourceState(),
+		DestinationState: model.DestinationState(),
 		Links: TransactionLinks{
-			Self: fmt.Sprintf("%s/v4/transactions/%s", url, model.ID),
+			Self:        fmt.Sprintf("%s/v4/transactions/%s", url, model.ID),
+			Attachments: fmt.Sprintf("%s/v4/transactions/%s/attachments", url, model.ID),
 		},
-	}
+	}, nil
+}
+
+// transactionTagFields removes the tags from the fields to update since they are not
+// a column of transactions. It returns if the tags are updated.
+func transactionTagFields(fields []any) ([]any, bool) {
+	columns := slices.DeleteFunc(slices.Clone(fields), func(field any) bool {
+		return field == "TagIDs"
+	})
+
+	return columns, len(columns) != len(fields)
 }
 
 type TransactionListResponse struct {
@@ -110,6 +147,40 @@ type TransactionResponse struct {
 	Data  *Transaction `json:"data"`                                                          // The Transaction data, if creation was successful
 }
 
+// TransactionBulkUpdate is the body for updating multiple transactions at once.
+type TransactionBulkUpdate struct {
+	TransactionEditable
+	IDs []uuid.UUID `json:"ids" example:"d430d7c3-d14c-4712-9336-ee56965a6673"` // IDs of the transactions to update. If set, only transactions with these IDs that also match the query parameters are updated.
+}
+
+// TransactionBulkDelete is the body for deleting multiple transactions at once.
+type TransactionBulkDelete struct {
+	IDs []uuid.UUID `json:"ids" example:"d430d7c3-d14c-4712-9336-ee56965a6673"` // IDs of the transactions to delete. If set, only transactions with these IDs that also match the query parameters are deleted.
+}
+
+type TransactionBulkQuery struct {
+	DryRun bool `form:"dryRun"` // Only report what would be changed without changing anything
+}
+
+type TransactionBulkResponse struct {
+	Error *string               `json:"error" example:"either transaction IDs or at least one filter must be set"` // The error, if any occurred
+	Count int                   `json:"count" example:"300"`                                                       // Number of transactions matching the request
+	Data  []TransactionResponse `json:"data"`                                                                      // One entry per matching transaction. For deletions and failed changes, this is the transaction as it was before.
+}
+
+func (t *TransactionBulkResponse) appendError(err error, currentStatus int, transaction Transaction) int {
+	s := err.Error()
+	t.Data = append(t.Data, TransactionResponse{Error: &s, Data: &transaction})
+
+	// The final status code is the highest HTTP status code number
+	newStatus := status(err)
+	if newStatus > currentStatus {
+		return newStatus
+	}
+
+	return currentStatus
+}
+
 // swagger:enum TransactionDirection
 type TransactionDirection string
 
@@ -128,6 +199,14 @@ const (
 	TypeTransfer TransactionType = "TRANSFER"
 )
 
+// swagger:enum TagMatch
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "ANY"
+	TagMatchAll TagMatch = "ALL"
+)
+
 type TransactionQueryFilter struct {
 	AvailableFromDate      time.Time            `form:"availableFromDate" filterField:"false"`      // Exact date. Time is ignored.
 	AvailableFromFromDate  time.Time            `form:"availableFromFromDate" filterField:"false"`  // From this date. Time is ignored.
@@ -145,11 +224,47 @@ type TransactionQueryFilter struct {
 	Direction              TransactionDirection `form:"direction" filterField:"false"`              // Direction of the transaction - are involved accounts internal or external?
 	Type                   TransactionType      `form:"type" filterField:"false"`                   // Type of the transaction - the effect the transaction has on the budget
 	EnvelopeID             ez_uuid.UUID         `form:"envelope"`                                   // ID of the envelope
+	ClearedSource          bool                 `form:"clearedSource" filterField:"false"`          // Is the transaction cleared or reconciled in the source account?
+	ClearedDestination     bool                 `form:"clearedDestination" filterField:"false"`     // Is the transaction cleared or reconciled in the destination account?
 	ReconciledSource       bool                 `form:"reconciledSource"`                           // Is the transaction reconciled in the source account?
 	ReconciledDestination  bool                 `form:"reconciledDestination"`                      // Is the transaction reconciled in the destination account?
 	AccountID              ez_uuid.UUID         `form:"account" filterField:"false"`                // ID of either source or destination account
+	Tags                   string               `form:"tags" filterField:"false"`                   // IDs of tags, separated by commas
+	TagMatch               TagMatch             `form:"tagMatch" filterField:"false"`               // Do transactions need to have any or all of the tags? Defaults to ANY
+	Filter                 string               `form:"filter" filterField:"false"`                 // Filter expression, combined with the other filters
 	Offset                 uint                 `form:"offset" filterField:"false"`                 // The offset of the first Transaction returned. Defaults to 0.
 	Limit                  int                  `form:"limit" filterField:"false"`                  // Maximum number of transactions to return. Defaults to 50.
+	Sort                   string               `form:"sort" filterField:"false"`                   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor                 string               `form:"cursor" filterField:"false"`                 // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// transactionSorting defines how lists of transactions can be sorted
+var transactionSorting = listSorting{
+	table: "transactions",
+	fields: map[string]string{
+		"date":          "datetime(transactions.date)",
+		"availableFrom": "date(transactions.available_from)",
+		"amount":        "transactions.amount",
+		"createdAt":     "transactions.created_at",
+	},
+	defaults: "-date,-createdAt",
+}
+
+// tagIDs returns the IDs of the tags the transactions are filtered by.
+func (f TransactionQueryFilter) tagIDs() ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	for _, s := range strings.Split(f.Tags, ",") {
+		id, err := uuid.Parse(strings.TrimSpace(s))
+		if err != nil {
+			return nil, errTransactionTagsInvalid
+		}
+
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
 }
 
 func (f TransactionQueryFilter) model() (models.Transaction, error) {
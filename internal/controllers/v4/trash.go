@@ -0,0 +1,270 @@
+package v4
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+// RegisterTrashRoutes registers the routes for the trash with
+// the RouterGroup that is passed.
+func RegisterTrashRoutes(r *gin.RouterGroup) {
+	{
+		r.OPTIONS("", OptionsTrashEntries)
+		r.GET("", httputil.NotModified, GetTrashEntries)
+	}
+
+	{
+		r.OPTIONS("/:id", OptionsTrashEntryDetail)
+		r.GET("/:id", GetTrashEntry)
+		r.DELETE("/:id", DeleteTrashEntry)
+		r.OPTIONS("/:id/restore", OptionsTrashEntryRestore)
+		r.POST("/:id/restore", RestoreTrashEntry)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Trash
+// @Success		204
+// @Router			/v4/trash [options]
+func OptionsTrashEntries(c *gin.Context) {
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Trash
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/trash/{id} [options]
+func OptionsTrashEntryDetail(c *gin.Context) {
+	_, err := getTrashEntry(c)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	httputil.OptionsGetDelete(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Trash
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/trash/{id}/restore [options]
+func OptionsTrashEntryRestore(c *gin.Context) {
+	_, err := getTrashEntry(c)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	httputil.OptionsPost(c)
+}
+
+// @Summary		Get trash
+// @Description	Returns deleted resources that can be restored, most recently deleted first
+// @Tags			Trash
+// @Produce		json
+// @Success		200	{object}	TrashEntryListResponse
+// @Success		304
+// @Failure		400				{object}	TrashEntryListResponse
+// @Failure		500				{object}	TrashEntryListResponse
+// @Param			model			query		string	false	"Filter by type of the deleted resource, e.g. Envelope"
+// @Param			resource		query		string	false	"Filter by ID of the deleted resource"
+// @Param			offset			query		uint	false	"The offset of the first trash entry returned. Defaults to 0."
+// @Param			limit			query		int		false	"Maximum number of trash entries to return. Defaults to 50."
+// @Param			sort			query		string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are createdAt, expiresAt. Defaults to -createdAt"
+// @Param			cursor			query		string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/trash [get]
+func GetTrashEntries(c *gin.Context) {
+	var filter TrashEntryQueryFilter
+	if err := c.Bind(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, TrashEntryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// Get the parameters set in the query string
+	_, setFields := httputil.GetURLFields(c.Request.URL, filter)
+
+	q := models.DB.
+		Where("expires_at > ?", time.Now()).
+		Where(&models.TrashEntry{
+			Model:      filter.Model,
+			ResourceID: filter.ResourceID.UUID,
+		})
+
+	// Default to 50 trash entries and set the limit
+	limit := 50
+	if slices.Contains(setFields, "Limit") {
+		limit = filter.Limit
+	}
+
+	page, err := newListPage(trashEntrySorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, TrashEntryListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
+
+	var entries []models.TrashEntry
+	err = q.Find(&entries).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TrashEntryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	entries, pagination, err := paginate(c, q, page, entries)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TrashEntryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := make([]TrashEntry, 0, len(entries))
+	for _, entry := range entries {
+		data = append(data, newTrashEntry(c, entry))
+	}
+
+	c.JSON(http.StatusOK, TrashEntryListResponse{
+		Data:       data,
+		Pagination: &pagination,
+	})
+}
+
+// @Summary		Get trash entry
+// @Description	Returns a specific deleted resource
+// @Tags			Trash
+// @Produce		json
+// @Success		200	{object}	TrashEntryResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
+// @Failure		400	{object}	TrashEntryResponse
+// @Failure		404	{object}	TrashEntryResponse
+// @Failure		500	{object}	TrashEntryResponse
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/trash/{id} [get]
+func GetTrashEntry(c *gin.Context) {
+	entry, err := getTrashEntry(c)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), TrashEntryResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := newTrashEntry(c, entry)
+	c.Header("ETag", httputil.ETag(entry.UpdatedAt))
+	c.JSON(http.StatusOK, TrashEntryResponse{Data: &data})
+}
+
+// @Summary		Delete trash entry
+// @Description	Permanently deletes a deleted resource so that it can not be restored anymore
+// @Tags			Trash
+// @Success		204
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/trash/{id} [delete]
+func DeleteTrashEntry(c *gin.Context) {
+	entry, err := getTrashEntry(c)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &entry)
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&entry).Error
+	})
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Restore trash entry
+// @Description	Restores a deleted resource together with all resources that were deleted with it. If any of them can not be restored, none are.
+// @Tags			Trash
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/trash/{id}/restore [post]
+func RestoreTrashEntry(c *gin.Context) {
+	entry, err := getTrashEntry(c)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	err = entry.Restore(models.DB.WithContext(c))
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// getTrashEntry returns the trash entry for the ID in the URI.
+//
+// Expired entries are treated as if they did not exist.
+func getTrashEntry(c *gin.Context) (models.TrashEntry, error) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		return models.TrashEntry{}, err
+	}
+
+	var entry models.TrashEntry
+	err = models.DB.Where("expires_at > ?", time.Now()).First(&entry, uri.ID).Error
+	return entry, err
+}
@@ -0,0 +1,137 @@
+package v4_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTrashRestore verifies that a deleted envelope is restored together
+// with its transactions.
+func (suite *TestSuiteStandard) TestTrashRestore() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID})
+	bank := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Bank", OnBudget: true})
+	shop := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Shop", External: true})
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{
+		SourceAccountID:      bank.Data.ID,
+		DestinationAccountID: shop.Data.ID,
+		EnvelopeID:           &envelope.Data.ID,
+		Amount:               decimal.NewFromFloat(42),
+	})
+
+	recorder := test.Request(suite.T(), http.MethodDelete, envelope.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, transaction.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("http://example.com/v4/trash?resource=%s", envelope.Data.ID), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var trash v4.TrashEntryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &trash)
+	require.Len(suite.T(), trash.Data, 1)
+
+	entry := trash.Data[0]
+	assert.Equal(suite.T(), "Envelope", entry.Model)
+	assert.Len(suite.T(), entry.Resources, 2)
+	assert.True(suite.T(), entry.ExpiresAt.After(entry.DeletedAt))
+
+	recorder = test.Request(suite.T(), http.MethodPost, entry.Links.Restore, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, transaction.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var restored v4.TransactionResponse
+	test.DecodeResponse(suite.T(), &recorder, &restored)
+	assert.Equal(suite.T(), envelope.Data.ID, *restored.Data.EnvelopeID)
+
+	recorder = test.Request(suite.T(), http.MethodGet, entry.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+}
+
+// TestTrashRestoreFails verifies that restoring fails when a referenced
+// resource does not exist anymore.
+func (suite *TestSuiteStandard) TestTrashRestoreFails() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID})
+
+	recorder := test.Request(suite.T(), http.MethodDelete, envelope.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodDelete, category.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, "http://example.com/v4/trash?model=Envelope", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var trash v4.TrashEntryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &trash)
+	require.Len(suite.T(), trash.Data, 1)
+
+	recorder = test.Request(suite.T(), http.MethodPost, trash.Data[0].Links.Restore, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+}
+
+// TestTrashDelete verifies that trash entries can be deleted permanently.
+func (suite *TestSuiteStandard) TestTrashDelete() {
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+
+	recorder := test.Request(suite.T(), http.MethodDelete, budget.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, "http://example.com/v4/trash", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var trash v4.TrashEntryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &trash)
+	require.Len(suite.T(), trash.Data, 1)
+
+	recorder = test.Request(suite.T(), http.MethodOptions, trash.Data[0].Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET, DELETE", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodOptions, trash.Data[0].Links.Restore, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, POST", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodDelete, trash.Data[0].Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodPost, trash.Data[0].Links.Restore, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+}
+
+// TestTrashFails verifies that requests for trash entries fail for invalid IDs.
+func (suite *TestSuiteStandard) TestTrashFails() {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"GET not a UUID", http.MethodGet, "/notauuid", http.StatusBadRequest},
+		{"GET not found", http.MethodGet, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"DELETE not found", http.MethodDelete, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"OPTIONS not found", http.MethodOptions, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a", http.StatusNotFound},
+		{"Restore not found", http.MethodPost, "/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/restore", http.StatusNotFound},
+		{"List invalid resource", http.MethodGet, "?resource=notauuid", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, tt.method, fmt.Sprintf("http://example.com/v4/trash%s", tt.path), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
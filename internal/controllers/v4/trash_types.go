@@ -0,0 +1,75 @@
+package v4
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	ez_uuid "github.com/envelope-zero/backend/v7/internal/uuid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TrashEntryLinks struct {
+	Self    string `json:"self" example:"https://example.com/api/v4/trash/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a"`            // The trash entry itself
+	Restore string `json:"restore" example:"https://example.com/api/v4/trash/4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a/restore"` // Restores the deleted resources
+}
+
+// TrashEntry is the API representation of a deleted resource in the trash.
+type TrashEntry struct {
+	ID         uuid.UUID              `json:"id" example:"4e9bbd4e-8d6c-4a5f-9a4e-0d7c6a3f2b1a"`         // ID of the trash entry
+	DeletedAt  time.Time              `json:"deletedAt" example:"2024-04-02T19:28:44.491514Z"`           // Time the resource was deleted
+	ExpiresAt  time.Time              `json:"expiresAt" example:"2024-05-02T19:28:44.491514Z"`           // Time after which the resource can not be restored anymore
+	Model      string                 `json:"model" example:"Envelope"`                                  // Type of the deleted resource
+	ResourceID uuid.UUID              `json:"resourceId" example:"0b4ea4b5-1a8e-4c35-9fa0-1a2a1a0a4f3e"` // ID of the deleted resource
+	Resources  []models.TrashResource `json:"resources"`                                                 // The deleted resource, followed by all resources that were deleted with it
+	Links      TrashEntryLinks        `json:"links"`                                                     // Links for the trash entry
+}
+
+func newTrashEntry(c *gin.Context, model models.TrashEntry) TrashEntry {
+	url := c.GetString(string(models.DBContextURL))
+	self := fmt.Sprintf("%s/v4/trash/%s", url, model.ID)
+
+	return TrashEntry{
+		ID:         model.ID,
+		DeletedAt:  model.CreatedAt,
+		ExpiresAt:  model.ExpiresAt,
+		Model:      model.Model,
+		ResourceID: model.ResourceID,
+		Resources:  model.Resources,
+		Links: TrashEntryLinks{
+			Self:    self,
+			Restore: self + "/restore",
+		},
+	}
+}
+
+type TrashEntryResponse struct {
+	Data  *TrashEntry `json:"data"`                                                          // Data for the trash entry
+	Error *string     `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+type TrashEntryListResponse struct {
+	Data       []TrashEntry `json:"data"`                                                          // List of trash entries
+	Error      *string      `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Pagination *Pagination  `json:"pagination"`                                                    // Pagination information
+}
+
+type TrashEntryQueryFilter struct {
+	Model      string       `form:"model"`    // By type of the deleted resource, e.g. "Envelope"
+	ResourceID ez_uuid.UUID `form:"resource"` // By ID of the deleted resource
+	Offset     uint         `form:"offset"`   // The offset of the first trash entry returned. Defaults to 0.
+	Limit      int          `form:"limit"`    // Maximum number of trash entries to return. Defaults to 50.
+	Sort       string       `form:"sort"`     // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor     string       `form:"cursor"`   // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// trashEntrySorting defines how lists of trash entries can be sorted
+var trashEntrySorting = listSorting{
+	table: "trash_entries",
+	fields: map[string]string{
+		"createdAt": "trash_entries.created_at",
+		"expiresAt": "trash_entries.expires_at",
+	},
+	defaults: "-createdAt",
+}
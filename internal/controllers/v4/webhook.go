@@ -0,0 +1,431 @@
+package v4
+
+import (
+	"net/http"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+// RegisterWebhookRoutes registers the routes for webhooks with
+// the RouterGroup that is passed.
+func RegisterWebhookRoutes(r *gin.RouterGroup) {
+	// Root group
+	{
+		r.OPTIONS("", OptionsWebhookList)
+		r.GET("", httputil.NotModified, GetWebhooks)
+		r.POST("", CreateWebhooks)
+	}
+
+	// Webhook with ID
+	{
+		r.OPTIONS("/:id", OptionsWebhookDetail)
+		r.GET("/:id", GetWebhook)
+		r.PATCH("/:id", UpdateWebhook)
+		r.DELETE("/:id", DeleteWebhook)
+	}
+
+	// Deliveries of the webhook
+	{
+		r.OPTIONS("/:id/deliveries", OptionsWebhookDeliveries)
+		r.GET("/:id/deliveries", httputil.NotModified, GetWebhookDeliveries)
+	}
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Webhooks
+// @Success		204
+// @Router			/v4/webhooks [options]
+func OptionsWebhookList(c *gin.Context) {
+	httputil.OptionsGetPost(c)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Webhooks
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/webhooks/{id} [options]
+func OptionsWebhookDetail(c *gin.Context) {
+	resourceOptionsDetail(c, models.Webhook{})
+}
+
+// @Summary		Create webhooks
+// @Description	Creates new webhooks. Events are "envelope.negative" and, for every type of resource, events for created, updated and deleted resources, e.g. "transaction.created" or "monthConfig.updated".
+// @Description	Payloads are sent as POST requests and signed with the secret. The X-Envelope-Zero-Signature header contains "sha256=" followed by the hex encoded HMAC-SHA256 of the body.
+// @Tags			Webhooks
+// @Produce		json
+// @Success		201			{object}	WebhookCreateResponse
+// @Failure		400			{object}	WebhookCreateResponse
+// @Failure		500			{object}	WebhookCreateResponse
+// @Param			webhooks	body		[]WebhookEditable	true	"Webhooks"
+// @Router			/v4/webhooks [post]
+func CreateWebhooks(c *gin.Context) {
+	var editables []WebhookEditable
+
+	// Bind data and return error if not possible
+	err := httputil.BindData(c, &editables)
+	if err != nil {
+		e := err.Error()
+		c.JSON(status(err), WebhookCreateResponse{
+			Error: &e,
+		})
+		return
+	}
+
+	// The final http status. Will be modified when errors occur
+	status := http.StatusCreated
+	r := WebhookCreateResponse{}
+
+	for _, editable := range editables {
+		webhook := editable.model()
+
+		err = models.DB.WithContext(c).Create(&webhook).Error
+		if err != nil {
+			status = r.appendError(err, status)
+			continue
+		}
+
+		data := newWebhook(c, webhook)
+		r.Data = append(r.Data, WebhookResponse{Data: &data})
+	}
+
+	c.JSON(status, r)
+}
+
+// @Summary		Get webhooks
+// @Description	Returns all webhooks, sorted by URL
+// @Tags			Webhooks
+// @Produce		json
+// @Success		200	{object}	WebhookListResponse
+// @Success		304
+// @Failure		500				{object}	WebhookListResponse
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/webhooks [get]
+func GetWebhooks(c *gin.Context) {
+	var webhooks []models.Webhook
+	err := models.DB.Order("url ASC, created_at ASC").Find(&webhooks).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// When there are no resources, we want an empty list, not null
+	data := make([]Webhook, 0)
+	for _, webhook := range webhooks {
+		data = append(data, newWebhook(c, webhook))
+	}
+
+	c.JSON(http.StatusOK, WebhookListResponse{Data: data})
+}
+
+// @Summary		Get webhook
+// @Description	Returns a specific webhook
+// @Tags			Webhooks
+// @Produce		json
+// @Success		200	{object}	WebhookResponse
+// @Header			200	{string}	ETag	"Entity tag of the resource, changes with every update"
+// @Failure		400	{object}	WebhookResponse
+// @Failure		404	{object}	WebhookResponse
+// @Failure		500	{object}	WebhookResponse
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/webhooks/{id} [get]
+func GetWebhook(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var webhook models.Webhook
+	err = models.DB.First(&webhook, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := newWebhook(c, webhook)
+	c.Header("ETag", httputil.ETag(webhook.UpdatedAt))
+	c.JSON(http.StatusOK, WebhookResponse{Data: &data})
+}
+
+// @Summary		Update webhook
+// @Description	Update an existing webhook. Only values to be updated need to be specified.
+// @Tags			Webhooks
+// @Accept			json
+// @Produce		json
+// @Success		200			{object}	WebhookResponse
+// @Failure		400			{object}	WebhookResponse
+// @Failure		404			{object}	WebhookResponse
+// @Failure		412			{object}	WebhookResponse
+// @Failure		500			{object}	WebhookResponse
+// @Param			id			path		URIID			true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			webhook		body		WebhookEditable	true	"Webhook"
+// @Param			If-Match	header		string			false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/webhooks/{id} [patch]
+func UpdateWebhook(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var webhook models.Webhook
+	err = models.DB.First(&webhook, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	updateFields, err := httputil.GetBodyFields(c, WebhookEditable{})
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var data WebhookEditable
+	err = httputil.BindData(c, &data)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &webhook)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&webhook).Select("", updateFields...).Updates(data.model()).Error
+	})
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	r := newWebhook(c, webhook)
+	c.Header("ETag", httputil.ETag(webhook.UpdatedAt))
+	c.JSON(http.StatusOK, WebhookResponse{Data: &r})
+}
+
+// @Summary		Delete webhook
+// @Description	Permanently deletes a webhook together with its deliveries
+// @Tags			Webhooks
+// @Success		204
+// @Failure		400			{object}	httpError
+// @Failure		404			{object}	httpError
+// @Failure		412			{object}	httpError
+// @Failure		500			{object}	httpError
+// @Param			id			path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			If-Match	header		string	false	"ETag of the resource. If the resource has changed since, the request fails with status 412"
+// @Router			/v4/webhooks/{id} [delete]
+func DeleteWebhook(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	var webhook models.Webhook
+	err = models.DB.First(&webhook, uri.ID).Error
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	err = models.DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		err := ifMatch(c, tx, &webhook)
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&webhook).Error
+	})
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Allowed HTTP verbs
+// @Description	Returns an empty response with the HTTP Header "allow" set to the allowed HTTP verbs
+// @Tags			Webhooks
+// @Success		204
+// @Failure		400	{object}	httpError
+// @Failure		404	{object}	httpError
+// @Failure		500	{object}	httpError
+// @Param			id	path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Router			/v4/webhooks/{id}/deliveries [options]
+func OptionsWebhookDeliveries(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	err = models.DB.First(&models.Webhook{}, uri.ID).Error
+	if err != nil {
+		c.JSON(status(err), httpError{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	httputil.OptionsGet(c)
+}
+
+// @Summary		Get deliveries
+// @Description	Returns the delivery log of a webhook, newest first. Failed deliveries are retried with an exponential backoff.
+// @Tags			Webhooks
+// @Produce		json
+// @Success		200	{object}	WebhookDeliveryListResponse
+// @Success		304
+// @Failure		400				{object}	WebhookDeliveryListResponse
+// @Failure		404				{object}	WebhookDeliveryListResponse
+// @Failure		500				{object}	WebhookDeliveryListResponse
+// @Param			id				path		URIID	true	"ignored, but needed: https://github.com/swaggo/swag/issues/1014"
+// @Param			event			query		string	false	"Filter by event"
+// @Param			status			query		string	false	"Filter by status. One of PENDING, SUCCEEDED, FAILED"
+// @Param			offset			query		uint	false	"The offset of the first delivery returned. Defaults to 0."
+// @Param			limit			query		int		false	"Maximum number of deliveries to return. Defaults to 50."
+// @Param			sort			query		string	false	"Fields to sort by, separated by commas. Prefix a field with - for descending order. Fields are createdAt. Defaults to -createdAt"
+// @Param			cursor			query		string	false	"Cursor of the page to return, taken from the next or previous link of another page. Cannot be used with offset"
+// @Param			If-None-Match	header		string	false	"ETag of an earlier response. If the data has not changed, the response is empty with status 304"
+// @Router			/v4/webhooks/{id}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	var uri URIID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookDeliveryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	err = models.DB.First(&models.Webhook{}, uri.ID).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookDeliveryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	var filter WebhookDeliveryQueryFilter
+	if err := c.Bind(&filter); err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, WebhookDeliveryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	// Get the parameters set in the query string
+	_, setFields := httputil.GetURLFields(c.Request.URL, filter)
+
+	if filter.Status != "" && !slices.Contains([]models.WebhookDeliveryStatus{models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed}, filter.Status) {
+		s := errWebhookDeliveryStatusInvalid.Error()
+		c.JSON(http.StatusBadRequest, WebhookDeliveryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	q := models.DB.
+		Where(&models.WebhookDelivery{
+			WebhookID: uri.ID.UUID,
+			Event:     filter.Event,
+			Status:    filter.Status,
+		})
+
+	// Default to 50 deliveries and set the limit
+	limit := 50
+	if slices.Contains(setFields, "Limit") {
+		limit = filter.Limit
+	}
+
+	page, err := newListPage(webhookDeliverySorting, filter.Sort, filter.Cursor, filter.Offset, limit)
+	if err != nil {
+		s := err.Error()
+		c.JSON(http.StatusBadRequest, WebhookDeliveryListResponse{
+			Error: &s,
+		})
+		return
+	}
+	q = page.query(q)
+
+	var deliveries []models.WebhookDelivery
+	err = q.Find(&deliveries).Error
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookDeliveryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	deliveries, pagination, err := paginate(c, q, page, deliveries)
+	if err != nil {
+		s := err.Error()
+		c.JSON(status(err), WebhookDeliveryListResponse{
+			Error: &s,
+		})
+		return
+	}
+
+	data := make([]WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		data = append(data, newWebhookDelivery(c, delivery))
+	}
+
+	c.JSON(http.StatusOK, WebhookDeliveryListResponse{
+		Data:       data,
+		Pagination: &pagination,
+	})
+}
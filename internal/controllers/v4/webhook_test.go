@@ -0,0 +1,228 @@
+package v4_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/envelope-zero/backend/v7/internal/webhook"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestWebhook(t *testing.T, c v4.WebhookEditable, expectedStatus ...int) v4.WebhookResponse {
+	if c.URL == "" {
+		c.URL = "https://example.com/hook"
+	}
+
+	// Default to 201 Created as expected status
+	if len(expectedStatus) == 0 {
+		expectedStatus = append(expectedStatus, http.StatusCreated)
+	}
+
+	r := test.Request(t, http.MethodPost, "http://example.com/v4/webhooks", []v4.WebhookEditable{c})
+	test.AssertHTTPStatus(t, &r, expectedStatus...)
+
+	var w v4.WebhookCreateResponse
+	test.DecodeResponse(t, &r, &w)
+
+	return w.Data[0]
+}
+
+// TestWebhooks verifies that webhooks can be created, listed, updated and deleted.
+func (suite *TestSuiteStandard) TestWebhooks() {
+	w := createTestWebhook(suite.T(), v4.WebhookEditable{URL: "https://home.example.com/hook", Events: []models.WebhookEvent{"transaction.created"}})
+	assert.Len(suite.T(), w.Data.Secret, 64, "A secret must be generated")
+	assert.Equal(suite.T(), fmt.Sprintf("http://example.com/v4/webhooks/%s/deliveries", w.Data.ID), w.Data.Links.Deliveries)
+
+	_ = createTestWebhook(suite.T(), v4.WebhookEditable{URL: "https://chat.example.com/hook", Secret: "s3cr3t", Events: []models.WebhookEvent{"envelope.negative"}})
+
+	recorder := test.Request(suite.T(), http.MethodGet, "http://example.com/v4/webhooks", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var list v4.WebhookListResponse
+	test.DecodeResponse(suite.T(), &recorder, &list)
+	require.Len(suite.T(), list.Data, 2)
+	assert.Equal(suite.T(), "https://chat.example.com/hook", list.Data[0].URL)
+	assert.Equal(suite.T(), "s3cr3t", list.Data[0].Secret)
+
+	recorder = test.Request(suite.T(), http.MethodPatch, w.Data.Links.Self, map[string]any{"events": []string{"transaction.created", "envelope.negative"}})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	recorder = test.Request(suite.T(), http.MethodGet, w.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var get v4.WebhookResponse
+	test.DecodeResponse(suite.T(), &recorder, &get)
+	assert.Equal(suite.T(), []models.WebhookEvent{"transaction.created", "envelope.negative"}, get.Data.Events)
+	assert.Equal(suite.T(), w.Data.Secret, get.Data.Secret)
+
+	recorder = test.Request(suite.T(), http.MethodOptions, "http://example.com/v4/webhooks", "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET, POST", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodOptions, w.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET, PATCH, DELETE", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodOptions, w.Data.Links.Deliveries, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+	assert.Equal(suite.T(), "OPTIONS, GET", recorder.Header().Get("allow"))
+
+	recorder = test.Request(suite.T(), http.MethodDelete, w.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNoContent)
+
+	recorder = test.Request(suite.T(), http.MethodGet, w.Data.Links.Self, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusNotFound)
+}
+
+// TestWebhooksFails verifies that invalid webhooks and requests fail.
+func (suite *TestSuiteStandard) TestWebhooksFails() {
+	r := createTestWebhook(suite.T(), v4.WebhookEditable{URL: "example.com", Events: []models.WebhookEvent{"transaction.created"}}, http.StatusBadRequest)
+	assert.Equal(suite.T(), models.ErrWebhookURLInvalid.Error(), *r.Error)
+
+	r = createTestWebhook(suite.T(), v4.WebhookEditable{Events: []models.WebhookEvent{"transaction.exploded"}}, http.StatusBadRequest)
+	assert.Contains(suite.T(), *r.Error, "transaction.exploded")
+
+	w := createTestWebhook(suite.T(), v4.WebhookEditable{Events: []models.WebhookEvent{"transaction.created"}})
+	recorder := test.Request(suite.T(), http.MethodPatch, w.Data.Links.Self, map[string]any{"events": []string{}})
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("%s?status=LOST", w.Data.Links.Deliveries), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusBadRequest)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"GET not a UUID", http.MethodGet, "webhooks/notauuid", http.StatusBadRequest},
+		{"GET not found", http.MethodGet, fmt.Sprintf("webhooks/%s", uuid.New()), http.StatusNotFound},
+		{"PATCH not found", http.MethodPatch, fmt.Sprintf("webhooks/%s", uuid.New()), http.StatusNotFound},
+		{"DELETE not a UUID", http.MethodDelete, "webhooks/notauuid", http.StatusBadRequest},
+		{"DELETE not found", http.MethodDelete, fmt.Sprintf("webhooks/%s", uuid.New()), http.StatusNotFound},
+		{"OPTIONS not found", http.MethodOptions, fmt.Sprintf("webhooks/%s", uuid.New()), http.StatusNotFound},
+		{"Deliveries not a UUID", http.MethodGet, "webhooks/notauuid/deliveries", http.StatusBadRequest},
+		{"Deliveries not found", http.MethodGet, fmt.Sprintf("webhooks/%s/deliveries", uuid.New()), http.StatusNotFound},
+		{"OPTIONS deliveries not found", http.MethodOptions, fmt.Sprintf("webhooks/%s/deliveries", uuid.New()), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			recorder := test.Request(t, tt.method, fmt.Sprintf("http://example.com/v4/%s", tt.path), "")
+			test.AssertHTTPStatus(t, &recorder, tt.status)
+		})
+	}
+}
+
+// TestWebhookDeliveries verifies that created transactions and negative envelopes are sent
+// to a receiver and logged as deliveries.
+func (suite *TestSuiteStandard) TestWebhookDeliveries() {
+	var payloads []models.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var p models.WebhookPayload
+		_ = json.Unmarshal(body, &p)
+		payloads = append(payloads, p)
+
+		// The first payload is rejected so that it is retried
+		if len(payloads) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	w := createTestWebhook(suite.T(), v4.WebhookEditable{URL: server.URL, Events: []models.WebhookEvent{"transaction.created", "envelope.negative"}})
+
+	budget := createTestBudget(suite.T(), v4.BudgetEditable{})
+	category := createTestCategory(suite.T(), v4.CategoryEditable{BudgetID: budget.Data.ID})
+	envelope := createTestEnvelope(suite.T(), v4.EnvelopeEditable{CategoryID: category.Data.ID})
+	account := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Checking", OnBudget: true})
+	external := createTestAccount(suite.T(), v4.AccountEditable{BudgetID: budget.Data.ID, Name: "Grocery Store", External: true})
+
+	january := types.NewMonth(2024, 1)
+	patchTestMonthConfig(suite.T(), envelope.Data.ID, january, v4.MonthConfigEditable{Allocation: decimal.NewFromFloat(10)})
+
+	transaction := createTestTransaction(suite.T(), v4.TransactionEditable{
+		Amount:               decimal.NewFromFloat(25),
+		EnvelopeID:           &envelope.Data.ID,
+		SourceAccountID:      account.Data.ID,
+		DestinationAccountID: external.Data.ID,
+		Date:                 time.Time(january),
+	})
+
+	require.Nil(suite.T(), webhook.Dispatcher{}.Dispatch(context.Background()))
+	require.Len(suite.T(), payloads, 2)
+
+	recorder := test.Request(suite.T(), http.MethodGet, w.Data.Links.Deliveries, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var deliveries v4.WebhookDeliveryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &deliveries)
+	require.Len(suite.T(), deliveries.Data, 2)
+
+	events := []models.WebhookEvent{deliveries.Data[0].Event, deliveries.Data[1].Event}
+	assert.ElementsMatch(suite.T(), []models.WebhookEvent{"transaction.created", "envelope.negative"}, events)
+
+	for _, p := range payloads {
+		switch p.Event {
+		case "transaction.created":
+			assert.Equal(suite.T(), transaction.Data.ID.String(), p.Data.(map[string]any)["resourceId"])
+		case "envelope.negative":
+			assert.Equal(suite.T(), envelope.Data.ID.String(), p.Data.(map[string]any)["envelopeId"])
+			assert.Equal(suite.T(), "-15", p.Data.(map[string]any)["balance"])
+		}
+	}
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("%s?status=PENDING", w.Data.Links.Deliveries), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	test.DecodeResponse(suite.T(), &recorder, &deliveries)
+	require.Len(suite.T(), deliveries.Data, 1, "The rejected delivery must be pending for a retry")
+	assert.Equal(suite.T(), payloads[0].ID, deliveries.Data[0].ID)
+	assert.Equal(suite.T(), http.StatusInternalServerError, deliveries.Data[0].ResponseStatus)
+	assert.Equal(suite.T(), 1, deliveries.Data[0].Attempts)
+	assert.NotNil(suite.T(), deliveries.Data[0].NextAttemptAt)
+	assert.Equal(suite.T(), w.Data.Links.Self, deliveries.Data[0].Links.Webhook)
+
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("%s?event=envelope.negative", w.Data.Links.Deliveries), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+	test.DecodeResponse(suite.T(), &recorder, &deliveries)
+	require.Len(suite.T(), deliveries.Data, 1)
+	assert.Equal(suite.T(), models.WebhookEventEnvelopeNegative, deliveries.Data[0].Event)
+
+	// The delivery log can be paged through
+	recorder = test.Request(suite.T(), http.MethodGet, fmt.Sprintf("%s?limit=1", w.Data.Links.Deliveries), "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var first v4.WebhookDeliveryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &first)
+	require.Len(suite.T(), first.Data, 1)
+	require.NotNil(suite.T(), first.Pagination.Next)
+	assert.True(suite.T(), strings.HasPrefix(*first.Pagination.Next, fmt.Sprintf("%s?", w.Data.Links.Deliveries)), *first.Pagination.Next)
+
+	recorder = test.Request(suite.T(), http.MethodGet, *first.Pagination.Next, "")
+	test.AssertHTTPStatus(suite.T(), &recorder, http.StatusOK)
+
+	var second v4.WebhookDeliveryListResponse
+	test.DecodeResponse(suite.T(), &recorder, &second)
+	require.Len(suite.T(), second.Data, 1)
+	assert.NotEqual(suite.T(), first.Data[0].ID, second.Data[0].ID)
+	assert.Nil(suite.T(), second.Pagination.Next)
+	assert.NotNil(suite.T(), second.Pagination.Previous)
+}
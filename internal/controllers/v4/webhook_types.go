@@ -0,0 +1,148 @@
+package v4
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookEditable represents all user configurable parameters
+type WebhookEditable struct {
+	URL    string                `json:"url" example:"https://home.example.com/hooks/envelope-zero"`                        // URL the payloads are sent to with a POST request
+	Secret string                `json:"secret" example:"8a2d0f6b5c3e4a1f9e7d6c5b4a3f2e1d" default:""`                      // Secret to sign the payloads with. Generated if not set on creation
+	Events []models.WebhookEvent `json:"events" example:"transaction.created,envelope.negative" swaggertype:"array,string"` // Events the webhook is notified about
+}
+
+func (editable WebhookEditable) model() models.Webhook {
+	return models.Webhook{
+		URL:    editable.URL,
+		Secret: editable.Secret,
+		Events: editable.Events,
+	}
+}
+
+type WebhookLinks struct {
+	Self       string `json:"self" example:"https://example.com/api/v4/webhooks/1b6a3d36-5a4b-4f6e-9d2c-8e7f6a5b4c3d"`                  // The webhook itself
+	Deliveries string `json:"deliveries" example:"https://example.com/api/v4/webhooks/1b6a3d36-5a4b-4f6e-9d2c-8e7f6a5b4c3d/deliveries"` // Deliveries of the webhook
+}
+
+type Webhook struct {
+	models.DefaultModel
+	WebhookEditable
+	Links WebhookLinks `json:"links"`
+}
+
+func newWebhook(c *gin.Context, model models.Webhook) Webhook {
+	url := c.GetString(string(models.DBContextURL))
+
+	return Webhook{
+		DefaultModel: model.DefaultModel,
+		WebhookEditable: WebhookEditable{
+			URL:    model.URL,
+			Secret: model.Secret,
+			Events: model.Events,
+		},
+		Links: WebhookLinks{
+			Self:       fmt.Sprintf("%s/v4/webhooks/%s", url, model.ID),
+			Deliveries: fmt.Sprintf("%s/v4/webhooks/%s/deliveries", url, model.ID),
+		},
+	}
+}
+
+type WebhookListResponse struct {
+	Data  []Webhook `json:"data"`                                                          // List of webhooks
+	Error *string   `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+type WebhookCreateResponse struct {
+	Data  []WebhookResponse `json:"data"`                                                          // List of the created webhooks or their respective error
+	Error *string           `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+func (w *WebhookCreateResponse) appendError(err error, currentStatus int) int {
+	s := err.Error()
+	w.Data = append(w.Data, WebhookResponse{Error: &s})
+
+	// The final status code is the highest HTTP status code number
+	newStatus := status(err)
+	if newStatus > currentStatus {
+		return newStatus
+	}
+
+	return currentStatus
+}
+
+type WebhookResponse struct {
+	Data  *Webhook `json:"data"`                                                          // Data for the webhook
+	Error *string  `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+}
+
+type WebhookDeliveryLinks struct {
+	Webhook string `json:"webhook" example:"https://example.com/api/v4/webhooks/1b6a3d36-5a4b-4f6e-9d2c-8e7f6a5b4c3d"` // The webhook of the delivery
+}
+
+// WebhookDelivery is the API representation of an entry in the delivery log of a webhook.
+type WebhookDelivery struct {
+	models.DefaultModel
+	WebhookID      uuid.UUID                    `json:"webhookId" example:"1b6a3d36-5a4b-4f6e-9d2c-8e7f6a5b4c3d"` // ID of the webhook
+	Event          models.WebhookEvent          `json:"event" example:"transaction.created"`                      // The event the webhook is notified about
+	Payload        json.RawMessage              `json:"payload" swaggertype:"object"`                             // The payload that is sent
+	Status         models.WebhookDeliveryStatus `json:"status" example:"PENDING"`                                 // Status of the delivery. One of PENDING, SUCCEEDED, FAILED
+	Attempts       int                          `json:"attempts" example:"2"`                                     // Number of attempts to send the payload
+	NextAttemptAt  *time.Time                   `json:"nextAttemptAt" example:"2024-04-02T19:32:44.491514Z"`      // Time of the next attempt. Only set for pending deliveries
+	ResponseStatus int                          `json:"responseStatus" example:"503"`                             // HTTP status of the response to the last attempt. 0 if no response was received
+	Error          string                       `json:"error" example:"the receiver responded with status 503"`   // Why the last attempt failed
+	Links          WebhookDeliveryLinks         `json:"links"`                                                    // Links for the delivery
+}
+
+func newWebhookDelivery(c *gin.Context, model models.WebhookDelivery) WebhookDelivery {
+	url := c.GetString(string(models.DBContextURL))
+
+	var nextAttemptAt *time.Time
+	if model.Status == models.WebhookDeliveryPending {
+		nextAttemptAt = &model.NextAttemptAt
+	}
+
+	return WebhookDelivery{
+		DefaultModel:   model.DefaultModel,
+		WebhookID:      model.WebhookID,
+		Event:          model.Event,
+		Payload:        json.RawMessage(model.Payload),
+		Status:         model.Status,
+		Attempts:       model.Attempts,
+		NextAttemptAt:  nextAttemptAt,
+		ResponseStatus: model.ResponseStatus,
+		Error:          model.Error,
+		Links: WebhookDeliveryLinks{
+			Webhook: fmt.Sprintf("%s/v4/webhooks/%s", url, model.WebhookID),
+		},
+	}
+}
+
+type WebhookDeliveryListResponse struct {
+	Data       []WebhookDelivery `json:"data"`                                                          // List of deliveries
+	Error      *string           `json:"error" example:"the specified resource ID is not a valid UUID"` // The error, if any occurred
+	Pagination *Pagination       `json:"pagination"`                                                    // Pagination information
+}
+
+type WebhookDeliveryQueryFilter struct {
+	Event  models.WebhookEvent          `form:"event"`  // By event
+	Status models.WebhookDeliveryStatus `form:"status"` // By status
+	Offset uint                         `form:"offset"` // The offset of the first delivery returned. Defaults to 0.
+	Limit  int                          `form:"limit"`  // Maximum number of deliveries to return. Defaults to 50.
+	Sort   string                       `form:"sort"`   // Fields to sort by, separated by commas. Prefix a field with - for descending order
+	Cursor string                       `form:"cursor"` // Cursor of the page to return. Taken from the next or previous link of another page
+}
+
+// webhookDeliverySorting defines how lists of deliveries can be sorted
+var webhookDeliverySorting = listSorting{
+	table: "webhook_deliveries",
+	fields: map[string]string{
+		"createdAt": "webhook_deliveries.created_at",
+	},
+	defaults: "-createdAt",
+}
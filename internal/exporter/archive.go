@@ -0,0 +1,53 @@
+package exporter
+
+import (
+	"archive/zip"
+	"io"
+	"path"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/storage"
+)
+
+// Archive writes a zip archive with the JSON export as export.json
+// and the file of each attachment as attachments/<ID>/<file name>.
+func Archive(w io.Writer, export []byte, attachments []models.Attachment, files storage.Storage) error {
+	archive := zip.NewWriter(w)
+
+	f, err := archive.Create("export.json")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(export)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		// Only the base name is used so that file names cannot
+		// point outside of the directory of the attachment
+		name := path.Base(attachment.FileName)
+		if name == "." || name == "/" || name == ".." {
+			name = "file"
+		}
+
+		f, err := archive.Create(path.Join("attachments", attachment.ID.String(), name))
+		if err != nil {
+			return err
+		}
+
+		content, err := files.Open(attachment.StorageName())
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(f, content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
@@ -0,0 +1,47 @@
+package exporter
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"io"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+)
+
+// CSV writes a zip archive with one CSV file per table.
+func CSV(w io.Writer, tables []models.Table) error {
+	archive := zip.NewWriter(w)
+
+	for _, table := range tables {
+		f, err := archive.Create(fileName(table) + ".csv")
+		if err != nil {
+			return err
+		}
+
+		writer := csv.NewWriter(f)
+		err = writer.Write(table.Header)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range table.Rows {
+			record := make([]string, 0, len(row))
+			for _, cell := range row {
+				record = append(record, text(cell))
+			}
+
+			err = writer.Write(record)
+			if err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		err = writer.Error()
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
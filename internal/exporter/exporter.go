@@ -0,0 +1,47 @@
+// Package exporter writes exports of budget data in formats for spreadsheet applications.
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+// fileName returns the name for the file or sheet of a table, e.g.
+// "match-rules" for "Match Rules".
+func fileName(table models.Table) string {
+	return strings.ReplaceAll(strings.ToLower(table.Name), " ", "-")
+}
+
+// text returns the textual representation of a cell.
+func text(cell any) string {
+	switch v := cell.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case decimal.Decimal:
+		return v.String()
+	case time.Time:
+		return v.Format(time.DateOnly)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.DateOnly)
+	case types.Month:
+		if v.IsZero() {
+			return ""
+		}
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
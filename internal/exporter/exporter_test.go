@@ -0,0 +1,120 @@
+package exporter_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/exporter"
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/storage"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var date = time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+var tables = []models.Table{
+	{
+		Name:   "Match Rules",
+		Header: []string{"Account", "Priority"},
+		Rows:   [][]any{{"Shop", uint(2)}},
+	},
+	{
+		Name:   "Transactions",
+		Header: []string{"Date", "Amount", "Note", "Reconciled", "Available From", "Cleared"},
+		Rows:   [][]any{{date, decimal.NewFromFloat(12.5), `Fish & "Chips" <3`, true, types.NewMonth(2024, 3), (*time.Time)(nil)}},
+	},
+}
+
+// files returns the content of all files in a zip archive.
+func files(t *testing.T, b []byte) map[string]string {
+	reader, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.Nil(t, err)
+
+	content := make(map[string]string)
+	for _, f := range reader.File {
+		r, err := f.Open()
+		require.Nil(t, err)
+
+		c, err := io.ReadAll(r)
+		require.Nil(t, err)
+		content[f.Name] = string(c)
+	}
+
+	return content
+}
+
+func TestCSV(t *testing.T) {
+	var b bytes.Buffer
+	require.Nil(t, exporter.CSV(&b, tables))
+
+	content := files(t, b.Bytes())
+	require.Len(t, content, 2)
+
+	records, err := csv.NewReader(bytes.NewBufferString(content["match-rules.csv"])).ReadAll()
+	require.Nil(t, err)
+	assert.Equal(t, [][]string{{"Account", "Priority"}, {"Shop", "2"}}, records)
+
+	records, err = csv.NewReader(bytes.NewBufferString(content["transactions.csv"])).ReadAll()
+	require.Nil(t, err)
+	assert.Equal(t, []string{"2024-03-15", "12.5", `Fish & "Chips" <3`, "true", "2024-03", ""}, records[1])
+}
+
+func TestXLSX(t *testing.T) {
+	var b bytes.Buffer
+	require.Nil(t, exporter.XLSX(&b, tables))
+
+	content := files(t, b.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		assert.Contains(t, content, name)
+	}
+
+	assert.Contains(t, content["xl/workbook.xml"], `<sheet name="Match Rules" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, content["xl/worksheets/sheet1.xml"], `<c r="B2"><v>2</v></c>`)
+
+	sheet := content["xl/worksheets/sheet2.xml"]
+	assert.Contains(t, sheet, `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Date</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2" s="2"><v>45366</v></c>`, "Dates must be Excel serial numbers")
+	assert.Contains(t, sheet, `<c r="B2"><v>12.5</v></c>`)
+	assert.Contains(t, sheet, `Fish &amp; &#34;Chips&#34; &lt;3`)
+	assert.Contains(t, sheet, `<c r="D2" t="b"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<c r="E2" s="3"><v>45352</v></c>`)
+	assert.NotContains(t, sheet, `r="F2"`, "Empty cells must be omitted")
+}
+
+func TestArchive(t *testing.T) {
+	s := storage.Local{Directory: t.TempDir()}
+
+	receipt := models.Attachment{DefaultModel: models.DefaultModel{ID: uuid.New()}, FileName: "receipt.pdf"}
+	require.Nil(t, s.Save(receipt.StorageName(), strings.NewReader("%PDF-1.4")))
+
+	traversal := models.Attachment{DefaultModel: models.DefaultModel{ID: uuid.New()}, FileName: "../../etc/passwd"}
+	require.Nil(t, s.Save(traversal.StorageName(), strings.NewReader("root")))
+
+	var b bytes.Buffer
+	require.Nil(t, exporter.Archive(&b, []byte(`{"data":{}}`), []models.Attachment{receipt, traversal}, s))
+
+	content := files(t, b.Bytes())
+	assert.Equal(t, map[string]string{
+		"export.json": `{"data":{}}`,
+		fmt.Sprintf("attachments/%s/receipt.pdf", receipt.ID): "%PDF-1.4",
+		fmt.Sprintf("attachments/%s/passwd", traversal.ID):    "root",
+	}, content)
+}
+
+func TestArchiveFileMissing(t *testing.T) {
+	missing := models.Attachment{DefaultModel: models.DefaultModel{ID: uuid.New()}, FileName: "receipt.pdf"}
+
+	var b bytes.Buffer
+	assert.ErrorIs(t, exporter.Archive(&b, []byte(`{}`), []models.Attachment{missing}, storage.Local{Directory: t.TempDir()}), fs.ErrNotExist)
+}
@@ -0,0 +1,194 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+// Cell styles, indices into cellXfs in xlsxStyles
+const (
+	styleDefault = iota
+	styleHeader
+	styleDate
+	styleMonth
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+%s</Types>`
+
+const xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/><numFmt numFmtId="165" formatCode="yyyy-mm"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="4">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+</cellXfs>
+</styleSheet>`
+
+// excelEpoch is the day that Excel date serial numbers count from.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// XLSX writes an Excel workbook with one sheet per table.
+//
+// Amounts are written as numbers and dates as dates so that they can
+// be used in calculations directly.
+func XLSX(w io.Writer, tables []models.Table) error {
+	archive := zip.NewWriter(w)
+
+	var overrides, sheets, rels bytes.Buffer
+	for i, table := range tables {
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", i+1)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(table.Name), i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`+"\n", i+1, i+1)
+	}
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`+"\n", len(tables)+1)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, overrides.String())},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` + sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+` + rels.String() + `</Relationships>`},
+		{"xl/styles.xml", xlsxStyles},
+	}
+
+	for _, file := range files {
+		f, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(f, file.content)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, table := range tables {
+		f, err := archive.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(f, worksheet(table))
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// worksheet returns the XML for the sheet of a table.
+func worksheet(table models.Table) string {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	b.WriteString(`<row r="1">`)
+	for column, name := range table.Header {
+		b.WriteString(stringCell(cellReference(column, 1), name, styleHeader))
+	}
+	b.WriteString(`</row>`)
+
+	for i, row := range table.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+2)
+		for column, value := range row {
+			b.WriteString(cell(cellReference(column, i+2), value))
+		}
+		b.WriteString(`</row>`)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// cell returns the XML for a single cell.
+func cell(reference string, value any) string {
+	switch v := value.(type) {
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		return fmt.Sprintf(`<c r="%s" t="b"><v>%d</v></c>`, reference, b)
+	case uint:
+		return fmt.Sprintf(`<c r="%s"><v>%d</v></c>`, reference, v)
+	case decimal.Decimal:
+		return fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, reference, v.String())
+	case time.Time:
+		return dateCell(reference, v, styleDate)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return dateCell(reference, *v, styleDate)
+	case types.Month:
+		if v.IsZero() {
+			return ""
+		}
+		return dateCell(reference, time.Time(v), styleMonth)
+	default:
+		return stringCell(reference, text(v), styleDefault)
+	}
+}
+
+// dateCell returns the XML for a cell containing the date of t.
+func dateCell(reference string, t time.Time, style int) string {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	serial := int(date.Sub(excelEpoch).Hours() / 24)
+	return fmt.Sprintf(`<c r="%s" s="%d"><v>%d</v></c>`, reference, style, serial)
+}
+
+// stringCell returns the XML for a cell containing an inline string.
+func stringCell(reference, s string, style int) string {
+	return fmt.Sprintf(`<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, reference, style, escape(s))
+}
+
+// cellReference returns the A1 style reference for a zero-based column and
+// a one-based row.
+func cellReference(column, row int) string {
+	name := ""
+	for column >= 0 {
+		name = string(rune('A'+column%26)) + name
+		column = column/26 - 1
+	}
+
+	return fmt.Sprintf("%s%d", name, row)
+}
+
+// escape escapes a string for use in XML.
+func escape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,403 @@
+// Package filter parses filter expressions like
+//
+//	amount > 50 and (envelope = "Food" or note ~ "pizza") and date >= 2024-01-01
+//
+// into an abstract syntax tree. The meaning of fields and values is up to the
+// caller, which translates the tree into a database query.
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	maxDepth       = 32  // Maximum nesting of an expression
+	maxComparisons = 100 // Maximum number of comparisons in an expression
+)
+
+// Error is an error in a filter expression.
+type Error struct {
+	Position int    // Position of the error in the expression, starting at 1
+	Message  string // Description of the error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid filter expression at position %d: %s", e.Position, e.Message)
+}
+
+// Errorf returns an error at the position.
+func Errorf(position int, format string, a ...any) *Error {
+	return &Error{Position: position, Message: fmt.Sprintf(format, a...)}
+}
+
+// Operator compares a field with a value.
+type Operator string
+
+const (
+	Equal          Operator = "="
+	NotEqual       Operator = "!="
+	Less           Operator = "<"
+	LessOrEqual    Operator = "<="
+	Greater        Operator = ">"
+	GreaterOrEqual Operator = ">="
+	Contains       Operator = "~"
+	NotContains    Operator = "!~"
+)
+
+// ValueKind is the kind of a value.
+type ValueKind string
+
+const (
+	String ValueKind = "string" // Text in double or single quotes
+	Number ValueKind = "number" // Decimal number
+	Date   ValueKind = "date"   // Date as YYYY-MM-DD
+	Word   ValueKind = "word"   // Unquoted word, e.g. true, null or OUT
+)
+
+// Node is a node of the syntax tree.
+type Node interface {
+	Pos() int
+}
+
+// And matches if both operands match.
+type And struct {
+	Left, Right Node
+	Position    int
+}
+
+// Or matches if at least one operand matches.
+type Or struct {
+	Left, Right Node
+	Position    int
+}
+
+// Not matches if the operand does not match.
+type Not struct {
+	Operand  Node
+	Position int
+}
+
+// Comparison compares a field with a value.
+type Comparison struct {
+	Field    string
+	Operator Operator
+	Value    Value
+	Position int
+}
+
+// Value is a literal value.
+type Value struct {
+	Kind     ValueKind
+	Text     string // The value. For strings, without quotes and with escapes resolved
+	Position int
+}
+
+func (n And) Pos() int        { return n.Position }
+func (n Or) Pos() int         { return n.Position }
+func (n Not) Pos() int        { return n.Position }
+func (n Comparison) Pos() int { return n.Position }
+
+// Parse parses a filter expression.
+func Parse(expression string) (Node, error) {
+	tokens, err := lex(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+	node, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, Errorf(t.position, "unexpected %s", t)
+	}
+
+	return node, nil
+}
+
+// parser is a recursive descent parser for the grammar
+//
+//	or         = and { "or" and }
+//	and        = not { "and" not }
+//	not        = "not" not | "(" or ")" | comparison
+//	comparison = field operator value
+type parser struct {
+	tokens      []token
+	next        int
+	comparisons int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEnd {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) or(depth int) (Node, error) {
+	left, err := p.and(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().isKeyword("or") {
+		position := p.advance().position
+		right, err := p.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right, Position: position}
+	}
+
+	return left, nil
+}
+
+func (p *parser) and(depth int) (Node, error) {
+	left, err := p.not(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().isKeyword("and") {
+		position := p.advance().position
+		right, err := p.not(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right, Position: position}
+	}
+
+	return left, nil
+}
+
+func (p *parser) not(depth int) (Node, error) {
+	t := p.peek()
+	if depth > maxDepth {
+		return nil, Errorf(t.position, "the expression is nested more than %d levels deep", maxDepth)
+	}
+
+	if t.isKeyword("not") {
+		p.advance()
+		operand, err := p.not(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Not{Operand: operand, Position: t.position}, nil
+	}
+
+	if t.kind == tokenLeftParen {
+		p.advance()
+		node, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.advance(); closing.kind != tokenRightParen {
+			return nil, Errorf(closing.position, "expected ) to close ( at position %d, found %s", t.position, closing)
+		}
+		return node, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Node, error) {
+	field := p.advance()
+	p.comparisons++
+	if p.comparisons > maxComparisons {
+		return nil, Errorf(field.position, "the expression has more than %d comparisons", maxComparisons)
+	}
+
+	if field.kind != tokenWord || field.isKeyword("and", "or", "not") {
+		return nil, Errorf(field.position, "expected a field name, found %s", field)
+	}
+
+	operator := p.advance()
+	if operator.kind != tokenOperator {
+		return nil, Errorf(operator.position, "expected an operator after %s, found %s", field.text, operator)
+	}
+
+	value := p.advance()
+	kind, ok := map[tokenKind]ValueKind{
+		tokenString: String,
+		tokenNumber: Number,
+		tokenDate:   Date,
+		tokenWord:   Word,
+	}[value.kind]
+	if !ok || value.isKeyword("and", "or", "not") {
+		return nil, Errorf(value.position, "expected a value after %s, found %s", operator.text, value)
+	}
+
+	return Comparison{
+		Field:    field.text,
+		Operator: Operator(operator.text),
+		Value:    Value{Kind: kind, Text: value.text, Position: value.position},
+		Position: field.position,
+	}, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenNumber
+	tokenDate
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// isKeyword returns if the token is one of the keywords. Keywords are case insensitive.
+func (t token) isKeyword(keywords ...string) bool {
+	if t.kind != tokenWord {
+		return false
+	}
+
+	for _, keyword := range keywords {
+		if strings.EqualFold(t.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEnd:
+		return "the end of the expression"
+	case tokenString:
+		return fmt.Sprintf("%q", t.text)
+	default:
+		return fmt.Sprintf("'%s'", t.text)
+	}
+}
+
+// lex splits the expression into tokens. The last token is always tokenEnd.
+func lex(expression string) ([]token, error) {
+	input := []rune(expression)
+	var tokens []token
+
+	for i := 0; i < len(input); {
+		r := input[i]
+		position := i + 1
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", position: position})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", position: position})
+			i++
+
+		case r == '"' || r == '\'':
+			var text strings.Builder
+			i++
+			for {
+				if i >= len(input) {
+					return nil, Errorf(position, "the string is not terminated")
+				}
+
+				if input[i] == r {
+					i++
+					break
+				}
+
+				if input[i] == '\\' {
+					if i+1 >= len(input) || (input[i+1] != r && input[i+1] != '\\') {
+						return nil, Errorf(i+1, `invalid escape sequence, only \%c and \\ are allowed`, r)
+					}
+					i++
+				}
+
+				text.WriteRune(input[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text.String(), position: position})
+
+		case strings.ContainsRune("=!<>~", r):
+			operator := string(r)
+			if i+1 < len(input) {
+				switch Operator(input[i : i+2]) {
+				case NotEqual, LessOrEqual, GreaterOrEqual, NotContains:
+					operator = string(input[i : i+2])
+				}
+			}
+
+			switch Operator(operator) {
+			case Equal, NotEqual, Less, LessOrEqual, Greater, GreaterOrEqual, Contains, NotContains:
+			default:
+				return nil, Errorf(position, "unknown operator '%s'", operator)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, position: position})
+			i += len([]rune(operator))
+
+		case r == '-' || r == '.' || isDigit(r):
+			start := i
+			for i < len(input) && (isDigit(input[i]) || input[i] == '.' || input[i] == '-') {
+				i++
+			}
+
+			text := string(input[start:i])
+			kind, ok := numberKind(text)
+			if !ok {
+				return nil, Errorf(position, "'%s' is neither a number nor a date in the format YYYY-MM-DD", text)
+			}
+			tokens = append(tokens, token{kind: kind, text: text, position: position})
+
+		case isLetter(r):
+			start := i
+			for i < len(input) && (isLetter(input[i]) || isDigit(input[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(input[start:i]), position: position})
+
+		default:
+			return nil, Errorf(position, "unexpected character '%c'", r)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, position: len(input) + 1}), nil
+}
+
+// numberKind returns if the text is a number or a date.
+func numberKind(text string) (tokenKind, bool) {
+	if len(text) == 10 && text[4] == '-' && text[7] == '-' && strings.Count(text, "-") == 2 && !strings.Contains(text, ".") {
+		return tokenDate, true
+	}
+
+	digits := strings.TrimPrefix(text, "-")
+	if digits == "" || strings.Count(digits, ".") > 1 || strings.Contains(digits, "-") || strings.HasPrefix(digits, ".") || strings.HasSuffix(digits, ".") {
+		return tokenEnd, false
+	}
+
+	return tokenNumber, true
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isLetter(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,111 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/envelope-zero/backend/v7/internal/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+// format returns a compact representation of the syntax tree.
+func format(n filter.Node) string {
+	switch n := n.(type) {
+	case filter.And:
+		return fmt.Sprintf("(%s AND %s)", format(n.Left), format(n.Right))
+	case filter.Or:
+		return fmt.Sprintf("(%s OR %s)", format(n.Left), format(n.Right))
+	case filter.Not:
+		return fmt.Sprintf("NOT %s", format(n.Operand))
+	case filter.Comparison:
+		return fmt.Sprintf("%s %s %s:%s", n.Field, n.Operator, n.Value.Kind, n.Value.Text)
+	}
+	return ""
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{`amount > 50`, `amount > number:50`},
+		{`amount>=-12.5`, `amount >= number:-12.5`},
+		{`date < 2024-01-01`, `date < date:2024-01-01`},
+		{`note ~ "pizza \"deluxe\" \\ 🍕"`, `note ~ string:pizza "deluxe" \ 🍕`},
+		{`note = 'it\'s "quoted"'`, `note = string:it's "quoted"`},
+		{`direction != OUT`, `direction != word:OUT`},
+		{`note !~ ""`, `note !~ string:`},
+		{`a = 1 and b = 2 or c = 3`, `((a = number:1 AND b = number:2) OR c = number:3)`},
+		{`a = 1 or b = 2 and c = 3`, `(a = number:1 OR (b = number:2 AND c = number:3))`},
+		{`(a = 1 or b = 2) and c = 3`, `((a = number:1 OR b = number:2) AND c = number:3)`},
+		{`NOT a = 1 AND not (b = 2)`, `(NOT a = number:1 AND NOT b = number:2)`},
+		{
+			`amount > 50 and (envelope = "Food" or note ~ "pizza") and date >= 2024-01-01`,
+			`((amount > number:50 AND (envelope = string:Food OR note ~ string:pizza)) AND date >= date:2024-01-01)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			node, err := filter.Parse(tt.expression)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, format(node))
+		})
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	node, err := filter.Parse(`note ~ "é" and amount > 5`)
+	assert.Nil(t, err)
+
+	and := node.(filter.And)
+	assert.Equal(t, 12, and.Position)
+	assert.Equal(t, 1, and.Left.Pos())
+	assert.Equal(t, 8, and.Left.(filter.Comparison).Value.Position)
+	assert.Equal(t, 16, and.Right.Pos(), "Positions are not counted in characters")
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		position   int
+		message    string
+	}{
+		{``, 1, "expected a field name, found the end of the expression"},
+		{`amount`, 7, "expected an operator after amount, found the end of the expression"},
+		{`amount >`, 9, "expected a value after >, found the end of the expression"},
+		{`amount > and`, 10, "expected a value after >, found 'and'"},
+		{`amount == 5`, 9, "expected a value after =, found '='"},
+		{`amount ! 5`, 8, "unknown operator '!'"},
+		{`amount > 5 amount < 10`, 12, "unexpected 'amount'"},
+		{`(amount > 5`, 12, "expected ) to close ( at position 1, found the end of the expression"},
+		{`amount > 5)`, 11, "unexpected ')'"},
+		{`note = "pizza`, 8, "the string is not terminated"},
+		{`note = 'pizza"`, 8, "the string is not terminated"},
+		{`note = "\n"`, 9, `invalid escape sequence, only \" and \\ are allowed`},
+		{`note = '\"'`, 9, `invalid escape sequence, only \' and \\ are allowed`},
+		{`date = 2024-1-1`, 8, "'2024-1-1' is neither a number nor a date in the format YYYY-MM-DD"},
+		{`amount = 1.2.3`, 10, "'1.2.3' is neither a number nor a date in the format YYYY-MM-DD"},
+		{`amount = $5`, 10, "unexpected character '$'"},
+		{`= 5`, 1, "expected a field name, found '='"},
+		{strings.Repeat("not ", 40) + "a = 1", 133, "the expression is nested more than 32 levels deep"},
+		{strings.Repeat("a = 1 or ", 100) + "a = 1", 901, "the expression has more than 100 comparisons"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := filter.Parse(tt.expression)
+
+			var filterErr *filter.Error
+			if !errors.As(err, &filterErr) {
+				t.Fatalf("Error is %v, expected a filter.Error", err)
+			}
+
+			assert.Equal(t, tt.position, filterErr.Position)
+			assert.Equal(t, tt.message, filterErr.Message)
+			assert.Equal(t, fmt.Sprintf("invalid filter expression at position %d: %s", tt.position, tt.message), err.Error())
+		})
+	}
+}
@@ -6,4 +6,6 @@ var (
 	ErrInvalidBody      = errors.New("the body of your request contains invalid or un-parseable data. Please check and try again")
 	ErrRequestBodyEmpty = errors.New("the request body must not be empty")
 	ErrInvalidUUID      = errors.New("the specified resource ID is not a valid UUID")
+
+	ErrPreconditionFailed = errors.New("the resource has been modified, the If-Match header does not match its current ETag")
 )
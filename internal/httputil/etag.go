@@ -0,0 +1,114 @@
+package httputil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag returns the entity tag of a resource that was last updated at the time.
+func ETag(updatedAt time.Time) string {
+	return fmt.Sprintf(`"%x"`, updatedAt.UnixNano())
+}
+
+// IfMatch checks the If-Match header of the request against the entity tag of a resource.
+//
+// If the header is set and does not match, ErrPreconditionFailed is returned. Requests
+// without the header always match so that clients can opt in to the check.
+func IfMatch(c *gin.Context, etag string) error {
+	header := c.GetHeader("If-Match")
+	if header == "" || matchETag(header, etag, false) {
+		return nil
+	}
+
+	return ErrPreconditionFailed
+}
+
+// NotModified is a middleware that sets the ETag header of successful responses to GET
+// requests to a hash of their body.
+//
+// If the If-None-Match header of the request matches the entity tag, the body is not
+// sent and the status is set to 304 Not Modified.
+func NotModified(c *gin.Context) {
+	if c.Request.Method != http.MethodGet {
+		c.Next()
+		return
+	}
+
+	w := &bufferedWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+	c.Writer = w.ResponseWriter
+
+	if w.Status() == http.StatusOK {
+		hash := sha256.Sum256(w.body.Bytes())
+		etag := fmt.Sprintf(`"%x"`, hash[:16])
+		c.Header("ETag", etag)
+
+		if matchETag(c.GetHeader("If-None-Match"), etag, true) {
+			c.Status(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+	}
+
+	_, _ = c.Writer.Write(w.body.Bytes())
+}
+
+// bufferedWriter keeps the body of a response so that it can be sent later.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// WriteHeader sets the status unless the body has been written already. This keeps the
+// status of the first response like gin does when a handler renders twice.
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.Written() {
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0 || w.ResponseWriter.Written()
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// matchETag reports if the header, a comma separated list of entity tags, contains the tag.
+// "*" matches every tag.
+//
+// With weak comparison, weak tags match their strong equivalent. Otherwise, weak tags never match.
+func matchETag(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		} else if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+package httputil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/httputil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	assert.Equal(t, httputil.ETag(updatedAt), httputil.ETag(updatedAt.In(time.FixedZone("CET", 3600))), "The location must not change the tag")
+	assert.NotEqual(t, httputil.ETag(updatedAt), httputil.ETag(updatedAt.Add(time.Nanosecond)))
+}
+
+func TestIfMatch(t *testing.T) {
+	etag := httputil.ETag(time.Now())
+
+	tests := []struct {
+		name   string
+		header string
+		err    error
+	}{
+		{"No header", "", nil},
+		{"Match", etag, nil},
+		{"Match in list", `"other", ` + etag, nil},
+		{"Any", "*", nil},
+		{"Mismatch", `"other"`, httputil.ErrPreconditionFailed},
+		{"Weak", "W/" + etag, httputil.ErrPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest(http.MethodPatch, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			assert.ErrorIs(t, httputil.IfMatch(c, etag), tt.err)
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	body := `{"data":"Groceries"}`
+
+	serve := func(method, ifNoneMatch string, status int) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+
+		r.Handle(method, "/", httputil.NotModified, func(c *gin.Context) {
+			c.String(status, body)
+		})
+
+		req, _ := http.NewRequest(method, "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		r.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := serve(http.MethodGet, "", http.StatusOK)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// The tag only depends on the body
+	assert.Equal(t, etag, serve(http.MethodGet, "", http.StatusOK).Header().Get("ETag"))
+
+	for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		w = serve(http.MethodGet, header, http.StatusOK)
+		assert.Equal(t, http.StatusNotModified, w.Code, header)
+		assert.Empty(t, w.Body.String(), header)
+		assert.Equal(t, etag, w.Header().Get("ETag"), header)
+	}
+
+	w = serve(http.MethodGet, `"other"`, http.StatusOK)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+
+	// Errors and other methods are not tagged
+	w = serve(http.MethodGet, etag, http.StatusNotFound)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	// The status of the first response is kept when rendering twice
+	w = httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.GET("/", httputil.NotModified, func(c *gin.Context) {
+		c.String(http.StatusBadRequest, "invalid")
+		c.String(http.StatusOK, body)
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	w = serve(http.MethodPost, etag, http.StatusOK)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+}
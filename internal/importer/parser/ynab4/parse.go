@@ -327,16 +327,22 @@ func parseTransactions(resources *importer.ParsedResources, transactions []Trans
 			newTransaction.Model.Amount = transaction.Amount.Neg()
 		}
 
-		// Set the reconciled flags
+		// Set the cleared and reconciled flags
 		if transaction.Cleared == "Reconciled" {
 			if transaction.Amount.IsNegative() {
 				newTransaction.Model.ReconciledSource = true
 			} else {
 				newTransaction.Model.ReconciledDestination = true
 			}
+		} else if transaction.Cleared == "Cleared" {
+			if transaction.Amount.IsNegative() {
+				newTransaction.Model.ClearedSource = true
+			} else {
+				newTransaction.Model.ClearedDestination = true
+			}
 		}
 
-		// If the transaction is a transfer, we need to set ReconciledDestination
+		// If the transaction is a transfer, we need to set the flags for the other account
 		if transaction.TargetAccountID != "" {
 			// We find the corresponding transaction with the TransferTransactionID
 			idx := slices.IndexFunc(transactions, func(t Transaction) bool { return t.EntityID == transaction.TransferTransactionID })
@@ -345,13 +351,19 @@ func parseTransactions(resources *importer.ParsedResources, transactions []Trans
 			}
 
 			// Depending on the transaction direction from the perspective of the current account, we need
-			// to set which Reconciled flag we set.
+			// to set which Cleared or Reconciled flag we set.
 			if transactions[idx].Cleared == "Reconciled" {
 				if transaction.Amount.IsNegative() {
 					newTransaction.Model.ReconciledDestination = true
 				} else {
 					newTransaction.Model.ReconciledSource = true
 				}
+			} else if transactions[idx].Cleared == "Cleared" {
+				if transaction.Amount.IsNegative() {
+					newTransaction.Model.ClearedDestination = true
+				} else {
+					newTransaction.Model.ClearedSource = true
+				}
 			}
 		}
 
@@ -415,13 +427,19 @@ func parseTransactions(resources *importer.ParsedResources, transactions []Trans
 				}
 
 				// Depending on the transaction direction from the perspective of the current account, we need
-				// to set which Reconciled flag we set.
+				// to set which Cleared or Reconciled flag we set.
 				if transactions[idx].Cleared == "Reconciled" {
 					if transaction.Amount.IsNegative() {
 						subTransaction.Model.ReconciledDestination = true
 					} else {
 						subTransaction.Model.ReconciledSource = true
 					}
+				} else if transactions[idx].Cleared == "Cleared" {
+					if transaction.Amount.IsNegative() {
+						subTransaction.Model.ClearedDestination = true
+					} else {
+						subTransaction.Model.ClearedSource = true
+					}
 				}
 			}
 
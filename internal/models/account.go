@@ -25,12 +25,22 @@ type Account struct {
 	InitialBalance     decimal.Decimal `gorm:"type:DECIMAL(20,8)"`
 	InitialBalanceDate *time.Time
 	Archived           bool
-	ImportHash         string // A SHA256 hash of a unique combination of values to use in duplicate detection for imports
+	Currency           string     // ISO 4217 code of the currency of the account. If empty, the currency of the budget is used
+	DefaultEnvelopeID  *uuid.UUID // Envelope for outgoing transactions to the account that have none set. Only external accounts, i.e. payees, can have one
+	ImportHash         string     // A SHA256 hash of a unique combination of values to use in duplicate detection for imports
 }
 
 var (
 	ErrAccountNameNotUnique    = errors.New("the account name must be unique for the budget")
 	ErrAccountCannotBeOnBudget = errors.New("the account cannot be set to on budget")
+	ErrAccountCurrencyInvalid  = errors.New("the currency of an account must be empty or a three letter ISO 4217 currency code")
+
+	ErrAccountDefaultEnvelopeNotExternal = errors.New("only external accounts can have a default envelope")
+	ErrAccountDefaultEnvelopeBudget      = errors.New("the default envelope must belong to the same budget as the account")
+
+	ErrAccountMergeSelf     = errors.New("an account can not be merged into itself")
+	ErrAccountMergeBudget   = errors.New("only accounts of the same budget can be merged")
+	ErrAccountMergeCurrency = errors.New("only accounts with the same currency can be merged")
 )
 
 // BeforeSave ensures consistency for the account
@@ -55,13 +65,27 @@ func (a *Account) BeforeCreate(tx *gorm.DB) error {
 	_ = a.DefaultModel.BeforeCreate(tx)
 
 	toSave := tx.Statement.Dest.(*Account)
-	return a.checkIntegrity(tx, *toSave)
+	toSave.Currency = normalizeCurrency(toSave.Currency)
+	if toSave.Currency != "" && !validCurrency(toSave.Currency) {
+		return ErrAccountCurrencyInvalid
+	}
+
+	err := a.checkIntegrity(tx, *toSave)
+	if err != nil {
+		return err
+	}
+
+	return checkDefaultEnvelope(tx, toSave.BudgetID, toSave.External, toSave.DefaultEnvelopeID)
 }
 
 // BeforeUpdate verifies the state of the account before
 // committing an update to the database.
 func (a *Account) BeforeUpdate(tx *gorm.DB) error {
 	toSave := tx.Statement.Dest.(Account)
+	if tx.Statement.Changed("Currency") && toSave.Currency != "" && !validCurrency(toSave.Currency) {
+		return ErrAccountCurrencyInvalid
+	}
+
 	if tx.Statement.Changed("BudgetID") {
 		err := a.checkIntegrity(tx, toSave)
 		if err != nil {
@@ -69,6 +93,26 @@ func (a *Account) BeforeUpdate(tx *gorm.DB) error {
 		}
 	}
 
+	if tx.Statement.Changed("BudgetID", "External", "DefaultEnvelopeID") {
+		budgetID, external, defaultEnvelopeID := a.BudgetID, a.External, a.DefaultEnvelopeID
+		if tx.Statement.Changed("BudgetID") {
+			budgetID = toSave.BudgetID
+		}
+
+		if tx.Statement.Changed("External") {
+			external = toSave.External
+		}
+
+		if tx.Statement.Changed("DefaultEnvelopeID") {
+			defaultEnvelopeID = toSave.DefaultEnvelopeID
+		}
+
+		err := checkDefaultEnvelope(tx, budgetID, external, defaultEnvelopeID)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Account is being set to be on budget, verify that no transactions
 	// with this account as destination has an envelope set
 	if tx.Statement.Changed("OnBudget") && toSave.OnBudget {
@@ -92,6 +136,13 @@ func (a *Account) BeforeUpdate(tx *gorm.DB) error {
 		}
 	}
 
+	// Whether transactions are incoming or outgoing for envelopes depends
+	// on the account being on budget, so all balances might change.
+	// The same is true for the currency since amounts are converted with it.
+	if tx.Statement.Changed("OnBudget", "Currency") {
+		return invalidateBudgetEnvelopeBalances(tx, a.BudgetID)
+	}
+
 	return nil
 }
 
@@ -100,6 +151,175 @@ func (a *Account) checkIntegrity(tx *gorm.DB, toSave Account) error {
 	return tx.First(&Budget{}, toSave.BudgetID).Error
 }
 
+// checkDefaultEnvelope verifies that the default envelope of an account exists
+// in the budget of the account and that the account is external.
+func checkDefaultEnvelope(tx *gorm.DB, budgetID uuid.UUID, external bool, envelopeID *uuid.UUID) error {
+	if envelopeID == nil {
+		return nil
+	}
+
+	if !external {
+		return ErrAccountDefaultEnvelopeNotExternal
+	}
+
+	var envelope Envelope
+	err := tx.Preload("Category").First(&envelope, envelopeID).Error
+	if err != nil {
+		return err
+	}
+
+	if envelope.Category.BudgetID != budgetID {
+		return ErrAccountDefaultEnvelopeBudget
+	}
+
+	return nil
+}
+
+// Merge merges the accounts with the IDs into the account.
+//
+// All transactions, match rules and reconciliations referencing the accounts
+// are changed to reference the account, the initial balances are added up,
+// then the accounts are deleted. Everything happens in one database transaction.
+//
+// Every transaction is verified with the same checks as when it is updated.
+func (a *Account) Merge(db *gorm.DB, ids []uuid.UUID) error {
+	// The merge works on a copy so that the account is not modified when it fails
+	target := *a
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var budget Budget
+		err := tx.First(&budget, a.BudgetID).Error
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if id == target.ID {
+				return ErrAccountMergeSelf
+			}
+
+			var source Account
+			err := tx.First(&source, id).Error
+			if err != nil {
+				return err
+			}
+
+			if source.BudgetID != target.BudgetID {
+				return ErrAccountMergeBudget
+			}
+
+			if !sameCurrency(budget.Currency, source.Currency, target.Currency) {
+				return ErrAccountMergeCurrency
+			}
+
+			err = target.merge(tx, source)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return db.First(a, a.ID).Error
+}
+
+// merge moves everything referencing the source account to the account
+// and deletes the source account.
+func (a *Account) merge(tx *gorm.DB, source Account) error {
+	var transactions []Transaction
+	err := tx.Where("source_account_id = ? OR destination_account_id = ?", source.ID, source.ID).Find(&transactions).Error
+	if err != nil {
+		return err
+	}
+
+	for _, t := range transactions {
+		sourceAccountID, destinationAccountID := t.SourceAccountID, t.DestinationAccountID
+		if sourceAccountID == source.ID {
+			sourceAccountID = a.ID
+		}
+
+		if destinationAccountID == source.ID {
+			destinationAccountID = a.ID
+		}
+
+		if sourceAccountID == destinationAccountID {
+			return fmt.Errorf("%w, but transaction %s is between the accounts", ErrSourceDoesNotEqualDestination, t.ID)
+		}
+
+		var transactionSource Account
+		err = tx.First(&transactionSource, sourceAccountID).Error
+		if err != nil {
+			return err
+		}
+
+		var transactionDestination Account
+		err = tx.First(&transactionDestination, destinationAccountID).Error
+		if err != nil {
+			return err
+		}
+
+		err = t.checkIntegrity(tx, t, transactionSource, transactionDestination)
+		if err != nil {
+			return fmt.Errorf("transaction %s: %w", t.ID, err)
+		}
+	}
+
+	// Hooks are skipped since the transactions have been verified above
+	err = tx.Model(&Transaction{}).Where(&Transaction{SourceAccountID: source.ID}).UpdateColumn("source_account_id", a.ID).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(&Transaction{}).Where(&Transaction{DestinationAccountID: source.ID}).UpdateColumn("destination_account_id", a.ID).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(&MatchRule{}).Where(&MatchRule{AccountID: source.ID}).UpdateColumn("account_id", a.ID).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(&Reconciliation{}).Where(&Reconciliation{AccountID: source.ID}).UpdateColumn("account_id", a.ID).Error
+	if err != nil {
+		return err
+	}
+
+	// The initial balance of the source is added to the account, starting at the earlier date
+	if !source.InitialBalance.IsZero() {
+		date := a.InitialBalanceDate
+		if date == nil || (source.InitialBalanceDate != nil && source.InitialBalanceDate.Before(*date)) {
+			date = source.InitialBalanceDate
+		}
+
+		balance := a.InitialBalance.Add(source.InitialBalance)
+		err = tx.Model(&Account{}).Where("id = ?", a.ID).UpdateColumns(map[string]any{
+			"initial_balance":      balance,
+			"initial_balance_date": date,
+		}).Error
+		if err != nil {
+			return err
+		}
+		a.InitialBalance, a.InitialBalanceDate = balance, date
+	}
+
+	err = tx.Delete(&source).Error
+	if err != nil {
+		return err
+	}
+
+	// Transactions of the source are incoming or outgoing for envelopes
+	// depending on the account they now reference
+	if source.OnBudget != a.OnBudget {
+		return invalidateBudgetEnvelopeBalances(tx, a.BudgetID)
+	}
+
+	return nil
+}
+
 // Transactions returns all transactions for this account.
 func (a Account) Transactions(db *gorm.DB) []Transaction {
 	var transactions []Transaction
@@ -144,13 +364,13 @@ func (a Account) GetBalanceMonth(db *gorm.DB, month types.Month) (balance, avail
 	// For available, only do so if the next month is after the availableFrom date
 	for _, t := range transactions {
 		if t.DestinationAccountID == a.ID {
-			balance = balance.Add(t.Amount)
+			balance = balance.Add(t.Received())
 
 			// If the transaction is an income transaction, but its AvailableFrom is after this month, skip it
 			if !month.AddDate(0, 1).After(t.AvailableFrom) && t.SourceAccount.External && t.EnvelopeID == nil {
 				continue
 			}
-			available = available.Add(t.Amount)
+			available = available.Add(t.Received())
 		} else {
 			balance = balance.Sub(t.Amount)
 			available = available.Sub(t.Amount)
@@ -160,6 +380,34 @@ func (a Account) GetBalanceMonth(db *gorm.DB, month types.Month) (balance, avail
 	return balance, available, err
 }
 
+// BudgetBalanceMonth calculates the balance and available sums for a specific month like GetBalanceMonth,
+// converted to the currency of the budget with the exchange rate at the end of the month.
+//
+// For the zero month, the current exchange rate is used.
+func (a Account) BudgetBalanceMonth(db *gorm.DB, rates ExchangeRates, month types.Month) (balance, available decimal.Decimal, err error) {
+	balance, available, err = a.GetBalanceMonth(db, month)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	date := time.Now()
+	if !month.IsZero() {
+		date = time.Time(month.AddDate(0, 1)).AddDate(0, 0, -1)
+	}
+
+	balance, err = rates.Convert(balance, a.Currency, date)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	available, err = rates.Convert(available, a.Currency, date)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	return balance, available, nil
+}
+
 // Balance calculates the balance of the account at a specific point in time, including all transactions
 func (a Account) Balance(db *gorm.DB, time time.Time) (balance decimal.Decimal, err error) {
 	var transactions []Transaction
@@ -184,7 +432,7 @@ func (a Account) Balance(db *gorm.DB, time time.Time) (balance decimal.Decimal,
 	// Add incoming transactions, subtract outgoing transactions
 	for _, transaction := range transactions {
 		if transaction.DestinationAccountID == a.ID {
-			balance = balance.Add(transaction.Amount)
+			balance = balance.Add(transaction.Received())
 		} else {
 			balance = balance.Sub(transaction.Amount)
 		}
@@ -216,7 +464,7 @@ func (a Account) ReconciledBalance(db *gorm.DB, time time.Time) (balance decimal
 	// Add incoming transactions, subtract outgoing transactions
 	for _, t := range transactions {
 		if t.DestinationAccountID == a.ID {
-			balance = balance.Add(t.Amount)
+			balance = balance.Add(t.Received())
 		} else {
 			balance = balance.Sub(t.Amount)
 		}
@@ -225,6 +473,53 @@ func (a Account) ReconciledBalance(db *gorm.DB, time time.Time) (balance decimal
 	return balance, err
 }
 
+// ClearedBalance calculates the balance of all cleared and reconciled transactions at a specific point in time.
+//
+// The initial balance is considered to be cleared.
+func (a Account) ClearedBalance(db *gorm.DB, time time.Time) (balance decimal.Decimal, err error) {
+	balance, err = a.sumTransactions(db, time, "(transactions.destination_account_id = ? AND (transactions.cleared_destination OR transactions.reconciled_destination)) OR (transactions.source_account_id = ? AND (transactions.cleared_source OR transactions.reconciled_source))")
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if a.InitialBalanceDate != nil && time.After(*a.InitialBalanceDate) {
+		balance = balance.Add(a.InitialBalance)
+	}
+
+	return balance, nil
+}
+
+// UnclearedBalance calculates the sum of all transactions that are neither cleared nor reconciled at a specific point in time.
+func (a Account) UnclearedBalance(db *gorm.DB, time time.Time) (balance decimal.Decimal, err error) {
+	return a.sumTransactions(db, time, "(transactions.destination_account_id = ? AND NOT transactions.cleared_destination AND NOT transactions.reconciled_destination) OR (transactions.source_account_id = ? AND NOT transactions.cleared_source AND NOT transactions.reconciled_source)")
+}
+
+// sumTransactions sums up all transactions before a specific point in time that match the
+// condition. The condition must have two placeholders for the account ID, the first
+// for incoming and the second for outgoing transactions.
+func (a Account) sumTransactions(db *gorm.DB, time time.Time, condition string) (sum decimal.Decimal, err error) {
+	var transactions []Transaction
+
+	err = db.
+		Where(condition, a.ID, a.ID).
+		Where("datetime(transactions.date) < datetime(?)", time).
+		Find(&transactions).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	// Add incoming transactions, subtract outgoing transactions
+	for _, t := range transactions {
+		if t.DestinationAccountID == a.ID {
+			sum = sum.Add(t.Received())
+		} else {
+			sum = sum.Sub(t.Amount)
+		}
+	}
+
+	return sum, nil
+}
+
 // SetRecentEnvelopes returns the most common envelopes used in the last 50
 // transactions where the account is the destination account.
 //
@@ -275,17 +570,48 @@ func (a Account) RecentEnvelopes(db *gorm.DB) ([]*uuid.UUID, error) {
 	return ids, nil
 }
 
-// Returns all accounts on this instance for export
-func (Account) Export() (json.RawMessage, error) {
+// Returns all accounts for export. If budgetID is set, only accounts of that budget are returned.
+func (Account) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[Account](accountBudgetScope(budgetID))
+}
+
+// Table returns all accounts for export as a table.
+func (Account) Table(budgetID *uuid.UUID) (Table, error) {
 	var accounts []Account
-	err := DB.Unscoped().Where(&Account{}).Find(&accounts).Error
+	err := DB.Scopes(accountBudgetScope(budgetID)).Preload("Budget").Order("accounts.name ASC").Find(&accounts).Error
 	if err != nil {
-		return nil, err
+		return Table{}, err
 	}
 
-	j, err := json.Marshal(&accounts)
+	// Accounts have no association to their default envelope, so the names are resolved here
+	var envelopes []Envelope
+	err = DB.Scopes(envelopeBudgetScope(budgetID)).Find(&envelopes).Error
 	if err != nil {
-		return json.RawMessage{}, err
+		return Table{}, err
+	}
+
+	names := make(map[uuid.UUID]string, len(envelopes))
+	for _, e := range envelopes {
+		names[e.ID] = e.Name
+	}
+
+	table := Table{
+		Name:   "Accounts",
+		Header: []string{"ID", "Budget", "Name", "Note", "On Budget", "External", "Archived", "Currency", "Default Envelope", "Initial Balance", "Initial Balance Date"},
+	}
+
+	for _, a := range accounts {
+		var defaultEnvelope string
+		if a.DefaultEnvelopeID != nil {
+			defaultEnvelope = names[*a.DefaultEnvelopeID]
+		}
+
+		table.Rows = append(table.Rows, []any{a.ID.String(), a.Budget.Name, a.Name, a.Note, a.OnBudget, a.External, a.Archived, a.Currency, defaultEnvelope, a.InitialBalance, a.InitialBalanceDate})
 	}
-	return json.RawMessage(j), nil
+
+	return table, nil
+}
+
+func accountBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "accounts.budget_id")
 }
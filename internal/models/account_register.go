@@ -0,0 +1,121 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RegisterPosition is the position of a transaction in the register of an account.
+//
+// Transactions are ordered by their date, transactions with the same date by their ID.
+type RegisterPosition struct {
+	Date time.Time
+	ID   uuid.UUID
+}
+
+// RegisterEntry is a transaction in the register of an account.
+type RegisterEntry struct {
+	Transaction
+	Amount            decimal.Decimal // Change of the account balance. Positive for incoming, negative for outgoing transactions
+	Balance           decimal.Decimal // Balance of the account after the transaction
+	ReconciledBalance decimal.Decimal // Reconciled balance of the account after the transaction
+}
+
+// Position returns the position of the entry in the register.
+func (r RegisterEntry) Position() RegisterPosition {
+	return RegisterPosition{Date: r.Date, ID: r.ID}
+}
+
+// Register returns up to limit transactions of the account in date order with the running balances
+// of the account after each of them. A negative limit returns all transactions.
+//
+// If after is set, only transactions after the position are returned. The running balances
+// are calculated from all transactions up to the position, so they are correct on every page.
+//
+// The initial balance of the account is added at its date.
+func (a Account) Register(db *gorm.DB, after *RegisterPosition, limit int) (entries []RegisterEntry, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var balance, reconciled decimal.Decimal
+		initialApplied := false
+
+		query := tx.
+			Where(tx.Where(Transaction{DestinationAccountID: a.ID}).Or(tx.Where(Transaction{SourceAccountID: a.ID}))).
+			Order("datetime(transactions.date) ASC, transactions.id ASC").
+			Limit(limit)
+
+		if after != nil {
+			var previous []Transaction
+			err := tx.
+				Where(tx.Where(Transaction{DestinationAccountID: a.ID}).Or(tx.Where(Transaction{SourceAccountID: a.ID}))).
+				Where("(datetime(transactions.date) < datetime(?) OR (datetime(transactions.date) = datetime(?) AND transactions.id <= ?))", after.Date, after.Date, after.ID).
+				Find(&previous).Error
+			if err != nil {
+				return err
+			}
+
+			for _, t := range previous {
+				amount, isReconciled := a.registerAmount(t)
+				balance = balance.Add(amount)
+				if isReconciled {
+					reconciled = reconciled.Add(amount)
+				}
+			}
+
+			if a.InitialBalanceDate != nil && !after.Date.Before(*a.InitialBalanceDate) {
+				balance = balance.Add(a.InitialBalance)
+				reconciled = reconciled.Add(a.InitialBalance)
+				initialApplied = true
+			}
+
+			query = query.Where("(datetime(transactions.date) > datetime(?) OR (datetime(transactions.date) = datetime(?) AND transactions.id > ?))", after.Date, after.Date, after.ID)
+		}
+
+		var transactions []Transaction
+		err := query.Find(&transactions).Error
+		if err != nil {
+			return err
+		}
+
+		entries = make([]RegisterEntry, 0, len(transactions))
+		for _, t := range transactions {
+			if !initialApplied && a.InitialBalanceDate != nil && !t.Date.Before(*a.InitialBalanceDate) {
+				balance = balance.Add(a.InitialBalance)
+				reconciled = reconciled.Add(a.InitialBalance)
+				initialApplied = true
+			}
+
+			amount, isReconciled := a.registerAmount(t)
+			balance = balance.Add(amount)
+			if isReconciled {
+				reconciled = reconciled.Add(amount)
+			}
+
+			entries = append(entries, RegisterEntry{
+				Transaction:       t,
+				Amount:            amount,
+				Balance:           balance,
+				ReconciledBalance: reconciled,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// registerAmount returns the change of the account balance by the transaction
+// and if the transaction is reconciled for the account.
+func (a Account) registerAmount(t Transaction) (decimal.Decimal, bool) {
+	if t.DestinationAccountID == a.ID {
+		return t.Received(), t.ReconciledDestination
+	}
+
+	return t.Amount.Neg(), t.ReconciledSource
+}
@@ -0,0 +1,70 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) TestAccountRegister() {
+	budget := suite.createTestBudget(models.Budget{})
+	initialDate := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Bank", OnBudget: true, InitialBalance: decimal.NewFromFloat(100), InitialBalanceDate: &initialDate})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true})
+
+	// Created out of order to verify the sorting
+	_ = suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(30), Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), ReconciledSource: true})
+	_ = suite.createTestTransaction(models.Transaction{SourceAccountID: shop.ID, DestinationAccountID: account.ID, Amount: decimal.NewFromFloat(10), Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ReconciledDestination: true})
+	_ = suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(5), Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)})
+	_ = suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(5), Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)})
+
+	expected := []struct {
+		amount     float64
+		balance    float64
+		reconciled float64
+	}{
+		{10, 10, 10},
+		{-5, 105, 110}, // The initial balance is added before the first transaction at or after its date
+		{-5, 100, 110},
+		{-30, 70, 80},
+	}
+
+	all, err := account.Register(models.DB, nil, -1)
+	suite.Require().Nil(err)
+	suite.Require().Len(all, 4)
+	suite.Assert().True(all[1].ID.String() < all[2].ID.String(), "Transactions on the same day are not sorted by ID")
+
+	for i, e := range expected {
+		suite.Assert().True(decimal.NewFromFloat(e.amount).Equal(all[i].Amount), "Entry %d has amount %s, expected %f", i, all[i].Amount, e.amount)
+		suite.Assert().True(decimal.NewFromFloat(e.balance).Equal(all[i].Balance), "Entry %d has balance %s, expected %f", i, all[i].Balance, e.balance)
+		suite.Assert().True(decimal.NewFromFloat(e.reconciled).Equal(all[i].ReconciledBalance), "Entry %d has reconciled balance %s, expected %f", i, all[i].ReconciledBalance, e.reconciled)
+	}
+
+	// Pages continue with the running balances of the previous page
+	var paged []models.RegisterEntry
+	var after *models.RegisterPosition
+	for {
+		page, err := account.Register(models.DB, after, 1)
+		suite.Require().Nil(err)
+		if len(page) == 0 {
+			break
+		}
+
+		paged = append(paged, page...)
+		position := page[len(page)-1].Position()
+		after = &position
+	}
+
+	suite.Require().Len(paged, len(all))
+	for i := range all {
+		suite.Assert().Equal(all[i].ID, paged[i].ID)
+		suite.Assert().True(all[i].Balance.Equal(paged[i].Balance), "Entry %d has balance %s on its page, expected %s", i, paged[i].Balance, all[i].Balance)
+		suite.Assert().True(all[i].ReconciledBalance.Equal(paged[i].ReconciledBalance), "Entry %d has reconciled balance %s on its page, expected %s", i, paged[i].ReconciledBalance, all[i].ReconciledBalance)
+	}
+
+	// The final balance is the account balance
+	balance, err := account.Balance(models.DB, time.Now())
+	suite.Require().Nil(err)
+	suite.Assert().True(balance.Equal(all[3].Balance), "Balance is %s, register has %s", balance, all[3].Balance)
+}
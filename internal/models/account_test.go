@@ -385,7 +385,7 @@ func (suite *TestSuiteStandard) TestAccountExport() {
 		_ = suite.createTestAccount(models.Account{BudgetID: budget.ID})
 	}
 
-	raw, err := models.Account{}.Export()
+	raw, err := models.Account{}.Export(nil)
 	if err != nil {
 		require.Fail(t, "account export failed", err)
 	}
@@ -398,3 +398,56 @@ func (suite *TestSuiteStandard) TestAccountExport() {
 
 	require.Len(t, accounts, 2, "Number of accounts in export is wrong")
 }
+
+func (suite *TestSuiteStandard) TestAccountMerge() {
+	budget := suite.createTestBudget(models.Budget{})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: suite.createTestCategory(models.Category{BudgetID: budget.ID}).ID})
+
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Checking", OnBudget: true, InitialBalance: decimal.NewFromFloat(100), InitialBalanceDate: &late})
+	duplicate := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Checking (Import)", OnBudget: true, InitialBalance: decimal.NewFromFloat(50), InitialBalanceDate: &early})
+	savings := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Savings", OnBudget: true})
+	payee := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true})
+
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: duplicate.ID, DestinationAccountID: payee.ID, EnvelopeID: &envelope.ID, Amount: decimal.NewFromFloat(17)})
+	matchRule := suite.createTestMatchRule(models.MatchRule{AccountID: duplicate.ID, Match: "Shop*"})
+
+	suite.Assert().ErrorIs(account.Merge(models.DB, []uuid.UUID{account.ID}), models.ErrAccountMergeSelf)
+	suite.Assert().ErrorIs(account.Merge(models.DB, []uuid.UUID{uuid.New()}), models.ErrResourceNotFound)
+
+	other := suite.createTestAccount(models.Account{BudgetID: suite.createTestBudget(models.Budget{}).ID})
+	suite.Assert().ErrorIs(account.Merge(models.DB, []uuid.UUID{other.ID}), models.ErrAccountMergeBudget)
+
+	dollar := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Dollar", Currency: "USD"})
+	suite.Assert().ErrorIs(account.Merge(models.DB, []uuid.UUID{dollar.ID}), models.ErrAccountMergeCurrency)
+
+	// Transfers between on-budget accounts must not have an envelope
+	offBudget := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Cash"})
+	_ = suite.createTestTransaction(models.Transaction{SourceAccountID: offBudget.ID, DestinationAccountID: savings.ID, EnvelopeID: &envelope.ID, Amount: decimal.NewFromFloat(3)})
+	suite.Assert().ErrorIs(account.Merge(models.DB, []uuid.UUID{offBudget.ID}), models.ErrTransactionTransferBetweenOnBudgetWithEnvelope)
+
+	// Transactions between the accounts would have the same source and destination
+	between := suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: savings.ID, Amount: decimal.NewFromFloat(5)})
+	suite.Assert().ErrorIs(account.Merge(models.DB, []uuid.UUID{duplicate.ID, savings.ID}), models.ErrSourceDoesNotEqualDestination)
+
+	// Nothing is merged when one of the accounts can not be merged
+	suite.Require().Nil(models.DB.First(&transaction, transaction.ID).Error)
+	suite.Assert().Equal(duplicate.ID, transaction.SourceAccountID)
+	suite.Require().Nil(models.DB.First(&models.Account{}, duplicate.ID).Error)
+	suite.Require().Nil(models.DB.Delete(&between).Error)
+
+	suite.Require().Nil(account.Merge(models.DB, []uuid.UUID{duplicate.ID}))
+	suite.Assert().True(decimal.NewFromFloat(150).Equal(account.InitialBalance), "Initial balance is %s", account.InitialBalance)
+	suite.Require().NotNil(account.InitialBalanceDate)
+	suite.Assert().True(early.Equal(*account.InitialBalanceDate))
+
+	suite.Require().Nil(models.DB.First(&transaction, transaction.ID).Error)
+	suite.Assert().Equal(account.ID, transaction.SourceAccountID)
+
+	suite.Require().Nil(models.DB.First(&matchRule, matchRule.ID).Error)
+	suite.Assert().Equal(account.ID, matchRule.AccountID)
+
+	err := models.DB.First(&models.Account{}, duplicate.ID).Error
+	suite.Assert().ErrorIs(err, models.ErrResourceNotFound)
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/envelope-zero/backend/v7/internal/storage"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Storage holds the files of attachments.
+var Storage storage.Storage = storage.Local{Directory: "data/attachments"}
+
+// Attachment is a file attached to a transaction, e.g. a receipt or an invoice.
+//
+// The file itself is kept in the Storage, the attachment only holds its metadata.
+type Attachment struct {
+	DefaultModel
+	Transaction   Transaction `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	TransactionID uuid.UUID   `gorm:"index"`
+	FileName      string
+	ContentType   string
+	Size          int64
+}
+
+func (a *Attachment) BeforeCreate(tx *gorm.DB) error {
+	_ = a.DefaultModel.BeforeCreate(tx)
+
+	toSave := tx.Statement.Dest.(*Attachment)
+	return tx.First(&Transaction{}, toSave.TransactionID).Error
+}
+
+func (a *Attachment) BeforeSave(_ *gorm.DB) error {
+	a.FileName = strings.TrimSpace(a.FileName)
+	return nil
+}
+
+// StorageName is the name of the file of the attachment in the Storage.
+func (a Attachment) StorageName() string {
+	return a.ID.String()
+}
+
+// CreateAttachment creates the attachment and saves the content as its file.
+//
+// If the file cannot be saved, the attachment is not created.
+func CreateAttachment(db *gorm.DB, attachment *Attachment, content io.Reader) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(attachment).Error
+		if err != nil {
+			return err
+		}
+
+		err = Storage.Save(attachment.StorageName(), content)
+		if err != nil {
+			log.Error().Str("attachment", attachment.ID.String()).Err(err).Msg("Saving the file failed")
+			return ErrGeneral
+		}
+
+		return nil
+	})
+}
+
+// StoredAttachments returns the storage names of all attachments, including the ones in the trash.
+func StoredAttachments(db *gorm.DB) ([]string, error) {
+	var names []string
+	err := db.Model(&Attachment{}).Pluck("id", &names).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TrashEntry
+	err = db.Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		names = append(names, trashedAttachments(entry.Resources)...)
+	}
+
+	return names, nil
+}
+
+// DeleteStoredAttachments deletes the files with the storage names.
+//
+// Files that cannot be deleted are logged and skipped since the
+// attachments they belong to do not exist anymore.
+func DeleteStoredAttachments(names []string) {
+	for _, name := range names {
+		err := Storage.Delete(name)
+		if err != nil {
+			log.Error().Str("file", name).Err(err).Msg("Deleting the file of an attachment failed")
+		}
+	}
+}
+
+// trashedAttachments returns the storage names of all attachments in the resources.
+func trashedAttachments(resources TrashResources) []string {
+	var names []string
+	for _, resource := range resources {
+		if resource.Model != "Attachment" {
+			continue
+		}
+
+		var a Attachment
+		err := json.Unmarshal(resource.Data, &a)
+		if err != nil {
+			log.Error().Err(err).Msg("Reading an attachment in the trash failed")
+			continue
+		}
+
+		names = append(names, a.StorageName())
+	}
+
+	return names
+}
+
+// Returns all attachments for export. If budgetID is set, only attachments of that budget are returned.
+func (Attachment) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[Attachment](attachmentBudgetScope(budgetID))
+}
+
+// Table returns all attachments for export as a table.
+func (Attachment) Table(budgetID *uuid.UUID) (Table, error) {
+	attachments, err := ExportedAttachments(budgetID)
+	if err != nil {
+		return Table{}, err
+	}
+
+	table := Table{
+		Name:   "Attachments",
+		Header: []string{"ID", "Transaction ID", "File Name", "Content Type", "Size"},
+	}
+
+	for _, a := range attachments {
+		table.Rows = append(table.Rows, []any{a.ID.String(), a.TransactionID.String(), a.FileName, a.ContentType, uint(a.Size)})
+	}
+
+	return table, nil
+}
+
+// ExportedAttachments returns all attachments that are part of an export, ordered by transaction and file name.
+// If budgetID is set, only attachments of that budget are returned.
+func ExportedAttachments(budgetID *uuid.UUID) ([]Attachment, error) {
+	var attachments []Attachment
+	err := DB.
+		Scopes(attachmentBudgetScope(budgetID)).
+		Joins("JOIN transactions AS sort_transactions ON sort_transactions.id = attachments.transaction_id").
+		Order("sort_transactions.date ASC, sort_transactions.created_at ASC, attachments.file_name ASC").
+		Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func attachmentBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "accounts.budget_id", "JOIN transactions ON transactions.id = attachments.transaction_id", "JOIN accounts ON accounts.id = transactions.source_account_id")
+}
@@ -0,0 +1,100 @@
+package models_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/storage"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) createTestAttachment(transactionID uuid.UUID) models.Attachment {
+	attachment := models.Attachment{TransactionID: transactionID, FileName: " receipt.pdf "}
+	err := models.CreateAttachment(models.DB, &attachment, strings.NewReader("%PDF"))
+	if err != nil {
+		suite.Assert().FailNow("Attachment could not be saved", "Error: %s, Attachment: %#v", err, attachment)
+	}
+
+	return attachment
+}
+
+func (suite *TestSuiteStandard) TestAttachmentCreate() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: bank.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(10)})
+
+	attachment := suite.createTestAttachment(transaction.ID)
+	suite.Assert().Equal("receipt.pdf", attachment.FileName)
+
+	f, err := models.Storage.Open(attachment.StorageName())
+	suite.Require().Nil(err)
+	f.Close()
+
+	err = models.CreateAttachment(models.DB, &models.Attachment{TransactionID: uuid.New()}, strings.NewReader(""))
+	suite.Assert().ErrorIs(err, models.ErrResourceNotFound)
+}
+
+func (suite *TestSuiteStandard) TestAttachmentCreateStorageFails() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: bank.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(10)})
+
+	// The directory cannot be created since a file with the same name exists
+	path := filepath.Join(suite.T().TempDir(), "attachments")
+	suite.Require().Nil(os.WriteFile(path, []byte{}, 0o600))
+	models.Storage = storage.Local{Directory: path}
+
+	err := models.CreateAttachment(models.DB, &models.Attachment{TransactionID: transaction.ID}, strings.NewReader("%PDF"))
+	suite.Assert().ErrorIs(err, models.ErrGeneral)
+
+	var count int64
+	suite.Require().Nil(models.DB.Model(&models.Attachment{}).Count(&count).Error)
+	suite.Assert().Equal(int64(0), count, "The attachment must not be created when its file cannot be saved")
+}
+
+func (suite *TestSuiteStandard) TestAttachmentPurgeTrash() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: bank.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(10)})
+	attachment := suite.createTestAttachment(transaction.ID)
+	kept := suite.createTestAttachment(suite.createTestTransaction(models.Transaction{SourceAccountID: bank.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(20)}).ID)
+
+	entry, _, err := models.MoveToTrash(models.DB, &transaction)
+	suite.Require().Nil(err)
+	suite.Require().Len(entry.Resources, 2)
+
+	names, err := models.StoredAttachments(models.DB)
+	suite.Require().Nil(err)
+	suite.Assert().ElementsMatch([]string{attachment.StorageName(), kept.StorageName()}, names, "Attachments in the trash must be included")
+
+	// The file is kept until the trash entry expires
+	files, err := models.PurgeTrash(models.DB)
+	suite.Require().Nil(err)
+	suite.Assert().Empty(files)
+
+	suite.Require().Nil(models.DB.Model(&entry).Update("ExpiresAt", time.Now().Add(-time.Minute)).Error)
+	files, err = models.PurgeTrash(models.DB)
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]string{attachment.StorageName()}, files)
+
+	// Files are only deleted by the caller once the database transaction is committed
+	f, err := models.Storage.Open(attachment.StorageName())
+	suite.Require().Nil(err)
+	f.Close()
+
+	models.DeleteStoredAttachments(files)
+	_, err = models.Storage.Open(attachment.StorageName())
+	suite.Assert().ErrorIs(err, fs.ErrNotExist)
+
+	f, err = models.Storage.Open(kept.StorageName())
+	suite.Require().Nil(err, "Files of other attachments must be kept")
+	f.Close()
+}
@@ -0,0 +1,372 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// AuditAction is the kind of change recorded by an audit entry.
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "CREATE"
+	AuditActionUpdate AuditAction = "UPDATE"
+	AuditActionDelete AuditAction = "DELETE"
+)
+
+// AuditEntry records a single change to a resource.
+//
+// Audit entries are written by database callbacks for every create, update
+// and delete of a model in the Registry.
+type AuditEntry struct {
+	DefaultModel
+	Model      string       `gorm:"index"` // Name of the model, e.g. "Transaction"
+	ResourceID uuid.UUID    `gorm:"index"` // ID of the resource. For month configs, this is the ID of the envelope.
+	Action     AuditAction  // The kind of change
+	Changes    AuditChanges `gorm:"type:text"` // Changed fields with their old and new values
+	RequestID  string       `gorm:"index"`     // ID of the HTTP request that caused the change, if any
+}
+
+// AuditChange is the value of a field before and after a change.
+type AuditChange struct {
+	Old any `json:"old"` // Value before the change, null for creations
+	New any `json:"new"` // Value after the change, null for deletions
+}
+
+// AuditChanges maps the database column names of changed fields to their change.
+type AuditChanges map[string]AuditChange
+
+// Scan implements the sql.Scanner interface.
+func (c *AuditChanges) Scan(value any) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case []byte:
+		return json.Unmarshal(v, c)
+	case nil:
+		*c = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into AuditChanges", value)
+	}
+}
+
+// Value implements the driver.Valuer interface.
+func (c AuditChanges) Value() (driver.Value, error) {
+	j, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(j), nil
+}
+
+// auditIgnoredColumns are not recorded in audit entries since they change with every update.
+var auditIgnoredColumns = []string{"created_at", "updated_at"}
+
+// auditRowsKey is the key for the rows as they were before an update or delete.
+const auditRowsKey = "envelope_zero:audit_rows"
+
+// auditEntriesKey is the key for the audit entries written for a statement.
+const auditEntriesKey = "envelope_zero:audit_entries"
+
+// auditEntryRowsKey is the key for the rows of the audit entries written for a statement,
+// in the same order as the entries. For deletions, these are the rows before the deletion.
+const auditEntryRowsKey = "envelope_zero:audit_entry_rows"
+
+// changeEventActions maps audit actions to the suffix of their event.
+var changeEventActions = map[AuditAction]string{
+	AuditActionCreate: "created",
+	AuditActionUpdate: "updated",
+	AuditActionDelete: "deleted",
+}
+
+// changeEvent returns the name of the event for a change to a resource of the model,
+// e.g. "transaction.created" or "monthConfig.updated".
+func changeEvent(model string, action AuditAction) string {
+	return fmt.Sprintf("%s%s.%s", strings.ToLower(model[:1]), model[1:], changeEventActions[action])
+}
+
+// audited returns if changes to the model of the statement are recorded.
+func audited(db *gorm.DB) bool {
+	if db.Statement.Schema == nil {
+		return false
+	}
+
+	for _, model := range Registry {
+		if reflect.TypeOf(model) == db.Statement.Schema.ModelType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// auditBeforeCallback stores the rows affected by an update or delete so that
+// their old values can be recorded.
+func auditBeforeCallback(db *gorm.DB) {
+	if db.Error != nil || !audited(db) {
+		return
+	}
+
+	rows, err := auditRows(db, nil)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	db.InstanceSet(auditRowsKey, rows)
+}
+
+// auditCreateCallback records the creation of resources.
+func auditCreateCallback(db *gorm.DB) {
+	if db.Error != nil || !audited(db) {
+		return
+	}
+
+	rows, err := auditRows(db, nil)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	writeAuditEntries(db, AuditActionCreate, nil, rows)
+}
+
+// auditUpdateCallback records the changes of updated resources.
+func auditUpdateCallback(db *gorm.DB) {
+	if db.Error != nil || !audited(db) {
+		return
+	}
+
+	before, ok := db.InstanceGet(auditRowsKey)
+	if !ok {
+		return
+	}
+
+	// Rows are looked up by the primary keys they had before the update
+	// since the update might have changed the columns used in its conditions
+	after, err := auditRows(db, before.([]map[string]any))
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	writeAuditEntries(db, AuditActionUpdate, before.([]map[string]any), after)
+}
+
+// auditDeleteCallback records the deletion of resources.
+func auditDeleteCallback(db *gorm.DB) {
+	if db.Error != nil || !audited(db) {
+		return
+	}
+
+	before, ok := db.InstanceGet(auditRowsKey)
+	if !ok {
+		return
+	}
+
+	writeAuditEntries(db, AuditActionDelete, before.([]map[string]any), nil)
+}
+
+// auditRows returns the rows affected by the statement.
+//
+// If rows are passed, the rows with the same primary keys are returned.
+// Otherwise, the rows are looked up by the primary keys of the statement's model
+// and its conditions.
+func auditRows(db *gorm.DB, rows []map[string]any) ([]map[string]any, error) {
+	stmt := db.Statement
+
+	// A new session on the same connection so that rows are read within the
+	// transaction of the statement. The model is needed to resolve conditions
+	// on the primary key, e.g. for db.Delete(&Account{}, id)
+	tx := db.Session(&gorm.Session{NewDB: true}).Model(reflect.New(stmt.Schema.ModelType).Interface()).Table(stmt.Table)
+
+	if rows != nil {
+		if len(rows) == 0 {
+			return rows, nil
+		}
+
+		values := make([][]any, 0, len(rows))
+		for _, row := range rows {
+			value := make([]any, 0, len(stmt.Schema.PrimaryFieldDBNames))
+			for _, column := range stmt.Schema.PrimaryFieldDBNames {
+				value = append(value, row[column])
+			}
+			values = append(values, value)
+		}
+
+		column, queryValues := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, values)
+		tx = tx.Where(clause.IN{Column: column, Values: queryValues})
+	} else {
+		conditions := false
+		if stmt.ReflectValue.IsValid() {
+			_, values := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
+			column, queryValues := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, values)
+			if len(queryValues) > 0 {
+				tx = tx.Where(clause.IN{Column: column, Values: queryValues})
+				conditions = true
+			}
+		}
+
+		if where, ok := stmt.Clauses["WHERE"]; ok {
+			if expression, ok := where.Expression.(clause.Where); ok {
+				tx = tx.Clauses(expression)
+				conditions = true
+			}
+		}
+
+		// Without conditions, the statement does not affect any rows
+		// or fails because of a missing WHERE clause
+		if !conditions {
+			return []map[string]any{}, nil
+		}
+	}
+
+	var result []map[string]any
+	err := tx.Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// writeAuditEntries writes the audit entries for the rows before and after a change.
+func writeAuditEntries(db *gorm.DB, action AuditAction, before, after []map[string]any) {
+	stmt := db.Statement
+	key := func(row map[string]any) string {
+		values := make([]any, 0, len(stmt.Schema.PrimaryFieldDBNames))
+		for _, column := range stmt.Schema.PrimaryFieldDBNames {
+			values = append(values, row[column])
+		}
+		return fmt.Sprint(values...)
+	}
+
+	old := make(map[string]map[string]any, len(before))
+	for _, row := range before {
+		old[key(row)] = row
+	}
+
+	requestID, _ := stmt.Context.Value(string(DBContextRequestID)).(string)
+
+	rows := after
+	if action == AuditActionDelete {
+		rows = before
+	}
+
+	entries := make([]AuditEntry, 0, len(rows))
+	entryRows := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		var oldRow, newRow map[string]any
+		switch action {
+		case AuditActionCreate:
+			newRow = row
+		case AuditActionUpdate:
+			oldRow, newRow = old[key(row)], row
+		case AuditActionDelete:
+			oldRow = row
+		}
+
+		changes := auditChanges(stmt.Schema, oldRow, newRow)
+		if len(changes) == 0 {
+			continue
+		}
+
+		id, err := resourceID(row[stmt.Schema.PrimaryFieldDBNames[0]])
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+
+		entries = append(entries, AuditEntry{
+			// The ID is set explicitly since the statement might skip hooks,
+			// e.g. when resources are restored from the trash
+			DefaultModel: DefaultModel{ID: uuid.New()},
+			Model:        stmt.Schema.Name,
+			ResourceID:   id,
+			Action:       action,
+			Changes:      changes,
+			RequestID:    requestID,
+		})
+		entryRows = append(entryRows, row)
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+
+	err := db.Session(&gorm.Session{NewDB: true}).Create(&entries).Error
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	db.InstanceSet(auditEntriesKey, entries)
+	db.InstanceSet(auditEntryRowsKey, entryRows)
+}
+
+// auditChanges returns the changes between the old and new values of a row.
+func auditChanges(s *schema.Schema, old, new map[string]any) AuditChanges {
+	changes := make(AuditChanges)
+
+	columns := make(map[string]bool)
+	for column := range old {
+		columns[column] = true
+	}
+	for column := range new {
+		columns[column] = true
+	}
+
+	for column := range columns {
+		if slices.Contains(auditIgnoredColumns, column) {
+			continue
+		}
+
+		field := s.LookUpField(column)
+		oldValue, newValue := auditValue(field, old[column]), auditValue(field, new[column])
+		if old != nil && new != nil && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		changes[column] = AuditChange{Old: oldValue, New: newValue}
+	}
+
+	return changes
+}
+
+// auditValue converts database values to values that can be stored as JSON.
+func auditValue(field *schema.Field, value any) any {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case int64:
+		// SQLite stores booleans as integers
+		if field != nil && field.DataType == schema.Bool {
+			return v != 0
+		}
+	}
+
+	return value
+}
+
+// resourceID parses the ID of a resource from its database value.
+func resourceID(value any) (uuid.UUID, error) {
+	switch v := value.(type) {
+	case string:
+		return uuid.Parse(v)
+	case []byte:
+		return uuid.ParseBytes(v)
+	default:
+		return uuid.Nil, errors.New("audit: resource ID is not a UUID")
+	}
+}
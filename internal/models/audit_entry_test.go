@@ -0,0 +1,135 @@
+package models_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func (suite *TestSuiteStandard) auditEntries(model string) []models.AuditEntry {
+	var entries []models.AuditEntry
+	err := models.DB.Where(&models.AuditEntry{Model: model}).Order("created_at ASC").Find(&entries).Error
+	suite.Require().Nil(err)
+
+	return entries
+}
+
+func (suite *TestSuiteStandard) TestAuditEntryLifecycle() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Bank", InitialBalance: decimal.NewFromFloat(10)})
+
+	err := models.DB.Model(&account).Select("Name", "OnBudget").Updates(models.Account{Name: "Savings", OnBudget: true}).Error
+	suite.Require().Nil(err)
+
+	err = models.DB.Delete(&account).Error
+	suite.Require().Nil(err)
+
+	entries := suite.auditEntries("Account")
+	suite.Require().Len(entries, 3)
+
+	create := entries[0]
+	suite.Assert().Equal(models.AuditActionCreate, create.Action)
+	suite.Assert().Equal(account.ID, create.ResourceID)
+	suite.Assert().Nil(create.Changes["name"].Old)
+	suite.Assert().Equal("Bank", create.Changes["name"].New)
+	suite.Assert().NotContains(create.Changes, "created_at", "Timestamps must not be recorded")
+
+	update := entries[1]
+	suite.Assert().Equal(models.AuditActionUpdate, update.Action)
+	suite.Assert().Len(update.Changes, 2, "Only changed fields must be recorded")
+	suite.Assert().Equal(models.AuditChange{Old: "Bank", New: "Savings"}, update.Changes["name"])
+	suite.Assert().Equal(models.AuditChange{Old: false, New: true}, update.Changes["on_budget"])
+
+	deletion := entries[2]
+	suite.Assert().Equal(models.AuditActionDelete, deletion.Action)
+	suite.Assert().Equal("Savings", deletion.Changes["name"].Old)
+	suite.Assert().Nil(deletion.Changes["name"].New)
+}
+
+func (suite *TestSuiteStandard) TestAuditEntryRequestID() {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Set(string(models.DBContextRequestID), "request-id")
+	budget := models.Budget{Name: "Audited"}
+
+	err := models.DB.WithContext(c).Create(&budget).Error
+	suite.Require().Nil(err)
+
+	entries := suite.auditEntries("Budget")
+	suite.Require().Len(entries, 1)
+	suite.Assert().Equal("request-id", entries[0].RequestID)
+}
+
+func (suite *TestSuiteStandard) TestAuditEntryMonthConfig() {
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: suite.createTestCategory(models.Category{BudgetID: suite.createTestBudget(models.Budget{}).ID}).ID})
+	monthConfig := suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: types.NewMonth(2024, 1), Allocation: decimal.NewFromFloat(10)})
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: types.NewMonth(2024, 2), Allocation: decimal.NewFromFloat(10)})
+
+	err := models.DB.Model(&monthConfig).Select("Note").Updates(models.MonthConfig{Note: "January"}).Error
+	suite.Require().Nil(err)
+
+	entries := suite.auditEntries("MonthConfig")
+	suite.Require().Len(entries, 3)
+	suite.Assert().Equal(envelope.ID, entries[2].ResourceID, "The resource ID for month configs is the envelope ID")
+	suite.Assert().Equal(models.AuditChanges{"note": {Old: "", New: "January"}}, entries[2].Changes, "Only the updated month config must be recorded")
+}
+
+func (suite *TestSuiteStandard) TestAuditEntryBulkDelete() {
+	budget := suite.createTestBudget(models.Budget{})
+	for _, name := range []string{"One", "Two", "Three"} {
+		_ = suite.createTestCategory(models.Category{BudgetID: budget.ID, Name: name})
+	}
+
+	err := models.DB.Where("budget_id = ?", budget.ID).Delete(&models.Category{}).Error
+	suite.Require().Nil(err)
+
+	var count int64
+	models.DB.Model(&models.AuditEntry{}).Where(&models.AuditEntry{Model: "Category", Action: models.AuditActionDelete}).Count(&count)
+	suite.Assert().Equal(int64(3), count)
+}
+
+func (suite *TestSuiteStandard) TestAuditEntryDeleteByID() {
+	account := suite.createTestAccount(models.Account{BudgetID: suite.createTestBudget(models.Budget{}).ID})
+	matchRule := suite.createTestMatchRule(models.MatchRule{AccountID: account.ID, Match: "Match"})
+
+	err := models.DB.Delete(&models.MatchRule{}, matchRule.ID).Error
+	suite.Require().Nil(err)
+
+	err = models.DB.First(&models.MatchRule{}, matchRule.ID).Error
+	suite.Assert().ErrorIs(err, models.ErrResourceNotFound)
+
+	entries := suite.auditEntries("MatchRule")
+	suite.Require().Len(entries, 2)
+	suite.Assert().Equal(models.AuditActionDelete, entries[1].Action)
+	suite.Assert().Equal(matchRule.ID, entries[1].ResourceID)
+}
+
+func (suite *TestSuiteStandard) TestAuditEntryRollback() {
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&models.Budget{}).Error
+		if err != nil {
+			return err
+		}
+
+		return errors.New("rollback")
+	})
+	suite.Require().NotNil(err)
+
+	suite.Assert().Empty(suite.auditEntries("Budget"), "Changes that are rolled back must not be recorded")
+}
+
+func (suite *TestSuiteStandard) TestAuditEntryChangesScan() {
+	var changes models.AuditChanges
+	suite.Assert().Nil(changes.Scan(`{"name":{"old":"a","new":"b"}}`))
+	suite.Assert().Equal(models.AuditChange{Old: "a", New: "b"}, changes["name"])
+
+	suite.Assert().Nil(changes.Scan(nil))
+	suite.Assert().Nil(changes)
+
+	suite.Assert().NotNil(changes.Scan(time.Now()))
+}
@@ -2,9 +2,12 @@ package models
 
 import (
 	"encoding/json"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
@@ -28,7 +31,17 @@ func (b *Budget) BeforeSave(_ *gorm.DB) error {
 	return nil
 }
 
-// Balance calculates the balance for a budget.
+func (b *Budget) BeforeUpdate(tx *gorm.DB) error {
+	// Amounts of accounts in other currencies are converted to the currency
+	// of the budget, so all envelope balances might change.
+	if tx.Statement.Changed("Currency") {
+		return invalidateBudgetEnvelopeBalances(tx, b.ID)
+	}
+
+	return nil
+}
+
+// Balance calculates the balance for a budget in the currency of the budget.
 func (b Budget) Balance(tx *gorm.DB) (balance decimal.Decimal, err error) {
 	// Get all OnBudget accounts for the budget
 	var accounts []Account
@@ -37,9 +50,14 @@ func (b Budget) Balance(tx *gorm.DB) (balance decimal.Decimal, err error) {
 		OnBudget: true,
 	}).Find(&accounts)
 
+	rates, err := LoadExchangeRates(tx, b.ID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
 	// Add all their balances to the budget's balance
 	for _, account := range accounts {
-		aBalance, _, err := account.GetBalanceMonth(tx, types.Month{})
+		aBalance, _, err := account.BudgetBalanceMonth(tx, rates, types.Month{})
 		if err != nil {
 			return decimal.Zero, err
 		}
@@ -50,11 +68,12 @@ func (b Budget) Balance(tx *gorm.DB) (balance decimal.Decimal, err error) {
 	return balance, nil
 }
 
-// Income returns the income for a budget in a given month.
+// Income returns the income for a budget in a given month in the currency of the budget.
 func (b Budget) Income(db *gorm.DB, month types.Month) (income decimal.Decimal, err error) {
 	var transactions []Transaction
 
 	err = db.
+		Preload("DestinationAccount").
 		Joins("JOIN accounts source_account ON transactions.source_account_id = source_account.id").
 		Joins("JOIN accounts destination_account ON transactions.destination_account_id = destination_account.id").
 		Joins("JOIN budgets ON source_account.budget_id = budgets.id").
@@ -69,11 +88,21 @@ func (b Budget) Income(db *gorm.DB, month types.Month) (income decimal.Decimal,
 		return decimal.Zero, err
 	}
 
+	rates, err := LoadExchangeRates(db, b.ID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
 	for _, t := range transactions {
-		income = income.Add(t.Amount)
+		amount, err := rates.Convert(t.Received(), t.DestinationAccount.Currency, t.Date)
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		income = income.Add(amount)
 	}
 
-	return income, err
+	return income, nil
 }
 
 // Allocated calculates the sum that has been budgeted for a specific month.
@@ -99,17 +128,755 @@ func (b Budget) Allocated(db *gorm.DB, month types.Month) (allocated decimal.Dec
 	return allocated, err
 }
 
-// Returns all budgets on this instance for export
-func (Budget) Export() (json.RawMessage, error) {
+// CloneOptions configures which resources are copied when a budget is cloned.
+//
+// Accounts, categories, envelopes, exchange rates and tags are always copied.
+type CloneOptions struct {
+	GoalsAndMatchRules bool // Copy goals and match rules
+	Transactions       bool // Copy transactions, allocations and initial balances of accounts. Attachments of transactions are not copied
+}
+
+// Clone creates a copy of the budget with a new name.
+//
+// All resources are created in one database transaction, if any of them fails,
+// nothing is created.
+func (b Budget) Clone(db *gorm.DB, name string, options CloneOptions) (clone Budget, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		clone = Budget{
+			Name:     name,
+			Note:     b.Note,
+			Currency: b.Currency,
+		}
+
+		err := tx.Create(&clone).Error
+		if err != nil {
+			return err
+		}
+
+		// Map the IDs of the original resources to the IDs of their copies
+		accountIDs := make(map[uuid.UUID]uuid.UUID)
+		envelopeIDs := make(map[uuid.UUID]uuid.UUID)
+		tagIDs := make(map[uuid.UUID]uuid.UUID)
+
+		var accounts []Account
+		err = tx.Where(&Account{BudgetID: b.ID}).Order("name ASC").Find(&accounts).Error
+		if err != nil {
+			return err
+		}
+
+		for _, a := range accounts {
+			account := Account{
+				BudgetID: clone.ID,
+				Name:     a.Name,
+				Note:     a.Note,
+				OnBudget: a.OnBudget,
+				External: a.External,
+				Archived: a.Archived,
+				Currency: a.Currency,
+			}
+
+			if options.Transactions {
+				account.InitialBalance = a.InitialBalance
+				account.InitialBalanceDate = a.InitialBalanceDate
+				account.ImportHash = a.ImportHash
+			}
+
+			err = tx.Create(&account).Error
+			if err != nil {
+				return err
+			}
+			accountIDs[a.ID] = account.ID
+		}
+
+		var rates []ExchangeRate
+		err = tx.Where(&ExchangeRate{BudgetID: b.ID}).Find(&rates).Error
+		if err != nil {
+			return err
+		}
+
+		for _, r := range rates {
+			err = tx.Create(&ExchangeRate{
+				BudgetID: clone.ID,
+				Currency: r.Currency,
+				Date:     r.Date,
+				Rate:     r.Rate,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var tags []Tag
+		err = tx.Where(&Tag{BudgetID: b.ID}).Order("name ASC").Find(&tags).Error
+		if err != nil {
+			return err
+		}
+
+		for _, t := range tags {
+			tag := Tag{
+				BudgetID: clone.ID,
+				Name:     t.Name,
+				Note:     t.Note,
+			}
+
+			err = tx.Create(&tag).Error
+			if err != nil {
+				return err
+			}
+			tagIDs[t.ID] = tag.ID
+		}
+
+		var categories []Category
+		err = tx.Where(&Category{BudgetID: b.ID}).Order("name ASC").Find(&categories).Error
+		if err != nil {
+			return err
+		}
+
+		for _, c := range categories {
+			category := Category{
+				BudgetID: clone.ID,
+				Name:     c.Name,
+				Note:     c.Note,
+				Archived: c.Archived,
+			}
+
+			err = tx.Create(&category).Error
+			if err != nil {
+				return err
+			}
+
+			envelopes, err := c.Envelopes(tx)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range envelopes {
+				envelope := Envelope{
+					CategoryID: category.ID,
+					Name:       e.Name,
+					Note:       e.Note,
+					Archived:   e.Archived,
+				}
+
+				err = tx.Create(&envelope).Error
+				if err != nil {
+					return err
+				}
+				envelopeIDs[e.ID] = envelope.ID
+			}
+		}
+
+		// Default envelopes can only be set once the envelopes have been copied
+		for _, a := range accounts {
+			if a.DefaultEnvelopeID == nil {
+				continue
+			}
+
+			envelopeID := envelopeIDs[*a.DefaultEnvelopeID]
+			account := Account{DefaultModel: DefaultModel{ID: accountIDs[a.ID]}, BudgetID: clone.ID, External: a.External}
+			err = tx.Model(&account).Select("DefaultEnvelopeID").Updates(Account{DefaultEnvelopeID: &envelopeID}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if options.GoalsAndMatchRules {
+			var matchRules []MatchRule
+			err = tx.
+				Joins("JOIN accounts ON accounts.id = match_rules.account_id").
+				Where("accounts.budget_id = ?", b.ID).
+				Find(&matchRules).Error
+			if err != nil {
+				return err
+			}
+
+			for _, m := range matchRules {
+				err = tx.Create(&MatchRule{
+					AccountID: accountIDs[m.AccountID],
+					Priority:  m.Priority,
+					Match:     m.Match,
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			var goals []Goal
+			err = tx.
+				Joins("JOIN envelopes ON envelopes.id = goals.envelope_id").
+				Joins("JOIN categories ON categories.id = envelopes.category_id").
+				Where("categories.budget_id = ?", b.ID).
+				Find(&goals).Error
+			if err != nil {
+				return err
+			}
+
+			for _, g := range goals {
+				err = tx.Create(&Goal{
+					Name:       g.Name,
+					Note:       g.Note,
+					EnvelopeID: envelopeIDs[g.EnvelopeID],
+					Amount:     g.Amount,
+					Month:      g.Month,
+					Archived:   g.Archived,
+					Period:     g.Period,
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if !options.Transactions {
+			return nil
+		}
+
+		var monthConfigs []MonthConfig
+		err = tx.
+			Joins("JOIN envelopes ON envelopes.id = month_configs.envelope_id").
+			Joins("JOIN categories ON categories.id = envelopes.category_id").
+			Where("categories.budget_id = ?", b.ID).
+			Find(&monthConfigs).Error
+		if err != nil {
+			return err
+		}
+
+		for _, m := range monthConfigs {
+			err = tx.Create(&MonthConfig{
+				EnvelopeID: envelopeIDs[m.EnvelopeID],
+				Month:      m.Month,
+				Allocation: m.Allocation,
+				Note:       m.Note,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var transactions []Transaction
+		err = tx.
+			Joins("JOIN accounts ON accounts.id = transactions.source_account_id").
+			Where("accounts.budget_id = ?", b.ID).
+			Find(&transactions).Error
+		if err != nil {
+			return err
+		}
+
+		for _, t := range transactions {
+			transaction := Transaction{
+				SourceAccountID:       accountIDs[t.SourceAccountID],
+				DestinationAccountID:  accountIDs[t.DestinationAccountID],
+				Date:                  t.Date,
+				Amount:                t.Amount,
+				DestinationAmount:     t.DestinationAmount,
+				Note:                  t.Note,
+				ClearedSource:         t.ClearedSource,
+				ClearedDestination:    t.ClearedDestination,
+				ReconciledSource:      t.ReconciledSource,
+				ReconciledDestination: t.ReconciledDestination,
+				AvailableFrom:         t.AvailableFrom,
+				ImportHash:            t.ImportHash,
+			}
+
+			if t.EnvelopeID != nil {
+				envelopeID := envelopeIDs[*t.EnvelopeID]
+				transaction.EnvelopeID = &envelopeID
+			}
+
+			err = tx.Create(&transaction).Error
+			if err != nil {
+				return err
+			}
+
+			var links []TransactionTag
+			err = tx.Where(&TransactionTag{TransactionID: t.ID}).Find(&links).Error
+			if err != nil {
+				return err
+			}
+
+			for _, l := range links {
+				err = tx.Create(&TransactionTag{TransactionID: transaction.ID, TagID: tagIDs[l.TagID]}).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Budget{}, err
+	}
+
+	return clone, nil
+}
+
+// Returns all budgets for export. If budgetID is set, only that budget is returned.
+func (Budget) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[Budget](budgetBudgetScope(budgetID))
+}
+
+// Table returns all budgets for export as a table.
+func (Budget) Table(budgetID *uuid.UUID) (Table, error) {
 	var budgets []Budget
-	err := DB.Unscoped().Where(&Budget{}).Find(&budgets).Error
+	err := DB.Scopes(budgetBudgetScope(budgetID)).Order("name ASC").Find(&budgets).Error
+	if err != nil {
+		return Table{}, err
+	}
+
+	table := Table{
+		Name:   "Budgets",
+		Header: []string{"ID", "Name", "Note", "Currency"},
+	}
+
+	for _, b := range budgets {
+		table.Rows = append(table.Rows, []any{b.ID.String(), b.Name, b.Note, b.Currency})
+	}
+
+	return table, nil
+}
+
+func budgetBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "budgets.id")
+}
+
+// ageOfMoneyOutflows is the number of most recent outflows that the age of money
+// is averaged over.
+const ageOfMoneyOutflows = 10
+
+// bufferDays is the maximum number of days used to calculate the average daily
+// outflow for the days of buffer.
+const bufferDays = 90
+
+// BudgetHealth contains metrics describing the health of a budget.
+type BudgetHealth struct {
+	AgeOfMoney              *int64        `json:"ageOfMoney" example:"34"`                                       // Average age in days of the money spent in the most recent outflows. null if no outflow could be matched to income
+	DaysOfBuffer            *int64        `json:"daysOfBuffer" example:"52"`                                     // Number of days the on-budget balance lasts at the average daily outflow. null if there was no outflow
+	MonthsAnalyzed          int           `json:"monthsAnalyzed" example:"14"`                                   // Number of months from the first month with data up to and including the requested month
+	OverspentMonths         []types.Month `json:"overspentMonths" example:"2024-03-01T00:00:00.000000Z"`         // Months in which at least one envelope had a negative balance
+	AvailableNegativeMonths []types.Month `json:"availableNegativeMonths" example:"2024-02-01T00:00:00.000000Z"` // Months in which the amount available to budget was negative
+}
+
+// moneyFlow is an inflow to or outflow from the on-budget accounts of a budget.
+type moneyFlow struct {
+	Amount   decimal.Decimal
+	Date     time.Time
+	Income   bool
+	Currency string // Currency of the on-budget account. The amount is converted to the currency of the budget after loading
+}
+
+// Health calculates the health metrics of a budget at the end of a month.
+//
+// Income is matched to outflows first in, first out. Income is money flowing from
+// off-budget to on-budget accounts without an envelope, as in Income, plus positive
+// initial balances of on-budget accounts. Outflows are all transactions from on-budget
+// to off-budget accounts.
+func (b Budget) Health(db *gorm.DB, month types.Month) (health BudgetHealth, err error) {
+	end := time.Time(month.AddDate(0, 1))
+
+	flows, err := b.moneyFlows(db, end)
+	if err != nil {
+		return BudgetHealth{}, err
+	}
+
+	health.AgeOfMoney = ageOfMoney(flows)
+
+	health.DaysOfBuffer, err = b.daysOfBuffer(db, month, flows)
+	if err != nil {
+		return BudgetHealth{}, err
+	}
+
+	health.OverspentMonths = make([]types.Month, 0)
+	health.AvailableNegativeMonths = make([]types.Month, 0)
+
+	first, err := b.firstMonth(db)
+	if err != nil {
+		return BudgetHealth{}, err
+	}
+
+	// No data at all or only data after the requested month
+	if first.IsZero() || first.After(month) {
+		return health, nil
+	}
+
+	var envelopes []Envelope
+	err = db.
+		Joins("JOIN categories ON envelopes.category_id = categories.id").
+		Where("categories.budget_id = ?", b.ID).
+		Find(&envelopes).Error
+	if err != nil {
+		return BudgetHealth{}, err
+	}
+
+	var accounts []Account
+	err = db.Where(&Account{BudgetID: b.ID, OnBudget: true}).Find(&accounts).Error
+	if err != nil {
+		return BudgetHealth{}, err
+	}
+
+	rates, err := LoadExchangeRates(db, b.ID)
+	if err != nil {
+		return BudgetHealth{}, err
+	}
+
+	// Balances are carried forward from month to month instead of
+	// being recalculated for every month
+	available, err := b.availableMonths(db, accounts, rates, first, month)
+	if err != nil {
+		return BudgetHealth{}, err
+	}
+
+	overspent := make([]bool, len(available))
+	for _, e := range envelopes {
+		months, err := e.Months(db, first, month)
+		if err != nil {
+			return BudgetHealth{}, err
+		}
+
+		// Available to budget is the available sum of all on-budget accounts
+		// minus the sum of all envelope balances, as for the month
+		for i, envelopeMonth := range months {
+			if envelopeMonth.Balance.IsNegative() {
+				overspent[i] = true
+			}
+
+			available[i] = available[i].Sub(envelopeMonth.Balance)
+		}
+	}
+
+	for i, m := 0, first; !m.After(month); i, m = i+1, m.AddDate(0, 1) {
+		health.MonthsAnalyzed++
+
+		if overspent[i] {
+			health.OverspentMonths = append(health.OverspentMonths, m)
+		}
+
+		if available[i].IsNegative() {
+			health.AvailableNegativeMonths = append(health.AvailableNegativeMonths, m)
+		}
+	}
+
+	return health, nil
+}
+
+// availableMonths calculates the sum of the on-budget accounts that is available for budgeting at the end
+// of every month from the first to the last month, inclusive, in the currency of the budget.
+//
+// The sums are the same as from Account.BudgetBalanceMonth, but the transactions of all accounts are
+// loaded at once and the sums are carried forward from month to month.
+func (b Budget) availableMonths(db *gorm.DB, accounts []Account, rates ExchangeRates, first, until types.Month) ([]decimal.Decimal, error) {
+	length := monthsBetween(first, until) + 1
+
+	// changes contains the change of the available sum of each account per month, in the currency of the account
+	changes := make(map[uuid.UUID][]decimal.Decimal, len(accounts))
+	ids := make([]uuid.UUID, 0, len(accounts))
+	for _, a := range accounts {
+		changes[a.ID] = make([]decimal.Decimal, length)
+		ids = append(ids, a.ID)
+	}
+
+	// change adds the amount to the available sum of the account from the month on.
+	// Changes before the first month are part of the sum for the first month
+	change := func(id uuid.UUID, month types.Month, amount decimal.Decimal) {
+		i := max(monthsBetween(first, month), 0)
+		if i < length {
+			changes[id][i] = changes[id][i].Add(amount)
+		}
+	}
+
+	for _, a := range accounts {
+		if a.InitialBalanceDate != nil {
+			change(a.ID, types.NewMonth(a.InitialBalanceDate.Year(), a.InitialBalanceDate.Month()), a.InitialBalance)
+		}
+	}
+
+	var transactions []Transaction
+	if len(ids) > 0 {
+		err := db.
+			Preload("SourceAccount").
+			Where(db.Where("transactions.source_account_id IN ?", ids).Or("transactions.destination_account_id IN ?", ids)).
+			Where("transactions.date < date(?)", until.AddDate(0, 1)).
+			Find(&transactions).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range transactions {
+		month := types.NewMonth(t.Date.Year(), t.Date.Month())
+
+		if _, ok := changes[t.DestinationAccountID]; ok {
+			// Income is only available from its AvailableFrom month on
+			availableFrom := month
+			if t.SourceAccount.External && t.EnvelopeID == nil && t.AvailableFrom.After(month) {
+				availableFrom = t.AvailableFrom
+			}
+
+			change(t.DestinationAccountID, availableFrom, t.Received())
+		}
+
+		if _, ok := changes[t.SourceAccountID]; ok {
+			change(t.SourceAccountID, month, t.Amount.Neg())
+		}
+	}
+
+	available := make([]decimal.Decimal, length)
+	sums := make(map[uuid.UUID]decimal.Decimal, len(accounts))
+	for i, m := 0, first; i < length; i, m = i+1, m.AddDate(0, 1) {
+		date := time.Time(m.AddDate(0, 1)).AddDate(0, 0, -1)
+
+		for _, a := range accounts {
+			sums[a.ID] = sums[a.ID].Add(changes[a.ID][i])
+
+			amount, err := rates.Convert(sums[a.ID], a.Currency, date)
+			if err != nil {
+				return nil, err
+			}
+
+			available[i] = available[i].Add(amount)
+		}
+	}
+
+	return available, nil
+}
+
+// monthsBetween returns the number of months from one month to another.
+// It is negative if the second month is before the first.
+func monthsBetween(from, to types.Month) int {
+	f, t := time.Time(from), time.Time(to)
+	return (t.Year()-f.Year())*12 + int(t.Month()) - int(f.Month())
+}
+
+// moneyFlows returns all inflows and outflows of the on-budget accounts before
+// a point in time, earliest first.
+func (b Budget) moneyFlows(db *gorm.DB, before time.Time) ([]moneyFlow, error) {
+	var flows []moneyFlow
+	err := db.
+		Table("transactions").
+		Joins("JOIN accounts source_account ON transactions.source_account_id = source_account.id").
+		Joins("JOIN accounts destination_account ON transactions.destination_account_id = destination_account.id").
+		Where("source_account.budget_id = ?", b.ID).
+		Where("transactions.date < date(?)", before).
+		Where(db.
+			Where("source_account.on_budget = false AND destination_account.on_budget = true AND transactions.envelope_id IS NULL").
+			Or("source_account.on_budget = true AND destination_account.on_budget = false")).
+		Select("IIF(destination_account.on_budget AND transactions.destination_amount > 0, transactions.destination_amount, transactions.amount) AS Amount, transactions.date AS Date, destination_account.on_budget AS Income, IIF(destination_account.on_budget, destination_account.currency, source_account.currency) AS Currency").
+		Order("datetime(transactions.date) ASC, transactions.created_at ASC").
+		Find(&flows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := LoadExchangeRates(db, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []Account
+	err = db.Where(&Account{BudgetID: b.ID, OnBudget: true}).Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Positive initial balances are income at the initial balance date
+	initial := make([]moneyFlow, 0)
+	for _, a := range accounts {
+		if !a.InitialBalance.IsPositive() {
+			continue
+		}
+
+		date := a.CreatedAt
+		if a.InitialBalanceDate != nil {
+			date = *a.InitialBalanceDate
+		}
+
+		if !date.Before(before) {
+			continue
+		}
+
+		initial = append(initial, moneyFlow{Amount: a.InitialBalance, Date: date, Income: true, Currency: a.Currency})
+	}
+
+	flows = append(initial, flows...)
+	for i := range flows {
+		flows[i].Amount, err = rates.Convert(flows[i].Amount, flows[i].Currency, flows[i].Date)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(flows, func(i, j int) bool {
+		return flows[i].Date.Before(flows[j].Date)
+	})
+
+	return flows, nil
+}
+
+// ageOfMoney matches income to outflows first in, first out and returns the average
+// age in days of the money spent in the most recent outflows, weighted by amount.
+func ageOfMoney(flows []moneyFlow) *int64 {
+	type lot struct {
+		amount decimal.Decimal
+		date   time.Time
+	}
+
+	type outflowAge struct {
+		weighted decimal.Decimal
+		matched  decimal.Decimal
+	}
+
+	lots := make([]lot, 0)
+	ages := make([]outflowAge, 0)
+
+	for _, flow := range flows {
+		if flow.Income {
+			lots = append(lots, lot{flow.Amount, flow.Date})
+			continue
+		}
+
+		// Outflows that cannot be matched to any income are ignored
+		age := outflowAge{}
+		remaining := flow.Amount
+		for remaining.IsPositive() && len(lots) > 0 {
+			take := decimal.Min(remaining, lots[0].amount)
+			days := int64(flow.Date.Sub(lots[0].date).Hours() / 24)
+
+			age.weighted = age.weighted.Add(take.Mul(decimal.NewFromInt(days)))
+			age.matched = age.matched.Add(take)
+			remaining = remaining.Sub(take)
+
+			lots[0].amount = lots[0].amount.Sub(take)
+			if !lots[0].amount.IsPositive() {
+				lots = lots[1:]
+			}
+		}
+
+		if age.matched.IsPositive() {
+			ages = append(ages, age)
+		}
+	}
+
+	if len(ages) == 0 {
+		return nil
+	}
+
+	if len(ages) > ageOfMoneyOutflows {
+		ages = ages[len(ages)-ageOfMoneyOutflows:]
+	}
+
+	weighted, matched := decimal.Zero, decimal.Zero
+	for _, age := range ages {
+		weighted = weighted.Add(age.weighted)
+		matched = matched.Add(age.matched)
+	}
+
+	result := weighted.Div(matched).Round(0).IntPart()
+	return &result
+}
+
+// daysOfBuffer returns how many days the balance of all on-budget accounts at the end
+// of the month lasts at the average daily outflow of the preceding days.
+//
+// The average is calculated over bufferDays, or the days since the first outflow
+// if that is shorter.
+func (b Budget) daysOfBuffer(db *gorm.DB, month types.Month, flows []moneyFlow) (*int64, error) {
+	end := time.Time(month.AddDate(0, 1))
+	start := end.AddDate(0, 0, -bufferDays)
+
+	var firstOutflow *time.Time
+	outflow := decimal.Zero
+	for _, flow := range flows {
+		if flow.Income {
+			continue
+		}
+
+		if firstOutflow == nil {
+			date := flow.Date
+			firstOutflow = &date
+		}
+
+		if !flow.Date.Before(start) {
+			outflow = outflow.Add(flow.Amount)
+		}
+	}
+
+	if !outflow.IsPositive() {
+		return nil, nil
+	}
+
+	if firstOutflow.After(start) {
+		start = *firstOutflow
+	}
+
+	days := int64(end.Sub(start).Hours() / 24)
+	if days < 1 {
+		days = 1
+	}
+	dailyOutflow := outflow.Div(decimal.NewFromInt(days))
+
+	var accounts []Account
+	err := db.Where(&Account{BudgetID: b.ID, OnBudget: true}).Find(&accounts).Error
 	if err != nil {
 		return nil, err
 	}
 
-	j, err := json.Marshal(&budgets)
+	rates, err := LoadExchangeRates(db, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := decimal.Zero
+	for _, a := range accounts {
+		aBalance, _, err := a.BudgetBalanceMonth(db, rates, month)
+		if err != nil {
+			return nil, err
+		}
+
+		balance = balance.Add(aBalance)
+	}
+
+	result := int64(0)
+	if balance.IsPositive() {
+		result = balance.Div(dailyOutflow).IntPart()
+	}
+
+	return &result, nil
+}
+
+// firstMonth returns the earliest month with a transaction or month config for the budget.
+// If the budget has neither, the zero month is returned.
+func (b Budget) firstMonth(db *gorm.DB) (types.Month, error) {
+	var transactions []Transaction
+	err := db.
+		Joins("JOIN accounts source_account ON transactions.source_account_id = source_account.id").
+		Where("source_account.budget_id = ?", b.ID).
+		Order("datetime(transactions.date) ASC").
+		Limit(1).
+		Find(&transactions).Error
 	if err != nil {
-		return json.RawMessage{}, err
+		return types.Month{}, err
 	}
-	return json.RawMessage(j), nil
+
+	var monthConfigs []MonthConfig
+	err = db.
+		Joins("JOIN envelopes ON month_configs.envelope_id = envelopes.id").
+		Joins("JOIN categories ON envelopes.category_id = categories.id").
+		Where("categories.budget_id = ?", b.ID).
+		Order("month_configs.month ASC").
+		Limit(1).
+		Find(&monthConfigs).Error
+	if err != nil {
+		return types.Month{}, err
+	}
+
+	var first types.Month
+	if len(transactions) > 0 {
+		first = types.MonthOf(transactions[0].Date)
+	}
+
+	if len(monthConfigs) > 0 && (first.IsZero() || monthConfigs[0].Month.Before(first)) {
+		first = monthConfigs[0].Month
+	}
+
+	return first, nil
 }
@@ -0,0 +1,238 @@
+package models
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BudgetEventBufferSize is the number of budget events that are kept so that
+// clients can resume their event stream.
+const BudgetEventBufferSize = 1000
+
+// BudgetEvent is a change to a resource of a budget that clients are notified about.
+//
+// Budget events are written by database callbacks for every change to accounts,
+// envelopes, month configs and transactions. Only the latest BudgetEventBufferSize
+// events of all budgets are kept.
+type BudgetEvent struct {
+	Sequence  uint64 `gorm:"primaryKey;autoIncrement"` // Position of the event in the events of all budgets. Increases with every event and is never reused
+	CreatedAt time.Time
+	BudgetID  uuid.UUID `gorm:"index"`
+	Type      string    // The kind of change, e.g. "transaction.created"
+	Data      string    `gorm:"type:text"` // The BudgetEventData as JSON
+}
+
+// budgetEventsWritten holds a channel for every budget that event streams are waiting
+// for. The channel is closed when events of the budget are written.
+var (
+	budgetEventsWrittenMutex sync.Mutex
+	budgetEventsWritten      = map[uuid.UUID]chan struct{}{}
+)
+
+// BudgetEventData describes the change of a budget event.
+type BudgetEventData struct {
+	Model      string       `json:"model" example:"Transaction"`                               // Name of the model
+	ResourceID uuid.UUID    `json:"resourceId" example:"5b2fd1ef-1ea6-4bd9-9bd9-4ab5c4f6a3b8"` // ID of the resource. For month configs, this is the ID of the envelope
+	Month      *types.Month `json:"month,omitempty" example:"2024-01-01T00:00:00Z"`            // The month of month configs
+	Action     AuditAction  `json:"action" example:"UPDATE"`                                   // The kind of change
+	Changes    AuditChanges `json:"changes"`                                                   // Changed fields with their old and new values
+	RequestID  string       `json:"requestId" example:"c6b1a2f0-5f4e-4c59-8c8e-3f2b7d0c1e9a"`  // ID of the HTTP request that caused the change, if any
+}
+
+// budgetEventSource defines how the budget of the resources of a table is looked up.
+type budgetEventSource struct {
+	column string // Column of the resource the budget is looked up with
+	table  string // Table the budget is looked up in by the value of the column. If empty, the column is the budget ID
+	budget string // Column with the ID of the budget
+	joins  string // Joins needed for the budget column
+}
+
+// budgetEventSources are the tables that budget events are written for.
+var budgetEventSources = map[string]budgetEventSource{
+	"accounts": {
+		column: "budget_id",
+	},
+	"envelopes": {
+		column: "category_id",
+		table:  "categories",
+		budget: "categories.budget_id",
+	},
+	"month_configs": {
+		column: "envelope_id",
+		table:  "envelopes",
+		budget: "categories.budget_id",
+		joins:  "JOIN categories ON categories.id = envelopes.category_id",
+	},
+	"transactions": {
+		column: "source_account_id",
+		table:  "accounts",
+		budget: "accounts.budget_id",
+	},
+}
+
+// budgetEventCallback writes the budget events for the changes of a statement.
+//
+// Changes are taken from the audit entries written for the statement.
+func budgetEventCallback(db *gorm.DB) {
+	source, ok := budgetEventSources[db.Statement.Table]
+	if db.Error != nil || !ok {
+		return
+	}
+
+	entries, ok := db.InstanceGet(auditEntriesKey)
+	if !ok {
+		return
+	}
+	rows, _ := db.InstanceGet(auditEntryRowsKey)
+
+	tx := db.Session(&gorm.Session{NewDB: true})
+	requestID, _ := db.Statement.Context.Value(string(DBContextRequestID)).(string)
+
+	var events []BudgetEvent
+	for i, entry := range entries.([]AuditEntry) {
+		row := rows.([]map[string]any)[i]
+
+		budgetID, ok, err := budgetEventBudget(tx, source, row[source.column])
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+
+		if !ok {
+			continue
+		}
+
+		data := BudgetEventData{
+			Model:      entry.Model,
+			ResourceID: entry.ResourceID,
+			Action:     entry.Action,
+			Changes:    entry.Changes,
+			RequestID:  requestID,
+		}
+
+		if value, ok := row["month"]; ok {
+			var month types.Month
+			if month.Scan(value) == nil {
+				data.Month = &month
+			}
+		}
+
+		j, err := json.Marshal(data)
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+
+		events = append(events, BudgetEvent{
+			BudgetID: budgetID,
+			Type:     changeEvent(entry.Model, entry.Action),
+			Data:     string(j),
+		})
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	err := tx.Create(&events).Error
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	// The SQLite connection pool has only one connection. Readers that are notified
+	// while the statement is part of a transaction therefore wait for the transaction
+	// to finish and do not miss the events.
+	notifyBudgetEvents(events)
+
+	// Only keep the latest events
+	err = tx.Where("sequence <= (SELECT MAX(sequence) FROM budget_events) - ?", BudgetEventBufferSize).Delete(&BudgetEvent{}).Error
+	if err != nil {
+		_ = db.AddError(err)
+	}
+}
+
+// BudgetEventsWritten returns a channel that is closed when the next events of the budget
+// are written.
+//
+// Callers need to get the channel before they read the events of the budget so that
+// events written in between are not missed.
+func BudgetEventsWritten(budgetID uuid.UUID) <-chan struct{} {
+	budgetEventsWrittenMutex.Lock()
+	defer budgetEventsWrittenMutex.Unlock()
+
+	written, ok := budgetEventsWritten[budgetID]
+	if !ok {
+		written = make(chan struct{})
+		budgetEventsWritten[budgetID] = written
+	}
+
+	return written
+}
+
+// notifyBudgetEvents notifies everyone waiting for events of the budgets of the events.
+func notifyBudgetEvents(events []BudgetEvent) {
+	budgetEventsWrittenMutex.Lock()
+	defer budgetEventsWrittenMutex.Unlock()
+
+	for _, event := range events {
+		written, ok := budgetEventsWritten[event.BudgetID]
+		if ok {
+			close(written)
+			delete(budgetEventsWritten, event.BudgetID)
+		}
+	}
+}
+
+// budgetEventBudget returns the ID of the budget for the value of the source column.
+// If the budget cannot be found, ok is false.
+func budgetEventBudget(db *gorm.DB, source budgetEventSource, value any) (budgetID uuid.UUID, ok bool, err error) {
+	id, err := resourceID(value)
+	if err != nil {
+		return uuid.Nil, false, nil
+	}
+
+	if source.table == "" {
+		return id, true, nil
+	}
+
+	var budgetIDs []uuid.UUID
+	err = db.Table(source.table).Joins(source.joins).Where(source.table+".id = ?", id).Pluck(source.budget, &budgetIDs).Error
+	if err != nil || len(budgetIDs) == 0 {
+		return uuid.Nil, false, err
+	}
+
+	return budgetIDs[0], true, nil
+}
+
+// BudgetEventSequences returns the sequences of the oldest and the latest budget event
+// that are kept. If there are no events, both are 0.
+func BudgetEventSequences(db *gorm.DB) (oldest, latest uint64, err error) {
+	var sequences struct {
+		Oldest uint64
+		Latest uint64
+	}
+
+	err = db.Model(&BudgetEvent{}).Select("COALESCE(MIN(sequence), 0) AS oldest, COALESCE(MAX(sequence), 0) AS latest").Scan(&sequences).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return sequences.Oldest, sequences.Latest, nil
+}
+
+// BudgetEvents returns up to limit events of the budget after the sequence, oldest first.
+func BudgetEvents(db *gorm.DB, budgetID uuid.UUID, after uint64, limit int) ([]BudgetEvent, error) {
+	var events []BudgetEvent
+	err := db.Where(&BudgetEvent{BudgetID: budgetID}).Where("sequence > ?", after).Order("sequence ASC").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
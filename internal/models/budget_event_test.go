@@ -0,0 +1,139 @@
+package models_test
+
+import (
+	"encoding/json"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// budgetEvents returns all kept events of the budget, oldest first.
+func (suite *TestSuiteStandard) budgetEvents(budgetID uuid.UUID) []models.BudgetEvent {
+	events, err := models.BudgetEvents(models.DB, budgetID, 0, models.BudgetEventBufferSize)
+	suite.Require().Nil(err)
+
+	return events
+}
+
+func (suite *TestSuiteStandard) TestBudgetEvents() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	external := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: types.NewMonth(2024, 1), Allocation: decimal.NewFromFloat(50)})
+
+	transaction := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      account.ID,
+		DestinationAccountID: external.ID,
+		Amount:               decimal.NewFromFloat(10),
+	})
+
+	err := models.DB.Model(&transaction).Select("Note").Updates(models.Transaction{Note: "Groceries"}).Error
+	suite.Require().Nil(err)
+
+	err = models.DB.Delete(&transaction).Error
+	suite.Require().Nil(err)
+
+	// Events of other budgets are not included
+	other := suite.createTestBudget(models.Budget{})
+	_ = suite.createTestAccount(models.Account{BudgetID: other.ID})
+
+	events := suite.budgetEvents(budget.ID)
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		names = append(names, event.Type)
+	}
+
+	// Budgets and categories do not have events
+	suite.Assert().Equal([]string{
+		"account.created",
+		"account.created",
+		"envelope.created",
+		"monthConfig.created",
+		"transaction.created",
+		"transaction.updated",
+		"transaction.deleted",
+	}, names)
+
+	for i := 1; i < len(events); i++ {
+		suite.Assert().Greater(events[i].Sequence, events[i-1].Sequence, "Sequences are not increasing")
+	}
+
+	var data models.BudgetEventData
+	suite.Require().Nil(json.Unmarshal([]byte(events[3].Data), &data))
+	suite.Assert().Equal(envelope.ID, data.ResourceID)
+	suite.Require().NotNil(data.Month)
+	suite.Assert().Equal(types.NewMonth(2024, 1), *data.Month)
+
+	data = models.BudgetEventData{}
+	suite.Require().Nil(json.Unmarshal([]byte(events[5].Data), &data))
+	suite.Assert().Equal(transaction.ID, data.ResourceID)
+	suite.Assert().Equal(models.AuditActionUpdate, data.Action)
+	suite.Assert().Nil(data.Month)
+	suite.Assert().Equal("Groceries", data.Changes["note"].New)
+
+	suite.Assert().Len(suite.budgetEvents(other.ID), 1)
+}
+
+func (suite *TestSuiteStandard) TestBudgetEventsBuffer() {
+	budget := suite.createTestBudget(models.Budget{})
+
+	events := make([]models.BudgetEvent, models.BudgetEventBufferSize)
+	for i := range events {
+		events[i] = models.BudgetEvent{BudgetID: budget.ID, Type: "account.updated", Data: "{}"}
+	}
+	suite.Require().Nil(models.DB.CreateInBatches(&events, 100).Error)
+
+	oldest, latest, err := models.BudgetEventSequences(models.DB)
+	suite.Require().Nil(err)
+	suite.Assert().Equal(uint64(1), oldest)
+	suite.Assert().Equal(uint64(models.BudgetEventBufferSize), latest)
+
+	// Writing an event removes the oldest one
+	_ = suite.createTestAccount(models.Account{BudgetID: budget.ID})
+
+	oldest, latest, err = models.BudgetEventSequences(models.DB)
+	suite.Require().Nil(err)
+	suite.Assert().Equal(uint64(2), oldest)
+	suite.Assert().Equal(uint64(models.BudgetEventBufferSize+1), latest)
+
+	// Sequences are not reused when events are deleted
+	suite.Require().Nil(models.DB.Where("true").Delete(&models.BudgetEvent{}).Error)
+	_ = suite.createTestAccount(models.Account{BudgetID: budget.ID})
+
+	events = suite.budgetEvents(budget.ID)
+	suite.Require().Len(events, 1)
+	suite.Assert().Equal(uint64(models.BudgetEventBufferSize+2), events[0].Sequence)
+}
+
+func (suite *TestSuiteStandard) TestBudgetEventsWritten() {
+	budget := suite.createTestBudget(models.Budget{})
+	other := suite.createTestBudget(models.Budget{})
+
+	written := models.BudgetEventsWritten(budget.ID)
+	otherWritten := models.BudgetEventsWritten(other.ID)
+	suite.Assert().Equal(written, models.BudgetEventsWritten(budget.ID), "Waiting for the same budget must return the same channel")
+
+	_ = suite.createTestAccount(models.Account{BudgetID: budget.ID})
+
+	select {
+	case <-written:
+	default:
+		suite.Fail("Writing an event must close the channel of its budget")
+	}
+
+	select {
+	case <-otherWritten:
+		suite.Fail("Writing an event must not close the channels of other budgets")
+	default:
+	}
+
+	select {
+	case <-models.BudgetEventsWritten(budget.ID):
+		suite.Fail("Waiting again must return a new channel")
+	default:
+	}
+}
@@ -3,6 +3,7 @@ package models_test
 import (
 	"encoding/json"
 	"strings"
+	"testing"
 	"time"
 
 	"github.com/envelope-zero/backend/v7/internal/models"
@@ -224,7 +225,7 @@ func (suite *TestSuiteStandard) TestBudgetExport() {
 		Name: "TestBudgetExport",
 	})
 
-	raw, err := models.Budget{}.Export()
+	raw, err := models.Budget{}.Export(nil)
 	if err != nil {
 		require.Fail(t, "budget export failed", err)
 	}
@@ -237,3 +238,194 @@ func (suite *TestSuiteStandard) TestBudgetExport() {
 
 	require.Len(t, budgets, 1, "Number of budgets in export is wrong")
 }
+
+func (suite *TestSuiteStandard) TestBudgetHealth() {
+	budget := suite.createTestBudget(models.Budget{})
+
+	cash := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true, Name: "TestBudgetHealth Cash"})
+	employer := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, Name: "TestBudgetHealth Employer"})
+	supermarket := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, Name: "TestBudgetHealth Supermarket"})
+
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	groceries := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Groceries"})
+	savings := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Savings"})
+
+	january := types.NewMonth(2024, 1)
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: groceries.ID, Month: january, Allocation: decimal.NewFromFloat(50)})
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: savings.ID, Month: january.AddDate(0, 1), Allocation: decimal.NewFromFloat(2000)})
+
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      employer.ID,
+		DestinationAccountID: cash.ID,
+		Amount:               decimal.NewFromFloat(1000),
+		Date:                 time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		AvailableFrom:        january,
+	})
+
+	for _, day := range []int{11, 31} {
+		_ = suite.createTestTransaction(models.Transaction{
+			SourceAccountID:      cash.ID,
+			DestinationAccountID: supermarket.ID,
+			EnvelopeID:           &groceries.ID,
+			Amount:               decimal.NewFromFloat(100),
+			Date:                 time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+		})
+	}
+
+	health, err := budget.Health(models.DB, january.AddDate(0, 1))
+	suite.Require().Nil(err)
+
+	// Both outflows are paid from the income on January 1st, aged 10 and 30 days
+	suite.Require().NotNil(health.AgeOfMoney)
+	suite.Assert().Equal(int64(20), *health.AgeOfMoney)
+
+	// 200 spent over the 50 days since the first outflow, 800 left
+	suite.Require().NotNil(health.DaysOfBuffer)
+	suite.Assert().Equal(int64(200), *health.DaysOfBuffer)
+
+	suite.Assert().Equal(2, health.MonthsAnalyzed)
+	suite.Assert().Equal([]types.Month{january}, health.OverspentMonths)
+	suite.Assert().Equal([]types.Month{january.AddDate(0, 1)}, health.AvailableNegativeMonths)
+
+	// Before the first transaction, there is nothing to calculate
+	health, err = budget.Health(models.DB, types.NewMonth(2023, 12))
+	suite.Require().Nil(err)
+	suite.Assert().Nil(health.AgeOfMoney)
+	suite.Assert().Nil(health.DaysOfBuffer)
+	suite.Assert().Equal(0, health.MonthsAnalyzed)
+}
+
+func (suite *TestSuiteStandard) TestBudgetHealthAgeOfMoneyFIFO() {
+	budget := suite.createTestBudget(models.Budget{})
+
+	initialBalanceDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cash := suite.createTestAccount(models.Account{
+		BudgetID:           budget.ID,
+		OnBudget:           true,
+		InitialBalance:     decimal.NewFromFloat(100),
+		InitialBalanceDate: &initialBalanceDate,
+	})
+	employer := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, Name: "Employer"})
+	supermarket := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, Name: "Supermarket"})
+
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      employer.ID,
+		DestinationAccountID: cash.ID,
+		Amount:               decimal.NewFromFloat(100),
+		Date:                 time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC),
+		AvailableFrom:        types.NewMonth(2024, 1),
+	})
+
+	// Half of the outflow is paid from the initial balance (20 days old),
+	// the other half from the income (10 days old)
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      cash.ID,
+		DestinationAccountID: supermarket.ID,
+		Amount:               decimal.NewFromFloat(200),
+		Date:                 time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC),
+	})
+
+	health, err := budget.Health(models.DB, types.NewMonth(2024, 1))
+	suite.Require().Nil(err)
+	suite.Require().NotNil(health.AgeOfMoney)
+	suite.Assert().Equal(int64(15), *health.AgeOfMoney)
+
+	// Nothing is left
+	suite.Require().NotNil(health.DaysOfBuffer)
+	suite.Assert().Equal(int64(0), *health.DaysOfBuffer)
+}
+
+func (suite *TestSuiteStandard) TestBudgetHealthDBFail() {
+	budget := suite.createTestBudget(models.Budget{})
+
+	suite.CloseDB()
+
+	_, err := budget.Health(models.DB, types.NewMonth(2024, 1))
+	suite.Assert().NotNil(err)
+}
+
+func (suite *TestSuiteStandard) TestBudgetClone() {
+	budget := suite.createTestBudget(models.Budget{Name: "Original", Note: "Note", Currency: "€"})
+
+	initialBalanceDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true, Name: "Bank", InitialBalance: decimal.NewFromFloat(100), InitialBalanceDate: &initialBalanceDate})
+	external := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, Name: "Shop"})
+	_ = suite.createTestMatchRule(models.MatchRule{AccountID: external.ID, Match: "Shop*"})
+
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID, Name: "Daily"})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Groceries"})
+	suite.Require().Nil(models.DB.Model(&external).Select("DefaultEnvelopeID").Updates(models.Account{DefaultEnvelopeID: &envelope.ID}).Error)
+	_ = suite.createTestGoal(models.Goal{EnvelopeID: envelope.ID, Name: "Stock up", Amount: decimal.NewFromFloat(50)})
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: types.NewMonth(2024, 1), Allocation: decimal.NewFromFloat(40)})
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      account.ID,
+		DestinationAccountID: external.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(15),
+		Date:                 time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+	})
+
+	count := func(clone models.Budget) (accounts, envelopes, goals, matchRules, monthConfigs, transactions int64) {
+		models.DB.Model(&models.Account{}).Where(&models.Account{BudgetID: clone.ID}).Count(&accounts)
+		models.DB.Model(&models.Envelope{}).Joins("JOIN categories ON categories.id = envelopes.category_id").Where("categories.budget_id = ?", clone.ID).Count(&envelopes)
+		models.DB.Model(&models.Goal{}).Joins("JOIN envelopes ON envelopes.id = goals.envelope_id").Joins("JOIN categories ON categories.id = envelopes.category_id").Where("categories.budget_id = ?", clone.ID).Count(&goals)
+		models.DB.Model(&models.MatchRule{}).Joins("JOIN accounts ON accounts.id = match_rules.account_id").Where("accounts.budget_id = ?", clone.ID).Count(&matchRules)
+		models.DB.Model(&models.MonthConfig{}).Joins("JOIN envelopes ON envelopes.id = month_configs.envelope_id").Joins("JOIN categories ON categories.id = envelopes.category_id").Where("categories.budget_id = ?", clone.ID).Count(&monthConfigs)
+		models.DB.Model(&models.Transaction{}).Joins("JOIN accounts ON accounts.id = transactions.source_account_id").Where("accounts.budget_id = ?", clone.ID).Count(&transactions)
+		return
+	}
+
+	tests := []struct {
+		name     string
+		options  models.CloneOptions
+		expected [6]int64
+	}{
+		{"Structure", models.CloneOptions{}, [6]int64{2, 1, 0, 0, 0, 0}},
+		{"Goals and match rules", models.CloneOptions{GoalsAndMatchRules: true}, [6]int64{2, 1, 1, 1, 0, 0}},
+		{"Everything", models.CloneOptions{GoalsAndMatchRules: true, Transactions: true}, [6]int64{2, 1, 1, 1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			clone, err := budget.Clone(models.DB, tt.name, tt.options)
+			require.Nil(t, err)
+			assert.NotEqual(t, budget.ID, clone.ID)
+			assert.Equal(t, tt.name, clone.Name)
+			assert.Equal(t, budget.Note, clone.Note)
+			assert.Equal(t, budget.Currency, clone.Currency)
+
+			accounts, envelopes, goals, matchRules, monthConfigs, transactions := count(clone)
+			assert.Equal(t, tt.expected, [6]int64{accounts, envelopes, goals, matchRules, monthConfigs, transactions})
+
+			var bank models.Account
+			require.Nil(t, models.DB.Where(&models.Account{BudgetID: clone.ID, Name: "Bank"}).First(&bank).Error)
+			assert.True(t, bank.OnBudget)
+
+			var shop models.Account
+			require.Nil(t, models.DB.Where(&models.Account{BudgetID: clone.ID, Name: "Shop"}).First(&shop).Error)
+			require.NotNil(t, shop.DefaultEnvelopeID)
+
+			var defaultEnvelope models.Envelope
+			require.Nil(t, models.DB.Preload("Category").First(&defaultEnvelope, shop.DefaultEnvelopeID).Error)
+			assert.Equal(t, clone.ID, defaultEnvelope.Category.BudgetID)
+			assert.Equal(t, "Groceries", defaultEnvelope.Name)
+
+			balance, err := clone.Balance(models.DB)
+			require.Nil(t, err)
+			if tt.options.Transactions {
+				assert.True(t, balance.Equal(decimal.NewFromFloat(85)), "Balance is %s", balance)
+			} else {
+				assert.True(t, balance.IsZero(), "Balance is %s", balance)
+			}
+		})
+	}
+}
+
+func (suite *TestSuiteStandard) TestBudgetCloneDBFail() {
+	budget := suite.createTestBudget(models.Budget{})
+
+	suite.CloseDB()
+
+	_, err := budget.Clone(models.DB, "Clone", models.CloneOptions{})
+	suite.Assert().NotNil(err)
+}
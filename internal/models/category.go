@@ -81,17 +81,31 @@ func (c *Category) Envelopes(tx *gorm.DB) ([]Envelope, error) {
 	return envelopes, nil
 }
 
-// Returns all categories on this instance for export
-func (Category) Export() (json.RawMessage, error) {
+// Returns all categories for export. If budgetID is set, only categories of that budget are returned.
+func (Category) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[Category](categoryBudgetScope(budgetID))
+}
+
+// Table returns all categories for export as a table.
+func (Category) Table(budgetID *uuid.UUID) (Table, error) {
 	var categories []Category
-	err := DB.Unscoped().Where(&Category{}).Find(&categories).Error
+	err := DB.Scopes(categoryBudgetScope(budgetID)).Preload("Budget").Order("categories.name ASC").Find(&categories).Error
 	if err != nil {
-		return nil, err
+		return Table{}, err
 	}
 
-	j, err := json.Marshal(&categories)
-	if err != nil {
-		return json.RawMessage{}, err
+	table := Table{
+		Name:   "Categories",
+		Header: []string{"ID", "Budget", "Name", "Note", "Archived"},
+	}
+
+	for _, c := range categories {
+		table.Rows = append(table.Rows, []any{c.ID.String(), c.Budget.Name, c.Name, c.Note, c.Archived})
 	}
-	return json.RawMessage(j), nil
+
+	return table, nil
+}
+
+func categoryBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "categories.budget_id")
 }
@@ -104,7 +104,7 @@ func (suite *TestSuiteStandard) TestCategoryExport() {
 		_ = suite.createTestCategory(models.Category{BudgetID: budget.ID, Name: fmt.Sprint(i)})
 	}
 
-	raw, err := models.Category{}.Export()
+	raw, err := models.Category{}.Export(nil)
 	if err != nil {
 		require.Fail(t, "category export failed", err)
 	}
@@ -19,7 +19,8 @@ var DB *gorm.DB
 type EZContext string
 
 const (
-	DBContextURL EZContext = "ez-backend-url"
+	DBContextURL       EZContext = "ez-backend-url"
+	DBContextRequestID EZContext = "ez-request-id"
 )
 
 // Connect opens the SQLite database and configures the connection pool.
@@ -111,6 +112,97 @@ func Connect(dsn string) error {
 		return err
 	}
 
+	// Audit callbacks. They run within the transaction of the statement so
+	// that changes are only recorded if they are committed
+	err = db.Callback().Create().After("gorm:after_create").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:audit_create", auditCreateCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Update().After("gorm:setup_reflect_value").Before("gorm:update").Register("envelope_zero:audit_before_update", auditBeforeCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Update().After("gorm:after_update").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:audit_update", auditUpdateCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Delete().Before("gorm:delete").Register("envelope_zero:audit_before_delete", auditBeforeCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Delete().After("gorm:after_delete").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:audit_delete", auditDeleteCallback)
+	if err != nil {
+		return err
+	}
+
+	// Search index callbacks. Updates and deletes use the rows stored by the audit callbacks
+	err = db.Callback().Create().After("envelope_zero:audit_create").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:search_create", searchCreateCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Update().After("envelope_zero:audit_update").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:search_update", searchUpdateCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Delete().After("envelope_zero:audit_delete").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:search_delete", searchDeleteCallback)
+	if err != nil {
+		return err
+	}
+
+	// Webhook callbacks. Balances of envelopes are read before the hooks of the models
+	// invalidate their snapshots, deliveries are queued from the audit entries
+	err = db.Callback().Create().After("gorm:begin_transaction").Before("gorm:before_create").Register("envelope_zero:webhook_before_create", webhookBeforeCreateCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Update().After("gorm:setup_reflect_value").Before("gorm:before_update").Register("envelope_zero:webhook_before_update", webhookBeforeCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Delete().After("gorm:begin_transaction").Before("gorm:before_delete").Register("envelope_zero:webhook_before_delete", webhookBeforeCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Create().After("envelope_zero:audit_create").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:webhook_create", webhookCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Update().After("envelope_zero:audit_update").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:webhook_update", webhookCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Delete().After("envelope_zero:audit_delete").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:webhook_delete", webhookCallback)
+	if err != nil {
+		return err
+	}
+
+	// Budget event callbacks, events are written from the audit entries
+	err = db.Callback().Create().After("envelope_zero:audit_create").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:budget_event_create", budgetEventCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Update().After("envelope_zero:audit_update").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:budget_event_update", budgetEventCallback)
+	if err != nil {
+		return err
+	}
+
+	err = db.Callback().Delete().After("envelope_zero:audit_delete").Before("gorm:commit_or_rollback_transaction").Register("envelope_zero:budget_event_delete", budgetEventCallback)
+	if err != nil {
+		return err
+	}
+
 	// Set the exported variable
 	DB = db
 
@@ -153,15 +245,30 @@ func createUpdateCallback(db *gorm.DB) {
 		db.Error = ErrCategoryNameNotUnique
 	}
 
+	// Tag names need to be unique per budget
+	if strings.Contains(db.Error.Error(), "UNIQUE constraint failed: tags.budget_id, tags.name") {
+		db.Error = ErrTagNameNotUnique
+	}
+
 	// Unique envelope names per category
 	if strings.Contains(db.Error.Error(), "UNIQUE constraint failed: envelopes.category_id, envelopes.name") {
 		db.Error = ErrEnvelopeNameNotUnique
 	}
 
+	// Unique goal names per envelope
+	if strings.Contains(db.Error.Error(), "UNIQUE constraint failed: goals.name, goals.envelope_id") {
+		db.Error = ErrGoalNameNotUnique
+	}
+
 	if strings.Contains(db.Error.Error(), "UNIQUE constraint failed: month_configs.envelope_id, month_configs.month") {
 		db.Error = ErrMonthConfigMonthNotUnique
 	}
 
+	// Only one exchange rate per currency and date in a budget
+	if strings.Contains(db.Error.Error(), "UNIQUE constraint failed: exchange_rates.budget_id, exchange_rates.currency, exchange_rates.date") {
+		db.Error = ErrExchangeRateNotUnique
+	}
+
 	// Source and destination accounts need to be different
 	if strings.Contains(db.Error.Error(), "CHECK constraint failed: source_destination_different") {
 		db.Error = ErrSourceDoesNotEqualDestination
@@ -191,12 +298,25 @@ func generalCallback(db *gorm.DB) {
 
 // migrate migrates all models to the schema defined in the code.
 func migrate(db *gorm.DB) (err error) {
+	// Removing soft-deleted transactions invalidates balance snapshots and removing
+	// soft-deleted envelopes removes them as default envelopes of accounts,
+	// so the table and the column need to exist first
+	err = db.AutoMigrate(EnvelopeBalance{}, Account{})
+	if err != nil {
+		return fmt.Errorf("error during DB migration: %w", err)
+	}
+
 	err = removeDeletedAt(db)
 	if err != nil {
 		return fmt.Errorf("error during DB migration: %w", err)
 	}
 
-	err = db.AutoMigrate(Budget{}, Account{}, Category{}, Envelope{}, Transaction{}, MonthConfig{}, MatchRule{}, Goal{})
+	err = db.AutoMigrate(Budget{}, Account{}, Category{}, Envelope{}, Transaction{}, MonthConfig{}, MatchRule{}, Goal{}, EnvelopeBalance{}, AuditEntry{}, TrashEntry{}, Reconciliation{}, ExchangeRate{}, Tag{}, TransactionTag{}, Attachment{}, Webhook{}, WebhookDelivery{}, BudgetEvent{})
+	if err != nil {
+		return fmt.Errorf("error during DB migration: %w", err)
+	}
+
+	err = migrateSearch(db)
 	if err != nil {
 		return fmt.Errorf("error during DB migration: %w", err)
 	}
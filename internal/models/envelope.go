@@ -3,7 +3,6 @@ package models
 import (
 	"encoding/json"
 	"errors"
-	"sort"
 	"strings"
 	"time"
 
@@ -23,7 +22,11 @@ type Envelope struct {
 	Archived   bool
 }
 
-var ErrEnvelopeNameNotUnique = errors.New("the envelope name must be unique for the category")
+var (
+	ErrEnvelopeNameNotUnique = errors.New("the envelope name must be unique for the category")
+	ErrEnvelopeMergeSelf     = errors.New("an envelope can not be merged into itself")
+	ErrEnvelopeMergeBudget   = errors.New("only envelopes of the same budget can be merged")
+)
 
 func (e *Envelope) BeforeCreate(tx *gorm.DB) error {
 	_ = e.DefaultModel.BeforeCreate(tx)
@@ -67,191 +70,256 @@ func (e *Envelope) BeforeUpdate(tx *gorm.DB) (err error) {
 	return err
 }
 
+// AfterDelete removes the envelope as default envelope from all accounts.
+func (e *Envelope) AfterDelete(tx *gorm.DB) error {
+	return tx.Model(&Account{}).Where("default_envelope_id = ?", e.ID).UpdateColumn("default_envelope_id", nil).Error
+}
+
 // checkIntegrity verifies references to other resources
 func (e *Envelope) checkIntegrity(tx *gorm.DB, toSave Envelope) error {
 	return tx.First(&Category{}, toSave.CategoryID).Error
 }
 
-// Spent returns the amount spent for the month the time.Time instance is in.
-func (e Envelope) Spent(db *gorm.DB, month types.Month) decimal.Decimal {
-	// All transactions where the Envelope ID matches and that have an external account as source and an internal account as destination
-	var incoming []Transaction
+// Merge merges the envelopes with the IDs into the envelope.
+//
+// All transactions, goals and default envelopes of accounts referencing the envelopes
+// are changed to reference the envelope. Allocations in month configs are added
+// to the ones of the envelope, then the envelopes are deleted.
+// Everything happens in one database transaction.
+func (e Envelope) Merge(db *gorm.DB, ids []uuid.UUID) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var category Category
+		err := tx.First(&category, e.CategoryID).Error
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if id == e.ID {
+				return ErrEnvelopeMergeSelf
+			}
+
+			var source Envelope
+			err := tx.Preload("Category").First(&source, id).Error
+			if err != nil {
+				return err
+			}
+
+			if source.Category.BudgetID != category.BudgetID {
+				return ErrEnvelopeMergeBudget
+			}
+
+			err = e.merge(tx, source)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The balances of the envelope change for all months
+		return tx.Where("envelope_id = ?", e.ID).Delete(&EnvelopeBalance{}).Error
+	})
+}
+
+// merge moves everything referencing the source envelope to the envelope
+// and deletes the source envelope.
+func (e Envelope) merge(tx *gorm.DB, source Envelope) error {
+	// Hooks are skipped since the envelopes are in the same budget
+	err := tx.Model(&Transaction{}).Where("envelope_id = ?", source.ID).UpdateColumn("envelope_id", e.ID).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(&Goal{}).Where(&Goal{EnvelopeID: source.ID}).UpdateColumn("envelope_id", e.ID).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(&Account{}).Where("default_envelope_id = ?", source.ID).UpdateColumn("default_envelope_id", e.ID).Error
+	if err != nil {
+		return err
+	}
 
-	db.Joins("SourceAccount").Joins("DestinationAccount").Where(
-		"SourceAccount__on_budget = 0 AND DestinationAccount__on_budget = 1 AND transactions.envelope_id = ?", e.ID,
-	).Find(&incoming)
+	var monthConfigs []MonthConfig
+	err = tx.Where(&MonthConfig{EnvelopeID: source.ID}).Find(&monthConfigs).Error
+	if err != nil {
+		return err
+	}
 
-	// Add all incoming transactions that are in the correct month
-	incomingSum := decimal.Zero
-	for _, transaction := range incoming {
-		if month.Contains(transaction.Date) {
-			incomingSum = incomingSum.Add(transaction.Amount)
+	for _, m := range monthConfigs {
+		var existing []MonthConfig
+		err = tx.Where(&MonthConfig{EnvelopeID: e.ID, Month: m.Month}).Find(&existing).Error
+		if err != nil {
+			return err
 		}
+
+		// Without a month config for the month, the one of the source is moved
+		if len(existing) == 0 {
+			err = tx.Model(&MonthConfig{}).Where(&MonthConfig{EnvelopeID: source.ID, Month: m.Month}).UpdateColumn("envelope_id", e.ID).Error
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		note := strings.TrimSpace(strings.Join([]string{existing[0].Note, m.Note}, "\n"))
+		err = tx.Model(&MonthConfig{}).Where(&MonthConfig{EnvelopeID: e.ID, Month: m.Month}).UpdateColumns(map[string]any{
+			"allocation": existing[0].Allocation.Add(m.Allocation),
+			"note":       note,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where(&MonthConfig{EnvelopeID: source.ID, Month: m.Month}).Delete(&MonthConfig{}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Delete(&source).Error
+}
+
+// Spent returns the amount spent for the month the time.Time instance is in,
+// in the currency of the budget.
+func (e Envelope) Spent(db *gorm.DB, month types.Month) (decimal.Decimal, error) {
+	// All transactions where the Envelope ID matches and that flow between an on-budget and an off-budget account
+	var transactions []AggregatedTransaction
+	err := aggregatedTransactions(db).
+		Where("transactions.envelope_id = ?", e.ID).
+		Where("source_account.on_budget != destination_account.on_budget").
+		Where("transactions.date >= date(?) AND transactions.date < date(?)", month, month.AddDate(0, 1)).
+		Find(&transactions).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	rates, err := e.exchangeRates(db, transactions)
+	if err != nil {
+		return decimal.Zero, err
 	}
 
-	var outgoing []Transaction
-	db.Joins("SourceAccount").Joins("DestinationAccount").Where(
-		"SourceAccount__on_budget = 1 AND DestinationAccount__on_budget = 0 AND transactions.envelope_id = ?", e.ID,
-	).Find(&outgoing)
+	spent := decimal.Zero
+	for _, transaction := range transactions {
+		amount, err := transaction.budgetAmount(rates)
+		if err != nil {
+			return decimal.Zero, err
+		}
 
-	// Add all outgoing transactions that are in the correct month
-	outgoingSum := decimal.Zero
-	for _, transaction := range outgoing {
-		if month.Contains(transaction.Date) {
-			outgoingSum = outgoingSum.Add(transaction.Amount)
+		// Outgoing transactions are spent, incoming transactions are refunds
+		if transaction.SourceAccountOnBudget {
+			spent = spent.Sub(amount)
+		} else {
+			spent = spent.Add(amount)
 		}
 	}
 
-	return outgoingSum.Neg().Add(incomingSum)
+	return spent, nil
 }
 
+// AggregatedTransaction contains the data of a transaction needed to calculate envelope data.
 type AggregatedTransaction struct {
 	Amount                     decimal.Decimal
+	DestinationAmount          decimal.Decimal
 	Date                       time.Time
 	SourceAccountOnBudget      bool
 	DestinationAccountOnBudget bool
+	SourceAccountCurrency      string
+	DestinationAccountCurrency string
 }
 
-// Balance calculates the balance of an Envelope in a specific month.
-func (e Envelope) Balance(db *gorm.DB, month types.Month) (decimal.Decimal, error) {
-	// Get all relevant data for rawTransactions
-	var rawTransactions []AggregatedTransaction
-	err := db.
+// aggregatedTransactionColumns are the columns selected for AggregatedTransactions.
+const aggregatedTransactionColumns = "transactions.amount AS Amount, transactions.destination_amount AS DestinationAmount, transactions.date AS Date, source_account.on_budget AS SourceAccountOnBudget, destination_account.on_budget AS DestinationAccountOnBudget, source_account.currency AS SourceAccountCurrency, destination_account.currency AS DestinationAccountCurrency"
+
+// aggregatedTransactions returns a query for AggregatedTransactions.
+func aggregatedTransactions(db *gorm.DB) *gorm.DB {
+	return db.
 		Table("transactions").
 		Joins("JOIN accounts source_account ON transactions.source_account_id = source_account.id").
 		Joins("JOIN accounts destination_account ON transactions.destination_account_id = destination_account.id").
-		Where("transactions.date < date(?)", month.AddDate(0, 1)).
-		Where("transactions.envelope_id = ?", e.ID).
-		Select("transactions.amount AS Amount, transactions.date AS Date, source_account.on_budget AS SourceAccountOnBudget, destination_account.on_budget AS DestinationAccountOnBudget").
-		Find(&rawTransactions).Error
-	if err != nil {
-		return decimal.Zero, err
-	}
-
-	// Sort monthTransactions by month
-	monthTransactions := make(map[types.Month][]AggregatedTransaction)
-	for _, transaction := range rawTransactions {
-		tDate := types.NewMonth(transaction.Date.Year(), transaction.Date.Month())
-		monthTransactions[tDate] = append(monthTransactions[tDate], transaction)
-	}
+		Select(aggregatedTransactionColumns)
+}
 
-	// Get MonthConfigs
-	var rawConfigs []MonthConfig
-	err = db.
-		Table("month_configs").
-		Where("month_configs.month < date(?)", month.AddDate(0, 1)).
-		Where("month_configs.envelope_id = ?", e.ID).
-		Find(&rawConfigs).Error
-	if err != nil {
-		return decimal.Zero, nil
+// budgetAmount returns the amount of the transaction for the envelope in the currency of the budget
+// at the date of the transaction.
+//
+// For outgoing transactions, this is the amount in the currency of the source account. For incoming
+// transactions, it is the amount received in the currency of the destination account.
+func (t AggregatedTransaction) budgetAmount(rates ExchangeRates) (decimal.Decimal, error) {
+	if t.SourceAccountOnBudget {
+		return rates.Convert(t.Amount, t.SourceAccountCurrency, t.Date)
 	}
 
-	// Sort MonthConfigs by month
-	configMonths := make(map[types.Month]MonthConfig)
-	for _, monthConfig := range rawConfigs {
-		configMonths[monthConfig.Month] = monthConfig
+	received := t.Amount
+	if t.DestinationAmount.IsPositive() {
+		received = t.DestinationAmount
 	}
 
-	// This is a helper map to only add unique months to the
-	// monthKeys slice
-	monthsWithData := make(map[types.Month]bool)
+	return rates.Convert(received, t.DestinationAccountCurrency, t.Date)
+}
 
-	// Create a slice of the months that have MonthConfigs
-	// to have a sorted list we can iterate over
-	monthKeys := make([]types.Month, 0)
-	for k := range configMonths {
-		if _, ok := monthsWithData[k]; !ok {
-			monthKeys = append(monthKeys, k)
-			monthsWithData[k] = true
+// exchangeRates returns the exchange rates of the budget of the envelope.
+//
+// They are only loaded if any of the transactions is from or to an account with its own currency.
+func (e Envelope) exchangeRates(db *gorm.DB, transactions []AggregatedTransaction) (ExchangeRates, error) {
+	needed := false
+	for _, t := range transactions {
+		if t.SourceAccountCurrency != "" || t.DestinationAccountCurrency != "" {
+			needed = true
+			break
 		}
 	}
 
-	// Add the months that have transaction data
-	for k := range monthTransactions {
-		if _, ok := monthsWithData[k]; !ok {
-			monthKeys = append(monthKeys, k)
-		}
+	if !needed {
+		return ExchangeRates{}, nil
 	}
 
-	// Sort by time so that earlier months are first
-	sort.Slice(monthKeys, func(i, j int) bool {
-		return monthKeys[i].Before(monthKeys[j])
-	})
+	var budgetIDs []uuid.UUID
+	err := db.
+		Table("envelopes").
+		Joins("JOIN categories ON envelopes.category_id = categories.id").
+		Where("envelopes.id = ?", e.ID).
+		Pluck("categories.budget_id", &budgetIDs).Error
+	if err != nil {
+		return ExchangeRates{}, err
+	}
 
-	if len(monthKeys) == 0 {
-		return decimal.Zero, nil
-	}
-
-	sum := decimal.Zero
-	loopMonth := monthKeys[0]
-	for i := 0; i < len(monthKeys); i++ {
-		currentMonthTransactions, transactionsOk := monthTransactions[loopMonth]
-		currentMonthConfig, configOk := configMonths[loopMonth]
-
-		// We always go forward one month until we
-		// reach the last one with data
-		loopMonth = loopMonth.AddDate(0, 1)
-
-		// If there is no data for the current month,
-		// we loop once more and go on to the next month
-		//
-		// We also reset the balance to 0 if it is negative
-		// since with no MonthConfig, the balance starts from 0 again
-		if !transactionsOk && !configOk {
-			i--
-			if sum.IsNegative() {
-				sum = decimal.Zero
-			}
-			continue
-		}
+	if len(budgetIDs) == 0 {
+		return ExchangeRates{}, ErrResourceNotFound
+	}
 
-		// Initialize the sum for this month
-		monthSum := sum
+	return LoadExchangeRates(db, budgetIDs[0])
+}
 
-		for _, transaction := range currentMonthTransactions {
-			if transaction.SourceAccountOnBudget {
-				// Outgoing gets subtracted
-				monthSum = monthSum.Sub(transaction.Amount)
-			} else {
-				// Incoming money gets added to the balance
-				monthSum = monthSum.Add(transaction.Amount)
-			}
+// Balance calculates the balance of an Envelope in a specific month.
+//
+// If there is a snapshot of the balance for the month, it is returned. Otherwise, the
+// balance is calculated from the latest earlier snapshot.
+func (e Envelope) Balance(db *gorm.DB, month types.Month) (balance decimal.Decimal, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		snapshot, ok, err := latestEnvelopeBalance(tx, e.ID, month)
+		if err != nil {
+			return err
 		}
 
-		// The zero value for a decimal is Zero, so we don't need to check
-		// if there is an allocation
-		monthSum = monthSum.Add(currentMonthConfig.Allocation)
-
-		// If the value is not negative, we're done here.
-		if !monthSum.IsNegative() {
-			sum = monthSum
-			continue
+		if ok && snapshot.Month.Equal(month) {
+			EnvelopeBalanceCacheLookups.WithLabelValues(EnvelopeBalanceCacheHit).Inc()
+			balance = snapshot.Balance
+			return nil
 		}
 
-		// If this is the last month, the sum is the monthSum
-		if monthSum.IsNegative() && loopMonth.After(month) {
-			sum = monthSum
-		} else if monthSum.IsNegative() {
-			sum = decimal.Zero
+		months, err := e.replay(tx, month, month, snapshot, ok)
+		if err != nil {
+			return err
 		}
 
-		// In cases where the sum is negative and we do not have
-		// configuration for the month before the month we are
-		// calculating the balance for, we set the balance to 0
-		// in the last loop iteration.
-		//
-		// This stops the rollover of overflow without configuration
-		// infinitely far into the future.
-		//
-		// We check the month before the month we are calculating for
-		// because if we do not have configuration for the current month,
-		// negative balance from the month before could still roll over.
-		if monthSum.IsNegative() && i+1 == len(monthKeys) && loopMonth.Before(month) {
-			sum = decimal.Zero
-		}
+		balance = months[0].Balance
+		return nil
+	})
+	if err != nil {
+		return decimal.Zero, err
 	}
 
-	return sum, nil
+	return balance, nil
 }
 
 // EnvelopeMonth contains data about an Envelope for a specific month.
@@ -264,7 +332,11 @@ type EnvelopeMonth struct {
 
 // Month calculates the month specific values for an envelope and returns an EnvelopeMonth and allocation ID for them.
 func (e Envelope) Month(db *gorm.DB, month types.Month) (EnvelopeMonth, error) {
-	spent := e.Spent(db, month)
+	spent, err := e.Spent(db, month)
+	if err != nil {
+		return EnvelopeMonth{}, err
+	}
+
 	envelopeMonth := EnvelopeMonth{
 		Envelope:   e,
 		Spent:      spent,
@@ -273,7 +345,7 @@ func (e Envelope) Month(db *gorm.DB, month types.Month) (EnvelopeMonth, error) {
 	}
 
 	var monthConfig MonthConfig
-	err := db.Where(&MonthConfig{
+	err = db.Where(&MonthConfig{
 		EnvelopeID: e.ID,
 		Month:      month,
 	}).Find(&monthConfig).Error
@@ -292,17 +364,182 @@ func (e Envelope) Month(db *gorm.DB, month types.Month) (EnvelopeMonth, error) {
 	return envelopeMonth, nil
 }
 
-// Returns all envelopes on this instance for export
-func (Envelope) Export() (json.RawMessage, error) {
-	var envelopes []Envelope
-	err := DB.Unscoped().Where(&Envelope{}).Find(&envelopes).Error
+// Months calculates the EnvelopeMonth for every month from the first to the last month, inclusive.
+//
+// The balance is carried forward from month to month instead of being recalculated
+// for every month. Negative balances do not roll over.
+func (e Envelope) Months(db *gorm.DB, from, until types.Month) (months []EnvelopeMonth, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		snapshot, ok, err := latestEnvelopeBalance(tx, e.ID, from.AddDate(0, -1))
+		if err != nil {
+			return err
+		}
+
+		months, err = e.replay(tx, from, until, snapshot, ok)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return months, nil
+}
+
+// replay calculates the EnvelopeMonth for every month from the first to the last month, inclusive,
+// and saves snapshots of their balances.
+//
+// If ok is true, the calculation starts after the month of the snapshot with its balance.
+// Otherwise, it starts at the first month with data for the envelope.
+func (e Envelope) replay(tx *gorm.DB, from, until types.Month, snapshot EnvelopeBalance, ok bool) ([]EnvelopeMonth, error) {
+	if ok {
+		EnvelopeBalanceCacheLookups.WithLabelValues(EnvelopeBalanceCachePartial).Inc()
+	} else {
+		EnvelopeBalanceCacheLookups.WithLabelValues(EnvelopeBalanceCacheMiss).Inc()
+	}
+
+	transactionQuery := aggregatedTransactions(tx).
+		Where("transactions.date < date(?)", until.AddDate(0, 1)).
+		Where("transactions.envelope_id = ?", e.ID)
+
+	configQuery := tx.
+		Table("month_configs").
+		Where("month_configs.month < date(?)", until.AddDate(0, 1)).
+		Where("month_configs.envelope_id = ?", e.ID)
+
+	// Only data after the snapshot is needed
+	first := from
+	balance := decimal.Zero
+	if ok {
+		first = snapshot.Month.AddDate(0, 1)
+		balance = snapshot.Balance
+		transactionQuery = transactionQuery.Where("transactions.date >= date(?)", first)
+		configQuery = configQuery.Where("month_configs.month >= date(?)", first)
+	}
+
+	var rawTransactions []AggregatedTransaction
+	err := transactionQuery.Find(&rawTransactions).Error
 	if err != nil {
 		return nil, err
 	}
 
-	j, err := json.Marshal(&envelopes)
+	var rawConfigs []MonthConfig
+	err = configQuery.Find(&rawConfigs).Error
 	if err != nil {
-		return json.RawMessage{}, err
+		return nil, err
+	}
+
+	rates, err := e.exchangeRates(tx, rawTransactions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort transactions and allocations by month and find the first month with data
+	monthTransactions := make(map[types.Month][]AggregatedTransaction)
+	for _, transaction := range rawTransactions {
+		month := types.NewMonth(transaction.Date.Year(), transaction.Date.Month())
+		monthTransactions[month] = append(monthTransactions[month], transaction)
+
+		if month.Before(first) {
+			first = month
+		}
 	}
-	return json.RawMessage(j), nil
+
+	allocations := make(map[types.Month]decimal.Decimal)
+	for _, monthConfig := range rawConfigs {
+		allocations[monthConfig.Month] = monthConfig.Allocation
+
+		if monthConfig.Month.Before(first) {
+			first = monthConfig.Month
+		}
+	}
+
+	months := make([]EnvelopeMonth, 0)
+	snapshots := make([]EnvelopeBalance, 0)
+	for month := first; !month.After(until); month = month.AddDate(0, 1) {
+		// Overspending is only shown in the month it happens in
+		if balance.IsNegative() {
+			balance = decimal.Zero
+		}
+
+		spent := decimal.Zero
+		for _, transaction := range monthTransactions[month] {
+			amount, err := transaction.budgetAmount(rates)
+			if err != nil {
+				return nil, err
+			}
+
+			if transaction.SourceAccountOnBudget {
+				// Outgoing gets subtracted
+				balance = balance.Sub(amount)
+			} else {
+				// Incoming money gets added to the balance
+				balance = balance.Add(amount)
+			}
+
+			// Spent only counts money flowing between on-budget and off-budget accounts
+			if transaction.SourceAccountOnBudget && !transaction.DestinationAccountOnBudget {
+				spent = spent.Sub(amount)
+			} else if !transaction.SourceAccountOnBudget && transaction.DestinationAccountOnBudget {
+				spent = spent.Add(amount)
+			}
+		}
+
+		// The zero value for a decimal is Zero, so we don't need to check
+		// if there is an allocation
+		allocation := allocations[month]
+		balance = balance.Add(allocation)
+
+		if month.Before(from) {
+			continue
+		}
+
+		months = append(months, EnvelopeMonth{
+			Envelope:   e,
+			Spent:      spent,
+			Balance:    balance,
+			Allocation: allocation,
+		})
+
+		snapshots = append(snapshots, EnvelopeBalance{
+			EnvelopeID: e.ID,
+			Month:      month,
+			Balance:    balance,
+		})
+	}
+
+	err = saveEnvelopeBalances(tx, snapshots)
+	if err != nil {
+		return nil, err
+	}
+
+	return months, nil
+}
+
+// Returns all envelopes for export. If budgetID is set, only envelopes of that budget are returned.
+func (Envelope) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[Envelope](envelopeBudgetScope(budgetID))
+}
+
+// Table returns all envelopes for export as a table.
+func (Envelope) Table(budgetID *uuid.UUID) (Table, error) {
+	var envelopes []Envelope
+	err := DB.Scopes(envelopeBudgetScope(budgetID)).Preload("Category").Order("envelopes.name ASC").Find(&envelopes).Error
+	if err != nil {
+		return Table{}, err
+	}
+
+	table := Table{
+		Name:   "Envelopes",
+		Header: []string{"ID", "Category", "Name", "Note", "Archived"},
+	}
+
+	for _, e := range envelopes {
+		table.Rows = append(table.Rows, []any{e.ID.String(), e.Category.Name, e.Name, e.Note, e.Archived})
+	}
+
+	return table, nil
+}
+
+func envelopeBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "categories.budget_id", "JOIN categories ON categories.id = envelopes.category_id")
 }
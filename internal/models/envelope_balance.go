@@ -0,0 +1,85 @@
+package models
+
+import (
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnvelopeBalance is a snapshot of the balance of an envelope at the end of a month.
+//
+// Snapshots are created when balances are calculated and are deleted for the month
+// of a change and all following months whenever a transaction or allocation for the
+// envelope changes.
+type EnvelopeBalance struct {
+	Timestamps
+	EnvelopeID uuid.UUID       `gorm:"primaryKey"`
+	Envelope   Envelope        `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Month      types.Month     `gorm:"primaryKey"`
+	Balance    decimal.Decimal `gorm:"type:DECIMAL(20,8)"`
+}
+
+// Results for lookups of envelope balance snapshots.
+const (
+	EnvelopeBalanceCacheHit     = "hit"     // A snapshot for the month exists
+	EnvelopeBalanceCachePartial = "partial" // A snapshot for an earlier month exists, later months are replayed
+	EnvelopeBalanceCacheMiss    = "miss"    // No snapshot exists, all months are replayed
+)
+
+// EnvelopeBalanceCacheLookups counts the lookups of envelope balance snapshots by their result.
+var EnvelopeBalanceCacheLookups = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "envelope_balance_cache_lookups_total",
+		Help: "How many envelope balance snapshot lookups were performed, partitioned by result.",
+	},
+	[]string{"result"},
+)
+
+// latestEnvelopeBalance returns the latest snapshot for the envelope at or before a month.
+//
+// If there is no snapshot, ok is false.
+func latestEnvelopeBalance(db *gorm.DB, envelopeID uuid.UUID, month types.Month) (snapshot EnvelopeBalance, ok bool, err error) {
+	var snapshots []EnvelopeBalance
+	err = db.
+		Where("envelope_id = ?", envelopeID).
+		Where("month < date(?)", month.AddDate(0, 1)).
+		Order("month DESC").
+		Limit(1).
+		Find(&snapshots).Error
+	if err != nil || len(snapshots) == 0 {
+		return EnvelopeBalance{}, false, err
+	}
+
+	return snapshots[0], true, nil
+}
+
+// saveEnvelopeBalances creates or updates snapshots.
+func saveEnvelopeBalances(db *gorm.DB, snapshots []EnvelopeBalance) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&snapshots).Error
+}
+
+// invalidateEnvelopeBalances deletes the snapshots for the envelope from the month on.
+func invalidateEnvelopeBalances(db *gorm.DB, envelopeID uuid.UUID, month types.Month) error {
+	return db.
+		Where("envelope_id = ?", envelopeID).
+		Where("month >= date(?)", month).
+		Delete(&EnvelopeBalance{}).Error
+}
+
+// invalidateBudgetEnvelopeBalances deletes all snapshots for all envelopes of a budget.
+func invalidateBudgetEnvelopeBalances(db *gorm.DB, budgetID uuid.UUID) error {
+	return db.
+		Where("envelope_id IN (?)", db.
+			Table("envelopes").
+			Select("envelopes.id").
+			Joins("JOIN categories ON envelopes.category_id = categories.id").
+			Where("categories.budget_id = ?", budgetID)).
+		Delete(&EnvelopeBalance{}).Error
+}
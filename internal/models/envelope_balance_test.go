@@ -0,0 +1,151 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+)
+
+// snapshotMonths returns the months that have a balance snapshot for the envelope.
+func (suite *TestSuiteStandard) snapshotMonths(envelope models.Envelope) []types.Month {
+	var snapshots []models.EnvelopeBalance
+	err := models.DB.Where(&models.EnvelopeBalance{EnvelopeID: envelope.ID}).Order("month ASC").Find(&snapshots).Error
+	suite.Require().Nil(err)
+
+	months := make([]types.Month, 0)
+	for _, snapshot := range snapshots {
+		months = append(months, snapshot.Month)
+	}
+
+	return months
+}
+
+func (suite *TestSuiteStandard) TestEnvelopeBalanceSnapshots() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	external := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+
+	january := types.NewMonth(2024, 1)
+	february := january.AddDate(0, 1)
+	march := january.AddDate(0, 2)
+
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: january, Allocation: decimal.NewFromFloat(100)})
+	transaction := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      account.ID,
+		DestinationAccountID: external.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(30),
+		Date:                 time.Time(february),
+	})
+
+	balance := func(month types.Month, expected float64) {
+		b, err := envelope.Balance(models.DB, month)
+		suite.Require().Nil(err)
+		suite.Assert().True(b.Equal(decimal.NewFromFloat(expected)), "Balance for %s is %s, expected %f", month, b, expected)
+	}
+
+	hits := func() float64 {
+		return testutil.ToFloat64(models.EnvelopeBalanceCacheLookups.WithLabelValues(models.EnvelopeBalanceCacheHit))
+	}
+
+	partials := func() float64 {
+		return testutil.ToFloat64(models.EnvelopeBalanceCacheLookups.WithLabelValues(models.EnvelopeBalanceCachePartial))
+	}
+
+	// The first calculation creates a snapshot
+	balance(january, 100)
+	suite.Assert().Equal([]types.Month{january}, suite.snapshotMonths(envelope))
+
+	// The second one uses it
+	h := hits()
+	balance(january, 100)
+	suite.Assert().Equal(h+1, hits())
+
+	// Later months start from the snapshot
+	p := partials()
+	balance(march, 70)
+	suite.Assert().Equal(p+1, partials())
+	suite.Assert().Equal([]types.Month{january, march}, suite.snapshotMonths(envelope))
+
+	// Changing the transaction invalidates the snapshots from its month on
+	err := models.DB.Model(&transaction).Select("Amount").Updates(models.Transaction{Amount: decimal.NewFromFloat(40)}).Error
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]types.Month{january}, suite.snapshotMonths(envelope))
+	balance(march, 60)
+
+	// Moving the transaction to an earlier month invalidates the snapshots from the earlier month on
+	err = models.DB.Model(&transaction).Select("Date").Updates(models.Transaction{Date: time.Time(january)}).Error
+	suite.Require().Nil(err)
+	suite.Assert().Empty(suite.snapshotMonths(envelope))
+	balance(january, 60)
+	balance(march, 60)
+
+	// Allocations invalidate the snapshots from their month on
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: february, Allocation: decimal.NewFromFloat(15)})
+	suite.Assert().Equal([]types.Month{january}, suite.snapshotMonths(envelope))
+	balance(march, 75)
+
+	err = models.DB.Delete(&models.MonthConfig{EnvelopeID: envelope.ID, Month: february}).Error
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]types.Month{january}, suite.snapshotMonths(envelope))
+	balance(march, 60)
+
+	// Deleting the transaction invalidates its month
+	err = models.DB.Delete(&transaction).Error
+	suite.Require().Nil(err)
+	suite.Assert().Empty(suite.snapshotMonths(envelope))
+	balance(march, 100)
+}
+
+func (suite *TestSuiteStandard) TestEnvelopeBalanceSnapshotsOnBudget() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: false})
+	external := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+
+	january := types.NewMonth(2024, 1)
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      account.ID,
+		DestinationAccountID: external.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(30),
+		Date:                 time.Time(january),
+	})
+
+	b, err := envelope.Balance(models.DB, january)
+	suite.Require().Nil(err)
+	suite.Assert().True(b.Equal(decimal.NewFromFloat(30)), "Balance is %s", b)
+
+	// Setting the account on budget makes the transaction outgoing
+	err = models.DB.Model(&account).Select("OnBudget").Updates(models.Account{OnBudget: true}).Error
+	suite.Require().Nil(err)
+	suite.Assert().Empty(suite.snapshotMonths(envelope))
+
+	b, err = envelope.Balance(models.DB, january)
+	suite.Require().Nil(err)
+	suite.Assert().True(b.Equal(decimal.NewFromFloat(-30)), "Balance is %s", b)
+}
+
+func (suite *TestSuiteStandard) TestEnvelopeBalanceSnapshotsEnvelopeDelete() {
+	budget := suite.createTestBudget(models.Budget{})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: types.NewMonth(2024, 1), Allocation: decimal.NewFromFloat(10)})
+	_, err := envelope.Balance(models.DB, types.NewMonth(2024, 3))
+	suite.Require().Nil(err)
+	suite.Require().NotEmpty(suite.snapshotMonths(envelope))
+
+	err = models.DB.Where(&models.MonthConfig{EnvelopeID: envelope.ID}).Delete(&models.MonthConfig{}).Error
+	suite.Require().Nil(err)
+
+	err = models.DB.Delete(&envelope).Error
+	suite.Require().Nil(err)
+	suite.Assert().Empty(suite.snapshotMonths(envelope))
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// EnvelopeHistoryType is the kind of an entry in the history of an envelope.
+type EnvelopeHistoryType string
+
+const (
+	EnvelopeHistoryRollover    EnvelopeHistoryType = "ROLLOVER"    // The balance at the end of the previous month is carried over
+	EnvelopeHistoryReset       EnvelopeHistoryType = "RESET"       // The negative balance at the end of the previous month is not carried over
+	EnvelopeHistoryAllocation  EnvelopeHistoryType = "ALLOCATION"  // Money is allocated to the envelope
+	EnvelopeHistoryTransaction EnvelopeHistoryType = "TRANSACTION" // A transaction for the envelope
+)
+
+// EnvelopeHistoryEntry is a change to the balance of an envelope.
+type EnvelopeHistoryEntry struct {
+	Type          EnvelopeHistoryType
+	Date          time.Time
+	TransactionID *uuid.UUID      // ID of the transaction for transaction entries
+	Note          string          // Note of the transaction or month config
+	Amount        decimal.Decimal // Change of the balance in the currency of the budget. For rollovers, the amount carried over
+	Balance       decimal.Decimal // Balance after the change
+}
+
+// historyTransaction is an AggregatedTransaction with the fields needed to identify it.
+type historyTransaction struct {
+	AggregatedTransaction
+	ID   uuid.UUID
+	Note string
+}
+
+// History returns all changes to the balance of the envelope from the first to the
+// last month, inclusive, in chronological order.
+//
+// Every month starts with the rollover of the balance of the previous month. Negative
+// balances are not rolled over, which is recorded as a reset. The allocation for the
+// month follows, then the transactions. The balance after the last entry of a month is
+// the balance returned by Balance.
+func (e Envelope) History(db *gorm.DB, from, until types.Month) (entries []EnvelopeHistoryEntry, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		balance, err := e.Balance(tx, from.AddDate(0, -1))
+		if err != nil {
+			return err
+		}
+
+		var transactions []historyTransaction
+		err = aggregatedTransactions(tx).
+			Select(aggregatedTransactionColumns+", transactions.id AS ID, transactions.note AS Note").
+			Where("transactions.envelope_id = ?", e.ID).
+			Where("transactions.date >= date(?)", from).
+			Where("transactions.date < date(?)", until.AddDate(0, 1)).
+			Order("transactions.date ASC, transactions.created_at ASC").
+			Find(&transactions).Error
+		if err != nil {
+			return err
+		}
+
+		var monthConfigs []MonthConfig
+		err = tx.
+			Where("month_configs.envelope_id = ?", e.ID).
+			Where("month_configs.month >= date(?)", from).
+			Where("month_configs.month < date(?)", until.AddDate(0, 1)).
+			Find(&monthConfigs).Error
+		if err != nil {
+			return err
+		}
+
+		aggregated := make([]AggregatedTransaction, 0, len(transactions))
+		for _, t := range transactions {
+			aggregated = append(aggregated, t.AggregatedTransaction)
+		}
+
+		rates, err := e.exchangeRates(tx, aggregated)
+		if err != nil {
+			return err
+		}
+
+		configs := make(map[types.Month]MonthConfig, len(monthConfigs))
+		for _, m := range monthConfigs {
+			configs[m.Month] = m
+		}
+
+		entries = make([]EnvelopeHistoryEntry, 0)
+		i := 0
+		for month := from; !month.After(until); month = month.AddDate(0, 1) {
+			start := time.Time(month)
+
+			// Overspending is only shown in the month it happens in
+			if balance.IsNegative() {
+				entries = append(entries, EnvelopeHistoryEntry{Type: EnvelopeHistoryReset, Date: start, Amount: balance.Neg(), Balance: decimal.Zero})
+				balance = decimal.Zero
+			} else if balance.IsPositive() {
+				entries = append(entries, EnvelopeHistoryEntry{Type: EnvelopeHistoryRollover, Date: start, Amount: balance, Balance: balance})
+			}
+
+			if m, ok := configs[month]; ok {
+				balance = balance.Add(m.Allocation)
+				entries = append(entries, EnvelopeHistoryEntry{Type: EnvelopeHistoryAllocation, Date: start, Note: m.Note, Amount: m.Allocation, Balance: balance})
+			}
+
+			next := time.Time(month.AddDate(0, 1))
+			for ; i < len(transactions) && transactions[i].Date.Before(next); i++ {
+				t := transactions[i]
+				amount, err := t.budgetAmount(rates)
+				if err != nil {
+					return err
+				}
+
+				// Outgoing transactions reduce the balance, incoming ones increase it
+				if t.SourceAccountOnBudget {
+					amount = amount.Neg()
+				}
+
+				balance = balance.Add(amount)
+				entries = append(entries, EnvelopeHistoryEntry{Type: EnvelopeHistoryTransaction, Date: t.Date, TransactionID: &t.ID, Note: t.Note, Amount: amount, Balance: balance})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,74 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) TestEnvelopeHistory() {
+	budget := suite.createTestBudget(models.Budget{})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: suite.createTestCategory(models.Category{BudgetID: budget.ID}).ID})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+
+	january := types.NewMonth(2024, time.January)
+	february := types.NewMonth(2024, time.February)
+	march := types.NewMonth(2024, time.March)
+
+	// December is before the history and only shows up in the rollover
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: january.AddDate(0, -1), Allocation: decimal.NewFromFloat(10)})
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: january, Allocation: decimal.NewFromFloat(50), Note: "Monthly budget"})
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: march, Allocation: decimal.NewFromFloat(30)})
+
+	shopping := suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: shop.ID, EnvelopeID: &envelope.ID, Amount: decimal.NewFromFloat(80), Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Note: "Big shopping"})
+	refund := suite.createTestTransaction(models.Transaction{SourceAccountID: shop.ID, DestinationAccountID: account.ID, EnvelopeID: &envelope.ID, Amount: decimal.NewFromFloat(5), Date: time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC)})
+
+	entries, err := envelope.History(models.DB, january, march)
+	suite.Require().Nil(err)
+
+	expected := []struct {
+		entryType models.EnvelopeHistoryType
+		date      time.Time
+		amount    float64
+		balance   float64
+	}{
+		{models.EnvelopeHistoryRollover, time.Time(january), 10, 10},
+		{models.EnvelopeHistoryAllocation, time.Time(january), 50, 60},
+		{models.EnvelopeHistoryTransaction, shopping.Date, -80, -20},
+		{models.EnvelopeHistoryReset, time.Time(february), 20, 0},
+		{models.EnvelopeHistoryTransaction, refund.Date, 5, 5},
+		{models.EnvelopeHistoryRollover, time.Time(march), 5, 5},
+		{models.EnvelopeHistoryAllocation, time.Time(march), 30, 35},
+	}
+
+	suite.Require().Len(entries, len(expected))
+	for i, e := range expected {
+		suite.Assert().Equal(e.entryType, entries[i].Type, "Entry %d", i)
+		suite.Assert().True(e.date.Equal(entries[i].Date), "Entry %d has date %s, expected %s", i, entries[i].Date, e.date)
+		suite.Assert().True(decimal.NewFromFloat(e.amount).Equal(entries[i].Amount), "Entry %d has amount %s, expected %f", i, entries[i].Amount, e.amount)
+		suite.Assert().True(decimal.NewFromFloat(e.balance).Equal(entries[i].Balance), "Entry %d has balance %s, expected %f", i, entries[i].Balance, e.balance)
+	}
+
+	suite.Assert().Equal("Monthly budget", entries[1].Note)
+	suite.Assert().Equal("Big shopping", entries[2].Note)
+	suite.Require().NotNil(entries[2].TransactionID)
+	suite.Assert().Equal(shopping.ID, *entries[2].TransactionID)
+
+	// The running balance matches the balance of the envelope
+	for _, month := range []types.Month{january, february, march} {
+		balance, err := envelope.Balance(models.DB, month)
+		suite.Require().Nil(err)
+
+		var last models.EnvelopeHistoryEntry
+		for _, entry := range entries {
+			if !entry.Date.Before(time.Time(month.AddDate(0, 1))) {
+				break
+			}
+			last = entry
+		}
+		suite.Assert().True(balance.Equal(last.Balance), "Balance for %s is %s, history has %s", month, balance, last.Balance)
+	}
+}
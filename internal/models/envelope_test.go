@@ -9,6 +9,7 @@ import (
 
 	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -271,7 +272,7 @@ func (suite *TestSuiteStandard) TestEnvelopeExport() {
 		_ = suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: fmt.Sprint(i)})
 	}
 
-	raw, err := models.Envelope{}.Export()
+	raw, err := models.Envelope{}.Export(nil)
 	if err != nil {
 		require.Fail(t, "envelope export failed", err)
 	}
@@ -284,3 +285,67 @@ func (suite *TestSuiteStandard) TestEnvelopeExport() {
 
 	require.Len(t, envelopes, 2, "Number of envelopes in export is wrong")
 }
+
+func (suite *TestSuiteStandard) TestEnvelopeMerge() {
+	budget := suite.createTestBudget(models.Budget{})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Groceries"})
+	duplicate := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Food"})
+
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	payee := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, DefaultEnvelopeID: &duplicate.ID})
+
+	january := types.NewMonth(2024, time.January)
+	february := types.NewMonth(2024, time.February)
+
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: payee.ID, EnvelopeID: &duplicate.ID, Amount: decimal.NewFromFloat(30), Date: time.Time(january)})
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: january, Allocation: decimal.NewFromFloat(100), Note: "Weekly shopping"})
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: duplicate.ID, Month: january, Allocation: decimal.NewFromFloat(50), Note: "Restaurants"})
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: duplicate.ID, Month: february, Allocation: decimal.NewFromFloat(20)})
+	goal := suite.createTestGoal(models.Goal{EnvelopeID: duplicate.ID, Name: "Party", Amount: decimal.NewFromFloat(200)})
+
+	// Balances are cached before the merge
+	balance, err := envelope.Balance(models.DB, february)
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(100).Equal(balance), "Balance is %s", balance)
+
+	suite.Assert().ErrorIs(envelope.Merge(models.DB, []uuid.UUID{envelope.ID}), models.ErrEnvelopeMergeSelf)
+	suite.Assert().ErrorIs(envelope.Merge(models.DB, []uuid.UUID{uuid.New()}), models.ErrResourceNotFound)
+
+	other := suite.createTestEnvelope(models.Envelope{CategoryID: suite.createTestCategory(models.Category{BudgetID: suite.createTestBudget(models.Budget{}).ID}).ID})
+	suite.Assert().ErrorIs(envelope.Merge(models.DB, []uuid.UUID{other.ID}), models.ErrEnvelopeMergeBudget)
+
+	// Goals with the same name can not be merged
+	conflict := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Conflict"})
+	_ = suite.createTestGoal(models.Goal{EnvelopeID: envelope.ID, Name: "Holiday", Amount: decimal.NewFromFloat(200)})
+	_ = suite.createTestGoal(models.Goal{EnvelopeID: conflict.ID, Name: "Holiday", Amount: decimal.NewFromFloat(200)})
+	suite.Assert().ErrorIs(envelope.Merge(models.DB, []uuid.UUID{duplicate.ID, conflict.ID}), models.ErrGoalNameNotUnique)
+	suite.Require().Nil(models.DB.First(&models.Envelope{}, duplicate.ID).Error)
+
+	suite.Require().Nil(envelope.Merge(models.DB, []uuid.UUID{duplicate.ID}))
+
+	suite.Require().Nil(models.DB.First(&transaction, transaction.ID).Error)
+	suite.Assert().Equal(envelope.ID, *transaction.EnvelopeID)
+
+	suite.Require().Nil(models.DB.First(&goal, goal.ID).Error)
+	suite.Assert().Equal(envelope.ID, goal.EnvelopeID)
+
+	var updated models.Account
+	suite.Require().Nil(models.DB.First(&updated, payee.ID).Error)
+	suite.Assert().Equal(envelope.ID, *updated.DefaultEnvelopeID)
+
+	var monthConfigs []models.MonthConfig
+	suite.Require().Nil(models.DB.Where(&models.MonthConfig{EnvelopeID: envelope.ID}).Order("month ASC").Find(&monthConfigs).Error)
+	suite.Require().Len(monthConfigs, 2)
+	suite.Assert().True(decimal.NewFromFloat(150).Equal(monthConfigs[0].Allocation), "Allocation is %s", monthConfigs[0].Allocation)
+	suite.Assert().Equal("Weekly shopping\nRestaurants", monthConfigs[0].Note)
+	suite.Assert().True(decimal.NewFromFloat(20).Equal(monthConfigs[1].Allocation), "Allocation is %s", monthConfigs[1].Allocation)
+
+	// 100 + 50 + 20 allocated, 30 spent
+	balance, err = envelope.Balance(models.DB, february)
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(140).Equal(balance), "Balance is %s", balance)
+
+	err = models.DB.First(&models.Envelope{}, duplicate.ID).Error
+	suite.Assert().ErrorIs(err, models.ErrResourceNotFound)
+}
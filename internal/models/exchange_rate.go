@@ -0,0 +1,273 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ExchangeRate is the value of one unit of a currency in the currency of the budget
+// at a specific date.
+type ExchangeRate struct {
+	DefaultModel
+	Budget   Budget          `json:"-"`
+	BudgetID uuid.UUID       `gorm:"uniqueIndex:exchange_rate_budget_currency_date"`
+	Currency string          `gorm:"uniqueIndex:exchange_rate_budget_currency_date"` // ISO 4217 code of the currency
+	Date     time.Time       `gorm:"uniqueIndex:exchange_rate_budget_currency_date"` // Date from which on the rate is valid. The time of day is always midnight UTC
+	Rate     decimal.Decimal `gorm:"type:DECIMAL(20,8)"`                             // Value of one unit of the currency in the currency of the budget
+}
+
+var (
+	ErrCurrencyInvalid         = errors.New("the currency must be a three letter ISO 4217 currency code")
+	ErrExchangeRateNotPositive = errors.New("the exchange rate must be positive")
+	ErrExchangeRateNotUnique   = errors.New("there is already an exchange rate for this currency and date in the budget")
+	ErrExchangeRateNotFound    = errors.New("there is no exchange rate for the currency")
+	ErrExchangeRateDateTime    = errors.New("the date of an exchange rate must be at midnight UTC")
+)
+
+var currencyRegexp = regexp.MustCompile("^[A-Z]{3}$")
+
+// normalizeCurrency returns the currency code in upper case without surrounding whitespace
+func normalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// validCurrency checks if the currency is a valid ISO 4217 code
+func validCurrency(currency string) bool {
+	return currencyRegexp.MatchString(currency)
+}
+
+// startOfDay returns midnight UTC of the day of the time
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// BeforeCreate normalizes the currency code and the date and
+// verifies the exchange rate.
+func (e *ExchangeRate) BeforeCreate(tx *gorm.DB) error {
+	_ = e.DefaultModel.BeforeCreate(tx)
+
+	toSave := tx.Statement.Dest.(*ExchangeRate)
+	toSave.Currency = normalizeCurrency(toSave.Currency)
+	toSave.Date = startOfDay(toSave.Date)
+	if !validCurrency(toSave.Currency) {
+		return ErrCurrencyInvalid
+	}
+
+	if !toSave.Rate.IsPositive() {
+		return ErrExchangeRateNotPositive
+	}
+
+	return e.checkIntegrity(tx, *toSave)
+}
+
+// BeforeUpdate verifies the state of the exchange rate before
+// committing an update to the database.
+//
+// Other than on creation, the currency code and the date are not normalized
+// and must already be valid.
+func (e *ExchangeRate) BeforeUpdate(tx *gorm.DB) error {
+	toSave := tx.Statement.Dest.(ExchangeRate)
+
+	if tx.Statement.Changed("Currency") && !validCurrency(toSave.Currency) {
+		return ErrCurrencyInvalid
+	}
+
+	if tx.Statement.Changed("Date") && !toSave.Date.Equal(startOfDay(toSave.Date)) {
+		return ErrExchangeRateDateTime
+	}
+
+	if tx.Statement.Changed("Rate") && !toSave.Rate.IsPositive() {
+		return ErrExchangeRateNotPositive
+	}
+
+	if tx.Statement.Changed("BudgetID") {
+		err := e.checkIntegrity(tx, toSave)
+		if err != nil {
+			return err
+		}
+
+		err = invalidateBudgetEnvelopeBalances(tx, toSave.BudgetID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return invalidateBudgetEnvelopeBalances(tx, e.BudgetID)
+}
+
+// AfterCreate invalidates the balance snapshots of all envelopes of the budget.
+func (e *ExchangeRate) AfterCreate(tx *gorm.DB) error {
+	return invalidateBudgetEnvelopeBalances(tx, e.BudgetID)
+}
+
+// AfterDelete invalidates the balance snapshots of all envelopes of the budget.
+func (e *ExchangeRate) AfterDelete(tx *gorm.DB) error {
+	return invalidateBudgetEnvelopeBalances(tx, e.BudgetID)
+}
+
+// checkIntegrity verifies references to other resources
+func (e *ExchangeRate) checkIntegrity(tx *gorm.DB, toSave ExchangeRate) error {
+	return tx.First(&Budget{}, toSave.BudgetID).Error
+}
+
+// Returns all exchange rates for export. If budgetID is set, only exchange rates of that budget are returned.
+func (ExchangeRate) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[ExchangeRate](exchangeRateBudgetScope(budgetID))
+}
+
+// Table returns all exchange rates for export as a table.
+func (ExchangeRate) Table(budgetID *uuid.UUID) (Table, error) {
+	var rates []ExchangeRate
+	err := DB.Scopes(exchangeRateBudgetScope(budgetID)).Preload("Budget").Order("exchange_rates.currency ASC, exchange_rates.date ASC").Find(&rates).Error
+	if err != nil {
+		return Table{}, err
+	}
+
+	table := Table{
+		Name:   "Exchange Rates",
+		Header: []string{"ID", "Budget", "Currency", "Date", "Rate"},
+	}
+
+	for _, r := range rates {
+		table.Rows = append(table.Rows, []any{r.ID.String(), r.Budget.Name, r.Currency, r.Date, r.Rate})
+	}
+
+	return table, nil
+}
+
+func exchangeRateBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "exchange_rates.budget_id")
+}
+
+// ExchangeRates converts amounts to the currency of a budget.
+type ExchangeRates struct {
+	currency string                    // Currency of the budget
+	rates    map[string][]ExchangeRate // Rates by currency, earliest first
+}
+
+// LoadExchangeRates loads all exchange rates of the budget.
+func LoadExchangeRates(db *gorm.DB, budgetID uuid.UUID) (ExchangeRates, error) {
+	var budget Budget
+	err := db.First(&budget, budgetID).Error
+	if err != nil {
+		return ExchangeRates{}, err
+	}
+
+	var rates []ExchangeRate
+	err = db.Where(&ExchangeRate{BudgetID: budgetID}).Find(&rates).Error
+	if err != nil {
+		return ExchangeRates{}, err
+	}
+
+	sort.SliceStable(rates, func(i, j int) bool {
+		return rates[i].Date.Before(rates[j].Date)
+	})
+
+	e := ExchangeRates{
+		currency: normalizeCurrency(budget.Currency),
+		rates:    make(map[string][]ExchangeRate),
+	}
+
+	for _, r := range rates {
+		e.rates[r.Currency] = append(e.rates[r.Currency], r)
+	}
+
+	return e, nil
+}
+
+// sameCurrency returns true if amounts in the currencies a and b do not need to be
+// converted into each other.
+//
+// An empty currency is the currency of the budget.
+func sameCurrency(budgetCurrency, a, b string) bool {
+	budgetCurrency = normalizeCurrency(budgetCurrency)
+	if a == "" {
+		a = budgetCurrency
+	}
+
+	if b == "" {
+		b = budgetCurrency
+	}
+
+	return a == b
+}
+
+// Convert converts an amount in a currency to the currency of the budget.
+//
+// The latest rate on or before the date is used. If there is none, the earliest
+// rate after the date is used.
+func (e ExchangeRates) Convert(amount decimal.Decimal, currency string, date time.Time) (decimal.Decimal, error) {
+	if sameCurrency(e.currency, currency, "") {
+		return amount, nil
+	}
+
+	rates := e.rates[currency]
+	if len(rates) == 0 {
+		return decimal.Zero, fmt.Errorf("%w %s", ErrExchangeRateNotFound, currency)
+	}
+
+	rate := rates[0].Rate
+	for _, r := range rates {
+		if r.Date.After(date) {
+			break
+		}
+		rate = r.Rate
+	}
+
+	return amount.Mul(rate), nil
+}
+
+// ImportExchangeRates creates the exchange rates. If a budget already has a rate for the
+// currency and date, its rate is updated instead.
+//
+// All rates are imported in one database transaction. If any of them fails, nothing is imported.
+func ImportExchangeRates(db *gorm.DB, rates []ExchangeRate) ([]ExchangeRate, error) {
+	imported := make([]ExchangeRate, 0, len(rates))
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, r := range rates {
+			var existing []ExchangeRate
+			err := tx.Where(&ExchangeRate{
+				BudgetID: r.BudgetID,
+				Currency: normalizeCurrency(r.Currency),
+				Date:     startOfDay(r.Date),
+			}).Limit(1).Find(&existing).Error
+			if err != nil {
+				return err
+			}
+
+			if len(existing) == 0 {
+				err = tx.Create(&r).Error
+				if err != nil {
+					return err
+				}
+
+				imported = append(imported, r)
+				continue
+			}
+
+			rate := existing[0]
+			err = tx.Model(&rate).Select("Rate").Updates(ExchangeRate{Rate: r.Rate}).Error
+			if err != nil {
+				return err
+			}
+
+			imported = append(imported, rate)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return imported, nil
+}
@@ -0,0 +1,227 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) createTestExchangeRate(rate models.ExchangeRate) models.ExchangeRate {
+	err := models.DB.Create(&rate).Error
+	if err != nil {
+		suite.Assert().FailNowf("Exchange rate could not be saved", "Error: %s, Exchange rate: %#v", err, rate)
+	}
+
+	return rate
+}
+
+func (suite *TestSuiteStandard) TestExchangeRateConvert() {
+	budget := suite.createTestBudget(models.Budget{Currency: "eur"})
+
+	_ = suite.createTestExchangeRate(models.ExchangeRate{BudgetID: budget.ID, Currency: "USD", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Rate: decimal.NewFromFloat(0.9)})
+	_ = suite.createTestExchangeRate(models.ExchangeRate{BudgetID: budget.ID, Currency: "USD", Date: time.Date(2024, 2, 1, 13, 0, 0, 0, time.UTC), Rate: decimal.NewFromFloat(0.8)})
+
+	rates, err := models.LoadExchangeRates(models.DB, budget.ID)
+	suite.Require().Nil(err)
+
+	tests := []struct {
+		name     string
+		currency string
+		date     time.Time
+		expected float64
+	}{
+		{"Budget currency", "", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 100},
+		{"Budget currency explicitly", "EUR", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 100},
+		{"Before first rate", "USD", time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), 90},
+		{"First rate", "USD", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 90},
+		{"Second rate on its date", "USD", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 80},
+		{"After second rate", "USD", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 80},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			converted, err := rates.Convert(decimal.NewFromFloat(100), tt.currency, tt.date)
+			suite.Require().Nil(err)
+			suite.Assert().True(decimal.NewFromFloat(tt.expected).Equal(converted), "Converted amount is %s", converted)
+		})
+	}
+
+	_, err = rates.Convert(decimal.NewFromFloat(100), "GBP", time.Now())
+	suite.Assert().ErrorIs(err, models.ErrExchangeRateNotFound)
+}
+
+func (suite *TestSuiteStandard) TestExchangeRateValidation() {
+	budget := suite.createTestBudget(models.Budget{Currency: "EUR"})
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	err := models.DB.Create(&models.ExchangeRate{BudgetID: budget.ID, Currency: "US Dollar", Date: date, Rate: decimal.NewFromFloat(1.1)}).Error
+	suite.Assert().ErrorIs(err, models.ErrCurrencyInvalid)
+
+	err = models.DB.Create(&models.ExchangeRate{BudgetID: budget.ID, Currency: "USD", Date: date}).Error
+	suite.Assert().ErrorIs(err, models.ErrExchangeRateNotPositive)
+
+	rate := suite.createTestExchangeRate(models.ExchangeRate{BudgetID: budget.ID, Currency: " usd ", Date: date.Add(14 * time.Hour), Rate: decimal.NewFromFloat(1.1)})
+	suite.Assert().Equal("USD", rate.Currency)
+	suite.Assert().Equal(date, rate.Date)
+
+	err = models.DB.Create(&models.ExchangeRate{BudgetID: budget.ID, Currency: "USD", Date: date, Rate: decimal.NewFromFloat(1.2)}).Error
+	suite.Assert().ErrorIs(err, models.ErrExchangeRateNotUnique)
+
+	err = models.DB.Model(&rate).Select("Currency").Updates(models.ExchangeRate{Currency: "gbp"}).Error
+	suite.Assert().ErrorIs(err, models.ErrCurrencyInvalid)
+
+	err = models.DB.Model(&rate).Select("Date").Updates(models.ExchangeRate{Date: date.Add(time.Hour)}).Error
+	suite.Assert().ErrorIs(err, models.ErrExchangeRateDateTime)
+
+	err = models.DB.Model(&rate).Select("Rate").Updates(models.ExchangeRate{Rate: decimal.NewFromFloat(-1)}).Error
+	suite.Assert().ErrorIs(err, models.ErrExchangeRateNotPositive)
+}
+
+func (suite *TestSuiteStandard) TestExchangeRateImport() {
+	budget := suite.createTestBudget(models.Budget{Currency: "EUR"})
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	existing := suite.createTestExchangeRate(models.ExchangeRate{BudgetID: budget.ID, Currency: "USD", Date: date, Rate: decimal.NewFromFloat(1.1)})
+
+	imported, err := models.ImportExchangeRates(models.DB, []models.ExchangeRate{
+		{BudgetID: budget.ID, Currency: "usd", Date: date, Rate: decimal.NewFromFloat(1.2)},
+		{BudgetID: budget.ID, Currency: "GBP", Date: date, Rate: decimal.NewFromFloat(1.15)},
+	})
+	suite.Require().Nil(err)
+	suite.Require().Len(imported, 2)
+	suite.Assert().Equal(existing.ID, imported[0].ID, "Existing rates must be updated")
+	suite.Assert().True(decimal.NewFromFloat(1.2).Equal(imported[0].Rate))
+
+	_, err = models.ImportExchangeRates(models.DB, []models.ExchangeRate{
+		{BudgetID: budget.ID, Currency: "CHF", Date: date, Rate: decimal.NewFromFloat(1.05)},
+		{BudgetID: budget.ID, Currency: "JPY", Date: date},
+	})
+	suite.Assert().ErrorIs(err, models.ErrExchangeRateNotPositive)
+
+	var count int64
+	suite.Require().Nil(models.DB.Model(&models.ExchangeRate{}).Where(&models.ExchangeRate{BudgetID: budget.ID, Currency: "CHF"}).Count(&count).Error)
+	suite.Assert().Equal(int64(0), count, "No rate must be imported when one of them fails")
+}
+
+func (suite *TestSuiteStandard) TestAccountCurrencyInvalid() {
+	budget := suite.createTestBudget(models.Budget{})
+
+	err := models.DB.Create(&models.Account{BudgetID: budget.ID, Currency: "Euro"}).Error
+	suite.Assert().ErrorIs(err, models.ErrAccountCurrencyInvalid)
+
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, Currency: "chf"})
+	suite.Assert().Equal("CHF", account.Currency)
+
+	err = models.DB.Model(&account).Select("Currency").Updates(models.Account{Currency: "1234"}).Error
+	suite.Assert().ErrorIs(err, models.ErrAccountCurrencyInvalid)
+}
+
+func (suite *TestSuiteStandard) TestTransactionDestinationAmount() {
+	budget := suite.createTestBudget(models.Budget{Currency: "EUR"})
+	euro := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Euro", OnBudget: true})
+	dollar := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Dollar", OnBudget: true, Currency: "USD"})
+	alsoEuro := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Also Euro", OnBudget: true, Currency: "EUR"})
+
+	err := models.DB.Create(&models.Transaction{SourceAccountID: euro.ID, DestinationAccountID: dollar.ID, Amount: decimal.NewFromFloat(100)}).Error
+	suite.Assert().ErrorIs(err, models.ErrTransactionDestinationAmountMissing)
+
+	err = models.DB.Create(&models.Transaction{SourceAccountID: euro.ID, DestinationAccountID: alsoEuro.ID, Amount: decimal.NewFromFloat(100), DestinationAmount: decimal.NewFromFloat(100)}).Error
+	suite.Assert().ErrorIs(err, models.ErrTransactionDestinationAmountSameCurrency)
+
+	transfer := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      euro.ID,
+		DestinationAccountID: dollar.ID,
+		Amount:               decimal.NewFromFloat(100),
+		DestinationAmount:    decimal.NewFromFloat(110),
+		Date:                 time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+	})
+
+	balance, err := dollar.Balance(models.DB, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(110).Equal(balance), "Balance is %s", balance)
+
+	balance, err = euro.Balance(models.DB, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(-100).Equal(balance), "Balance is %s", balance)
+
+	err = models.DB.Model(&transfer).Select("DestinationAmount").Updates(models.Transaction{}).Error
+	suite.Assert().ErrorIs(err, models.ErrTransactionDestinationAmountMissing)
+}
+
+func (suite *TestSuiteStandard) TestBudgetCurrencyConversion() {
+	budget := suite.createTestBudget(models.Budget{Currency: "EUR"})
+	dollar := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Dollar", OnBudget: true, Currency: "USD"})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true, Currency: "USD"})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+	january := types.NewMonth(2024, 1)
+
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      dollar.ID,
+		DestinationAccountID: shop.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(100),
+		Date:                 time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+	})
+
+	// Without exchange rates, amounts can not be converted
+	_, err := envelope.Balance(models.DB, january)
+	suite.Assert().ErrorIs(err, models.ErrExchangeRateNotFound)
+
+	_ = suite.createTestExchangeRate(models.ExchangeRate{BudgetID: budget.ID, Currency: "USD", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Rate: decimal.NewFromFloat(0.9)})
+
+	balance, err := envelope.Balance(models.DB, january)
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(-90).Equal(balance), "Envelope balance is %s", balance)
+
+	spent, err := envelope.Spent(models.DB, january)
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(-90).Equal(spent), "Spent is %s", spent)
+
+	budgetBalance, err := budget.Balance(models.DB)
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(-90).Equal(budgetBalance), "Budget balance is %s", budgetBalance)
+
+	// A new rate invalidates the cached envelope balances
+	_ = suite.createTestExchangeRate(models.ExchangeRate{BudgetID: budget.ID, Currency: "USD", Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Rate: decimal.NewFromFloat(0.5)})
+
+	balance, err = envelope.Balance(models.DB, january)
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(-50).Equal(balance), "Envelope balance is %s", balance)
+
+	// The budget balance uses the latest rate
+	budgetBalance, err = budget.Balance(models.DB)
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(-50).Equal(budgetBalance), "Budget balance is %s", budgetBalance)
+}
+
+func (suite *TestSuiteStandard) TestBudgetCurrencyChange() {
+	budget := suite.createTestBudget(models.Budget{Currency: "EUR"})
+	dollar := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Dollar", OnBudget: true, Currency: "USD"})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true, Currency: "USD"})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+	january := types.NewMonth(2024, 1)
+
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      dollar.ID,
+		DestinationAccountID: shop.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(10),
+		Date:                 time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+	})
+	_ = suite.createTestExchangeRate(models.ExchangeRate{BudgetID: budget.ID, Currency: "USD", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Rate: decimal.NewFromFloat(2)})
+
+	balance, err := envelope.Balance(models.DB, january)
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(-20).Equal(balance), "Envelope balance is %s", balance)
+
+	// With the budget in the currency of the account, amounts are not converted anymore
+	err = models.DB.Model(&budget).Select("Currency").Updates(models.Budget{Currency: "USD"}).Error
+	suite.Require().Nil(err)
+
+	balance, err = envelope.Balance(models.DB, january)
+	suite.Require().Nil(err)
+	suite.Assert().True(decimal.NewFromFloat(-10).Equal(balance), "Envelope balance is %s", balance)
+}
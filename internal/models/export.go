@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Table is a representation of all instances of a model with human-readable
+// columns, e.g. names of referenced resources instead of their IDs.
+//
+// Cells are string, bool, uint, decimal.Decimal, time.Time, *time.Time or types.Month.
+type Table struct {
+	Name   string   // Name of the table
+	Header []string // Column names
+	Rows   [][]any  // One row per instance
+}
+
+// budgetScope returns a scope that limits queries to resources of a budget.
+//
+// column is the column holding the budget ID, joins are the JOIN clauses needed
+// to reach it. If budgetID is nil, the query is not limited.
+func budgetScope(budgetID *uuid.UUID, column string, joins ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if budgetID == nil {
+			return db
+		}
+
+		for _, join := range joins {
+			db = db.Joins(join)
+		}
+
+		return db.Where(column+" = ?", budgetID)
+	}
+}
+
+// export returns all instances of a model within the scope as JSON.
+func export[T any](scope func(*gorm.DB) *gorm.DB) (json.RawMessage, error) {
+	var resources []T
+	err := DB.Scopes(scope).Find(&resources).Error
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := json.Marshal(&resources)
+	if err != nil {
+		return json.RawMessage{}, err
+	}
+	return json.RawMessage(j), nil
+}
@@ -0,0 +1,41 @@
+package models_test
+
+import (
+	"strings"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) TestTablesBudget() {
+	budget := suite.createTestBudget(models.Budget{})
+	other := suite.createTestBudget(models.Budget{})
+
+	for _, b := range []models.Budget{budget, other} {
+		account := suite.createTestAccount(models.Account{BudgetID: b.ID})
+		external := suite.createTestAccount(models.Account{BudgetID: b.ID, External: true})
+		_ = suite.createTestMatchRule(models.MatchRule{AccountID: account.ID, Match: "Match"})
+		category := suite.createTestCategory(models.Category{BudgetID: b.ID})
+		envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+		_ = suite.createTestGoal(models.Goal{EnvelopeID: envelope.ID, Amount: decimal.NewFromFloat(10)})
+		_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID})
+		transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: external.ID, Amount: decimal.NewFromFloat(10)})
+		tag := suite.createTestTag(models.Tag{BudgetID: b.ID, Name: "Tag"})
+		suite.Require().Nil(models.SetTransactionTags(models.DB, transaction.ID, []uuid.UUID{tag.ID}))
+		suite.Require().Nil(models.CreateAttachment(models.DB, &models.Attachment{TransactionID: transaction.ID, FileName: "receipt.pdf"}, strings.NewReader("%PDF")))
+		suite.Require().Nil(models.DB.Create(&models.Reconciliation{AccountID: account.ID}).Error)
+		suite.Require().Nil(models.DB.Create(&models.ExchangeRate{BudgetID: b.ID, Currency: "USD", Rate: decimal.NewFromFloat(0.9)}).Error)
+	}
+
+	for _, model := range models.Registry {
+		budgetTable, err := model.Table(&budget.ID)
+		suite.Require().Nil(err)
+		suite.Assert().NotEmpty(budgetTable.Name)
+		suite.Assert().NotEmpty(budgetTable.Rows, "table %s must contain resources of the budget", budgetTable.Name)
+
+		table, err := model.Table(nil)
+		suite.Require().Nil(err)
+		suite.Assert().Len(table.Rows, 2*len(budgetTable.Rows), "table %s must contain resources of all budgets", table.Name)
+	}
+}
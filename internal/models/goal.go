@@ -23,7 +23,10 @@ type Goal struct {
 	Period     uint
 }
 
-var ErrGoalAmountNotPositive = errors.New("goal amounts must be larger than zero")
+var (
+	ErrGoalAmountNotPositive = errors.New("goal amounts must be larger than zero")
+	ErrGoalNameNotUnique     = errors.New("the goal name must be unique for the envelope")
+)
 
 func (g *Goal) BeforeCreate(tx *gorm.DB) error {
 	_ = g.DefaultModel.BeforeCreate(tx)
@@ -64,17 +67,34 @@ func (g *Goal) AfterSave(_ *gorm.DB) error {
 	return nil
 }
 
-// Returns all goals on this instance for export
-func (Goal) Export() (json.RawMessage, error) {
+// Returns all goals for export. If budgetID is set, only goals of that budget are returned.
+func (Goal) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[Goal](goalBudgetScope(budgetID))
+}
+
+// Table returns all goals for export as a table.
+func (Goal) Table(budgetID *uuid.UUID) (Table, error) {
 	var goals []Goal
-	err := DB.Unscoped().Where(&Goal{}).Find(&goals).Error
+	err := DB.Scopes(goalBudgetScope(budgetID)).Preload("Envelope.Category").Order("goals.month ASC, goals.name ASC").Find(&goals).Error
 	if err != nil {
-		return nil, err
+		return Table{}, err
 	}
 
-	j, err := json.Marshal(&goals)
-	if err != nil {
-		return json.RawMessage{}, err
+	table := Table{
+		Name:   "Goals",
+		Header: []string{"ID", "Category", "Envelope", "Name", "Note", "Amount", "Month", "Period", "Archived"},
 	}
-	return json.RawMessage(j), nil
+
+	for _, g := range goals {
+		table.Rows = append(table.Rows, []any{g.ID.String(), g.Envelope.Category.Name, g.Envelope.Name, g.Name, g.Note, g.Amount, g.Month, g.Period, g.Archived})
+	}
+
+	return table, nil
+}
+
+func goalBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "categories.budget_id",
+		"JOIN envelopes ON envelopes.id = goals.envelope_id",
+		"JOIN categories ON categories.id = envelopes.category_id",
+	)
 }
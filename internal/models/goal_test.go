@@ -101,7 +101,7 @@ func (suite *TestSuiteStandard) TestGoalExport() {
 		_ = suite.createTestGoal(models.Goal{EnvelopeID: envelope.ID, Name: fmt.Sprint(i), Amount: decimal.NewFromFloat(17)})
 	}
 
-	raw, err := models.Goal{}.Export()
+	raw, err := models.Goal{}.Export(nil)
 	if err != nil {
 		require.Fail(t, "goal export failed", err)
 	}
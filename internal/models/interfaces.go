@@ -1,10 +1,15 @@
 package models
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
 
 // Model is an interface that
 type Model interface {
-	Export() (json.RawMessage, error) // All instances of this model for export.
+	Export(budgetID *uuid.UUID) (json.RawMessage, error) // All instances of this model for export. If budgetID is set, only instances of that budget.
+	Table(budgetID *uuid.UUID) (Table, error)            // All instances of this model for export with human-readable columns
 }
 
 // The "Registry" is a slice of all models available
@@ -16,8 +21,13 @@ var Registry = []Model{
 	Budget{},
 	Category{},
 	Envelope{},
+	ExchangeRate{},
 	Goal{},
 	MatchRule{},
 	MonthConfig{},
+	Reconciliation{},
+	Tag{},
 	Transaction{},
+	TransactionTag{},
+	Attachment{},
 }
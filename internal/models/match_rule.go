@@ -38,17 +38,43 @@ func (m *MatchRule) checkIntegrity(tx *gorm.DB, toSave MatchRule) error {
 	return tx.First(&Account{}, toSave.AccountID).Error
 }
 
-// Returns all match rules on this instance for export
-func (MatchRule) Export() (json.RawMessage, error) {
+// Returns all match rules for export. If budgetID is set, only match rules of that budget are returned.
+func (MatchRule) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[MatchRule](matchRuleBudgetScope(budgetID))
+}
+
+// Table returns all match rules for export as a table.
+func (MatchRule) Table(budgetID *uuid.UUID) (Table, error) {
 	var matchRules []MatchRule
-	err := DB.Unscoped().Where(&MatchRule{}).Find(&matchRules).Error
+	err := DB.Scopes(matchRuleBudgetScope(budgetID)).Order("match_rules.priority ASC, match_rules.match ASC").Find(&matchRules).Error
 	if err != nil {
-		return nil, err
+		return Table{}, err
 	}
 
-	j, err := json.Marshal(&matchRules)
+	// Match rules have no association to their account, so the names are resolved here
+	var accounts []Account
+	err = DB.Scopes(accountBudgetScope(budgetID)).Find(&accounts).Error
 	if err != nil {
-		return json.RawMessage{}, err
+		return Table{}, err
+	}
+
+	names := make(map[uuid.UUID]string, len(accounts))
+	for _, a := range accounts {
+		names[a.ID] = a.Name
+	}
+
+	table := Table{
+		Name:   "Match Rules",
+		Header: []string{"ID", "Account", "Priority", "Match"},
 	}
-	return json.RawMessage(j), nil
+
+	for _, m := range matchRules {
+		table.Rows = append(table.Rows, []any{m.ID.String(), names[m.AccountID], m.Priority, m.Match})
+	}
+
+	return table, nil
+}
+
+func matchRuleBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "accounts.budget_id", "JOIN accounts ON accounts.id = match_rules.account_id")
 }
@@ -62,7 +62,7 @@ func (suite *TestSuiteStandard) TestMatchRuleExport() {
 		_ = suite.createTestMatchRule(models.MatchRule{AccountID: account.ID})
 	}
 
-	raw, err := models.MatchRule{}.Export()
+	raw, err := models.MatchRule{}.Export(nil)
 	if err != nil {
 		require.Fail(t, "match rule export failed", err)
 	}
@@ -22,6 +22,11 @@ type Timestamps struct {
 	UpdatedAt time.Time `json:"updatedAt" example:"2022-04-17T20:14:01.048145Z"` // Last time the resource was updated
 }
 
+// LastUpdate returns the time the resource was last updated.
+func (t Timestamps) LastUpdate() time.Time {
+	return t.UpdatedAt
+}
+
 // BeforeCreate is set to generate a UUID for the resource.
 func (m *DefaultModel) BeforeCreate(_ *gorm.DB) error {
 	m.ID = uuid.New()
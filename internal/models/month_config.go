@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/envelope-zero/backend/v7/internal/types"
@@ -19,24 +20,165 @@ type MonthConfig struct {
 	Note       string
 }
 
-var ErrMonthConfigMonthNotUnique = errors.New("you can not create multiple month configs for the same envelope and month")
+var (
+	ErrMonthConfigMonthNotUnique = errors.New("you can not create multiple month configs for the same envelope and month")
+
+	ErrAllocationMoveAmountNotPositive = errors.New("the amount to move must be positive")
+	ErrAllocationMoveNoEnvelope        = errors.New("at least one of the envelopes to move money between must be set")
+	ErrAllocationMoveSameEnvelope      = errors.New("money can not be moved from an envelope to itself")
+	ErrAllocationMoveBudget            = errors.New("the envelopes must belong to the budget")
+)
 
 func (m *MonthConfig) BeforeSave(_ *gorm.DB) error {
 	m.Note = strings.TrimSpace(m.Note)
 	return nil
 }
 
-// Returns all match rules on this instance for export
-func (MonthConfig) Export() (json.RawMessage, error) {
+// AfterSave invalidates the balance snapshots of the envelope.
+func (m *MonthConfig) AfterSave(tx *gorm.DB) error {
+	return invalidateEnvelopeBalances(tx, m.EnvelopeID, m.Month)
+}
+
+// AfterDelete invalidates the balance snapshots of the envelope.
+func (m *MonthConfig) AfterDelete(tx *gorm.DB) error {
+	return invalidateEnvelopeBalances(tx, m.EnvelopeID, m.Month)
+}
+
+// MoveAllocation moves the amount from the allocation of the source envelope
+// to the allocation of the destination envelope for the month.
+//
+// When the source or destination is nil, the money is moved from or to the
+// amount available to budget. A line describing the move is added to the notes
+// of the month configs so that it can be traced later.
+func MoveAllocation(db *gorm.DB, budgetID uuid.UUID, month types.Month, source, destination *uuid.UUID, amount decimal.Decimal, note string) error {
+	if !amount.IsPositive() {
+		return ErrAllocationMoveAmountNotPositive
+	}
+
+	if source == nil && destination == nil {
+		return ErrAllocationMoveNoEnvelope
+	}
+
+	if source != nil && destination != nil && *source == *destination {
+		return ErrAllocationMoveSameEnvelope
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		sourceName, err := allocationMoveName(tx, budgetID, source)
+		if err != nil {
+			return err
+		}
+
+		destinationName, err := allocationMoveName(tx, budgetID, destination)
+		if err != nil {
+			return err
+		}
+
+		note = strings.TrimSpace(note)
+		if note != "" {
+			note = fmt.Sprintf(": %s", note)
+		}
+
+		if source != nil {
+			err = addAllocation(tx, *source, month, amount.Neg(), fmt.Sprintf("Moved %s to %s%s", amount, destinationName, note))
+			if err != nil {
+				return err
+			}
+		}
+
+		if destination != nil {
+			err = addAllocation(tx, *destination, month, amount, fmt.Sprintf("Moved %s from %s%s", amount, sourceName, note))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// allocationMoveName verifies that the envelope belongs to the budget and returns its name.
+//
+// For nil, money is moved from or to the amount available to budget.
+func allocationMoveName(tx *gorm.DB, budgetID uuid.UUID, envelopeID *uuid.UUID) (string, error) {
+	if envelopeID == nil {
+		return "available to budget", nil
+	}
+
+	var envelope Envelope
+	err := tx.Preload("Category").First(&envelope, envelopeID).Error
+	if err != nil {
+		return "", err
+	}
+
+	if envelope.Category.BudgetID != budgetID {
+		return "", ErrAllocationMoveBudget
+	}
+
+	return envelope.Name, nil
+}
+
+// addAllocation adds the amount to the allocation of the envelope for the month
+// and appends the line to the note of the month config.
+func addAllocation(tx *gorm.DB, envelopeID uuid.UUID, month types.Month, amount decimal.Decimal, line string) error {
 	var monthConfigs []MonthConfig
-	err := DB.Unscoped().Where(&MonthConfig{}).Find(&monthConfigs).Error
+	err := tx.Where(&MonthConfig{EnvelopeID: envelopeID, Month: month}).Find(&monthConfigs).Error
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if len(monthConfigs) == 0 {
+		return tx.Create(&MonthConfig{EnvelopeID: envelopeID, Month: month, Allocation: amount, Note: line}).Error
 	}
 
-	j, err := json.Marshal(&monthConfigs)
+	m := monthConfigs[0]
+	return tx.Model(&m).Select("Allocation", "Note").Updates(MonthConfig{
+		Allocation: m.Allocation.Add(amount),
+		Note:       strings.TrimSpace(strings.Join([]string{m.Note, line}, "\n")),
+	}).Error
+}
+
+// Returns all month configs for export. If budgetID is set, only month configs of that budget are returned.
+func (MonthConfig) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[MonthConfig](monthConfigBudgetScope(budgetID))
+}
+
+// Table returns all month configs for export as a table.
+func (MonthConfig) Table(budgetID *uuid.UUID) (Table, error) {
+	var monthConfigs []MonthConfig
+	err := DB.Scopes(monthConfigBudgetScope(budgetID)).Order("month_configs.month ASC").Find(&monthConfigs).Error
 	if err != nil {
-		return json.RawMessage{}, err
+		return Table{}, err
 	}
-	return json.RawMessage(j), nil
+
+	// Month configs have no association to their envelope, so the names are resolved here
+	var envelopes []Envelope
+	err = DB.Scopes(envelopeBudgetScope(budgetID)).Preload("Category").Find(&envelopes).Error
+	if err != nil {
+		return Table{}, err
+	}
+
+	lookup := make(map[uuid.UUID]Envelope, len(envelopes))
+	for _, e := range envelopes {
+		lookup[e.ID] = e
+	}
+
+	table := Table{
+		Name:   "Month Configs",
+		Header: []string{"Month", "Category", "Envelope", "Allocation", "Note"},
+	}
+
+	for _, m := range monthConfigs {
+		envelope := lookup[m.EnvelopeID]
+		table.Rows = append(table.Rows, []any{m.Month, envelope.Category.Name, envelope.Name, m.Allocation, m.Note})
+	}
+
+	return table, nil
+}
+
+func monthConfigBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "categories.budget_id",
+		"JOIN envelopes ON envelopes.id = month_configs.envelope_id",
+		"JOIN categories ON categories.id = envelopes.category_id",
+	)
 }
@@ -7,6 +7,8 @@ import (
 
 	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -36,7 +38,7 @@ func (suite *TestSuiteStandard) TestMonthConfigExport() {
 	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: types.NewMonth(1977, time.January)})
 	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: types.NewMonth(1977, time.February)})
 
-	raw, err := models.MonthConfig{}.Export()
+	raw, err := models.MonthConfig{}.Export(nil)
 	if err != nil {
 		require.Fail(t, "month config export failed", err)
 	}
@@ -49,3 +51,33 @@ func (suite *TestSuiteStandard) TestMonthConfigExport() {
 
 	require.Len(t, monthConfigs, 2, "Number of monthc configs in export is wrong")
 }
+
+func (suite *TestSuiteStandard) TestMoveAllocation() {
+	budget := suite.createTestBudget(models.Budget{})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	rent := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Rent"})
+	groceries := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Groceries"})
+	other := suite.createTestEnvelope(models.Envelope{CategoryID: suite.createTestCategory(models.Category{BudgetID: suite.createTestBudget(models.Budget{}).ID}).ID})
+
+	month := types.NewMonth(2024, time.May)
+	_ = suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: rent.ID, Month: month, Allocation: decimal.NewFromFloat(500), Note: "Base rent"})
+
+	ten := decimal.NewFromFloat(10)
+	suite.Assert().ErrorIs(models.MoveAllocation(models.DB, budget.ID, month, &rent.ID, &groceries.ID, decimal.Zero, ""), models.ErrAllocationMoveAmountNotPositive)
+	suite.Assert().ErrorIs(models.MoveAllocation(models.DB, budget.ID, month, nil, nil, ten, ""), models.ErrAllocationMoveNoEnvelope)
+	suite.Assert().ErrorIs(models.MoveAllocation(models.DB, budget.ID, month, &rent.ID, &rent.ID, ten, ""), models.ErrAllocationMoveSameEnvelope)
+	suite.Assert().ErrorIs(models.MoveAllocation(models.DB, budget.ID, month, &rent.ID, &other.ID, ten, ""), models.ErrAllocationMoveBudget)
+	suite.Assert().ErrorIs(models.MoveAllocation(models.DB, budget.ID, month, &rent.ID, &uuid.UUID{1}, ten, ""), models.ErrResourceNotFound)
+
+	suite.Require().Nil(models.MoveAllocation(models.DB, budget.ID, month, &rent.ID, &groceries.ID, decimal.NewFromFloat(25.5), " Cheaper flat "))
+
+	var source models.MonthConfig
+	suite.Require().Nil(models.DB.Where(&models.MonthConfig{EnvelopeID: rent.ID, Month: month}).First(&source).Error)
+	suite.Assert().True(decimal.NewFromFloat(474.5).Equal(source.Allocation), "Allocation is %s", source.Allocation)
+	suite.Assert().Equal("Base rent\nMoved 25.5 to Groceries: Cheaper flat", source.Note)
+
+	var destination models.MonthConfig
+	suite.Require().Nil(models.DB.Where(&models.MonthConfig{EnvelopeID: groceries.ID, Month: month}).First(&destination).Error)
+	suite.Assert().True(decimal.NewFromFloat(25.5).Equal(destination.Allocation), "Allocation is %s", destination.Allocation)
+	suite.Assert().Equal("Moved 25.5 from Rent: Cheaper flat", destination.Note)
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrPayeeNotExternal = errors.New("payees must be external accounts")
+
+// MergePayees merges the payees with the IDs into the account.
+//
+// Payees are external accounts, the account and the payees must be one.
+// See Merge for how the payees are merged.
+func (a *Account) MergePayees(db *gorm.DB, ids []uuid.UUID) error {
+	if !a.External {
+		return ErrPayeeNotExternal
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			var payee Account
+			err := tx.First(&payee, id).Error
+			if err != nil {
+				return err
+			}
+
+			if !payee.External {
+				return ErrPayeeNotExternal
+			}
+		}
+
+		return a.Merge(tx, ids)
+	})
+}
@@ -0,0 +1,86 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) TestAccountDefaultEnvelope() {
+	budget := suite.createTestBudget(models.Budget{})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: suite.createTestCategory(models.Category{BudgetID: budget.ID}).ID})
+	otherEnvelope := suite.createTestEnvelope(models.Envelope{CategoryID: suite.createTestCategory(models.Category{BudgetID: suite.createTestBudget(models.Budget{}).ID}).ID})
+
+	err := models.DB.Create(&models.Account{BudgetID: budget.ID, Name: "Internal", DefaultEnvelopeID: &envelope.ID}).Error
+	suite.Assert().ErrorIs(err, models.ErrAccountDefaultEnvelopeNotExternal)
+
+	err = models.DB.Create(&models.Account{BudgetID: budget.ID, Name: "Other budget", External: true, DefaultEnvelopeID: &otherEnvelope.ID}).Error
+	suite.Assert().ErrorIs(err, models.ErrAccountDefaultEnvelopeBudget)
+
+	err = models.DB.Create(&models.Account{BudgetID: budget.ID, Name: "Missing", External: true, DefaultEnvelopeID: &uuid.UUID{1}}).Error
+	suite.Assert().ErrorIs(err, models.ErrResourceNotFound)
+
+	payee := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, DefaultEnvelopeID: &envelope.ID})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+
+	err = models.DB.Model(&payee).Select("External").Updates(models.Account{External: false}).Error
+	suite.Assert().ErrorIs(err, models.ErrAccountDefaultEnvelopeNotExternal)
+
+	// Outgoing transactions without an envelope get the default envelope of the payee
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: payee.ID, Amount: decimal.NewFromFloat(10)})
+	suite.Require().NotNil(transaction.EnvelopeID)
+	suite.Assert().Equal(envelope.ID, *transaction.EnvelopeID)
+
+	// Incoming transactions are not changed
+	transaction = suite.createTestTransaction(models.Transaction{SourceAccountID: payee.ID, DestinationAccountID: account.ID, Amount: decimal.NewFromFloat(10)})
+	suite.Assert().Nil(transaction.EnvelopeID)
+
+	// Deleting the envelope removes it as default envelope
+	unused := suite.createTestEnvelope(models.Envelope{CategoryID: envelope.CategoryID, Name: "Unused"})
+	suite.Require().Nil(models.DB.Model(&payee).Select("DefaultEnvelopeID").Updates(models.Account{DefaultEnvelopeID: &unused.ID}).Error)
+	suite.Require().Nil(models.DB.Delete(&unused).Error)
+	var updated models.Account
+	suite.Require().Nil(models.DB.First(&updated, payee.ID).Error)
+	suite.Assert().Nil(updated.DefaultEnvelopeID)
+}
+
+func (suite *TestSuiteStandard) TestAccountMergePayees() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	payee := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Amazon", External: true})
+	duplicate := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "AMAZON.DE", External: true})
+
+	outgoing := suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: duplicate.ID, Amount: decimal.NewFromFloat(17), Date: time.Now(), ReconciledSource: true})
+	incoming := suite.createTestTransaction(models.Transaction{SourceAccountID: duplicate.ID, DestinationAccountID: account.ID, Amount: decimal.NewFromFloat(3), Date: time.Now()})
+	matchRule := suite.createTestMatchRule(models.MatchRule{AccountID: duplicate.ID, Match: "AMAZON*"})
+
+	suite.Assert().ErrorIs(account.MergePayees(models.DB, []uuid.UUID{duplicate.ID}), models.ErrPayeeNotExternal)
+	suite.Assert().ErrorIs(payee.MergePayees(models.DB, []uuid.UUID{payee.ID}), models.ErrAccountMergeSelf)
+	suite.Assert().ErrorIs(payee.MergePayees(models.DB, []uuid.UUID{account.ID}), models.ErrPayeeNotExternal)
+
+	other := suite.createTestAccount(models.Account{BudgetID: suite.createTestBudget(models.Budget{}).ID, External: true})
+	suite.Assert().ErrorIs(payee.MergePayees(models.DB, []uuid.UUID{other.ID}), models.ErrAccountMergeBudget)
+
+	dollar := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, Currency: "USD"})
+	suite.Assert().ErrorIs(payee.MergePayees(models.DB, []uuid.UUID{dollar.ID}), models.ErrAccountMergeCurrency)
+
+	// Nothing is merged when one of the payees can not be merged
+	suite.Assert().ErrorIs(payee.MergePayees(models.DB, []uuid.UUID{duplicate.ID, dollar.ID}), models.ErrAccountMergeCurrency)
+	suite.Require().Nil(models.DB.First(&models.Account{}, duplicate.ID).Error)
+
+	suite.Require().Nil(payee.MergePayees(models.DB, []uuid.UUID{duplicate.ID}))
+
+	suite.Require().Nil(models.DB.First(&outgoing, outgoing.ID).Error)
+	suite.Assert().Equal(payee.ID, outgoing.DestinationAccountID)
+
+	suite.Require().Nil(models.DB.First(&incoming, incoming.ID).Error)
+	suite.Assert().Equal(payee.ID, incoming.SourceAccountID)
+
+	suite.Require().Nil(models.DB.First(&matchRule, matchRule.ID).Error)
+	suite.Assert().Equal(payee.ID, matchRule.AccountID)
+
+	err := models.DB.First(&models.Account{}, duplicate.ID).Error
+	suite.Assert().ErrorIs(err, models.ErrResourceNotFound)
+}
@@ -0,0 +1,297 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ReconciliationState is the state of a reconciliation.
+type ReconciliationState string
+
+const (
+	ReconciliationStateInProgress ReconciliationState = "IN_PROGRESS"
+	ReconciliationStateCompleted  ReconciliationState = "COMPLETED"
+)
+
+// ReconciliationAdjustmentAccount is the name of the external account that
+// adjustment transactions for reconciliations are booked against.
+const ReconciliationAdjustmentAccount = "Reconciliation adjustment"
+
+// Reconciliation is the comparison of an account with a statement, e.g. from a bank.
+type Reconciliation struct {
+	DefaultModel
+	Account          Account   `json:"-"`
+	AccountID        uuid.UUID `gorm:"index"`
+	StatementDate    time.Time
+	StatementBalance decimal.Decimal `gorm:"type:DECIMAL(20,8)"`
+	Note             string
+	State            ReconciliationState
+	CompletedAt      *time.Time
+	AdjustmentID     *uuid.UUID // ID of the transaction created on completion to balance the account with the statement
+}
+
+var (
+	ErrReconciliationCompleted           = errors.New("the reconciliation is already completed and can not be changed")
+	ErrReconciliationAccountExternal     = errors.New("external accounts can not be reconciled")
+	ErrReconciliationNotBalanced         = errors.New("the reconciled balance does not match the statement balance. Reconcile more transactions or create an adjustment")
+	ErrReconciliationTransactionNotFound = errors.New("the transaction is not an unreconciled transaction of the account before the statement date")
+)
+
+func (r *Reconciliation) BeforeCreate(tx *gorm.DB) error {
+	_ = r.DefaultModel.BeforeCreate(tx)
+
+	toSave := tx.Statement.Dest.(*Reconciliation)
+	toSave.State = ReconciliationStateInProgress
+	toSave.CompletedAt = nil
+	toSave.AdjustmentID = nil
+
+	return r.checkIntegrity(tx, *toSave)
+}
+
+func (r *Reconciliation) BeforeSave(_ *gorm.DB) error {
+	r.Note = strings.TrimSpace(r.Note)
+	return nil
+}
+
+// BeforeUpdate verifies the state of the reconciliation before
+// committing an update to the database.
+func (r *Reconciliation) BeforeUpdate(tx *gorm.DB) error {
+	if r.State == ReconciliationStateCompleted {
+		return ErrReconciliationCompleted
+	}
+
+	if tx.Statement.Changed("AccountID") {
+		toSave := tx.Statement.Dest.(Reconciliation)
+		return r.checkIntegrity(tx, toSave)
+	}
+
+	return nil
+}
+
+// checkIntegrity verifies references to other resources
+func (r *Reconciliation) checkIntegrity(tx *gorm.DB, toSave Reconciliation) error {
+	var account Account
+	err := tx.First(&account, toSave.AccountID).Error
+	if err != nil {
+		return err
+	}
+
+	if account.External {
+		return ErrReconciliationAccountExternal
+	}
+
+	return nil
+}
+
+// end returns the time until which transactions are part of the reconciliation.
+//
+// The statement date is included completely.
+func (r Reconciliation) end() time.Time {
+	return time.Date(r.StatementDate.Year(), r.StatementDate.Month(), r.StatementDate.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// Transactions returns all transactions of the account up to and including the
+// statement date that are not reconciled in the account.
+func (r Reconciliation) Transactions(db *gorm.DB) ([]Transaction, error) {
+	var transactions []Transaction
+	err := db.
+		Where("(transactions.source_account_id = ? AND NOT transactions.reconciled_source) OR (transactions.destination_account_id = ? AND NOT transactions.reconciled_destination)", r.AccountID, r.AccountID).
+		Where("datetime(transactions.date) < datetime(?)", r.end()).
+		Order("datetime(transactions.date) ASC, datetime(transactions.created_at) ASC").
+		Find(&transactions).Error
+
+	return transactions, err
+}
+
+// ReconciledBalance returns the balance of the account at the end of the statement date,
+// only including transactions that are reconciled already.
+func (r Reconciliation) ReconciledBalance(db *gorm.DB) (decimal.Decimal, error) {
+	var account Account
+	err := db.First(&account, r.AccountID).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return account.ReconciledBalance(db, r.end())
+}
+
+// Difference returns the difference between the statement balance and the
+// reconciled balance of the account if the transactions are reconciled, too.
+func (r Reconciliation) Difference(db *gorm.DB, transactions []Transaction) (decimal.Decimal, error) {
+	balance, err := r.ReconciledBalance(db)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	for _, t := range transactions {
+		if t.DestinationAccountID == r.AccountID {
+			balance = balance.Add(t.Received())
+		} else {
+			balance = balance.Sub(t.Amount)
+		}
+	}
+
+	return r.StatementBalance.Sub(balance), nil
+}
+
+// Complete marks transactions as reconciled in the account and completes the reconciliation.
+//
+// If transactionIDs is nil, all unreconciled transactions up to the statement date are
+// reconciled. If the reconciled balance does not match the statement balance afterwards,
+// a transaction for the difference is created if adjust is true. Otherwise, completing fails.
+func (r *Reconciliation) Complete(db *gorm.DB, transactionIDs []uuid.UUID, adjust bool) error {
+	if r.State == ReconciliationStateCompleted {
+		return ErrReconciliationCompleted
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		transactions, err := r.Transactions(tx)
+		if err != nil {
+			return err
+		}
+
+		if transactionIDs != nil {
+			selected := make([]Transaction, 0, len(transactionIDs))
+			for _, id := range transactionIDs {
+				found := false
+				for _, t := range transactions {
+					if t.ID == id {
+						selected = append(selected, t)
+						found = true
+						break
+					}
+				}
+
+				if !found {
+					return ErrReconciliationTransactionNotFound
+				}
+			}
+			transactions = selected
+		}
+
+		difference, err := r.Difference(tx, transactions)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range transactions {
+			update := Transaction{ClearedSource: true, ReconciledSource: true}
+			fields := []string{"ClearedSource", "ReconciledSource"}
+			if t.DestinationAccountID == r.AccountID {
+				update = Transaction{ClearedDestination: true, ReconciledDestination: true}
+				fields = []string{"ClearedDestination", "ReconciledDestination"}
+			}
+
+			err = tx.Model(&t).Select(fields).Updates(update).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var adjustmentID *uuid.UUID
+		if !difference.IsZero() {
+			if !adjust {
+				return ErrReconciliationNotBalanced
+			}
+
+			adjustment, err := r.adjust(tx, difference)
+			if err != nil {
+				return err
+			}
+			adjustmentID = &adjustment.ID
+		}
+
+		now := time.Now()
+		err = tx.Model(r).Select("State", "CompletedAt", "AdjustmentID").Updates(Reconciliation{
+			State:        ReconciliationStateCompleted,
+			CompletedAt:  &now,
+			AdjustmentID: adjustmentID,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		r.State = ReconciliationStateCompleted
+		r.CompletedAt = &now
+		r.AdjustmentID = adjustmentID
+		return nil
+	})
+}
+
+// adjust creates a reconciled transaction for the difference between the account and
+// the statement.
+//
+// The transaction is booked against an external account that is created if it does not exist yet.
+// For accounts with their own currency, there is one external account per currency.
+func (r Reconciliation) adjust(tx *gorm.DB, difference decimal.Decimal) (Transaction, error) {
+	var account Account
+	err := tx.First(&account, r.AccountID).Error
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	name := ReconciliationAdjustmentAccount
+	if account.Currency != "" {
+		name = fmt.Sprintf("%s (%s)", ReconciliationAdjustmentAccount, account.Currency)
+	}
+
+	var external Account
+	err = tx.Where(Account{BudgetID: account.BudgetID, Name: name}).Attrs(Account{External: true, Currency: account.Currency}).FirstOrCreate(&external).Error
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	adjustment := Transaction{
+		SourceAccountID:       external.ID,
+		DestinationAccountID:  account.ID,
+		Amount:                difference.Abs(),
+		Date:                  r.StatementDate,
+		Note:                  ReconciliationAdjustmentAccount,
+		ReconciledDestination: true,
+	}
+
+	// A negative difference means that there is less money in the account than the statement says
+	if difference.IsNegative() {
+		adjustment.SourceAccountID, adjustment.DestinationAccountID = account.ID, external.ID
+		adjustment.ReconciledSource, adjustment.ReconciledDestination = true, false
+	}
+
+	err = tx.Create(&adjustment).Error
+	return adjustment, err
+}
+
+// Returns all reconciliations for export. If budgetID is set, only reconciliations of that budget are returned.
+func (Reconciliation) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[Reconciliation](reconciliationBudgetScope(budgetID))
+}
+
+// Table returns all reconciliations for export as a table.
+func (Reconciliation) Table(budgetID *uuid.UUID) (Table, error) {
+	var reconciliations []Reconciliation
+	err := DB.Scopes(reconciliationBudgetScope(budgetID)).Preload("Account").Order("reconciliations.statement_date ASC").Find(&reconciliations).Error
+	if err != nil {
+		return Table{}, err
+	}
+
+	table := Table{
+		Name:   "Reconciliations",
+		Header: []string{"ID", "Account", "Statement Date", "Statement Balance", "Note", "State", "Completed At"},
+	}
+
+	for _, r := range reconciliations {
+		table.Rows = append(table.Rows, []any{r.ID.String(), r.Account.Name, r.StatementDate, r.StatementBalance, r.Note, string(r.State), r.CompletedAt})
+	}
+
+	return table, nil
+}
+
+func reconciliationBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "accounts.budget_id", "JOIN accounts ON accounts.id = reconciliations.account_id")
+}
@@ -0,0 +1,184 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) TestReconciliationComplete() {
+	initialBalanceDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Bank", OnBudget: true, InitialBalance: decimal.NewFromFloat(100), InitialBalanceDate: &initialBalanceDate})
+	employer := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Employer", External: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true})
+
+	salary := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      employer.ID,
+		DestinationAccountID: bank.ID,
+		Amount:               decimal.NewFromFloat(1000),
+		Date:                 time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	})
+
+	shopping := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      bank.ID,
+		DestinationAccountID: shop.ID,
+		Amount:               decimal.NewFromFloat(50),
+		Date:                 time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC),
+	})
+
+	// After the statement date, must not be part of the reconciliation
+	later := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      bank.ID,
+		DestinationAccountID: shop.ID,
+		Amount:               decimal.NewFromFloat(20),
+		Date:                 time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	reconciliation := models.Reconciliation{
+		AccountID:        bank.ID,
+		StatementDate:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		StatementBalance: decimal.NewFromFloat(1050),
+	}
+	suite.Require().Nil(models.DB.Create(&reconciliation).Error)
+	suite.Assert().Equal(models.ReconciliationStateInProgress, reconciliation.State)
+
+	transactions, err := reconciliation.Transactions(models.DB)
+	suite.Require().Nil(err)
+	suite.Require().Len(transactions, 2)
+	suite.Assert().Equal(salary.ID, transactions[0].ID)
+	suite.Assert().Equal(shopping.ID, transactions[1].ID)
+
+	difference, err := reconciliation.Difference(models.DB, transactions)
+	suite.Require().Nil(err)
+	suite.Assert().True(difference.IsZero(), "Difference is %s", difference)
+
+	err = reconciliation.Complete(models.DB, nil, false)
+	suite.Require().Nil(err)
+	suite.Assert().Equal(models.ReconciliationStateCompleted, reconciliation.State)
+	suite.Assert().NotNil(reconciliation.CompletedAt)
+	suite.Assert().Nil(reconciliation.AdjustmentID)
+
+	suite.Require().Nil(models.DB.First(&salary, salary.ID).Error)
+	suite.Assert().True(salary.ReconciledDestination)
+	suite.Assert().False(salary.ReconciledSource)
+
+	suite.Require().Nil(models.DB.First(&shopping, shopping.ID).Error)
+	suite.Assert().True(shopping.ReconciledSource)
+
+	suite.Require().Nil(models.DB.First(&later, later.ID).Error)
+	suite.Assert().False(later.ReconciledSource)
+
+	err = models.DB.Model(&reconciliation).Select("Note").Updates(models.Reconciliation{Note: "Changed"}).Error
+	suite.Assert().ErrorIs(err, models.ErrReconciliationCompleted)
+
+	err = reconciliation.Complete(models.DB, nil, false)
+	suite.Assert().ErrorIs(err, models.ErrReconciliationCompleted)
+}
+
+func (suite *TestSuiteStandard) TestReconciliationAdjustment() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Bank", OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true})
+
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      bank.ID,
+		DestinationAccountID: shop.ID,
+		Amount:               decimal.NewFromFloat(30),
+		Date:                 time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+	})
+
+	reconciliation := models.Reconciliation{
+		AccountID:        bank.ID,
+		StatementDate:    time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		StatementBalance: decimal.NewFromFloat(-42.5),
+	}
+	suite.Require().Nil(models.DB.Create(&reconciliation).Error)
+
+	err := reconciliation.Complete(models.DB, nil, false)
+	suite.Require().ErrorIs(err, models.ErrReconciliationNotBalanced)
+
+	transactions, err := reconciliation.Transactions(models.DB)
+	suite.Require().Nil(err)
+	suite.Assert().Len(transactions, 1, "Transactions must not be reconciled when completing fails")
+
+	err = reconciliation.Complete(models.DB, nil, true)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(reconciliation.AdjustmentID)
+
+	var adjustment models.Transaction
+	suite.Require().Nil(models.DB.First(&adjustment, reconciliation.AdjustmentID).Error)
+	suite.Assert().True(decimal.NewFromFloat(12.5).Equal(adjustment.Amount), "Adjustment amount is %s", adjustment.Amount)
+	suite.Assert().Equal(bank.ID, adjustment.SourceAccountID)
+	suite.Assert().True(adjustment.ReconciledSource)
+
+	var external models.Account
+	suite.Require().Nil(models.DB.First(&external, adjustment.DestinationAccountID).Error)
+	suite.Assert().True(external.External)
+	suite.Assert().Equal(models.ReconciliationAdjustmentAccount, external.Name)
+
+	balance, err := bank.ReconciledBalance(models.DB, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	suite.Require().Nil(err)
+	suite.Assert().True(reconciliation.StatementBalance.Equal(balance), "Reconciled balance is %s", balance)
+}
+
+func (suite *TestSuiteStandard) TestReconciliationSelectedTransactions() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Bank", OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true})
+
+	selected := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      bank.ID,
+		DestinationAccountID: shop.ID,
+		Amount:               decimal.NewFromFloat(10),
+		Date:                 time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+	})
+
+	pending := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      bank.ID,
+		DestinationAccountID: shop.ID,
+		Amount:               decimal.NewFromFloat(15),
+		Date:                 time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+	})
+
+	reconciliation := models.Reconciliation{
+		AccountID:        bank.ID,
+		StatementDate:    time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		StatementBalance: decimal.NewFromFloat(-10),
+	}
+	suite.Require().Nil(models.DB.Create(&reconciliation).Error)
+
+	err := reconciliation.Complete(models.DB, []uuid.UUID{uuid.New()}, false)
+	suite.Assert().ErrorIs(err, models.ErrReconciliationTransactionNotFound)
+
+	err = reconciliation.Complete(models.DB, []uuid.UUID{selected.ID}, false)
+	suite.Require().Nil(err)
+
+	suite.Require().Nil(models.DB.First(&pending, pending.ID).Error)
+	suite.Assert().False(pending.ReconciledSource)
+}
+
+func (suite *TestSuiteStandard) TestReconciliationExternalAccount() {
+	budget := suite.createTestBudget(models.Budget{})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+
+	err := models.DB.Create(&models.Reconciliation{AccountID: shop.ID}).Error
+	suite.Assert().ErrorIs(err, models.ErrReconciliationAccountExternal)
+}
+
+func (suite *TestSuiteStandard) TestTrashAccountWithReconciliation() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+
+	reconciliation := models.Reconciliation{AccountID: bank.ID, StatementDate: time.Now()}
+	suite.Require().Nil(models.DB.Create(&reconciliation).Error)
+
+	entry, _, err := models.MoveToTrash(models.DB, &bank)
+	suite.Require().Nil(err)
+	suite.Assert().ErrorIs(models.DB.First(&models.Reconciliation{}, reconciliation.ID).Error, models.ErrResourceNotFound)
+
+	suite.Require().Nil(entry.Restore(models.DB))
+	suite.Assert().Nil(models.DB.First(&models.Reconciliation{}, reconciliation.ID).Error)
+}
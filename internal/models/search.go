@@ -0,0 +1,274 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrSearchQueryEmpty = errors.New("the search query must contain at least one word")
+
+// SearchResultType is the type of resource a search result refers to.
+type SearchResultType string
+
+const (
+	SearchResultAccount     SearchResultType = "ACCOUNT"
+	SearchResultCategory    SearchResultType = "CATEGORY"
+	SearchResultEnvelope    SearchResultType = "ENVELOPE"
+	SearchResultGoal        SearchResultType = "GOAL"
+	SearchResultTransaction SearchResultType = "TRANSACTION"
+)
+
+// SearchResult is a resource that matches a search.
+type SearchResult struct {
+	Type       SearchResultType
+	ResourceID uuid.UUID
+	Text       string  // The text that matched: the name of the resource, for transactions the note
+	Score      float64 // How well the resource matches. Higher is better
+}
+
+// searchTable is the FTS5 table that the searchable text of all resources is indexed in.
+const searchTable = "search_index"
+
+// searchSource defines how the resources of a table are indexed.
+type searchSource struct {
+	resultType SearchResultType
+	column     string // Column with the text to index
+	budget     string // Column with the ID of the budget of the resource
+	joins      string // Joins needed for the budget column
+}
+
+// searchSources are the indexed resources by table.
+var searchSources = map[string]searchSource{
+	"accounts": {
+		resultType: SearchResultAccount,
+		column:     "accounts.name",
+		budget:     "accounts.budget_id",
+	},
+	"categories": {
+		resultType: SearchResultCategory,
+		column:     "categories.name",
+		budget:     "categories.budget_id",
+	},
+	"envelopes": {
+		resultType: SearchResultEnvelope,
+		column:     "envelopes.name",
+		budget:     "categories.budget_id",
+		joins:      "JOIN categories ON categories.id = envelopes.category_id",
+	},
+	"goals": {
+		resultType: SearchResultGoal,
+		column:     "goals.name",
+		budget:     "categories.budget_id",
+		joins:      "JOIN envelopes ON envelopes.id = goals.envelope_id JOIN categories ON categories.id = envelopes.category_id",
+	},
+	"transactions": {
+		resultType: SearchResultTransaction,
+		column:     "transactions.note",
+		budget:     "accounts.budget_id",
+		joins:      "JOIN accounts ON accounts.id = transactions.source_account_id",
+	},
+}
+
+// migrateSearch creates the search index. When it is created, all existing resources are indexed.
+func migrateSearch(db *gorm.DB) error {
+	if db.Migrator().HasTable(searchTable) {
+		return nil
+	}
+
+	err := db.Exec(fmt.Sprintf("CREATE VIRTUAL TABLE %s USING fts5(type UNINDEXED, resource_id UNINDEXED, budget_id UNINDEXED, text, tokenize = 'unicode61 remove_diacritics 2')", searchTable)).Error
+	if err != nil {
+		return err
+	}
+
+	for table := range searchSources {
+		err = indexSearch(db, table, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexSearch updates the search index for the resources of the table with the IDs.
+// If ids is nil, all resources of the table are indexed.
+func indexSearch(db *gorm.DB, table string, ids []uuid.UUID) error {
+	source := searchSources[table]
+
+	if ids != nil {
+		err := unindexSearch(db, table, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (type, resource_id, budget_id, text) SELECT ?, %s.id, %s, %s FROM %s %s WHERE %s != ''",
+		searchTable, table, source.budget, source.column, table, source.joins, source.column,
+	)
+
+	values := []any{source.resultType}
+	if ids != nil {
+		query = fmt.Sprintf("%s AND %s.id IN ?", query, table)
+		values = append(values, ids)
+	}
+
+	return db.Exec(query, values...).Error
+}
+
+// unindexSearch removes the resources of the table with the IDs from the search index.
+func unindexSearch(db *gorm.DB, table string, ids []uuid.UUID) error {
+	return db.Exec(fmt.Sprintf("DELETE FROM %s WHERE type = ? AND resource_id IN ?", searchTable), searchSources[table].resultType, ids).Error
+}
+
+// searchIDs returns the IDs of the rows.
+func searchIDs(rows []map[string]any) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		id, err := resourceID(row["id"])
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// searchCreateCallback adds created resources to the search index.
+func searchCreateCallback(db *gorm.DB) {
+	if _, ok := searchSources[db.Statement.Table]; db.Error != nil || !ok {
+		return
+	}
+
+	rows, err := auditRows(db, nil)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	ids, err := searchIDs(rows)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	err = indexSearch(db.Session(&gorm.Session{NewDB: true}), db.Statement.Table, ids)
+	if err != nil {
+		_ = db.AddError(err)
+	}
+}
+
+// searchBeforeIDs returns the IDs of the resources affected by an update or delete.
+//
+// The rows are the ones stored by auditBeforeCallback.
+func searchBeforeIDs(db *gorm.DB) ([]uuid.UUID, error) {
+	rows, ok := db.InstanceGet(auditRowsKey)
+	if !ok {
+		return nil, nil
+	}
+
+	return searchIDs(rows.([]map[string]any))
+}
+
+// searchUpdateCallback updates the search index for updated resources.
+func searchUpdateCallback(db *gorm.DB) {
+	if _, ok := searchSources[db.Statement.Table]; db.Error != nil || !ok {
+		return
+	}
+
+	ids, err := searchBeforeIDs(db)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	err = indexSearch(db.Session(&gorm.Session{NewDB: true}), db.Statement.Table, ids)
+	if err != nil {
+		_ = db.AddError(err)
+	}
+}
+
+// searchDeleteCallback removes deleted resources from the search index.
+func searchDeleteCallback(db *gorm.DB) {
+	if _, ok := searchSources[db.Statement.Table]; db.Error != nil || !ok {
+		return
+	}
+
+	ids, err := searchBeforeIDs(db)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	err = unindexSearch(db.Session(&gorm.Session{NewDB: true}), db.Statement.Table, ids)
+	if err != nil {
+		_ = db.AddError(err)
+	}
+}
+
+// searchQuery converts the words of a search into an FTS5 query.
+//
+// Every word is quoted so that characters with a special meaning in FTS5 are
+// searched for literally. Words match as prefixes and all words need to match.
+func searchQuery(search string) string {
+	words := strings.Fields(search)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, fmt.Sprintf(`"%s"*`, strings.ReplaceAll(word, `"`, `""`)))
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// Search returns up to limit resources of the budget that match the search, best matches first.
+func Search(db *gorm.DB, budgetID uuid.UUID, search string, limit int) ([]SearchResult, error) {
+	query := searchQuery(search)
+	if query == "" {
+		return nil, ErrSearchQueryEmpty
+	}
+
+	var rows []struct {
+		Type       SearchResultType
+		ResourceID uuid.UUID
+		Text       string
+		Rank       float64
+	}
+
+	err := db.Raw(
+		fmt.Sprintf("SELECT type, resource_id, text, bm25(%[1]s) AS rank FROM %[1]s WHERE %[1]s MATCH ? AND budget_id = ? ORDER BY rank LIMIT ?", searchTable),
+		query, budgetID, limit,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(rows))
+	for _, row := range rows {
+		// bm25 is lower for better matches
+		results = append(results, SearchResult{
+			Type:       row.Type,
+			ResourceID: row.ResourceID,
+			Text:       row.Text,
+			Score:      -row.Rank,
+		})
+	}
+
+	return results, nil
+}
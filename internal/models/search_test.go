@@ -0,0 +1,106 @@
+package models_test
+
+import (
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) TestSearch() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Grocery Bank", OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID, Name: "Groceries"})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Café"})
+	goal := suite.createTestGoal(models.Goal{EnvelopeID: envelope.ID, Name: "Grocery stockpile for the winter months", Amount: decimal.NewFromFloat(100)})
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(10), Note: "Weekly groceries"})
+
+	// Resources of other budgets are not found
+	otherBudget := suite.createTestBudget(models.Budget{})
+	_ = suite.createTestCategory(models.Category{BudgetID: otherBudget.ID, Name: "Groceries"})
+
+	results, err := models.Search(models.DB, budget.ID, "grocer", 10)
+	suite.Require().Nil(err)
+	suite.Require().Len(results, 4)
+	suite.Assert().Equal(category.ID, results[0].ResourceID, "The shortest matching text is not ranked first")
+	suite.Assert().Equal(models.SearchResultCategory, results[0].Type)
+
+	for i := 1; i < len(results); i++ {
+		suite.Assert().GreaterOrEqual(results[i-1].Score, results[i].Score, "Results are not sorted by score")
+	}
+
+	ids := make(map[models.SearchResultType]string)
+	for _, result := range results {
+		ids[result.Type] = result.ResourceID.String()
+	}
+	suite.Assert().Equal(map[models.SearchResultType]string{
+		models.SearchResultAccount:     account.ID.String(),
+		models.SearchResultCategory:    category.ID.String(),
+		models.SearchResultGoal:        goal.ID.String(),
+		models.SearchResultTransaction: transaction.ID.String(),
+	}, ids)
+
+	// Diacritics are ignored, all words need to match
+	results, err = models.Search(models.DB, budget.ID, "cafe", 10)
+	suite.Require().Nil(err)
+	suite.Require().Len(results, 1)
+	suite.Assert().Equal(envelope.ID, results[0].ResourceID)
+	suite.Assert().Equal("Café", results[0].Text)
+
+	results, err = models.Search(models.DB, budget.ID, "weekly groceries", 10)
+	suite.Require().Nil(err)
+	suite.Require().Len(results, 1)
+	suite.Assert().Equal(transaction.ID, results[0].ResourceID)
+
+	// Characters with a special meaning in FTS5 are searched for literally
+	results, err = models.Search(models.DB, budget.ID, `"weekly AND NOT *`, 10)
+	suite.Require().Nil(err)
+	suite.Assert().Len(results, 0)
+
+	// The limit is respected
+	results, err = models.Search(models.DB, budget.ID, "grocer", 2)
+	suite.Require().Nil(err)
+	suite.Assert().Len(results, 2)
+
+	_, err = models.Search(models.DB, budget.ID, "   ", 10)
+	suite.Assert().ErrorIs(err, models.ErrSearchQueryEmpty)
+}
+
+func (suite *TestSuiteStandard) TestSearchIndexUpdates() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Checking", OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true})
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: account.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(10), Note: "Bicycle repair"})
+
+	results, err := models.Search(models.DB, budget.ID, "bicycle", 10)
+	suite.Require().Nil(err)
+	suite.Require().Len(results, 1)
+
+	// Updates replace the indexed text
+	err = models.DB.Model(&transaction).Select("Note").Updates(models.Transaction{Note: "Train ticket"}).Error
+	suite.Require().Nil(err)
+
+	results, err = models.Search(models.DB, budget.ID, "bicycle", 10)
+	suite.Require().Nil(err)
+	suite.Assert().Len(results, 0)
+
+	results, err = models.Search(models.DB, budget.ID, "train", 10)
+	suite.Require().Nil(err)
+	suite.Require().Len(results, 1)
+	suite.Assert().Equal(transaction.ID, results[0].ResourceID)
+
+	err = models.DB.Model(&account).Select("Name").Updates(models.Account{Name: "Savings"}).Error
+	suite.Require().Nil(err)
+
+	results, err = models.Search(models.DB, budget.ID, "savings", 10)
+	suite.Require().Nil(err)
+	suite.Require().Len(results, 1)
+	suite.Assert().Equal(models.SearchResultAccount, results[0].Type)
+
+	// Deleted resources are removed
+	err = models.DB.Delete(&transaction).Error
+	suite.Require().Nil(err)
+
+	results, err = models.Search(models.DB, budget.ID, "train", 10)
+	suite.Require().Nil(err)
+	suite.Assert().Len(results, 0)
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag is a label for transactions that is independent of envelopes, e.g. "Vacation 2024".
+type Tag struct {
+	DefaultModel
+	Budget   Budget    `json:"-"`
+	BudgetID uuid.UUID `gorm:"uniqueIndex:tag_budget_name"`
+	Name     string    `gorm:"uniqueIndex:tag_budget_name"`
+	Note     string
+}
+
+// TransactionTag links a transaction with a tag.
+type TransactionTag struct {
+	DefaultModel
+	Transaction   Transaction `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	TransactionID uuid.UUID   `gorm:"uniqueIndex:transaction_tag"`
+	Tag           Tag         `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	TagID         uuid.UUID   `gorm:"uniqueIndex:transaction_tag;index"`
+}
+
+var (
+	ErrTagNameNotUnique     = errors.New("the tag name must be unique for the budget")
+	ErrTransactionTagBudget = errors.New("the tag must belong to the same budget as the transaction")
+)
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	_ = t.DefaultModel.BeforeCreate(tx)
+
+	toSave := tx.Statement.Dest.(*Tag)
+	return t.checkIntegrity(tx, *toSave)
+}
+
+func (t *Tag) BeforeSave(_ *gorm.DB) error {
+	t.Name = strings.TrimSpace(t.Name)
+	t.Note = strings.TrimSpace(t.Note)
+
+	return nil
+}
+
+// BeforeUpdate verifies the budget when the tag is moved to another budget.
+func (t *Tag) BeforeUpdate(tx *gorm.DB) error {
+	toSave := tx.Statement.Dest.(Tag)
+	if tx.Statement.Changed("BudgetID") {
+		var count int64
+		err := tx.Model(&TransactionTag{}).Where(&TransactionTag{TagID: t.ID}).Count(&count).Error
+		if err != nil {
+			return err
+		}
+
+		// Tags can only be moved to another budget as long as
+		// no transaction uses them
+		if count > 0 {
+			return ErrTransactionTagBudget
+		}
+
+		return t.checkIntegrity(tx, toSave)
+	}
+
+	return nil
+}
+
+// checkIntegrity verifies references to other resources
+func (t *Tag) checkIntegrity(tx *gorm.DB, toSave Tag) error {
+	return tx.First(&Budget{}, toSave.BudgetID).Error
+}
+
+// BeforeCreate verifies that the tag and the transaction belong to the same budget.
+func (t *TransactionTag) BeforeCreate(tx *gorm.DB) error {
+	_ = t.DefaultModel.BeforeCreate(tx)
+
+	toSave := tx.Statement.Dest.(*TransactionTag)
+
+	var tag Tag
+	err := tx.First(&tag, toSave.TagID).Error
+	if err != nil {
+		return err
+	}
+
+	var transaction Transaction
+	err = tx.Preload("SourceAccount").First(&transaction, toSave.TransactionID).Error
+	if err != nil {
+		return err
+	}
+
+	if tag.BudgetID != transaction.SourceAccount.BudgetID {
+		return ErrTransactionTagBudget
+	}
+
+	return nil
+}
+
+// TransactionTagIDs returns the IDs of the tags of a transaction, sorted by the tag name.
+func TransactionTagIDs(db *gorm.DB, transactionID uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	err := db.
+		Model(&TransactionTag{}).
+		Joins("JOIN tags ON tags.id = transaction_tags.tag_id").
+		Where("transaction_tags.transaction_id = ?", transactionID).
+		Order("tags.name ASC").
+		Pluck("transaction_tags.tag_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// SetTransactionTags sets the tags of a transaction to exactly the tags with the IDs.
+//
+// Links to tags that are not in tagIDs anymore are deleted, links to new tags are created.
+func SetTransactionTags(db *gorm.DB, transactionID uuid.UUID, tagIDs []uuid.UUID) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var links []TransactionTag
+		err := tx.Where(&TransactionTag{TransactionID: transactionID}).Find(&links).Error
+		if err != nil {
+			return err
+		}
+
+		existing := make(map[uuid.UUID]bool, len(links))
+		for _, link := range links {
+			if slices.Contains(tagIDs, link.TagID) {
+				existing[link.TagID] = true
+				continue
+			}
+
+			err = tx.Delete(&link).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, id := range tagIDs {
+			if existing[id] {
+				continue
+			}
+
+			err = tx.Create(&TransactionTag{TransactionID: transactionID, TagID: id}).Error
+			if err != nil {
+				return err
+			}
+			existing[id] = true
+		}
+
+		return nil
+	})
+}
+
+// Returns all tags for export. If budgetID is set, only tags of that budget are returned.
+func (Tag) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[Tag](tagBudgetScope(budgetID))
+}
+
+// Table returns all tags for export as a table.
+func (Tag) Table(budgetID *uuid.UUID) (Table, error) {
+	var tags []Tag
+	err := DB.Scopes(tagBudgetScope(budgetID)).Preload("Budget").Order("tags.name ASC").Find(&tags).Error
+	if err != nil {
+		return Table{}, err
+	}
+
+	table := Table{
+		Name:   "Tags",
+		Header: []string{"ID", "Budget", "Name", "Note"},
+	}
+
+	for _, t := range tags {
+		table.Rows = append(table.Rows, []any{t.ID.String(), t.Budget.Name, t.Name, t.Note})
+	}
+
+	return table, nil
+}
+
+func tagBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "tags.budget_id")
+}
+
+// Returns all links between transactions and tags for export. If budgetID is set, only links of that budget are returned.
+func (TransactionTag) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[TransactionTag](transactionTagBudgetScope(budgetID))
+}
+
+// Table returns all links between transactions and tags for export as a table.
+func (TransactionTag) Table(budgetID *uuid.UUID) (Table, error) {
+	var links []TransactionTag
+	err := DB.
+		Scopes(transactionTagBudgetScope(budgetID)).
+		Preload("Tag").
+		Preload("Transaction").
+		Joins("JOIN transactions AS sort_transactions ON sort_transactions.id = transaction_tags.transaction_id").
+		Joins("JOIN tags AS sort_tags ON sort_tags.id = transaction_tags.tag_id").
+		Order("sort_transactions.date ASC, sort_transactions.created_at ASC, sort_tags.name ASC").
+		Find(&links).Error
+	if err != nil {
+		return Table{}, err
+	}
+
+	table := Table{
+		Name:   "Transaction Tags",
+		Header: []string{"Transaction ID", "Date", "Tag"},
+	}
+
+	for _, l := range links {
+		table.Rows = append(table.Rows, []any{l.TransactionID.String(), l.Transaction.Date, l.Tag.Name})
+	}
+
+	return table, nil
+}
+
+func transactionTagBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "tags.budget_id", "JOIN tags ON tags.id = transaction_tags.tag_id")
+}
@@ -0,0 +1,128 @@
+package models_test
+
+import (
+	"strings"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) TestTagTrimWhitespace() {
+	name := "\t Whitespace galore!   "
+	note := " Some more whitespace in the notes    "
+
+	tag := suite.createTestTag(models.Tag{
+		Name:     name,
+		Note:     note,
+		BudgetID: suite.createTestBudget(models.Budget{}).ID,
+	})
+
+	suite.Assert().Equal(strings.TrimSpace(name), tag.Name)
+	suite.Assert().Equal(strings.TrimSpace(note), tag.Note)
+}
+
+func (suite *TestSuiteStandard) TestTagNameNotUnique() {
+	budget := suite.createTestBudget(models.Budget{})
+	_ = suite.createTestTag(models.Tag{BudgetID: budget.ID, Name: "Vacation"})
+
+	err := models.DB.Create(&models.Tag{BudgetID: budget.ID, Name: "Vacation"}).Error
+	suite.Assert().ErrorIs(err, models.ErrTagNameNotUnique)
+
+	// The same name can be used in another budget
+	_ = suite.createTestTag(models.Tag{BudgetID: suite.createTestBudget(models.Budget{}).ID, Name: "Vacation"})
+}
+
+func (suite *TestSuiteStandard) TestSetTransactionTags() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: bank.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(10)})
+
+	vacation := suite.createTestTag(models.Tag{BudgetID: budget.ID, Name: "Vacation"})
+	tax := suite.createTestTag(models.Tag{BudgetID: budget.ID, Name: "Tax deductible"})
+	other := suite.createTestTag(models.Tag{BudgetID: suite.createTestBudget(models.Budget{}).ID, Name: "Other"})
+
+	err := models.SetTransactionTags(models.DB, transaction.ID, []uuid.UUID{vacation.ID, tax.ID, vacation.ID})
+	suite.Require().Nil(err)
+
+	ids, err := models.TransactionTagIDs(models.DB, transaction.ID)
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]uuid.UUID{tax.ID, vacation.ID}, ids)
+
+	err = models.SetTransactionTags(models.DB, transaction.ID, []uuid.UUID{vacation.ID})
+	suite.Require().Nil(err)
+
+	ids, err = models.TransactionTagIDs(models.DB, transaction.ID)
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]uuid.UUID{vacation.ID}, ids)
+
+	// Tags of other budgets fail and the existing tags are kept
+	err = models.SetTransactionTags(models.DB, transaction.ID, []uuid.UUID{other.ID})
+	suite.Assert().ErrorIs(err, models.ErrTransactionTagBudget)
+
+	ids, err = models.TransactionTagIDs(models.DB, transaction.ID)
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]uuid.UUID{vacation.ID}, ids)
+
+	// Tags cannot be moved to another budget while they are in use
+	err = models.DB.Model(&vacation).Select("BudgetID").Updates(models.Tag{BudgetID: other.BudgetID}).Error
+	suite.Assert().ErrorIs(err, models.ErrTransactionTagBudget)
+
+	err = models.DB.Model(&tax).Select("BudgetID").Updates(models.Tag{BudgetID: other.BudgetID}).Error
+	suite.Assert().Nil(err)
+}
+
+func (suite *TestSuiteStandard) TestTrashRestoreTagTransactionDeleted() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: bank.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(10)})
+	kept := suite.createTestTransaction(models.Transaction{SourceAccountID: bank.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(20)})
+	tag := suite.createTestTag(models.Tag{BudgetID: budget.ID, Name: "Vacation"})
+
+	suite.Require().Nil(models.SetTransactionTags(models.DB, transaction.ID, []uuid.UUID{tag.ID}))
+	suite.Require().Nil(models.SetTransactionTags(models.DB, kept.ID, []uuid.UUID{tag.ID}))
+
+	entry, _, err := models.MoveToTrash(models.DB, &tag)
+	suite.Require().Nil(err)
+	suite.Require().Len(entry.Resources, 3)
+
+	_, _, err = models.MoveToTrash(models.DB, &transaction)
+	suite.Require().Nil(err)
+
+	// The tag is restored, but only the link to the transaction that still exists
+	err = entry.Restore(models.DB)
+	suite.Require().Nil(err)
+
+	ids, err := models.TransactionTagIDs(models.DB, kept.ID)
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]uuid.UUID{tag.ID}, ids)
+
+	var count int64
+	suite.Require().Nil(models.DB.Model(&models.TransactionTag{}).Where(&models.TransactionTag{TagID: tag.ID}).Count(&count).Error)
+	suite.Assert().Equal(int64(1), count)
+}
+
+func (suite *TestSuiteStandard) TestBudgetCloneTags() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true, Name: "Bank"})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, Name: "Shop"})
+	transaction := suite.createTestTransaction(models.Transaction{SourceAccountID: bank.ID, DestinationAccountID: shop.ID, Amount: decimal.NewFromFloat(10)})
+	tag := suite.createTestTag(models.Tag{BudgetID: budget.ID, Name: "Vacation", Note: "Lisbon"})
+	suite.Require().Nil(models.SetTransactionTags(models.DB, transaction.ID, []uuid.UUID{tag.ID}))
+
+	clone, err := budget.Clone(models.DB, "Clone", models.CloneOptions{Transactions: true})
+	suite.Require().Nil(err)
+
+	var cloned models.Tag
+	suite.Require().Nil(models.DB.Where(&models.Tag{BudgetID: clone.ID}).First(&cloned).Error)
+	suite.Assert().NotEqual(tag.ID, cloned.ID)
+	suite.Assert().Equal("Vacation", cloned.Name)
+	suite.Assert().Equal("Lisbon", cloned.Note)
+
+	var links []models.TransactionTag
+	suite.Require().Nil(models.DB.Where(&models.TransactionTag{TagID: cloned.ID}).Find(&links).Error)
+	suite.Require().Len(links, 1)
+	suite.Assert().NotEqual(transaction.ID, links[0].TransactionID)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/storage"
 	"github.com/envelope-zero/backend/v7/test"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
@@ -37,6 +38,8 @@ func (suite *TestSuiteStandard) SetupTest() {
 	if err != nil {
 		log.Fatalf("Database connection failed with: %#v", err)
 	}
+
+	models.Storage = storage.Local{Directory: suite.T().TempDir()}
 }
 
 // CloseDB closes the database connection. This enables testing the handling
@@ -124,3 +127,12 @@ func (suite *TestSuiteStandard) createTestGoal(goal models.Goal) models.Goal {
 
 	return goal
 }
+
+func (suite *TestSuiteStandard) createTestTag(tag models.Tag) models.Tag {
+	err := models.DB.Create(&tag).Error
+	if err != nil {
+		suite.Assert().FailNow("Tag could not be saved", "Error: %s, Tag: %#v", err, tag)
+	}
+
+	return tag
+}
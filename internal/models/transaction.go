@@ -24,7 +24,10 @@ type Transaction struct {
 	Envelope              Envelope        `json:"-"`
 	Date                  time.Time       // Time of day is currently only used for sorting
 	Amount                decimal.Decimal `gorm:"type:DECIMAL(20,8)"`
+	DestinationAmount     decimal.Decimal `gorm:"type:DECIMAL(20,8)"` // Amount in the currency of the destination account. Only set if the currencies of the accounts differ
 	Note                  string
+	ClearedSource         bool        // Has the transaction cleared in the source account, e.g. is it on the bank statement?
+	ClearedDestination    bool        // Has the transaction cleared in the destination account, e.g. is it on the bank statement?
 	ReconciledSource      bool        // Is the transaction reconciled in the source account?
 	ReconciledDestination bool        // Is the transaction reconciled in the destination account?
 	AvailableFrom         types.Month // Only used for income transactions. Defaults to the transaction date.
@@ -39,8 +42,59 @@ var (
 	ErrTransactionTransferBetweenOnBudgetWithEnvelope = errors.New("transfers between two on-budget accounts must not have an envelope set. Such a transaction would be incoming and outgoing for this envelope at the same time, which is not possible")
 	ErrTransactionInvalidSourceAccount                = errors.New("invalid source account")
 	ErrTransactionInvalidDestinationAccount           = errors.New("invalid destination account")
+	ErrTransactionReconciled                          = errors.New("the transaction is reconciled and can not be changed. Set it to not reconciled in the same request to change it")
+	ErrTransactionDestinationAmountMissing            = errors.New("transactions between accounts with different currencies need a positive destination amount")
+	ErrTransactionDestinationAmountSameCurrency       = errors.New("the destination amount can only be set for transactions between accounts with different currencies")
 )
 
+// TransactionState is the state of a transaction in one of its accounts.
+type TransactionState string
+
+const (
+	TransactionStateUncleared  TransactionState = "UNCLEARED"
+	TransactionStateCleared    TransactionState = "CLEARED"
+	TransactionStateReconciled TransactionState = "RECONCILED"
+)
+
+// transactionLockedFields are the fields that can not be changed while
+// the transaction is reconciled.
+var transactionLockedFields = []string{"SourceAccountID", "DestinationAccountID", "EnvelopeID", "Date", "Amount", "DestinationAmount", "Note", "AvailableFrom", "ImportHash"}
+
+// SourceState returns the state of the transaction in the source account.
+//
+// Reconciled transactions are always cleared.
+func (t Transaction) SourceState() TransactionState {
+	return transactionState(t.ClearedSource, t.ReconciledSource)
+}
+
+// DestinationState returns the state of the transaction in the destination account.
+//
+// Reconciled transactions are always cleared.
+func (t Transaction) DestinationState() TransactionState {
+	return transactionState(t.ClearedDestination, t.ReconciledDestination)
+}
+
+// Received returns the amount that the destination account receives in its currency.
+func (t Transaction) Received() decimal.Decimal {
+	if t.DestinationAmount.IsPositive() {
+		return t.DestinationAmount
+	}
+
+	return t.Amount
+}
+
+func transactionState(cleared, reconciled bool) TransactionState {
+	if reconciled {
+		return TransactionStateReconciled
+	}
+
+	if cleared {
+		return TransactionStateCleared
+	}
+
+	return TransactionStateUncleared
+}
+
 func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 	_ = t.DefaultModel.BeforeCreate(tx)
 
@@ -62,12 +116,27 @@ func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 		return fmt.Errorf("%w: %w", ErrTransactionInvalidDestinationAccount, err)
 	}
 
+	err = checkCurrencies(tx, source, destination, toSave.DestinationAmount)
+	if err != nil {
+		return err
+	}
+
+	// Outgoing transactions to a payee without an envelope use the default envelope of the payee
+	if toSave.EnvelopeID == nil && source.OnBudget && destination.DefaultEnvelopeID != nil {
+		toSave.EnvelopeID = destination.DefaultEnvelopeID
+	}
+
 	return t.checkIntegrity(tx, *toSave, source, destination)
 }
 
 func (t *Transaction) BeforeUpdate(tx *gorm.DB) (err error) {
 	toSave := tx.Statement.Dest.(Transaction)
 
+	err = t.checkLocked(tx, toSave)
+	if err != nil {
+		return err
+	}
+
 	if tx.Statement.Changed("Amount") && !decimal.Decimal.IsPositive(toSave.Amount) {
 		return ErrTransactionAmountNotPositive
 	}
@@ -96,7 +165,88 @@ func (t *Transaction) BeforeUpdate(tx *gorm.DB) (err error) {
 		return fmt.Errorf("%w: %w", ErrTransactionInvalidDestinationAccount, err)
 	}
 
-	return t.checkIntegrity(tx, toSave, source, destination)
+	destinationAmount := t.DestinationAmount
+	if tx.Statement.Changed("DestinationAmount") {
+		destinationAmount = toSave.DestinationAmount
+	}
+
+	err = checkCurrencies(tx, source, destination, destinationAmount)
+	if err != nil {
+		return err
+	}
+
+	err = t.checkIntegrity(tx, toSave, source, destination)
+	if err != nil {
+		return err
+	}
+
+	// Invalidate the balance snapshots for the envelope before and after the update
+	envelopeID := t.EnvelopeID
+	if tx.Statement.Changed("EnvelopeID") {
+		envelopeID = toSave.EnvelopeID
+	}
+
+	date := t.Date
+	if tx.Statement.Changed("Date") {
+		date = toSave.Date
+	}
+
+	err = t.invalidateEnvelopeBalances(tx)
+	if err != nil {
+		return err
+	}
+
+	return (&Transaction{EnvelopeID: envelopeID, Date: date}).invalidateEnvelopeBalances(tx)
+}
+
+// AfterCreate invalidates the balance snapshots of the envelope.
+func (t *Transaction) AfterCreate(tx *gorm.DB) error {
+	return t.invalidateEnvelopeBalances(tx)
+}
+
+// AfterDelete invalidates the balance snapshots of the envelope.
+func (t *Transaction) AfterDelete(tx *gorm.DB) error {
+	return t.invalidateEnvelopeBalances(tx)
+}
+
+// invalidateEnvelopeBalances deletes the balance snapshots of the transaction's
+// envelope from the month of the transaction on.
+func (t *Transaction) invalidateEnvelopeBalances(tx *gorm.DB) error {
+	if t.EnvelopeID == nil || *t.EnvelopeID == uuid.Nil {
+		return nil
+	}
+
+	return invalidateEnvelopeBalances(tx, *t.EnvelopeID, types.MonthOf(t.Date))
+}
+
+// checkLocked verifies that a reconciled transaction is only changed when the
+// update also sets it to not reconciled for all accounts it is reconciled in.
+//
+// Changing only the cleared and reconciled flags is always possible.
+func (t *Transaction) checkLocked(tx *gorm.DB, toSave Transaction) error {
+	if !t.ReconciledSource && !t.ReconciledDestination {
+		return nil
+	}
+
+	changed := false
+	for _, field := range transactionLockedFields {
+		if tx.Statement.Changed(field) {
+			changed = true
+			break
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	unlockedSource := !t.ReconciledSource || (tx.Statement.Changed("ReconciledSource") && !toSave.ReconciledSource)
+	unlockedDestination := !t.ReconciledDestination || (tx.Statement.Changed("ReconciledDestination") && !toSave.ReconciledDestination)
+	if !unlockedSource || !unlockedDestination {
+		return ErrTransactionReconciled
+	}
+
+	return nil
 }
 
 func (t *Transaction) checkIntegrity(tx *gorm.DB, toSave Transaction, source, destination Account) error {
@@ -116,8 +266,32 @@ func (t *Transaction) checkIntegrity(tx *gorm.DB, toSave Transaction, source, de
 	return nil
 }
 
+// checkCurrencies verifies that the destination amount is set if and only if
+// the currencies of the accounts differ.
+func checkCurrencies(tx *gorm.DB, source, destination Account, destinationAmount decimal.Decimal) error {
+	same := source.Currency == destination.Currency
+	if !same {
+		var budget Budget
+		err := tx.First(&budget, source.BudgetID).Error
+		if err != nil {
+			return err
+		}
+		same = sameCurrency(budget.Currency, source.Currency, destination.Currency)
+	}
+
+	if same && !destinationAmount.IsZero() {
+		return ErrTransactionDestinationAmountSameCurrency
+	}
+
+	if !same && !destinationAmount.IsPositive() {
+		return ErrTransactionDestinationAmountMissing
+	}
+
+	return nil
+}
+
 // BeforeSave
-//   - ensures that ReconciledSource and ReconciledDestination are set to valid values
+//   - ensures that the cleared and reconciled flags are set to valid values
 //   - trims whitespace from string fields
 func (t *Transaction) BeforeSave(tx *gorm.DB) (err error) {
 	t.Note = strings.TrimSpace(t.Note)
@@ -140,9 +314,18 @@ func (t *Transaction) BeforeSave(tx *gorm.DB) (err error) {
 		return fmt.Errorf("%w, transaction date: %s, available month %s", ErrAvailabilityMonthTooEarly, t.Date.Format("2006-01-02"), t.AvailableFrom)
 	}
 
-	// Enforce ReconciledSource = false when source account is external
-	// Only verify when ReconciledSource is true as false is always acceptable
-	if t.SourceAccount.ID == uuid.Nil && t.ReconciledSource {
+	// Reconciled transactions are always cleared
+	if t.ReconciledSource {
+		t.ClearedSource = true
+	}
+
+	if t.ReconciledDestination {
+		t.ClearedDestination = true
+	}
+
+	// Enforce ReconciledSource and ClearedSource = false when source account is external
+	// Only verify when one of them is true as false is always acceptable
+	if t.SourceAccount.ID == uuid.Nil && (t.ReconciledSource || t.ClearedSource) {
 		a := Account{}
 		err = tx.Where(&Account{DefaultModel: DefaultModel{ID: t.SourceAccountID}}).First(&a).Error
 		if err != nil {
@@ -151,17 +334,19 @@ func (t *Transaction) BeforeSave(tx *gorm.DB) (err error) {
 
 		if a.External {
 			t.ReconciledSource = false
+			t.ClearedSource = false
 		}
 
 		// We only need to enforce the value if the source account is external,
 		// therefore else if is acceptable here
 	} else if t.SourceAccount.External {
 		t.ReconciledSource = false
+		t.ClearedSource = false
 	}
 
-	// Enforce ReconciledDestination = false when destination account is external
-	// Only verify when ReconciledDestination is true as false is always acceptable
-	if t.DestinationAccount.ID == uuid.Nil && t.ReconciledDestination {
+	// Enforce ReconciledDestination and ClearedDestination = false when destination account is external
+	// Only verify when one of them is true as false is always acceptable
+	if t.DestinationAccount.ID == uuid.Nil && (t.ReconciledDestination || t.ClearedDestination) {
 		a := Account{}
 		err = tx.Where(&Account{DefaultModel: DefaultModel{ID: t.DestinationAccountID}}).First(&a).Error
 		if err != nil {
@@ -170,28 +355,50 @@ func (t *Transaction) BeforeSave(tx *gorm.DB) (err error) {
 
 		if a.External {
 			t.ReconciledDestination = false
+			t.ClearedDestination = false
 		}
 
 		// We only need to enforce the value if the source account is external,
 		// therefore else if is acceptable here
 	} else if t.DestinationAccount.External {
 		t.ReconciledDestination = false
+		t.ClearedDestination = false
 	}
 
 	return err
 }
 
-// Returns all transactions on this instance for export
-func (Transaction) Export() (json.RawMessage, error) {
+// Returns all transactions for export. If budgetID is set, only transactions of that budget are returned.
+func (Transaction) Export(budgetID *uuid.UUID) (json.RawMessage, error) {
+	return export[Transaction](transactionBudgetScope(budgetID))
+}
+
+// Table returns all transactions for export as a table.
+func (Transaction) Table(budgetID *uuid.UUID) (Table, error) {
 	var transactions []Transaction
-	err := DB.Unscoped().Where(&Transaction{}).Find(&transactions).Error
+	err := DB.
+		Scopes(transactionBudgetScope(budgetID)).
+		Preload("SourceAccount").
+		Preload("DestinationAccount").
+		Preload("Envelope.Category").
+		Order("transactions.date ASC, transactions.created_at ASC").
+		Find(&transactions).Error
 	if err != nil {
-		return nil, err
+		return Table{}, err
 	}
 
-	j, err := json.Marshal(&transactions)
-	if err != nil {
-		return json.RawMessage{}, err
+	table := Table{
+		Name:   "Transactions",
+		Header: []string{"ID", "Date", "Source Account", "Destination Account", "Category", "Envelope", "Amount", "Destination Amount", "Note", "Available From", "Cleared Source", "Cleared Destination", "Reconciled Source", "Reconciled Destination"},
 	}
-	return json.RawMessage(j), nil
+
+	for _, t := range transactions {
+		table.Rows = append(table.Rows, []any{t.ID.String(), t.Date, t.SourceAccount.Name, t.DestinationAccount.Name, t.Envelope.Category.Name, t.Envelope.Name, t.Amount, t.DestinationAmount, t.Note, t.AvailableFrom, t.ClearedSource, t.ClearedDestination, t.ReconciledSource, t.ReconciledDestination})
+	}
+
+	return table, nil
+}
+
+func transactionBudgetScope(budgetID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return budgetScope(budgetID, "accounts.budget_id", "JOIN accounts ON accounts.id = transactions.source_account_id")
 }
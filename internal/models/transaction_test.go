@@ -293,7 +293,7 @@ func (suite *TestSuiteStandard) TestTransactionExport() {
 		_ = suite.createTestTransaction(models.Transaction{SourceAccountID: internalAccount.ID, DestinationAccountID: externalAccount.ID, Amount: decimal.NewFromFloat(10)})
 	}
 
-	raw, err := models.Transaction{}.Export()
+	raw, err := models.Transaction{}.Export(nil)
 	if err != nil {
 		require.Fail(t, "transaction export failed", err)
 	}
@@ -306,3 +306,108 @@ func (suite *TestSuiteStandard) TestTransactionExport() {
 
 	require.Len(t, transactions, 2, "number of transactions in export is wrong")
 }
+
+func (suite *TestSuiteStandard) TestTransactionExportBudget() {
+	budget := suite.createTestBudget(models.Budget{})
+	other := suite.createTestBudget(models.Budget{})
+
+	for _, b := range []models.Budget{budget, other} {
+		internalAccount := suite.createTestAccount(models.Account{BudgetID: b.ID})
+		externalAccount := suite.createTestAccount(models.Account{External: true, BudgetID: b.ID})
+		_ = suite.createTestTransaction(models.Transaction{SourceAccountID: internalAccount.ID, DestinationAccountID: externalAccount.ID, Amount: decimal.NewFromFloat(10)})
+	}
+
+	raw, err := models.Transaction{}.Export(&budget.ID)
+	suite.Require().Nil(err)
+
+	var transactions []models.Transaction
+	suite.Require().Nil(json.Unmarshal(raw, &transactions))
+	suite.Require().Len(transactions, 1, "only transactions of the budget must be exported")
+}
+
+func (suite *TestSuiteStandard) TestTransactionTable() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Bank"})
+	external := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID, Name: "Daily"})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Groceries"})
+
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      account.ID,
+		DestinationAccountID: external.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(12.5),
+		Note:                 "Weekly shopping",
+	})
+
+	table, err := models.Transaction{}.Table(&budget.ID)
+	suite.Require().Nil(err)
+	suite.Require().Len(table.Rows, 1)
+	suite.Require().Len(table.Rows[0], len(table.Header))
+
+	row := make(map[string]any)
+	for i, column := range table.Header {
+		row[column] = table.Rows[0][i]
+	}
+
+	suite.Assert().Equal("Bank", row["Source Account"])
+	suite.Assert().Equal("Shop", row["Destination Account"])
+	suite.Assert().Equal("Daily", row["Category"])
+	suite.Assert().Equal("Groceries", row["Envelope"])
+	suite.Assert().Equal("Weekly shopping", row["Note"])
+	suite.Assert().True(decimal.NewFromFloat(12.5).Equal(row["Amount"].(decimal.Decimal)))
+}
+
+func (suite *TestSuiteStandard) TestTransactionState() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Bank"})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Shop", External: true})
+
+	transaction := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:       bank.ID,
+		DestinationAccountID:  shop.ID,
+		Amount:                decimal.NewFromFloat(5),
+		ReconciledSource:      true,
+		ClearedDestination:    true,
+		ReconciledDestination: true,
+	})
+
+	suite.Assert().True(transaction.ClearedSource, "Reconciled transactions must be cleared")
+	suite.Assert().Equal(models.TransactionStateReconciled, transaction.SourceState())
+	suite.Assert().False(transaction.ClearedDestination, "Transactions can not be cleared in external accounts")
+	suite.Assert().Equal(models.TransactionStateUncleared, transaction.DestinationState())
+
+	suite.Assert().Equal(models.TransactionStateCleared, models.Transaction{ClearedSource: true}.SourceState())
+}
+
+func (suite *TestSuiteStandard) TestTransactionReconciledLocked() {
+	budget := suite.createTestBudget(models.Budget{})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Bank"})
+	cash := suite.createTestAccount(models.Account{BudgetID: budget.ID, Name: "Cash"})
+
+	transaction := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:       bank.ID,
+		DestinationAccountID:  cash.ID,
+		Amount:                decimal.NewFromFloat(20),
+		ReconciledSource:      true,
+		ReconciledDestination: true,
+	})
+
+	err := models.DB.Model(&transaction).Select("Amount").Updates(models.Transaction{Amount: decimal.NewFromFloat(25)}).Error
+	suite.Assert().ErrorIs(err, models.ErrTransactionReconciled)
+
+	// Unlocking only one of the reconciled accounts is not enough
+	err = models.DB.Model(&transaction).Select("Amount", "ReconciledSource").Updates(models.Transaction{Amount: decimal.NewFromFloat(25)}).Error
+	suite.Assert().ErrorIs(err, models.ErrTransactionReconciled)
+
+	// Status flags can be changed without unlocking
+	err = models.DB.Model(&transaction).Select("ClearedSource").Updates(models.Transaction{ClearedSource: true}).Error
+	suite.Assert().Nil(err)
+
+	err = models.DB.Model(&transaction).Select("Amount", "ReconciledSource", "ReconciledDestination").Updates(models.Transaction{Amount: decimal.NewFromFloat(25)}).Error
+	suite.Require().Nil(err)
+
+	suite.Require().Nil(models.DB.First(&transaction, transaction.ID).Error)
+	suite.Assert().True(decimal.NewFromFloat(25).Equal(transaction.Amount))
+	suite.Assert().Equal(models.TransactionStateCleared, transaction.SourceState())
+}
@@ -0,0 +1,535 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TrashRetention is the time deleted resources are kept in the trash.
+//
+// If it is 0, deleted resources are not kept.
+var TrashRetention = 30 * 24 * time.Hour
+
+var ErrTrashReferenceNotFound = errors.New("the deleted resource references a resource that does not exist anymore, restore that first")
+
+// TrashEntry holds a deleted resource together with all resources that
+// were deleted with it so that they can be restored until the entry expires.
+type TrashEntry struct {
+	DefaultModel
+	Model            string                `gorm:"index"`     // Name of the model of the deleted resource, e.g. "Envelope"
+	ResourceID       uuid.UUID             `gorm:"index"`     // ID of the deleted resource
+	ExpiresAt        time.Time             `gorm:"index"`     // Time at which the entry is purged
+	Resources        TrashResources        `gorm:"type:text"` // The deleted resources in the order they are restored in
+	DefaultEnvelopes TrashDefaultEnvelopes `gorm:"type:text"` // Default envelopes of accounts that were removed with the deleted envelopes
+}
+
+// TrashResource is a single deleted resource.
+type TrashResource struct {
+	Model string          `json:"model" example:"Transaction"` // Name of the model of the resource
+	Data  json.RawMessage `json:"data"`                        // The resource as it was before it was deleted
+}
+
+// TrashResources is a list of deleted resources. The resource that was
+// deleted is always the first one, followed by its dependent resources.
+type TrashResources []TrashResource
+
+// Scan implements the sql.Scanner interface.
+func (r *TrashResources) Scan(value any) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	case []byte:
+		return json.Unmarshal(v, r)
+	case nil:
+		*r = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into TrashResources", value)
+	}
+}
+
+// Value implements the driver.Valuer interface.
+func (r TrashResources) Value() (driver.Value, error) {
+	j, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(j), nil
+}
+
+// TrashDefaultEnvelopes maps the IDs of accounts to the IDs of their default envelopes.
+type TrashDefaultEnvelopes map[uuid.UUID]uuid.UUID
+
+// Scan implements the sql.Scanner interface.
+func (d *TrashDefaultEnvelopes) Scan(value any) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	case []byte:
+		return json.Unmarshal(v, d)
+	case nil:
+		*d = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into TrashDefaultEnvelopes", value)
+	}
+}
+
+// Value implements the driver.Valuer interface.
+func (d TrashDefaultEnvelopes) Value() (driver.Value, error) {
+	j, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return string(j), nil
+}
+
+// trashOrder is the order in which deleted resources are restored. Models
+// come after all models they reference. Resources are deleted in reverse order.
+var trashOrder = []string{"Budget", "ExchangeRate", "Account", "Category", "Envelope", "Goal", "MatchRule", "MonthConfig", "Reconciliation", "Tag", "Transaction", "TransactionTag", "Attachment"}
+
+// MoveToTrash deletes the resource and all resources depending on it and
+// keeps them in a trash entry.
+//
+// It returns the storage names of attachment files that are not needed anymore. They
+// must only be deleted with DeleteStoredAttachments once the database transaction
+// is committed since the attachments are restored if it is rolled back.
+//
+// resource must be a pointer to a model in the Registry.
+func MoveToTrash(db *gorm.DB, resource any) (entry TrashEntry, files []string, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		c := trashCollector{tx: tx, seen: make(map[string]bool)}
+		err := c.collect(resource)
+		if err != nil {
+			return err
+		}
+
+		slices.SortStableFunc(c.resources, func(a, b TrashResource) int {
+			return slices.Index(trashOrder, a.Model) - slices.Index(trashOrder, b.Model)
+		})
+
+		defaultEnvelopes, err := c.defaultEnvelopes()
+		if err != nil {
+			return err
+		}
+
+		for i := len(c.resources) - 1; i >= 0; i-- {
+			instance, err := c.resources[i].instance()
+			if err != nil {
+				return err
+			}
+
+			err = tx.Delete(instance).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		files, err = PurgeTrash(tx)
+		if err != nil {
+			return err
+		}
+
+		// Without a trash, the files of attachments are not needed anymore
+		if TrashRetention <= 0 {
+			files = append(files, trashedAttachments(c.resources)...)
+			return nil
+		}
+
+		id, _ := reflect.Indirect(reflect.ValueOf(resource)).FieldByName("ID").Interface().(uuid.UUID)
+		entry = TrashEntry{
+			Model:            c.resources[0].Model,
+			ResourceID:       id,
+			ExpiresAt:        time.Now().Add(TrashRetention),
+			Resources:        c.resources,
+			DefaultEnvelopes: defaultEnvelopes,
+		}
+
+		return tx.Create(&entry).Error
+	})
+	if err != nil {
+		return TrashEntry{}, nil, err
+	}
+
+	return entry, files, nil
+}
+
+// Restore creates all resources of the trash entry again and removes the entry.
+//
+// Resources are restored as they were when they were deleted, including their IDs.
+func (t TrashEntry) Restore(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var accounts []*Account
+		for _, resource := range t.Resources {
+			instance, err := resource.instance()
+			if err != nil {
+				return err
+			}
+
+			err = checkTrashReferences(tx, instance)
+
+			// The tag of a transaction might have been deleted after the transaction
+			// or the other way around. Links are only restored if both exist.
+			if _, ok := instance.(*TransactionTag); ok && errors.Is(err, ErrTrashReferenceNotFound) {
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			// Hooks are skipped since they generate new IDs and validate
+			// the resource as if it was new
+			err = tx.Session(&gorm.Session{SkipHooks: true}).Create(instance).Error
+			if err != nil {
+				return err
+			}
+
+			switch r := instance.(type) {
+			case *Account:
+				accounts = append(accounts, r)
+			case *Transaction:
+				err = r.invalidateEnvelopeBalances(tx)
+			case *MonthConfig:
+				err = invalidateEnvelopeBalances(tx, r.EnvelopeID, r.Month)
+			case *ExchangeRate:
+				err = invalidateBudgetEnvelopeBalances(tx, r.BudgetID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// The default envelope of an account might have been deleted
+		// after the account. Such accounts are restored without one.
+		for _, a := range accounts {
+			if a.DefaultEnvelopeID == nil {
+				continue
+			}
+
+			var count int64
+			err := tx.Model(&Envelope{}).Where("id = ?", a.DefaultEnvelopeID).Count(&count).Error
+			if err != nil {
+				return err
+			}
+
+			if count == 0 {
+				err = tx.Model(a).UpdateColumn("default_envelope_id", nil).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// Accounts get their default envelope back unless they have a new one by now
+		for accountID, envelopeID := range t.DefaultEnvelopes {
+			err := tx.Model(&Account{}).Where("id = ? AND default_envelope_id IS NULL", accountID).UpdateColumn("default_envelope_id", envelopeID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&t).Error
+	})
+}
+
+// PurgeTrash permanently deletes all expired trash entries.
+//
+// It returns the storage names of the files of the attachments in them, which
+// must be deleted with DeleteStoredAttachments once the database transaction is committed.
+func PurgeTrash(db *gorm.DB) ([]string, error) {
+	var entries []TrashEntry
+	err := db.Where("expires_at <= ?", time.Now()).Find(&entries).Error
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+
+	err = db.Delete(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		files = append(files, trashedAttachments(entry.Resources)...)
+	}
+
+	return files, nil
+}
+
+// instance returns a pointer to a new instance of the model of the resource
+// with the data of the resource.
+func (r TrashResource) instance() (any, error) {
+	for _, model := range Registry {
+		t := reflect.TypeOf(model)
+		if t.Name() != r.Model {
+			continue
+		}
+
+		instance := reflect.New(t).Interface()
+		err := json.Unmarshal(r.Data, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		return instance, nil
+	}
+
+	return nil, fmt.Errorf("trash: unknown model %s", r.Model)
+}
+
+// trashCollector collects a resource and all resources depending on it.
+type trashCollector struct {
+	tx        *gorm.DB
+	resources TrashResources
+	seen      map[string]bool
+}
+
+// add adds the resource to the collected resources.
+// It returns false if the resource has already been collected.
+func (c *trashCollector) add(resource any) (bool, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return false, err
+	}
+
+	model := reflect.Indirect(reflect.ValueOf(resource)).Type().Name()
+	key := model + string(data)
+	if c.seen[key] {
+		return false, nil
+	}
+	c.seen[key] = true
+
+	c.resources = append(c.resources, TrashResource{Model: model, Data: data})
+	return true, nil
+}
+
+// collect adds the resource and recursively all resources depending on it.
+func (c *trashCollector) collect(resource any) error {
+	added, err := c.add(resource)
+	if err != nil || !added {
+		return err
+	}
+
+	var dependents []any
+	switch r := resource.(type) {
+	case *Budget:
+		var accounts []Account
+		err = c.tx.Where(&Account{BudgetID: r.ID}).Find(&accounts).Error
+		for i := range accounts {
+			dependents = append(dependents, &accounts[i])
+		}
+
+		var categories []Category
+		if err == nil {
+			err = c.tx.Where(&Category{BudgetID: r.ID}).Find(&categories).Error
+		}
+		for i := range categories {
+			dependents = append(dependents, &categories[i])
+		}
+
+		var rates []ExchangeRate
+		if err == nil {
+			err = c.tx.Where(&ExchangeRate{BudgetID: r.ID}).Find(&rates).Error
+		}
+		for i := range rates {
+			dependents = append(dependents, &rates[i])
+		}
+
+		var tags []Tag
+		if err == nil {
+			err = c.tx.Where(&Tag{BudgetID: r.ID}).Find(&tags).Error
+		}
+		for i := range tags {
+			dependents = append(dependents, &tags[i])
+		}
+
+	case *Account:
+		var matchRules []MatchRule
+		err = c.tx.Where(&MatchRule{AccountID: r.ID}).Find(&matchRules).Error
+		for i := range matchRules {
+			dependents = append(dependents, &matchRules[i])
+		}
+
+		var reconciliations []Reconciliation
+		if err == nil {
+			err = c.tx.Where(&Reconciliation{AccountID: r.ID}).Find(&reconciliations).Error
+		}
+		for i := range reconciliations {
+			dependents = append(dependents, &reconciliations[i])
+		}
+
+		var transactions []Transaction
+		if err == nil {
+			err = c.tx.Where("source_account_id = ? OR destination_account_id = ?", r.ID, r.ID).Find(&transactions).Error
+		}
+		for i := range transactions {
+			dependents = append(dependents, &transactions[i])
+		}
+
+	case *Category:
+		var envelopes []Envelope
+		err = c.tx.Where(&Envelope{CategoryID: r.ID}).Find(&envelopes).Error
+		for i := range envelopes {
+			dependents = append(dependents, &envelopes[i])
+		}
+
+	case *Envelope:
+		var goals []Goal
+		err = c.tx.Where(&Goal{EnvelopeID: r.ID}).Find(&goals).Error
+		for i := range goals {
+			dependents = append(dependents, &goals[i])
+		}
+
+		var monthConfigs []MonthConfig
+		if err == nil {
+			err = c.tx.Where(&MonthConfig{EnvelopeID: r.ID}).Find(&monthConfigs).Error
+		}
+		for i := range monthConfigs {
+			dependents = append(dependents, &monthConfigs[i])
+		}
+
+		var transactions []Transaction
+		if err == nil {
+			err = c.tx.Where("envelope_id = ?", r.ID).Find(&transactions).Error
+		}
+		for i := range transactions {
+			dependents = append(dependents, &transactions[i])
+		}
+
+	case *Tag:
+		var links []TransactionTag
+		err = c.tx.Where(&TransactionTag{TagID: r.ID}).Find(&links).Error
+		for i := range links {
+			dependents = append(dependents, &links[i])
+		}
+
+	case *Transaction:
+		var links []TransactionTag
+		err = c.tx.Where(&TransactionTag{TransactionID: r.ID}).Find(&links).Error
+		for i := range links {
+			dependents = append(dependents, &links[i])
+		}
+
+		var attachments []Attachment
+		if err == nil {
+			err = c.tx.Where(&Attachment{TransactionID: r.ID}).Find(&attachments).Error
+		}
+		for i := range attachments {
+			dependents = append(dependents, &attachments[i])
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, dependent := range dependents {
+		err = c.collect(dependent)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// defaultEnvelopes returns the accounts that have one of the collected envelopes as default envelope.
+func (c *trashCollector) defaultEnvelopes() (TrashDefaultEnvelopes, error) {
+	var ids []uuid.UUID
+	for _, resource := range c.resources {
+		if resource.Model != "Envelope" {
+			continue
+		}
+
+		instance, err := resource.instance()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, instance.(*Envelope).ID)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var accounts []Account
+	err := c.tx.Where("default_envelope_id IN ?", ids).Find(&accounts).Error
+	if err != nil || len(accounts) == 0 {
+		return nil, err
+	}
+
+	defaultEnvelopes := make(TrashDefaultEnvelopes, len(accounts))
+	for _, a := range accounts {
+		defaultEnvelopes[a.ID] = *a.DefaultEnvelopeID
+	}
+
+	return defaultEnvelopes, nil
+}
+
+// checkTrashReferences verifies that all resources referenced by a
+// resource that is restored exist.
+func checkTrashReferences(tx *gorm.DB, resource any) error {
+	references := map[string]uuid.UUID{}
+	switch r := resource.(type) {
+	case *Account:
+		references["budgets"] = r.BudgetID
+	case *Category:
+		references["budgets"] = r.BudgetID
+	case *Envelope:
+		references["categories"] = r.CategoryID
+	case *ExchangeRate:
+		references["budgets"] = r.BudgetID
+	case *Goal:
+		references["envelopes"] = r.EnvelopeID
+	case *MonthConfig:
+		references["envelopes"] = r.EnvelopeID
+	case *MatchRule:
+		references["accounts"] = r.AccountID
+	case *Reconciliation:
+		references["accounts"] = r.AccountID
+	case *Tag:
+		references["budgets"] = r.BudgetID
+	case *TransactionTag:
+		references["transactions"] = r.TransactionID
+		references["tags"] = r.TagID
+	case *Attachment:
+		references["transactions"] = r.TransactionID
+	case *Transaction:
+		// Source and destination are both accounts, so they are checked with one query
+		if r.EnvelopeID != nil {
+			references["envelopes"] = *r.EnvelopeID
+		}
+
+		var count int64
+		err := tx.Table("accounts").Where("id IN (?)", []uuid.UUID{r.SourceAccountID, r.DestinationAccountID}).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count != 2 {
+			return ErrTrashReferenceNotFound
+		}
+	}
+
+	for table, id := range references {
+		var count int64
+		err := tx.Table(table).Where("id = ?", id).Count(&count).Error
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			return ErrTrashReferenceNotFound
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+func (suite *TestSuiteStandard) TestTrashEnvelope() {
+	budget := suite.createTestBudget(models.Budget{})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID, Name: "Groceries"})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+
+	transaction := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      bank.ID,
+		DestinationAccountID: shop.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(13.37),
+		Date:                 time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+		Note:                 "Weekly shopping",
+	})
+	monthConfig := suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: types.NewMonth(2024, 2), Allocation: decimal.NewFromFloat(50)})
+	goal := suite.createTestGoal(models.Goal{EnvelopeID: envelope.ID, Name: "Buffer", Amount: decimal.NewFromFloat(100)})
+
+	entry, _, err := models.MoveToTrash(models.DB, &envelope)
+	suite.Require().Nil(err)
+	suite.Assert().Equal("Envelope", entry.Model)
+	suite.Assert().Equal(envelope.ID, entry.ResourceID)
+	suite.Require().Len(entry.Resources, 4)
+	suite.Assert().Equal("Envelope", entry.Resources[0].Model, "The deleted resource must be restored first")
+
+	suite.Assert().ErrorIs(models.DB.First(&models.Envelope{}, envelope.ID).Error, models.ErrResourceNotFound)
+	suite.Assert().ErrorIs(models.DB.First(&models.Transaction{}, transaction.ID).Error, models.ErrResourceNotFound)
+	suite.Assert().ErrorIs(models.DB.First(&models.Goal{}, goal.ID).Error, models.ErrResourceNotFound)
+	suite.Assert().ErrorIs(models.DB.Where(&models.MonthConfig{EnvelopeID: envelope.ID}).First(&models.MonthConfig{}).Error, models.ErrResourceNotFound)
+
+	err = entry.Restore(models.DB)
+	suite.Require().Nil(err)
+
+	var restored models.Transaction
+	suite.Require().Nil(models.DB.First(&restored, transaction.ID).Error)
+	suite.Assert().True(transaction.Amount.Equal(restored.Amount))
+	suite.Assert().Equal(transaction.Note, restored.Note)
+	suite.Assert().Equal(envelope.ID, *restored.EnvelopeID)
+
+	var restoredMonthConfig models.MonthConfig
+	suite.Require().Nil(models.DB.Where(&models.MonthConfig{EnvelopeID: envelope.ID, Month: monthConfig.Month}).First(&restoredMonthConfig).Error)
+	suite.Assert().True(monthConfig.Allocation.Equal(restoredMonthConfig.Allocation))
+
+	suite.Assert().Nil(models.DB.First(&models.Goal{}, goal.ID).Error)
+	suite.Assert().ErrorIs(models.DB.First(&models.TrashEntry{}, entry.ID).Error, models.ErrResourceNotFound, "The trash entry must be removed after restoring")
+}
+
+func (suite *TestSuiteStandard) TestTrashBudget() {
+	budget := suite.createTestBudget(models.Budget{})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+	bank := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, DefaultEnvelopeID: &envelope.ID})
+	_ = suite.createTestMatchRule(models.MatchRule{AccountID: shop.ID, Match: "Shop*"})
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      bank.ID,
+		DestinationAccountID: shop.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(10),
+	})
+
+	entry, _, err := models.MoveToTrash(models.DB, &budget)
+	suite.Require().Nil(err)
+
+	// Budget, 2 accounts, category, envelope, match rule and the transaction,
+	// which depends on both accounts and the envelope, but is only kept once
+	suite.Assert().Len(entry.Resources, 7)
+
+	var count int64
+	suite.Require().Nil(models.DB.Model(&models.Account{}).Count(&count).Error)
+	suite.Assert().Equal(int64(0), count)
+
+	err = entry.Restore(models.DB)
+	suite.Require().Nil(err)
+
+	suite.Require().Nil(models.DB.Model(&models.Transaction{}).Count(&count).Error)
+	suite.Assert().Equal(int64(1), count)
+	suite.Require().Nil(models.DB.Model(&models.MatchRule{}).Count(&count).Error)
+	suite.Assert().Equal(int64(1), count)
+
+	var restoredShop models.Account
+	suite.Require().Nil(models.DB.First(&restoredShop, shop.ID).Error)
+	suite.Require().NotNil(restoredShop.DefaultEnvelopeID, "The default envelope must be kept when it is restored, too")
+	suite.Assert().Equal(envelope.ID, *restoredShop.DefaultEnvelopeID)
+}
+
+func (suite *TestSuiteStandard) TestTrashRestoreDefaultEnvelopeDeleted() {
+	budget := suite.createTestBudget(models.Budget{})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, DefaultEnvelopeID: &envelope.ID})
+
+	entry, _, err := models.MoveToTrash(models.DB, &shop)
+	suite.Require().Nil(err)
+
+	_, _, err = models.MoveToTrash(models.DB, &envelope)
+	suite.Require().Nil(err)
+
+	err = entry.Restore(models.DB)
+	suite.Require().Nil(err)
+
+	var restored models.Account
+	suite.Require().Nil(models.DB.First(&restored, shop.ID).Error)
+	suite.Assert().Nil(restored.DefaultEnvelopeID)
+}
+
+func (suite *TestSuiteStandard) TestTrashRestorePayeeDefaultEnvelope() {
+	budget := suite.createTestBudget(models.Budget{})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+	shop := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true, DefaultEnvelopeID: &envelope.ID})
+
+	entry, _, err := models.MoveToTrash(models.DB, &envelope)
+	suite.Require().Nil(err)
+
+	var account models.Account
+	suite.Require().Nil(models.DB.First(&account, shop.ID).Error)
+	suite.Assert().Nil(account.DefaultEnvelopeID, "The deleted envelope must be removed as default envelope")
+
+	err = entry.Restore(models.DB)
+	suite.Require().Nil(err)
+
+	suite.Require().Nil(models.DB.First(&account, shop.ID).Error)
+	suite.Require().NotNil(account.DefaultEnvelopeID, "The default envelope must be set again when the envelope is restored")
+	suite.Assert().Equal(envelope.ID, *account.DefaultEnvelopeID)
+}
+
+func (suite *TestSuiteStandard) TestTrashRestoreReferenceNotFound() {
+	budget := suite.createTestBudget(models.Budget{})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+
+	envelopeEntry, _, err := models.MoveToTrash(models.DB, &envelope)
+	suite.Require().Nil(err)
+
+	_, _, err = models.MoveToTrash(models.DB, &category)
+	suite.Require().Nil(err)
+
+	err = envelopeEntry.Restore(models.DB)
+	suite.Assert().ErrorIs(err, models.ErrTrashReferenceNotFound)
+	suite.Assert().Nil(models.DB.First(&models.TrashEntry{}, envelopeEntry.ID).Error, "The trash entry must be kept when restoring fails")
+}
+
+func (suite *TestSuiteStandard) TestTrashRestoreAtomic() {
+	budget := suite.createTestBudget(models.Budget{})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID, Name: "Bills"})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+
+	entry, _, err := models.MoveToTrash(models.DB, &category)
+	suite.Require().Nil(err)
+
+	_ = suite.createTestCategory(models.Category{BudgetID: budget.ID, Name: "Bills"})
+
+	err = entry.Restore(models.DB)
+	suite.Assert().ErrorIs(err, models.ErrCategoryNameNotUnique)
+	suite.Assert().ErrorIs(models.DB.First(&models.Envelope{}, envelope.ID).Error, models.ErrResourceNotFound, "No resource must be restored when restoring fails")
+}
+
+func (suite *TestSuiteStandard) TestTrashRetention() {
+	retention := models.TrashRetention
+	defer func() { models.TrashRetention = retention }()
+
+	budget := suite.createTestBudget(models.Budget{})
+	expired := models.TrashEntry{Model: "Budget", ResourceID: budget.ID, ExpiresAt: time.Now().Add(-time.Minute)}
+	suite.Require().Nil(models.DB.Create(&expired).Error)
+
+	models.TrashRetention = 0
+	entry, _, err := models.MoveToTrash(models.DB, &budget)
+	suite.Require().Nil(err)
+	suite.Assert().Equal(models.TrashEntry{}, entry, "No trash entry must be created without retention")
+
+	var count int64
+	suite.Require().Nil(models.DB.Model(&models.TrashEntry{}).Count(&count).Error)
+	suite.Assert().Equal(int64(0), count, "Expired trash entries must be purged")
+}
@@ -0,0 +1,606 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// WebhookEvent is the kind of change a webhook is notified about.
+//
+// For every model in the Registry, there are events for created, updated and
+// deleted resources, e.g. "transaction.created" or "monthConfig.updated".
+type WebhookEvent string
+
+// WebhookEventEnvelopeNegative is sent when the balance of an envelope in the
+// month of a change to a transaction or allocation drops below zero.
+const WebhookEventEnvelopeNegative WebhookEvent = "envelope.negative"
+
+var (
+	ErrWebhookURLInvalid     = errors.New("the webhook URL must be an absolute http or https URL")
+	ErrWebhookEventsEmpty    = errors.New("a webhook must subscribe to at least one event")
+	ErrWebhookEventUnknown   = errors.New("unknown webhook event")
+	ErrWebhookSecretNotValid = errors.New("the webhook secret must not be empty")
+)
+
+// webhookEvent returns the event for a change to a resource of the model.
+func webhookEvent(model string, action AuditAction) WebhookEvent {
+	return WebhookEvent(changeEvent(model, action))
+}
+
+// WebhookEvents returns all events that webhooks can subscribe to, sorted by name.
+func WebhookEvents() []WebhookEvent {
+	events := []WebhookEvent{WebhookEventEnvelopeNegative}
+	for _, model := range Registry {
+		for _, action := range []AuditAction{AuditActionCreate, AuditActionUpdate, AuditActionDelete} {
+			events = append(events, webhookEvent(reflect.TypeOf(model).Name(), action))
+		}
+	}
+
+	slices.Sort(events)
+	return events
+}
+
+// WebhookEventList is a list of webhook events that is stored as JSON.
+type WebhookEventList []WebhookEvent
+
+// Scan implements the sql.Scanner interface.
+func (l *WebhookEventList) Scan(value any) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case []byte:
+		return json.Unmarshal(v, l)
+	case nil:
+		*l = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into WebhookEventList", value)
+	}
+}
+
+// Value implements the driver.Valuer interface.
+func (l WebhookEventList) Value() (driver.Value, error) {
+	if l == nil {
+		l = WebhookEventList{}
+	}
+
+	j, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(j), nil
+}
+
+// Webhook is a URL that is notified about changes.
+//
+// Notifications are queued as WebhookDelivery within the transaction of the change
+// and sent by a dispatcher, so that changes that are rolled back are never sent.
+type Webhook struct {
+	DefaultModel
+	URL    string           // URL the payloads are sent to with a POST request
+	Secret string           // Secret used to sign the payloads
+	Events WebhookEventList `gorm:"type:text"` // Events the webhook is notified about
+}
+
+func (w *Webhook) BeforeCreate(tx *gorm.DB) error {
+	_ = w.DefaultModel.BeforeCreate(tx)
+
+	toSave := tx.Statement.Dest.(*Webhook)
+
+	// Generate a secret if none is set
+	if strings.TrimSpace(toSave.Secret) == "" {
+		secret := make([]byte, 32)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return err
+		}
+		toSave.Secret = hex.EncodeToString(secret)
+	}
+
+	return checkWebhook(toSave.URL, toSave.Events)
+}
+
+func (w *Webhook) BeforeUpdate(tx *gorm.DB) error {
+	toSave := tx.Statement.Dest.(Webhook)
+
+	u := w.URL
+	if tx.Statement.Changed("URL") {
+		u = toSave.URL
+	}
+
+	events := w.Events
+	if tx.Statement.Changed("Events") {
+		events = toSave.Events
+	}
+
+	if tx.Statement.Changed("Secret") && strings.TrimSpace(toSave.Secret) == "" {
+		return ErrWebhookSecretNotValid
+	}
+
+	return checkWebhook(u, events)
+}
+
+func (w *Webhook) BeforeSave(_ *gorm.DB) error {
+	w.URL = strings.TrimSpace(w.URL)
+	w.Secret = strings.TrimSpace(w.Secret)
+	return nil
+}
+
+// checkWebhook verifies the URL and events of a webhook.
+func checkWebhook(rawURL string, events []WebhookEvent) error {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrWebhookURLInvalid
+	}
+
+	if len(events) == 0 {
+		return ErrWebhookEventsEmpty
+	}
+
+	valid := WebhookEvents()
+	for _, event := range events {
+		if !slices.Contains(valid, event) {
+			return fmt.Errorf("%w: %s", ErrWebhookEventUnknown, event)
+		}
+	}
+
+	return nil
+}
+
+// WebhookDeliveryStatus is the status of a delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"   // The delivery is waiting for its next attempt
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED" // The receiver accepted the payload
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"    // All attempts failed
+)
+
+// Retries of failed deliveries. The delay doubles with every attempt.
+var (
+	WebhookMaxAttempts = 5
+	WebhookRetryDelay  = time.Minute
+)
+
+// WebhookDelivery is a notification of a webhook about a single event.
+//
+// Deliveries are kept as a log of all notifications and their results.
+type WebhookDelivery struct {
+	DefaultModel
+	Webhook        Webhook               `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	WebhookID      uuid.UUID             `gorm:"index"`
+	Event          WebhookEvent          // The event the webhook is notified about
+	Payload        string                `gorm:"type:text"` // The JSON payload that is sent
+	Status         WebhookDeliveryStatus `gorm:"index"`
+	Attempts       int                   // Number of attempts to send the payload
+	NextAttemptAt  time.Time             `gorm:"index"` // Time of the next attempt for pending deliveries
+	ResponseStatus int                   // HTTP status of the response to the last attempt. 0 if no response was received
+	Error          string                // Why the last attempt failed
+}
+
+// BeforeCreate only generates an ID if none is set since the ID is part of the payload.
+func (d *WebhookDelivery) BeforeCreate(_ *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// WebhookPayload is the JSON body sent to webhooks.
+type WebhookPayload struct {
+	ID    uuid.UUID    `json:"id"`    // ID of the delivery
+	Event WebhookEvent `json:"event"` // The event
+	Time  time.Time    `json:"time"`  // Time of the change
+	Data  any          `json:"data"`  // The change, WebhookResourceData or WebhookEnvelopeData depending on the event
+}
+
+// WebhookResourceData is the data of events for created, updated and deleted resources.
+type WebhookResourceData struct {
+	Model      string       `json:"model"`      // Name of the model, e.g. "Transaction"
+	ResourceID uuid.UUID    `json:"resourceId"` // ID of the resource. For month configs, this is the ID of the envelope.
+	Action     AuditAction  `json:"action"`     // The kind of change
+	Changes    AuditChanges `json:"changes"`    // Changed fields, identified by their database column, with their old and new values
+	RequestID  string       `json:"requestId"`  // ID of the HTTP request that made the change
+}
+
+// WebhookEnvelopeData is the data of the envelope.negative event.
+type WebhookEnvelopeData struct {
+	EnvelopeID uuid.UUID       `json:"envelopeId"` // ID of the envelope
+	Month      types.Month     `json:"month"`      // Month of the balance
+	Balance    decimal.Decimal `json:"balance"`    // Balance at the end of the month
+	RequestID  string          `json:"requestId"`  // ID of the HTTP request that made the change
+}
+
+// DueWebhookDeliveries returns all pending deliveries whose next attempt is due with their webhook.
+func DueWebhookDeliveries(db *gorm.DB, now time.Time) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := db.
+		Preload("Webhook").
+		Where(&WebhookDelivery{Status: WebhookDeliveryPending}).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC, created_at ASC").
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Record records the result of an attempt to send the delivery.
+//
+// An attempt succeeds when the receiver responds with a 2xx status. Failed deliveries
+// are retried with an exponential backoff until WebhookMaxAttempts is reached.
+func (d *WebhookDelivery) Record(db *gorm.DB, status int, attemptErr error, now time.Time) error {
+	d.Attempts++
+	d.ResponseStatus = status
+	d.Error = ""
+
+	switch {
+	case attemptErr != nil:
+		d.Error = attemptErr.Error()
+	case status < 200 || status > 299:
+		d.Error = fmt.Sprintf("the receiver responded with status %d", status)
+	}
+
+	switch {
+	case d.Error == "":
+		d.Status = WebhookDeliverySucceeded
+	case d.Attempts >= WebhookMaxAttempts:
+		d.Status = WebhookDeliveryFailed
+	default:
+		d.Status = WebhookDeliveryPending
+		d.NextAttemptAt = now.Add(WebhookRetryDelay << (d.Attempts - 1))
+	}
+
+	return db.Model(d).Select("Attempts", "ResponseStatus", "Error", "Status", "NextAttemptAt").Updates(*d).Error
+}
+
+// webhookBalancesKey is the key for the envelope balances before a change.
+const webhookBalancesKey = "envelope_zero:webhook_balances"
+
+// envelopeMonth identifies the balance of an envelope in a month.
+type envelopeMonth struct {
+	envelopeID uuid.UUID
+	month      types.Month
+}
+
+// webhookBalanceSources are the tables with changes that affect envelope balances
+// and the fields the envelope and month are stored in.
+var webhookBalanceSources = map[string]struct{ envelope, month string }{
+	"transactions":  {"EnvelopeID", "Date"},
+	"month_configs": {"EnvelopeID", "Month"},
+}
+
+// subscribedWebhooks returns all webhooks that are subscribed to the event.
+func subscribedWebhooks(db *gorm.DB, event WebhookEvent) ([]Webhook, error) {
+	var webhooks []Webhook
+	err := db.Session(&gorm.Session{NewDB: true}).Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subscribed := make([]Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if slices.Contains(webhook.Events, event) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+
+	return subscribed, nil
+}
+
+// webhookBeforeCreateCallback stores the balances of the envelopes affected by created
+// transactions or allocations, see webhookBeforeCallback.
+func webhookBeforeCreateCallback(db *gorm.DB) {
+	storeWebhookBalances(db, false)
+}
+
+// webhookBeforeCallback stores the balances of the envelopes affected by a change
+// to transactions or allocations so that envelopes going negative can be detected.
+//
+// It runs before the hooks of the models since they invalidate the balance snapshots.
+func webhookBeforeCallback(db *gorm.DB) {
+	storeWebhookBalances(db, true)
+}
+
+// storeWebhookBalances stores the balances of the envelopes and months of the resources
+// the statement changes. If existing is set, the resources exist before the statement.
+func storeWebhookBalances(db *gorm.DB, existing bool) {
+	source, ok := webhookBalanceSources[db.Statement.Table]
+	if db.Error != nil || !ok || !audited(db) {
+		return
+	}
+
+	webhooks, err := subscribedWebhooks(db, WebhookEventEnvelopeNegative)
+	if err != nil || len(webhooks) == 0 {
+		_ = db.AddError(err)
+		return
+	}
+
+	stmt := db.Statement
+	envelopeField, monthField := stmt.Schema.LookUpField(source.envelope), stmt.Schema.LookUpField(source.month)
+
+	var before []map[string]any
+	if existing {
+		before, err = auditRows(db, nil)
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+	}
+
+	candidates := make(map[envelopeMonth]bool)
+	var envelopes []uuid.UUID
+	var months []types.Month
+	for _, row := range before {
+		if e, m, ok := webhookEnvelopeMonth(row[envelopeField.DBName], row[monthField.DBName]); ok {
+			candidates[envelopeMonth{e, m}] = true
+			envelopes = append(envelopes, e)
+			months = append(months, m)
+		}
+	}
+
+	// The new values of created or updated resources
+	for _, values := range webhookStatementValues(stmt, envelopeField, monthField) {
+		newEnvelopes, newMonths := envelopes, months
+		if e, ok := webhookEnvelopeID(values[0]); ok {
+			newEnvelopes = []uuid.UUID{e}
+		}
+		if m, ok := webhookMonth(values[1]); ok {
+			newMonths = []types.Month{m}
+		}
+
+		for _, e := range newEnvelopes {
+			for _, m := range newMonths {
+				candidates[envelopeMonth{e, m}] = true
+			}
+		}
+	}
+
+	balances := make(map[envelopeMonth]decimal.Decimal, len(candidates))
+	for candidate := range candidates {
+		balance, ok, err := webhookEnvelopeBalance(db, candidate)
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+
+		if ok {
+			balances[candidate] = balance
+		}
+	}
+
+	db.InstanceSet(webhookBalancesKey, balances)
+}
+
+// webhookCallback queues the deliveries for the changes of a statement.
+//
+// Changes to resources are taken from the audit entries written for the statement.
+func webhookCallback(db *gorm.DB) {
+	if db.Error != nil || !audited(db) {
+		return
+	}
+
+	requestID, _ := db.Statement.Context.Value(string(DBContextRequestID)).(string)
+
+	var deliveries []WebhookDelivery
+	if entries, ok := db.InstanceGet(auditEntriesKey); ok {
+		for _, entry := range entries.([]AuditEntry) {
+			d, err := webhookDeliveries(db, webhookEvent(entry.Model, entry.Action), WebhookResourceData{
+				Model:      entry.Model,
+				ResourceID: entry.ResourceID,
+				Action:     entry.Action,
+				Changes:    entry.Changes,
+				RequestID:  requestID,
+			})
+			if err != nil {
+				_ = db.AddError(err)
+				return
+			}
+			deliveries = append(deliveries, d...)
+		}
+	}
+
+	if balances, ok := db.InstanceGet(webhookBalancesKey); ok {
+		for candidate, before := range balances.(map[envelopeMonth]decimal.Decimal) {
+			balance, ok, err := webhookEnvelopeBalance(db, candidate)
+			if err != nil {
+				_ = db.AddError(err)
+				return
+			}
+
+			if !ok || before.IsNegative() || !balance.IsNegative() {
+				continue
+			}
+
+			d, err := webhookDeliveries(db, WebhookEventEnvelopeNegative, WebhookEnvelopeData{
+				EnvelopeID: candidate.envelopeID,
+				Month:      candidate.month,
+				Balance:    balance,
+				RequestID:  requestID,
+			})
+			if err != nil {
+				_ = db.AddError(err)
+				return
+			}
+			deliveries = append(deliveries, d...)
+		}
+	}
+
+	if len(deliveries) == 0 {
+		return
+	}
+
+	err := db.Session(&gorm.Session{NewDB: true}).Create(&deliveries).Error
+	if err != nil {
+		_ = db.AddError(err)
+	}
+}
+
+// webhookDeliveries returns the deliveries of the event with the data for all subscribed webhooks.
+func webhookDeliveries(db *gorm.DB, event WebhookEvent, data any) ([]WebhookDelivery, error) {
+	webhooks, err := subscribedWebhooks(db, event)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deliveries := make([]WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		id := uuid.New()
+		payload, err := json.Marshal(WebhookPayload{
+			ID:    id,
+			Event: event,
+			Time:  now,
+			Data:  data,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, WebhookDelivery{
+			DefaultModel:  DefaultModel{ID: id},
+			WebhookID:     webhook.ID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	return deliveries, nil
+}
+
+// webhookEnvelopeBalance returns the balance of the envelope in the month.
+// If the envelope does not exist, ok is false.
+func webhookEnvelopeBalance(db *gorm.DB, candidate envelopeMonth) (balance decimal.Decimal, ok bool, err error) {
+	tx := db.Session(&gorm.Session{NewDB: true})
+
+	var envelopes []Envelope
+	err = tx.Where("id = ?", candidate.envelopeID).Limit(1).Find(&envelopes).Error
+	if err != nil || len(envelopes) == 0 {
+		return decimal.Zero, false, err
+	}
+
+	balance, err = envelopes[0].Balance(tx, candidate.month)
+	if err != nil {
+		return decimal.Zero, false, err
+	}
+
+	return balance, true, nil
+}
+
+// webhookStatementValues returns the values of the fields for all resources
+// the statement creates or updates.
+//
+// For updates, values of fields that are not updated are nil.
+func webhookStatementValues(stmt *gorm.Statement, fields ...*schema.Field) [][]any {
+	switch dest := stmt.Dest.(type) {
+	case map[string]any:
+		values := make([]any, 0, len(fields))
+		for _, field := range fields {
+			v, ok := dest[field.Name]
+			if !ok {
+				v = dest[field.DBName]
+			}
+			values = append(values, v)
+		}
+		return [][]any{values}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(stmt.Dest))
+	rows := []reflect.Value{value}
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		rows = rows[:0]
+		for i := range value.Len() {
+			rows = append(rows, reflect.Indirect(value.Index(i)))
+		}
+	}
+
+	result := make([][]any, 0, len(rows))
+	for _, row := range rows {
+		if row.Kind() != reflect.Struct || row.Type() != stmt.Schema.ModelType {
+			continue
+		}
+
+		values := make([]any, 0, len(fields))
+		for _, field := range fields {
+			v, zero := field.ValueOf(stmt.Context, row)
+			if zero {
+				v = nil
+			}
+			values = append(values, v)
+		}
+		result = append(result, values)
+	}
+
+	return result
+}
+
+// webhookEnvelopeMonth parses the envelope and month of a change.
+func webhookEnvelopeMonth(envelope, month any) (uuid.UUID, types.Month, bool) {
+	e, ok := webhookEnvelopeID(envelope)
+	if !ok {
+		return uuid.Nil, types.Month{}, false
+	}
+
+	m, ok := webhookMonth(month)
+	if !ok {
+		return uuid.Nil, types.Month{}, false
+	}
+
+	return e, m, true
+}
+
+// webhookEnvelopeID parses the ID of an envelope from a field or database value.
+func webhookEnvelopeID(value any) (uuid.UUID, bool) {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return v, v != uuid.Nil
+	case *uuid.UUID:
+		if v == nil {
+			return uuid.Nil, false
+		}
+		return *v, *v != uuid.Nil
+	case string, []byte:
+		id, err := resourceID(v)
+		return id, err == nil && id != uuid.Nil
+	default:
+		return uuid.Nil, false
+	}
+}
+
+// webhookMonth parses a month from a field or database value.
+func webhookMonth(value any) (types.Month, bool) {
+	var m types.Month
+	switch v := value.(type) {
+	case types.Month:
+		m = v
+	case time.Time:
+		m = types.MonthOf(v)
+	case nil:
+		return types.Month{}, false
+	default:
+		if m.Scan(v) != nil {
+			return types.Month{}, false
+		}
+	}
+
+	return m, !m.IsZero()
+}
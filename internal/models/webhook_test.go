@@ -0,0 +1,238 @@
+package models_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/types"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func (suite *TestSuiteStandard) createTestWebhook(webhook models.Webhook) models.Webhook {
+	if webhook.URL == "" {
+		webhook.URL = "https://example.com/hook"
+	}
+
+	err := models.DB.Create(&webhook).Error
+	if err != nil {
+		suite.Assert().FailNow("Webhook could not be saved", "Error: %s, Webhook: %#v", err, webhook)
+	}
+
+	return webhook
+}
+
+// webhookDeliveries returns the deliveries of the webhook, oldest first.
+func (suite *TestSuiteStandard) webhookDeliveries(webhook models.Webhook) []models.WebhookDelivery {
+	var deliveries []models.WebhookDelivery
+	err := models.DB.Where(&models.WebhookDelivery{WebhookID: webhook.ID}).Order("created_at ASC").Find(&deliveries).Error
+	suite.Require().Nil(err)
+
+	return deliveries
+}
+
+func (suite *TestSuiteStandard) TestWebhookEvents() {
+	events := models.WebhookEvents()
+	suite.Assert().Contains(events, models.WebhookEventEnvelopeNegative)
+	suite.Assert().Contains(events, models.WebhookEvent("transaction.created"))
+	suite.Assert().Contains(events, models.WebhookEvent("monthConfig.updated"))
+	suite.Assert().Contains(events, models.WebhookEvent("transactionTag.deleted"))
+	suite.Assert().Len(events, len(models.Registry)*3+1)
+}
+
+func (suite *TestSuiteStandard) TestWebhookCreate() {
+	webhook := suite.createTestWebhook(models.Webhook{URL: " https://example.com/hook ", Events: models.WebhookEventList{"transaction.created"}})
+	suite.Assert().Equal("https://example.com/hook", webhook.URL)
+	suite.Assert().Len(webhook.Secret, 64, "A secret must be generated")
+
+	tests := []struct {
+		name    string
+		webhook models.Webhook
+		err     error
+	}{
+		{"No scheme", models.Webhook{URL: "example.com/hook", Events: models.WebhookEventList{"transaction.created"}}, models.ErrWebhookURLInvalid},
+		{"FTP", models.Webhook{URL: "ftp://example.com/hook", Events: models.WebhookEventList{"transaction.created"}}, models.ErrWebhookURLInvalid},
+		{"No events", models.Webhook{URL: "https://example.com/hook"}, models.ErrWebhookEventsEmpty},
+		{"Unknown event", models.Webhook{URL: "https://example.com/hook", Events: models.WebhookEventList{"transaction.exploded"}}, models.ErrWebhookEventUnknown},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := models.DB.Create(&tt.webhook).Error
+			suite.Assert().ErrorIs(err, tt.err)
+		})
+	}
+}
+
+func (suite *TestSuiteStandard) TestWebhookUpdate() {
+	webhook := suite.createTestWebhook(models.Webhook{Events: models.WebhookEventList{"transaction.created"}})
+
+	err := models.DB.Model(&webhook).Select("Events").Updates(models.Webhook{Events: models.WebhookEventList{}}).Error
+	suite.Assert().ErrorIs(err, models.ErrWebhookEventsEmpty)
+
+	err = models.DB.Model(&webhook).Select("Secret").Updates(models.Webhook{Secret: " "}).Error
+	suite.Assert().ErrorIs(err, models.ErrWebhookSecretNotValid)
+
+	err = models.DB.Model(&webhook).Select("URL").Updates(models.Webhook{URL: "https://example.com/other"}).Error
+	suite.Assert().Nil(err)
+}
+
+func (suite *TestSuiteStandard) TestWebhookDeliveriesQueued() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	external := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+
+	webhook := suite.createTestWebhook(models.Webhook{Events: models.WebhookEventList{"transaction.created", "transaction.deleted"}})
+	other := suite.createTestWebhook(models.Webhook{Events: models.WebhookEventList{"account.created"}})
+
+	transaction := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      account.ID,
+		DestinationAccountID: external.ID,
+		Amount:               decimal.NewFromFloat(12.5),
+	})
+
+	// Updates are not subscribed to
+	err := models.DB.Model(&transaction).Select("Note").Updates(models.Transaction{Note: "Groceries"}).Error
+	suite.Require().Nil(err)
+
+	err = models.DB.Delete(&transaction).Error
+	suite.Require().Nil(err)
+
+	deliveries := suite.webhookDeliveries(webhook)
+	suite.Require().Len(deliveries, 2)
+	suite.Assert().Equal(models.WebhookEvent("transaction.created"), deliveries[0].Event)
+	suite.Assert().Equal(models.WebhookDeliveryPending, deliveries[0].Status)
+	suite.Assert().Equal(models.WebhookEvent("transaction.deleted"), deliveries[1].Event)
+
+	var payload struct {
+		ID    string                     `json:"id"`
+		Event models.WebhookEvent        `json:"event"`
+		Data  models.WebhookResourceData `json:"data"`
+	}
+	suite.Require().Nil(json.Unmarshal([]byte(deliveries[0].Payload), &payload))
+	suite.Assert().Equal(deliveries[0].ID.String(), payload.ID)
+	suite.Assert().Equal(models.WebhookEvent("transaction.created"), payload.Event)
+	suite.Assert().Equal(transaction.ID, payload.Data.ResourceID)
+	suite.Assert().Equal(models.AuditActionCreate, payload.Data.Action)
+	suite.Assert().Equal("12.5", payload.Data.Changes["amount"].New)
+
+	// Accounts were created before the webhook
+	suite.Assert().Len(suite.webhookDeliveries(other), 0)
+}
+
+func (suite *TestSuiteStandard) TestWebhookDeliveriesRollback() {
+	budget := suite.createTestBudget(models.Budget{})
+	webhook := suite.createTestWebhook(models.Webhook{Events: models.WebhookEventList{"account.created"}})
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&models.Account{BudgetID: budget.ID, Name: "Rolled back"}).Error
+		suite.Require().Nil(err)
+		return errors.New("roll back")
+	})
+	suite.Require().NotNil(err)
+
+	suite.Assert().Len(suite.webhookDeliveries(webhook), 0, "Changes that are rolled back must not be delivered")
+}
+
+func (suite *TestSuiteStandard) TestWebhookEnvelopeNegative() {
+	budget := suite.createTestBudget(models.Budget{})
+	account := suite.createTestAccount(models.Account{BudgetID: budget.ID, OnBudget: true})
+	external := suite.createTestAccount(models.Account{BudgetID: budget.ID, External: true})
+	category := suite.createTestCategory(models.Category{BudgetID: budget.ID})
+	envelope := suite.createTestEnvelope(models.Envelope{CategoryID: category.ID})
+
+	webhook := suite.createTestWebhook(models.Webhook{Events: models.WebhookEventList{models.WebhookEventEnvelopeNegative}})
+
+	january := types.NewMonth(2024, 1)
+	monthConfig := suite.createTestMonthConfig(models.MonthConfig{EnvelopeID: envelope.ID, Month: january, Allocation: decimal.NewFromFloat(50)})
+
+	// Spending within the allocation does not make the envelope negative
+	transaction := suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      account.ID,
+		DestinationAccountID: external.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(40),
+		Date:                 time.Time(january).AddDate(0, 0, 4),
+	})
+	suite.Assert().Len(suite.webhookDeliveries(webhook), 0)
+
+	// Spending more makes it negative
+	_ = suite.createTestTransaction(models.Transaction{
+		SourceAccountID:      account.ID,
+		DestinationAccountID: external.ID,
+		EnvelopeID:           &envelope.ID,
+		Amount:               decimal.NewFromFloat(25),
+		Date:                 time.Time(january).AddDate(0, 0, 9),
+	})
+
+	deliveries := suite.webhookDeliveries(webhook)
+	suite.Require().Len(deliveries, 1)
+	suite.Assert().Equal(models.WebhookEventEnvelopeNegative, deliveries[0].Event)
+
+	var payload struct {
+		Data models.WebhookEnvelopeData `json:"data"`
+	}
+	suite.Require().Nil(json.Unmarshal([]byte(deliveries[0].Payload), &payload))
+	suite.Assert().Equal(envelope.ID, payload.Data.EnvelopeID)
+	suite.Assert().Equal(january, payload.Data.Month)
+	suite.Assert().True(payload.Data.Balance.Equal(decimal.NewFromFloat(-15)), "Balance is %s", payload.Data.Balance)
+
+	// Staying negative is not notified again
+	err := models.DB.Model(&transaction).Select("Amount").Updates(models.Transaction{Amount: decimal.NewFromFloat(45)}).Error
+	suite.Require().Nil(err)
+	suite.Assert().Len(suite.webhookDeliveries(webhook), 1)
+
+	// Allocating more makes it positive, reducing the allocation again makes it negative
+	err = models.DB.Model(&monthConfig).Select("Allocation").Updates(models.MonthConfig{Allocation: decimal.NewFromFloat(100)}).Error
+	suite.Require().Nil(err)
+	suite.Assert().Len(suite.webhookDeliveries(webhook), 1)
+
+	err = models.DB.Model(&monthConfig).Select("Allocation").Updates(models.MonthConfig{Allocation: decimal.NewFromFloat(60)}).Error
+	suite.Require().Nil(err)
+	suite.Assert().Len(suite.webhookDeliveries(webhook), 2)
+}
+
+func (suite *TestSuiteStandard) TestWebhookDeliveryRecord() {
+	webhook := suite.createTestWebhook(models.Webhook{Events: models.WebhookEventList{"budget.created"}})
+	_ = suite.createTestBudget(models.Budget{})
+
+	now := time.Now()
+	deliveries, err := models.DueWebhookDeliveries(models.DB, now)
+	suite.Require().Nil(err)
+	suite.Require().Len(deliveries, 1)
+	suite.Assert().Equal(webhook.URL, deliveries[0].Webhook.URL, "The webhook must be loaded")
+
+	delivery := deliveries[0]
+
+	// Failed attempts are retried with an exponential backoff
+	suite.Require().Nil(delivery.Record(models.DB, 503, nil, now))
+	suite.Assert().Equal(models.WebhookDeliveryPending, delivery.Status)
+	suite.Assert().Equal("the receiver responded with status 503", delivery.Error)
+	suite.Assert().WithinDuration(now.Add(models.WebhookRetryDelay), delivery.NextAttemptAt, time.Second)
+
+	suite.Require().Nil(delivery.Record(models.DB, 0, errors.New("connection refused"), now))
+	suite.Assert().Equal("connection refused", delivery.Error)
+	suite.Assert().WithinDuration(now.Add(2*models.WebhookRetryDelay), delivery.NextAttemptAt, time.Second)
+
+	deliveries, err = models.DueWebhookDeliveries(models.DB, now)
+	suite.Require().Nil(err)
+	suite.Assert().Len(deliveries, 0, "Deliveries must not be sent before their next attempt is due")
+
+	suite.Require().Nil(delivery.Record(models.DB, 204, nil, now))
+	suite.Assert().Equal(models.WebhookDeliverySucceeded, delivery.Status)
+	suite.Assert().Equal("", delivery.Error)
+	suite.Assert().Equal(3, delivery.Attempts)
+
+	// After the maximum number of attempts, the delivery fails
+	delivery.Attempts = models.WebhookMaxAttempts - 1
+	suite.Require().Nil(delivery.Record(models.DB, 500, nil, now))
+	suite.Assert().Equal(models.WebhookDeliveryFailed, delivery.Status)
+
+	var stored models.WebhookDelivery
+	suite.Require().Nil(models.DB.First(&stored, delivery.ID).Error)
+	suite.Assert().Equal(models.WebhookDeliveryFailed, stored.Status)
+	suite.Assert().Equal(models.WebhookMaxAttempts, stored.Attempts)
+	suite.Assert().Equal(500, stored.ResponseStatus)
+}
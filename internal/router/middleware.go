@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -19,9 +20,19 @@ func URLMiddleware(url *url.URL) gin.HandlerFunc {
 	}
 }
 
+// RequestIDMiddleware makes the request ID available to database callbacks
+// for the audit log.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(string(models.DBContextRequestID), requestid.Get(c))
+		c.Next()
+	}
+}
+
 var metrics = []prometheus.Collector{
 	requestCount,
 	requestDuration,
+	models.EnvelopeBalanceCacheLookups,
 }
 
 // registerPrometheusMetrics registers all Prometheus metrics
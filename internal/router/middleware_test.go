@@ -8,6 +8,7 @@ import (
 
 	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/envelope-zero/backend/v7/internal/router"
+	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -29,3 +30,20 @@ func TestURLMiddleware(t *testing.T) {
 
 	assert.Equal(t, "https://ez.example.com:8081/api", w.Body.String())
 }
+
+func TestRequestIDMiddleware(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, r := gin.CreateTestContext(w)
+
+	r.Use(requestid.New())
+	r.GET("/", func(_ *gin.Context) {
+		router.RequestIDMiddleware()(c)
+		c.String(http.StatusOK, c.GetString(string(models.DBContextRequestID)))
+	})
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "https://ez.example.com/", nil)
+	c.Request.Header.Set("X-Request-ID", "some-request-id")
+	r.ServeHTTP(w, c.Request)
+
+	assert.Equal(t, "some-request-id", w.Body.String())
+}
@@ -50,6 +50,7 @@ func Config(url *url.URL) (*gin.Engine, func(), error) {
 
 	r.Use(gin.Recovery())
 	r.Use(requestid.New())
+	r.Use(RequestIDMiddleware())
 	r.Use(URLMiddleware(url))
 	r.Use(MetricsMiddleware())
 	r.NoMethod(func(c *gin.Context) {
@@ -154,15 +155,27 @@ func AttachRoutes(group *gin.RouterGroup) {
 		v4Group := group.Group("/v4")
 		v4.RegisterRootRoutes(v4Group.Group(""))
 		v4.RegisterAccountRoutes(v4Group.Group("/accounts"))
+		v4.RegisterAttachmentRoutes(v4Group.Group("/attachments"))
+		v4.RegisterAuditRoutes(v4Group.Group("/audit"))
 		v4.RegisterBudgetRoutes(v4Group.Group("/budgets"))
 		v4.RegisterCategoryRoutes(v4Group.Group("/categories"))
 		v4.RegisterEnvelopeRoutes(v4Group.Group("/envelopes"))
+		v4.RegisterEventRoutes(v4Group.Group("/events"))
+		v4.RegisterExchangeRateRoutes(v4Group.Group("/exchange-rates"))
 		v4.RegisterExportRoutes(v4Group.Group("/export"), version)
 		v4.RegisterGoalRoutes(v4Group.Group("/goals"))
 		v4.RegisterImportRoutes(v4Group.Group("/import"))
 		v4.RegisterMatchRuleRoutes(v4Group.Group("/match-rules"))
 		v4.RegisterMonthConfigRoutes(v4Group.Group("/envelopes"))
 		v4.RegisterMonthRoutes(v4Group.Group("/months"))
+		v4.RegisterPayeeRoutes(v4Group.Group("/payees"))
+		v4.RegisterReconciliationRoutes(v4Group.Group("/reconciliations"))
+		v4.RegisterReportRoutes(v4Group.Group("/reports"))
+		v4.RegisterSearchRoutes(v4Group.Group("/search"))
+		v4.RegisterTagRoutes(v4Group.Group("/tags"))
+		v4.RegisterTemplateRoutes(v4Group.Group("/templates"))
 		v4.RegisterTransactionRoutes(v4Group.Group("/transactions"))
+		v4.RegisterTrashRoutes(v4Group.Group("/trash"))
+		v4.RegisterWebhookRoutes(v4Group.Group("/webhooks"))
 	}
 }
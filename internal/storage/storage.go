@@ -0,0 +1,94 @@
+// Package storage keeps files that are not stored in the database, e.g. attachments.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidName = errors.New("the file name is invalid")
+
+// Storage saves, opens and deletes files by their name.
+type Storage interface {
+	Save(name string, r io.Reader) error     // Saves the content of r as the file with the name, replacing an existing file
+	Open(name string) (io.ReadCloser, error) // Opens the file with the name. Returns an error wrapping fs.ErrNotExist if it does not exist
+	Delete(name string) error                // Deletes the file with the name. Deleting a file that does not exist is not an error
+}
+
+// Local stores files in a directory on the local disk.
+type Local struct {
+	Directory string // The directory files are stored in. It is created when the first file is saved
+}
+
+// path returns the path of the file with the name.
+//
+// Names must not contain path separators so that files cannot be
+// read or written outside of the directory.
+func (l Local) path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidName, name)
+	}
+
+	return filepath.Join(l.Directory, name), nil
+}
+
+// Save writes the file to a temporary file first so that
+// an existing file is only replaced once the content is complete.
+func (l Local) Save(name string, r io.Reader) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(l.Directory, 0o750)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(l.Directory, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func (l Local) Open(name string) (io.ReadCloser, error) {
+	path, err := l.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (l Local) Delete(name string) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
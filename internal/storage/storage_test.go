@@ -0,0 +1,49 @@
+package storage_test
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/envelope-zero/backend/v7/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	s := storage.Local{Directory: filepath.Join(t.TempDir(), "attachments")}
+
+	require.Nil(t, s.Save("receipt", strings.NewReader("first")))
+	require.Nil(t, s.Save("receipt", strings.NewReader("second")), "Existing files must be replaced")
+
+	f, err := s.Open("receipt")
+	require.Nil(t, err)
+	content, err := io.ReadAll(f)
+	require.Nil(t, err)
+	f.Close()
+	assert.Equal(t, "second", string(content))
+
+	entries, err := os.ReadDir(s.Directory)
+	require.Nil(t, err)
+	assert.Len(t, entries, 1, "Temporary files must be removed")
+
+	require.Nil(t, s.Delete("receipt"))
+	require.Nil(t, s.Delete("receipt"), "Deleting a file that does not exist must not fail")
+
+	_, err = s.Open("receipt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestLocalInvalidName(t *testing.T) {
+	s := storage.Local{Directory: t.TempDir()}
+
+	for _, name := range []string{"", ".", "..", "../receipt", "a/b", `a\b`} {
+		assert.ErrorIs(t, s.Save(name, strings.NewReader("")), storage.ErrInvalidName, "Save with %q", name)
+		_, err := s.Open(name)
+		assert.ErrorIs(t, err, storage.ErrInvalidName, "Open with %q", name)
+		assert.ErrorIs(t, s.Delete(name), storage.ErrInvalidName, "Delete with %q", name)
+	}
+}
@@ -0,0 +1,134 @@
+// Package templates implements starter templates for the category and envelope structure of budgets.
+package templates
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//go:embed data/*.json
+var data embed.FS
+
+var ErrTemplateNotFound = fmt.Errorf("%w template matching your query", models.ErrResourceNotFound)
+
+type Envelope struct {
+	Name string `json:"name" example:"Groceries"`                   // Name of the envelope
+	Note string `json:"note" example:"Food and household supplies"` // Note for the envelope
+}
+
+type Category struct {
+	Name      string     `json:"name" example:"Daily Spending"`           // Name of the category
+	Note      string     `json:"note" example:"Everything we need daily"` // Note for the category
+	Envelopes []Envelope `json:"envelopes"`                               // Envelopes in the category
+}
+
+// Template is a category and envelope structure that can be applied to a budget.
+type Template struct {
+	ID          string     `json:"id" example:"household"`                                  // ID of the template
+	Name        string     `json:"name" example:"Household"`                                // Name of the template
+	Description string     `json:"description" example:"Common categories for a household"` // Description of the template
+	Categories  []Category `json:"categories"`                                              // Categories in the template
+}
+
+// List returns all templates, sorted by their ID.
+func List() ([]Template, error) {
+	entries, err := data.ReadDir("data")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]Template, 0, len(entries))
+	for _, entry := range entries {
+		template, err := Get(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+
+	return templates, nil
+}
+
+// Get returns the template with the ID.
+func Get(id string) (Template, error) {
+	// The ID must not be used to read anything outside of the template directory
+	if id == "" || strings.ContainsAny(id, "/\\.") {
+		return Template{}, ErrTemplateNotFound
+	}
+
+	content, err := data.ReadFile(path.Join("data", id+".json"))
+	if err != nil {
+		return Template{}, ErrTemplateNotFound
+	}
+
+	var template Template
+	err = json.Unmarshal(content, &template)
+	if err != nil {
+		return Template{}, fmt.Errorf("template %s is invalid: %w", id, err)
+	}
+	template.ID = id
+
+	return template, nil
+}
+
+// Apply creates the categories and envelopes of the template for a budget.
+//
+// Categories and envelopes that already exist with the same name are reused, so
+// applying a template multiple times does not fail. All resources are created in
+// one database transaction.
+//
+// It returns the categories of the template.
+func (t Template) Apply(db *gorm.DB, budgetID uuid.UUID) (categories []models.Category, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&models.Budget{}, budgetID).Error
+		if err != nil {
+			return err
+		}
+
+		for _, c := range t.Categories {
+			category := models.Category{
+				BudgetID: budgetID,
+				Name:     c.Name,
+			}
+
+			err := tx.Where(&category).Attrs(models.Category{Note: c.Note}).FirstOrCreate(&category).Error
+			if err != nil {
+				return err
+			}
+
+			for _, e := range c.Envelopes {
+				envelope := models.Envelope{
+					CategoryID: category.ID,
+					Name:       e.Name,
+				}
+
+				err := tx.Where(&envelope).Attrs(models.Envelope{Note: e.Note}).FirstOrCreate(&envelope).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			categories = append(categories, category)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
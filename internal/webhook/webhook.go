@@ -0,0 +1,112 @@
+// Package webhook sends the payloads of webhook deliveries to their receivers.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Headers sent with every payload.
+const (
+	HeaderEvent     = "X-Envelope-Zero-Event"     // The event of the delivery
+	HeaderDelivery  = "X-Envelope-Zero-Delivery"  // The ID of the delivery
+	HeaderSignature = "X-Envelope-Zero-Signature" // The signature of the payload, see Sign
+)
+
+// Sign returns the signature of the payload for the secret.
+//
+// The signature is the hex encoded HMAC-SHA256 of the payload, prefixed with "sha256=".
+// Receivers verify a payload by calculating the signature themselves and comparing it
+// to the value of the signature header.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
+
+// defaultClient is used when a Dispatcher has no client. The timeout keeps a receiver
+// that does not respond from blocking all later deliveries.
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// Dispatcher sends pending webhook deliveries.
+type Dispatcher struct {
+	Client *http.Client // Client used to send the payloads. Defaults to a client with a timeout of 10 seconds
+}
+
+// Run dispatches due deliveries at every interval until the context is cancelled.
+func (d Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := d.Dispatch(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Dispatching webhook deliveries failed")
+			}
+		}
+	}
+}
+
+// Dispatch sends all deliveries that are due and records the results.
+func (d Dispatcher) Dispatch(ctx context.Context) error {
+	deliveries, err := models.DueWebhookDeliveries(models.DB, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		status, err := d.send(ctx, delivery)
+
+		err = delivery.Record(models.DB, status, err, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send sends the payload of the delivery to its webhook and returns the status of the response.
+func (d Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	payload := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderSignature, Sign(delivery.Webhook.Secret, payload))
+
+	client := d.Client
+	if client == nil {
+		client = defaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Read the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,163 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/envelope-zero/backend/v7/internal/models"
+	"github.com/envelope-zero/backend/v7/internal/webhook"
+	"github.com/envelope-zero/backend/v7/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a webhook receiver that records the requests it receives.
+type receiver struct {
+	sync.Mutex
+	statuses []int // Statuses to respond with, in order. Defaults to 204 when empty
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (r *receiver) received() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.requests)
+}
+
+// setup connects to a new database and creates a webhook for created budgets
+// that sends its payloads to the receiver.
+func setup(t *testing.T, r *receiver) models.Webhook {
+	require.Nil(t, models.Connect(test.TmpFile(t)))
+	t.Cleanup(func() {
+		sqlDB, _ := models.DB.DB()
+		sqlDB.Close()
+	})
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	w := models.Webhook{URL: server.URL, Secret: "s3cr3t", Events: models.WebhookEventList{"budget.created"}}
+	require.Nil(t, models.DB.Create(&w).Error)
+
+	return w
+}
+
+func TestSign(t *testing.T) {
+	// Reference value calculated with: printf '{"event":"budget.created"}' | openssl dgst -sha256 -hmac s3cr3t
+	assert.Equal(t, "sha256=4eaa8b1e8aced3987e701cf8ac1755b02b959df5de4d4565766c3bc8d9d1ff3a", webhook.Sign("s3cr3t", []byte(`{"event":"budget.created"}`)))
+}
+
+func TestDispatch(t *testing.T) {
+	r := &receiver{}
+	w := setup(t, r)
+
+	budget := models.Budget{Name: "Household"}
+	require.Nil(t, models.DB.Create(&budget).Error)
+
+	require.Nil(t, webhook.Dispatcher{}.Dispatch(context.Background()))
+	require.Equal(t, 1, r.received())
+
+	req, body := r.requests[0], r.bodies[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "budget.created", req.Header.Get(webhook.HeaderEvent))
+	assert.Equal(t, webhook.Sign(w.Secret, body), req.Header.Get(webhook.HeaderSignature))
+	assert.Contains(t, string(body), budget.ID.String())
+
+	var delivery models.WebhookDelivery
+	require.Nil(t, models.DB.Where(&models.WebhookDelivery{WebhookID: w.ID}).First(&delivery).Error)
+	assert.Equal(t, delivery.ID.String(), req.Header.Get(webhook.HeaderDelivery))
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, http.StatusNoContent, delivery.ResponseStatus)
+
+	// Delivered payloads are not sent again
+	require.Nil(t, webhook.Dispatcher{}.Dispatch(context.Background()))
+	assert.Equal(t, 1, r.received())
+}
+
+func TestDispatchRetry(t *testing.T) {
+	r := &receiver{statuses: []int{http.StatusServiceUnavailable}}
+	w := setup(t, r)
+
+	require.Nil(t, models.DB.Create(&models.Budget{}).Error)
+	require.Nil(t, webhook.Dispatcher{}.Dispatch(context.Background()))
+
+	var delivery models.WebhookDelivery
+	require.Nil(t, models.DB.Where(&models.WebhookDelivery{WebhookID: w.ID}).First(&delivery).Error)
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.ResponseStatus)
+	assert.True(t, delivery.NextAttemptAt.After(time.Now()), "The retry must be delayed")
+
+	// The retry is not sent before it is due
+	require.Nil(t, webhook.Dispatcher{}.Dispatch(context.Background()))
+	assert.Equal(t, 1, r.received())
+
+	require.Nil(t, models.DB.Model(&delivery).Select("NextAttemptAt").Updates(models.WebhookDelivery{NextAttemptAt: time.Now()}).Error)
+	require.Nil(t, webhook.Dispatcher{}.Dispatch(context.Background()))
+	assert.Equal(t, 2, r.received())
+	assert.Equal(t, r.bodies[0], r.bodies[1], "Retries must send the same payload")
+
+	require.Nil(t, models.DB.First(&delivery, delivery.ID).Error)
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+}
+
+func TestDispatchUnreachable(t *testing.T) {
+	r := &receiver{}
+	w := setup(t, r)
+
+	require.Nil(t, models.DB.Model(&w).Select("URL").Updates(models.Webhook{URL: "http://127.0.0.1:1/hook"}).Error)
+	require.Nil(t, models.DB.Create(&models.Budget{}).Error)
+	require.Nil(t, webhook.Dispatcher{}.Dispatch(context.Background()))
+
+	var delivery models.WebhookDelivery
+	require.Nil(t, models.DB.Where(&models.WebhookDelivery{WebhookID: w.ID}).First(&delivery).Error)
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 0, delivery.ResponseStatus)
+	assert.Contains(t, delivery.Error, "connection refused")
+}
+
+func TestRun(t *testing.T) {
+	r := &receiver{}
+	_ = setup(t, r)
+
+	require.Nil(t, models.DB.Create(&models.Budget{}).Error)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		webhook.Dispatcher{}.Run(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return r.received() == 1 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("The dispatcher did not stop when the context was cancelled")
+	}
+}
@@ -10,8 +10,10 @@ import (
 	"syscall"
 	"time"
 
+	v4 "github.com/envelope-zero/backend/v7/internal/controllers/v4"
 	"github.com/envelope-zero/backend/v7/internal/models"
 	"github.com/envelope-zero/backend/v7/internal/router"
+	"github.com/envelope-zero/backend/v7/internal/webhook"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -54,6 +56,16 @@ func main() {
 		log.Fatal().Msg("environment variable API_URL must be a valid URL")
 	}
 
+	// Retention period for deleted resources in the trash
+	trashRetention, ok := os.LookupEnv("TRASH_RETENTION")
+	if ok {
+		retention, err := time.ParseDuration(trashRetention)
+		if err != nil || retention < 0 {
+			log.Fatal().Msg("environment variable TRASH_RETENTION must be a valid, non-negative duration, e.g. 720h")
+		}
+		models.TrashRetention = retention
+	}
+
 	// Create the data directory if it does not exist yet
 	err = os.MkdirAll("data", os.ModePerm)
 	if err != nil {
@@ -88,10 +100,17 @@ func main() {
 		Handler: r,
 	}
 
+	// Event streams stay open until they are stopped
+	srv.RegisterOnShutdown(v4.StopEventStreams)
+
 	// Wait for interrupt signal to gracefully shutdown the server with a timeout of 5 seconds.
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
+	// Send webhook deliveries in the background until the server stops
+	dispatcherCtx, stopDispatcher := context.WithCancel(context.Background())
+	go webhook.Dispatcher{}.Run(dispatcherCtx, 5*time.Second)
+
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal().Str("event", "Error during startup").Err(err).Msg("backend")
@@ -101,6 +120,7 @@ func main() {
 
 	<-quit
 	log.Info().Str("event", "Received SIGINT or SIGTERM, stopping gracefully with 25 seconds timeout").Msg("backend")
+	stopDispatcher()
 
 	// Create a context with a 25 second timeout for the server to shut down in
 	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
@@ -16,21 +16,30 @@ import (
 // File contents are returned as a buffer and a map for the HTTP request headers
 func LoadTestFile(t *testing.T, filePath string) (*bytes.Buffer, map[string]string) {
 	path := path.Join("../../../test/data", filePath)
-	body := new(bytes.Buffer)
-
-	mw := multipart.NewWriter(body)
 
 	file, err := os.Open(path)
 	if err != nil {
 		assert.FailNow(t, err.Error())
 	}
+	defer file.Close()
+
+	return MultipartFile(t, filePath, file)
+}
+
+// MultipartFile creates a multipart form with the content as the file with the name
+//
+// The form is returned as a buffer and a map for the HTTP request headers
+func MultipartFile(t *testing.T, name string, content io.Reader) (*bytes.Buffer, map[string]string) {
+	body := new(bytes.Buffer)
+
+	mw := multipart.NewWriter(body)
 
-	w, err := mw.CreateFormFile("file", filePath)
+	w, err := mw.CreateFormFile("file", name)
 	if err != nil {
 		assert.Fail(t, err.Error())
 	}
 
-	if _, err := io.Copy(w, file); err != nil {
+	if _, err := io.Copy(w, content); err != nil {
 		assert.Fail(t, err.Error())
 	}
 
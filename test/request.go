@@ -2,6 +2,7 @@ package test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -17,6 +18,11 @@ import (
 
 // Request is a helper method to simplify making a HTTP request for tests.
 func Request(t *testing.T, method, reqURL string, body any, headers ...map[string]string) httptest.ResponseRecorder {
+	return RequestContext(t, context.Background(), method, reqURL, body, headers...)
+}
+
+// RequestContext makes a HTTP request with a context, e.g. to end requests that stream their response.
+func RequestContext(t *testing.T, ctx context.Context, method, reqURL string, body any, headers ...map[string]string) httptest.ResponseRecorder {
 	var byteBuffer *bytes.Buffer
 	var err error
 
@@ -53,7 +59,7 @@ func Request(t *testing.T, method, reqURL string, body any, headers ...map[strin
 	router.AttachRoutes(r.Group("/"))
 
 	recorder := httptest.NewRecorder()
-	req, _ := http.NewRequest(method, reqURL, byteBuffer)
+	req, _ := http.NewRequestWithContext(ctx, method, reqURL, byteBuffer)
 
 	for _, headerMap := range headers {
 		for header, value := range headerMap {